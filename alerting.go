@@ -0,0 +1,183 @@
+package benchmark
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/Harikakasimahanthi/benchmark-test/configs"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/alert"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/format"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/metric"
+)
+
+// alertCheckInterval is how often the latest readings are evaluated, shorter sustain durations check more often
+const alertCheckInterval = time.Second * 30
+
+// alerting opens an incident for every metric whose readings meet a High severity condition for the sustain duration,
+// e.g. a node which is down or a chain which stopped finalizing, and resolves it once the metric measures healthy
+// again. Incidents are keyed by session, group and metric, so the services deduplicate repeated triggers.
+type alerting struct {
+	notifiers []alert.Notifier
+	sustain   time.Duration
+	// unhealthy holds since when the metrics have been unhealthy, open the incidents opened for them
+	unhealthy map[string]time.Time
+	open      map[string]bool
+	checked   time.Time
+}
+
+// newAlerting creates the notifiers of the configured services, it returns nil when none is configured
+func newAlerting(config configs.Alerting) *alerting {
+	notifiers := newNotifiers(config)
+	if len(notifiers) == 0 {
+		return nil
+	}
+
+	return &alerting{
+		notifiers: notifiers,
+		sustain:   config.Sustain,
+		unhealthy: make(map[string]time.Time),
+		open:      make(map[string]bool),
+	}
+}
+
+// newNotifiers creates the notifiers of the configured services, services whose key can't be read are skipped
+func newNotifiers(config configs.Alerting) []alert.Notifier {
+	var notifiers []alert.Notifier
+	if config.PagerDutyKeyPath != "" {
+		if key, err := readKey(config.PagerDutyKeyPath); err != nil {
+			slog.With("err", err.Error()).Error("failed reading PagerDuty routing key, incidents are not opened in PagerDuty")
+		} else {
+			notifiers = append(notifiers, alert.NewPagerDuty(key))
+		}
+	}
+	if config.OpsgenieKeyPath != "" {
+		if key, err := readKey(config.OpsgenieKeyPath); err != nil {
+			slog.With("err", err.Error()).Error("failed reading Opsgenie API key, alerts are not opened in Opsgenie")
+		} else {
+			notifiers = append(notifiers, alert.NewOpsgenie(config.OpsgenieURL, key))
+		}
+	}
+	return notifiers
+}
+
+func readKey(path string) (string, error) {
+	key, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(key)), nil
+}
+
+// Run evaluates the readings measured since the previous check until the context is done. Incidents still open when
+// the run ends are left to the operators.
+func (a *alerting) Run(ctx context.Context, services []*Service) {
+	interval := alertCheckInterval
+	if a.sustain > 0 && a.sustain < interval {
+		interval = a.sustain
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	a.checked = time.Now()
+	for {
+		select {
+		case now := <-ticker.C:
+			a.check(ctx, services, now)
+		case <-ctx.Done():
+			slog.Debug("alerting was stopped")
+			return
+		}
+	}
+}
+
+func (a *alerting) check(ctx context.Context, services []*Service, now time.Time) {
+	for _, s := range services {
+		for group, groupMetrics := range s.metrics {
+			for _, m := range groupMetrics {
+				evaluation := m.EvaluateSince(a.checked)
+				// Metrics without new readings keep their state, e.g. those measuring once per epoch
+				if len(evaluation.Severities) == 0 {
+					continue
+				}
+
+				key := alertKey(s.session, group, m.GetName())
+				if !highSeverity(evaluation) {
+					delete(a.unhealthy, key)
+					if a.open[key] && notifyAll(a.notifiers, key, func(n alert.Notifier) error { return n.Resolve(ctx, key) }) {
+						delete(a.open, key)
+					}
+					continue
+				}
+
+				since, ok := a.unhealthy[key]
+				if !ok {
+					since = a.checked
+					a.unhealthy[key] = since
+				}
+				if a.open[key] || now.Sub(since) < a.sustain {
+					continue
+				}
+				incident := newAlert(key, s.session, group, m.GetName(), evaluation, now.Sub(since))
+				a.open[key] = notifyAll(a.notifiers, key, func(n alert.Notifier) error { return n.Trigger(ctx, incident) })
+			}
+		}
+	}
+	a.checked = now
+}
+
+// notifyAll sends the event to every service, it reports whether all of them received it. Failed events are sent again
+// on the next check, the services deduplicate them by key.
+func notifyAll(notifiers []alert.Notifier, key string, send func(alert.Notifier) error) bool {
+	delivered := true
+	for _, notifier := range notifiers {
+		if err := send(notifier); err != nil {
+			slog.With("err", err.Error()).With("service", notifier.Name()).With("alert", key).Error("failed notifying incident")
+			delivered = false
+			continue
+		}
+		slog.With("service", notifier.Name()).With("alert", key).Info("incident notified")
+	}
+	return delivered
+}
+
+func highSeverity(evaluation metric.Evaluation) bool {
+	for _, severity := range evaluation.Severities {
+		if metric.CompareSeverities(severity, metric.SeverityHigh) >= 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func alertKey(session string, group metric.Group, name string) string {
+	parts := []string{"benchmark"}
+	if session != "" {
+		parts = append(parts, session)
+	}
+	return strings.Join(append(parts, string(group), name), "/")
+}
+
+func newAlert(key, session string, group metric.Group, name string, evaluation metric.Evaluation, lasting time.Duration) alert.Alert {
+	source := session
+	if source == "" {
+		source, _ = os.Hostname()
+	}
+	details := map[string]string{
+		"group":      string(group),
+		"metric":     name,
+		"conditions": evaluation.Reasons(),
+	}
+	if hints := hintsFor(group, evaluation.Conditions); len(hints) != 0 {
+		details["hint"] = hints[0]
+	}
+	return alert.Alert{
+		Key:     key,
+		Summary: fmt.Sprintf("%s %s metric of %s unhealthy for %s", group, name, source, format.Duration(lasting)),
+		Source:  source,
+		Details: details,
+	}
+}
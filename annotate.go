@@ -0,0 +1,73 @@
+package benchmark
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/annotation"
+)
+
+const (
+	annotateServerFlag = "server"
+	annotateTimeout    = time.Second * 10
+)
+
+var AnnotateCMD = &cobra.Command{
+	Use:   "annotate <text>",
+	Short: "Mark an event, e.g. 'restarted geth', on the timeline of the running benchmark",
+	Args:  cobra.MinimumNArgs(1),
+	// The annotation is posted to the running benchmark, which has the configuration
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		return nil
+	},
+	RunE: func(cobraCMD *cobra.Command, args []string) error {
+		server, err := cobraCMD.Flags().GetString(annotateServerFlag)
+		if err != nil {
+			return err
+		}
+		body, err := json.Marshal(annotation.Annotation{Text: strings.Join(args, " ")})
+		if err != nil {
+			return err
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), annotateTimeout)
+		defer cancel()
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimSuffix(server, "/")+"/api/v1/annotations", bytes.NewReader(body))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		res, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return errors.Join(err, fmt.Errorf("failed reaching the running benchmark at '%s'", server))
+		}
+		defer res.Body.Close()
+
+		if res.StatusCode != http.StatusCreated {
+			var failure struct {
+				Error string `json:"error"`
+			}
+			_ = json.NewDecoder(res.Body).Decode(&failure)
+			return fmt.Errorf("annotation was rejected with status '%s': %s", res.Status, failure.Error)
+		}
+		var added annotation.Annotation
+		if err := json.NewDecoder(res.Body).Decode(&added); err != nil {
+			return err
+		}
+		fmt.Fprintf(cobraCMD.OutOrStdout(), "annotated %s: %s\n", added.Time.Format(time.RFC3339), added.Text)
+		return nil
+	},
+}
+
+func init() {
+	AnnotateCMD.Flags().String(annotateServerFlag, fmt.Sprintf("http://localhost:%d", defaultServerPort), "Web server of the running benchmark")
+	CMD.AddCommand(AnnotateCMD)
+}
@@ -0,0 +1,62 @@
+package benchmark
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/Harikakasimahanthi/benchmark-test/configs"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/metric"
+)
+
+// faultInjector is implemented by metrics whose readings can be degraded on purpose
+type faultInjector interface {
+	InjectFaults(faults ...metric.Fault) error
+}
+
+// injectFaults degrades the readings of the metrics chosen by the chaos configuration, the time of the faults is
+// relative to the start of the run. Faults which match no enabled metric fail, they would never trigger anything.
+func injectFaults(metrics map[metric.Group][]metricService, chaos configs.Chaos, start time.Time) error {
+	for _, fault := range chaos.Faults {
+		injected := metric.Fault{
+			Measurement: fault.Measurement,
+			Value:       fault.Value,
+			Start:       start.Add(fault.After),
+		}
+		if fault.Duration != 0 {
+			injected.End = injected.Start.Add(fault.Duration)
+		}
+
+		matched := false
+		for group, groupMetrics := range metrics {
+			if !strings.EqualFold(string(group), fault.Group) {
+				continue
+			}
+			for _, m := range groupMetrics {
+				injector, ok := m.(faultInjector)
+				if !ok || !strings.EqualFold(m.GetName(), fault.Metric) {
+					continue
+				}
+				if err := injector.InjectFaults(injected); err != nil {
+					return errors.Join(err, errors.New("invalid chaos fault"))
+				}
+				matched = true
+			}
+		}
+		if !matched {
+			return fmt.Errorf("chaos fault targets metric '%s' of group '%s' which is not enabled", fault.Metric, fault.Group)
+		}
+
+		slog.
+			With("group", fault.Group).
+			With("metric_name", fault.Metric).
+			With("measurement_name", fault.Measurement).
+			With("value", fault.Value).
+			With("start", injected.Start).
+			With("end", injected.End).
+			Warn("chaos fault injected, the readings are degraded on purpose")
+	}
+	return nil
+}
@@ -1,82 +1,460 @@
 package benchmark
 
 import (
+	"bytes"
 	"context"
+	"errors"
+	"fmt"
+	"io"
 	"log/slog"
 	"os"
+	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 
 	"github.com/Harikakasimahanthi/benchmark-test/configs"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/alert"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/annotation"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/audit"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/clientinfo"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/clock"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/exporter"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/failure"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/forks"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/format"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/grpcclient"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/hardware"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/healthcheck"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/httpclient"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/ipc"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/journal"
 	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/lifecycle"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/logger"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/metric"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/network"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/recording"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/run"
 	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/server/host"
 	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/server/route"
+	"github.com/Harikakasimahanthi/benchmark-test/metrics/connectivity"
+	"github.com/Harikakasimahanthi/benchmark-test/metrics/consensus"
 	"github.com/Harikakasimahanthi/benchmark-test/report"
 )
 
 const (
 	durationFlag             = "duration"
+	presetFlag               = "preset"
 	defaultExecutionDuration = time.Minute * 15
 
-	serverPortFlag    = "port"
-	defaultServerPort = 8080
+	serverPortFlag     = "port"
+	defaultServerPort  = 8080
+	latencyBucketsFlag = "latency-buckets"
 
 	consensusAddrFlag              = "consensus-addr"
+	consensusProxyFlag             = "consensus-proxy"
+	consensusGRPCFlag              = "consensus-grpc-enabled"
+	consensusGRPCAddrFlag          = "consensus-grpc-addr"
 	consensusMetricClientFlag      = "consensus-metric-client-enabled"
 	consensusMetricLatencyFlag     = "consensus-metric-latency-enabled"
 	consensusMetricPeersFlag       = "consensus-metric-peers-enabled"
 	consensusMetricAttestationFlag = "consensus-metric-attestation-enabled"
+	consensusMetricHeadDelayFlag   = "consensus-metric-head-delay-enabled"
+	consensusMetricSyncFlag        = "consensus-metric-sync-enabled"
+	consensusMetricDutySimFlag     = "consensus-metric-duty-simulation-enabled"
+	consensusMetricBalancesFlag    = "consensus-metric-balances-enabled"
+	consensusMetricSyncCommFlag    = "consensus-metric-sync-committee-enabled"
+	consensusMetricProposalFlag    = "consensus-metric-proposal-dry-run-enabled"
+	consensusMetricDutyCalFlag     = "consensus-metric-duty-calendar-enabled"
+	consensusMetricInboundFlag     = "consensus-metric-inbound-enabled"
+	consensusMetricNetworkFlag     = "consensus-metric-network-enabled"
+	consensusInboundProbeURLFlag   = "consensus-inbound-probe-url"
+	consensusMetricRuntimeFlag     = "consensus-metric-runtime-enabled"
+	consensusRuntimeAddrFlag       = "consensus-runtime-metrics-addr"
+	consensusOtherAddrsFlag        = "consensus-other-addrs"
+	consensusCheckpointFlag        = "consensus-checkpoint-providers"
+	consensusBackfillEpochsFlag    = "consensus-attestation-backfill-epochs"
+	consensusMetricVoteCheckFlag   = "consensus-metric-vote-cross-check-enabled"
+	consensusMetricBlobsFlag       = "consensus-metric-blobs-enabled"
+	consensusMetricAggregationFlag = "consensus-metric-aggregation-enabled"
+	consensusMetricBuilderFlag     = "consensus-metric-builder-comparison-enabled"
+	consensusBuilderRelaysFlag     = "consensus-builder-relays"
+	consensusMetricFailoverFlag    = "consensus-metric-failover-enabled"
+	consensusFailoverAddrFlag      = "consensus-failover-addr"
+	consensusFailoverSimulateFlag  = "consensus-failover-simulate-every"
 
-	executionAddrFlag          = "execution-addr"
-	executionMetricPeersFlag   = "execution-metric-peers-enabled"
-	executionMetricLatencyFlag = "execution-metric-latency-enabled"
+	executionAddrFlag             = "execution-addr"
+	executionProxyFlag            = "execution-proxy"
+	executionEngineAddrFlag       = "execution-engine-addr"
+	executionJWTSecretPathFlag    = "execution-jwt-secret-path"
+	executionMetricPeersFlag      = "execution-metric-peers-enabled"
+	executionMetricAdminPeersFlag = "execution-metric-admin-peers-enabled"
+	executionMetricLatencyFlag    = "execution-metric-latency-enabled"
+	executionMetricEngineFlag     = "execution-metric-engine-enabled"
+	executionMetricEngineSimFlag  = "execution-metric-engine-simulate"
+	executionMetricRuntimeFlag    = "execution-metric-runtime-enabled"
+	executionRuntimeAddrFlag      = "execution-runtime-metrics-addr"
+	executionMetricInboundFlag    = "execution-metric-inbound-enabled"
+	executionInboundProbeURLFlag  = "execution-inbound-probe-url"
+	executionMetricStateFlag      = "execution-metric-state-access-enabled"
 
-	infraMetricCPUFlag    = "infra-metric-cpu-enabled"
-	infraMetricMemoryFlag = "infra-metric-memory-enabled"
+	validatorAddrFlag                   = "validator-addr"
+	validatorIndicesFlag                = "validator-indices"
+	validatorOtherAddrsFlag             = "validator-other-addrs"
+	validatorSlashingProtectionPathFlag = "validator-slashing-protection-path"
+	validatorTokenPathFlag              = "validator-token-path"
+	validatorProxyFlag                  = "validator-proxy"
+	validatorMetricKeySafetyFlag        = "validator-metric-key-safety-enabled"
+
+	infraMetricCPUFlag         = "infra-metric-cpu-enabled"
+	infraMetricMemoryFlag      = "infra-metric-memory-enabled"
+	infraMetricProbeFlag       = "infra-metric-probe-enabled"
+	infraProbeHostsFlag        = "infra-probe-hosts"
+	infraMetricDataDirsFlag    = "infra-metric-data-dirs-enabled"
+	infraDataDirsFlag          = "infra-data-dirs"
+	infraPortsAuditFlag        = "infra-ports-audit-enabled"
+	infraMetricPressureFlag    = "infra-metric-memory-pressure-enabled"
+	infraMetricLoadFlag        = "infra-metric-load-enabled"
+	infraSchedLatencyFlag      = "infra-scheduler-latency-enabled"
+	infraMetricHardwareFlag    = "infra-metric-hardware-enabled"
+	infraJournalFlag           = "infra-journal-enabled"
+	infraJournalUnitsFlag      = "infra-journal-units"
+	infraDiskBenchFlag         = "infra-disk-bench-enabled"
+	infraDiskBenchDirFlag      = "infra-disk-bench-dir"
+	infraDiskBenchSizeFlag     = "infra-disk-bench-size-mb"
+	infraDiskBenchDurationFlag = "infra-disk-bench-duration"
+
+	observerMetricFlag = "observer-metric-enabled"
 
 	networkFlag = "network"
+
+	reportLocaleFlag         = "report-locale"
+	reportEpochsFlag         = "report-epochs"
+	reportHistogramsFlag     = "report-histograms"
+	reportTimelineFlag       = "report-timeline"
+	reportTemplateFlag       = "report-template"
+	reportTimezoneFlag       = "report-timezone"
+	reportDurationFormatFlag = "report-duration-format"
+
+	artifactsDirFlag    = "artifacts-dir"
+	keepRunsFlag        = "artifacts-keep-runs"
+	keepDaysFlag        = "artifacts-keep-days"
+	compressionFlag     = "artifacts-compression"
+	chunkFlag           = "artifacts-chunk"
+	defaultArtifactsDir = "./artifacts"
+
+	clientDetectionTimeout = time.Second * 5
+
+	speedTestIperf3Flag      = "speed-test-iperf3"
+	speedTestDownloadURLFlag = "speed-test-download-url"
+	speedTestUploadURLFlag   = "speed-test-upload-url"
+	speedTestDurationFlag    = "speed-test-duration"
+
+	updateCheckFlag = "update-check"
+	redactFlag      = "redact"
+	userAgentFlag   = "user-agent"
+	proxyFlag       = "proxy"
+	startAtFlag     = "start-at"
+	recordsOutFlag  = "records-out"
+
+	preflightFlag          = "preflight-enabled"
+	preflightOnFailureFlag = "preflight-on-failure"
+
+	recordFlag = "record"
+	replayFlag = "replay"
+
+	strictReadOnlyFlag = "strict-read-only"
+	auditLogFlag       = "audit-log"
+	defaultAuditLog    = "./audit.jsonl"
+
+	pushgatewayURLFlag      = "pushgateway-url"
+	pushgatewayIntervalFlag = "pushgateway-interval"
+	healthcheckURLFlag      = "healthcheck-url"
+	healthcheckIntervalFlag = "healthcheck-interval"
+	alertPagerDutyKeyFlag   = "alert-pagerduty-routing-key-path"
+	alertOpsgenieKeyFlag    = "alert-opsgenie-api-key-path"
+	alertOpsgenieURLFlag    = "alert-opsgenie-url"
+	alertSustainFlag        = "alert-sustain"
+	defaultPushgatewayJob   = "solostaking_benchmark"
 )
 
+// bindErr is the failure binding the flags to the configuration keys, it fails the command instead of the start of the
+// program
+var bindErr error
+
 func init() {
 	addFlags(CMD)
-	if err := bindFlags(CMD); err != nil {
-		panic(err.Error())
-	}
+	bindErr = bindFlags(CMD)
 }
 
 var CMD = &cobra.Command{
 	Use:   "benchmark",
 	Short: "Run benchmarks of solo staking node",
-	Run: func(cobraCMD *cobra.Command, args []string) {
-		var (
-			ctx    context.Context
-			cancel context.CancelFunc
-		)
-		if configs.Values.Benchmark.Duration == 0 {
-			ctx, cancel = context.WithCancel(context.Background())
-		} else {
-			ctx, cancel = context.WithTimeout(context.Background(), configs.Values.Benchmark.Duration)
+	// Invalid configurations are reported as errors, the usage would hide them
+	SilenceUsage: true,
+	RunE: func(cobraCMD *cobra.Command, args []string) (err error) {
+		if bindErr != nil {
+			return failure.Runtime(bindErr)
 		}
 
 		// Validate solo staking setup
+		if err := configs.CheckKeys(viper.AllKeys()); err != nil {
+			return failure.Config(err)
+		}
 		isValid, err := configs.Values.Benchmark.Validate()
 		if !isValid {
-			panic(err.Error())
+			return failure.Config(errors.Join(err, errors.New("invalid benchmark configuration")))
+		}
+
+		if err := format.SetLocale(configs.Values.Benchmark.Report.Locale); err != nil {
+			return failure.Config(err)
+		}
+		if err := format.SetTimezone(configs.Values.Benchmark.Report.Timezone); err != nil {
+			return failure.Config(err)
+		}
+		if err := format.SetDurationFormat(configs.Values.Benchmark.Report.DurationFormat); err != nil {
+			return failure.Config(err)
+		}
+		for _, severity := range configs.Values.Benchmark.Report.Severities {
+			metric.SetSeverityWeight(metric.SeverityLevel(severity.Name), severity.Weight)
+		}
+
+		// Obscure the addresses and identifiers in the outputs of the run, so they can be shared publicly
+		if configs.Values.Benchmark.Redact {
+			redactor = newRedactor(configs.Values.Benchmark)
+			exporter.SetRedaction(redactor.String)
+		}
+
+		if configs.Values.Benchmark.UpdateCheck {
+			go checkForUpdate(cobraCMD.Root().Version)
 		}
 
-		// Load enabled metrics (remove SSV-related metrics)
-		metrics, err := LoadEnabledMetrics(configs.Values)
+		// Audit the requests to the nodes and record their responses, or replay them, before the nodes are contacted
+		// for the first time
+		closeInterception, err := setUpInterception(configs.Values.Benchmark)
 		if err != nil {
-			panic(err.Error())
+			return failure.Config(err)
+		}
+		defer closeInterception()
+		httpclient.SetUserAgent(configs.Values.Benchmark.UserAgent)
+		if err := httpclient.SetDefaultProxy(configs.Values.Benchmark.Proxy); err != nil {
+			return failure.Config(err)
 		}
 
-		// Initialize benchmark service
-		benchmarkService := New(metrics, report.New())
+		// Create the artifacts directory of the run
+		var benchmarkRun *run.Run
+		if configs.Values.Benchmark.Artifacts.Dir != "" {
+			benchmarkRun, err = newRun(configs.Values.Benchmark, cobraCMD.Root().Version)
+			if err != nil {
+				return failure.Runtime(err)
+			}
+			slog.With("run_id", benchmarkRun.ID).With("dir", benchmarkRun.Dir).Info("run artifacts directory created")
 
-		// Start the benchmark service
-		go benchmarkService.Start(ctx)
+			artifacts := configs.Values.Benchmark.Artifacts
+			pruned, err := run.Prune(artifacts.Dir, artifacts.Retention(), benchmarkRun.ID, false)
+			if err != nil {
+				slog.With("err", err.Error()).Warn("failed pruning runs of the artifacts directory")
+			}
+			if len(pruned) != 0 {
+				slog.With("runs", pruned).Info("pruned runs exceeding the retention")
+			}
+		}
+
+		// Capture the available bandwidth before the benchmark starts and once it is finished
+		if speedTest := configs.Values.Benchmark.SpeedTest; speedTest.Enabled() {
+			runSpeedTest(speedTest, benchmarkRun, "speed_test_start")
+			if benchmarkRun != nil {
+				benchmarkRun.BeforeFinish(func() {
+					runSpeedTest(speedTest, benchmarkRun, "speed_test_end")
+				})
+			}
+		}
+
+		// Runs of a fleet start measuring at the same time on every machine
+		if err := waitForStart(cobraCMD); err != nil {
+			return failure.Config(err)
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		// Ping the dead man's switch, which alerts when the benchmark stops running
+		var pinger *healthcheck.Pinger
+		if healthcheckConfig := configs.Values.Benchmark.Export.Healthcheck; healthcheckConfig.URL != "" {
+			pinger = healthcheck.New(healthcheckConfig.URL, healthcheckConfig.Interval)
+			pinger.Start()
+			go pinger.Run(ctx)
+			defer func() {
+				if err != nil {
+					pinger.Fail(err.Error())
+				}
+			}()
+		}
+
+		// Export to Pushgateway alongside the report
+		var pushgateway *report.Pushgateway
+		if pushgatewayConfig := configs.Values.Benchmark.Export.Pushgateway; pushgatewayConfig.URL != "" {
+			pushgateway = newPushgateway(pushgatewayConfig, benchmarkRun)
+			go pushgateway.Run(ctx)
+		}
+
+		// Serve the measurements on the metrics endpoint, labeled by the endpoint of every session
+		if err := exporter.Register(configs.Values.Benchmark.Server.LatencyBuckets); err != nil {
+			return failure.Config(err)
+		}
+
+		// Annotations posted during the run are exported, marked on the timeline and kept with the run
+		annotations := newAnnotations(benchmarkRun)
+		if journalConfig := configs.Values.Benchmark.Infrastructure.Journal; journalConfig.Enabled {
+			go followJournal(ctx, journalConfig.Units, annotations)
+		}
+
+		// Compare the measurements with a stored baseline run, loaded before the nodes are contacted
+		var baselineWatch *watch
+		if configs.Values.Benchmark.Watch.Baseline != "" {
+			baselineWatch, err = newWatch(configs.Values.Benchmark, annotations)
+			if err != nil {
+				return failure.Config(err)
+			}
+		}
+
+		// Load enabled metrics of every session, each session gets its own metric instances
+		// The records of all sessions of a fleet run go to one document
+		recordsOut, err := newRecordsOut(cobraCMD)
+		if err != nil {
+			return failure.Runtime(err)
+		}
+		if recordsOut != nil {
+			defer recordsOut.Close()
+			configs.Values.Benchmark.Report.Mode = configs.ReportModeMerged
+		}
+		var mergedReport report.Sink
+		if configs.Values.Benchmark.Report.Mode == configs.ReportModeMerged {
+			mergedReport = redacted(withPushgateway(newReport(configs.Values.Benchmark.Report, benchmarkRun, "", true), pushgateway))
+			if recordsOut != nil {
+				mergedReport = report.NewMulti(mergedReport, report.NewJSON(recordsOut))
+			}
+		}
+
+		// The security checks run once, their findings are added to every report
+		if configs.Values.Benchmark.Infrastructure.PortsAudit.Enabled {
+			securityRecords = auditPorts(benchmarkRun)
+		}
+		if configs.Values.Benchmark.Infrastructure.DiskBench.Enabled {
+			runDiskBench(configs.Values.Benchmark.Infrastructure, benchmarkRun)
+		}
+
+		var services []*Service
+		start := time.Now()
+		for _, session := range configs.Values.Benchmark.SessionConfigs() {
+			if err := configureEndpoints(session); err != nil {
+				return failure.Config(err)
+			}
+			clients := detectClients(session)
+			if benchmarkRun != nil {
+				benchmarkRun.SetMetadata(sessionKey("clients", session.Name), clients)
+			}
+			identity, err := verifyNetwork(session, benchmarkRun)
+			if err != nil {
+				return failure.Config(err)
+			}
+			spec, err := fetchSpec(session)
+			if err != nil {
+				return failure.Config(err)
+			}
+			warnForkReadiness(session, clients, identity, spec)
+			securityRecords = append(securityRecords, verifyCheckpoints(session, benchmarkRun)...)
+			if benchmarkRun != nil {
+				benchmarkRun.SetMetadata(sessionKey("spec", session.Name), spec)
+			}
+
+			metrics, err := LoadEnabledMetrics(session, clients, spec)
+			if err != nil {
+				return failure.Config(err)
+			}
+			// Metrics which can't measure are found before the run rather than reported with empty aggregates
+			if preflightConfig := session.Preflight; preflightConfig.Enabled && session.Recording.Replay == "" {
+				results := preflight(session, clients, metrics)
+				if benchmarkRun != nil {
+					benchmarkRun.SetMetadata(sessionKey("preflight", session.Name), results)
+				}
+				if failed := failedPreflight(results); len(failed) != 0 {
+					if preflightConfig.OnFailure == configs.PreflightAbort {
+						report.RenderPreflight(os.Stdout, failed, "aborting the run")
+						return failure.Connectivity(fmt.Errorf("preflight failed for %d metrics", len(failed)))
+					}
+					report.RenderPreflight(os.Stdout, failed, "running without them")
+					withoutFailed(metrics, failed)
+				}
+			}
+			if err := injectFaults(metrics, session.Chaos, start); err != nil {
+				return failure.Config(err)
+			}
+			if err := addTrends(metrics, session.Trends); err != nil {
+				return failure.Config(err)
+			}
+			healthRules, err := newRules(metrics, session.Rules)
+			if err != nil {
+				return failure.Config(err)
+			}
+			if benchmarkRun != nil && len(session.Chaos.Faults) != 0 {
+				benchmarkRun.SetMetadata(sessionKey("chaos_faults", session.Name), session.Chaos.Faults)
+			}
+
+			sessionReport := mergedReport
+			if sessionReport == nil {
+				sessionReport = redacted(withPushgateway(newReport(configs.Values.Benchmark.Report, benchmarkRun, session.Name, false), pushgateway))
+			}
+
+			// Initialize benchmark service
+			service := New(metrics, sessionReport).WithSession(session.Name).WithRun(benchmarkRun).WithRules(healthRules)
+			if configs.Values.Benchmark.Report.Epochs {
+				service.WithEpochs(spec)
+			}
+			if configs.Values.Benchmark.Report.Histograms {
+				service.WithHistograms()
+			}
+			if artifacts := configs.Values.Benchmark.Artifacts; artifacts.Compression != "" || artifacts.Chunk > 0 {
+				service.WithArchive(artifacts.Compression, artifacts.Chunk)
+			}
+			if configs.Values.Benchmark.Report.Timeline {
+				service.WithTimeline().WithAnnotations(annotations)
+			}
+			services = append(services, service)
+		}
+
+		// The benchmark duration starts once every session passed its preflight
+		if duration := configs.Values.Benchmark.Duration; duration != 0 {
+			var timedCancel context.CancelFunc
+			ctx, timedCancel = context.WithTimeout(ctx, duration)
+			defer timedCancel()
+		}
+
+		// Open incidents for lasting High severity conditions
+		if alerting := newAlerting(configs.Values.Benchmark.Export.Alerting); alerting != nil {
+			go alerting.Run(ctx, services)
+		}
+		if baselineWatch != nil {
+			go baselineWatch.Run(ctx, services)
+		}
+
+		// Start the benchmark sessions
+		go func() {
+			RunSessions(ctx, services, benchmarkRun)
+			if pinger != nil {
+				pinger.Success(completionMessage(benchmarkRun))
+			}
+		}()
+		if duration := configs.Values.Benchmark.Duration; duration != 0 {
+			go LogProgress(ctx, services, duration)
+		}
+
+		// Emit interim reports on SIGUSR1 and on GET /report without ending the run
+		go lifecycle.ListenForInterimReport(ctx, func() {
+			writeInterimReport(benchmarkRun, services)
+		}, make(chan os.Signal, 1))
 
 		// Set up web server for metrics
 		slog.With("port", configs.Values.Benchmark.Server.Port).Info("running web host")
@@ -84,83 +462,775 @@ var CMD = &cobra.Command{
 			route.
 				NewRouter().
 				WithMetrics().
+				WithReport(func(w io.Writer) { RenderInterim(w, services) }).
+				WithAnnotations(annotations).
+				WithMetricControl(
+					func() []route.MetricState { return metricStates(services) },
+					func(group, name string, enabled bool) ([]route.MetricState, error) {
+						return setMetricEnabled(services, group, name, enabled)
+					}).
 				Router())
-		host.Run()
+		if err := host.Run(); err != nil {
+			return failure.Runtime(err)
+		}
 
 		// Handle application shutdown gracefully
 		lifecycle.ListenForApplicationShutDown(ctx, func() {
 			cancel()
 			slog.Warn("terminating the application")
 		}, make(chan os.Signal))
+		return nil
 	},
 }
 
+// completionMessage tells the health check which run completed and where its artifacts are
+func completionMessage(benchmarkRun *run.Run) string {
+	if benchmarkRun == nil {
+		return "benchmark run completed"
+	}
+	return fmt.Sprintf("benchmark run '%s' completed, artifacts in '%s'", benchmarkRun.ID, benchmarkRun.Dir)
+}
+
+func newRun(config configs.Benchmark, version string) (*run.Run, error) {
+	benchmarkRun, err := run.New(config.Artifacts.Dir)
+	if err != nil {
+		return nil, err
+	}
+
+	logFile, err := benchmarkRun.Create("logs.jsonl")
+	if err != nil {
+		return nil, err
+	}
+	if redactor != nil {
+		logger.AddOutput(redactor.Writer(logFile))
+		benchmarkRun.Redact(redactor.JSON)
+	} else {
+		logger.AddOutput(logFile)
+	}
+
+	benchmarkRun.Metadata.Version = version
+	// The configuration is stored without its secrets, the artifacts are shared
+	benchmarkRun.Metadata.Config = config.Redacted()
+	for _, session := range config.SessionConfigs() {
+		benchmarkRun.Metadata.Sessions = append(benchmarkRun.Metadata.Sessions, session.Name)
+	}
+
+	return benchmarkRun, nil
+}
+
+// newReport creates the configured sinks of the report of the session, a merged report is shared by all sessions
+func newReport(config configs.Report, benchmarkRun *run.Run, session string, merged bool) report.Sink {
+	sinks := config.Sinks
+	if len(sinks) == 0 {
+		sinks = []configs.ReportSink{{Type: configs.SinkConsole}}
+	}
+
+	name := sessionKey("report", session)
+	var created []report.Sink
+	for _, sink := range sinks {
+		switch sink.Type {
+		case configs.SinkConsole:
+			if merged {
+				created = append(created, report.NewMerged(reportOutput(benchmarkRun, name+".txt")))
+			} else {
+				created = append(created, report.New(reportOutput(benchmarkRun, name+".txt")))
+			}
+		case configs.SinkMarkdown:
+			created = append(created, report.NewMarkdown(os.Stdout, merged))
+		case configs.SinkJSON, configs.SinkHTML:
+			file, err := benchmarkRun.Create(name + "." + sink.Type)
+			if err != nil {
+				slog.With("err", err.Error()).With("sink", sink.Type).Error("failed creating report artifact, the sink is skipped")
+				continue
+			}
+			if sink.Type == configs.SinkJSON {
+				created = append(created, report.NewJSON(file))
+			} else {
+				created = append(created, report.NewHTML(file, merged))
+			}
+		case configs.SinkPDF:
+			created = append(created, report.NewPDF(benchmarkRun.Path(name+".pdf"), merged))
+		case configs.SinkWebhook:
+			created = append(created, report.NewWebhook(sink.URL))
+		case configs.SinkDiscord:
+			artifacts := ""
+			if benchmarkRun != nil {
+				artifacts = benchmarkRun.Dir
+				if sink.ArtifactsURL != "" {
+					artifacts = strings.TrimSuffix(sink.ArtifactsURL, "/") + "/" + benchmarkRun.ID
+				}
+			}
+			if sink.URL != "" {
+				created = append(created, report.NewDiscordWebhook(sink.URL, artifacts, merged))
+				continue
+			}
+			token, err := os.ReadFile(sink.TokenPath)
+			if err != nil {
+				slog.With("err", err.Error()).Error("failed reading Discord bot token, the sink is skipped")
+				continue
+			}
+			created = append(created, report.NewDiscordBot(strings.TrimSpace(string(token)), sink.Channel, artifacts, merged))
+		}
+	}
+
+	if config.Template != "" {
+		name := report.TemplateOutput(filepath.Base(config.Template))
+		extension := filepath.Ext(name)
+		out := io.Writer(os.Stdout)
+		if benchmarkRun != nil {
+			file, err := benchmarkRun.Create(sessionKey(strings.TrimSuffix(name, extension), session) + extension)
+			if err != nil {
+				slog.With("err", err.Error()).Error("failed creating report template artifact, writing to stdout")
+			} else {
+				out = file
+			}
+		}
+		if t, err := report.NewTemplate(config.Template, out); err != nil {
+			slog.With("err", err.Error()).Error("failed loading report template, the template is skipped")
+		} else {
+			created = append(created, t)
+		}
+	}
+
+	if len(created) == 1 {
+		return created[0]
+	}
+	return report.NewMulti(created...)
+}
+
+// reportOutput writes the report to stdout and, when artifacts are enabled, to the named file of the run
+func reportOutput(benchmarkRun *run.Run, name string) io.Writer {
+	if benchmarkRun == nil {
+		return os.Stdout
+	}
+	file, err := benchmarkRun.Create(name)
+	if err != nil {
+		slog.With("err", err.Error()).With("name", name).Error("failed creating report artifact, writing to stdout only")
+		return os.Stdout
+	}
+	return io.MultiWriter(os.Stdout, file)
+}
+
+// setUpInterception routes the requests to the nodes through the auditor and the recorder or replayer, it returns the
+// function closing the audit log and the recording
+func setUpInterception(config configs.Benchmark) (func(), error) {
+	auditor, closeAudit, err := setUpAudit(config.Audit)
+	if err != nil {
+		return nil, err
+	}
+	recording, closeRecording, err := setUpRecording(config.Recording)
+	if err != nil {
+		closeAudit()
+		return nil, err
+	}
+
+	// Blocked requests are audited but never recorded
+	interceptors := []httpclient.Interceptor{auditor}
+	if recording != nil {
+		interceptors = append(interceptors, recording)
+	}
+	httpclient.SetInterceptor(httpclient.Chain(interceptors...))
+	if recording != nil {
+		ipc.SetInterceptor(recording)
+	}
+	audit.Set(auditor)
+	return func() {
+		closeRecording()
+		closeAudit()
+	}, nil
+}
+
+// setUpAudit creates the auditor of the requests, it only writes a log when one is set or strict read-only mode is on
+func setUpAudit(config configs.Audit) (*audit.Auditor, func(), error) {
+	path := config.Log
+	if path == "" && config.StrictReadOnly {
+		path = defaultAuditLog
+	}
+	auditor, err := audit.New(path, config.StrictReadOnly)
+	if err != nil {
+		return nil, nil, err
+	}
+	if config.StrictReadOnly {
+		slog.With("audit_log", path).Info("strict read-only mode, requests which could change the nodes are blocked")
+	} else if path != "" {
+		slog.With("audit_log", path).Info("auditing the requests to the nodes")
+	}
+	return auditor, func() {
+		if err := auditor.Close(); err != nil {
+			slog.With("err", err.Error()).Error("failed closing audit log")
+		}
+	}, nil
+}
+
+// recordingInterceptor records or replays both the requests and the IPC calls to the nodes
+type recordingInterceptor interface {
+	httpclient.Interceptor
+	ipc.Interceptor
+}
+
+// setUpRecording creates the interceptor recording the responses of the nodes, or replaying them, nil when neither is
+// set. It returns the function closing the recording.
+func setUpRecording(config configs.Recording) (recordingInterceptor, func(), error) {
+	switch {
+	case config.Record != "":
+		recorder, err := recording.NewRecorder(config.Record)
+		if err != nil {
+			return nil, nil, err
+		}
+		slog.With("dir", config.Record).Info("recording node responses")
+		return recorder, func() {
+			if err := recorder.Close(); err != nil {
+				slog.With("err", err.Error()).Error("failed closing recording")
+			}
+		}, nil
+	case config.Replay != "":
+		replayer, err := recording.NewReplayer(config.Replay)
+		if err != nil {
+			return nil, nil, err
+		}
+		clock.Set(replayer.Start())
+		slog.With("dir", config.Replay).With("start", replayer.Start()).Info("replaying recorded node responses")
+		return replayer, func() {}, nil
+	}
+	return nil, func() {}, nil
+}
+
+// writeInterimReport writes the interim report to stdout and, when artifacts are enabled, to a timestamped file of the run
+func writeInterimReport(benchmarkRun *run.Run, services []*Service) {
+	var buffer bytes.Buffer
+	RenderInterim(&buffer, services)
+	os.Stdout.Write(buffer.Bytes())
+
+	if benchmarkRun == nil {
+		return
+	}
+	path := benchmarkRun.Path(fmt.Sprintf("report-interim-%s.txt", time.Now().Format("20060102-150405")))
+	if err := os.WriteFile(path, buffer.Bytes(), 0o644); err != nil {
+		slog.With("err", err.Error()).With("path", path).Error("failed writing interim report artifact")
+	}
+}
+
+// runSpeedTest measures the bandwidth and records it in the run metadata under the key
+func runSpeedTest(config configs.SpeedTest, benchmarkRun *run.Run, key string) {
+	slog.With("key", key).Info("running bandwidth speed test")
+	result := connectivity.SpeedTest{
+		Iperf3:      config.Iperf3,
+		DownloadURL: config.DownloadURL,
+		UploadURL:   config.UploadURL,
+		Duration:    config.Duration,
+	}.Run(context.Background())
+
+	log := slog.
+		With("key", key).
+		With("method", result.Method).
+		With("download", format.Number(result.DownloadBitsPerSecond/1e6, 2)+" Mbit/s").
+		With("upload", format.Number(result.UploadBitsPerSecond/1e6, 2)+" Mbit/s")
+	if result.Error != "" {
+		log.With("err", result.Error).Error("bandwidth speed test failed")
+	} else {
+		log.Info("bandwidth speed test finished")
+	}
+
+	if benchmarkRun != nil {
+		benchmarkRun.SetMetadata(key, result)
+	}
+}
+
+// newAnnotations returns the store of the annotations of the run, which exports every annotation and writes them to
+// the 'annotations.json' artifact when the run finishes
+func newAnnotations(benchmarkRun *run.Run) *annotation.Store {
+	store := annotation.NewStore()
+	store.OnAdd(func(a annotation.Annotation) {
+		slog.With("source", a.Source).With("text", a.Text).Info("annotation added")
+		exporter.SetAnnotation(a.Source, a.Text, a.Time)
+	})
+	if benchmarkRun != nil {
+		benchmarkRun.BeforeFinish(func() {
+			if err := benchmarkRun.WriteJSON(annotation.Artifact, store.List()); err != nil {
+				slog.With("err", err.Error()).Error("failed writing annotations artifact")
+			}
+		})
+	}
+	return store
+}
+
+// followJournal adds the events of the units in the systemd journal to the annotations until the run ends
+func followJournal(ctx context.Context, units []string, annotations *annotation.Store) {
+	slog.With("units", units).Info("following the systemd journal")
+	err := journal.Follow(ctx, units, func(a annotation.Annotation) {
+		if _, err := annotations.Add(a); err != nil {
+			slog.With("err", err.Error()).Warn("failed adding journal annotation")
+		}
+	})
+	if err != nil {
+		slog.With("err", err.Error()).Error("stopped following the systemd journal")
+	}
+}
+
+// runDiskBench measures the disk before the clients are measured and records the result in the run metadata, the
+// passive metrics can't tell the random I/O performance the execution client depends on
+func runDiskBench(config configs.Infrastructure, benchmarkRun *run.Run) {
+	dir := config.DiskBenchDir()
+	log := slog.With("dir", dir)
+	log.Info("running disk benchmark")
+	result, err := hardware.Disk(context.Background(), dir, config.DiskBench.SizeMB<<20, config.DiskBench.Duration)
+	if err != nil {
+		log.With("err", err.Error()).Error("disk benchmark failed")
+		return
+	}
+
+	log.
+		With("random_read_iops", format.Number(result.RandomReadIOPS, 0)).
+		With("fsync_p50", format.Duration(result.FsyncP50)).
+		With("fsync_p99", format.Duration(result.FsyncP99)).
+		With("cached", result.Cached).
+		Info("disk benchmark finished")
+	if benchmarkRun != nil {
+		benchmarkRun.SetMetadata("disk_benchmark", result)
+	}
+}
+
+// configureEndpoints registers the configured headers and proxies of the nodes of the session with the shared HTTP
+// client
+func configureEndpoints(config configs.Benchmark) error {
+	beaconAddresses := append([]string{config.BeaconNode.Address}, config.BeaconNode.OtherAddresses...)
+	if address := config.BeaconNode.GRPC.Address; config.BeaconNode.GRPC.Enabled && address != "" {
+		target, err := grpcclient.Target(address)
+		if err != nil {
+			return err
+		}
+		beaconAddresses = append(beaconAddresses, target)
+	}
+	// The fallback stands in for the beacon node, so it is reached like it
+	if failover := config.BeaconNode.Metrics.Failover; failover.Enabled {
+		beaconAddresses = append(beaconAddresses, failover.Fallback)
+	}
+
+	endpoints := []struct {
+		addresses []string
+		headers   map[string]string
+		proxy     string
+	}{
+		{beaconAddresses, config.BeaconNode.Headers, config.BeaconNode.Proxy},
+		{[]string{config.ExecutionNode.Address}, config.ExecutionNode.Headers, config.ExecutionNode.Proxy},
+		{[]string{config.ExecutionNode.EngineAddress}, nil, config.ExecutionNode.Proxy},
+		{append([]string{config.ValidatorClient.Address}, config.ValidatorClient.OtherAddresses...), config.ValidatorClient.Headers, config.ValidatorClient.Proxy},
+	}
+	for _, endpoint := range endpoints {
+		for _, address := range endpoint.addresses {
+			if err := configureEndpoint(address, endpoint.headers, endpoint.proxy); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// configureEndpoint registers the headers and the proxy of a single node address
+func configureEndpoint(address string, headers map[string]string, proxy string) error {
+	if address == "" || ipc.IsSocket(address) {
+		return nil
+	}
+	if len(headers) != 0 {
+		if err := httpclient.SetHeaders(address, headers); err != nil {
+			return errors.Join(err, errors.New("failed setting the headers of the nodes"))
+		}
+	}
+	if proxy != "" {
+		if err := httpclient.SetProxy(address, proxy); err != nil {
+			return errors.Join(err, fmt.Errorf("failed setting the proxy of '%s'", address))
+		}
+	}
+	return nil
+}
+
+// detectClients identifies the clients of the session so metrics can use their client specific adapters
+func detectClients(config configs.Benchmark) clientinfo.Detection {
+	ctx, cancel := context.WithTimeout(context.Background(), clientDetectionTimeout)
+	defer cancel()
+
+	clients := clientinfo.Detect(ctx, config.BeaconNode.Address, config.ExecutionNode.Address)
+	if config.BeaconNode.GRPC.Enabled {
+		clients.ConsensusGRPC = selectGRPC(ctx, config.BeaconNode, clients.Consensus)
+	}
+	return clients
+}
+
+// selectGRPC returns the native gRPC API of the consensus client when it answers, otherwise the metrics keep using the
+// beacon API
+func selectGRPC(ctx context.Context, config configs.BeaconNode, adapter clientinfo.Adapter) string {
+	address := config.GRPC.Address
+	if address == "" {
+		var err error
+		if address, err = adapter.GRPCAddress(config.Address); err != nil {
+			slog.With("err", err.Error()).Info("consensus client has no gRPC API, using the beacon API")
+			return ""
+		}
+		// The default address is on another port than the beacon API, it gets the headers and proxy of the node too
+		target, err := grpcclient.Target(address)
+		if err == nil {
+			err = configureEndpoint(target, config.Headers, config.Proxy)
+		}
+		if err != nil {
+			slog.With("err", err.Error()).With("address", address).Warn("failed configuring consensus client gRPC API, using the beacon API")
+			return ""
+		}
+	}
+
+	syncing, err := consensus.GRPCSyncing(ctx, address)
+	if err != nil {
+		slog.With("err", err.Error()).With("address", address).Warn("consensus client gRPC API not reachable, using the beacon API")
+		return ""
+	}
+	slog.With("address", address).With("syncing", syncing).Info("using consensus client gRPC API")
+	return address
+}
+
+// verifyNetwork fails fast when the beacon node is on another network than configured and returns its identity. An
+// unreachable node doesn't fail the run, its metrics report it, and has no identity.
+func verifyNetwork(config configs.Benchmark, benchmarkRun *run.Run) (*consensus.Identity, error) {
+	if config.BeaconNode.Address == "" {
+		return nil, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), clientDetectionTimeout)
+	defer cancel()
+
+	identity, err := consensus.FetchIdentity(ctx, config.BeaconNode.Address)
+	if err != nil {
+		slog.With("err", err.Error()).Warn("failed verifying the network of the beacon node")
+		return nil, nil
+	}
+	if benchmarkRun != nil {
+		benchmarkRun.SetMetadata(sessionKey("identity", config.Name), identity)
+	}
+
+	return &identity, identity.VerifyNetwork(network.Name(config.Network))
+}
+
+// warnForkReadiness warns about clients which stop following the chain at an upcoming fork of the network
+func warnForkReadiness(config configs.Benchmark, clients clientinfo.Detection, identity *consensus.Identity, spec network.Spec) {
+	var schedule map[string]uint64
+	if identity != nil {
+		schedule = identity.ScheduledVersions()
+	}
+	for _, readiness := range forks.Check(network.Name(config.Network), forks.Schedule, clients, schedule, spec.Epoch(spec.CurrentSlot())) {
+		if !readiness.Upcoming || (readiness.Status != report.ForkOutdated && readiness.Status != report.ForkUnscheduled) {
+			continue
+		}
+		slog.
+			With("fork", readiness.Fork).
+			With("epoch", readiness.Epoch).
+			With("layer", readiness.Layer).
+			With("client", readiness.Client).
+			With("version", readiness.Version).
+			With("minimum", readiness.Minimum).
+			Warn("client is not ready for the upcoming fork, " + readiness.Detail)
+	}
+}
+
+// fetchSpec reads the timing of the chain from the beacon node, so the slot and epoch math follows the network the
+// node is on. The preset of the configured network is used when the node can't be asked, a node whose deposit chain
+// differs from the preset fails the run.
+func fetchSpec(config configs.Benchmark) (network.Spec, error) {
+	fallback := network.DefaultSpec(network.Name(config.Network))
+	if config.BeaconNode.Address == "" {
+		return fallback, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), clientDetectionTimeout)
+	defer cancel()
+
+	spec, err := consensus.FetchSpec(ctx, config.BeaconNode.Address, fallback)
+	if err != nil {
+		slog.With("err", err.Error()).Warn("failed fetching the spec of the beacon node, using the timing of the configured network")
+		return fallback, nil
+	}
+	slog.
+		With("genesis_time", spec.GenesisTime).
+		With("slot_duration", spec.SlotDuration).
+		With("slots_per_epoch", spec.SlotsPerEpoch).
+		Debug("fetched the spec of the beacon node")
+	return spec, spec.Verify(network.Name(config.Network))
+}
+
+// sessionKey suffixes the key with the session name when running multiple sessions
+func sessionKey(key, session string) string {
+	if session == "" {
+		return key
+	}
+	return fmt.Sprintf("%s-%s", key, session)
+}
+
+func newPushgateway(config configs.Pushgateway, benchmarkRun *run.Run) *report.Pushgateway {
+	job := config.Job
+	if job == "" {
+		job = defaultPushgatewayJob
+	}
+	grouping := make(map[string]string)
+	if benchmarkRun != nil {
+		grouping["run_id"] = benchmarkRun.ID
+	}
+	return report.NewPushgateway(config.URL, job, grouping, config.Interval)
+}
+
+func withPushgateway(r report.Sink, pushgateway *report.Pushgateway) report.Sink {
+	if pushgateway == nil {
+		return r
+	}
+	return report.NewMulti(r, pushgateway)
+}
+
 func addFlags(cobraCMD *cobra.Command) {
 	// Flags related to benchmark duration and server port
 	cobraCMD.Flags().Duration(durationFlag, defaultExecutionDuration, "Duration for which the application will run to gather metrics, e.g. '5m'")
+	cobraCMD.Flags().String(presetFlag, "", "Preset of duration and metrics: 'quick' (5m connectivity sanity check), 'standard' (1h with the validator duties) or 'deep' (24h adding the metrics loading the nodes), overridden by the other settings")
 	cobraCMD.Flags().Uint16(serverPortFlag, defaultServerPort, "Web server port with metrics endpoint exposed, e.g. '8080'")
+	cobraCMD.Flags().Float64Slice(latencyBucketsFlag, exporter.DefaultLatencyBuckets, "Buckets in seconds of the latency histograms on the metrics endpoint, scrapers supporting native histograms ignore them")
 
 	// Consensus client related flags
 	cobraCMD.Flags().String(consensusAddrFlag, "", "Consensus client address (beacon node API) with scheme (HTTP/HTTPS) and port, e.g. https://lighthouse:5052")
+	cobraCMD.Flags().Bool(consensusGRPCFlag, false, "Collect the peers and the attestation data through the native gRPC API of consensus clients having one (Prysm), when it answers")
+	cobraCMD.Flags().String(consensusGRPCAddrFlag, "", "Native gRPC API of the consensus client as 'host:port' or 'https://host:port', defaults to the gRPC port of the detected client, e.g. prysm:4000")
 	cobraCMD.Flags().Bool(consensusMetricClientFlag, true, "Enable consensus client metric")
 	cobraCMD.Flags().Bool(consensusMetricLatencyFlag, true, "Enable consensus client latency metric")
 	cobraCMD.Flags().Bool(consensusMetricPeersFlag, true, "Enable consensus client peers metric")
 	cobraCMD.Flags().Bool(consensusMetricAttestationFlag, true, "Enable consensus client attestation metric")
+	cobraCMD.Flags().Int(consensusBackfillEpochsFlag, 0, "Epochs before the run the attestation metric evaluates at startup, the head votes are backfilled for the validators set by --"+validatorIndicesFlag)
+	cobraCMD.Flags().Bool(consensusMetricHeadDelayFlag, true, "Enable consensus client head delay metric")
+	cobraCMD.Flags().Bool(consensusMetricSyncFlag, true, "Enable consensus client sync distance metric")
+	cobraCMD.Flags().Bool(consensusMetricDutySimFlag, false, "Enable validator attestation duty simulation against the consensus client")
+	cobraCMD.Flags().Bool(consensusMetricBalancesFlag, false, "Enable balance and rewards tracking of the validators set by --"+validatorIndicesFlag)
+	cobraCMD.Flags().Bool(consensusMetricSyncCommFlag, false, "Enable sync committee participation tracking of the validators set by --"+validatorIndicesFlag)
+	cobraCMD.Flags().Bool(consensusMetricProposalFlag, false, "Enable periodic block production dry runs, blocks are never signed nor published")
+	cobraCMD.Flags().Bool(consensusMetricDutyCalFlag, false, "Enable proposer and sync committee duty tracking of the validators set by --"+validatorIndicesFlag+", resource spikes during their proposals are expected")
+	cobraCMD.Flags().Bool(consensusMetricInboundFlag, true, "Enable consensus client inbound P2P connectivity metric")
+	cobraCMD.Flags().Bool(consensusMetricNetworkFlag, true, "Enable consensus client network and fork verification metric")
+	cobraCMD.Flags().Bool(consensusMetricRuntimeFlag, true, "Enable Go runtime stats (goroutines, heap, GC pauses) of Go consensus clients, e.g. Prysm")
+	cobraCMD.Flags().String(consensusRuntimeAddrFlag, "", "Prometheus endpoint of the consensus client, the default endpoint of the detected client when empty, e.g. http://prysm:8080/metrics")
+	cobraCMD.Flags().StringSlice(consensusOtherAddrsFlag, nil, "Beacon API addresses of further beacon nodes whose votes are cross-checked, e.g. a fallback node running another client")
+	cobraCMD.Flags().StringSlice(consensusCheckpointFlag, nil, "Checkpoint sync endpoints whose finalized checkpoint is compared with the one of the consensus client at run start, e.g. 'https://mainnet.checkpoint.sigp.io'")
+	cobraCMD.Flags().Bool(consensusMetricVoteCheckFlag, false, "Enable the head and target vote cross-check between the consensus client and the nodes set by --"+consensusOtherAddrsFlag)
+	cobraCMD.Flags().Bool(consensusMetricBlobsFlag, false, "Enable consensus client blob sidecar availability and retrieval latency metric")
+	cobraCMD.Flags().Bool(consensusMetricAggregationFlag, false, "Enable consensus client attestation pool size and aggregate attestation success and latency metric")
+	cobraCMD.Flags().Bool(consensusMetricBuilderFlag, false, "Enable the comparison of locally built payload values with the bids of the relays set by --"+consensusBuilderRelaysFlag)
+	cobraCMD.Flags().StringSlice(consensusBuilderRelaysFlag, nil, "MEV-Boost relays whose bids are compared with the local payloads, e.g. 'https://boost-relay.flashbots.net'")
+	cobraCMD.Flags().Bool(consensusMetricFailoverFlag, false, "Enable the measurement of how quickly the fallback set by --"+consensusFailoverAddrFlag+" serves duties once the consensus client is unavailable")
+	cobraCMD.Flags().String(consensusFailoverAddrFlag, "", "Beacon API address of the fallback beacon node the validator client switches over to")
+	cobraCMD.Flags().Duration(consensusFailoverSimulateFlag, 0, "Interval at which an outage of the consensus client is simulated to measure the switchover to the fallback, e.g. '1h', 0 only observes real outages")
+	cobraCMD.Flags().String(consensusInboundProbeURLFlag, "", "External port check service, '{host}' and '{port}' are replaced by the ENR address and a 2xx status means reachable, e.g. 'https://portcheck.example/{host}/{port}'")
+
+	// Validator related flags
+	cobraCMD.Flags().String(validatorAddrFlag, "", "Validator client keymanager API address with scheme (HTTP/HTTPS) and port, e.g. http://lighthouse-vc:5062")
+	cobraCMD.Flags().UintSlice(validatorIndicesFlag, nil, "Indices of the validators run by this node, e.g. '1234,5678'")
+	cobraCMD.Flags().StringSlice(validatorOtherAddrsFlag, nil, "Keymanager API addresses of other validator clients which must not load the same keys, e.g. a backup machine")
+	cobraCMD.Flags().String(validatorSlashingProtectionPathFlag, "", "Slashing protection database file or directory of the validator client, e.g. /data/validators/slashing_protection.sqlite")
+	cobraCMD.Flags().String(validatorTokenPathFlag, "", "Keymanager API token file of the validator client, e.g. /data/validators/api-token.txt")
+	cobraCMD.Flags().Bool(validatorMetricKeySafetyFlag, false, "Enable validator client key and slashing protection safety checks")
 
 	// Execution client related flags
-	cobraCMD.Flags().String(executionAddrFlag, "", "Execution client address with scheme (HTTP/HTTPS) and port, e.g. https://geth:8545")
+	cobraCMD.Flags().String(executionAddrFlag, "", "Execution client address with scheme (HTTP/HTTPS) and port, e.g. https://geth:8545, or the path of its IPC socket, e.g. /data/geth/geth.ipc")
+	cobraCMD.Flags().String(executionEngineAddrFlag, "", "Execution client authenticated Engine API address with scheme (HTTP/HTTPS) and port, e.g. http://geth:8551")
+	cobraCMD.Flags().String(executionJWTSecretPathFlag, "", "Path to the hex encoded JWT secret shared by consensus and execution clients, e.g. /secrets/jwt.hex")
 	cobraCMD.Flags().Bool(executionMetricPeersFlag, true, "Enable execution client peers metric")
+	cobraCMD.Flags().Bool(executionMetricAdminPeersFlag, false, "Enable admin_peers protocol and direction breakdown of execution client peers metric")
 	cobraCMD.Flags().Bool(executionMetricLatencyFlag, true, "Enable execution client latency metric")
+	cobraCMD.Flags().Bool(executionMetricEngineFlag, false, "Enable execution client Engine API metric")
+	cobraCMD.Flags().Bool(executionMetricEngineSimFlag, false, "Simulate consensus client engine_newPayloadV3 calls in the Engine API metric")
+	cobraCMD.Flags().Bool(executionMetricRuntimeFlag, true, "Enable Go runtime stats (goroutines, heap, GC pauses) of Go execution clients, e.g. Geth")
+	cobraCMD.Flags().String(executionRuntimeAddrFlag, "", "Prometheus endpoint of the execution client, the default endpoint of the detected client when empty, e.g. http://geth:6060/debug/metrics/prometheus")
+	cobraCMD.Flags().Bool(executionMetricInboundFlag, false, "Enable execution client inbound P2P connectivity metric, requires the admin RPC namespace")
+	cobraCMD.Flags().String(executionInboundProbeURLFlag, "", "External port check service, '{host}' and '{port}' are replaced by the enode address and a 2xx status means reachable, e.g. 'https://portcheck.example/{host}/{port}'")
+	cobraCMD.Flags().Bool(executionMetricStateFlag, false, "Enable execution client state access metric, times eth_getProof and eth_call against the disk reads of the host")
 
 	// Infrastructure metric flags (CPU and Memory)
 	cobraCMD.Flags().Bool(infraMetricCPUFlag, true, "Enable infrastructure CPU metric")
 	cobraCMD.Flags().Bool(infraMetricMemoryFlag, true, "Enable infrastructure memory metric")
+	cobraCMD.Flags().Bool(infraMetricPressureFlag, true, "Enable infrastructure swap, memory pressure (PSI) and OOM kill metric, Linux only")
+	cobraCMD.Flags().Bool(infraMetricLoadFlag, true, "Enable infrastructure load average metric, normalized by the core count, Linux only")
+	cobraCMD.Flags().Bool(infraSchedLatencyFlag, false, "Sample the scheduler run queue latency from /proc/schedstat along with the load averages")
+	cobraCMD.Flags().Bool(infraMetricHardwareFlag, false, "Enable hardware checks for ARM boards (e.g. Raspberry Pi): undervoltage, throttling, SD card storage and a suitability verdict")
+	cobraCMD.Flags().Bool(infraMetricProbeFlag, false, "Enable infrastructure packet loss and jitter probe metric")
+	cobraCMD.Flags().StringSlice(infraProbeHostsFlag, []string{"1.1.1.1"}, "Hosts probed with ICMP echo requests, or with TCP connects when given as 'host:port', e.g. '1.1.1.1,203.0.113.5:30303'")
+	cobraCMD.Flags().Bool(infraMetricDataDirsFlag, false, "Enable growth tracking of the client data directories with a disk full projection")
+	cobraCMD.Flags().StringSlice(infraDataDirsFlag, nil, "Data directories of the clients, e.g. '/var/lib/lighthouse,/var/lib/geth/geth/chaindata'")
+	cobraCMD.Flags().Bool(infraPortsAuditFlag, true, "Audit the listening ports at run start for publicly reachable RPC, keymanager and metrics APIs")
+	cobraCMD.Flags().Bool(infraJournalFlag, false, "Annotate the timeline with the restarts of the units set by --"+infraJournalUnitsFlag+" and the OOM kills and segfaults in the systemd journal")
+	cobraCMD.Flags().StringSlice(infraJournalUnitsFlag, nil, "systemd units of the clients, e.g. 'geth,lighthouse-beacon'")
+	cobraCMD.Flags().Bool(infraDiskBenchFlag, false, "Measure the 4k random read IOPS and the fsync latency of the disk at run start, recorded in the run metadata")
+	cobraCMD.Flags().String(infraDiskBenchDirFlag, "", "Directory on the measured disk the test file is written to, defaults to the first data directory")
+	cobraCMD.Flags().Int64(infraDiskBenchSizeFlag, 256, "Size of the disk benchmark test file in MB")
+	cobraCMD.Flags().Duration(infraDiskBenchDurationFlag, time.Second*5, "Duration of the random reads and of the synced writes of the disk benchmark")
+
+	// Observer flag
+	cobraCMD.Flags().Bool(observerMetricFlag, true, "Enable tracking of the benchmark tool's own CPU, memory, goroutine and GC usage")
 
 	// Ethereum network flag
-	cobraCMD.Flags().String(networkFlag, "", "Ethereum network to use, either 'mainnet' or 'holesky'")
+	cobraCMD.Flags().String(networkFlag, "", "Network to use: 'mainnet', 'holesky', 'sepolia', 'hoodi', 'gnosis', 'chiado' or 'custom' for any other network timed by the spec of the beacon node")
+
+	// Report flags
+	cobraCMD.Flags().String(reportLocaleFlag, "en", "Number formatting locale of the report, one of 'en', 'de', 'fr', 'de-CH' or 'raw' for machine readable numbers")
+	cobraCMD.Flags().String(reportTimezoneFlag, "local", "Timezone of the timestamps of the report, 'local', 'utc' or an IANA name, e.g. 'Europe/Berlin'")
+	cobraCMD.Flags().String(reportDurationFormatFlag, format.DurationScaled, "Format of the durations of the report, one of 'scaled', 'seconds' or 'milliseconds'")
+	cobraCMD.Flags().Bool(reportEpochsFlag, false, "Add a breakdown of the consensus measurements per epoch to the report")
+	cobraCMD.Flags().Bool(reportHistogramsFlag, false, "Add ASCII histograms of the measured durations to the latency metrics of the report")
+	cobraCMD.Flags().Bool(reportTimelineFlag, false, "Add a timeline of the health of every metric over the run to the report")
+	cobraCMD.Flags().String(reportTemplateFlag, "", "Go template file rendering the report in a custom layout, e.g. 'report.md.tmpl'")
+	cobraCMD.Flags().String(pushgatewayURLFlag, "", "Prometheus Pushgateway address the run pushes its metrics to, e.g. http://pushgateway:9091")
+	cobraCMD.Flags().Duration(pushgatewayIntervalFlag, time.Minute, "Interval of intermediate pushes to the Pushgateway, 0 to push final values only")
+	cobraCMD.Flags().String(healthcheckURLFlag, "", "Dead man's switch pinged while the run lasts and once it completes, e.g. https://hc-ping.com/<uuid>")
+	cobraCMD.Flags().Duration(healthcheckIntervalFlag, time.Minute*5, "Interval of the health check pings while the run lasts, 0 to ping at the start and the end only")
+	cobraCMD.Flags().String(alertPagerDutyKeyFlag, "", "File of the PagerDuty Events API v2 routing key, incidents are opened for High severity conditions lasting the sustain duration")
+	cobraCMD.Flags().String(alertOpsgenieKeyFlag, "", "File of the Opsgenie API integration key, alerts are opened for High severity conditions lasting the sustain duration")
+	cobraCMD.Flags().String(alertOpsgenieURLFlag, alert.DefaultOpsgenieURL, "Opsgenie API address, e.g. https://api.eu.opsgenie.com for the EU instance")
+	cobraCMD.Flags().Duration(alertSustainFlag, time.Minute*5, "How long a High severity condition lasts before an incident is opened")
+	cobraCMD.Flags().String(speedTestIperf3Flag, "", "iperf3 server ('host[:port]') the bandwidth is measured against at run start and end, requires the iperf3 binary")
+	cobraCMD.Flags().String(speedTestDownloadURLFlag, "", "HTTP test file downloaded to measure the bandwidth at run start and end")
+	cobraCMD.Flags().String(speedTestUploadURLFlag, "", "HTTP endpoint data is uploaded to, to measure the upload bandwidth at run start and end")
+	cobraCMD.Flags().Duration(speedTestDurationFlag, time.Second*10, "Duration of every direction of the bandwidth speed test")
+	cobraCMD.Flags().String(recordFlag, "", "Directory all HTTP, event stream and IPC responses of the nodes are recorded to, to replay the run later")
+	cobraCMD.Flags().String(replayFlag, "", "Directory of a recording the run is replayed from instead of contacting the nodes, the clock starts at the recorded time. Metrics dialing the nodes or hosts themselves (IPC latency, probe, speed test) can not be replayed")
+	cobraCMD.Flags().Bool(strictReadOnlyFlag, false, "Block every request which could change the nodes, e.g. publishing or admin calls, and audit all requests to "+defaultAuditLog+" unless another audit log is set")
+	cobraCMD.Flags().String(proxyFlag, "", "HTTP(S) or SOCKS5 proxy the nodes are reached through, e.g. socks5://127.0.0.1:9050 for Tor, the proxy of the environment (HTTPS_PROXY) when empty")
+	cobraCMD.Flags().String(consensusProxyFlag, "", "Proxy of the consensus client addresses, overriding --proxy, 'direct' to bypass it")
+	cobraCMD.Flags().String(executionProxyFlag, "", "Proxy of the execution client addresses, overriding --proxy, 'direct' to bypass it")
+	cobraCMD.Flags().String(validatorProxyFlag, "", "Proxy of the validator client addresses, overriding --proxy, 'direct' to bypass it")
+	cobraCMD.Flags().String(userAgentFlag, "", "User-Agent of the requests to the nodes, the default of the HTTP client when empty")
+	cobraCMD.Flags().String(auditLogFlag, "", "File every HTTP and JSON-RPC request made to the nodes is logged to as JSON lines")
+	cobraCMD.Flags().String(startAtFlag, "", "Time the measurements start at, RFC 3339 formatted, so runs on several machines measure the same period")
+	cobraCMD.Flags().String(recordsOutFlag, "", "File the records of all sessions are written to as JSON, used by fleet agents")
+	_ = cobraCMD.Flags().MarkHidden(recordsOutFlag)
+	cobraCMD.Flags().Bool(updateCheckFlag, false, "Check for a newer release at startup and note it in the report footer")
+	cobraCMD.Flags().Bool(preflightFlag, true, "Probe what every enabled metric depends on, e.g. the beacon API endpoints, once before the run starts")
+	cobraCMD.Flags().String(preflightOnFailureFlag, configs.PreflightDisable, "What happens to metrics failing the preflight: 'disable' runs without them, 'abort' fails the run")
+	cobraCMD.Flags().Bool(redactFlag, false, "Replace the URLs, IPs, hostnames and validator indices in the reports, artifacts and exported metrics with hashed tokens, so they can be shared publicly")
+	cobraCMD.Flags().String(artifactsDirFlag, defaultArtifactsDir, "Directory every run writes its report, raw data points, logs and metadata to, empty to disable")
+	cobraCMD.Flags().Int(keepRunsFlag, 0, "Number of most recent runs kept in the artifacts directory, older ones are pruned at the start of a run, 0 keeps all")
+	cobraCMD.Flags().Int(keepDaysFlag, 0, "Days runs are kept in the artifacts directory, older ones are pruned at the start of a run, 0 keeps all")
+	cobraCMD.Flags().String(compressionFlag, "", "Compression of the raw data points of the artifacts, 'gzip' or 'zstd'")
+	cobraCMD.Flags().Duration(chunkFlag, 0, "Time span of the files the raw data points are written to while measuring, e.g. '1h', 0 writes a single file at the end")
+}
+
+// flagKeys maps every flag to the configuration key it overrides
+var flagKeys = []struct {
+	flag, key string
+}{
+	{durationFlag, "benchmark.duration"},
+	{presetFlag, "benchmark.preset"},
+	{serverPortFlag, "benchmark.server.port"},
+	{latencyBucketsFlag, "benchmark.server.latency_buckets"},
+	{consensusAddrFlag, "benchmark.beacon_node.address"},
+	{consensusGRPCFlag, "benchmark.beacon_node.grpc.enabled"},
+	{consensusGRPCAddrFlag, "benchmark.beacon_node.grpc.address"},
+	{executionAddrFlag, "benchmark.execution_node.address"},
+	{executionEngineAddrFlag, "benchmark.execution_node.engine_address"},
+	{executionJWTSecretPathFlag, "benchmark.execution_node.jwt_secret_path"},
+	{networkFlag, "benchmark.network"},
+	{consensusMetricClientFlag, "benchmark.beacon_node.metrics.client.enabled"},
+	{consensusMetricLatencyFlag, "benchmark.beacon_node.metrics.latency.enabled"},
+	{consensusMetricPeersFlag, "benchmark.beacon_node.metrics.peers.enabled"},
+	{consensusMetricAttestationFlag, "benchmark.beacon_node.metrics.attestation.enabled"},
+	{consensusBackfillEpochsFlag, "benchmark.beacon_node.metrics.attestation.backfill_epochs"},
+	{consensusMetricHeadDelayFlag, "benchmark.beacon_node.metrics.head_delay.enabled"},
+	{consensusMetricSyncFlag, "benchmark.beacon_node.metrics.sync_status.enabled"},
+	{consensusMetricDutySimFlag, "benchmark.beacon_node.metrics.duty_simulation.enabled"},
+	{consensusMetricBalancesFlag, "benchmark.beacon_node.metrics.balances.enabled"},
+	{consensusMetricSyncCommFlag, "benchmark.beacon_node.metrics.sync_committee.enabled"},
+	{consensusMetricProposalFlag, "benchmark.beacon_node.metrics.proposal_dry_run.enabled"},
+	{consensusMetricDutyCalFlag, "benchmark.beacon_node.metrics.duty_calendar.enabled"},
+	{consensusMetricNetworkFlag, "benchmark.beacon_node.metrics.network.enabled"},
+	{consensusMetricInboundFlag, "benchmark.beacon_node.metrics.inbound.enabled"},
+	{consensusInboundProbeURLFlag, "benchmark.beacon_node.metrics.inbound.probe_url"},
+	{consensusMetricRuntimeFlag, "benchmark.beacon_node.metrics.runtime.enabled"},
+	{consensusRuntimeAddrFlag, "benchmark.beacon_node.metrics.runtime.address"},
+	{consensusOtherAddrsFlag, "benchmark.beacon_node.other_addresses"},
+	{consensusCheckpointFlag, "benchmark.beacon_node.checkpoint_providers"},
+	{consensusMetricVoteCheckFlag, "benchmark.beacon_node.metrics.vote_cross_check.enabled"},
+	{consensusMetricBlobsFlag, "benchmark.beacon_node.metrics.blobs.enabled"},
+	{consensusMetricAggregationFlag, "benchmark.beacon_node.metrics.aggregation.enabled"},
+	{consensusMetricBuilderFlag, "benchmark.beacon_node.metrics.builder_comparison.enabled"},
+	{consensusBuilderRelaysFlag, "benchmark.beacon_node.metrics.builder_comparison.relays"},
+	{consensusMetricFailoverFlag, "benchmark.beacon_node.metrics.failover.enabled"},
+	{consensusFailoverAddrFlag, "benchmark.beacon_node.metrics.failover.fallback"},
+	{consensusFailoverSimulateFlag, "benchmark.beacon_node.metrics.failover.simulate_every"},
+	{validatorAddrFlag, "benchmark.validator_client.address"},
+	{validatorIndicesFlag, "benchmark.validator_client.indices"},
+	{validatorOtherAddrsFlag, "benchmark.validator_client.other_addresses"},
+	{validatorSlashingProtectionPathFlag, "benchmark.validator_client.slashing_protection_path"},
+	{validatorTokenPathFlag, "benchmark.validator_client.token_path"},
+	{validatorMetricKeySafetyFlag, "benchmark.validator_client.metrics.key_safety.enabled"},
+	{executionMetricPeersFlag, "benchmark.execution_node.metrics.peers.enabled"},
+	{executionMetricAdminPeersFlag, "benchmark.execution_node.metrics.admin_peers.enabled"},
+	{executionMetricLatencyFlag, "benchmark.execution_node.metrics.latency.enabled"},
+	{executionMetricEngineFlag, "benchmark.execution_node.metrics.engine.enabled"},
+	{executionMetricEngineSimFlag, "benchmark.execution_node.metrics.engine.simulate"},
+	{executionMetricRuntimeFlag, "benchmark.execution_node.metrics.runtime.enabled"},
+	{executionRuntimeAddrFlag, "benchmark.execution_node.metrics.runtime.address"},
+	{executionMetricInboundFlag, "benchmark.execution_node.metrics.inbound.enabled"},
+	{executionInboundProbeURLFlag, "benchmark.execution_node.metrics.inbound.probe_url"},
+	{executionMetricStateFlag, "benchmark.execution_node.metrics.state_access.enabled"},
+	{infraMetricCPUFlag, "benchmark.infrastructure.metrics.cpu.enabled"},
+	{infraMetricMemoryFlag, "benchmark.infrastructure.metrics.memory.enabled"},
+	{infraMetricPressureFlag, "benchmark.infrastructure.metrics.memory_pressure.enabled"},
+	{infraMetricLoadFlag, "benchmark.infrastructure.metrics.load.enabled"},
+	{infraSchedLatencyFlag, "benchmark.infrastructure.metrics.load.scheduler_latency"},
+	{infraMetricHardwareFlag, "benchmark.infrastructure.metrics.hardware.enabled"},
+	{infraMetricProbeFlag, "benchmark.infrastructure.metrics.probe.enabled"},
+	{infraProbeHostsFlag, "benchmark.infrastructure.metrics.probe.hosts"},
+	{infraMetricDataDirsFlag, "benchmark.infrastructure.metrics.data_dirs.enabled"},
+	{infraDataDirsFlag, "benchmark.infrastructure.metrics.data_dirs.paths"},
+	{infraPortsAuditFlag, "benchmark.infrastructure.ports_audit.enabled"},
+	{infraJournalFlag, "benchmark.infrastructure.journal.enabled"},
+	{infraJournalUnitsFlag, "benchmark.infrastructure.journal.units"},
+	{infraDiskBenchFlag, "benchmark.infrastructure.disk_bench.enabled"},
+	{infraDiskBenchDirFlag, "benchmark.infrastructure.disk_bench.dir"},
+	{infraDiskBenchSizeFlag, "benchmark.infrastructure.disk_bench.size_mb"},
+	{infraDiskBenchDurationFlag, "benchmark.infrastructure.disk_bench.duration"},
+	{observerMetricFlag, "benchmark.observer.enabled"},
+	{reportLocaleFlag, "benchmark.report.locale"},
+	{reportEpochsFlag, "benchmark.report.epochs"},
+	{reportHistogramsFlag, "benchmark.report.histograms"},
+	{reportTimelineFlag, "benchmark.report.timeline"},
+	{reportTemplateFlag, "benchmark.report.template"},
+	{reportTimezoneFlag, "benchmark.report.timezone"},
+	{reportDurationFormatFlag, "benchmark.report.duration_format"},
+	{pushgatewayURLFlag, "benchmark.export.pushgateway.url"},
+	{pushgatewayIntervalFlag, "benchmark.export.pushgateway.interval"},
+	{healthcheckURLFlag, "benchmark.export.healthcheck.url"},
+	{healthcheckIntervalFlag, "benchmark.export.healthcheck.interval"},
+	{alertPagerDutyKeyFlag, "benchmark.export.alerting.pagerduty_routing_key_path"},
+	{alertOpsgenieKeyFlag, "benchmark.export.alerting.opsgenie_api_key_path"},
+	{alertOpsgenieURLFlag, "benchmark.export.alerting.opsgenie_url"},
+	{alertSustainFlag, "benchmark.export.alerting.sustain"},
+	{artifactsDirFlag, "benchmark.artifacts.dir"},
+	{keepRunsFlag, "benchmark.artifacts.keep_runs"},
+	{keepDaysFlag, "benchmark.artifacts.keep_days"},
+	{compressionFlag, "benchmark.artifacts.compression"},
+	{chunkFlag, "benchmark.artifacts.chunk"},
+	{updateCheckFlag, "benchmark.update_check"},
+	{redactFlag, "benchmark.redact"},
+	{preflightFlag, "benchmark.preflight.enabled"},
+	{preflightOnFailureFlag, "benchmark.preflight.on_failure"},
+	{speedTestIperf3Flag, "benchmark.speed_test.iperf3"},
+	{speedTestDownloadURLFlag, "benchmark.speed_test.download_url"},
+	{speedTestUploadURLFlag, "benchmark.speed_test.upload_url"},
+	{speedTestDurationFlag, "benchmark.speed_test.duration"},
+	{recordFlag, "benchmark.recording.record"},
+	{replayFlag, "benchmark.recording.replay"},
+	{strictReadOnlyFlag, "benchmark.audit.strict_read_only"},
+	{auditLogFlag, "benchmark.audit.log"},
+	{userAgentFlag, "benchmark.user_agent"},
+	{proxyFlag, "benchmark.proxy"},
+	{consensusProxyFlag, "benchmark.beacon_node.proxy"},
+	{executionProxyFlag, "benchmark.execution_node.proxy"},
+	{validatorProxyFlag, "benchmark.validator_client.proxy"},
 }
 
 func bindFlags(cmd *cobra.Command) error {
-	// Bind flags for benchmark duration, server, and client configurations
-	if err := viper.BindPFlag("benchmark.duration", cmd.Flags().Lookup(durationFlag)); err != nil {
-		return err
-	}
-	if err := viper.BindPFlag("benchmark.server.port", cmd.Flags().Lookup(serverPortFlag)); err != nil {
-		return err
-	}
-	if err := viper.BindPFlag("benchmark.consensus.address", cmd.Flags().Lookup(consensusAddrFlag)); err != nil {
-		return err
-	}
-	if err := viper.BindPFlag("benchmark.execution.address", cmd.Flags().Lookup(executionAddrFlag)); err != nil {
-		return err
-	}
-	if err := viper.BindPFlag("benchmark.network", cmd.Flags().Lookup(networkFlag)); err != nil {
-		return err
-	}
-	if err := viper.BindPFlag("benchmark.consensus.metrics.client.enabled", cmd.Flags().Lookup(consensusMetricClientFlag)); err != nil {
-		return err
-	}
-	if err := viper.BindPFlag("benchmark.consensus.metrics.latency.enabled", cmd.Flags().Lookup(consensusMetricLatencyFlag)); err != nil {
-		return err
-	}
-	if err := viper.BindPFlag("benchmark.consensus.metrics.peers.enabled", cmd.Flags().Lookup(consensusMetricPeersFlag)); err != nil {
-		return err
-	}
-	if err := viper.BindPFlag("benchmark.consensus.metrics.attestation.enabled", cmd.Flags().Lookup(consensusMetricAttestationFlag)); err != nil {
-		return err
-	}
-	if err := viper.BindPFlag("benchmark.execution.metrics.peers.enabled", cmd.Flags().Lookup(executionMetricPeersFlag)); err != nil {
-		return err
-	}
-	if err := viper.BindPFlag("benchmark.execution.metrics.latency.enabled", cmd.Flags().Lookup(executionMetricLatencyFlag)); err != nil {
-		return err
-	}
-	if err := viper.BindPFlag("benchmark.infrastructure.metrics.cpu.enabled", cmd.Flags().Lookup(infraMetricCPUFlag)); err != nil {
-		return err
-	}
-	if err := viper.BindPFlag("benchmark.infrastructure.metrics.memory.enabled", cmd.Flags().Lookup(infraMetricMemoryFlag)); err != nil {
-		return err
+	for _, binding := range flagKeys {
+		if err := viper.BindPFlag(binding.key, cmd.Flags().Lookup(binding.flag)); err != nil {
+			return errors.Join(err, fmt.Errorf("failed binding flag '%s'", binding.flag))
+		}
 	}
-
 	return nil
 }
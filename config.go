@@ -0,0 +1,157 @@
+package benchmark
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/Harikakasimahanthi/benchmark-test/configs"
+)
+
+// keyDescriptions documents the configuration keys which have no flag
+var keyDescriptions = map[string]string{
+	"benchmark.name":                                          "Name of the session, only used within 'sessions'",
+	"benchmark.beacon_node.headers":                           "Extra headers sent to every address of the consensus client, by name, e.g. for reverse proxies filtering by header",
+	"benchmark.execution_node.headers":                        "Extra headers sent to the address of the execution client, by name, e.g. for hosted providers filtering by header",
+	"benchmark.validator_client.headers":                      "Extra headers sent to every keymanager API address of the validator clients, by name",
+	"benchmark.beacon_node.metrics.latency.paths":             "Alternative network routes to the consensus client, each with 'name', 'address' and 'proxy'",
+	"benchmark.execution_node.metrics.latency.paths":          "Alternative network routes to the execution client, each with 'name', 'address' and 'proxy'",
+	"benchmark.validator_client.metrics.proposals.enabled":    "Measure block proposals of the validators",
+	"benchmark.validator_client.metrics.attestations.enabled": "Measure attestations of the validators",
+	"benchmark.validator_client.metrics.duties.enabled":       "Measure duties of the validators",
+	"benchmark.infrastructure.metrics.disk.enabled":           "Measure disk usage",
+	"benchmark.report.mode":                                   "Report of multiple sessions, either 'separate' or 'merged'",
+	"benchmark.report.severities":                             "Weights of the severities when scoring the health, each with 'name' and 'weight'",
+	"benchmark.export.pushgateway.job":                        "Job name of the metrics pushed to the Pushgateway",
+	"benchmark.chaos.faults":                                  "Degraded readings injected on purpose to verify alerts trigger, each with 'group', 'metric', 'measurement', 'value', 'after' and 'duration'",
+	"benchmark.trends":                                        "Health conditions on the change of a measurement within a window, each with 'group', 'metric', 'measurement', 'operator', 'change', 'percent', 'window' and 'severity'",
+	"benchmark.rules":                                         "Health rules spanning several metrics, each with 'name', 'severity', 'hint' and 'conditions' of 'group', 'metric', 'measurement', 'statistic', 'operator' and 'threshold'",
+	"benchmark.sessions":                                      "Sessions benchmarking other nodes side by side, each overriding the keys of this section",
+}
+
+// suffixDescriptions documents the keys without flag shared by many metrics, by the last element of the key
+var suffixDescriptions = map[string]string{
+	"timeout": "Timeout of a single measurement, shorter than the interval of the metric, the default of the metric when '0s'",
+}
+
+var ConfigCMD = &cobra.Command{
+	Use:   "config",
+	Short: "Inspect the benchmark configuration",
+	// Inspecting the configuration must work while it is invalid or missing
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		return nil
+	},
+}
+
+var PrintDefaultsCMD = &cobra.Command{
+	Use:   "print-defaults",
+	Short: "Print a commented configuration file with the default values",
+	RunE: func(cobraCMD *cobra.Command, args []string) error {
+		return printDefaults(cobraCMD.OutOrStdout(), CMD.Flags())
+	},
+}
+
+func init() {
+	ConfigCMD.AddCommand(PrintDefaultsCMD)
+	CMD.AddCommand(ConfigCMD)
+}
+
+// printDefaults writes every configuration key as YAML, documented by the usage of its flag and its environment variable
+func printDefaults(w io.Writer, flags *pflag.FlagSet) error {
+	flagsByKey := make(map[string]*pflag.Flag, len(flagKeys))
+	for _, binding := range flagKeys {
+		flag := flags.Lookup(binding.flag)
+		if flag == nil {
+			return fmt.Errorf("flag '%s' was not found", binding.flag)
+		}
+		flagsByKey[binding.key] = flag
+	}
+
+	var section []string
+	for _, field := range configs.Fields(configs.BenchmarkPrefix, configs.Benchmark{}) {
+		path := strings.Split(field.Key, ".")
+		parents, name := path[:len(path)-1], path[len(path)-1]
+
+		// Open the sections which differ from the ones of the previous key
+		common := 0
+		for common < len(section) && common < len(parents) && section[common] == parents[common] {
+			common++
+		}
+		for depth := common; depth < len(parents); depth++ {
+			fmt.Fprintf(w, "%s%s:\n", indent(depth), parents[depth])
+		}
+		section = parents
+
+		value, comment, sources := defaultValue(field.Type, nil), keyDescriptions[field.Key], []string{}
+		if comment == "" {
+			comment = suffixDescriptions[name]
+		}
+		flag, ok := flagsByKey[field.Key]
+		if ok {
+			value, comment = defaultValue(field.Type, flag), flag.Usage
+			sources = append(sources, "--"+flag.Name)
+		}
+		// Lists of sections and maps can only be configured in the file
+		if (field.Type.Kind() != reflect.Slice || field.Type.Elem().Kind() != reflect.Struct) && field.Type.Kind() != reflect.Map {
+			sources = append(sources, configs.EnvName(field.Key))
+		}
+		if len(sources) != 0 {
+			comment += fmt.Sprintf(" (%s)", strings.Join(sources, ", "))
+		}
+		fmt.Fprintf(w, "%s# %s\n", indent(len(parents)), comment)
+		fmt.Fprintf(w, "%s%s: %s\n", indent(len(parents)), name, value)
+	}
+	return nil
+}
+
+func indent(depth int) string {
+	return strings.Repeat("  ", depth)
+}
+
+// defaultValue formats the default of the flag as YAML, or the zero value of the type without flag
+func defaultValue(t reflect.Type, flag *pflag.Flag) string {
+	if t == reflect.TypeOf(time.Duration(0)) {
+		if flag == nil {
+			return `"0s"`
+		}
+		return fmt.Sprintf("%q", flag.DefValue)
+	}
+
+	switch t.Kind() {
+	case reflect.Slice:
+		var values []string
+		if flag != nil {
+			if slice, ok := flag.Value.(pflag.SliceValue); ok {
+				values = slice.GetSlice()
+			}
+		}
+		for i, value := range values {
+			if t.Elem().Kind() == reflect.String {
+				values[i] = fmt.Sprintf("%q", value)
+			}
+		}
+		return "[" + strings.Join(values, ", ") + "]"
+	case reflect.String:
+		if flag == nil {
+			return `""`
+		}
+		return fmt.Sprintf("%q", flag.DefValue)
+	case reflect.Map:
+		return "{}"
+	case reflect.Bool:
+		if flag == nil {
+			return "false"
+		}
+		return flag.DefValue
+	default:
+		if flag == nil {
+			return "0"
+		}
+		return flag.DefValue
+	}
+}
@@ -2,29 +2,138 @@ package configs
 
 import (
 	"errors"
+	"fmt"
+	"net"
 	"net/url"
+	"os"
+	"os/exec"
+	"strings"
 	"time"
 
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/exporter"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/ipc"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/journal"
 	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/network"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/run"
 )
 
 type Metric struct {
 	Enabled bool `mapstructure:"enabled"`
 }
 
+// TimedMetric contacts an endpoint, Timeout bounds a single measurement and must be shorter than the interval of the
+// metric. Without timeout the default of the metric is used. The first measurement is taken at the start of the run,
+// WarmUp waits one interval instead, e.g. for a node whose first answers are slow while it warms its caches.
+type TimedMetric struct {
+	Metric  `mapstructure:",squash"`
+	Timeout time.Duration `mapstructure:"timeout"`
+	WarmUp  bool          `mapstructure:"warm_up"`
+}
+
 // Consensus layer (Beacon Node) metrics
 type BeaconMetrics struct {
-	Client      Metric `mapstructure:"client"`
-	Latency     Metric `mapstructure:"latency"`
-	Peers       Metric `mapstructure:"peers"`
-	Attestation Metric `mapstructure:"attestation"`
-	SyncStatus  Metric `mapstructure:"sync_status"`
+	Client      TimedMetric       `mapstructure:"client"`
+	Latency     LatencyMetric     `mapstructure:"latency"`
+	Peers       TimedMetric       `mapstructure:"peers"`
+	Attestation AttestationMetric `mapstructure:"attestation"`
+	SyncStatus  TimedMetric       `mapstructure:"sync_status"`
+	HeadDelay   Metric            `mapstructure:"head_delay"`
+	// DutySimulation replays the attestation workflow of the configured validators against the beacon node
+	DutySimulation Metric `mapstructure:"duty_simulation"`
+	// Balances tracks the rewards of the configured validators
+	Balances TimedMetric `mapstructure:"balances"`
+	// SyncCommittee tracks the sync committee message inclusion of the configured validators
+	SyncCommittee TimedMetric `mapstructure:"sync_committee"`
+	// ProposalDryRun periodically requests an unsigned block without publishing it
+	ProposalDryRun Metric `mapstructure:"proposal_dry_run"`
+	// Inbound checks whether the P2P port of the node is reachable from outside
+	Inbound InboundMetric `mapstructure:"inbound"`
+	// Network verifies the node stays on the configured network and fork
+	Network TimedMetric `mapstructure:"network"`
+	// Runtime scrapes the Go runtime stats of Go clients (e.g. Prysm) from their Prometheus endpoint
+	Runtime RuntimeMetric `mapstructure:"runtime"`
+	// VoteCrossCheck compares the head and target votes of the beacon node with the ones of the other beacon nodes
+	VoteCrossCheck Metric `mapstructure:"vote_cross_check"`
+	// DutyCalendar tracks the proposer and sync committee duties of the configured validators
+	DutyCalendar Metric `mapstructure:"duty_calendar"`
+	// Blobs checks the blob sidecars of every block committing to blobs are served at the attestation deadline
+	Blobs TimedMetric `mapstructure:"blobs"`
+	// Aggregation checks the attestation pool and the aggregate attestations served at the aggregation deadline
+	Aggregation TimedMetric `mapstructure:"aggregation"`
+	// BuilderComparison compares the value of locally built payloads with the bids of the MEV-Boost relays
+	BuilderComparison BuilderComparisonMetric `mapstructure:"builder_comparison"`
+	// Failover measures how quickly a fallback beacon node serves duties once the beacon node is unavailable
+	Failover FailoverMetric `mapstructure:"failover"`
+}
+
+// Failover metric, Fallback is the beacon API of the fallback node the validator client switches over to. Real outages
+// of the beacon node are observed, SimulateEvery additionally treats the beacon node as unavailable once every
+// interval, e.g. '1h', zero only observes.
+type FailoverMetric struct {
+	TimedMetric   `mapstructure:",squash"`
+	Fallback      string        `mapstructure:"fallback"`
+	SimulateEvery time.Duration `mapstructure:"simulate_every"`
+}
+
+// Builder comparison metric, Relays are the MEV-Boost relays whose data API reports the bids, e.g.
+// 'https://boost-relay.flashbots.net'
+type BuilderComparisonMetric struct {
+	Metric `mapstructure:",squash"`
+	Relays []string `mapstructure:"relays"`
+}
+
+// Attestation metric, BackfillEpochs are the epochs before the run evaluated at startup from the chain history
+type AttestationMetric struct {
+	Metric         `mapstructure:",squash"`
+	BackfillEpochs int `mapstructure:"backfill_epochs"`
+}
+
+// Inbound connectivity metric, ProbeURL is an external port check service requested with '{host}' and '{port}'
+// replaced by the address announced in the ENR or enode of the node, a 2xx response status means the port is reachable
+type InboundMetric struct {
+	TimedMetric `mapstructure:",squash"`
+	ProbeURL    string `mapstructure:"probe_url"`
 }
 
 // Execution layer metrics
 type ExecutionMetrics struct {
-	Peers   Metric `mapstructure:"peers"`
-	Latency Metric `mapstructure:"latency"`
+	Peers      TimedMetric   `mapstructure:"peers"`
+	AdminPeers Metric        `mapstructure:"admin_peers"`
+	Latency    LatencyMetric `mapstructure:"latency"`
+	Engine     EngineMetric  `mapstructure:"engine"`
+	// Inbound checks whether the P2P port of the node is reachable from outside, it needs the admin RPC namespace
+	Inbound InboundMetric `mapstructure:"inbound"`
+	// Runtime scrapes the Go runtime stats of Go clients (e.g. Geth) from their Prometheus endpoint
+	Runtime RuntimeMetric `mapstructure:"runtime"`
+	// StateAccess times eth_getProof and eth_call reads of recently used accounts against the disk reads of the host
+	StateAccess TimedMetric `mapstructure:"state_access"`
+}
+
+// Latency metric, Paths are alternative routes to the same endpoint whose overhead is compared to the node address
+type LatencyMetric struct {
+	TimedMetric `mapstructure:",squash"`
+	Paths       []NetworkPath `mapstructure:"paths"`
+}
+
+// NetworkPath is a route to an endpoint, e.g. its direct IP, a reverse proxy in front of it or its VPN address.
+// Proxy optionally routes the requests through an HTTP(S) or SOCKS5 proxy.
+type NetworkPath struct {
+	Name    string `mapstructure:"name"`
+	Address string `mapstructure:"address"`
+	Proxy   string `mapstructure:"proxy"`
+}
+
+// Engine API metric, Simulate enables newPayload latency measurement by re-submitting the payload of the latest block
+type EngineMetric struct {
+	TimedMetric `mapstructure:",squash"`
+	Simulate    bool `mapstructure:"simulate"`
+}
+
+// Go runtime metric, Address is the Prometheus endpoint of the client. Without address the default endpoint of the
+// detected client is scraped, clients which are not written in Go are skipped.
+type RuntimeMetric struct {
+	TimedMetric `mapstructure:",squash"`
+	Address     string `mapstructure:"address"`
 }
 
 // Validator client metrics
@@ -32,20 +141,68 @@ type ValidatorMetrics struct {
 	Proposals    Metric `mapstructure:"proposals"`
 	Attestations Metric `mapstructure:"attestations"`
 	Duties       Metric `mapstructure:"duties"`
+	// KeySafety checks loaded keys, slashing protection and keys loaded by several validator clients
+	KeySafety TimedMetric `mapstructure:"key_safety"`
 }
 
 // Infrastructure metrics (System Monitoring)
 type InfrastructureMetrics struct {
-	CPU    Metric `mapstructure:"cpu"`
-	Memory Metric `mapstructure:"memory"`
-	Disk   Metric `mapstructure:"disk"`
+	CPU    Metric      `mapstructure:"cpu"`
+	Memory Metric      `mapstructure:"memory"`
+	Disk   Metric      `mapstructure:"disk"`
+	Probe  ProbeMetric `mapstructure:"probe"`
+	// DataDirs tracks the growth of the client data directories and projects when the disk is full
+	DataDirs DataDirMetric `mapstructure:"data_dirs"`
+	// MemoryPressure tracks swapping, memory stalls and OOM kills (Linux only)
+	MemoryPressure Metric `mapstructure:"memory_pressure"`
+	// Load tracks the load averages normalized by the core count (Linux only)
+	Load LoadMetric `mapstructure:"load"`
+	// Hardware checks ARM boards (e.g. Raspberry Pi) for undervoltage, throttling and data stored on SD cards, the
+	// storage of the data directories is checked when they are configured
+	Hardware Metric `mapstructure:"hardware"`
+}
+
+// Load average metric, SchedulerLatency additionally samples the run queue wait from /proc/schedstat
+type LoadMetric struct {
+	Metric           `mapstructure:",squash"`
+	SchedulerLatency bool `mapstructure:"scheduler_latency"`
+}
+
+// Data directory growth metric, Paths are the data directories of the clients, e.g. '/var/lib/lighthouse'
+type DataDirMetric struct {
+	Metric `mapstructure:",squash"`
+	Paths  []string `mapstructure:"paths"`
+}
+
+// Packet loss and jitter probe, Hosts are probed with ICMP echo requests or with TCP connects when given as 'host:port'
+type ProbeMetric struct {
+	TimedMetric `mapstructure:",squash"`
+	Hosts       []string `mapstructure:"hosts"`
 }
 
 type BeaconNode struct {
-	Address string        `mapstructure:"address"`
+	Address string `mapstructure:"address"`
+	// OtherAddresses are the APIs of further beacon nodes whose votes are cross-checked with the ones of Address
+	OtherAddresses []string `mapstructure:"other_addresses"`
+	// CheckpointProviders are beacon APIs trusted to be on the canonical chain, e.g. checkpoint sync endpoints, whose
+	// genesis and finalized checkpoint are compared with the ones of Address at run start
+	CheckpointProviders []string `mapstructure:"checkpoint_providers"`
+	// Headers are sent with every request to the addresses, the names are case-insensitive
+	Headers map[string]string `mapstructure:"headers"`
+	// Proxy the addresses are reached through, overriding the one of the benchmark
+	Proxy string `mapstructure:"proxy"`
+	// GRPC collects the peers and the attestation data through the native gRPC API of clients having one (Prysm)
+	GRPC    GRPC          `mapstructure:"grpc"`
 	Metrics BeaconMetrics `mapstructure:"metrics"`
 }
 
+// GRPC is the native gRPC API of a client, Address ('host:port' or 'https://host:port') defaults to the gRPC port of
+// the detected client on the host of the API address
+type GRPC struct {
+	Enabled bool   `mapstructure:"enabled"`
+	Address string `mapstructure:"address"`
+}
+
 func (b BeaconNode) AddrURL() (*url.URL, error) {
 	parsedURL, err := url.Parse(b.Address)
 	if err != nil {
@@ -55,10 +212,21 @@ func (b BeaconNode) AddrURL() (*url.URL, error) {
 }
 
 type ExecutionNode struct {
-	Address string           `mapstructure:"address"`
+	Address       string `mapstructure:"address"`
+	EngineAddress string `mapstructure:"engine_address"`
+	JWTSecretPath string `mapstructure:"jwt_secret_path"`
+	// Headers are sent with every request to the address, not the engine address, the names are case-insensitive
+	Headers map[string]string `mapstructure:"headers"`
+	// Proxy the address and the engine address are reached through, overriding the one of the benchmark
+	Proxy   string           `mapstructure:"proxy"`
 	Metrics ExecutionMetrics `mapstructure:"metrics"`
 }
 
+// IsIPC reports whether the execution client is reached through its IPC socket instead of HTTP
+func (e ExecutionNode) IsIPC() bool {
+	return ipc.IsSocket(e.Address)
+}
+
 func (e ExecutionNode) AddrURL() (*url.URL, error) {
 	parsedURL, err := url.Parse(e.Address)
 	if err != nil {
@@ -68,8 +236,20 @@ func (e ExecutionNode) AddrURL() (*url.URL, error) {
 }
 
 type ValidatorClient struct {
-	Address string           `mapstructure:"address"`
-	Metrics ValidatorMetrics `mapstructure:"metrics"`
+	// Address of the keymanager API
+	Address string   `mapstructure:"address"`
+	Indices []uint64 `mapstructure:"indices"`
+	// OtherAddresses are the keymanager APIs of other validator clients, e.g. a backup machine, which must not load the same keys
+	OtherAddresses []string `mapstructure:"other_addresses"`
+	// TokenPath is the keymanager API token file, its token is sent to every validator client address
+	TokenPath string `mapstructure:"token_path"`
+	// Headers are sent with every request to the addresses, the names are case-insensitive
+	Headers map[string]string `mapstructure:"headers"`
+	// Proxy the addresses are reached through, overriding the one of the benchmark
+	Proxy string `mapstructure:"proxy"`
+	// SlashingProtectionPath is the slashing protection database file or directory of the validator client
+	SlashingProtectionPath string           `mapstructure:"slashing_protection_path"`
+	Metrics                ValidatorMetrics `mapstructure:"metrics"`
 }
 
 func (v ValidatorClient) AddrURL() (*url.URL, error) {
@@ -82,13 +262,263 @@ func (v ValidatorClient) AddrURL() (*url.URL, error) {
 
 type Infrastructure struct {
 	Metrics InfrastructureMetrics `mapstructure:"metrics"`
+	// PortsAudit lists the listening ports at run start and reports the publicly reachable client APIs
+	PortsAudit Metric `mapstructure:"ports_audit"`
+	// DiskBench measures the random read IOPS and the fsync latency of a disk at run start
+	DiskBench DiskBench `mapstructure:"disk_bench"`
+	// Journal annotates the timeline with the restarts of the client units and the OOM kills and segfaults logged to
+	// the systemd journal
+	Journal Journal `mapstructure:"journal"`
+}
+
+// Journal follows the systemd journal for the lifecycle events of Units, e.g. 'geth' or 'lighthouse-beacon.service'
+type Journal struct {
+	Metric `mapstructure:",squash"`
+	Units  []string `mapstructure:"units"`
+}
+
+// DiskBench measures the disk holding Dir, by default the first data directory, with a test file of SizeMB for
+// Duration per measurement
+type DiskBench struct {
+	Enabled  bool          `mapstructure:"enabled"`
+	Dir      string        `mapstructure:"dir"`
+	SizeMB   int64         `mapstructure:"size_mb"`
+	Duration time.Duration `mapstructure:"duration"`
+}
+
+// DiskBenchDir is the directory the disk benchmark test file is written to, Dir or else the first data directory or else the working
+// directory
+func (i Infrastructure) DiskBenchDir() string {
+	switch {
+	case i.DiskBench.Dir != "":
+		return i.DiskBench.Dir
+	case len(i.Metrics.DataDirs.Paths) != 0:
+		return i.Metrics.DataDirs.Paths[0]
+	default:
+		return "."
+	}
 }
 
 type Server struct {
 	Port uint16 `mapstructure:"port"`
+	// LatencyBuckets are the classic buckets in seconds of the latency histograms served on '/metrics'
+	LatencyBuckets []float64 `mapstructure:"latency_buckets"`
+}
+
+const (
+	ReportModeSeparate = "separate"
+	ReportModeMerged   = "merged"
+)
+
+const (
+	// SinkConsole is the table written to stdout and the run artifacts
+	SinkConsole = "console"
+	// SinkJSON and SinkHTML write 'report.json' and 'report.html' artifacts of the run
+	SinkJSON = "json"
+	SinkHTML = "html"
+	// SinkPDF writes a 'report.pdf' summary artifact for sharing, printed by wkhtmltopdf or a headless Chrome
+	SinkPDF = "pdf"
+	// SinkMarkdown writes Markdown tables to stdout, e.g. for a CI job summary
+	SinkMarkdown = "markdown"
+	// SinkWebhook posts the records as JSON to the URL of the sink
+	SinkWebhook = "webhook"
+	// SinkDiscord posts the run summary to a Discord channel, through the webhook URL or as the bot of the token
+	SinkDiscord = "discord"
+)
+
+type Report struct {
+	// Mode defines whether sessions render their own report ('separate') or share a single one ('merged')
+	Mode string `mapstructure:"mode"`
+	// Locale defines the number formatting of the report, e.g. 'en' or 'de', 'raw' keeps numbers machine readable
+	Locale string `mapstructure:"locale"`
+	// Timezone of the timestamps of the report, 'local', 'utc' or an IANA name, e.g. 'Europe/Berlin'
+	Timezone string `mapstructure:"timezone"`
+	// DurationFormat is either 'scaled' to the most readable unit, 'seconds' or 'milliseconds'
+	DurationFormat string `mapstructure:"duration_format"`
+	// Epochs adds a breakdown of the consensus measurements per epoch to the report
+	Epochs bool `mapstructure:"epochs"`
+	// Histograms adds the distribution of the durations of the latency metrics to the report
+	Histograms bool `mapstructure:"histograms"`
+	// Timeline adds the health of every metric per time bucket of the run to the report
+	Timeline bool `mapstructure:"timeline"`
+	// Template is a Go template file rendered with the records in addition to the sinks, e.g. 'report.md.tmpl'. The
+	// output is an artifact named after the template without its '.tmpl' extension, or stdout without artifacts.
+	Template string `mapstructure:"template"`
+	// Sinks render the report, all of them at the end of the run, the console only when none are configured
+	Sinks []ReportSink `mapstructure:"sinks"`
+	// Severities define custom severity levels or override the weights of the built-in ones (Low 1, Medium 2, High 3)
+	Severities []Severity `mapstructure:"severities"`
+}
+
+// ReportSink is a renderer of the report, URL is the endpoint of the webhook sinks
+type ReportSink struct {
+	Type string `mapstructure:"type"`
+	URL  string `mapstructure:"url"`
+	// TokenPath and Channel post to Discord as a bot instead of through a webhook
+	TokenPath string `mapstructure:"token_path"`
+	Channel   string `mapstructure:"channel"`
+	// ArtifactsURL links the summary to the artifacts of the run, published at '<artifacts_url>/<run-id>'
+	ArtifactsURL string `mapstructure:"artifacts_url"`
+}
+
+// Severity is a severity level and its weight, which orders the levels and adds up to the score of a metric
+type Severity struct {
+	Name   string `mapstructure:"name"`
+	Weight int    `mapstructure:"weight"`
+}
+
+type Artifacts struct {
+	// Dir is the directory in which every run creates its '<run-id>' artifacts directory
+	Dir string `mapstructure:"dir"`
+	// KeepRuns and KeepDays bound the runs kept in the directory, older runs are pruned at the start of every run.
+	// 0 keeps them all.
+	KeepRuns int `mapstructure:"keep_runs"`
+	KeepDays int `mapstructure:"keep_days"`
+	// Compression of the raw data points, either 'gzip' or 'zstd' (requires the zstd command), none by default
+	Compression string `mapstructure:"compression"`
+	// Chunk writes the raw data points in files per time span while measuring, e.g. '1h' for multi-day runs
+	Chunk time.Duration `mapstructure:"chunk"`
+}
+
+// Retention returns the bounds of the runs kept in the artifacts directory
+func (a Artifacts) Retention() run.Retention {
+	return run.Retention{Runs: a.KeepRuns, MaxAge: time.Duration(a.KeepDays) * 24 * time.Hour}
+}
+
+type Pushgateway struct {
+	URL string `mapstructure:"url"`
+	Job string `mapstructure:"job"`
+	// Interval of intermediate pushes, 0 pushes the final values only
+	Interval time.Duration `mapstructure:"interval"`
+}
+
+// SpeedTest measures the available bandwidth at run start and end, against an iperf3 server ('host[:port]')
+// or by downloading an HTTP test file and optionally uploading to an HTTP endpoint
+type SpeedTest struct {
+	Iperf3      string        `mapstructure:"iperf3"`
+	DownloadURL string        `mapstructure:"download_url"`
+	UploadURL   string        `mapstructure:"upload_url"`
+	Duration    time.Duration `mapstructure:"duration"`
+}
+
+func (s SpeedTest) Enabled() bool {
+	return s.Iperf3 != "" || s.DownloadURL != "" || s.UploadURL != ""
+}
+
+// Audit writes every request made to the nodes to the log, strict read-only mode blocks those which could change the
+// nodes, e.g. publishing or admin calls
+type Audit struct {
+	StrictReadOnly bool   `mapstructure:"strict_read_only"`
+	Log            string `mapstructure:"log"`
+}
+
+// Recording captures the responses of the nodes to a directory, or replays a captured run instead of contacting the nodes
+type Recording struct {
+	Record string `mapstructure:"record"`
+	Replay string `mapstructure:"replay"`
+}
+
+// Chaos injects degraded readings into chosen metrics during the run, to verify health conditions, alerts and webhooks
+// trigger as configured
+type Chaos struct {
+	Faults []Fault `mapstructure:"faults"`
+}
+
+const (
+	// PreflightDisable runs without the metrics failing the preflight
+	PreflightDisable = "disable"
+	// PreflightAbort fails the run when a metric fails the preflight
+	PreflightAbort = "abort"
+)
+
+// Preflight probes what every enabled metric depends on once before the run starts, e.g. the beacon API endpoints, so
+// metrics which can't measure are reported up front. OnFailure is either 'disable' (default) or 'abort'.
+type Preflight struct {
+	Enabled   bool   `mapstructure:"enabled"`
+	OnFailure string `mapstructure:"on_failure"`
+}
+
+// Watch compares the measurements of the run with those of a stored baseline run, e.g. right after a client upgrade,
+// and alerts on readings deviating more than Sigma standard deviations from the baseline mean
+type Watch struct {
+	// Baseline is the run ID in the artifacts directory, or the directory, of the baseline run
+	Baseline string  `mapstructure:"baseline"`
+	Sigma    float64 `mapstructure:"sigma"`
+}
+
+// Fault replaces the readings of a measurement of a metric, e.g. 'Consensus', 'Peers', 'PeerCount' and '0', from the time
+// after the run started for the duration, or until the run ends without duration
+type Fault struct {
+	Group       string        `mapstructure:"group"`
+	Metric      string        `mapstructure:"metric"`
+	Measurement string        `mapstructure:"measurement"`
+	Value       string        `mapstructure:"value"`
+	After       time.Duration `mapstructure:"after"`
+	Duration    time.Duration `mapstructure:"duration"`
+}
+
+// Trend is a health condition on the change of a measurement of a metric within the window, e.g. 'Consensus', 'Peers',
+// 'PeerCount' changing by '<=' -50 percent within 5m. The change is in the unit of the measurement unless in percent.
+type Trend struct {
+	Group       string        `mapstructure:"group"`
+	Metric      string        `mapstructure:"metric"`
+	Measurement string        `mapstructure:"measurement"`
+	Operator    string        `mapstructure:"operator"`
+	Change      float64       `mapstructure:"change"`
+	Percent     bool          `mapstructure:"percent"`
+	Window      time.Duration `mapstructure:"window"`
+	Severity    string        `mapstructure:"severity"`
+}
+
+// Rule is a health rule spanning several metrics, it applies its severity and hint when all of its conditions are met,
+// e.g. the peers of both clients being low points at the network rather than at one of the clients
+type Rule struct {
+	Name       string          `mapstructure:"name"`
+	Conditions []RuleCondition `mapstructure:"conditions"`
+	Severity   string          `mapstructure:"severity"`
+	Hint       string          `mapstructure:"hint"`
+}
+
+// RuleCondition compares a statistic of the readings of a measurement of a metric, 'mean' (default), 'min', 'max' or
+// 'last', with the threshold, a number or a duration, e.g. 'Consensus', 'Peers', 'PeerCount', '<=' and '10'
+type RuleCondition struct {
+	Group       string `mapstructure:"group"`
+	Metric      string `mapstructure:"metric"`
+	Measurement string `mapstructure:"measurement"`
+	Statistic   string `mapstructure:"statistic"`
+	Operator    string `mapstructure:"operator"`
+	Threshold   string `mapstructure:"threshold"`
+}
+
+// Healthcheck is a dead man's switch such as healthchecks.io pinged while the run lasts and once it completes, it
+// alerts when the pings stop
+type Healthcheck struct {
+	URL string `mapstructure:"url"`
+	// Interval of the pings while the run lasts, 0 pings at the start and the end of the run only
+	Interval time.Duration `mapstructure:"interval"`
+}
+
+// Alerting opens incidents in PagerDuty and Opsgenie when a High severity condition of a metric lasts, and resolves them
+// once the metric recovers. The keys are read from files, so they are not stored with the run metadata.
+type Alerting struct {
+	PagerDutyKeyPath string `mapstructure:"pagerduty_routing_key_path"`
+	OpsgenieKeyPath  string `mapstructure:"opsgenie_api_key_path"`
+	// OpsgenieURL is the API of the Opsgenie instance, e.g. https://api.eu.opsgenie.com
+	OpsgenieURL string `mapstructure:"opsgenie_url"`
+	// Sustain is how long the condition lasts before an incident is opened
+	Sustain time.Duration `mapstructure:"sustain"`
+}
+
+type Export struct {
+	Pushgateway Pushgateway `mapstructure:"pushgateway"`
+	Healthcheck Healthcheck `mapstructure:"healthcheck"`
+	Alerting    Alerting    `mapstructure:"alerting"`
 }
 
 type Benchmark struct {
+	Name string `mapstructure:"name"`
+	// Preset bundles the duration and metrics of a benchmark, 'quick', 'standard' or 'deep', below the other settings
+	Preset          string          `mapstructure:"preset"`
 	BeaconNode      BeaconNode      `mapstructure:"beacon_node"`
 	ExecutionNode   ExecutionNode   `mapstructure:"execution_node"`
 	ValidatorClient ValidatorClient `mapstructure:"validator_client"`
@@ -96,15 +526,225 @@ type Benchmark struct {
 	Server          Server          `mapstructure:"server"`
 	Duration        time.Duration   `mapstructure:"duration"`
 	Network         string          `mapstructure:"network"`
+	Report          Report          `mapstructure:"report"`
+	Artifacts       Artifacts       `mapstructure:"artifacts"`
+	Export          Export          `mapstructure:"export"`
+	SpeedTest       SpeedTest       `mapstructure:"speed_test"`
+	Recording       Recording       `mapstructure:"recording"`
+	Audit           Audit           `mapstructure:"audit"`
+	Chaos           Chaos           `mapstructure:"chaos"`
+	Trends          []Trend         `mapstructure:"trends"`
+	Rules           []Rule          `mapstructure:"rules"`
+	Watch           Watch           `mapstructure:"watch"`
+	Preflight       Preflight       `mapstructure:"preflight"`
+	// Proxy the nodes are reached through, e.g. 'socks5://127.0.0.1:9050' for Tor, unless they set their own
+	Proxy string `mapstructure:"proxy"`
+	// UserAgent replaces the User-Agent of the requests to the nodes unless empty
+	UserAgent string `mapstructure:"user_agent"`
+	// UpdateCheck compares the running version with the latest release at startup and notes newer ones in the report
+	UpdateCheck bool `mapstructure:"update_check"`
+	// Redact replaces the addresses and identifiers in the outputs of the run with hashed tokens, so they can be shared
+	Redact bool `mapstructure:"redact"`
+	// Observer tracks the resource usage of the benchmark tool itself
+	Observer Metric      `mapstructure:"observer"`
+	Sessions []Benchmark `mapstructure:"sessions"`
+}
+
+// SessionConfigs returns the configuration of every benchmark session to run.
+// When no sessions are declared the benchmark itself is the only session.
+func (b *Benchmark) SessionConfigs() []Benchmark {
+	if len(b.Sessions) == 0 {
+		return []Benchmark{*b}
+	}
+	return b.Sessions
 }
 
 func (b *Benchmark) Validate() (bool, error) {
+	if b.Server.Port == 0 {
+		return false, errors.New("server port should be between 1 and 65535")
+	}
+	if err := exporter.ValidateBuckets(b.Server.LatencyBuckets); err != nil {
+		return false, err
+	}
+	for name, duration := range map[string]time.Duration{
+		"duration":                           b.Duration,
+		"export.pushgateway.interval":        b.Export.Pushgateway.Interval,
+		"export.healthcheck.interval":        b.Export.Healthcheck.Interval,
+		"export.alerting.sustain":            b.Export.Alerting.Sustain,
+		"speed_test.duration":                b.SpeedTest.Duration,
+		"artifacts.chunk":                    b.Artifacts.Chunk,
+		"infrastructure.disk_bench.duration": b.Infrastructure.DiskBench.Duration,
+	} {
+		if duration < 0 {
+			return false, fmt.Errorf("%s should not be negative, got '%s'", name, duration)
+		}
+	}
+
+	if b.Artifacts.KeepRuns < 0 || b.Artifacts.KeepDays < 0 {
+		return false, errors.New("artifacts retention should not be negative")
+	}
+	switch b.Artifacts.Compression {
+	case run.CompressionNone, run.CompressionGzip:
+	case run.CompressionZstd:
+		if _, err := exec.LookPath(run.CompressionZstd); err != nil {
+			return false, errors.Join(err, errors.New("artifacts compression 'zstd' requires the zstd command"))
+		}
+	default:
+		return false, fmt.Errorf("artifacts compression should be either '%s' or '%s'", run.CompressionGzip, run.CompressionZstd)
+	}
+
+	switch b.Report.Mode {
+	case "", ReportModeSeparate, ReportModeMerged:
+	default:
+		return false, fmt.Errorf("report mode should be either '%s' or '%s'", ReportModeSeparate, ReportModeMerged)
+	}
+
+	if b.Report.Template != "" {
+		if _, err := os.Stat(b.Report.Template); err != nil {
+			return false, errors.Join(err, fmt.Errorf("report template '%s' is not readable", b.Report.Template))
+		}
+	}
+
+	for i, sink := range b.Report.Sinks {
+		switch sink.Type {
+		case SinkConsole, SinkMarkdown:
+		case SinkJSON, SinkHTML, SinkPDF:
+			if b.Artifacts.Dir == "" {
+				return false, fmt.Errorf("report sink '%s' writes to the artifacts directory, which is not configured", sink.Type)
+			}
+		case SinkWebhook:
+			url, err := sanitizeURL(sink.URL)
+			if err != nil {
+				return false, errors.Join(err, errors.New("report webhook address was not a valid URL"))
+			}
+			b.Report.Sinks[i].URL = url
+		case SinkDiscord:
+			if sink.URL == "" && (sink.TokenPath == "" || sink.Channel == "") {
+				return false, errors.New("report sink 'discord' requires either a webhook URL or a bot token path and a channel")
+			}
+			if sink.URL != "" {
+				url, err := sanitizeURL(sink.URL)
+				if err != nil {
+					return false, errors.Join(err, errors.New("report sink 'discord' webhook address was not a valid URL"))
+				}
+				b.Report.Sinks[i].URL = url
+			}
+		default:
+			return false, fmt.Errorf("report sink '%s' should be one of '%s', '%s', '%s', '%s', '%s', '%s' or '%s'",
+				sink.Type, SinkConsole, SinkJSON, SinkHTML, SinkPDF, SinkMarkdown, SinkWebhook, SinkDiscord)
+		}
+	}
+
+	if b.Recording.Record != "" && b.Recording.Replay != "" {
+		return false, errors.New("a run can either record or replay, not both")
+	}
+	if b.Recording.Replay != "" && b.SpeedTest.Enabled() {
+		return false, errors.New("the speed test measures the network of the host live, it can not be replayed")
+	}
+
+	for _, fault := range b.Chaos.Faults {
+		if fault.Group == "" || fault.Metric == "" || fault.Measurement == "" || fault.Value == "" {
+			return false, errors.New("chaos faults should have a group, metric, measurement and value")
+		}
+		if fault.After < 0 || fault.Duration < 0 {
+			return false, fmt.Errorf("chaos fault of '%s' should not start or last negative durations", fault.Measurement)
+		}
+	}
+
+	switch b.Preflight.OnFailure {
+	case "", PreflightDisable, PreflightAbort:
+	default:
+		return false, fmt.Errorf("preflight failure action should be either '%s' or '%s'", PreflightDisable, PreflightAbort)
+	}
+
+	if b.Watch.Baseline != "" && b.Watch.Sigma <= 0 {
+		return false, fmt.Errorf("watch sigma should be positive, got '%g'", b.Watch.Sigma)
+	}
+
+	for _, trend := range b.Trends {
+		if trend.Group == "" || trend.Metric == "" || trend.Measurement == "" || trend.Severity == "" {
+			return false, errors.New("trends should have a group, metric, measurement and severity")
+		}
+		if trend.Window <= 0 {
+			return false, fmt.Errorf("trend of '%s' should have a positive window, got '%s'", trend.Measurement, trend.Window)
+		}
+	}
+
+	for _, rule := range b.Rules {
+		if rule.Name == "" || rule.Severity == "" {
+			return false, errors.New("rules should have a name and a severity")
+		}
+		for _, condition := range rule.Conditions {
+			if condition.Group == "" || condition.Metric == "" || condition.Measurement == "" || condition.Operator == "" || condition.Threshold == "" {
+				return false, fmt.Errorf("conditions of rule '%s' should have a group, metric, measurement, operator and threshold", rule.Name)
+			}
+		}
+	}
+
+	for _, severity := range b.Report.Severities {
+		if severity.Name == "" || severity.Weight < 0 {
+			return false, fmt.Errorf("severity '%s' should have a name and a non-negative weight", severity.Name)
+		}
+	}
+
+	if b.Export.Pushgateway.URL != "" {
+		url, err := sanitizeURL(b.Export.Pushgateway.URL)
+		if err != nil {
+			return false, errors.Join(err, errors.New("pushgateway address was not a valid URL"))
+		}
+		b.Export.Pushgateway.URL = url
+	}
+
+	if b.Export.Healthcheck.URL != "" {
+		if _, err := url.ParseRequestURI(b.Export.Healthcheck.URL); err != nil {
+			return false, errors.Join(err, errors.New("health check address was not a valid URL"))
+		}
+	}
+
+	if b.Export.Alerting.OpsgenieURL != "" {
+		if _, err := url.ParseRequestURI(b.Export.Alerting.OpsgenieURL); err != nil {
+			return false, errors.Join(err, errors.New("alerting Opsgenie address was not a valid URL"))
+		}
+	}
+
+	for _, address := range []string{b.SpeedTest.DownloadURL, b.SpeedTest.UploadURL} {
+		if address == "" {
+			continue
+		}
+		if _, err := url.ParseRequestURI(address); err != nil {
+			return false, errors.Join(err, fmt.Errorf("speed test address '%s' was not a valid URL", address))
+		}
+	}
+
+	if len(b.Sessions) != 0 {
+		return b.validateSessions()
+	}
+
+	if blocked := b.blockedByStrictReadOnly(); len(blocked) != 0 {
+		return false, fmt.Errorf("strict read-only mode blocks the requests of the metrics '%s', disable them", strings.Join(blocked, "', '"))
+	}
+	if live := b.notReplayable(); len(live) != 0 {
+		return false, fmt.Errorf("the metrics '%s' reach the nodes without requests which can be replayed, disable them", strings.Join(live, "', '"))
+	}
+
 	// Validate beacon node if relevant metrics are enabled
 	if b.BeaconNode.Metrics.Peers.Enabled ||
 		b.BeaconNode.Metrics.Attestation.Enabled ||
 		b.BeaconNode.Metrics.Client.Enabled ||
 		b.BeaconNode.Metrics.Latency.Enabled ||
-		b.BeaconNode.Metrics.SyncStatus.Enabled {
+		b.BeaconNode.Metrics.SyncStatus.Enabled ||
+		b.BeaconNode.Metrics.HeadDelay.Enabled ||
+		b.BeaconNode.Metrics.DutySimulation.Enabled ||
+		b.BeaconNode.Metrics.Balances.Enabled ||
+		b.BeaconNode.Metrics.SyncCommittee.Enabled ||
+		b.BeaconNode.Metrics.ProposalDryRun.Enabled ||
+		b.BeaconNode.Metrics.Inbound.Enabled ||
+		b.BeaconNode.Metrics.Network.Enabled ||
+		b.BeaconNode.Metrics.VoteCrossCheck.Enabled ||
+		b.BeaconNode.Metrics.Aggregation.Enabled ||
+		b.BeaconNode.Metrics.BuilderComparison.Enabled ||
+		b.BeaconNode.Metrics.DutyCalendar.Enabled ||
+		b.BeaconNode.Metrics.Failover.Enabled {
 		url, err := sanitizeURL(b.BeaconNode.Address)
 		if err != nil {
 			return false, errors.Join(err, errors.New("beacon node address was not a valid URL"))
@@ -112,9 +752,63 @@ func (b *Benchmark) Validate() (bool, error) {
 		b.BeaconNode.Address = url
 	}
 
+	if b.BeaconNode.Metrics.Attestation.BackfillEpochs < 0 {
+		return false, errors.New("attestation backfill epochs should not be negative")
+	}
+
+	if builder := b.BeaconNode.Metrics.BuilderComparison; builder.Enabled {
+		if len(builder.Relays) == 0 {
+			return false, errors.New("builder comparison metric requires at least one relay")
+		}
+		for i, relay := range builder.Relays {
+			url, err := sanitizeURL(relay)
+			if err != nil {
+				return false, errors.Join(err, fmt.Errorf("relay address '%s' was not a valid URL", relay))
+			}
+			builder.Relays[i] = url
+		}
+	}
+
+	if b.BeaconNode.Metrics.VoteCrossCheck.Enabled {
+		if len(b.BeaconNode.OtherAddresses) == 0 {
+			return false, errors.New("vote cross-check metric requires other beacon node addresses")
+		}
+		for i, address := range b.BeaconNode.OtherAddresses {
+			url, err := sanitizeURL(address)
+			if err != nil {
+				return false, errors.Join(err, fmt.Errorf("other beacon node address '%s' was not a valid URL", address))
+			}
+			b.BeaconNode.OtherAddresses[i] = url
+		}
+	}
+
+	if failover := &b.BeaconNode.Metrics.Failover; failover.Enabled {
+		if failover.Fallback == "" {
+			return false, errors.New("failover metric requires the address of the fallback beacon node")
+		}
+		url, err := sanitizeURL(failover.Fallback)
+		if err != nil {
+			return false, errors.Join(err, fmt.Errorf("fallback beacon node address '%s' was not a valid URL", failover.Fallback))
+		}
+		failover.Fallback = url
+		if failover.SimulateEvery < 0 {
+			return false, errors.New("failover simulation interval should not be negative")
+		}
+	}
+
+	for i, address := range b.BeaconNode.CheckpointProviders {
+		url, err := sanitizeURL(address)
+		if err != nil {
+			return false, errors.Join(err, fmt.Errorf("checkpoint provider '%s' was not a valid URL", address))
+		}
+		b.BeaconNode.CheckpointProviders[i] = url
+	}
+
 	// Validate execution node if relevant metrics are enabled
-	if b.ExecutionNode.Metrics.Peers.Enabled ||
-		b.ExecutionNode.Metrics.Latency.Enabled {
+	if (b.ExecutionNode.Metrics.Peers.Enabled ||
+		b.ExecutionNode.Metrics.Latency.Enabled ||
+		b.ExecutionNode.Metrics.Inbound.Enabled ||
+		b.ExecutionNode.Metrics.StateAccess.Enabled) && !b.ExecutionNode.IsIPC() {
 		url, err := sanitizeURL(b.ExecutionNode.Address)
 		if err != nil {
 			return false, errors.Join(err, errors.New("execution node address was not a valid URL"))
@@ -122,15 +816,94 @@ func (b *Benchmark) Validate() (bool, error) {
 		b.ExecutionNode.Address = url
 	}
 
+	if err := validatePaths(b.BeaconNode.Metrics.Latency.Paths); err != nil {
+		return false, errors.Join(err, errors.New("beacon node latency paths were not valid"))
+	}
+	if err := validatePaths(b.ExecutionNode.Metrics.Latency.Paths); err != nil {
+		return false, errors.Join(err, errors.New("execution node latency paths were not valid"))
+	}
+
+	for _, runtime := range []*RuntimeMetric{&b.BeaconNode.Metrics.Runtime, &b.ExecutionNode.Metrics.Runtime} {
+		if !runtime.Enabled || runtime.Address == "" {
+			continue
+		}
+		url, err := sanitizeURL(runtime.Address)
+		if err != nil {
+			return false, errors.Join(err, errors.New("runtime metric Prometheus address was not a valid URL"))
+		}
+		runtime.Address = url
+	}
+
+	if address := b.BeaconNode.GRPC.Address; b.BeaconNode.GRPC.Enabled && address != "" {
+		if _, _, err := net.SplitHostPort(strings.TrimPrefix(address, "https://")); err != nil {
+			return false, errors.Join(err, fmt.Errorf("beacon node gRPC address '%s' was not 'host:port'", address))
+		}
+	}
+
+	if b.Infrastructure.Metrics.DataDirs.Enabled && len(b.Infrastructure.Metrics.DataDirs.Paths) == 0 {
+		return false, errors.New("data directory metric requires at least one data directory path")
+	}
+	if b.Infrastructure.Journal.Enabled {
+		if len(b.Infrastructure.Journal.Units) == 0 {
+			return false, errors.New("journal ingestion requires at least one unit")
+		}
+		if _, err := exec.LookPath(journal.Command); err != nil {
+			return false, errors.Join(err, errors.New("journal ingestion requires journalctl"))
+		}
+	}
+	if diskBench := b.Infrastructure.DiskBench; diskBench.Enabled {
+		if diskBench.SizeMB <= 0 {
+			return false, fmt.Errorf("disk benchmark size should be positive, got '%d'", diskBench.SizeMB)
+		}
+		if info, err := os.Stat(b.Infrastructure.DiskBenchDir()); err != nil || !info.IsDir() {
+			return false, fmt.Errorf("disk benchmark directory '%s' was not a directory", b.Infrastructure.DiskBenchDir())
+		}
+	}
+
+	// Validate Engine API endpoint if the engine metric is enabled
+	if b.ExecutionNode.Metrics.Engine.Enabled {
+		url, err := sanitizeURL(b.ExecutionNode.EngineAddress)
+		if err != nil {
+			return false, errors.Join(err, errors.New("execution node engine address was not a valid URL"))
+		}
+		b.ExecutionNode.EngineAddress = url
+		if b.ExecutionNode.JWTSecretPath == "" {
+			return false, errors.New("execution node JWT secret path is required by the engine metric")
+		}
+	}
+
 	// Validate validator client if relevant metrics are enabled
 	if b.ValidatorClient.Metrics.Proposals.Enabled ||
 		b.ValidatorClient.Metrics.Attestations.Enabled ||
-		b.ValidatorClient.Metrics.Duties.Enabled {
+		b.ValidatorClient.Metrics.Duties.Enabled ||
+		b.ValidatorClient.Metrics.KeySafety.Enabled {
 		url, err := sanitizeURL(b.ValidatorClient.Address)
 		if err != nil {
 			return false, errors.Join(err, errors.New("validator client address was not a valid URL"))
 		}
 		b.ValidatorClient.Address = url
+
+		for i, address := range b.ValidatorClient.OtherAddresses {
+			url, err := sanitizeURL(address)
+			if err != nil {
+				return false, errors.Join(err, fmt.Errorf("other validator client address '%s' was not a valid URL", address))
+			}
+			b.ValidatorClient.OtherAddresses[i] = url
+		}
+	}
+
+	if b.BeaconNode.Metrics.Balances.Enabled && len(b.ValidatorClient.Indices) == 0 {
+		return false, errors.New("balances metric requires validator indices")
+	}
+	if b.BeaconNode.Metrics.SyncCommittee.Enabled && len(b.ValidatorClient.Indices) == 0 {
+		return false, errors.New("sync committee metric requires validator indices")
+	}
+	if b.BeaconNode.Metrics.DutyCalendar.Enabled && len(b.ValidatorClient.Indices) == 0 {
+		return false, errors.New("duty calendar metric requires validator indices")
+	}
+
+	if b.Infrastructure.Metrics.Probe.Enabled && len(b.Infrastructure.Metrics.Probe.Hosts) == 0 {
+		return false, errors.New("probe metric requires at least one host")
 	}
 
 	// Validate network name
@@ -141,3 +914,123 @@ func (b *Benchmark) Validate() (bool, error) {
 
 	return true, nil
 }
+
+func validatePaths(paths []NetworkPath) error {
+	names := make(map[string]struct{}, len(paths))
+	for i := range paths {
+		path := &paths[i]
+		if path.Name == "" {
+			return fmt.Errorf("network path '%s' has no name", path.Address)
+		}
+		if _, ok := names[path.Name]; ok {
+			return fmt.Errorf("network path name '%s' is used more than once", path.Name)
+		}
+		names[path.Name] = struct{}{}
+
+		address, err := sanitizeURL(path.Address)
+		if err != nil {
+			return errors.Join(err, fmt.Errorf("network path '%s' address was not a valid URL", path.Name))
+		}
+		path.Address = address
+
+		if path.Proxy != "" {
+			if _, err := url.Parse(path.Proxy); err != nil {
+				return errors.Join(err, fmt.Errorf("network path '%s' proxy was not a valid URL", path.Name))
+			}
+		}
+	}
+	return nil
+}
+
+// blockedByStrictReadOnly returns the enabled metrics which rely on requests strict read-only mode blocks: publishing to
+// the beacon node and the admin and engine namespaces of the execution node. They would only fail every measurement.
+func (b *Benchmark) blockedByStrictReadOnly() []string {
+	if !b.Audit.StrictReadOnly {
+		return nil
+	}
+	var blocked []string
+	for _, metric := range []struct {
+		name    string
+		enabled bool
+	}{
+		{"beacon_node.metrics.duty_simulation", b.BeaconNode.Metrics.DutySimulation.Enabled},
+		{"execution_node.metrics.admin_peers", b.ExecutionNode.Metrics.AdminPeers.Enabled},
+		{"execution_node.metrics.engine", b.ExecutionNode.Metrics.Engine.Enabled},
+		{"execution_node.metrics.inbound", b.ExecutionNode.Metrics.Inbound.Enabled},
+	} {
+		if metric.enabled {
+			blocked = append(blocked, metric.name)
+		}
+	}
+	return blocked
+}
+
+// notReplayable returns the enabled metrics which dial the nodes or hosts themselves instead of sending requests, so a
+// replay has no recorded responses to answer them with: the latency of an IPC socket and the TCP probe of the hosts.
+func (b *Benchmark) notReplayable() []string {
+	if b.Recording.Replay == "" {
+		return nil
+	}
+	var live []string
+	for _, metric := range []struct {
+		name    string
+		enabled bool
+	}{
+		{"execution_node.metrics.latency", b.ExecutionNode.Metrics.Latency.Enabled && b.ExecutionNode.IsIPC()},
+		{"infrastructure.metrics.probe", b.Infrastructure.Metrics.Probe.Enabled},
+	} {
+		if metric.enabled {
+			live = append(live, metric.name)
+		}
+	}
+	return live
+}
+
+func (b *Benchmark) validateSessions() (bool, error) {
+	names := make(map[string]struct{}, len(b.Sessions))
+
+	for i := range b.Sessions {
+		session := &b.Sessions[i]
+		if len(session.Sessions) != 0 {
+			return false, fmt.Errorf("session '%s' can not declare nested sessions", session.Name)
+		}
+
+		// Sessions share the run duration, web host, recording and audit, the network and preflight are inherited
+		// unless overridden
+		if session.Name == "" {
+			session.Name = fmt.Sprintf("session-%d", i+1)
+		}
+		if session.Network == "" {
+			session.Network = b.Network
+		}
+		session.Duration = b.Duration
+		session.Server = b.Server
+		session.Recording = b.Recording
+		session.Audit = b.Audit
+		if session.Preflight == (Preflight{}) {
+			session.Preflight = b.Preflight
+		}
+		if len(session.Chaos.Faults) == 0 {
+			session.Chaos = b.Chaos
+		}
+		if len(session.Trends) == 0 {
+			session.Trends = b.Trends
+		}
+		if len(session.Rules) == 0 {
+			session.Rules = b.Rules
+		}
+		// The benchmark process is shared by all sessions, so only the first one observes it
+		session.Observer = Metric{Enabled: b.Observer.Enabled && i == 0}
+
+		if _, ok := names[session.Name]; ok {
+			return false, fmt.Errorf("session name '%s' is used more than once", session.Name)
+		}
+		names[session.Name] = struct{}{}
+
+		if _, err := session.Validate(); err != nil {
+			return false, errors.Join(err, fmt.Errorf("session '%s' was not valid", session.Name))
+		}
+	}
+
+	return true, nil
+}
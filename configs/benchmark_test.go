@@ -0,0 +1,57 @@
+package configs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGivenSessionsWhenValidateSessionsThenPreflightAndRecordingInherited(t *testing.T) {
+	config := Benchmark{
+		Server:    Server{Port: 8080},
+		Network:   "mainnet",
+		Preflight: Preflight{Enabled: true, OnFailure: PreflightAbort},
+		Recording: Recording{Replay: "recording.jsonl"},
+		Sessions: []Benchmark{
+			{Name: "inherited"},
+			{Name: "overridden", Preflight: Preflight{Enabled: true}, Recording: Recording{Record: "other.jsonl"}},
+		},
+	}
+
+	_, err := config.validateSessions()
+	require.NoError(t, err)
+
+	assert.Equal(t, config.Preflight, config.Sessions[0].Preflight)
+	assert.Equal(t, Preflight{Enabled: true}, config.Sessions[1].Preflight)
+	assert.Equal(t, config.Recording, config.Sessions[0].Recording)
+	assert.Equal(t, config.Recording, config.Sessions[1].Recording, "the recording is shared by the run")
+}
+
+func TestGivenStrictReadOnlyWhenMetricsNeedBlockedRequestsThenListed(t *testing.T) {
+	config := Benchmark{Audit: Audit{StrictReadOnly: true}}
+	config.BeaconNode.Metrics.DutySimulation.Enabled = true
+	config.BeaconNode.Metrics.Peers.Enabled = true
+	config.ExecutionNode.Metrics.AdminPeers.Enabled = true
+
+	assert.Equal(t, []string{"beacon_node.metrics.duty_simulation", "execution_node.metrics.admin_peers"}, config.blockedByStrictReadOnly())
+
+	config.Audit.StrictReadOnly = false
+	assert.Empty(t, config.blockedByStrictReadOnly())
+}
+
+func TestGivenReplayWhenMetricsDialThemselvesThenListed(t *testing.T) {
+	config := Benchmark{Recording: Recording{Replay: "recording"}}
+	config.ExecutionNode.Address = "/data/geth/geth.ipc"
+	config.ExecutionNode.Metrics.Latency.Enabled = true
+	config.Infrastructure.Metrics.Probe.Enabled = true
+	config.BeaconNode.Metrics.Peers.Enabled = true
+
+	assert.Equal(t, []string{"execution_node.metrics.latency", "infrastructure.metrics.probe"}, config.notReplayable())
+
+	config.ExecutionNode.Address = "http://localhost:8545"
+	assert.Equal(t, []string{"infrastructure.metrics.probe"}, config.notReplayable(), "latency over HTTP is replayed")
+
+	config.Recording.Replay = ""
+	assert.Empty(t, config.notReplayable())
+}
@@ -0,0 +1,37 @@
+package configs
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// EnvPrefix prefixes the environment variables of the configuration keys
+const EnvPrefix = "BENCHMARK"
+
+// EnvName returns the environment variable of the key, e.g. BENCHMARK_BEACON_NODE_ADDRESS for benchmark.beacon_node.address.
+// The benchmark section is implied by the prefix.
+func EnvName(key string) string {
+	key = strings.TrimPrefix(key, BenchmarkPrefix+".")
+	return EnvPrefix + "_" + strings.ToUpper(strings.ReplaceAll(key, ".", "_"))
+}
+
+// BindEnv lets every configuration key be supplied through its environment variable, e.g. for Docker and Kubernetes
+// deployments. Lists are comma separated. Flags take precedence over environment variables, which take precedence
+// over the configuration file.
+func BindEnv(v *viper.Viper) error {
+	// Keys of the other sections, e.g. BENCHMARK_LOGGER_LEVEL
+	v.SetEnvPrefix(EnvPrefix)
+	v.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
+	v.AutomaticEnv()
+
+	// Automatic environment variables are only looked up for keys viper knows about, unmarshalling needs all of them
+	for _, field := range Fields(BenchmarkPrefix, Benchmark{}) {
+		if err := v.BindEnv(field.Key, EnvName(field.Key)); err != nil {
+			return errors.Join(err, fmt.Errorf("failed binding environment variable of key '%s'", field.Key))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package configs
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const envTestConfig = `
+benchmark:
+  beacon_node:
+    address: http://file:5052
+  execution_node:
+    address: http://file:8545
+  server:
+    port: 8080
+`
+
+// loadConfig loads the benchmark section the way the application does, with two flags bound and set by the args
+func loadConfig(t *testing.T, args ...string) Benchmark {
+	v := viper.New()
+	v.SetConfigType("yaml")
+	require.NoError(t, BindEnv(v))
+	require.NoError(t, v.ReadConfig(strings.NewReader(envTestConfig)))
+
+	flags := pflag.NewFlagSet("benchmark", pflag.ContinueOnError)
+	flags.Uint16("server-port", 8080, "")
+	flags.String("consensus-addr", "", "")
+	require.NoError(t, flags.Parse(args))
+	require.NoError(t, v.BindPFlag("benchmark.server.port", flags.Lookup("server-port")))
+	require.NoError(t, v.BindPFlag("benchmark.beacon_node.address", flags.Lookup("consensus-addr")))
+
+	var config struct {
+		Benchmark Benchmark `mapstructure:"benchmark"`
+	}
+	require.NoError(t, v.Unmarshal(&config))
+	return config.Benchmark
+}
+
+func TestGivenKeyWhenEnvNameThenPrefixedWithoutSection(t *testing.T) {
+	assert.Equal(t, "BENCHMARK_BEACON_NODE_ADDRESS", EnvName("benchmark.beacon_node.address"))
+	assert.Equal(t, "BENCHMARK_EXPORT_PUSHGATEWAY_INTERVAL", EnvName("benchmark.export.pushgateway.interval"))
+}
+
+func TestGivenOnlyFileWhenLoadThenFileValuesUsed(t *testing.T) {
+	config := loadConfig(t)
+
+	assert.Equal(t, "http://file:5052", config.BeaconNode.Address)
+	assert.Equal(t, uint16(8080), config.Server.Port)
+}
+
+func TestGivenEnvWhenLoadThenEnvOverridesFile(t *testing.T) {
+	t.Setenv("BENCHMARK_BEACON_NODE_ADDRESS", "http://env:5052")
+	t.Setenv("BENCHMARK_SERVER_PORT", "9090")
+
+	config := loadConfig(t)
+
+	assert.Equal(t, "http://env:5052", config.BeaconNode.Address)
+	assert.Equal(t, uint16(9090), config.Server.Port)
+	assert.Equal(t, "http://file:8545", config.ExecutionNode.Address)
+}
+
+func TestGivenFlagAndEnvWhenLoadThenFlagOverridesEnv(t *testing.T) {
+	t.Setenv("BENCHMARK_BEACON_NODE_ADDRESS", "http://env:5052")
+	t.Setenv("BENCHMARK_SERVER_PORT", "9090")
+
+	config := loadConfig(t, "--consensus-addr=http://flag:5052")
+
+	assert.Equal(t, "http://flag:5052", config.BeaconNode.Address)
+	// The default of a flag which wasn't set doesn't override the environment
+	assert.Equal(t, uint16(9090), config.Server.Port)
+}
+
+func TestGivenEnvOfKeyMissingInFileWhenLoadThenValueUsed(t *testing.T) {
+	t.Setenv("BENCHMARK_INFRASTRUCTURE_METRICS_PROBE_HOSTS", "1.1.1.1,8.8.8.8")
+	t.Setenv("BENCHMARK_DURATION", "15m")
+
+	config := loadConfig(t)
+
+	assert.Equal(t, []string{"1.1.1.1", "8.8.8.8"}, config.Infrastructure.Metrics.Probe.Hosts)
+	assert.Equal(t, 15*time.Minute, config.Duration)
+}
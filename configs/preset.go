@@ -0,0 +1,103 @@
+package configs
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+const (
+	// PresetKey selects a preset of the benchmark, either in the configuration or with the '--preset' flag
+	PresetKey = BenchmarkPrefix + ".preset"
+
+	PresetQuick    = "quick"
+	PresetStandard = "standard"
+	PresetDeep     = "deep"
+)
+
+var (
+	// quickPreset is a sanity check of the connectivity of the nodes and the resources of the host
+	quickPreset = map[string]any{
+		"duration":                                       5 * time.Minute,
+		"beacon_node.metrics.client.enabled":             true,
+		"beacon_node.metrics.latency.enabled":            true,
+		"beacon_node.metrics.peers.enabled":              true,
+		"beacon_node.metrics.sync_status.enabled":        true,
+		"beacon_node.metrics.network.enabled":            true,
+		"execution_node.metrics.peers.enabled":           true,
+		"execution_node.metrics.latency.enabled":         true,
+		"infrastructure.metrics.cpu.enabled":             true,
+		"infrastructure.metrics.memory.enabled":          true,
+		"infrastructure.metrics.disk.enabled":            true,
+		"infrastructure.metrics.hardware.enabled":        true,
+		"infrastructure.metrics.load.enabled":            true,
+		"infrastructure.metrics.memory_pressure.enabled": true,
+	}
+
+	// standardPreset adds the duties of a validator to the quick preset, over enough epochs to see their variance
+	standardPreset = with(quickPreset, map[string]any{
+		"duration": time.Hour,
+		"beacon_node.metrics.attestation.enabled": true,
+		"beacon_node.metrics.aggregation.enabled": true,
+		"beacon_node.metrics.head_delay.enabled":  true,
+		"beacon_node.metrics.blobs.enabled":       true,
+		"report.epochs":                           true,
+		"report.timeline":                         true,
+	})
+
+	// deepPreset measures a whole day, so daily patterns like backups and pruning show up, and adds the metrics putting
+	// load on the nodes. The Engine API metric is left out, it needs the engine address and JWT secret.
+	deepPreset = with(standardPreset, map[string]any{
+		"duration": 24 * time.Hour,
+		"beacon_node.metrics.proposal_dry_run.enabled":  true,
+		"execution_node.metrics.state_access.enabled":   true,
+		"infrastructure.metrics.load.scheduler_latency": true,
+		"report.histograms":                             true,
+		"artifacts.chunk":                               time.Hour,
+		"artifacts.compression":                         "gzip",
+	})
+
+	presets = map[string]map[string]any{
+		PresetQuick:    quickPreset,
+		PresetStandard: standardPreset,
+		PresetDeep:     deepPreset,
+	}
+)
+
+// ApplyPreset sets the settings of the preset of the configuration as defaults below the 'benchmark' section, so the
+// configuration file, environment variables and flags override them
+func ApplyPreset(v *viper.Viper) error {
+	name := strings.ToLower(v.GetString(PresetKey))
+	if name == "" {
+		return nil
+	}
+
+	preset, ok := presets[name]
+	if !ok {
+		available := make([]string, 0, len(presets))
+		for preset := range presets {
+			available = append(available, preset)
+		}
+		sort.Strings(available)
+		return fmt.Errorf("preset '%s' should be one of %s", name, strings.Join(available, ", "))
+	}
+	for key, value := range preset {
+		v.SetDefault(BenchmarkPrefix+"."+key, value)
+	}
+	return nil
+}
+
+// with returns the settings of the preset overridden by the settings
+func with(preset, settings map[string]any) map[string]any {
+	merged := make(map[string]any, len(preset)+len(settings))
+	for key, value := range preset {
+		merged[key] = value
+	}
+	for key, value := range settings {
+		merged[key] = value
+	}
+	return merged
+}
@@ -0,0 +1,121 @@
+package configs
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+const (
+	// DefaultFile is the configuration file read from the working directory, or from the configuration directory
+	DefaultFile = "config.yaml"
+	// ProfilesKey is the section of a configuration file holding its named profiles
+	ProfilesKey = "profiles"
+
+	profileExtension = ".yaml"
+)
+
+// Load reads the configuration at the path and merges the named profile over it. The path is either a file whose
+// 'profiles' section holds the profiles, or a directory holding an optional config.yaml and one '<profile>.yaml' file
+// per profile. Without path ./config.yaml is read when it exists, the configuration can come from flags and environment
+// variables only. The preset of the configuration is applied last, below all of them.
+func Load(v *viper.Viper, path, profile string) error {
+	if err := load(v, path, profile); err != nil {
+		return err
+	}
+	return ApplyPreset(v)
+}
+
+func load(v *viper.Viper, path, profile string) error {
+	v.SetConfigType("yaml")
+
+	if path == "" {
+		path = DefaultFile
+		if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) && profile == "" {
+			return nil
+		}
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return errors.Join(err, fmt.Errorf("failed reading configuration '%s'", path))
+	}
+	if info.IsDir() {
+		return loadDir(v, path, profile)
+	}
+
+	v.SetConfigFile(path)
+	if err := v.ReadInConfig(); err != nil {
+		return errors.Join(err, fmt.Errorf("failed reading configuration file '%s'", path))
+	}
+	if profile == "" {
+		return nil
+	}
+
+	// Viper keys are case-insensitive
+	settings := v.Sub(ProfilesKey + "." + strings.ToLower(profile))
+	if settings == nil {
+		var available []string
+		for name := range v.GetStringMap(ProfilesKey) {
+			available = append(available, name)
+		}
+		return profileNotFound(profile, path, available)
+	}
+	if err := v.MergeConfigMap(settings.AllSettings()); err != nil {
+		return errors.Join(err, fmt.Errorf("failed applying profile '%s'", profile))
+	}
+	return nil
+}
+
+func loadDir(v *viper.Viper, dir, profile string) error {
+	base := filepath.Join(dir, DefaultFile)
+	_, err := os.Stat(base)
+	switch {
+	case err == nil:
+		v.SetConfigFile(base)
+		if err := v.ReadInConfig(); err != nil {
+			return errors.Join(err, fmt.Errorf("failed reading configuration file '%s'", base))
+		}
+	case !errors.Is(err, os.ErrNotExist):
+		return errors.Join(err, fmt.Errorf("failed reading configuration file '%s'", base))
+	case profile == "":
+		return fmt.Errorf("configuration directory '%s' has no '%s', a profile is required", dir, DefaultFile)
+	}
+	if profile == "" {
+		return nil
+	}
+
+	file, err := os.Open(filepath.Join(dir, profile+profileExtension))
+	if errors.Is(err, os.ErrNotExist) {
+		var available []string
+		matches, _ := filepath.Glob(filepath.Join(dir, "*"+profileExtension))
+		for _, match := range matches {
+			if name := filepath.Base(match); name != DefaultFile {
+				available = append(available, strings.TrimSuffix(name, profileExtension))
+			}
+		}
+		return profileNotFound(profile, dir, available)
+	}
+	if err != nil {
+		return errors.Join(err, fmt.Errorf("failed reading profile '%s'", profile))
+	}
+	defer file.Close()
+
+	if err := v.MergeConfig(file); err != nil {
+		return errors.Join(err, fmt.Errorf("failed applying profile '%s'", profile))
+	}
+	return nil
+}
+
+func profileNotFound(profile, path string, available []string) error {
+	if len(available) == 0 {
+		return fmt.Errorf("profile '%s' was not found, '%s' has no profiles", profile, path)
+	}
+	sort.Strings(available)
+	return fmt.Errorf("profile '%s' was not found in '%s', available profiles: %s", profile, path, strings.Join(available, ", "))
+}
@@ -0,0 +1,92 @@
+package configs
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const profilesTestConfig = `
+benchmark:
+  beacon_node:
+    address: http://localhost:5052
+  network: mainnet
+profiles:
+  mainnet-nuc:
+    benchmark:
+      beacon_node:
+        address: http://nuc:5052
+`
+
+func writeFile(t *testing.T, path, content string) {
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+}
+
+func TestGivenProfileOfFileWhenLoadThenMergedOverFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nodes.yaml")
+	writeFile(t, path, profilesTestConfig)
+	v := viper.New()
+
+	require.NoError(t, Load(v, path, "mainnet-nuc"))
+
+	assert.Equal(t, "http://nuc:5052", v.GetString("benchmark.beacon_node.address"))
+	assert.Equal(t, "mainnet", v.GetString("benchmark.network"))
+}
+
+func TestGivenUnknownProfileWhenLoadThenFailsListingProfiles(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nodes.yaml")
+	writeFile(t, path, profilesTestConfig)
+
+	err := Load(viper.New(), path, "holesky")
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "available profiles: mainnet-nuc")
+}
+
+func TestGivenProfileOfDirectoryWhenLoadThenMergedOverConfigFile(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, DefaultFile), "benchmark:\n  network: mainnet\n  beacon_node:\n    address: http://localhost:5052\n")
+	writeFile(t, filepath.Join(dir, "holesky.yaml"), "benchmark:\n  network: holesky\n")
+	v := viper.New()
+
+	require.NoError(t, Load(v, dir, "holesky"))
+
+	assert.Equal(t, "holesky", v.GetString("benchmark.network"))
+	assert.Equal(t, "http://localhost:5052", v.GetString("benchmark.beacon_node.address"))
+}
+
+func TestGivenDirectoryWithoutConfigFileWhenLoadWithoutProfileThenFails(t *testing.T) {
+	err := Load(viper.New(), t.TempDir(), "")
+
+	assert.Error(t, err)
+}
+
+func TestGivenPresetWhenLoadThenDefaultsBelowConfigFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	writeFile(t, path, `
+benchmark:
+  preset: quick
+  duration: 10m
+`)
+	v := viper.New()
+
+	require.NoError(t, Load(v, path, ""))
+
+	assert.Equal(t, "10m", v.GetString("benchmark.duration"))
+	assert.True(t, v.GetBool("benchmark.beacon_node.metrics.latency.enabled"))
+	assert.False(t, v.GetBool("benchmark.beacon_node.metrics.attestation.enabled"))
+	assert.NoError(t, CheckKeys(v.AllKeys()))
+}
+
+func TestGivenUnknownPresetWhenLoadThenFailsListingPresets(t *testing.T) {
+	v := viper.New()
+	v.Set(PresetKey, "thorough")
+
+	err := Load(v, filepath.Join(t.TempDir(), "missing.yaml"), "")
+
+	require.Error(t, err)
+}
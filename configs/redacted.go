@@ -0,0 +1,105 @@
+package configs
+
+import (
+	"maps"
+	"net/url"
+	"slices"
+)
+
+// redactedValue replaces the secrets in the redacted configuration
+const redactedValue = "redacted"
+
+// Redacted returns a copy of the benchmark without its secrets, for storing it with the run. The values of the headers
+// are masked, so are the credentials of the addresses and proxies and the paths of the report sinks and the
+// healthcheck, which carry the tokens of their webhooks.
+func (b Benchmark) Redacted() Benchmark {
+	b.BeaconNode.Address = redactUserinfo(b.BeaconNode.Address)
+	b.BeaconNode.OtherAddresses = redactUserinfos(b.BeaconNode.OtherAddresses)
+	b.BeaconNode.CheckpointProviders = redactUserinfos(b.BeaconNode.CheckpointProviders)
+	b.BeaconNode.Headers = redactHeaders(b.BeaconNode.Headers)
+	b.BeaconNode.Proxy = redactUserinfo(b.BeaconNode.Proxy)
+	b.BeaconNode.Metrics.Latency.Paths = redactPaths(b.BeaconNode.Metrics.Latency.Paths)
+
+	b.ExecutionNode.Address = redactUserinfo(b.ExecutionNode.Address)
+	b.ExecutionNode.EngineAddress = redactUserinfo(b.ExecutionNode.EngineAddress)
+	b.ExecutionNode.Headers = redactHeaders(b.ExecutionNode.Headers)
+	b.ExecutionNode.Proxy = redactUserinfo(b.ExecutionNode.Proxy)
+	b.ExecutionNode.Metrics.Latency.Paths = redactPaths(b.ExecutionNode.Metrics.Latency.Paths)
+
+	b.ValidatorClient.Address = redactUserinfo(b.ValidatorClient.Address)
+	b.ValidatorClient.OtherAddresses = redactUserinfos(b.ValidatorClient.OtherAddresses)
+	b.ValidatorClient.Headers = redactHeaders(b.ValidatorClient.Headers)
+	b.ValidatorClient.Proxy = redactUserinfo(b.ValidatorClient.Proxy)
+
+	b.Proxy = redactUserinfo(b.Proxy)
+	b.Export.Pushgateway.URL = redactUserinfo(b.Export.Pushgateway.URL)
+	b.Export.Healthcheck.URL = redactPath(b.Export.Healthcheck.URL)
+
+	b.Report.Sinks = slices.Clone(b.Report.Sinks)
+	for i := range b.Report.Sinks {
+		b.Report.Sinks[i].URL = redactPath(b.Report.Sinks[i].URL)
+	}
+
+	if b.Sessions != nil {
+		sessions := make([]Benchmark, 0, len(b.Sessions))
+		for _, session := range b.Sessions {
+			sessions = append(sessions, session.Redacted())
+		}
+		b.Sessions = sessions
+	}
+	return b
+}
+
+// redactHeaders keeps the names of the headers, their values are e.g. the tokens of an Authorization header
+func redactHeaders(headers map[string]string) map[string]string {
+	if headers == nil {
+		return nil
+	}
+	redacted := maps.Clone(headers)
+	for name := range redacted {
+		redacted[name] = redactedValue
+	}
+	return redacted
+}
+
+func redactUserinfos(addresses []string) []string {
+	if addresses == nil {
+		return nil
+	}
+	redacted := make([]string, 0, len(addresses))
+	for _, address := range addresses {
+		redacted = append(redacted, redactUserinfo(address))
+	}
+	return redacted
+}
+
+func redactPaths(paths []NetworkPath) []NetworkPath {
+	paths = slices.Clone(paths)
+	for i := range paths {
+		paths[i].Address = redactUserinfo(paths[i].Address)
+		paths[i].Proxy = redactUserinfo(paths[i].Proxy)
+	}
+	return paths
+}
+
+// redactUserinfo masks the credentials of the URL, addresses which aren't URLs, e.g. IPC sockets, are kept
+func redactUserinfo(address string) string {
+	parsed, err := url.Parse(address)
+	if err != nil || parsed.User == nil {
+		return address
+	}
+	parsed.User = url.User(redactedValue)
+	return parsed.String()
+}
+
+// redactPath keeps the scheme and the host of the URL only, the path and the query of webhooks hold their tokens
+func redactPath(address string) string {
+	parsed, err := url.Parse(address)
+	if err != nil || parsed.Host == "" {
+		return address
+	}
+	if parsed.Path == "" && parsed.RawQuery == "" && parsed.User == nil {
+		return address
+	}
+	return parsed.Scheme + "://" + parsed.Host + "/" + redactedValue
+}
@@ -0,0 +1,135 @@
+package configs
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// BenchmarkPrefix is the key of the benchmark section in the configuration file
+const BenchmarkPrefix = "benchmark"
+
+// Field is a configurable field under its dotted key, as viper flattens the configuration
+type Field struct {
+	Key  string
+	Type reflect.Type
+}
+
+// Fields returns every configurable field of the type in declaration order.
+// Squashed structs are inlined and lists (e.g. sessions) are leaves.
+func Fields(prefix string, value any) []Field {
+	var fields []Field
+	collectFields(reflect.TypeOf(value), prefix, &fields)
+	return fields
+}
+
+func collectFields(t reflect.Type, prefix string, fields *[]Field) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name, options, _ := strings.Cut(field.Tag.Get("mapstructure"), ",")
+		if options == "squash" {
+			collectFields(field.Type, prefix, fields)
+			continue
+		}
+		if name == "" {
+			name = strings.ToLower(field.Name)
+		}
+
+		key := name
+		if prefix != "" {
+			key = prefix + "." + name
+		}
+		if field.Type.Kind() == reflect.Struct && field.Type.PkgPath() == t.PkgPath() {
+			collectFields(field.Type, key, fields)
+			continue
+		}
+		*fields = append(*fields, Field{Key: key, Type: field.Type})
+	}
+}
+
+// CheckKeys fails on keys of the benchmark section which don't match any configuration field, e.g. typos which would
+// silently be ignored otherwise, and suggests the closest known key. Keys of other sections are not checked.
+func CheckKeys(keys []string) error {
+	known := make(map[string]struct{})
+	// maps take keys of any name, e.g. the names of headers
+	var maps []string
+	for _, field := range Fields(BenchmarkPrefix, Benchmark{}) {
+		known[field.Key] = struct{}{}
+		if field.Type.Kind() == reflect.Map {
+			maps = append(maps, field.Key+".")
+		}
+	}
+
+	var unknown []string
+	for _, key := range keys {
+		if !strings.HasPrefix(key, BenchmarkPrefix+".") {
+			continue
+		}
+		if _, ok := known[key]; ok || hasAnyPrefix(key, maps) {
+			continue
+		}
+
+		message := fmt.Sprintf("'%s'", key)
+		if suggestion := closestKey(key, known); suggestion != "" {
+			message += fmt.Sprintf(" (did you mean '%s'?)", suggestion)
+		}
+		unknown = append(unknown, message)
+	}
+	if len(unknown) == 0 {
+		return nil
+	}
+
+	sort.Strings(unknown)
+	return fmt.Errorf("unknown configuration keys: %s", strings.Join(unknown, ", "))
+}
+
+func hasAnyPrefix(key string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// closestKey returns the known key with the smallest edit distance, if it is close enough to be a typo.
+// The shared prefix of the benchmark section doesn't count towards being close.
+func closestKey(key string, known map[string]struct{}) string {
+	key = strings.TrimPrefix(key, BenchmarkPrefix+".")
+	best, bestDistance := "", len(key)/3+1
+	for candidate := range known {
+		distance := editDistance(key, strings.TrimPrefix(candidate, BenchmarkPrefix+"."))
+		if distance < bestDistance || (distance == bestDistance && candidate < best) {
+			best, bestDistance = candidate, distance
+		}
+	}
+	return best
+}
+
+func editDistance(a, b string) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)]
+}
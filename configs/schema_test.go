@@ -0,0 +1,35 @@
+package configs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGivenKnownKeysWhenCheckKeysThenSucceeds(t *testing.T) {
+	err := CheckKeys([]string{
+		"benchmark.beacon_node.address",
+		"benchmark.beacon_node.metrics.inbound.probe_url",
+		"benchmark.server.port",
+		"benchmark.sessions",
+		"logger.level",
+	})
+
+	assert.NoError(t, err)
+}
+
+func TestGivenMisspelledKeyWhenCheckKeysThenSuggestsClosestKey(t *testing.T) {
+	err := CheckKeys([]string{"benchmark.beacon_node.adress", "benchmark.unrelated"})
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "'benchmark.beacon_node.adress' (did you mean 'benchmark.beacon_node.address'?)")
+	assert.Contains(t, err.Error(), "'benchmark.unrelated'")
+	assert.NotContains(t, err.Error(), "'benchmark.unrelated' (did you mean")
+}
+
+func TestGivenKeysOfMapWhenCheckKeysThenSucceeds(t *testing.T) {
+	err := CheckKeys([]string{"benchmark.execution_node.headers.x-api-key", "benchmark.beacon_node.headers.cf-access-client-id"})
+
+	assert.NoError(t, err)
+}
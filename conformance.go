@@ -0,0 +1,48 @@
+package benchmark
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/spf13/cobra"
+
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/conformance"
+	"github.com/Harikakasimahanthi/benchmark-test/report"
+)
+
+var ConformanceCMD = &cobra.Command{
+	Use:   "conformance",
+	Short: "Check which endpoints of the standard beacon API the metrics depend on the beacon node supports",
+	Long: `Check which endpoints of the standard beacon API the metrics depend on the beacon node supports.
+
+Every endpoint is reported as supported, unsupported when the node doesn't serve it, violation when the response
+doesn't follow the specification, or unreachable. The metrics using an endpoint which isn't supported report nothing.`,
+	// Checking a node must work without a configuration
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		return nil
+	},
+	RunE: func(cobraCMD *cobra.Command, args []string) error {
+		address, err := cobraCMD.Flags().GetString(consensusAddrFlag)
+		if err != nil {
+			return err
+		}
+		if address == "" {
+			return fmt.Errorf("the address of the beacon node is required, set --%s", consensusAddrFlag)
+		}
+
+		results := conformance.Check(cobraCMD.Context(), &http.Client{}, address)
+		report.RenderConformance(cobraCMD.OutOrStdout(), results)
+		for _, result := range results {
+			if result.Status != report.ConformanceSupported {
+				return errors.New("the beacon node doesn't support every endpoint the metrics depend on")
+			}
+		}
+		return nil
+	},
+}
+
+func init() {
+	ConformanceCMD.Flags().String(consensusAddrFlag, "", "Address of the beacon node, e.g. 'http://localhost:5052'")
+	CMD.AddCommand(ConformanceCMD)
+}
@@ -0,0 +1,83 @@
+package benchmark
+
+import (
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/Harikakasimahanthi/benchmark-test/configs"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/network"
+	"github.com/Harikakasimahanthi/benchmark-test/testutil/mocknode"
+)
+
+const (
+	demoLatencyFlag   = "latency"
+	demoJitterFlag    = "jitter"
+	demoErrorRateFlag = "error-rate"
+
+	defaultDemoDuration = time.Minute * 2
+)
+
+var DemoCMD = &cobra.Command{
+	Use:   "demo",
+	Short: "Run the benchmark against a built-in mock node, to try the tool without a real node",
+	RunE: func(cobraCMD *cobra.Command, args []string) error {
+		duration, err := cobraCMD.Flags().GetDuration(durationFlag)
+		if err != nil {
+			return err
+		}
+		latency, err := cobraCMD.Flags().GetDuration(demoLatencyFlag)
+		if err != nil {
+			return err
+		}
+		jitter, err := cobraCMD.Flags().GetDuration(demoJitterFlag)
+		if err != nil {
+			return err
+		}
+		errorRate, err := cobraCMD.Flags().GetFloat64(demoErrorRateFlag)
+		if err != nil {
+			return err
+		}
+		if errorRate < 0 || errorRate > 1 {
+			return errors.New("error rate should be between 0 and 1")
+		}
+
+		node := mocknode.New(network.Mainnet).WithLatency(latency, jitter).WithErrorRate(errorRate).Start()
+		defer node.Close()
+		slog.
+			With("consensus_addr", node.ConsensusURL()).
+			With("execution_addr", node.ExecutionURL()).
+			Info("mock node started")
+
+		configs.Values.Benchmark = demoConfig(configs.Values.Benchmark, node, duration)
+		return CMD.RunE(cobraCMD, args)
+	},
+}
+
+func init() {
+	DemoCMD.Flags().Duration(durationFlag, defaultDemoDuration, "Duration of the demo run, e.g. '5m'")
+	DemoCMD.Flags().Duration(demoLatencyFlag, time.Millisecond*20, "Latency added to every response of the mock node")
+	DemoCMD.Flags().Duration(demoJitterFlag, time.Millisecond*30, "Random latency of up to the jitter added on top of the latency")
+	DemoCMD.Flags().Float64(demoErrorRateFlag, 0.02, "Share of the requests the mock node fails, between 0 and 1")
+	CMD.AddCommand(DemoCMD)
+}
+
+// demoConfig points the benchmark at the mock node. Metrics built on the go-eth2-client need more of the beacon node
+// API than the mock serves, so they are disabled.
+func demoConfig(config configs.Benchmark, node *mocknode.Node, duration time.Duration) configs.Benchmark {
+	config.Duration = duration
+	config.Network = string(network.Mainnet)
+	config.Sessions = nil
+	config.Recording = configs.Recording{}
+
+	config.BeaconNode.Address = node.ConsensusURL()
+	config.BeaconNode.Metrics.Attestation.Enabled = false
+	config.BeaconNode.Metrics.HeadDelay.Enabled = false
+
+	config.ExecutionNode.Address = node.ExecutionURL()
+	config.ExecutionNode.Metrics.Runtime.Address = node.ExecutionURL() + "/debug/metrics/prometheus"
+	config.ExecutionNode.Metrics.Engine.Enabled = false
+	return config
+}
@@ -0,0 +1,135 @@
+package benchmark
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/format"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/metric"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/network"
+	"github.com/Harikakasimahanthi/benchmark-test/metrics/consensus"
+	"github.com/Harikakasimahanthi/benchmark-test/report"
+)
+
+// epochTotals accumulates the consensus measurements of the data points within an epoch
+type epochTotals struct {
+	sums     map[string]float64
+	maxima   map[string]float64
+	minima   map[string]float64
+	measured map[string]bool
+}
+
+// epochRecords breaks the consensus measurements of the session down by epoch, so the report shows whether
+// problems were constant or isolated to some epochs
+func epochRecords(session string, metrics map[metric.Group][]metricService, spec network.Spec) []report.Record {
+	epochs := make(map[uint64]*epochTotals)
+	for _, group := range []metric.Group{metric.ConsensusGroup, metric.ValidatorGroup} {
+		for _, m := range metrics[group] {
+			for _, dp := range m.ExportDataPoints() {
+				if dp.Timestamp.Before(spec.GenesisTime) {
+					continue
+				}
+				epoch := spec.Epoch(spec.SlotAt(dp.Timestamp))
+				totals, ok := epochs[epoch]
+				if !ok {
+					totals = &epochTotals{
+						sums:     make(map[string]float64),
+						maxima:   make(map[string]float64),
+						minima:   make(map[string]float64),
+						measured: make(map[string]bool),
+					}
+					epochs[epoch] = totals
+				}
+				for name, value := range dp.Values {
+					if number, ok := metric.ToFloat(value); ok {
+						totals.add(name, number)
+					}
+				}
+			}
+		}
+	}
+
+	numbers := make([]uint64, 0, len(epochs))
+	for epoch := range epochs {
+		numbers = append(numbers, epoch)
+	}
+	sort.Slice(numbers, func(i, j int) bool { return numbers[i] < numbers[j] })
+
+	records := make([]report.Record, 0, len(numbers))
+	for _, epoch := range numbers {
+		start := spec.SlotTime(spec.EpochStart(epoch))
+		records = append(records, epochs[epoch].record(session, epoch, start))
+	}
+	return records
+}
+
+func (e *epochTotals) add(name string, value float64) {
+	e.sums[name] += value
+	if !e.measured[name] || value > e.maxima[name] {
+		e.maxima[name] = value
+	}
+	if !e.measured[name] || value < e.minima[name] {
+		e.minima[name] = value
+	}
+	e.measured[name] = true
+}
+
+// record summarizes the epoch, it is unhealthy when attestations were missed or voted for the wrong head, or the
+// node fell behind the chain
+func (e *epochTotals) record(session string, epoch uint64, start time.Time) report.Record {
+	lines := []string{fmt.Sprintf("from %s", format.Clock(start))}
+	severity := make(map[string]metric.SeverityLevel)
+
+	if e.measured[consensus.ReceivedBlockMeasurement] || e.measured[consensus.MissedBlockMeasurement] {
+		received, missed := e.sums[consensus.ReceivedBlockMeasurement], e.sums[consensus.MissedBlockMeasurement]
+		line := fmt.Sprintf("blocks=%.0f, missed_blocks=%.0f, missed_attestations=%.0f",
+			received, missed, e.sums[consensus.MissedAttestationMeasurement])
+		if received != 0 {
+			participation := e.sums[consensus.FreshAttestationMeasurement] / received * 100
+			line += fmt.Sprintf(", head_votes=%s %%", format.Number(participation, 2))
+			if participation < 97 {
+				severity[consensus.CorrectnessMeasurement] = metric.SeverityMedium
+			}
+		}
+		if e.sums[consensus.MissedAttestationMeasurement] > 0 {
+			severity[consensus.MissedAttestationMeasurement] = metric.SeverityMedium
+		}
+		lines = append(lines, line)
+	}
+
+	var details []string
+	if e.measured[consensus.DurationP90Measurement] {
+		details = append(details, "latency_p90_max="+format.Duration(time.Duration(e.maxima[consensus.DurationP90Measurement])))
+	}
+	if e.measured[consensus.PeerCountMeasurement] {
+		details = append(details, fmt.Sprintf("peers_min=%.0f", e.minima[consensus.PeerCountMeasurement]))
+	}
+	if e.measured[consensus.SyncDistanceMeasurement] {
+		distance := e.maxima[consensus.SyncDistanceMeasurement]
+		details = append(details, fmt.Sprintf("sync_distance_max=%.0f", distance))
+		if distance >= 2 {
+			severity[consensus.SyncDistanceMeasurement] = metric.SeverityMedium
+		}
+	}
+	if total := e.sums[consensus.OnTimeMeasurement] + e.sums[consensus.LateMeasurement] + e.sums[consensus.FailedPipelineMeasurement]; total != 0 {
+		details = append(details, fmt.Sprintf("on_time_duties=%s %%", format.Number(e.sums[consensus.OnTimeMeasurement]/total*100, 2)))
+	}
+	if len(details) != 0 {
+		lines = append(lines, strings.Join(details, ", "))
+	}
+
+	health := metric.Healthy
+	if len(severity) != 0 {
+		health = metric.Unhealthy
+	}
+	return report.Record{
+		Session:    session,
+		GroupName:  metric.EpochsGroup,
+		MetricName: fmt.Sprint(epoch),
+		Value:      strings.Join(lines, " \n "),
+		Health:     health,
+		Severity:   severity,
+	}
+}
@@ -0,0 +1,329 @@
+package benchmark
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/clock"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/fleet"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/lifecycle"
+	"github.com/Harikakasimahanthi/benchmark-test/report"
+)
+
+const (
+	fleetListenFlag    = "listen"
+	fleetDirFlag       = "dir"
+	fleetTokenPathFlag = "token-path"
+	fleetAgentsFlag    = "agents"
+	fleetLeadFlag      = "lead"
+	fleetServerFlag    = "server"
+	fleetEveryFlag     = "every"
+
+	fleetServerTokenPathFlag = "server-token-path"
+)
+
+var FleetCMD = &cobra.Command{
+	Use:   "fleet",
+	Short: "Run the benchmark on several machines at the same time and compare them",
+}
+
+var FleetAgentCMD = &cobra.Command{
+	Use:   "agent",
+	Short: "Run the benchmark on this machine when a coordinator schedules it, or periodically for an aggregation server",
+	// The runs load the configuration of this machine themselves
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		return nil
+	},
+	RunE: func(cobraCMD *cobra.Command, args []string) error {
+		listen, err := cobraCMD.Flags().GetString(fleetListenFlag)
+		if err != nil {
+			return err
+		}
+		dir, err := cobraCMD.Flags().GetString(fleetDirFlag)
+		if err != nil {
+			return err
+		}
+		token, err := fleetToken(cobraCMD, fleetTokenPathFlag)
+		if err != nil {
+			return err
+		}
+		if err := fleet.CheckListen(listen, token); err != nil {
+			return err
+		}
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return errors.Join(err, errors.New("failed creating the fleet directory"))
+		}
+		hostname, err := os.Hostname()
+		if err != nil {
+			return errors.Join(err, errors.New("failed reading the hostname"))
+		}
+
+		ctx, cancel := context.WithCancel(cobraCMD.Context())
+		defer cancel()
+
+		agent := fleet.NewAgent(ctx, hostname, cobraCMD.Root().Version, dir, token, benchmarkLauncher(cobraCMD))
+		server := &http.Server{Addr: listen, Handler: agent.Handler(), ReadHeaderTimeout: time.Second * 10}
+		go func() {
+			slog.With("address", listen).Info("fleet agent waiting for runs")
+			if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				slog.With("err", err.Error()).Error("fleet agent stopped serving")
+				cancel()
+			}
+		}()
+
+		if err := runPeriodically(ctx, cobraCMD, agent); err != nil {
+			return err
+		}
+
+		lifecycle.ListenForApplicationShutDown(ctx, func() {
+			cancel()
+			_ = server.Close()
+		}, make(chan os.Signal))
+		return nil
+	},
+}
+
+var FleetRunCMD = &cobra.Command{
+	Use:   "run",
+	Short: "Run the benchmark on the agents at the same time and render one report comparing them",
+	// The agents run with their own configuration
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		return nil
+	},
+	RunE: func(cobraCMD *cobra.Command, args []string) error {
+		addresses, err := cobraCMD.Flags().GetStringSlice(fleetAgentsFlag)
+		if err != nil {
+			return err
+		}
+		if len(addresses) == 0 {
+			return errors.New("no agents to run the benchmark on")
+		}
+		duration, err := cobraCMD.Flags().GetDuration(durationFlag)
+		if err != nil {
+			return err
+		}
+		lead, err := cobraCMD.Flags().GetDuration(fleetLeadFlag)
+		if err != nil {
+			return err
+		}
+		if duration <= 0 || lead <= 0 {
+			return errors.New("the duration and the lead time of a fleet run should be positive")
+		}
+		token, err := fleetToken(cobraCMD, fleetTokenPathFlag)
+		if err != nil {
+			return err
+		}
+
+		agents := make([]*fleet.Client, 0, len(addresses))
+		for _, address := range addresses {
+			agents = append(agents, fleet.NewClient(address, token))
+		}
+
+		ctx, cancel := context.WithCancel(cobraCMD.Context())
+		defer cancel()
+		go lifecycle.ListenForApplicationShutDown(ctx, cancel, make(chan os.Signal, 1))
+
+		hosts := fleet.Coordinate(ctx, agents, duration, lead)
+		report.RenderFleet(cobraCMD.OutOrStdout(), hosts)
+
+		var failed []string
+		for _, host := range hosts {
+			if host.Err != "" {
+				failed = append(failed, host.Host)
+			}
+		}
+		if len(failed) != 0 {
+			return fmt.Errorf("the run failed on %s", strings.Join(failed, ", "))
+		}
+		return nil
+	},
+}
+
+var FleetServerCMD = &cobra.Command{
+	Use:   "server",
+	Short: "Collect the runs pushed by the agents and serve them as fleet dashboard and API",
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		return nil
+	},
+	RunE: func(cobraCMD *cobra.Command, args []string) error {
+		listen, err := cobraCMD.Flags().GetString(fleetListenFlag)
+		if err != nil {
+			return err
+		}
+		dir, err := cobraCMD.Flags().GetString(fleetDirFlag)
+		if err != nil {
+			return err
+		}
+		token, err := fleetToken(cobraCMD, fleetTokenPathFlag)
+		if err != nil {
+			return err
+		}
+		if err := fleet.CheckListen(listen, token); err != nil {
+			return err
+		}
+		if dir != "" {
+			if err := os.MkdirAll(dir, 0o755); err != nil {
+				return errors.Join(err, errors.New("failed creating the fleet directory"))
+			}
+		}
+		aggregation, err := fleet.NewServer(dir, token)
+		if err != nil {
+			return errors.Join(err, errors.New("failed loading the kept runs"))
+		}
+
+		ctx, cancel := context.WithCancel(cobraCMD.Context())
+		defer cancel()
+
+		server := &http.Server{Addr: listen, Handler: aggregation.Handler(), ReadHeaderTimeout: time.Second * 10}
+		go func() {
+			slog.With("address", listen).Info("fleet server waiting for runs")
+			if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				slog.With("err", err.Error()).Error("fleet server stopped serving")
+				cancel()
+			}
+		}()
+
+		lifecycle.ListenForApplicationShutDown(ctx, func() {
+			cancel()
+			_ = server.Close()
+		}, make(chan os.Signal))
+		return nil
+	},
+}
+
+func init() {
+	FleetAgentCMD.Flags().String(fleetListenFlag, ":9100", "Address the agent API listens on")
+	FleetAgentCMD.Flags().String(fleetDirFlag, "./fleet", "Directory the records of the runs are written to")
+	FleetAgentCMD.Flags().String(fleetTokenPathFlag, "", "File of the token the coordinator has to present, empty to accept any coordinator when listening on the loopback interface")
+	FleetAgentCMD.Flags().String(fleetServerFlag, "", "Address of the aggregation server the agent pushes periodic runs to, e.g. http://monitor:9200, empty to only run when scheduled")
+	FleetAgentCMD.Flags().Duration(fleetEveryFlag, time.Hour*6, "Interval of the periodic runs")
+	FleetAgentCMD.Flags().Duration(durationFlag, time.Minute*15, "Duration of every periodic run")
+	FleetAgentCMD.Flags().String(fleetServerTokenPathFlag, "", "File of the token presented to the aggregation server")
+
+	FleetRunCMD.Flags().StringSlice(fleetAgentsFlag, nil, "Addresses of the agents, e.g. http://node-1:9100,http://node-2:9100")
+	FleetRunCMD.Flags().Duration(durationFlag, defaultExecutionDuration, "Duration of the run on every agent")
+	FleetRunCMD.Flags().Duration(fleetLeadFlag, time.Second*30, "Time the agents get to set up the run before all of them start measuring")
+	FleetRunCMD.Flags().String(fleetTokenPathFlag, "", "File of the token presented to the agents")
+
+	FleetServerCMD.Flags().String(fleetListenFlag, ":9200", "Address the dashboard and API listen on")
+	FleetServerCMD.Flags().String(fleetDirFlag, "./fleet-server", "Directory the pushed runs are kept in, empty to keep them in memory only")
+	FleetServerCMD.Flags().String(fleetTokenPathFlag, "", "File of the token the agents have to present, empty to accept any agent when listening on the loopback interface")
+
+	FleetCMD.AddCommand(FleetAgentCMD, FleetRunCMD, FleetServerCMD)
+	CMD.AddCommand(FleetCMD)
+}
+
+// runPeriodically starts the periodic runs of the agent when an aggregation server is set
+func runPeriodically(ctx context.Context, cobraCMD *cobra.Command, agent *fleet.Agent) error {
+	address, err := cobraCMD.Flags().GetString(fleetServerFlag)
+	if err != nil || address == "" {
+		return err
+	}
+	every, err := cobraCMD.Flags().GetDuration(fleetEveryFlag)
+	if err != nil {
+		return err
+	}
+	duration, err := cobraCMD.Flags().GetDuration(durationFlag)
+	if err != nil {
+		return err
+	}
+	if duration <= 0 || every < duration {
+		return errors.New("the duration of the periodic runs should be positive and not exceed their interval")
+	}
+	token, err := fleetToken(cobraCMD, fleetServerTokenPathFlag)
+	if err != nil {
+		return err
+	}
+
+	slog.With("server", address).With("every", every).With("duration", duration).Info("running the benchmark periodically")
+	go agent.RunEvery(ctx, every, duration, fleet.NewClient(address, token))
+	return nil
+}
+
+func fleetToken(cobraCMD *cobra.Command, flag string) (string, error) {
+	path, err := cobraCMD.Flags().GetString(flag)
+	if err != nil || path == "" {
+		return "", err
+	}
+	token, err := os.ReadFile(path)
+	if err != nil {
+		return "", errors.Join(err, errors.New("failed reading the fleet token"))
+	}
+	return strings.TrimSpace(string(token)), nil
+}
+
+// benchmarkLauncher runs the benchmark as child process with the configuration and profile the agent was started
+// with, so every run starts from a clean state
+func benchmarkLauncher(cobraCMD *cobra.Command) fleet.Launcher {
+	var inherited []string
+	for _, flag := range []string{"config", "profile"} {
+		if value, err := cobraCMD.Flags().GetString(flag); err == nil && value != "" {
+			inherited = append(inherited, "--"+flag, value)
+		}
+	}
+
+	return func(ctx context.Context, startAt time.Time, duration time.Duration, recordsPath string) error {
+		executable, err := os.Executable()
+		if err != nil {
+			return errors.Join(err, errors.New("failed locating the benchmark binary"))
+		}
+		args := append([]string{
+			CMD.Name(),
+			"--" + durationFlag, duration.String(),
+			"--" + recordsOutFlag, recordsPath,
+		}, inherited...)
+		if !startAt.IsZero() {
+			args = append(args, "--"+startAtFlag, startAt.Format(time.RFC3339))
+		}
+
+		command := exec.CommandContext(ctx, executable, args...)
+		command.Stdout, command.Stderr = os.Stdout, os.Stderr
+		if err := command.Run(); err != nil {
+			return errors.Join(err, errors.New("the benchmark run failed"))
+		}
+		return nil
+	}
+}
+
+// waitForStart waits until the start time of the run when one is set, runs of a fleet are set up before it
+func waitForStart(cobraCMD *cobra.Command) error {
+	value, err := cobraCMD.Flags().GetString(startAtFlag)
+	if err != nil || value == "" {
+		return err
+	}
+	startAt, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return errors.Join(err, fmt.Errorf("start time '%s' should be RFC 3339 formatted", value))
+	}
+
+	wait := clock.Until(startAt)
+	if wait < 0 {
+		slog.With("start_at", startAt).With("late", -wait).Warn("the run was set up after its start time, it starts late")
+		return nil
+	}
+	slog.With("start_at", startAt).Info("waiting for the start time of the run")
+	time.Sleep(wait)
+	return nil
+}
+
+// newRecordsOut creates the file the records are written to, nil when none is set
+func newRecordsOut(cobraCMD *cobra.Command) (*os.File, error) {
+	path, err := cobraCMD.Flags().GetString(recordsOutFlag)
+	if err != nil || path == "" {
+		return nil, err
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, errors.Join(err, errors.New("failed creating the records file"))
+	}
+	return file, nil
+}
@@ -0,0 +1,86 @@
+package benchmark
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/spf13/cobra"
+
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/clientinfo"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/forks"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/network"
+	"github.com/Harikakasimahanthi/benchmark-test/metrics/consensus"
+	"github.com/Harikakasimahanthi/benchmark-test/report"
+)
+
+var ForksCMD = &cobra.Command{
+	Use:   "forks",
+	Short: "Check the clients are ready for the forks of the network",
+	Long: `Check the clients are ready for the forks of the network.
+
+The releases of the consensus and execution clients are compared with the first releases supporting every fork known
+to this release of the benchmark, and the fork schedule of the beacon node must contain the forks. Clients which are
+not ready for an upcoming fork stop following the chain at its epoch.`,
+	// Checking the nodes must work without a configuration
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		return nil
+	},
+	RunE: func(cobraCMD *cobra.Command, args []string) error {
+		consensusAddress, err := cobraCMD.Flags().GetString(consensusAddrFlag)
+		if err != nil {
+			return err
+		}
+		executionAddress, err := cobraCMD.Flags().GetString(executionAddrFlag)
+		if err != nil {
+			return err
+		}
+		name, err := cobraCMD.Flags().GetString(networkFlag)
+		if err != nil {
+			return err
+		}
+		if err := network.Name(name).Validate(); err != nil {
+			return err
+		}
+		if consensusAddress == "" && executionAddress == "" {
+			return fmt.Errorf("the address of a node is required, set --%s or --%s", consensusAddrFlag, executionAddrFlag)
+		}
+
+		ctx := cobraCMD.Context()
+		clients := clientinfo.Detect(ctx, consensusAddress, executionAddress)
+		spec := network.DefaultSpec(network.Name(name))
+		var schedule map[string]uint64
+		if consensusAddress != "" {
+			identity, err := consensus.FetchIdentity(ctx, consensusAddress)
+			if err != nil {
+				return errors.Join(err, errors.New("failed fetching the fork schedule of the beacon node"))
+			}
+			schedule = identity.ScheduledVersions()
+			if fetched, err := consensus.FetchSpec(ctx, consensusAddress, spec); err == nil {
+				spec = fetched
+			} else {
+				slog.With("err", err.Error()).Warn("failed fetching the spec of the beacon node, using the timing of the network")
+			}
+		}
+
+		readiness := forks.Check(network.Name(name), forks.Schedule, clients, schedule, spec.Epoch(spec.CurrentSlot()))
+		if len(readiness) == 0 {
+			fmt.Fprintf(cobraCMD.OutOrStdout(), "No forks of network '%s' are known\n", name)
+			return nil
+		}
+		report.RenderForkReadiness(cobraCMD.OutOrStdout(), readiness)
+		for _, r := range readiness {
+			if r.Upcoming && (r.Status == report.ForkOutdated || r.Status == report.ForkUnscheduled) {
+				return errors.New("the clients are not ready for an upcoming fork")
+			}
+		}
+		return nil
+	},
+}
+
+func init() {
+	ForksCMD.Flags().String(consensusAddrFlag, "", "Address of the beacon node, e.g. 'http://localhost:5052'")
+	ForksCMD.Flags().String(executionAddrFlag, "", "Address of the execution client, e.g. 'http://localhost:8545'")
+	ForksCMD.Flags().String(networkFlag, string(network.Mainnet), "Network of the nodes")
+	CMD.AddCommand(ForksCMD)
+}
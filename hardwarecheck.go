@@ -0,0 +1,68 @@
+package benchmark
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/hardware"
+	"github.com/Harikakasimahanthi/benchmark-test/report"
+)
+
+const (
+	hardwareDirFlag      = "dir"
+	hardwareDiskSizeFlag = "disk-size-mb"
+	hardwareDurationFlag = "test-duration"
+)
+
+var HardwareCheckCMD = &cobra.Command{
+	Use:   "hardware-check",
+	Short: "Grade the machine against the requirements of staking",
+	Long: `Grade the machine against the requirements of staking.
+
+Micro-benchmarks of the CPU (SHA-256 on a single and on all cores), the memory (size and copy bandwidth), the disk
+(4k random reads and synced writes of a test file) and the clock (synchronization and timer overshoot) are graded
+good, minimum or insufficient. Run it on the disk of the client data directories before syncing the clients.`,
+	// Assessing the machine must work without a configuration
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		return nil
+	},
+	RunE: func(cobraCMD *cobra.Command, args []string) error {
+		dir, err := cobraCMD.Flags().GetString(hardwareDirFlag)
+		if err != nil {
+			return err
+		}
+		diskSize, err := cobraCMD.Flags().GetInt64(hardwareDiskSizeFlag)
+		if err != nil {
+			return err
+		}
+		duration, err := cobraCMD.Flags().GetDuration(hardwareDurationFlag)
+		if err != nil {
+			return err
+		}
+		if diskSize <= 0 || duration <= 0 {
+			return fmt.Errorf("--%s and --%s should be positive", hardwareDiskSizeFlag, hardwareDurationFlag)
+		}
+
+		fmt.Fprintf(cobraCMD.OutOrStdout(), "Assessing the machine, this takes about %s\n\n", 6*duration)
+		assessment, err := hardware.Assess(cobraCMD.Context(), dir, diskSize<<20, duration)
+		if err != nil {
+			return errors.Join(err, errors.New("failed assessing the machine"))
+		}
+		checks := hardware.Grade(assessment)
+		report.RenderHardwareCheck(cobraCMD.OutOrStdout(), checks)
+		if report.OverallGrade(checks) == report.GradeInsufficient {
+			return errors.New("the machine doesn't meet the minimum requirements of staking")
+		}
+		return nil
+	},
+}
+
+func init() {
+	HardwareCheckCMD.Flags().String(hardwareDirFlag, ".", "Directory of the disk test file, on the disk of the client data directories")
+	HardwareCheckCMD.Flags().Int64(hardwareDiskSizeFlag, 256, "Size of the disk test file in MB")
+	HardwareCheckCMD.Flags().Duration(hardwareDurationFlag, 5*time.Second, "Duration of every micro-benchmark")
+	CMD.AddCommand(HardwareCheckCMD)
+}
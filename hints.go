@@ -0,0 +1,92 @@
+package benchmark
+
+import (
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/metric"
+	"github.com/Harikakasimahanthi/benchmark-test/metrics/consensus"
+	"github.com/Harikakasimahanthi/benchmark-test/metrics/execution"
+	"github.com/Harikakasimahanthi/benchmark-test/metrics/goruntime"
+	"github.com/Harikakasimahanthi/benchmark-test/metrics/infrastructure"
+	"github.com/Harikakasimahanthi/benchmark-test/metrics/observer"
+	"github.com/Harikakasimahanthi/benchmark-test/metrics/validator"
+)
+
+// hints is the catalog of remediation hints rendered beneath the report rows whose health conditions were met,
+// keyed by the metric group and the measurement of the condition
+var hints = map[metric.Group]map[string]string{
+	metric.ConsensusGroup: {
+		consensus.VersionMeasurement:              "The node did not answer /eth/v1/node/version, check the beacon node is running and its HTTP API is enabled and reachable",
+		consensus.DurationP90Measurement:          "Slow API responses delay duties, check CPU and disk load of the machine and the network path to the beacon node",
+		consensus.PeerCountMeasurement:            "Few consensus peers, check the P2P port (default 9000 TCP/UDP) is forwarded and not blocked by a firewall",
+		consensus.CorrectnessMeasurement:          "Attestations voted for the wrong head, check head delay, peer count and the time synchronization (NTP) of the machine",
+		consensus.SyncDistanceMeasurement:         "The node is behind the chain head, check it is syncing, its peers and the disk performance",
+		consensus.ELOfflineMeasurement:            "The beacon node can't reach the execution client, check the Engine API address and the JWT secret shared by both clients",
+		consensus.OptimisticMeasurement:           "The head was not verified by the execution client yet, check the execution client is synced",
+		consensus.GenesisMismatchMeasurement:      "The node is on another network than configured, check --network and the beacon node address",
+		consensus.ForkMismatchMeasurement:         "The node did not transition to the scheduled fork, update the client and check it is synced",
+		consensus.InboundReachableMeasurement:     "The P2P port is not reachable from outside, forward it on the router (default 9000 TCP/UDP) or enable UPnP",
+		consensus.PrivateENRMeasurement:           "The node announces a private IP, set its public address (e.g. --enr-address) or enable UPnP on the router",
+		consensus.TargetDivergenceMeasurement:     "The beacon nodes vote for different targets, one of them follows a minority fork, check the diverging node's client version and peers",
+		consensus.VoteDivergenceStreakMeasurement: "A beacon node keeps voting for another head than the others, check whether it lags behind, its peers and its execution client",
+		goruntime.GCPauseMeasurement:              "Long GC pauses stall the client, check the machine has free memory and reduce the client cache sizes",
+	},
+	metric.ExecutionGroup: {
+		execution.PeerCountMeasurement:           "Few execution peers, check the P2P port (default 30303 TCP/UDP) is forwarded and not blocked by a firewall",
+		execution.DurationP90Measurement:         "Slow JSON-RPC responses, check CPU and disk load of the machine and the network path to the execution client",
+		execution.CapabilitiesFailedMeasurement:  "The Engine API rejected the request, check the engine address and that the JWT secret matches the one of the execution client",
+		execution.MissingCapabilitiesMeasurement: "The execution client misses Engine API methods required by the consensus client, update the execution client",
+		execution.NewPayloadDurationMeasurement:  "Slow block validation delays attestations and proposals, check the disk performance of the execution client",
+		execution.InboundReachableMeasurement:    "The P2P port is not reachable from outside, forward it on the router (default 30303 TCP/UDP) or enable UPnP",
+		execution.PrivateEnodeMeasurement:        "The node announces a private IP, set its public address (e.g. --nat extip:<IP>) or enable UPnP on the router",
+		goruntime.GCPauseMeasurement:             "Long GC pauses stall the client, check the machine has free memory and reduce the client cache sizes",
+	},
+	metric.ValidatorGroup: {
+		consensus.OnTimeRateMeasurement:                "Attestation duties would be late, check the beacon node latency and the load of the machine",
+		consensus.BalanceDeltaMeasurement:              "The validators lost balance, check the validator client is running and its attestations are included",
+		consensus.SyncCommitteeMissStreakMeasurement:   "Sync committee messages are missing, check the validator client is running and connected to the beacon node",
+		consensus.SyncCommitteeInclusionMeasurement:    "Sync committee messages are not included, check the beacon node peers and the validator client logs",
+		consensus.ProductionFailedMeasurement:          "The node could not produce a block, check the execution client is synced and the builder (MEV-Boost) configuration",
+		consensus.ProductionDurationMeasurement:        "Slow block production risks missed proposals, check the execution client performance and the builder timeouts",
+		consensus.MissedProposalMeasurement:            "A validator missed its proposal, check the validator client logs and the block production of the beacon node",
+		validator.LoadedKeysMeasurement:                "The validator client loaded no keys, check its keystores were imported",
+		validator.DuplicateKeysMeasurement:             "The same keys are loaded by several validator clients, stop all but one immediately to avoid slashing",
+		validator.SlashingProtectionMissingMeasurement: "The slashing protection database was not found, check the path and never run validators without it",
+		validator.SlashingProtectionAgeMeasurement:     "The slashing protection database is not updated, check the validator client is signing duties",
+	},
+	metric.InfrastructureGroup: {
+		infrastructure.FreeMemoryMeasurement:       "The machine ran out of memory, reduce the client cache sizes or add memory",
+		infrastructure.OOMKillsMeasurement:         "The kernel killed processes for lack of memory, check the clients restarted, reduce their cache sizes or add memory",
+		infrastructure.PressureFullMeasurement:     "All tasks stalled waiting for memory, reduce the client cache sizes or add memory",
+		infrastructure.PressureSomeMeasurement:     "Tasks stall waiting for memory, reduce the client cache sizes or add memory",
+		infrastructure.SwapOutMeasurement:          "The machine swaps out memory, which slows the clients down, reduce their cache sizes or add memory",
+		infrastructure.Load5Measurement:            "More tasks are runnable than there are cores, stop other workloads on the machine or move to a CPU with more cores",
+		infrastructure.SchedulerLatencyMeasurement: "Tasks wait for a core before they run, stop other workloads on the machine or move to a CPU with more cores",
+		infrastructure.UndervoltageMeasurement:     "The board is undervolted, use the official power supply and avoid powering the SSD from the board's USB ports",
+		infrastructure.ThrottledMeasurement:        "The CPU is throttled, add a heatsink and fan to the board and check the power supply",
+		infrastructure.SDCardMeasurement:           "The data is stored on an SD card, which is too slow and wears out, move it to an SSD",
+		infrastructure.IOWaitMeasurement:           "The CPU waits for the disk, move the client databases to a faster (NVMe) disk",
+		infrastructure.StealMeasurement:            "The hypervisor gives the CPU time of the VM to other guests, use dedicated cores or a bare-metal machine",
+		infrastructure.PacketLossMeasurement:       "Packets are lost, check the network cables, Wi-Fi usage and the ISP connection",
+		infrastructure.JitterMeasurement:           "The latency fluctuates, check for saturated uplinks, e.g. from other devices or the clients' own bandwidth usage",
+		infrastructure.DiskFullDaysMeasurement:     "The disk of the data directories fills up, prune the execution client database or move the data to a larger disk",
+	},
+	metric.ObserverGroup: {
+		observer.CPUPercentMeasurement: "The benchmark itself uses noticeable CPU, disable expensive metrics or increase their intervals",
+	},
+}
+
+// hintsFor returns the hints of the conditions which were met, once per measurement
+func hintsFor(group metric.Group, conditions []metric.ConditionResult) []string {
+	var result []string
+	seen := make(map[string]struct{}, len(conditions))
+	for _, condition := range conditions {
+		if _, ok := seen[condition.Measurement]; ok {
+			continue
+		}
+		seen[condition.Measurement] = struct{}{}
+
+		if hint, ok := hints[group][condition.Measurement]; ok {
+			result = append(result, hint)
+		}
+	}
+	return result
+}
@@ -0,0 +1,58 @@
+package alert
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+const requestTimeout = time.Second * 10
+
+type (
+	// Alert is an incident about a metric, Key identifies it across triggering and resolving
+	Alert struct {
+		Key     string
+		Summary string
+		// Source is the session or machine the alert is about
+		Source  string
+		Details map[string]string
+	}
+
+	// Notifier opens and resolves incidents in an incident management service
+	Notifier interface {
+		Name() string
+		Trigger(ctx context.Context, alert Alert) error
+		Resolve(ctx context.Context, key string) error
+	}
+)
+
+// postJSON posts the body to the URL, the headers are added to the request
+func postJSON(ctx context.Context, client *http.Client, url string, headers map[string]string, body any) error {
+	encoded, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(encoded))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+
+	res, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return fmt.Errorf("%s responded with status %d", url, res.StatusCode)
+	}
+	return nil
+}
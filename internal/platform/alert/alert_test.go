@@ -0,0 +1,59 @@
+package alert
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type request struct {
+	path          string
+	authorization string
+	body          map[string]any
+}
+
+func recorder(t *testing.T, requests *[]request) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		*requests = append(*requests, request{path: r.URL.RequestURI(), authorization: r.Header.Get("Authorization"), body: body})
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestGivenPagerDutyWhenTriggeredAndResolvedThenEventsShareDedupKey(t *testing.T) {
+	var requests []request
+	pagerDuty := NewPagerDuty("routing")
+	pagerDuty.url = recorder(t, &requests).URL
+
+	require.NoError(t, pagerDuty.Trigger(context.Background(), Alert{Key: "main/Consensus/Latency", Summary: "Latency is unhealthy", Source: "main"}))
+	require.NoError(t, pagerDuty.Resolve(context.Background(), "main/Consensus/Latency"))
+
+	require.Len(t, requests, 2)
+	assert.Equal(t, "trigger", requests[0].body["event_action"])
+	assert.Equal(t, "routing", requests[0].body["routing_key"])
+	assert.Equal(t, "Latency is unhealthy", requests[0].body["payload"].(map[string]any)["summary"])
+	assert.Equal(t, "resolve", requests[1].body["event_action"])
+	assert.Equal(t, requests[0].body["dedup_key"], requests[1].body["dedup_key"])
+}
+
+func TestGivenOpsgenieWhenTriggeredAndResolvedThenAliasIdentifiesAlert(t *testing.T) {
+	var requests []request
+	opsgenie := NewOpsgenie(recorder(t, &requests).URL, "key")
+
+	require.NoError(t, opsgenie.Trigger(context.Background(), Alert{Key: "main/Consensus/Latency", Summary: "Latency is unhealthy"}))
+	require.NoError(t, opsgenie.Resolve(context.Background(), "main/Consensus/Latency"))
+
+	require.Len(t, requests, 2)
+	assert.Equal(t, "/v2/alerts", requests[0].path)
+	assert.Equal(t, "GenieKey key", requests[0].authorization)
+	assert.Equal(t, "main/Consensus/Latency", requests[0].body["alias"])
+	assert.Equal(t, "/v2/alerts/main%2FConsensus%2FLatency/close?identifierType=alias", requests[1].path)
+}
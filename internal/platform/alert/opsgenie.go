@@ -0,0 +1,61 @@
+package alert
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// DefaultOpsgenieURL is the API of the US instance, accounts of the EU instance use https://api.eu.opsgenie.com
+const DefaultOpsgenieURL = "https://api.opsgenie.com"
+
+// Opsgenie opens alerts through the Alert API, the key of an alert is its alias which deduplicates them
+type Opsgenie struct {
+	apiKey string
+	url    string
+	client *http.Client
+}
+
+// NewOpsgenie sends the alerts to the API with the key of an API integration
+func NewOpsgenie(apiURL, apiKey string) *Opsgenie {
+	if apiURL == "" {
+		apiURL = DefaultOpsgenieURL
+	}
+	return &Opsgenie{
+		apiKey: apiKey,
+		url:    strings.TrimSuffix(apiURL, "/"),
+		client: &http.Client{Timeout: requestTimeout},
+	}
+}
+
+func (o *Opsgenie) Name() string {
+	return "Opsgenie"
+}
+
+func (o *Opsgenie) Trigger(ctx context.Context, alert Alert) error {
+	// The message of an Opsgenie alert is limited to 130 characters, the summary is kept whole in the description
+	message := alert.Summary
+	if runes := []rune(message); len(runes) > 130 {
+		message = string(runes[:129]) + "…"
+	}
+	return postJSON(ctx, o.client, o.url+"/v2/alerts", o.headers(), map[string]any{
+		"message":     message,
+		"alias":       alert.Key,
+		"description": alert.Summary,
+		"source":      alert.Source,
+		"priority":    "P1",
+		"details":     alert.Details,
+	})
+}
+
+func (o *Opsgenie) Resolve(ctx context.Context, key string) error {
+	return postJSON(ctx, o.client, fmt.Sprintf("%s/v2/alerts/%s/close?identifierType=alias", o.url, url.PathEscape(key)), o.headers(), map[string]any{
+		"source": "benchmark",
+	})
+}
+
+func (o *Opsgenie) headers() map[string]string {
+	return map[string]string{"Authorization": "GenieKey " + o.apiKey}
+}
@@ -0,0 +1,50 @@
+package alert
+
+import (
+	"context"
+	"net/http"
+)
+
+const pagerDutyEventsURL = "https://events.pagerduty.com/v2/enqueue"
+
+// PagerDuty opens incidents through the Events API v2 of a PagerDuty service, the key of an alert deduplicates them
+type PagerDuty struct {
+	routingKey string
+	url        string
+	client     *http.Client
+}
+
+// NewPagerDuty sends the events to the service of the integration (routing) key
+func NewPagerDuty(routingKey string) *PagerDuty {
+	return &PagerDuty{
+		routingKey: routingKey,
+		url:        pagerDutyEventsURL,
+		client:     &http.Client{Timeout: requestTimeout},
+	}
+}
+
+func (p *PagerDuty) Name() string {
+	return "PagerDuty"
+}
+
+func (p *PagerDuty) Trigger(ctx context.Context, alert Alert) error {
+	return postJSON(ctx, p.client, p.url, nil, map[string]any{
+		"routing_key":  p.routingKey,
+		"event_action": "trigger",
+		"dedup_key":    alert.Key,
+		"payload": map[string]any{
+			"summary":        alert.Summary,
+			"source":         alert.Source,
+			"severity":       "critical",
+			"custom_details": alert.Details,
+		},
+	})
+}
+
+func (p *PagerDuty) Resolve(ctx context.Context, key string) error {
+	return postJSON(ctx, p.client, p.url, nil, map[string]any{
+		"routing_key":  p.routingKey,
+		"event_action": "resolve",
+		"dedup_key":    key,
+	})
+}
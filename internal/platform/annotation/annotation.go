@@ -0,0 +1,96 @@
+package annotation
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// SourceUser marks annotations posted by the user, e.g. through 'benchmark annotate'
+	SourceUser = "user"
+	// Artifact is the run artifact the annotations are written to
+	Artifact = "annotations.json"
+)
+
+// maxTextLength bounds the text of an annotation, it is a note rather than a log
+const maxTextLength = 500
+
+type (
+	// Annotation marks an event during a run, e.g. an operator restarting a client, so metric changes can be
+	// correlated with it
+	Annotation struct {
+		Time   time.Time `json:"time"`
+		Text   string    `json:"text"`
+		Source string    `json:"source"`
+	}
+
+	// Store holds the annotations of a run, it is safe for concurrent use
+	Store struct {
+		annotations []Annotation
+		listeners   []func(Annotation)
+		mutex       sync.Mutex
+	}
+)
+
+func NewStore() *Store {
+	return &Store{}
+}
+
+// OnAdd registers a listener called with every annotation added afterwards, e.g. to export it
+func (s *Store) OnAdd(listener func(Annotation)) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.listeners = append(s.listeners, listener)
+}
+
+// Add validates and stores the annotation, a zero time is set to now and an empty source to SourceUser
+func (s *Store) Add(a Annotation) (Annotation, error) {
+	a.Text = strings.TrimSpace(a.Text)
+	if a.Text == "" {
+		return a, errors.New("annotation text should not be empty")
+	}
+	if len(a.Text) > maxTextLength {
+		return a, errors.New("annotation text should not exceed 500 characters")
+	}
+	if a.Time.IsZero() {
+		a.Time = time.Now()
+	}
+	if a.Source == "" {
+		a.Source = SourceUser
+	}
+
+	s.mutex.Lock()
+	s.annotations = append(s.annotations, a)
+	listeners := s.listeners
+	s.mutex.Unlock()
+
+	for _, listener := range listeners {
+		listener(a)
+	}
+	return a, nil
+}
+
+// List returns the annotations ordered by time
+func (s *Store) List() []Annotation {
+	return s.Between(time.Time{}, time.Time{})
+}
+
+// Between returns the annotations from the start until before the end ordered by time, zero times leave the span open
+func (s *Store) Between(from, to time.Time) []Annotation {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	annotations := []Annotation{}
+	for _, a := range s.annotations {
+		if (!from.IsZero() && a.Time.Before(from)) || (!to.IsZero() && !a.Time.Before(to)) {
+			continue
+		}
+		annotations = append(annotations, a)
+	}
+	sort.SliceStable(annotations, func(i, j int) bool { return annotations[i].Time.Before(annotations[j].Time) })
+	return annotations
+}
@@ -0,0 +1,50 @@
+package annotation
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGivenAnnotationWithoutTimeAndSourceWhenAddThenDefaultsSet(t *testing.T) {
+	store := NewStore()
+	var exported []Annotation
+	store.OnAdd(func(a Annotation) { exported = append(exported, a) })
+
+	added, err := store.Add(Annotation{Text: "  restarted geth "})
+	require.NoError(t, err)
+
+	assert.Equal(t, "restarted geth", added.Text)
+	assert.Equal(t, SourceUser, added.Source)
+	assert.WithinDuration(t, time.Now(), added.Time, time.Second)
+	assert.Equal(t, []Annotation{added}, exported)
+}
+
+func TestGivenEmptyTextWhenAddThenError(t *testing.T) {
+	store := NewStore()
+
+	_, err := store.Add(Annotation{Text: " "})
+
+	assert.Error(t, err)
+	assert.Empty(t, store.List())
+}
+
+func TestGivenAnnotationsWhenBetweenThenOrderedWithinSpan(t *testing.T) {
+	store := NewStore()
+	start := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	for _, offset := range []time.Duration{20 * time.Minute, 0, 10 * time.Minute, 30 * time.Minute} {
+		_, err := store.Add(Annotation{Time: start.Add(offset), Text: offset.String()})
+		require.NoError(t, err)
+	}
+
+	annotations := store.Between(start, start.Add(30*time.Minute))
+
+	var texts []string
+	for _, a := range annotations {
+		texts = append(texts, a.Text)
+	}
+	assert.Equal(t, []string{"0s", "10m0s", "20m0s"}, texts)
+	assert.Len(t, store.List(), 4)
+}
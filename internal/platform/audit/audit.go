@@ -0,0 +1,261 @@
+package audit
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ErrBlocked is returned for the requests strict read-only mode keeps from the nodes
+var ErrBlocked = errors.New("request blocked by strict read-only mode")
+
+var (
+	// readOnlyRPCPrefixes are the JSON-RPC namespaces which only read the state of a node
+	readOnlyRPCPrefixes = []string{"eth_", "net_", "web3_", "txpool_"}
+	// writingRPCPrefixes are the methods of the read-only namespaces which sign, submit or keep state on the node
+	writingRPCPrefixes = []string{"eth_send", "eth_sign", "eth_submit", "eth_newFilter", "eth_newBlockFilter", "eth_newPendingTransactionFilter", "eth_uninstallFilter", "eth_subscribe", "eth_unsubscribe"}
+	// readOnlyPostPaths are the beacon API endpoints and the gRPC methods of the native APIs which take their query as
+	// POST body without changing anything
+	readOnlyPostPaths = []string{
+		"/eth/v1/beacon/rewards/", "/eth/v1/validator/duties/", "/eth/v1/beacon/states/", "/eth/v1/validator/liveness/",
+		"/ethereum.eth.v1alpha1.Node/", "/ethereum.eth.v1alpha1.BeaconNodeValidator/GetAttestationData",
+	}
+)
+
+type (
+	// Entry is a request made to a node as written to the audit log
+	Entry struct {
+		Time time.Time `json:"time"`
+		// Transport is 'http' or 'ipc'
+		Transport string `json:"transport"`
+		Method    string `json:"method,omitempty"`
+		// Target is the URL without credentials and query, or the path of the IPC socket
+		Target     string   `json:"target"`
+		RPCMethods []string `json:"rpc_methods,omitempty"`
+		Status     int      `json:"status,omitempty"`
+		DurationMS int64    `json:"duration_ms"`
+		Blocked    bool     `json:"blocked,omitempty"`
+		Err        string   `json:"error,omitempty"`
+	}
+
+	// Auditor writes every request made to the nodes to the audit log. In strict read-only mode it blocks the
+	// requests which could change anything on the nodes: non-GET requests other than the read-only queries of the
+	// beacon API, and JSON-RPC methods outside the read-only namespaces, e.g. admin_ or engine_ methods.
+	Auditor struct {
+		strict  bool
+		file    *os.File
+		encoder *json.Encoder
+		mutex   sync.Mutex
+	}
+)
+
+var current atomic.Pointer[Auditor]
+
+// New creates an auditor writing to the file of the path, no log is written when it is empty
+func New(path string, strict bool) (*Auditor, error) {
+	a := &Auditor{strict: strict}
+	if path == "" {
+		return a, nil
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, errors.Join(err, fmt.Errorf("failed opening audit log '%s'", path))
+	}
+	a.file, a.encoder = file, json.NewEncoder(file)
+	return a, nil
+}
+
+// Set makes the auditor check the requests sent over IPC, HTTP requests are checked by intercepting them
+func Set(a *Auditor) {
+	current.Store(a)
+}
+
+func (a *Auditor) RoundTrip(req *http.Request, next http.RoundTripper) (*http.Response, error) {
+	req, body, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	entry := Entry{
+		Time:       time.Now(),
+		Transport:  "http",
+		Method:     req.Method,
+		Target:     target(req),
+		RPCMethods: rpcMethods(body),
+	}
+	if err := a.checkHTTP(req.Method, req.URL.Path, entry.RPCMethods); err != nil {
+		entry.Blocked, entry.Err = true, err.Error()
+		a.write(entry)
+		return nil, err
+	}
+
+	res, err := next.RoundTrip(req)
+	entry.DurationMS = time.Since(entry.Time).Milliseconds()
+	if err != nil {
+		entry.Err = err.Error()
+	} else {
+		entry.Status = res.StatusCode
+	}
+	a.write(entry)
+	return res, err
+}
+
+// IPC checks and logs the JSON-RPC request sent over the socket of the path by the call
+func IPC(path string, request any, call func() error) error {
+	a := current.Load()
+	if a == nil {
+		return call()
+	}
+
+	body, err := json.Marshal(request)
+	if err != nil {
+		return err
+	}
+	entry := Entry{
+		Time:       time.Now(),
+		Transport:  "ipc",
+		Target:     path,
+		RPCMethods: rpcMethods(body),
+	}
+	if err := a.checkRPC(entry.RPCMethods); err != nil {
+		entry.Blocked, entry.Err = true, err.Error()
+		a.write(entry)
+		return err
+	}
+
+	err = call()
+	entry.DurationMS = time.Since(entry.Time).Milliseconds()
+	if err != nil {
+		entry.Err = err.Error()
+	}
+	a.write(entry)
+	return err
+}
+
+func (a *Auditor) checkHTTP(method, path string, rpc []string) error {
+	if !a.strict {
+		return nil
+	}
+	switch {
+	case method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions:
+		return nil
+	case method == http.MethodPost && len(rpc) != 0:
+		return a.checkRPC(rpc)
+	case method == http.MethodPost:
+		for _, readOnly := range readOnlyPostPaths {
+			if strings.Contains(path, readOnly) {
+				return nil
+			}
+		}
+	}
+	return errors.Join(ErrBlocked, fmt.Errorf("%s %s may change the node", method, path))
+}
+
+func (a *Auditor) checkRPC(methods []string) error {
+	if !a.strict {
+		return nil
+	}
+	for _, method := range methods {
+		if !readOnlyRPC(method) {
+			return errors.Join(ErrBlocked, fmt.Errorf("JSON-RPC method %s may change the node", method))
+		}
+	}
+	return nil
+}
+
+func readOnlyRPC(method string) bool {
+	for _, prefix := range writingRPCPrefixes {
+		if strings.HasPrefix(method, prefix) {
+			return false
+		}
+	}
+	for _, prefix := range readOnlyRPCPrefixes {
+		if strings.HasPrefix(method, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func (a *Auditor) write(entry Entry) {
+	if a.encoder == nil {
+		return
+	}
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	// A failed write must not fail the request
+	_ = a.encoder.Encode(entry)
+}
+
+func (a *Auditor) Close() error {
+	if a.file == nil {
+		return nil
+	}
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	return a.file.Close()
+}
+
+// rpcMethods returns the methods of a JSON-RPC request or batch, none when the body is no JSON-RPC
+func rpcMethods(body []byte) []string {
+	body = bytes.TrimSpace(body)
+	if len(body) == 0 {
+		return nil
+	}
+	type call struct {
+		Method string `json:"method"`
+	}
+	var calls []call
+	if body[0] == '[' {
+		if err := json.Unmarshal(body, &calls); err != nil {
+			return nil
+		}
+	} else {
+		var single call
+		if err := json.Unmarshal(body, &single); err != nil {
+			return nil
+		}
+		calls = append(calls, single)
+	}
+
+	var methods []string
+	for _, c := range calls {
+		if c.Method != "" {
+			methods = append(methods, c.Method)
+		}
+	}
+	return methods
+}
+
+// target is the URL of the request without credentials and query, they may hold secrets
+func target(req *http.Request) string {
+	u := *req.URL
+	u.User, u.RawQuery, u.Fragment = nil, "", ""
+	return u.String()
+}
+
+func readRequestBody(req *http.Request) (*http.Request, []byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return req, nil, nil
+	}
+
+	body, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	req = req.Clone(req.Context())
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	return req, body, nil
+}
@@ -0,0 +1,90 @@
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGivenStrictModeWhenRequestsThenChangingOnesBlocked(t *testing.T) {
+	var received []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = append(received, r.Method+" "+r.URL.Path)
+	}))
+	defer server.Close()
+	auditor, err := New("", true)
+	require.NoError(t, err)
+
+	tests := []struct {
+		method, path, body string
+		blocked            bool
+	}{
+		{http.MethodGet, "/eth/v1/node/syncing", "", false},
+		{http.MethodPost, "/eth/v1/validator/duties/attester/10", `["1"]`, false},
+		{http.MethodPost, "/", `{"jsonrpc":"2.0","method":"eth_blockNumber","id":1}`, false},
+		{http.MethodPost, "/ethereum.eth.v1alpha1.Node/ListPeers", "", false},
+		{http.MethodPost, "/eth/v1/beacon/pool/attestations", `[{}]`, true},
+		{http.MethodDelete, "/eth/v1/keystores", `{}`, true},
+		{http.MethodPost, "/", `{"jsonrpc":"2.0","method":"admin_peers","id":1}`, true},
+		{http.MethodPost, "/", `[{"method":"eth_chainId"},{"method":"eth_sendRawTransaction"}]`, true},
+	}
+	for _, test := range tests {
+		req, err := http.NewRequest(test.method, server.URL+test.path, strings.NewReader(test.body))
+		require.NoError(t, err)
+
+		res, err := auditor.RoundTrip(req, http.DefaultTransport)
+
+		if test.blocked {
+			assert.ErrorIs(t, err, ErrBlocked, test.method+" "+test.path+" "+test.body)
+			continue
+		}
+		require.NoError(t, err, test.method+" "+test.path+" "+test.body)
+		res.Body.Close()
+	}
+	assert.Equal(t, []string{"GET /eth/v1/node/syncing", "POST /eth/v1/validator/duties/attester/10", "POST /", "POST /ethereum.eth.v1alpha1.Node/ListPeers"}, received)
+}
+
+func TestGivenAuditLogWhenRequestsThenEveryRequestLogged(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	auditor, err := New(path, false)
+	require.NoError(t, err)
+
+	req, err := http.NewRequest(http.MethodPost, server.URL+"/?apikey=secret", strings.NewReader(`{"method":"admin_peers"}`))
+	require.NoError(t, err)
+	res, err := auditor.RoundTrip(req, http.DefaultTransport)
+	require.NoError(t, err, "only strict mode blocks requests")
+	res.Body.Close()
+	Set(auditor)
+	defer Set(nil)
+	require.NoError(t, IPC("/data/geth.ipc", map[string]any{"method": "eth_syncing"}, func() error { return nil }))
+	require.NoError(t, auditor.Close())
+
+	file, err := os.Open(path)
+	require.NoError(t, err)
+	defer file.Close()
+	var entries []Entry
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var entry Entry
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &entry))
+		entries = append(entries, entry)
+	}
+
+	require.Len(t, entries, 2)
+	assert.Equal(t, "http", entries[0].Transport)
+	assert.Equal(t, server.URL+"/", entries[0].Target, "the query may hold secrets")
+	assert.Equal(t, []string{"admin_peers"}, entries[0].RPCMethods)
+	assert.Equal(t, http.StatusOK, entries[0].Status)
+	assert.Equal(t, "ipc", entries[1].Transport)
+	assert.Equal(t, []string{"eth_syncing"}, entries[1].RPCMethods)
+}
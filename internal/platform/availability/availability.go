@@ -0,0 +1,102 @@
+package availability
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+var tracker = struct {
+	targets map[string]*state
+	mutex   sync.Mutex
+}{targets: make(map[string]*state)}
+
+type (
+	// Window is a contiguous period in which a target failed, End is zero while it is ongoing
+	Window struct {
+		Start time.Time `json:"start"`
+		End   time.Time `json:"end"`
+	}
+
+	// Target is an endpoint or metric observed since the passed time along with its outage windows
+	Target struct {
+		Name    string    `json:"name"`
+		Since   time.Time `json:"since"`
+		Windows []Window  `json:"windows"`
+	}
+
+	state struct {
+		since        time.Time
+		failingSince time.Time
+		windows      []Window
+	}
+)
+
+// Uptime returns the share of time in percent the target was available between its first observation and until
+func (t Target) Uptime(until time.Time) float64 {
+	observed := until.Sub(t.Since)
+	if observed <= 0 {
+		return 100
+	}
+
+	var down time.Duration
+	for _, window := range t.Windows {
+		end := window.End
+		if end.IsZero() {
+			end = until
+		}
+		down += end.Sub(window.Start)
+	}
+	return max(0, 100-float64(down)/float64(observed)*100)
+}
+
+// Success records a successful measurement of the target, ending its ongoing outage window
+func Success(name string) {
+	tracker.mutex.Lock()
+	defer tracker.mutex.Unlock()
+
+	s := observe(name)
+	if !s.failingSince.IsZero() {
+		s.windows = append(s.windows, Window{Start: s.failingSince, End: time.Now()})
+		s.failingSince = time.Time{}
+	}
+}
+
+// Failure records a failed measurement of the target, starting an outage window unless one is ongoing
+func Failure(name string) {
+	tracker.mutex.Lock()
+	defer tracker.mutex.Unlock()
+
+	s := observe(name)
+	if s.failingSince.IsZero() {
+		s.failingSince = time.Now()
+	}
+}
+
+func observe(name string) *state {
+	s, ok := tracker.targets[name]
+	if !ok {
+		s = &state{since: time.Now()}
+		tracker.targets[name] = s
+	}
+	return s
+}
+
+// Targets returns every target observed so far, sorted by name
+func Targets() []Target {
+	tracker.mutex.Lock()
+	defer tracker.mutex.Unlock()
+
+	targets := make([]Target, 0, len(tracker.targets))
+	for name, s := range tracker.targets {
+		windows := append([]Window(nil), s.windows...)
+		if !s.failingSince.IsZero() {
+			windows = append(windows, Window{Start: s.failingSince})
+		}
+		targets = append(targets, Target{Name: name, Since: s.since, Windows: windows})
+	}
+	sort.Slice(targets, func(i, j int) bool {
+		return targets[i].Name < targets[j].Name
+	})
+	return targets
+}
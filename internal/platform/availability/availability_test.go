@@ -0,0 +1,40 @@
+package availability
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGivenOutageWindowsWhenUptimeThenDowntimeIsSubtracted(t *testing.T) {
+	since := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	target := Target{
+		Since: since,
+		Windows: []Window{
+			{Start: since.Add(10 * time.Minute), End: since.Add(15 * time.Minute)},
+			// Ongoing outage lasts until the end of the observation
+			{Start: since.Add(95 * time.Minute)},
+		},
+	}
+
+	assert.InDelta(t, 90.0, target.Uptime(since.Add(100*time.Minute)), 0.001)
+}
+
+func TestGivenFailuresWhenSuccessThenWindowIsClosed(t *testing.T) {
+	Failure("test/metric")
+	Failure("test/metric")
+	Success("test/metric")
+	Failure("test/metric")
+
+	for _, target := range Targets() {
+		if target.Name != "test/metric" {
+			continue
+		}
+		assert.Len(t, target.Windows, 2)
+		assert.False(t, target.Windows[0].End.IsZero())
+		assert.True(t, target.Windows[1].End.IsZero())
+		return
+	}
+	t.Fatal("target was not tracked")
+}
@@ -0,0 +1,183 @@
+package breaker
+
+import (
+	"errors"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/availability"
+)
+
+const defaultThreshold = 3
+
+var (
+	// ErrOpen is returned instead of contacting an endpoint which is considered down
+	ErrOpen = errors.New("circuit breaker is open, endpoint is backed off")
+
+	// DefaultBackoffs are the waits after the first, second and every further consecutive outage probe failure
+	DefaultBackoffs = []time.Duration{time.Second * 30, time.Minute, time.Minute * 5}
+
+	registry = struct {
+		breakers map[string]*Breaker
+		mutex    sync.Mutex
+	}{breakers: make(map[string]*Breaker)}
+)
+
+type (
+	// Breaker stops requests to an endpoint after consecutive failures. While open, a single probe request is let
+	// through once the backoff elapsed; its success closes the breaker, its failure reopens it with the next backoff.
+	Breaker struct {
+		endpoint  string
+		threshold int
+		backoffs  []time.Duration
+
+		since       time.Time
+		failures    int
+		opened      int
+		openUntil   time.Time
+		probing     bool
+		outageStart time.Time
+		lastError   string
+		outages     []Outage
+		mutex       sync.Mutex
+	}
+
+	// Outage is a window in which the endpoint failed, End is zero while the outage is ongoing
+	Outage struct {
+		Start     time.Time `json:"start"`
+		End       time.Time `json:"end"`
+		LastError string    `json:"last_error"`
+	}
+)
+
+func New(endpoint string, threshold int, backoffs []time.Duration) *Breaker {
+	return &Breaker{
+		since:     time.Now(),
+		endpoint:  endpoint,
+		threshold: threshold,
+		backoffs:  backoffs,
+	}
+}
+
+// For returns the breaker shared by all collectors of the endpoint
+func For(endpoint string) *Breaker {
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+
+	b, ok := registry.breakers[endpoint]
+	if !ok {
+		b = New(endpoint, defaultThreshold, DefaultBackoffs)
+		registry.breakers[endpoint] = b
+	}
+	return b
+}
+
+// All returns the breakers of every endpoint contacted so far, sorted by endpoint
+func All() []*Breaker {
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+
+	breakers := make([]*Breaker, 0, len(registry.breakers))
+	for _, b := range registry.breakers {
+		breakers = append(breakers, b)
+	}
+	sort.Slice(breakers, func(i, j int) bool {
+		return breakers[i].endpoint < breakers[j].endpoint
+	})
+	return breakers
+}
+
+func (b *Breaker) Endpoint() string {
+	return b.endpoint
+}
+
+// Allow reports whether a request may be sent to the endpoint
+func (b *Breaker) Allow() bool {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if b.openUntil.IsZero() {
+		return true
+	}
+	if b.probing || time.Now().Before(b.openUntil) {
+		return false
+	}
+	b.probing = true
+	return true
+}
+
+// Success records a successful request, closing the breaker and ending an ongoing outage
+func (b *Breaker) Success() {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if b.opened > 0 {
+		b.outages = append(b.outages, Outage{Start: b.outageStart, End: time.Now(), LastError: b.lastError})
+		slog.With("endpoint", b.endpoint).With("down_since", b.outageStart).Info("endpoint recovered")
+	}
+	b.failures, b.opened, b.probing = 0, 0, false
+	b.openUntil, b.outageStart = time.Time{}, time.Time{}
+}
+
+// Release gives up the probe slot of a request which ended without a result, e.g. cancelled by its caller
+func (b *Breaker) Release() {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.probing = false
+}
+
+// Failure records a failed request, opening the breaker once the failure threshold is reached
+func (b *Breaker) Failure(err error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if b.failures == 0 && b.outageStart.IsZero() {
+		b.outageStart = time.Now()
+	}
+	b.failures++
+	b.lastError = err.Error()
+	b.probing = false
+
+	if b.failures < b.threshold {
+		return
+	}
+
+	backoff := b.backoffs[min(b.opened, len(b.backoffs)-1)]
+	b.opened++
+	b.openUntil = time.Now().Add(backoff)
+	slog.
+		With("endpoint", b.endpoint).
+		With("backoff", backoff.String()).
+		With("err", b.lastError).
+		Warn("endpoint is failing, backing off")
+}
+
+// Outages returns the outage windows of the endpoint including an ongoing one
+func (b *Breaker) Outages() []Outage {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	outages := append([]Outage(nil), b.outages...)
+	if b.opened > 0 {
+		outages = append(outages, Outage{Start: b.outageStart, LastError: b.lastError})
+	}
+	return outages
+}
+
+// Target describes the availability of the endpoint since its first request
+func (b *Breaker) Target() availability.Target {
+	outages := b.Outages()
+
+	windows := make([]availability.Window, 0, len(outages))
+	for _, outage := range outages {
+		windows = append(windows, availability.Window{Start: outage.Start, End: outage.End})
+	}
+	return availability.Target{
+		Name:    b.endpoint,
+		Since:   b.since,
+		Windows: windows,
+	}
+}
@@ -0,0 +1,81 @@
+package breaker
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/audit"
+)
+
+func TestGivenConsecutiveFailuresWhenThresholdReachedThenBreakerOpens(t *testing.T) {
+	b := New("http://node", 2, []time.Duration{time.Hour})
+
+	b.Failure(errors.New("connection refused"))
+	assert.True(t, b.Allow())
+
+	b.Failure(errors.New("connection refused"))
+	assert.False(t, b.Allow())
+	assert.Len(t, b.Outages(), 1)
+	assert.True(t, b.Outages()[0].End.IsZero())
+}
+
+func TestGivenOpenBreakerWhenBackoffElapsedThenSingleProbeIsAllowed(t *testing.T) {
+	b := New("http://node", 1, []time.Duration{time.Millisecond})
+
+	b.Failure(errors.New("connection refused"))
+	time.Sleep(2 * time.Millisecond)
+
+	assert.True(t, b.Allow())
+	assert.False(t, b.Allow())
+}
+
+func TestGivenOpenBreakerWhenProbeSucceedsThenOutageIsClosed(t *testing.T) {
+	b := New("http://node", 1, []time.Duration{time.Millisecond})
+
+	b.Failure(errors.New("connection refused"))
+	time.Sleep(2 * time.Millisecond)
+	assert.True(t, b.Allow())
+	b.Success()
+
+	assert.True(t, b.Allow())
+	outages := b.Outages()
+	assert.Len(t, outages, 1)
+	assert.False(t, outages[0].End.IsZero())
+	assert.Equal(t, "connection refused", outages[0].LastError)
+}
+
+func TestGivenFailuresBelowThresholdWhenSuccessThenNoOutageIsRecorded(t *testing.T) {
+	b := New("http://node", 3, DefaultBackoffs)
+
+	b.Failure(errors.New("timeout"))
+	b.Success()
+
+	assert.Empty(t, b.Outages())
+}
+
+func TestGivenRequestsBlockedByStrictModeWhenSentThenBreakerStaysClosed(t *testing.T) {
+	transport := NewTransport(roundTripFunc(func(*http.Request) (*http.Response, error) {
+		return nil, errors.Join(audit.ErrBlocked, errors.New("JSON-RPC method admin_peers may change the node"))
+	}))
+
+	for range defaultThreshold + 1 {
+		req := httptest.NewRequest(http.MethodPost, "http://blocked.node:8545", nil)
+		_, err := transport.RoundTrip(req)
+		assert.ErrorIs(t, err, audit.ErrBlocked)
+	}
+
+	b := For("http://blocked.node:8545")
+	assert.True(t, b.Allow())
+	assert.Empty(t, b.Outages())
+}
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
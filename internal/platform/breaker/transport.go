@@ -0,0 +1,46 @@
+package breaker
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/audit"
+)
+
+// Transport guards every endpoint (scheme and host) it sends requests to with its circuit breaker.
+// Connection errors and 5xx responses count as failures, requests cancelled by their caller or blocked by strict
+// read-only mode before reaching the endpoint don't count.
+type Transport struct {
+	next http.RoundTripper
+}
+
+func NewTransport(next http.RoundTripper) *Transport {
+	return &Transport{
+		next: next,
+	}
+}
+
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	b := For(req.URL.Scheme + "://" + req.URL.Host)
+	if !b.Allow() {
+		return nil, fmt.Errorf("%w: '%s'", ErrOpen, b.endpoint)
+	}
+
+	res, err := t.next.RoundTrip(req)
+	if err != nil {
+		if req.Context().Err() != nil || errors.Is(err, audit.ErrBlocked) {
+			b.Release()
+		} else {
+			b.Failure(err)
+		}
+		return nil, err
+	}
+
+	if res.StatusCode >= http.StatusInternalServerError {
+		b.Failure(fmt.Errorf("received unsuccessful status code. Code: '%s'", res.Status))
+	} else {
+		b.Success()
+	}
+	return res, nil
+}
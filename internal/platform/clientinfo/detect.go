@@ -0,0 +1,171 @@
+package clientinfo
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+type Client string
+
+const (
+	Unknown Client = "unknown"
+
+	Lighthouse Client = "lighthouse"
+	Teku       Client = "teku"
+	Nimbus     Client = "nimbus"
+	Prysm      Client = "prysm"
+	Lodestar   Client = "lodestar"
+	Grandine   Client = "grandine"
+
+	Geth       Client = "geth"
+	Nethermind Client = "nethermind"
+	Besu       Client = "besu"
+	Reth       Client = "reth"
+	Erigon     Client = "erigon"
+)
+
+type (
+	// Adapter describes the client specific defaults and API quirks metrics need to take into account
+	Adapter struct {
+		Client Client
+		// P2PPort is the default TCP/UDP port used for peering, the inbound connectivity metrics probe it when the node
+		// doesn't announce its port
+		P2PPort uint16
+		// MetricsPort is the default port of the client Prometheus endpoint
+		MetricsPort uint16
+		// MetricsPath is the path of the client Prometheus endpoint, '/metrics' when empty
+		MetricsPath string
+		// GoRuntime tells whether the client is written in Go and exposes the stats of the Go runtime
+		GoRuntime bool
+		// AdminPeers tells whether the client implements admin_peers with the geth response shape
+		AdminPeers bool
+		// GatewayAPI tells whether the beacon node API is served through a gRPC gateway with deviating responses
+		GatewayAPI bool
+		// GRPCPort is the default port of the native gRPC API, zero when the client has none
+		GRPCPort uint16
+	}
+
+	Detection struct {
+		Consensus        Adapter
+		ConsensusVersion string
+		Execution        Adapter
+		ExecutionVersion string
+		// ConsensusGRPC is the native gRPC API of the consensus client selected for the metrics, empty when unused
+		ConsensusGRPC string
+	}
+)
+
+var adapters = map[Client]Adapter{
+	Lighthouse: {Client: Lighthouse, P2PPort: 9000, MetricsPort: 5054},
+	Teku:       {Client: Teku, P2PPort: 9000, MetricsPort: 8008},
+	Nimbus:     {Client: Nimbus, P2PPort: 9000, MetricsPort: 8008},
+	Prysm:      {Client: Prysm, P2PPort: 13000, MetricsPort: 8080, GatewayAPI: true, GoRuntime: true, GRPCPort: 4000},
+	Lodestar:   {Client: Lodestar, P2PPort: 9000, MetricsPort: 8008},
+	Grandine:   {Client: Grandine, P2PPort: 9000, MetricsPort: 5054},
+
+	Geth:       {Client: Geth, P2PPort: 30303, MetricsPort: 6060, MetricsPath: "/debug/metrics/prometheus", AdminPeers: true, GoRuntime: true},
+	Nethermind: {Client: Nethermind, P2PPort: 30303, MetricsPort: 9091, AdminPeers: true},
+	Besu:       {Client: Besu, P2PPort: 30303, MetricsPort: 9545, AdminPeers: true},
+	Reth:       {Client: Reth, P2PPort: 30303, MetricsPort: 9001, AdminPeers: true},
+	Erigon:     {Client: Erigon, P2PPort: 30303, MetricsPort: 6060, MetricsPath: "/debug/metrics/prometheus", AdminPeers: false, GoRuntime: true},
+}
+
+// AdapterOf returns the adapter of the client, unknown clients get spec defaults without quirks
+func AdapterOf(client Client) Adapter {
+	if adapter, ok := adapters[client]; ok {
+		return adapter
+	}
+	return Adapter{Client: Unknown}
+}
+
+// PrometheusURL returns the default Prometheus endpoint of the client on the host of its API address, e.g.
+// 'http://geth:6060/debug/metrics/prometheus' for 'http://geth:8545'
+func (a Adapter) PrometheusURL(address string) (string, error) {
+	parsed, err := url.Parse(address)
+	if err != nil {
+		return "", err
+	}
+	if parsed.Hostname() == "" || a.MetricsPort == 0 {
+		return "", fmt.Errorf("the Prometheus endpoint of client '%s' at '%s' is unknown", a.Client, address)
+	}
+
+	path := a.MetricsPath
+	if path == "" {
+		path = "/metrics"
+	}
+	return (&url.URL{
+		Scheme: parsed.Scheme,
+		Host:   net.JoinHostPort(parsed.Hostname(), strconv.Itoa(int(a.MetricsPort))),
+		Path:   path,
+	}).String(), nil
+}
+
+// GRPCAddress returns the default native gRPC API of the client on the host of its API address, e.g. 'prysm:4000' for
+// 'http://prysm:3500'
+func (a Adapter) GRPCAddress(address string) (string, error) {
+	parsed, err := url.Parse(address)
+	if err != nil {
+		return "", err
+	}
+	if parsed.Hostname() == "" || a.GRPCPort == 0 {
+		return "", fmt.Errorf("client '%s' at '%s' has no known gRPC API", a.Client, address)
+	}
+	return net.JoinHostPort(parsed.Hostname(), strconv.Itoa(int(a.GRPCPort))), nil
+}
+
+// clients are matched with the version strings in order, so a version naming several clients always identifies the
+// same one
+var clients = []Client{Lighthouse, Teku, Nimbus, Prysm, Lodestar, Grandine, Geth, Nethermind, Besu, Reth, Erigon}
+
+// ParseClient identifies the client from its version string, e.g. 'teku/v24.1.0/linux-x86_64/-eclipseadoptium-openjdk64bitservervm-java-17'
+func ParseClient(version string) Client {
+	name, _, _ := strings.Cut(strings.ToLower(version), "/")
+	for _, client := range clients {
+		if strings.Contains(name, string(client)) {
+			return client
+		}
+	}
+	return Unknown
+}
+
+// Detect queries the client versions of both layers and selects their adapters.
+// Unreachable endpoints are detected as unknown clients, so metrics fall back to spec behaviour.
+func Detect(ctx context.Context, consensusURL, executionURL string) Detection {
+	var detection Detection
+
+	detection.Consensus = AdapterOf(Unknown)
+	if consensusURL != "" {
+		version, err := ConsensusVersion(ctx, consensusURL)
+		if err != nil {
+			slog.With("err", err.Error()).Warn("failed detecting consensus client")
+		} else {
+			detection.ConsensusVersion = version
+			detection.Consensus = AdapterOf(ParseClient(version))
+		}
+	}
+
+	detection.Execution = AdapterOf(Unknown)
+	if executionURL != "" {
+		version, err := ExecutionVersion(ctx, executionURL)
+		if err != nil {
+			slog.With("err", err.Error()).Warn("failed detecting execution client")
+		} else {
+			detection.ExecutionVersion = version
+			detection.Execution = AdapterOf(ParseClient(version))
+		}
+	}
+
+	slog.
+		With("consensus_client", detection.Consensus.Client).
+		With("consensus_version", detection.ConsensusVersion).
+		With("execution_client", detection.Execution.Client).
+		With("execution_version", detection.ExecutionVersion).
+		Info("clients detected")
+
+	return detection
+}
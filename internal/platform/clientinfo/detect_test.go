@@ -0,0 +1,54 @@
+package clientinfo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGivenClientVersionWhenParseClientThenIdentifiesClient(t *testing.T) {
+	tests := []struct {
+		version  string
+		expected Client
+	}{
+		{version: "Lighthouse/v5.1.3-3058b96/x86_64-linux", expected: Lighthouse},
+		{version: "teku/v24.1.0/linux-x86_64/-eclipseadoptium-openjdk64bitservervm-java-17", expected: Teku},
+		{version: "Nimbus/v24.2.0-6b7b0ff7-stateofus", expected: Nimbus},
+		{version: "Prysm/v5.0.0/7c8a3e54e6f6b3f9a2", expected: Prysm},
+		{version: "Geth/v1.13.14-stable-2bd6bd01/linux-amd64/go1.21.7", expected: Geth},
+		{version: "Nethermind/v1.25.4+20b10b35/linux-x64/dotnet8.0.2", expected: Nethermind},
+		{version: "besu/v24.1.2/linux-x86_64/openjdk-java-17", expected: Besu},
+		{version: "reth/v0.2.0-beta.2-d3e0ce4/x86_64-unknown-linux-gnu", expected: Reth},
+		{version: "erigon/2.58.2/linux-amd64/go1.21.6", expected: Erigon},
+		{version: "reth-geth-bridge/v1", expected: Geth},
+		{version: "somethingelse/v1", expected: Unknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.version, func(t *testing.T) {
+			assert.Equal(t, tt.expected, ParseClient(tt.version))
+		})
+	}
+}
+
+func TestGivenClientAddressWhenPrometheusURLThenDefaultEndpointOnSameHost(t *testing.T) {
+	address, err := AdapterOf(Geth).PrometheusURL("http://geth:8545")
+	assert.NoError(t, err)
+	assert.Equal(t, "http://geth:6060/debug/metrics/prometheus", address)
+
+	address, err = AdapterOf(Prysm).PrometheusURL("http://10.0.0.2:3500")
+	assert.NoError(t, err)
+	assert.Equal(t, "http://10.0.0.2:8080/metrics", address)
+
+	_, err = AdapterOf(Unknown).PrometheusURL("http://node:5052")
+	assert.Error(t, err)
+}
+
+func TestGivenClientAddressWhenGRPCAddressThenDefaultPortOnSameHost(t *testing.T) {
+	address, err := AdapterOf(Prysm).GRPCAddress("http://10.0.0.2:3500")
+	assert.NoError(t, err)
+	assert.Equal(t, "10.0.0.2:4000", address)
+
+	_, err = AdapterOf(Lighthouse).GRPCAddress("http://lighthouse:5052")
+	assert.Error(t, err)
+}
@@ -0,0 +1,88 @@
+package clientinfo
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/httpclient"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/ipc"
+)
+
+// ConsensusVersion fetches the client version string from the beacon node API, e.g. 'Lighthouse/v5.1.3-3058b96/x86_64-linux'
+func ConsensusVersion(ctx context.Context, url string) (string, error) {
+	var resp struct {
+		Data struct {
+			Version string `json:"version"`
+		} `json:"data"`
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/eth/v1/node/version", url), nil)
+	if err != nil {
+		return "", err
+	}
+	if err := do(req, &resp); err != nil {
+		return "", errors.Join(err, errors.New("failed fetching consensus client version"))
+	}
+
+	return resp.Data.Version, nil
+}
+
+// ExecutionVersion fetches the client version string via the web3_clientVersion RPC method over HTTP or IPC, e.g. 'Geth/v1.13.14-stable/linux-amd64/go1.21.7'
+func ExecutionVersion(ctx context.Context, url string) (string, error) {
+	var resp struct {
+		Result string `json:"result"`
+		Error  *struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+
+	request := map[string]any{
+		"jsonrpc": "2.0",
+		"method":  "web3_clientVersion",
+		"params":  []any{},
+		"id":      1,
+	}
+
+	if ipc.IsSocket(url) {
+		if err := ipc.Call(ctx, url, request, &resp); err != nil {
+			return "", errors.Join(err, errors.New("failed fetching execution client version"))
+		}
+	} else {
+		body, err := json.Marshal(request)
+		if err != nil {
+			return "", err
+		}
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+		if err != nil {
+			return "", err
+		}
+		req.Header.Set("Content-Type", "application/json")
+
+		if err := do(req, &resp); err != nil {
+			return "", errors.Join(err, errors.New("failed fetching execution client version"))
+		}
+	}
+	if resp.Error != nil {
+		return "", fmt.Errorf("web3_clientVersion RPC error: '%s'", resp.Error.Message)
+	}
+
+	return resp.Result, nil
+}
+
+func do(req *http.Request, result any) error {
+	res, err := httpclient.Default.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("received unsuccessful status code. Code: '%s'", res.Status)
+	}
+
+	return json.NewDecoder(res.Body).Decode(result)
+}
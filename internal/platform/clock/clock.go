@@ -0,0 +1,29 @@
+package clock
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// offset shifts the clock from the wall clock, so a replayed run sees the time of its recording
+var offset atomic.Int64
+
+// Now returns the time of the run, which is the wall clock unless the clock was shifted
+func Now() time.Time {
+	return time.Now().Add(time.Duration(offset.Load()))
+}
+
+// Since returns the run time elapsed since t
+func Since(t time.Time) time.Duration {
+	return Now().Sub(t)
+}
+
+// Until returns the run time remaining until t
+func Until(t time.Time) time.Duration {
+	return t.Sub(Now())
+}
+
+// Set shifts the clock, so it continues from now. Time passes at the speed of the wall clock.
+func Set(now time.Time) {
+	offset.Store(int64(now.Sub(time.Now())))
+}
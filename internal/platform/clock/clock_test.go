@@ -0,0 +1,17 @@
+package clock
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGivenClockSetWhenNowThenContinuesFromSetTime(t *testing.T) {
+	recorded := time.Date(2024, 9, 1, 12, 0, 0, 0, time.UTC)
+	Set(recorded)
+	defer Set(time.Now())
+
+	assert.WithinDuration(t, recorded, Now(), time.Second)
+	assert.InDelta(t, float64(time.Hour), float64(Until(recorded.Add(time.Hour))), float64(time.Second))
+}
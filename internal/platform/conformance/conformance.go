@@ -0,0 +1,259 @@
+package conformance
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Harikakasimahanthi/benchmark-test/report"
+)
+
+const (
+	slotsPerEpoch = 32
+
+	checkTimeout = time.Second * 5
+	// eventTimeout covers a slot without block, a head event is expected at least every other slot
+	eventTimeout = time.Second * 25
+)
+
+// check exercises one endpoint of the beacon API the benchmark depends on
+type check struct {
+	category string
+	method   string
+	// path formats the endpoint with the head slot of the node
+	path   func(head uint64) string
+	body   string
+	usedBy []string
+	// fields are required in the data of the response as dotted paths, '[]' steps into the elements of an array
+	fields []string
+	// statuses are the accepted status codes besides 200
+	statuses []int
+	// stream reads the first event of an event stream instead of a JSON response
+	stream bool
+}
+
+var checks = []check{
+	{category: "node", method: http.MethodGet, path: fixed("/eth/v1/node/version"), usedBy: []string{"Client", "Latency"}, fields: []string{"version"}},
+	{category: "node", method: http.MethodGet, path: fixed("/eth/v1/node/health"), usedBy: []string{"Client", "Latency Paths"}, statuses: []int{http.StatusPartialContent}},
+	{category: "node", method: http.MethodGet, path: fixed("/eth/v1/node/syncing"), usedBy: []string{"Client", "Sync"}, fields: []string{"head_slot", "sync_distance", "is_syncing"}},
+	{category: "node", method: http.MethodGet, path: fixed("/eth/v1/node/peer_count"), usedBy: []string{"Peers"}, fields: []string{"connected"}},
+	{category: "node", method: http.MethodGet, path: fixed("/eth/v1/node/peers"), usedBy: []string{"Peers", "Inbound Connectivity"}, fields: []string{"[].peer_id", "[].direction"}},
+	{category: "node", method: http.MethodGet, path: fixed("/eth/v1/node/identity"), usedBy: []string{"Network", "Inbound Connectivity"}, fields: []string{"peer_id", "enr"}},
+	{category: "config", method: http.MethodGet, path: fixed("/eth/v1/beacon/genesis"), usedBy: []string{"Network"}, fields: []string{"genesis_time", "genesis_fork_version"}},
+	{category: "config", method: http.MethodGet, path: fixed("/eth/v1/config/fork_schedule"), usedBy: []string{"Network"}, fields: []string{"[].current_version", "[].epoch"}},
+	{category: "beacon", method: http.MethodGet, path: fixed("/eth/v1/beacon/states/head/fork"), usedBy: []string{"Network"}, fields: []string{"current_version"}},
+	{category: "beacon", method: http.MethodGet, path: fixed("/eth/v1/beacon/blocks/head/root"), usedBy: []string{"Attestation", "Vote Cross-Check"}, fields: []string{"root"}},
+	{category: "beacon", method: http.MethodGet, path: slotPath("/eth/v1/beacon/headers/%d"), usedBy: []string{"Attestation", "Duty Calendar"}, fields: []string{"root", "header.message.proposer_index"}},
+	{category: "beacon", method: http.MethodGet, path: slotPath("/eth/v2/beacon/blocks/%d"), usedBy: []string{"Sync Committee"}, fields: []string{"message.slot", "message.body"}},
+	{category: "beacon", method: http.MethodGet, path: slotPath("/eth/v1/beacon/blinded_blocks/%d"), usedBy: []string{"Blobs"}, fields: []string{"message.body"}},
+	{category: "beacon", method: http.MethodGet, path: slotPath("/eth/v1/beacon/blob_sidecars/%d"), usedBy: []string{"Blobs"}, fields: []string{"[].index"}},
+	{category: "beacon", method: http.MethodGet, path: fixed("/eth/v1/beacon/states/head/validator_balances?id=0"), usedBy: []string{"Balances"}, fields: []string{"[].index", "[].balance"}},
+	{category: "beacon", method: http.MethodGet, path: fixed("/eth/v1/beacon/states/head/sync_committees"), usedBy: []string{"Sync Committee"}, fields: []string{"validators"}},
+	{category: "beacon", method: http.MethodPost, path: finalizedEpochPath("/eth/v1/beacon/rewards/attestations/%d"), body: `["0"]`, usedBy: []string{"Attestation"}, fields: []string{"total_rewards"}},
+	{category: "validator", method: http.MethodGet, path: epochPath("/eth/v1/validator/duties/proposer/%d"), usedBy: []string{"Duty Calendar"}, fields: []string{"[].pubkey", "[].validator_index", "[].slot"}},
+	{category: "validator", method: http.MethodPost, path: epochPath("/eth/v1/validator/duties/attester/%d"), body: `["0"]`, usedBy: []string{"Duty Simulation"}, fields: []string{"[].validator_index", "[].committee_index", "[].slot"}},
+	{category: "validator", method: http.MethodPost, path: epochPath("/eth/v1/validator/duties/sync/%d"), body: `["0"]`, usedBy: []string{"Duty Calendar"}},
+	{category: "validator", method: http.MethodGet, path: slotPath("/eth/v1/validator/attestation_data?slot=%d&committee_index=0"), usedBy: []string{"Attestation", "Duty Simulation", "Vote Cross-Check"}, fields: []string{"beacon_block_root", "source.epoch", "target.root"}},
+	{category: "events", method: http.MethodGet, path: fixed("/eth/v1/events?topics=head"), usedBy: []string{"Attestation", "Head Delay"}, fields: []string{"slot", "block"}, stream: true},
+}
+
+// Check exercises the beacon API surface the benchmark depends on at the address, one endpoint after the other. The
+// endpoints taking a slot or an epoch are checked with the head of the node.
+func Check(ctx context.Context, client *http.Client, address string) []report.ConformanceResult {
+	address = strings.TrimSuffix(address, "/")
+	head := headSlot(ctx, client, address)
+
+	results := make([]report.ConformanceResult, 0, len(checks))
+	for _, c := range checks {
+		path := c.path(head)
+		result := report.ConformanceResult{Category: c.category, Endpoint: c.method + " " + path, UsedBy: c.usedBy}
+		result.Status, result.Detail = c.run(ctx, client, address+path)
+		results = append(results, result)
+	}
+	return results
+}
+
+func (c check) run(ctx context.Context, client *http.Client, url string) (string, string) {
+	timeout := checkTimeout
+	if c.stream {
+		timeout = eventTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var body io.Reader
+	if c.body != "" {
+		body = strings.NewReader(c.body)
+	}
+	req, err := http.NewRequestWithContext(ctx, c.method, url, body)
+	if err != nil {
+		return report.ConformanceUnreachable, err.Error()
+	}
+	if c.body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.stream {
+		req.Header.Set("Accept", "text/event-stream")
+	}
+
+	res, err := client.Do(req)
+	if err != nil {
+		return report.ConformanceUnreachable, err.Error()
+	}
+	defer res.Body.Close()
+
+	switch {
+	case res.StatusCode == http.StatusNotFound || res.StatusCode == http.StatusMethodNotAllowed || res.StatusCode == http.StatusNotImplemented:
+		return report.ConformanceUnsupported, fmt.Sprintf("status %d", res.StatusCode)
+	case res.StatusCode != http.StatusOK && !contains(c.statuses, res.StatusCode):
+		return report.ConformanceViolation, fmt.Sprintf("unexpected status %d", res.StatusCode)
+	}
+
+	if c.stream {
+		if !strings.HasPrefix(res.Header.Get("Content-Type"), "text/event-stream") {
+			return report.ConformanceViolation, fmt.Sprintf("content type '%s' is not an event stream", res.Header.Get("Content-Type"))
+		}
+		data, err := firstEvent(res.Body)
+		if err != nil {
+			return report.ConformanceViolation, err.Error()
+		}
+		return validate(data, c.fields, false)
+	}
+	if len(c.fields) == 0 {
+		return report.ConformanceSupported, ""
+	}
+	data, err := io.ReadAll(res.Body)
+	if err != nil {
+		return report.ConformanceViolation, errors.Join(err, errors.New("failed reading the response")).Error()
+	}
+	return validate(data, c.fields, true)
+}
+
+// validate checks the fields are present in the JSON document, within its 'data' when it is wrapped
+func validate(document []byte, fields []string, wrapped bool) (string, string) {
+	var decoded any
+	if err := json.Unmarshal(document, &decoded); err != nil {
+		return report.ConformanceViolation, "the response is no valid JSON"
+	}
+	prefix := ""
+	if wrapped {
+		object, ok := decoded.(map[string]any)
+		if !ok || object["data"] == nil {
+			return report.ConformanceViolation, "missing 'data'"
+		}
+		decoded, prefix = object["data"], "data."
+	}
+
+	var missing []string
+	for _, field := range fields {
+		if !hasField(decoded, strings.Split(field, ".")) {
+			missing = append(missing, "'"+prefix+field+"'")
+		}
+	}
+	if len(missing) != 0 {
+		return report.ConformanceViolation, "missing " + strings.Join(missing, ", ")
+	}
+	return report.ConformanceSupported, ""
+}
+
+// hasField navigates the path through the value, the elements of an empty array can't miss fields
+func hasField(value any, path []string) bool {
+	if len(path) == 0 {
+		return value != nil
+	}
+	if path[0] == "[]" {
+		elements, ok := value.([]any)
+		if !ok {
+			return false
+		}
+		for _, element := range elements {
+			if !hasField(element, path[1:]) {
+				return false
+			}
+		}
+		return true
+	}
+	object, ok := value.(map[string]any)
+	if !ok {
+		return false
+	}
+	return hasField(object[path[0]], path[1:])
+}
+
+// firstEvent returns the data of the first event of the stream
+func firstEvent(stream io.Reader) ([]byte, error) {
+	scanner := bufio.NewScanner(stream)
+	for scanner.Scan() {
+		if data, ok := bytes.CutPrefix(scanner.Bytes(), []byte("data:")); ok {
+			return bytes.TrimSpace(data), nil
+		}
+	}
+	if err := scanner.Err(); err != nil && !errors.Is(err, context.DeadlineExceeded) {
+		return nil, errors.Join(err, errors.New("failed reading the event stream"))
+	}
+	return nil, fmt.Errorf("no event within %s", eventTimeout)
+}
+
+// headSlot returns the head slot of the node, 0 when it is unknown
+func headSlot(ctx context.Context, client *http.Client, address string) uint64 {
+	ctx, cancel := context.WithTimeout(ctx, checkTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, address+"/eth/v1/node/syncing", nil)
+	if err != nil {
+		return 0
+	}
+	res, err := client.Do(req)
+	if err != nil {
+		return 0
+	}
+	defer res.Body.Close()
+
+	var syncing struct {
+		Data struct {
+			HeadSlot string `json:"head_slot"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&syncing); err != nil {
+		return 0
+	}
+	slot, err := strconv.ParseUint(syncing.Data.HeadSlot, 10, 64)
+	if err != nil {
+		return 0
+	}
+	return slot
+}
+
+func fixed(path string) func(uint64) string {
+	return func(uint64) string { return path }
+}
+
+func slotPath(format string) func(uint64) string {
+	return func(head uint64) string { return fmt.Sprintf(format, head) }
+}
+
+func epochPath(format string) func(uint64) string {
+	return func(head uint64) string { return fmt.Sprintf(format, head/slotsPerEpoch) }
+}
+
+// finalizedEpochPath formats the path with an epoch behind the head which is finalized on a healthy chain
+func finalizedEpochPath(format string) func(uint64) string {
+	return func(head uint64) string { return fmt.Sprintf(format, max(head/slotsPerEpoch, 2)-2) }
+}
+
+func contains(statuses []int, status int) bool {
+	for _, s := range statuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,94 @@
+package conformance
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Harikakasimahanthi/benchmark-test/report"
+)
+
+var responses = map[string]string{
+	"/eth/v1/node/version":                          `{"data":{"version":"Lighthouse/v5.1.0"}}`,
+	"/eth/v1/node/syncing":                          `{"data":{"head_slot":"6400","sync_distance":"0","is_syncing":false}}`,
+	"/eth/v1/node/peers":                            `{"data":[{"peer_id":"16Uiu2","direction":"inbound"}]}`,
+	"/eth/v1/node/identity":                         `{"data":{"peer_id":"16Uiu2"}}`,
+	"/eth/v1/beacon/genesis":                        `{"data":{"genesis_time":"1606824023","genesis_fork_version":"0x00000000"}}`,
+	"/eth/v1/config/fork_schedule":                  `{"data":[{"current_version":"0x00000000","epoch":"0"}]}`,
+	"/eth/v1/beacon/states/head/fork":               `{"data":{"current_version":"0x04000000"}}`,
+	"/eth/v1/beacon/blocks/head/root":               `{"data":{"root":"0xcf8e"}}`,
+	"/eth/v1/beacon/headers/6400":                   `{"data":{"root":"0xcf8e","header":{"message":{"proposer_index":"1"}}}}`,
+	"/eth/v2/beacon/blocks/6400":                    `{"data":{"message":{"slot":"6400","body":{}}}}`,
+	"/eth/v1/beacon/blinded_blocks/6400":            `{"data":{"message":{"slot":"6400","body":{"blob_kzg_commitments":[]}}}}`,
+	"/eth/v1/beacon/blob_sidecars/6400":             `{"data":[]}`,
+	"/eth/v1/beacon/states/head/validator_balances": `{"data":[{"index":"0","balance":"32000000000"}]}`,
+	"/eth/v1/beacon/states/head/sync_committees":    `{"data":{"validators":["1","2"]}}`,
+	"/eth/v1/beacon/rewards/attestations/198":       `{"data":{"total_rewards":[]}}`,
+	"/eth/v1/validator/duties/proposer/200":         `{"data":[{"pubkey":"0x93","validator_index":"1","slot":"6400"}]}`,
+	"/eth/v1/validator/duties/attester/200":         `{"data":[]}`,
+	"/eth/v1/validator/duties/sync/200":             `{"data":[]}`,
+	"/eth/v1/validator/attestation_data":            `{"data":{"beacon_block_root":"0xcf8e","source":{"epoch":"198"},"target":{"root":"0xcf8e"}}}`,
+}
+
+func TestGivenNodeWhenCheckThenEndpointsClassified(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/eth/v1/node/health":
+			w.WriteHeader(http.StatusPartialContent)
+		case "/eth/v1/events":
+			w.Header().Set("Content-Type", "text/event-stream")
+			w.Write([]byte("event: head\ndata: {\"slot\":\"6401\",\"block\":\"0x9a2f\"}\n\n"))
+		default:
+			response, ok := responses[r.URL.Path]
+			if !ok {
+				http.NotFound(w, r)
+				return
+			}
+			w.Write([]byte(response))
+		}
+	}))
+	defer server.Close()
+
+	results := Check(context.Background(), http.DefaultClient, server.URL+"/")
+
+	statuses := make(map[string]string)
+	details := make(map[string]string)
+	for _, result := range results {
+		statuses[result.Endpoint] = result.Status
+		details[result.Endpoint] = result.Detail
+	}
+	require.Len(t, results, len(checks))
+	assert.Equal(t, report.ConformanceSupported, statuses["GET /eth/v1/node/health"])
+	assert.Equal(t, report.ConformanceSupported, statuses["POST /eth/v1/beacon/rewards/attestations/198"])
+	assert.Equal(t, report.ConformanceSupported, statuses["GET /eth/v1/validator/attestation_data?slot=6400&committee_index=0"])
+	assert.Equal(t, report.ConformanceSupported, statuses["GET /eth/v1/events?topics=head"])
+	assert.Equal(t, report.ConformanceSupported, statuses["GET /eth/v1/beacon/blob_sidecars/6400"])
+	assert.Equal(t, report.ConformanceUnsupported, statuses["GET /eth/v1/node/peer_count"])
+	assert.Equal(t, report.ConformanceViolation, statuses["GET /eth/v1/node/identity"])
+	assert.Equal(t, "missing 'data.enr'", details["GET /eth/v1/node/identity"])
+}
+
+func TestGivenUnreachableNodeWhenCheckThenEveryEndpointUnreachable(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+
+	results := Check(context.Background(), http.DefaultClient, server.URL)
+
+	for _, result := range results {
+		assert.Equal(t, report.ConformanceUnreachable, result.Status, result.Endpoint)
+	}
+}
+
+func TestGivenFieldPathsWhenValidateThenMissingFieldsListed(t *testing.T) {
+	status, detail := validate([]byte(`{"data":[{"a":{"b":"1"}},{"a":{}}]}`), []string{"[].a", "[].a.b"}, true)
+
+	assert.Equal(t, report.ConformanceViolation, status)
+	assert.Equal(t, "missing 'data.[].a.b'", detail)
+
+	status, _ = validate([]byte(`{"data":[]}`), []string{"[].a"}, true)
+	assert.Equal(t, report.ConformanceSupported, status)
+}
@@ -0,0 +1,113 @@
+package enr
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+)
+
+// Record holds the network fields of an Ethereum Node Record (EIP-778), the signature is not verified
+type Record struct {
+	Seq   uint64
+	IP    net.IP
+	TCP   uint16
+	UDP   uint16
+	Pairs map[string][]byte
+}
+
+// Parse decodes the textual 'enr:' representation of a node record
+func Parse(text string) (Record, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(strings.TrimPrefix(text, "enr:"))
+	if err != nil {
+		return Record{}, errors.Join(err, errors.New("ENR was not valid base64"))
+	}
+
+	content, rest, err := readList(raw)
+	if err != nil {
+		return Record{}, err
+	}
+	if len(rest) != 0 {
+		return Record{}, errors.New("ENR has trailing data")
+	}
+
+	var items [][]byte
+	for len(content) != 0 {
+		var item []byte
+		item, content, err = readString(content)
+		if err != nil {
+			return Record{}, err
+		}
+		items = append(items, item)
+	}
+	// Signature, sequence number and key/value pairs
+	if len(items) < 2 || len(items)%2 != 0 {
+		return Record{}, fmt.Errorf("ENR has an invalid number of items: %d", len(items))
+	}
+
+	record := Record{
+		Seq:   toUint(items[1]),
+		Pairs: make(map[string][]byte, (len(items)-2)/2),
+	}
+	for i := 2; i < len(items); i += 2 {
+		record.Pairs[string(items[i])] = items[i+1]
+	}
+	if ip, ok := record.Pairs["ip"]; ok && len(ip) == net.IPv4len {
+		record.IP = net.IP(ip)
+	}
+	record.TCP = uint16(toUint(record.Pairs["tcp"]))
+	record.UDP = uint16(toUint(record.Pairs["udp"]))
+
+	return record, nil
+}
+
+// readList reads the RLP list header and returns its content
+func readList(data []byte) ([]byte, []byte, error) {
+	if len(data) == 0 || data[0] < 0xc0 {
+		return nil, nil, errors.New("ENR is not an RLP list")
+	}
+	return readPayload(data, 0xc0)
+}
+
+// readString reads an RLP string, nested lists are not used by node records
+func readString(data []byte) ([]byte, []byte, error) {
+	switch {
+	case len(data) == 0:
+		return nil, nil, errors.New("unexpected end of ENR")
+	case data[0] < 0x80:
+		return data[:1], data[1:], nil
+	case data[0] < 0xc0:
+		return readPayload(data, 0x80)
+	default:
+		return nil, nil, errors.New("unexpected RLP list in ENR")
+	}
+}
+
+// readPayload reads a string (offset 0x80) or list (offset 0xc0) payload with a short or long length prefix
+func readPayload(data []byte, offset byte) ([]byte, []byte, error) {
+	prefix := data[0] - offset
+	start, length := 1, uint64(prefix)
+	if prefix > 55 {
+		lengthSize := int(prefix - 55)
+		if len(data) < 1+lengthSize {
+			return nil, nil, errors.New("unexpected end of ENR")
+		}
+		start, length = 1+lengthSize, toUint(data[1:1+lengthSize])
+	}
+	if uint64(len(data)-start) < length {
+		return nil, nil, errors.New("unexpected end of ENR")
+	}
+	end := start + int(length)
+	return data[start:end], data[end:], nil
+}
+
+func toUint(value []byte) uint64 {
+	if len(value) > 8 {
+		return 0
+	}
+	padded := make([]byte, 8)
+	copy(padded[8-len(value):], value)
+	return binary.BigEndian.Uint64(padded)
+}
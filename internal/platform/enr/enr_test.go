@@ -0,0 +1,26 @@
+package enr
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGivenEIP778ExampleWhenParseThenDecodesNetworkFields(t *testing.T) {
+	// Example record of EIP-778
+	record, err := Parse("enr:-IS4QHCYrYZbAKWCBRlAy5zzaDZXJBGkcnh4MHcBFZntXNFrdvJjX04jRzjzCBOonrkTfj499SZuOh8R33Ls8RRcy5wBgmlkgnY0gmlwhH8AAAGJc2VjcDI1NmsxoQPKY0yuDUmstAHYpMa2_oxVtw0RW_QAdpzBQA8yWM0xOIN1ZHCCdl8")
+
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), record.Seq)
+	assert.Equal(t, "127.0.0.1", record.IP.String())
+	assert.Equal(t, uint16(30303), record.UDP)
+	assert.Equal(t, uint16(0), record.TCP)
+	assert.Equal(t, []byte("v4"), record.Pairs["id"])
+}
+
+func TestGivenInvalidRecordWhenParseThenFails(t *testing.T) {
+	_, err := Parse("enr:AAAA")
+
+	assert.Error(t, err)
+}
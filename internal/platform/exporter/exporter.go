@@ -0,0 +1,175 @@
+package exporter
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/ipc"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/metric"
+)
+
+const (
+	namespace = "solostaking_benchmark"
+
+	sessionLabel  = "session"
+	groupLabel    = "group"
+	endpointLabel = "endpoint"
+	typeLabel     = "type"
+	outcomeLabel  = "outcome"
+	sourceLabel   = "source"
+	textLabel     = "text"
+
+	// Outcomes of the slots observed by the attestation metric
+	MissedBlock       = "missed_block"
+	MissedAttestation = "missed_attestation"
+	FreshAttestation  = "fresh_attestation"
+
+	// nativeBucketFactor is the growth of the buckets of the native histograms, at most 10% per bucket
+	nativeBucketFactor = 1.1
+)
+
+// DefaultLatencyBuckets are the classic buckets of the latency histograms in seconds. Scrapers supporting native
+// histograms get the exponential native buckets instead, which need no configuration.
+var DefaultLatencyBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5}
+
+var (
+	latency = newLatency(DefaultLatencyBuckets)
+	peers   = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "peers",
+		Help:      "Connected peers of the node",
+	}, []string{sessionLabel, groupLabel, endpointLabel})
+	correctness = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "attestation_correctness_percent",
+		Help:      "Share of the blocks whose attestation would have voted for the correct head",
+	}, []string{sessionLabel, groupLabel, endpointLabel})
+	slots = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "attestation_slots_total",
+		Help:      "Slots observed by the attestation metric by outcome, blocks were received in the slots with an attestation",
+	}, []string{sessionLabel, groupLabel, endpointLabel, outcomeLabel})
+	memory = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "memory_bytes",
+		Help:      "Memory of the machine by type, e.g. used or free",
+	}, []string{sessionLabel, groupLabel, endpointLabel, typeLabel})
+	annotations = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "annotation_timestamp_seconds",
+		Help:      "Time of the annotations of the run, e.g. client restarts, for dashboards to mark on their charts",
+	}, []string{sourceLabel, textLabel})
+
+	// redact obscures the endpoints and texts of the labels, the labels are kept as they are unless SetRedaction is
+	// called
+	redact = func(label string) string { return label }
+)
+
+type sessionKey struct{}
+
+func newLatency(buckets []float64) *prometheus.HistogramVec {
+	return prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace:                      namespace,
+		Name:                           "request_duration_seconds",
+		Help:                           "Duration of the latency requests to the node",
+		Buckets:                        buckets,
+		NativeHistogramBucketFactor:    nativeBucketFactor,
+		NativeHistogramMaxBucketNumber: 100,
+	}, []string{sessionLabel, groupLabel, endpointLabel})
+}
+
+// Register registers the collectors served on '/metrics'. The latency histograms use the classic buckets, or the
+// default ones when none are passed. It must be called before the metrics are measured.
+func Register(buckets []float64) error {
+	if len(buckets) != 0 {
+		if err := ValidateBuckets(buckets); err != nil {
+			return err
+		}
+		latency = newLatency(buckets)
+	}
+
+	for _, collector := range []prometheus.Collector{latency, peers, correctness, slots, memory, annotations} {
+		if err := prometheus.Register(collector); err != nil {
+			return errors.Join(err, errors.New("failed registering Prometheus collector"))
+		}
+	}
+	return nil
+}
+
+// ValidateBuckets checks the upper bounds of the buckets are positive and increasing
+func ValidateBuckets(buckets []float64) error {
+	for i, bucket := range buckets {
+		if bucket <= 0 {
+			return errors.New("latency buckets should be positive")
+		}
+		if i > 0 && bucket <= buckets[i-1] {
+			return errors.New("latency buckets should be in increasing order")
+		}
+	}
+	return nil
+}
+
+// SetRedaction obscures the endpoints and the annotation texts of the labels with the function, e.g. so the metrics
+// can be shared publicly. It must be called before the metrics are measured.
+func SetRedaction(redactLabel func(string) string) {
+	redact = redactLabel
+}
+
+// WithSession labels the values recorded with the context by the session, so the sessions of a run measuring the same
+// endpoints don't overwrite each other
+func WithSession(ctx context.Context, session string) context.Context {
+	return context.WithValue(ctx, sessionKey{}, session)
+}
+
+// session is the session of the context, empty when the run has a single one
+func session(ctx context.Context) string {
+	name, _ := ctx.Value(sessionKey{}).(string)
+	return name
+}
+
+// ObserveLatency records the duration of a request to the endpoint
+func ObserveLatency(ctx context.Context, group metric.Group, endpoint string, duration time.Duration) {
+	latency.WithLabelValues(session(ctx), string(group), Endpoint(endpoint)).Observe(duration.Seconds())
+}
+
+// SetPeers records the connected peers of the node at the endpoint
+func SetPeers(ctx context.Context, group metric.Group, endpoint string, count float64) {
+	peers.WithLabelValues(session(ctx), string(group), Endpoint(endpoint)).Set(count)
+}
+
+// SetCorrectness records the attestation correctness in percent of the node at the endpoint
+func SetCorrectness(ctx context.Context, endpoint string, percent float64) {
+	correctness.WithLabelValues(session(ctx), string(metric.ConsensusGroup), Endpoint(endpoint)).Set(percent)
+}
+
+// CountSlot counts a slot of the outcome (e.g. MissedBlock) observed by the node at the endpoint
+func CountSlot(ctx context.Context, endpoint, outcome string) {
+	slots.WithLabelValues(session(ctx), string(metric.ConsensusGroup), Endpoint(endpoint), outcome).Inc()
+}
+
+// SetMemory records the memory of the type (e.g. 'used') of the local machine
+func SetMemory(ctx context.Context, kind string, bytes uint64) {
+	memory.WithLabelValues(session(ctx), string(metric.InfrastructureGroup), "localhost", kind).Set(float64(bytes))
+}
+
+// SetAnnotation records the time of the annotation of the source (e.g. 'user') with the text
+func SetAnnotation(source, text string, at time.Time) {
+	annotations.WithLabelValues(source, redact(text)).Set(float64(at.UnixMilli()) / 1000)
+}
+
+// Endpoint is the label of the address, its scheme and host. Paths and credentials are dropped, they may hold API
+// keys (e.g. 'https://mainnet.provider.example/v3/<key>'). IPC sockets keep their path.
+func Endpoint(address string) string {
+	if ipc.IsSocket(address) {
+		return redact(address)
+	}
+	parsed, err := url.Parse(address)
+	if err != nil || parsed.Host == "" {
+		return "unknown"
+	}
+	return redact(parsed.Scheme + "://" + parsed.Host)
+}
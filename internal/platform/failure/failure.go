@@ -0,0 +1,110 @@
+// Package failure classifies the errors ending a run, so the benchmark exits with a code scripts can react to, e.g.
+// retrying when a node was unreachable but not when the configuration is invalid, and users are told what to check.
+package failure
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Kind of failure, each kind exits with its own code
+type Kind int
+
+const (
+	// KindConfig is an invalid configuration or flag value, it fails until the user fixes it
+	KindConfig Kind = iota + 1
+	// KindConnectivity is a node which couldn't be reached or answered unexpectedly
+	KindConnectivity
+	// KindRuntime is a failure of the machine running the benchmark, e.g. the artifacts directory being unwritable
+	KindRuntime
+)
+
+// Exit codes of the kinds of failure, unclassified errors, e.g. unknown flags, exit with ExitUnknown
+const (
+	ExitUnknown      = 1
+	ExitConfig       = 2
+	ExitConnectivity = 3
+	ExitRuntime      = 4
+)
+
+// Error is an error of a kind
+type Error struct {
+	Kind Kind
+	Err  error
+}
+
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Config classifies the error as configuration failure, nil stays nil. Errors which are already classified keep their
+// kind, the classification closest to the cause is the most specific.
+func Config(err error) error {
+	return classify(KindConfig, err)
+}
+
+// Connectivity classifies the error as connectivity failure, like Config
+func Connectivity(err error) error {
+	return classify(KindConnectivity, err)
+}
+
+// Runtime classifies the error as runtime failure, like Config
+func Runtime(err error) error {
+	return classify(KindRuntime, err)
+}
+
+func classify(kind Kind, err error) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := KindOf(err); ok {
+		return err
+	}
+	return &Error{Kind: kind, Err: err}
+}
+
+// KindOf returns the kind of the error, false for unclassified errors
+func KindOf(err error) (Kind, bool) {
+	var classified *Error
+	if !errors.As(err, &classified) {
+		return 0, false
+	}
+	return classified.Kind, true
+}
+
+// ExitCode returns the exit code of the error, 0 without error
+func ExitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	kind, _ := KindOf(err)
+	switch kind {
+	case KindConfig:
+		return ExitConfig
+	case KindConnectivity:
+		return ExitConnectivity
+	case KindRuntime:
+		return ExitRuntime
+	default:
+		return ExitUnknown
+	}
+}
+
+// Describe explains the error to the user, with what to check for its kind
+func Describe(err error) string {
+	kind, _ := KindOf(err)
+	switch kind {
+	case KindConfig:
+		return fmt.Sprintf("Configuration error: %s\nCheck the configuration file, the environment variables and the flags, 'benchmark --help' lists them.", err)
+	case KindConnectivity:
+		return fmt.Sprintf("Connectivity error: %s\nCheck the node addresses are reachable from this machine and the nodes are running.", err)
+	case KindRuntime:
+		return fmt.Sprintf("Runtime error: %s\nCheck the logs for details.", err)
+	default:
+		return fmt.Sprintf("Error: %s", err)
+	}
+}
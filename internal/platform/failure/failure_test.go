@@ -0,0 +1,48 @@
+package failure
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGivenClassifiedErrorsWhenExitCodeThenCodeOfTheKind(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected int
+	}{
+		{"No error", nil, 0},
+		{"Unclassified", errors.New("unknown flag"), ExitUnknown},
+		{"Config", Config(errors.New("invalid port")), ExitConfig},
+		{"Connectivity", Connectivity(errors.New("connection refused")), ExitConnectivity},
+		{"Runtime", Runtime(errors.New("disk full")), ExitRuntime},
+		{"Joined", errors.Join(Connectivity(errors.New("connection refused")), errors.New("failed creating metric")), ExitConnectivity},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, ExitCode(test.err))
+		})
+	}
+}
+
+func TestGivenClassifiedErrorWhenClassifiedAgainThenKindKept(t *testing.T) {
+	cause := errors.New("connection refused")
+
+	err := Config(errors.Join(Connectivity(cause), errors.New("failed loading metrics")))
+
+	kind, ok := KindOf(err)
+	assert.True(t, ok)
+	assert.Equal(t, KindConnectivity, kind)
+	assert.ErrorIs(t, err, cause)
+	assert.Nil(t, Runtime(nil))
+}
+
+func TestGivenConfigErrorWhenDescribeThenKindAndHintNamed(t *testing.T) {
+	description := Describe(Config(errors.New("server port should be between 1 and 65535")))
+
+	assert.Contains(t, description, "Configuration error: server port should be between 1 and 65535")
+	assert.Contains(t, description, "--help")
+}
@@ -0,0 +1,270 @@
+package fleet
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/Harikakasimahanthi/benchmark-test/report"
+)
+
+// maxStartDelay bounds how far ahead a run may be scheduled, a run far in the future would keep the agent busy
+const maxStartDelay = time.Hour * 24
+
+// Launcher runs a benchmark which starts measuring at the time, right away when it is zero, for the duration and writes
+// its records as JSON report to the path
+type Launcher func(ctx context.Context, startAt time.Time, duration time.Duration, recordsPath string) error
+
+// Agent runs the benchmarks scheduled by a coordinator or periodically, one at a time. It keeps the records of the
+// runs until the coordinator collects them, periodic runs are pushed to an aggregation server.
+type Agent struct {
+	host    string
+	version string
+	dir     string
+	token   string
+	launch  Launcher
+	ctx     context.Context
+	runs    map[string]*RunStatus
+	busy    bool
+	mutex   sync.Mutex
+}
+
+// NewAgent creates an agent writing the records of its runs to the directory, requests must carry the token as bearer
+// token unless it is empty. The runs are stopped when the context is done. Agents listening on other interfaces than
+// the loopback one require a token, see CheckListen.
+func NewAgent(ctx context.Context, host, version, dir, token string, launch Launcher) *Agent {
+	return &Agent{
+		host:    host,
+		version: version,
+		dir:     dir,
+		token:   token,
+		launch:  launch,
+		ctx:     ctx,
+		runs:    make(map[string]*RunStatus),
+	}
+}
+
+// CheckListen fails when an agent or an aggregation server would listen on other interfaces than the loopback one
+// without a token, anyone reaching the machine could run benchmarks against its nodes or push runs otherwise
+func CheckListen(listen, token string) error {
+	if token != "" {
+		return nil
+	}
+	host, _, err := net.SplitHostPort(listen)
+	if err != nil {
+		return errors.Join(err, fmt.Errorf("invalid listen address '%s'", listen))
+	}
+	if host == "localhost" {
+		return nil
+	}
+	if ip := net.ParseIP(host); ip != nil && ip.IsLoopback() {
+		return nil
+	}
+	return fmt.Errorf("listening on '%s' requires a token, only the loopback interface is open to anyone without one", listen)
+}
+
+// Handler serves the agent API: 'GET /fleet/status', 'POST /fleet/runs' and 'GET /fleet/runs/{id}'
+func (a *Agent) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /fleet/status", authorized(a.token, a.handleStatus))
+	mux.HandleFunc("POST /fleet/runs", authorized(a.token, a.handleSchedule))
+	mux.HandleFunc("GET /fleet/runs/{id}", authorized(a.token, a.handleRun))
+	return mux
+}
+
+// authorized rejects requests which don't carry the token as bearer token, unless it is empty
+func authorized(token string, handler http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if token != "" && subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), []byte("Bearer "+token)) != 1 {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		handler(w, r)
+	}
+}
+
+func (a *Agent) handleStatus(w http.ResponseWriter, _ *http.Request) {
+	a.mutex.Lock()
+	status := AgentStatus{Host: a.host, Version: a.version, Time: time.Now(), Busy: a.busy}
+	a.mutex.Unlock()
+
+	writeJSON(w, http.StatusOK, status)
+}
+
+func (a *Agent) handleSchedule(w http.ResponseWriter, r *http.Request) {
+	var request RunRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, "invalid run request", http.StatusBadRequest)
+		return
+	}
+	duration, err := time.ParseDuration(request.Duration)
+	if err != nil || duration <= 0 {
+		http.Error(w, "the duration of the run should be positive", http.StatusBadRequest)
+		return
+	}
+	if request.StartAt.After(time.Now().Add(maxStartDelay)) {
+		http.Error(w, fmt.Sprintf("the run should start within %s", maxStartDelay), http.StatusBadRequest)
+		return
+	}
+
+	status, ok := a.reserve(request.StartAt)
+	if !ok {
+		http.Error(w, "the agent is running another benchmark", http.StatusConflict)
+		return
+	}
+
+	slog.With("run_id", status.ID).With("start_at", request.StartAt).With("duration", duration).Info("fleet run scheduled")
+	go a.run(status.ID, request.StartAt, duration)
+	writeJSON(w, http.StatusAccepted, status)
+}
+
+// RunEvery runs the benchmark every interval for the duration until the context is done, the first run starts right
+// away. Every result is pushed to the aggregation server, runs are skipped while a coordinator's run is ongoing.
+func (a *Agent) RunEvery(ctx context.Context, every, duration time.Duration, server *Client) {
+	ticker := time.NewTicker(every)
+	defer ticker.Stop()
+
+	for {
+		startedAt := time.Now()
+		status, ok := a.reserve(time.Time{})
+		if ok {
+			status = a.run(status.ID, status.StartAt, duration)
+			a.forget(status.ID)
+			result := Result{
+				FleetHost: report.FleetHost{Host: a.host, At: startedAt, Records: status.Records, Err: status.Error},
+				Version:   a.version,
+				Duration:  duration.String(),
+			}
+			if err := server.Push(ctx, result); err != nil {
+				slog.With("err", err.Error()).With("run_id", status.ID).Error("failed pushing the run to the aggregation server")
+			} else {
+				slog.With("run_id", status.ID).Info("run pushed to the aggregation server")
+			}
+		} else {
+			slog.Warn("skipping the periodic run, the agent is running another benchmark")
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			slog.Debug("periodic runs were stopped")
+			return
+		}
+	}
+}
+
+// reserve registers a run starting at the time unless the agent is busy
+func (a *Agent) reserve(startAt time.Time) (RunStatus, bool) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	if a.busy {
+		return RunStatus{}, false
+	}
+	status := &RunStatus{
+		ID:      strconv.FormatInt(time.Now().UnixNano(), 36),
+		Host:    a.host,
+		State:   StateScheduled,
+		StartAt: startAt,
+	}
+	a.runs[status.ID] = status
+	a.busy = true
+	return *status, true
+}
+
+// forget drops a run nobody collects from the agent
+func (a *Agent) forget(id string) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	delete(a.runs, id)
+	if err := os.Remove(a.recordsPath(id)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		slog.With("err", err.Error()).With("run_id", id).Warn("failed removing the records of the run")
+	}
+}
+
+func (a *Agent) recordsPath(id string) string {
+	return filepath.Join(a.dir, fmt.Sprintf("fleet-%s.json", id))
+}
+
+// run runs the benchmark of the reserved run and returns its final status
+func (a *Agent) run(id string, startAt time.Time, duration time.Duration) RunStatus {
+	path := a.recordsPath(id)
+	a.setState(id, StateRunning)
+	err := a.launch(a.ctx, startAt, duration, path)
+
+	var records []report.Record
+	if err == nil {
+		records, err = readRecords(path)
+	}
+
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	status := a.runs[id]
+	a.busy = false
+	if err != nil {
+		slog.With("err", err.Error()).With("run_id", id).Error("fleet run failed")
+		status.State, status.Error = StateFailed, err.Error()
+		return *status
+	}
+	slog.With("run_id", id).Info("fleet run finished")
+	status.State, status.Records = StateFinished, records
+	return *status
+}
+
+func (a *Agent) setState(id, state string) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	a.runs[id].State = state
+}
+
+func (a *Agent) handleRun(w http.ResponseWriter, r *http.Request) {
+	a.mutex.Lock()
+	status, ok := a.runs[r.PathValue("id")]
+	var response RunStatus
+	if ok {
+		response = *status
+	}
+	a.mutex.Unlock()
+
+	if !ok {
+		http.Error(w, "unknown run", http.StatusNotFound)
+		return
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+// readRecords reads the records of a JSON report
+func readRecords(path string) ([]report.Record, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Join(err, errors.New("failed reading the records of the run"))
+	}
+	var document struct {
+		Records []report.Record `json:"records"`
+	}
+	if err := json.Unmarshal(data, &document); err != nil {
+		return nil, errors.Join(err, errors.New("failed decoding the records of the run"))
+	}
+	return document.Records, nil
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		slog.With("err", err.Error()).Warn("failed writing fleet API response")
+	}
+}
@@ -0,0 +1,77 @@
+package fleet
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/metric"
+	"github.com/Harikakasimahanthi/benchmark-test/report"
+)
+
+func TestGivenScheduledRunWhenFinishedThenRecordsCollected(t *testing.T) {
+	release := make(chan struct{})
+	launch := func(ctx context.Context, startAt time.Time, duration time.Duration, recordsPath string) error {
+		<-release
+		data, err := json.Marshal(map[string]any{"records": []report.Record{{GroupName: metric.ConsensusGroup, MetricName: "Peers", Health: metric.Healthy}}})
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(recordsPath, data, 0o644)
+	}
+	server := httptest.NewServer(NewAgent(context.Background(), "node-1", "1.0", t.TempDir(), "secret", launch).Handler())
+	defer server.Close()
+	client := NewClient(server.URL, "secret")
+
+	run, err := client.Schedule(context.Background(), time.Now(), time.Minute)
+	require.NoError(t, err)
+
+	status, err := client.Status(context.Background())
+	require.NoError(t, err)
+	assert.True(t, status.Busy)
+	_, err = client.Schedule(context.Background(), time.Now(), time.Minute)
+	assert.Error(t, err, "a busy agent rejects runs")
+
+	close(release)
+	require.Eventually(t, func() bool {
+		run, err = client.Run(context.Background(), run.ID)
+		return err == nil && run.Done()
+	}, time.Second*5, time.Millisecond*10)
+	assert.Equal(t, StateFinished, run.State)
+	assert.Equal(t, "node-1", run.Host)
+	require.Len(t, run.Records, 1)
+	assert.Equal(t, "Peers", run.Records[0].MetricName)
+}
+
+func TestGivenWrongTokenWhenStatusThenUnauthorized(t *testing.T) {
+	server := httptest.NewServer(NewAgent(context.Background(), "node-1", "1.0", t.TempDir(), "secret", nil).Handler())
+	defer server.Close()
+
+	_, err := NewClient(server.URL, "guess").Status(context.Background())
+
+	assert.ErrorContains(t, err, "401")
+}
+
+func TestGivenListenAddressWhenCheckListenThenTokenRequiredBeyondLoopback(t *testing.T) {
+	assert.NoError(t, CheckListen("127.0.0.1:9100", ""))
+	assert.NoError(t, CheckListen("localhost:9100", ""))
+	assert.NoError(t, CheckListen("[::1]:9100", ""))
+	assert.NoError(t, CheckListen(":9100", "secret"))
+	assert.Error(t, CheckListen(":9100", ""))
+	assert.Error(t, CheckListen("192.168.1.10:9100", ""))
+}
+
+func TestGivenStartFarAheadWhenScheduleThenRejected(t *testing.T) {
+	server := httptest.NewServer(NewAgent(context.Background(), "node-1", "1.0", t.TempDir(), "secret", nil).Handler())
+	defer server.Close()
+
+	_, err := NewClient(server.URL, "secret").Schedule(context.Background(), time.Now().Add(maxStartDelay+time.Hour), time.Minute)
+
+	assert.ErrorContains(t, err, "400")
+}
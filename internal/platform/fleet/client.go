@@ -0,0 +1,89 @@
+package fleet
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Client calls the API of an agent, as coordinator, or the one of the aggregation server, as agent
+type Client struct {
+	url    string
+	token  string
+	client *http.Client
+}
+
+func NewClient(url, token string) *Client {
+	return &Client{
+		url:    strings.TrimSuffix(url, "/"),
+		token:  token,
+		client: &http.Client{Timeout: time.Second * 30},
+	}
+}
+
+// URL returns the address of the agent or the server
+func (c *Client) URL() string {
+	return c.url
+}
+
+func (c *Client) Status(ctx context.Context) (AgentStatus, error) {
+	var status AgentStatus
+	err := c.do(ctx, http.MethodGet, "/fleet/status", nil, &status)
+	return status, err
+}
+
+// Schedule schedules a run starting at the time for the duration
+func (c *Client) Schedule(ctx context.Context, startAt time.Time, duration time.Duration) (RunStatus, error) {
+	var status RunStatus
+	err := c.do(ctx, http.MethodPost, "/fleet/runs", RunRequest{StartAt: startAt, Duration: duration.String()}, &status)
+	return status, err
+}
+
+func (c *Client) Run(ctx context.Context, id string) (RunStatus, error) {
+	var status RunStatus
+	err := c.do(ctx, http.MethodGet, "/fleet/runs/"+id, nil, &status)
+	return status, err
+}
+
+// Push sends the result of a run to the aggregation server
+func (c *Client) Push(ctx context.Context, result Result) error {
+	var stored struct{}
+	return c.do(ctx, http.MethodPost, "/fleet/results", result, &stored)
+}
+
+func (c *Client) do(ctx context.Context, method, path string, body, result any) error {
+	var reader io.Reader
+	if body != nil {
+		encoded, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(encoded)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.url+path, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	res, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		message, _ := io.ReadAll(io.LimitReader(res.Body, 512))
+		return fmt.Errorf("%s responded with status %d: %s", c.url, res.StatusCode, strings.TrimSpace(string(message)))
+	}
+	return json.NewDecoder(res.Body).Decode(result)
+}
@@ -0,0 +1,86 @@
+package fleet
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/Harikakasimahanthi/benchmark-test/report"
+)
+
+const (
+	// maxClockSkew is the offset between the clocks of the coordinator and an agent above which their measurement
+	// periods no longer line up, the clocks should be synchronized with NTP
+	maxClockSkew = time.Second
+	pollInterval = time.Second * 10
+)
+
+// Coordinate schedules a run of the duration on every agent, all starting once the lead time passed so they measure
+// the same period, and collects their records once the runs ended. Agents which fail are reported with their error.
+func Coordinate(ctx context.Context, agents []*Client, duration, lead time.Duration) []report.FleetHost {
+	startAt := time.Now().Add(lead).Truncate(time.Second)
+	hosts := make([]report.FleetHost, len(agents))
+
+	var wg sync.WaitGroup
+	for i, agent := range agents {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			hosts[i] = coordinate(ctx, agent, startAt, duration)
+		}()
+	}
+	wg.Wait()
+	return hosts
+}
+
+func coordinate(ctx context.Context, agent *Client, startAt time.Time, duration time.Duration) report.FleetHost {
+	host := report.FleetHost{Host: agent.URL()}
+	log := slog.With("agent", agent.URL())
+
+	sent := time.Now()
+	status, err := agent.Status(ctx)
+	if err != nil {
+		host.Err = err.Error()
+		return host
+	}
+	host.Host = status.Host
+	// The agent read its clock about halfway through the request
+	if skew := status.Time.Sub(sent.Add(time.Since(sent) / 2)); skew.Abs() > maxClockSkew {
+		log.With("skew", skew).Warn("the clock of the agent is off, its measurements do not line up with the other agents")
+	}
+	if status.Busy {
+		host.Err = "the agent is running another benchmark"
+		return host
+	}
+
+	run, err := agent.Schedule(ctx, startAt, duration)
+	if err != nil {
+		host.Err = err.Error()
+		return host
+	}
+	log.With("run_id", run.ID).With("start_at", startAt).Info("fleet run scheduled")
+
+	wait := time.Until(startAt.Add(duration))
+	for {
+		select {
+		case <-time.After(wait):
+		case <-ctx.Done():
+			host.Err = errors.Join(ctx.Err(), errors.New("stopped waiting for the run")).Error()
+			return host
+		}
+		wait = pollInterval
+
+		run, err = agent.Run(ctx, run.ID)
+		if err != nil {
+			log.With("err", err.Error()).Warn("failed polling the fleet run")
+			continue
+		}
+		if !run.Done() {
+			continue
+		}
+		host.Records, host.Err = run.Records, run.Error
+		return host
+	}
+}
@@ -0,0 +1,60 @@
+package fleet
+
+import (
+	"time"
+
+	"github.com/Harikakasimahanthi/benchmark-test/report"
+)
+
+const (
+	StateScheduled = "scheduled"
+	StateRunning   = "running"
+	StateFinished  = "finished"
+	StateFailed    = "failed"
+)
+
+type (
+	// AgentStatus tells the coordinator who the agent is, whether it is free and what time its clock shows
+	AgentStatus struct {
+		Host    string    `json:"host"`
+		Version string    `json:"version"`
+		Time    time.Time `json:"time"`
+		Busy    bool      `json:"busy"`
+	}
+
+	// RunRequest schedules a run on an agent, every agent of a distributed run gets the same start time
+	RunRequest struct {
+		StartAt  time.Time `json:"start_at"`
+		Duration string    `json:"duration"`
+	}
+
+	// Result is a run an agent pushes to the aggregation server
+	Result struct {
+		report.FleetHost
+		Version  string `json:"version"`
+		Duration string `json:"duration"`
+	}
+
+	// HostSummary is the latest run of a host as listed by the aggregation server
+	HostSummary struct {
+		Host    string    `json:"host"`
+		Version string    `json:"version"`
+		At      time.Time `json:"at"`
+		report.FleetSummary
+	}
+
+	// RunStatus is the state of a run scheduled on an agent, the records are set once it finished
+	RunStatus struct {
+		ID      string          `json:"id"`
+		Host    string          `json:"host"`
+		State   string          `json:"state"`
+		StartAt time.Time       `json:"start_at"`
+		Error   string          `json:"error,omitempty"`
+		Records []report.Record `json:"records,omitempty"`
+	}
+)
+
+// Done tells whether the run ended, successfully or not
+func (s RunStatus) Done() bool {
+	return s.State == StateFinished || s.State == StateFailed
+}
@@ -0,0 +1,192 @@
+package fleet
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"sync"
+
+	"github.com/Harikakasimahanthi/benchmark-test/report"
+)
+
+const (
+	// historyLimit is the number of runs the aggregation server keeps per host
+	historyLimit = 100
+	// hostLimit is the number of hosts the aggregation server keeps runs of, pushes of further hosts are rejected
+	hostLimit = 1000
+	// maxResultSize bounds the body of a pushed run, the records of a run are far smaller
+	maxResultSize = 8 << 20
+)
+
+var (
+	unsafeFileName = regexp.MustCompile(`[^A-Za-z0-9._-]`)
+
+	errTooManyHosts = fmt.Errorf("the server keeps the runs of %d hosts at most", hostLimit)
+)
+
+// Server aggregates the runs pushed by the agents. It keeps the latest runs of every host, in the directory when one
+// is set so they survive restarts, and serves them as fleet dashboard and API.
+type Server struct {
+	dir   string
+	token string
+	hosts map[string][]Result
+	mutex sync.Mutex
+}
+
+// NewServer creates an aggregation server loading the runs kept in the directory, agents must push with the token as
+// bearer token unless it is empty. The dashboard and the API reading the runs are open. Servers listening on other
+// interfaces than the loopback one require a token, see CheckListen.
+func NewServer(dir, token string) (*Server, error) {
+	s := &Server{
+		dir:   dir,
+		token: token,
+		hosts: make(map[string][]Result),
+	}
+	if dir == "" {
+		return s, nil
+	}
+
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, errors.Join(err, fmt.Errorf("failed reading the runs in %s", path))
+		}
+		var results []Result
+		if err := json.Unmarshal(data, &results); err != nil {
+			return nil, errors.Join(err, fmt.Errorf("failed decoding the runs in %s", path))
+		}
+		if len(results) != 0 {
+			s.hosts[results[0].Host] = results
+		}
+	}
+	return s, nil
+}
+
+// Handler serves the dashboard on 'GET /' and the API: 'POST /fleet/results', 'GET /fleet/hosts' and
+// 'GET /fleet/hosts/{host}'
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /{$}", s.handleDashboard)
+	mux.HandleFunc("POST /fleet/results", authorized(s.token, s.handlePush))
+	mux.HandleFunc("GET /fleet/hosts", s.handleHosts)
+	mux.HandleFunc("GET /fleet/hosts/{host}", s.handleHost)
+	return mux
+}
+
+func (s *Server) handlePush(w http.ResponseWriter, r *http.Request) {
+	var result Result
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxResultSize)).Decode(&result); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			http.Error(w, "result too large", http.StatusRequestEntityTooLarge)
+			return
+		}
+		http.Error(w, "invalid result", http.StatusBadRequest)
+		return
+	}
+	if result.Host == "" {
+		http.Error(w, "the result should name its host", http.StatusBadRequest)
+		return
+	}
+
+	if err := s.add(result); errors.Is(err, errTooManyHosts) {
+		http.Error(w, err.Error(), http.StatusInsufficientStorage)
+		return
+	} else if err != nil {
+		slog.With("err", err.Error()).With("host", result.Host).Error("failed storing the pushed run")
+		http.Error(w, "failed storing the result", http.StatusInternalServerError)
+		return
+	}
+	slog.With("host", result.Host).With("at", result.At).Info("run pushed by agent")
+	writeJSON(w, http.StatusCreated, struct{}{})
+}
+
+// add appends the run to the history of its host, dropping the oldest ones beyond the limit. Runs of new hosts are
+// rejected once the server keeps the runs of hostLimit hosts.
+func (s *Server) add(result Result) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if _, ok := s.hosts[result.Host]; !ok && len(s.hosts) >= hostLimit {
+		return errTooManyHosts
+	}
+
+	history := append(s.hosts[result.Host], result)
+	sort.SliceStable(history, func(i, j int) bool { return history[i].At.Before(history[j].At) })
+	if len(history) > historyLimit {
+		history = history[len(history)-historyLimit:]
+	}
+	s.hosts[result.Host] = history
+
+	if s.dir == "" {
+		return nil
+	}
+	data, err := json.Marshal(history)
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(s.dir, unsafeFileName.ReplaceAllString(result.Host, "_")+".json")
+	return os.WriteFile(path, data, 0o644)
+}
+
+// Latest returns the latest run of every host, sorted by host
+func (s *Server) Latest() []Result {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	latest := make([]Result, 0, len(s.hosts))
+	for _, history := range s.hosts {
+		latest = append(latest, history[len(history)-1])
+	}
+	sort.Slice(latest, func(i, j int) bool { return latest[i].Host < latest[j].Host })
+	return latest
+}
+
+func (s *Server) handleDashboard(w http.ResponseWriter, _ *http.Request) {
+	latest := s.Latest()
+	hosts := make([]report.FleetHost, 0, len(latest))
+	for _, result := range latest {
+		hosts = append(hosts, result.FleetHost)
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := report.RenderFleetDashboard(w, hosts); err != nil {
+		slog.With("err", err.Error()).Warn("failed writing the fleet dashboard")
+	}
+}
+
+func (s *Server) handleHosts(w http.ResponseWriter, _ *http.Request) {
+	latest := s.Latest()
+	summaries := make([]HostSummary, 0, len(latest))
+	for _, result := range latest {
+		summaries = append(summaries, HostSummary{
+			Host:         result.Host,
+			Version:      result.Version,
+			At:           result.At,
+			FleetSummary: result.Summarize(),
+		})
+	}
+	writeJSON(w, http.StatusOK, summaries)
+}
+
+func (s *Server) handleHost(w http.ResponseWriter, r *http.Request) {
+	s.mutex.Lock()
+	history := append([]Result(nil), s.hosts[r.PathValue("host")]...)
+	s.mutex.Unlock()
+
+	if len(history) == 0 {
+		http.Error(w, "unknown host", http.StatusNotFound)
+		return
+	}
+	writeJSON(w, http.StatusOK, history)
+}
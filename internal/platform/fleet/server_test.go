@@ -0,0 +1,129 @@
+package fleet
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/metric"
+	"github.com/Harikakasimahanthi/benchmark-test/report"
+)
+
+func TestGivenPushedRunsWhenListingHostsThenLatestRunOfEveryHost(t *testing.T) {
+	aggregation, err := NewServer(t.TempDir(), "secret")
+	require.NoError(t, err)
+	server := httptest.NewServer(aggregation.Handler())
+	defer server.Close()
+	client := NewClient(server.URL, "secret")
+
+	now := time.Now().Truncate(time.Second)
+	require.NoError(t, client.Push(context.Background(), Result{FleetHost: report.FleetHost{Host: "node-1", At: now.Add(-time.Hour), Records: []report.Record{
+		{GroupName: metric.ConsensusGroup, MetricName: "Peers", Health: metric.Unhealthy, Severity: map[string]metric.SeverityLevel{"Peers": metric.SeverityHigh}},
+	}}}))
+	require.NoError(t, client.Push(context.Background(), Result{FleetHost: report.FleetHost{Host: "node-1", At: now, Records: []report.Record{
+		{GroupName: metric.ConsensusGroup, MetricName: "Peers", Health: metric.Healthy},
+	}}}))
+	require.NoError(t, client.Push(context.Background(), Result{FleetHost: report.FleetHost{Host: "node-2", At: now, Err: "node unreachable"}}))
+
+	res, err := http.Get(server.URL + "/fleet/hosts")
+	require.NoError(t, err)
+	defer res.Body.Close()
+	var hosts []HostSummary
+	require.NoError(t, json.NewDecoder(res.Body).Decode(&hosts))
+
+	require.Len(t, hosts, 2)
+	assert.Equal(t, "node-1", hosts[0].Host)
+	assert.True(t, now.Equal(hosts[0].At))
+	assert.Equal(t, 1, hosts[0].Healthy)
+	assert.Equal(t, 0, hosts[0].Score)
+	assert.Equal(t, "Failed: node unreachable", hosts[1].Verdict)
+}
+
+func TestGivenKeptRunsWhenServerRestartsThenHistoryLoaded(t *testing.T) {
+	dir := t.TempDir()
+	first, err := NewServer(dir, "")
+	require.NoError(t, err)
+	require.NoError(t, first.add(Result{FleetHost: report.FleetHost{Host: "node/1", At: time.Now()}, Version: "1.0"}))
+
+	restarted, err := NewServer(dir, "")
+	require.NoError(t, err)
+
+	latest := restarted.Latest()
+	require.Len(t, latest, 1)
+	assert.Equal(t, "node/1", latest[0].Host)
+	assert.Equal(t, "1.0", latest[0].Version)
+}
+
+func TestGivenWrongTokenWhenPushingThenRejected(t *testing.T) {
+	aggregation, err := NewServer("", "secret")
+	require.NoError(t, err)
+	server := httptest.NewServer(aggregation.Handler())
+	defer server.Close()
+
+	err = NewClient(server.URL, "guess").Push(context.Background(), Result{FleetHost: report.FleetHost{Host: "node-1"}})
+
+	assert.ErrorContains(t, err, "401")
+	assert.Empty(t, aggregation.Latest())
+}
+
+func TestGivenOversizedPushOrTooManyHostsWhenPushingThenRejected(t *testing.T) {
+	aggregation, err := NewServer("", "")
+	require.NoError(t, err)
+	server := httptest.NewServer(aggregation.Handler())
+	defer server.Close()
+	client := NewClient(server.URL, "")
+	for i := range hostLimit {
+		aggregation.hosts[fmt.Sprintf("node-%d", i)] = []Result{{FleetHost: report.FleetHost{Host: fmt.Sprintf("node-%d", i)}}}
+	}
+
+	oversized := client.Push(context.Background(), Result{FleetHost: report.FleetHost{Host: "node-0", Err: strings.Repeat("x", maxResultSize)}})
+	newHost := client.Push(context.Background(), Result{FleetHost: report.FleetHost{Host: "node-new"}})
+	knownHost := client.Push(context.Background(), Result{FleetHost: report.FleetHost{Host: "node-0", At: time.Now()}})
+
+	assert.ErrorContains(t, oversized, "413")
+	assert.ErrorContains(t, newHost, "507")
+	assert.NoError(t, knownHost, "known hosts keep pushing")
+	assert.Len(t, aggregation.Latest(), hostLimit)
+}
+
+func TestGivenPeriodicAgentWhenRunFinishedThenPushedToServer(t *testing.T) {
+	aggregation, err := NewServer("", "")
+	require.NoError(t, err)
+	server := httptest.NewServer(aggregation.Handler())
+	defer server.Close()
+
+	launch := func(ctx context.Context, startAt time.Time, duration time.Duration, recordsPath string) error {
+		data, err := json.Marshal(map[string]any{"records": []report.Record{{GroupName: metric.ConsensusGroup, MetricName: "Peers", Health: metric.Healthy}}})
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(recordsPath, data, 0o644)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	agent := NewAgent(ctx, "node-1", "1.0", t.TempDir(), "", launch)
+	go agent.RunEvery(ctx, time.Hour, time.Minute, NewClient(server.URL, ""))
+
+	require.Eventually(t, func() bool { return len(aggregation.Latest()) == 1 }, time.Second*5, time.Millisecond*10)
+	latest := aggregation.Latest()[0]
+	assert.Equal(t, "node-1", latest.Host)
+	assert.Equal(t, "1m0s", latest.Duration)
+	require.Len(t, latest.Records, 1)
+
+	res, err := http.Get(server.URL + "/")
+	require.NoError(t, err)
+	defer res.Body.Close()
+	page, err := io.ReadAll(res.Body)
+	require.NoError(t, err)
+	assert.Contains(t, string(page), "node-1")
+}
@@ -0,0 +1,110 @@
+package forks
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/clientinfo"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/network"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/update"
+	"github.com/Harikakasimahanthi/benchmark-test/report"
+)
+
+const (
+	ConsensusLayer = "consensus"
+	ExecutionLayer = "execution"
+)
+
+// Fork is a network upgrade of a network together with the first client releases following the chain after it
+type Fork struct {
+	Name    string
+	Network network.Name
+	Epoch   uint64
+	// Version is the fork version of the beacon chain from the epoch on
+	Version string
+	// Minimum are the first releases of the clients supporting the fork, without prefix or suffix
+	Minimum map[clientinfo.Client]string
+}
+
+// fulu are the first releases supporting Fusaka (Fulu on the beacon chain, Osaka on the execution layer) on mainnet,
+// they support the testnets as well
+var fulu = map[clientinfo.Client]string{
+	clientinfo.Lighthouse: "8.0.0",
+	clientinfo.Prysm:      "7.0.0",
+	clientinfo.Teku:       "25.11.1",
+	clientinfo.Nimbus:     "25.11.0",
+	clientinfo.Lodestar:   "1.36.0",
+	clientinfo.Grandine:   "2.0.0",
+	clientinfo.Geth:       "1.16.7",
+	clientinfo.Nethermind: "1.35.0",
+	clientinfo.Besu:       "25.11.0",
+	clientinfo.Erigon:     "3.2.2",
+	clientinfo.Reth:       "1.9.0",
+}
+
+// Schedule are the forks known to this release, it is extended when the client teams announce the releases of a fork
+var Schedule = []Fork{
+	{Name: "Fulu", Network: network.Mainnet, Epoch: 411392, Version: "0x06000000", Minimum: fulu},
+	{Name: "Fulu", Network: network.Sepolia, Epoch: 272640, Version: "0x90000075", Minimum: fulu},
+	{Name: "Fulu", Network: network.Holesky, Epoch: 165120, Version: "0x06017000", Minimum: fulu},
+	{Name: "Fulu", Network: network.Hoodi, Epoch: 50688, Version: "0x70000910", Minimum: fulu},
+}
+
+// Check compares the releases of the detected clients and the fork schedule of the beacon node with the forks of the
+// network. The schedule maps the fork versions advertised by the node to their epochs, a nil schedule isn't checked.
+func Check(name network.Name, forks []Fork, detection clientinfo.Detection, schedule map[string]uint64, currentEpoch uint64) []report.ForkReadiness {
+	var readiness []report.ForkReadiness
+	for _, fork := range forks {
+		if fork.Network != name {
+			continue
+		}
+
+		consensus := checkRelease(fork, ConsensusLayer, detection.Consensus.Client, detection.ConsensusVersion, currentEpoch)
+		if epoch, ok := schedule[strings.ToLower(fork.Version)]; schedule != nil && (!ok || epoch != fork.Epoch) {
+			consensus.Status = report.ForkUnscheduled
+			consensus.Detail = fmt.Sprintf("fork version %s at epoch %d is missing from the fork schedule of the node", fork.Version, fork.Epoch)
+		}
+		readiness = append(readiness,
+			consensus,
+			checkRelease(fork, ExecutionLayer, detection.Execution.Client, detection.ExecutionVersion, currentEpoch))
+	}
+	return readiness
+}
+
+func checkRelease(fork Fork, layer string, client clientinfo.Client, fullVersion string, currentEpoch uint64) report.ForkReadiness {
+	version := releaseOf(fullVersion)
+	readiness := report.ForkReadiness{
+		Fork:     fork.Name,
+		Epoch:    fork.Epoch,
+		Upcoming: fork.Epoch > currentEpoch,
+		Layer:    layer,
+		Client:   string(client),
+		Version:  version,
+		Minimum:  fork.Minimum[client],
+		Status:   report.ForkReady,
+	}
+	switch {
+	case readiness.Minimum == "":
+		readiness.Status, readiness.Detail = report.ForkUnknown, "no known release supporting the fork"
+	case version == "":
+		readiness.Status, readiness.Detail = report.ForkUnknown, fmt.Sprintf("release of '%s' is unknown", fullVersion)
+	case update.Newer(readiness.Minimum, version):
+		readiness.Status, readiness.Detail = report.ForkOutdated, fmt.Sprintf("stops following the chain at epoch %d", fork.Epoch)
+	}
+	return readiness
+}
+
+// releaseOf returns the release of a client version, e.g. 'v5.1.3-3058b96' of 'Lighthouse/v5.1.3-3058b96/x86_64-linux'.
+// Versions whose release isn't numbered, e.g. development builds, have none.
+func releaseOf(version string) string {
+	parts := strings.Split(version, "/")
+	if len(parts) < 2 {
+		return ""
+	}
+	release := parts[1]
+	if trimmed := strings.TrimPrefix(release, "v"); trimmed == "" || !unicode.IsDigit(rune(trimmed[0])) {
+		return ""
+	}
+	return release
+}
@@ -0,0 +1,60 @@
+package forks
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/clientinfo"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/network"
+	"github.com/Harikakasimahanthi/benchmark-test/report"
+)
+
+var testFork = Fork{
+	Name:    "Gloas",
+	Network: network.Mainnet,
+	Epoch:   500000,
+	Version: "0x07000000",
+	Minimum: map[clientinfo.Client]string{clientinfo.Lighthouse: "9.0.0", clientinfo.Geth: "1.17.0"},
+}
+
+func TestGivenOutdatedClientsWhenCheckThenNotReady(t *testing.T) {
+	detection := clientinfo.Detection{
+		Consensus:        clientinfo.AdapterOf(clientinfo.Lighthouse),
+		ConsensusVersion: "Lighthouse/v8.1.0-3058b96/x86_64-linux",
+		Execution:        clientinfo.AdapterOf(clientinfo.Geth),
+		ExecutionVersion: "Geth/v1.17.0-stable-2bd6bd01/linux-amd64/go1.24.1",
+	}
+
+	readiness := Check(network.Mainnet, []Fork{testFork}, detection, map[string]uint64{"0x07000000": 500000}, 400000)
+
+	require.Len(t, readiness, 2)
+	assert.Equal(t, report.ForkOutdated, readiness[0].Status)
+	assert.Equal(t, "v8.1.0-3058b96", readiness[0].Version)
+	assert.True(t, readiness[0].Upcoming)
+	assert.Equal(t, report.ForkReady, readiness[1].Status)
+}
+
+func TestGivenScheduleWithoutForkWhenCheckThenUnscheduled(t *testing.T) {
+	detection := clientinfo.Detection{
+		Consensus:        clientinfo.AdapterOf(clientinfo.Lighthouse),
+		ConsensusVersion: "Lighthouse/v9.0.0/x86_64-linux",
+	}
+
+	readiness := Check(network.Mainnet, []Fork{testFork}, detection, map[string]uint64{"0x06000000": 411392}, 400000)
+
+	require.Len(t, readiness, 2)
+	assert.Equal(t, report.ForkUnscheduled, readiness[0].Status)
+	assert.Equal(t, report.ForkUnknown, readiness[1].Status)
+}
+
+func TestGivenOtherNetworkWhenCheckThenNothingChecked(t *testing.T) {
+	assert.Empty(t, Check(network.Gnosis, []Fork{testFork}, clientinfo.Detection{}, nil, 0))
+}
+
+func TestGivenDevelopmentBuildWhenReleaseOfThenNone(t *testing.T) {
+	assert.Equal(t, "3.2.2", releaseOf("erigon/3.2.2/linux-amd64/go1.24.1"))
+	assert.Empty(t, releaseOf("Geth/unstable/linux-amd64"))
+	assert.Empty(t, releaseOf("Geth"))
+}
@@ -0,0 +1,204 @@
+package format
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// ValueWidth is the fixed width values are padded to, so columns of the report line up
+const ValueWidth = 9
+
+type Locale struct {
+	DecimalSeparator string
+	GroupSeparator   string
+}
+
+var (
+	// Locales are named by their BCP 47 tags in lower case, they are looked up case-insensitively
+	Locales = map[string]Locale{
+		"en":    {DecimalSeparator: ".", GroupSeparator: ","},
+		"de":    {DecimalSeparator: ",", GroupSeparator: "."},
+		"fr":    {DecimalSeparator: ",", GroupSeparator: " "},
+		"de-ch": {DecimalSeparator: ".", GroupSeparator: "'"},
+		// raw keeps numbers machine readable, without grouping
+		"raw": {DecimalSeparator: ".", GroupSeparator: ""},
+	}
+
+	current = Locales["en"]
+	// timezone of the timestamps of the report, local time unless configured otherwise
+	timezone = time.Local
+	// durationFormat is one of DurationFormats
+	durationFormat = DurationScaled
+
+	byteUnits = []string{"B", "KB", "MB", "GB", "TB", "PB"}
+)
+
+const (
+	// DurationScaled scales durations to the most readable unit, e.g. '850.25ms' or '1.20s'
+	DurationScaled = "scaled"
+	// DurationSeconds and DurationMilliseconds keep a single unit, so durations compare at a glance and parse easily
+	DurationSeconds      = "seconds"
+	DurationMilliseconds = "milliseconds"
+
+	// timestampLayout keeps the offset, so timestamps are unambiguous whatever the configured timezone
+	timestampLayout = "2006-01-02 15:04:05 Z07:00"
+)
+
+// SetLocale selects the locale used for number formatting
+func SetLocale(name string) error {
+	if name == "" {
+		return nil
+	}
+	locale, ok := Locales[strings.ToLower(name)]
+	if !ok {
+		return fmt.Errorf("locale '%s' is not supported", name)
+	}
+	current = locale
+	return nil
+}
+
+// SetTimezone selects the timezone of the timestamps, 'local', 'utc' or a name of the IANA database, e.g. 'Europe/Berlin'
+func SetTimezone(name string) error {
+	switch strings.ToLower(name) {
+	case "", "local":
+		timezone = time.Local
+	case "utc":
+		timezone = time.UTC
+	default:
+		location, err := time.LoadLocation(name)
+		if err != nil {
+			return fmt.Errorf("timezone '%s' is not supported: %w", name, err)
+		}
+		timezone = location
+	}
+	return nil
+}
+
+// SetDurationFormat selects how durations are formatted, one of 'scaled', 'seconds' or 'milliseconds'
+func SetDurationFormat(name string) error {
+	switch strings.ToLower(name) {
+	case "":
+		durationFormat = DurationScaled
+	case DurationScaled, DurationSeconds, DurationMilliseconds:
+		durationFormat = strings.ToLower(name)
+	default:
+		return fmt.Errorf("duration format '%s' should be one of '%s', '%s' or '%s'", name, DurationScaled, DurationSeconds, DurationMilliseconds)
+	}
+	return nil
+}
+
+// Timestamp formats the time with its date and offset in the configured timezone, e.g. '2024-05-01 14:03:12 Z'
+func Timestamp(t time.Time) string {
+	return t.In(timezone).Format(timestampLayout)
+}
+
+// Clock formats the time of day in the configured timezone, e.g. '14:03:12'
+func Clock(t time.Time) string {
+	return t.In(timezone).Format(time.TimeOnly)
+}
+
+// Number formats the value with the passed number of decimals using the separators of the current locale
+func Number(value float64, decimals int) string {
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return strconv.FormatFloat(value, 'f', decimals, 64)
+	}
+
+	formatted := strconv.FormatFloat(math.Abs(value), 'f', decimals, 64)
+	integer, fraction, _ := strings.Cut(formatted, ".")
+
+	var builder strings.Builder
+	if value < 0 && strings.Trim(formatted, "0.") != "" {
+		builder.WriteString("-")
+	}
+	for i, digit := range integer {
+		if i != 0 && (len(integer)-i)%3 == 0 {
+			builder.WriteString(current.GroupSeparator)
+		}
+		builder.WriteRune(digit)
+	}
+	if fraction != "" {
+		builder.WriteString(current.DecimalSeparator)
+		builder.WriteString(fraction)
+	}
+
+	return builder.String()
+}
+
+// Duration formats the duration in the configured format, by default scaled to the most readable unit, e.g.
+// '850.25ms' or '1.20s'
+func Duration(d time.Duration) string {
+	switch durationFormat {
+	case DurationSeconds:
+		return Number(d.Seconds(), 3) + "s"
+	case DurationMilliseconds:
+		return Number(float64(d)/float64(time.Millisecond), 2) + "ms"
+	}
+
+	abs := d
+	if abs < 0 {
+		abs = -abs
+	}
+	switch {
+	case abs < time.Microsecond:
+		return fmt.Sprintf("%dns", d.Nanoseconds())
+	case abs < time.Millisecond:
+		return Number(float64(d)/float64(time.Microsecond), 2) + "µs"
+	case abs < time.Second:
+		return Number(float64(d)/float64(time.Millisecond), 2) + "ms"
+	case abs < time.Minute:
+		return Number(d.Seconds(), 2) + "s"
+	case abs < time.Hour:
+		return Number(d.Minutes(), 2) + "m"
+	default:
+		return Number(d.Hours(), 2) + "h"
+	}
+}
+
+// Bytes formats the byte count scaled to the most readable binary unit, e.g. '15.62GB'
+func Bytes(bytes float64) string {
+	unit := 0
+	for math.Abs(bytes) >= 1024 && unit < len(byteUnits)-1 {
+		bytes /= 1024
+		unit++
+	}
+	if unit == 0 {
+		return Number(bytes, 0) + byteUnits[unit]
+	}
+	return Number(bytes, 2) + byteUnits[unit]
+}
+
+// Value formats any measurement value according to its type
+func Value(value any) string {
+	switch v := value.(type) {
+	case time.Duration:
+		return Duration(v)
+	case float32:
+		return Number(float64(v), 2)
+	case float64:
+		return Number(v, 2)
+	case int:
+		return Number(float64(v), 0)
+	case int64:
+		return Number(float64(v), 0)
+	case uint16:
+		return Number(float64(v), 0)
+	case uint32:
+		return Number(float64(v), 0)
+	case uint64:
+		return Number(float64(v), 0)
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+// Pad left pads the value with spaces up to the passed width
+func Pad(value string, width int) string {
+	if length := utf8.RuneCountInString(value); length < width {
+		return strings.Repeat(" ", width-length) + value
+	}
+	return value
+}
@@ -0,0 +1,72 @@
+package format
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGivenValuesWhenFormatThenScalesUnits(t *testing.T) {
+	tests := []struct {
+		name     string
+		actual   string
+		expected string
+	}{
+		{name: "Nanoseconds", actual: Duration(500), expected: "500ns"},
+		{name: "Milliseconds", actual: Duration(1500 * time.Microsecond), expected: "1.50ms"},
+		{name: "Seconds", actual: Duration(2500 * time.Millisecond), expected: "2.50s"},
+		{name: "Minutes", actual: Duration(90 * time.Second), expected: "1.50m"},
+		{name: "Bytes", actual: Bytes(512), expected: "512B"},
+		{name: "Megabytes", actual: Bytes(1.5 * 1024 * 1024), expected: "1.50MB"},
+		{name: "Gigabytes", actual: Bytes(16 * 1024 * 1024 * 1024), expected: "16.00GB"},
+		{name: "Grouped number", actual: Number(1234567.891, 2), expected: "1,234,567.89"},
+		{name: "Negative number", actual: Number(-1234, 0), expected: "-1,234"},
+		{name: "Padded value", actual: Pad("1.50ms", ValueWidth), expected: "   1.50ms"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.actual)
+		})
+	}
+}
+
+func TestGivenLocaleWhenFormatNumberThenUsesLocaleSeparators(t *testing.T) {
+	defer func() { current = Locales["en"] }()
+
+	assert.NoError(t, SetLocale("de"))
+	assert.Equal(t, "1.234.567,89", Number(1234567.891, 2))
+
+	assert.NoError(t, SetLocale("de-CH"))
+	assert.Equal(t, "1'234'567.89", Number(1234567.891, 2))
+
+	assert.Error(t, SetLocale("xx"))
+	assert.Error(t, SetLocale("ch"))
+}
+
+func TestGivenTimezoneWhenFormatTimestampThenUsesTimezone(t *testing.T) {
+	defer func() { timezone = time.Local }()
+	at := time.Date(2024, 5, 1, 12, 3, 12, 0, time.UTC)
+
+	assert.NoError(t, SetTimezone("utc"))
+	assert.Equal(t, "2024-05-01 12:03:12 Z", Timestamp(at))
+
+	timezone = time.FixedZone("CEST", 2*60*60)
+	assert.Equal(t, "2024-05-01 14:03:12 +02:00", Timestamp(at))
+	assert.Equal(t, "14:03:12", Clock(at))
+
+	assert.Error(t, SetTimezone("Mars/Olympus"))
+}
+
+func TestGivenDurationFormatWhenFormatDurationThenKeepsSingleUnit(t *testing.T) {
+	defer func() { durationFormat = DurationScaled }()
+
+	assert.NoError(t, SetDurationFormat("seconds"))
+	assert.Equal(t, "0.850s", Duration(850*time.Millisecond))
+
+	assert.NoError(t, SetDurationFormat("milliseconds"))
+	assert.Equal(t, "1,200.00ms", Duration(1200*time.Millisecond))
+
+	assert.Error(t, SetDurationFormat("weeks"))
+}
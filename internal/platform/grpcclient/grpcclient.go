@@ -0,0 +1,201 @@
+package grpcclient
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/http2"
+	"golang.org/x/net/proxy"
+
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/breaker"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/httpclient"
+)
+
+// Status codes of gRPC responses the callers tell apart
+const (
+	codeOK            = 0
+	codeUnimplemented = 12
+)
+
+// maxMessageSize bounds the responses, the default of the gRPC servers
+const maxMessageSize = 4 << 20
+
+// ErrUnimplemented is returned for methods the server doesn't implement, e.g. of newer API versions
+var ErrUnimplemented = errors.New("gRPC method is not implemented by the server")
+
+// Client invokes unary methods of a gRPC server. The messages are encoded by the callers, which keeps the client free
+// of generated code for the few methods the collectors need. The requests go through the transports of the shared HTTP
+// client, so they get the headers, audit, recording and circuit breaker of the endpoint like the other requests.
+type Client struct {
+	target string
+	client *http.Client
+}
+
+// Target returns the URL of the server at the address, 'http://host:port' for 'host:port' and 'https://host:port'
+// for TLS. The headers and proxy of the server are registered for it.
+func Target(address string) (string, error) {
+	scheme, host := "http", address
+	if strings.HasPrefix(address, "https://") {
+		parsed, err := url.Parse(address)
+		if err != nil {
+			return "", errors.Join(err, fmt.Errorf("gRPC address '%s' was not valid", address))
+		}
+		scheme, host = "https", parsed.Host
+	}
+	if _, _, err := net.SplitHostPort(host); err != nil {
+		return "", errors.Join(err, fmt.Errorf("gRPC address '%s' was not 'host:port'", address))
+	}
+	return scheme + "://" + host, nil
+}
+
+// New returns a client of the server at the address, 'host:port' for plaintext HTTP/2 or 'https://host:port' for TLS
+func New(address string) (*Client, error) {
+	target, err := Target(address)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Client{target: target}
+	transport := &http2.Transport{}
+	if strings.HasPrefix(target, "https://") {
+		transport.DialTLSContext = func(ctx context.Context, network, addr string, config *tls.Config) (net.Conn, error) {
+			conn, err := c.dial(ctx, network, addr)
+			if err != nil {
+				return nil, err
+			}
+			tlsConn := tls.Client(conn, config)
+			if err := tlsConn.HandshakeContext(ctx); err != nil {
+				conn.Close()
+				return nil, err
+			}
+			return tlsConn, nil
+		}
+	} else {
+		// Plaintext HTTP/2 without upgrade, as gRPC servers without TLS expect it
+		transport.AllowHTTP = true
+		transport.DialTLSContext = func(ctx context.Context, network, addr string, _ *tls.Config) (net.Conn, error) {
+			return c.dial(ctx, network, addr)
+		}
+	}
+	c.client = &http.Client{Transport: httpclient.Configure(breaker.NewTransport(httpclient.Intercept(transport)))}
+	return c, nil
+}
+
+// dial connects to the server through the proxy of its endpoint. HTTP/2 isn't tunnelled through HTTP proxies, so only
+// SOCKS5 proxies are supported, the others fail rather than reaching the server directly.
+func (c *Client) dial(ctx context.Context, network, addr string) (net.Conn, error) {
+	req, err := http.NewRequest(http.MethodPost, c.target, nil)
+	if err != nil {
+		return nil, err
+	}
+	proxyURL, err := httpclient.Proxy(req)
+	if err != nil {
+		return nil, err
+	}
+	if proxyURL == nil {
+		var dialer net.Dialer
+		return dialer.DialContext(ctx, network, addr)
+	}
+
+	if proxyURL.Scheme != "socks5" && proxyURL.Scheme != "socks5h" {
+		return nil, fmt.Errorf("gRPC can only be proxied through SOCKS5, proxy scheme '%s' is not supported", proxyURL.Scheme)
+	}
+	dialer, err := proxy.FromURL(proxyURL, proxy.Direct)
+	if err != nil {
+		return nil, errors.Join(err, errors.New("failed creating the SOCKS5 dialer of the gRPC proxy"))
+	}
+	if contextDialer, ok := dialer.(proxy.ContextDialer); ok {
+		return contextDialer.DialContext(ctx, network, addr)
+	}
+	return dialer.Dial(network, addr)
+}
+
+// Invoke calls the method, e.g. '/ethereum.eth.v1alpha1.Node/ListPeers', with the encoded request message and returns
+// the encoded response message
+func (c *Client) Invoke(ctx context.Context, method string, request []byte) ([]byte, error) {
+	body := make([]byte, 5, 5+len(request))
+	binary.BigEndian.PutUint32(body[1:], uint32(len(request)))
+	body = append(body, request...)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.target+method, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/grpc+proto")
+	req.Header.Set("TE", "trailers")
+
+	res, err := c.client.Do(req)
+	if err != nil {
+		return nil, errors.Join(err, fmt.Errorf("failed invoking gRPC method '%s'", method))
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("gRPC method '%s' received unsuccessful status code. Code: '%s'", method, res.Status)
+	}
+
+	// Errors without a message are sent as headers only, the others in the trailers after the message
+	if err := status(method, res.Header); err != nil {
+		return nil, err
+	}
+	message, err := readMessage(res.Body)
+	if err != nil {
+		return nil, errors.Join(err, fmt.Errorf("failed reading the response of gRPC method '%s'", method))
+	}
+	if _, err := io.Copy(io.Discard, res.Body); err != nil {
+		return nil, err
+	}
+	if err := status(method, res.Trailer); err != nil {
+		return nil, err
+	}
+	return message, nil
+}
+
+// status returns the error of the gRPC status in the header, nil when the header has none or it is OK
+func status(method string, header http.Header) error {
+	value := header.Get("Grpc-Status")
+	if value == "" {
+		return nil
+	}
+	code, err := strconv.Atoi(value)
+	if err != nil {
+		return fmt.Errorf("gRPC method '%s' received invalid status '%s'", method, value)
+	}
+	switch code {
+	case codeOK:
+		return nil
+	case codeUnimplemented:
+		return errors.Join(ErrUnimplemented, fmt.Errorf("gRPC method '%s' is not implemented", method))
+	default:
+		return fmt.Errorf("gRPC method '%s' failed with status %d: '%s'", method, code, header.Get("Grpc-Message"))
+	}
+}
+
+// readMessage reads a length-prefixed message, compressed messages aren't supported as no compression is requested
+func readMessage(body io.Reader) ([]byte, error) {
+	var prefix [5]byte
+	if _, err := io.ReadFull(body, prefix[:]); err != nil {
+		return nil, err
+	}
+	if prefix[0] != 0 {
+		return nil, errors.New("compressed gRPC messages are not supported")
+	}
+	length := binary.BigEndian.Uint32(prefix[1:])
+	if length > maxMessageSize {
+		return nil, fmt.Errorf("gRPC message of %d bytes exceeds the limit of %d bytes", length, maxMessageSize)
+	}
+	message := make([]byte, length)
+	if _, err := io.ReadFull(body, message); err != nil {
+		return nil, err
+	}
+	return message, nil
+}
@@ -0,0 +1,128 @@
+package grpcclient
+
+import (
+	"context"
+	"encoding/binary"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/httpclient"
+)
+
+func newServer(t *testing.T, handler http.HandlerFunc) *Client {
+	client, _ := newServerAt(t, handler)
+	return client
+}
+
+// newServerAt returns the client along with the URL of the server
+func newServerAt(t *testing.T, handler http.HandlerFunc) (*Client, string) {
+	server := httptest.NewServer(h2c.NewHandler(handler, &http2.Server{}))
+	t.Cleanup(server.Close)
+
+	client, err := New(strings.TrimPrefix(server.URL, "http://"))
+	require.NoError(t, err)
+	return client, server.URL
+}
+
+func TestGivenServerWhenInvokeThenReturnsResponseMessage(t *testing.T) {
+	var method string
+	var request []byte
+	client := newServer(t, func(w http.ResponseWriter, r *http.Request) {
+		method = r.URL.Path
+		body, _ := io.ReadAll(r.Body)
+		request = body[5:]
+
+		w.Header().Set("Content-Type", "application/grpc")
+		w.Header().Set("Trailer", "Grpc-Status")
+		message := []byte{0x08, 0x01}
+		prefix := make([]byte, 5)
+		binary.BigEndian.PutUint32(prefix[1:], uint32(len(message)))
+		_, _ = w.Write(append(prefix, message...))
+		w.Header().Set("Grpc-Status", "0")
+	})
+
+	response, err := client.Invoke(context.Background(), "/ethereum.eth.v1alpha1.Node/GetSyncStatus", []byte{0x10, 0x02})
+	require.NoError(t, err)
+
+	assert.Equal(t, "/ethereum.eth.v1alpha1.Node/GetSyncStatus", method)
+	assert.Equal(t, []byte{0x10, 0x02}, request)
+	assert.Equal(t, []byte{0x08, 0x01}, response)
+}
+
+func TestGivenUnimplementedMethodWhenInvokeThenErrUnimplemented(t *testing.T) {
+	client := newServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/grpc")
+		w.Header().Set("Grpc-Status", "12")
+	})
+
+	_, err := client.Invoke(context.Background(), "/ethereum.eth.v1alpha1.Node/GetHealth", nil)
+
+	assert.ErrorIs(t, err, ErrUnimplemented)
+}
+
+func TestGivenFailedMethodWhenInvokeThenReturnsStatusMessage(t *testing.T) {
+	client := newServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/grpc")
+		w.Header().Set("Grpc-Status", "14")
+		w.Header().Set("Grpc-Message", "syncing")
+	})
+
+	_, err := client.Invoke(context.Background(), "/ethereum.eth.v1alpha1.Node/ListPeers", nil)
+
+	require.Error(t, err)
+	assert.NotErrorIs(t, err, ErrUnimplemented)
+	assert.Contains(t, err.Error(), "syncing")
+}
+
+func TestGivenAddressWithoutPortWhenNewThenError(t *testing.T) {
+	_, err := New("prysm")
+
+	assert.Error(t, err)
+}
+
+func TestGivenHeadersOfEndpointWhenInvokeThenSent(t *testing.T) {
+	var apiKey string
+	client, url := newServerAt(t, func(w http.ResponseWriter, r *http.Request) {
+		apiKey = r.Header.Get("X-Api-Key")
+		w.Header().Set("Content-Type", "application/grpc")
+		w.Header().Set("Grpc-Status", "12")
+	})
+	require.NoError(t, httpclient.SetHeaders(url, map[string]string{"X-Api-Key": "secret"}))
+
+	_, err := client.Invoke(context.Background(), "/ethereum.eth.v1alpha1.Node/GetHealth", nil)
+
+	assert.ErrorIs(t, err, ErrUnimplemented)
+	assert.Equal(t, "secret", apiKey)
+}
+
+func TestGivenHTTPProxyOfEndpointWhenInvokeThenFailsRatherThanDirect(t *testing.T) {
+	var reached bool
+	client, url := newServerAt(t, func(w http.ResponseWriter, r *http.Request) {
+		reached = true
+	})
+	require.NoError(t, httpclient.SetProxy(url, "http://127.0.0.1:3128"))
+
+	_, err := client.Invoke(context.Background(), "/ethereum.eth.v1alpha1.Node/GetHealth", nil)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "SOCKS5")
+	assert.False(t, reached)
+}
+
+func TestGivenAddressWhenTargetThenURLOfScheme(t *testing.T) {
+	target, err := Target("prysm:4000")
+	require.NoError(t, err)
+	assert.Equal(t, "http://prysm:4000", target)
+
+	target, err = Target("https://prysm:4000")
+	require.NoError(t, err)
+	assert.Equal(t, "https://prysm:4000", target)
+}
@@ -0,0 +1,41 @@
+package hardware
+
+import (
+	"context"
+	"time"
+
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/metric"
+)
+
+// timerTick is the sleep whose overshoot is measured by the clock benchmark
+const timerTick = time.Millisecond
+
+// ClockResult tells whether the system clock is synchronized, e.g. by NTP, and how late timers fire. Validators
+// attest at fixed times of the slot, a clock off by a second or timers firing late make them miss the head.
+type ClockResult struct {
+	// Synchronized is unknown (nil) on systems not reporting the synchronization of the kernel clock
+	Synchronized *bool         `json:"synchronized,omitempty"`
+	MaxError     time.Duration `json:"max_error,omitempty"`
+	TimerP50     time.Duration `json:"timer_p50"`
+	TimerP99     time.Duration `json:"timer_p99"`
+}
+
+// Clock reads the synchronization state of the clock and measures the overshoot of short sleeps for the duration
+func Clock(ctx context.Context, duration time.Duration) ClockResult {
+	var result ClockResult
+	result.Synchronized, result.MaxError = clockSync()
+
+	ctx, cancel := context.WithTimeout(ctx, duration)
+	defer cancel()
+	var overshoots []time.Duration
+	for ctx.Err() == nil {
+		start := time.Now()
+		time.Sleep(timerTick)
+		overshoots = append(overshoots, max(time.Since(start)-timerTick, 0))
+	}
+	if len(overshoots) != 0 {
+		percentiles := metric.CalculatePercentiles(overshoots, 50, 99)
+		result.TimerP50, result.TimerP99 = percentiles[50], percentiles[99]
+	}
+	return result
+}
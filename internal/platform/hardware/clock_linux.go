@@ -0,0 +1,20 @@
+package hardware
+
+import (
+	"syscall"
+	"time"
+)
+
+// timeError is the state adjtimex returns while the kernel clock is not synchronized
+const timeError = 5
+
+// clockSync reads the synchronization state and the maximum error of the kernel clock without adjusting it
+func clockSync() (*bool, time.Duration) {
+	var timex syscall.Timex
+	state, err := syscall.Adjtimex(&timex)
+	if err != nil {
+		return nil, 0
+	}
+	synchronized := state != timeError
+	return &synchronized, time.Duration(timex.Maxerror) * time.Microsecond
+}
@@ -0,0 +1,10 @@
+//go:build !linux
+
+package hardware
+
+import "time"
+
+// clockSync is unknown, only Linux reports the synchronization of the kernel clock
+func clockSync() (*bool, time.Duration) {
+	return nil, 0
+}
@@ -0,0 +1,61 @@
+package hardware
+
+import (
+	"context"
+	"crypto/sha256"
+	"runtime"
+	"sync"
+	"time"
+)
+
+// hashBlock is the size of the blocks hashed by the CPU benchmark
+const hashBlock = 1 << 20
+
+// CPUResult is the SHA-256 throughput of a single core and of all cores, hashing is what clients spend much of their
+// CPU time on when verifying blocks and attestations
+type CPUResult struct {
+	Cores          int     `json:"cores"`
+	SingleCoreMBps float64 `json:"single_core_mbps"`
+	MultiCoreMBps  float64 `json:"multi_core_mbps"`
+}
+
+// CPU hashes on a single core for the duration, then on every core for the duration
+func CPU(ctx context.Context, duration time.Duration) CPUResult {
+	cores := runtime.NumCPU()
+	return CPUResult{
+		Cores:          cores,
+		SingleCoreMBps: hashThroughput(ctx, 1, duration),
+		MultiCoreMBps:  hashThroughput(ctx, cores, duration),
+	}
+}
+
+func hashThroughput(ctx context.Context, workers int, duration time.Duration) float64 {
+	ctx, cancel := context.WithTimeout(ctx, duration)
+	defer cancel()
+
+	var (
+		wg     sync.WaitGroup
+		mutex  sync.Mutex
+		hashed int
+	)
+	start := time.Now()
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			block := make([]byte, hashBlock)
+			count := 0
+			for ctx.Err() == nil {
+				sum := sha256.Sum256(block)
+				// Chaining the hashes keeps the compiler from dropping them
+				block[0] = sum[0]
+				count++
+			}
+			mutex.Lock()
+			hashed += count
+			mutex.Unlock()
+		}()
+	}
+	wg.Wait()
+	return float64(hashed) / time.Since(start).Seconds()
+}
@@ -0,0 +1,136 @@
+package hardware
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	mathrand "math/rand/v2"
+	"os"
+	"time"
+	"unsafe"
+
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/metric"
+)
+
+const (
+	// blockSize is the size of the random reads and writes, the page size of the databases of the clients
+	blockSize = 4096
+	// fillChunk is the size of the writes filling the test file
+	fillChunk = 1 << 20
+)
+
+// DiskResult is the performance of the disk holding the test file. Cached results were read through the page cache,
+// because the filesystem doesn't support direct I/O, and overstate the disk.
+type DiskResult struct {
+	SequentialWriteMBps float64       `json:"sequential_write_mbps"`
+	RandomReadIOPS      float64       `json:"random_read_iops"`
+	SyncWriteIOPS       float64       `json:"sync_write_iops"`
+	FsyncP50            time.Duration `json:"fsync_p50"`
+	FsyncP99            time.Duration `json:"fsync_p99"`
+	Cached              bool          `json:"cached"`
+}
+
+// Disk writes a test file of the size to the directory, then reads random blocks of it for the duration and writes
+// random blocks synced to the disk for the duration, like the random I/O of the state databases of the clients. The
+// test file is removed afterwards.
+func Disk(ctx context.Context, dir string, size int64, duration time.Duration) (DiskResult, error) {
+	var result DiskResult
+	size = max(size-size%fillChunk, fillChunk)
+
+	file, err := os.CreateTemp(dir, ".benchmark-disk-*")
+	if err != nil {
+		return result, errors.Join(err, errors.New("failed creating the disk test file"))
+	}
+	path := file.Name()
+	defer os.Remove(path)
+
+	chunk := make([]byte, fillChunk)
+	if _, err := rand.Read(chunk); err != nil {
+		_ = file.Close()
+		return result, err
+	}
+	start := time.Now()
+	for written := int64(0); written < size; written += fillChunk {
+		if _, err := file.Write(chunk); err != nil {
+			_ = file.Close()
+			return result, errors.Join(err, errors.New("failed filling the disk test file"))
+		}
+	}
+	if err := file.Sync(); err != nil {
+		_ = file.Close()
+		return result, err
+	}
+	result.SequentialWriteMBps = float64(size) / 1e6 / time.Since(start).Seconds()
+	if err := file.Close(); err != nil {
+		return result, err
+	}
+
+	direct, cached, err := openDirect(path)
+	if err != nil {
+		return result, errors.Join(err, errors.New("failed opening the disk test file"))
+	}
+	defer direct.Close()
+	result.Cached = cached
+
+	block := alignedBlock()
+	blocks := size / blockSize
+	reads, elapsed, err := repeat(ctx, duration, func() error {
+		_, err := direct.ReadAt(block, randomBlock(blocks))
+		return err
+	})
+	if err != nil {
+		return result, errors.Join(err, errors.New("failed reading the disk test file"))
+	}
+	result.RandomReadIOPS = float64(reads) / elapsed.Seconds()
+
+	var fsyncs []time.Duration
+	writes, elapsed, err := repeat(ctx, duration, func() error {
+		if _, err := direct.WriteAt(block, randomBlock(blocks)); err != nil {
+			return err
+		}
+		start := time.Now()
+		err = direct.Sync()
+		fsyncs = append(fsyncs, time.Since(start))
+		return err
+	})
+	if err != nil {
+		return result, errors.Join(err, errors.New("failed writing the disk test file"))
+	}
+	result.SyncWriteIOPS = float64(writes) / elapsed.Seconds()
+	if len(fsyncs) != 0 {
+		percentiles := metric.CalculatePercentiles(fsyncs, 50, 99)
+		result.FsyncP50, result.FsyncP99 = percentiles[50], percentiles[99]
+	}
+	return result, nil
+}
+
+// repeat runs the operation until the duration passed, returning the number of runs and the time they took
+func repeat(ctx context.Context, duration time.Duration, operation func() error) (int, time.Duration, error) {
+	ctx, cancel := context.WithTimeout(ctx, duration)
+	defer cancel()
+
+	count := 0
+	start := time.Now()
+	for ctx.Err() == nil {
+		if err := operation(); err != nil {
+			return count, time.Since(start), err
+		}
+		count++
+	}
+	return count, time.Since(start), nil
+}
+
+// randomBlock returns the offset of a random block of the file
+func randomBlock(blocks int64) int64 {
+	return mathrand.Int64N(blocks) * blockSize
+}
+
+// alignedBlock returns a block aligned to the block size in memory, as direct I/O requires
+func alignedBlock() []byte {
+	buffer := make([]byte, 2*blockSize)
+	start := 0
+	if misalignment := int(uintptr(unsafe.Pointer(&buffer[0])) & (blockSize - 1)); misalignment != 0 {
+		start = blockSize - misalignment
+	}
+	return buffer[start : start+blockSize]
+}
@@ -0,0 +1,17 @@
+package hardware
+
+import (
+	"errors"
+	"os"
+	"syscall"
+)
+
+// openDirect opens the file bypassing the page cache, or through it on filesystems without direct I/O, e.g. tmpfs
+func openDirect(path string) (*os.File, bool, error) {
+	file, err := os.OpenFile(path, os.O_RDWR|syscall.O_DIRECT, 0)
+	if errors.Is(err, syscall.EINVAL) {
+		file, err = os.OpenFile(path, os.O_RDWR, 0)
+		return file, true, err
+	}
+	return file, false, err
+}
@@ -0,0 +1,11 @@
+//go:build !linux
+
+package hardware
+
+import "os"
+
+// openDirect opens the file through the page cache, direct I/O is only supported on Linux
+func openDirect(path string) (*os.File, bool, error) {
+	file, err := os.OpenFile(path, os.O_RDWR, 0)
+	return file, true, err
+}
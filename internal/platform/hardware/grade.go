@@ -0,0 +1,122 @@
+package hardware
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/format"
+	"github.com/Harikakasimahanthi/benchmark-test/report"
+)
+
+type (
+	// Assessment holds the results of the micro-benchmarks of the machine
+	Assessment struct {
+		CPU    CPUResult    `json:"cpu"`
+		Memory MemoryResult `json:"memory"`
+		Disk   DiskResult   `json:"disk"`
+		Clock  ClockResult  `json:"clock"`
+	}
+
+	// requirement bounds a result for the good and the minimum grade
+	requirement struct {
+		good, minimum float64
+		// lowerIsBetter grades latencies, whose values must stay below the bounds
+		lowerIsBetter bool
+	}
+)
+
+// Requirements of staking on mainnet, a full node and a validator on the same machine. SHA-256 throughput without hash
+// instructions on ARM boards hardly reaches the minimum, 4k random read IOPS of SATA SSDs do, those of NVMe SSDs are
+// good. A fsync of a SSD with power loss protection takes below a millisecond, of consumer SSDs some milliseconds.
+var (
+	singleCoreRequirement = requirement{good: 500, minimum: 150}
+	coresRequirement      = requirement{good: 8, minimum: 4}
+	memoryRequirement     = requirement{good: 32, minimum: 16}
+	bandwidthRequirement  = requirement{good: 8, minimum: 2}
+	readIOPSRequirement   = requirement{good: 10000, minimum: 3000}
+	fsyncRequirement      = requirement{good: float64(5 * time.Millisecond), minimum: float64(20 * time.Millisecond), lowerIsBetter: true}
+	clockErrorRequirement = requirement{good: float64(100 * time.Millisecond), minimum: float64(500 * time.Millisecond), lowerIsBetter: true}
+	timerRequirement      = requirement{good: float64(2 * time.Millisecond), minimum: float64(10 * time.Millisecond), lowerIsBetter: true}
+)
+
+// Assess runs the micro-benchmarks, each for the duration, the disk with a test file of the size in the directory
+func Assess(ctx context.Context, dir string, diskSize int64, duration time.Duration) (Assessment, error) {
+	var assessment Assessment
+	var err error
+
+	assessment.CPU = CPU(ctx, duration)
+	if assessment.Memory, err = Memory(ctx, duration); err != nil {
+		return assessment, err
+	}
+	if assessment.Disk, err = Disk(ctx, dir, diskSize, duration); err != nil {
+		return assessment, err
+	}
+	assessment.Clock = Clock(ctx, duration)
+	return assessment, nil
+}
+
+// Grade grades the results of the assessment against the requirements of staking
+func Grade(a Assessment) []report.HardwareCheck {
+	reads := readIOPSRequirement.check("Disk random read", a.Disk.RandomReadIOPS, fmt.Sprintf("%.0f IOPS (4k)", a.Disk.RandomReadIOPS), countUnit)
+	if a.Disk.Cached {
+		reads.Grade, reads.Detail = report.GradeUnknown, "the filesystem doesn't support direct I/O, the reads hit the page cache"
+	}
+	checks := []report.HardwareCheck{
+		singleCoreRequirement.check("CPU single core", a.CPU.SingleCoreMBps, fmt.Sprintf("%.0f MB/s SHA-256", a.CPU.SingleCoreMBps), mbpsUnit),
+		coresRequirement.check("CPU cores", float64(a.CPU.Cores), fmt.Sprintf("%d cores, %.0f MB/s SHA-256", a.CPU.Cores, a.CPU.MultiCoreMBps), countUnit),
+		memoryRequirement.check("RAM size", float64(a.Memory.TotalBytes)/1e9, format.Bytes(float64(a.Memory.TotalBytes)), gigabytesUnit),
+		bandwidthRequirement.check("RAM bandwidth", a.Memory.BandwidthGBps, fmt.Sprintf("%.1f GB/s", a.Memory.BandwidthGBps), gigabytesPerSecondUnit),
+		reads,
+		fsyncRequirement.check("Disk fsync", float64(a.Disk.FsyncP99),
+			fmt.Sprintf("p99=%s, %.0f synced writes/s", format.Duration(a.Disk.FsyncP99), a.Disk.SyncWriteIOPS), durationUnit),
+	}
+
+	clock := clockErrorRequirement.check("Clock sync", float64(a.Clock.MaxError), "max error "+format.Duration(a.Clock.MaxError), durationUnit)
+	switch {
+	case a.Clock.Synchronized == nil:
+		clock.Result, clock.Grade, clock.Detail = "-", report.GradeUnknown, "the system doesn't report the synchronization of its clock"
+	case !*a.Clock.Synchronized:
+		clock.Result, clock.Grade, clock.Detail = "not synchronized", report.GradeInsufficient, "enable NTP, e.g. chrony or systemd-timesyncd"
+	}
+	checks = append(checks, clock,
+		timerRequirement.check("Timer overshoot", float64(a.Clock.TimerP99), "p99="+format.Duration(a.Clock.TimerP99), durationUnit))
+	return checks
+}
+
+func (r requirement) check(name string, value float64, result string, unit func(float64) string) report.HardwareCheck {
+	bound := "≥ "
+	if r.lowerIsBetter {
+		bound = "≤ "
+	}
+	return report.HardwareCheck{
+		Check:   name,
+		Result:  result,
+		Good:    bound + unit(r.good),
+		Minimum: bound + unit(r.minimum),
+		Grade:   r.grade(value),
+	}
+}
+
+func (r requirement) grade(value float64) string {
+	meets := func(bound float64) bool {
+		if r.lowerIsBetter {
+			return value <= bound
+		}
+		return value >= bound
+	}
+	switch {
+	case meets(r.good):
+		return report.GradeGood
+	case meets(r.minimum):
+		return report.GradeMinimum
+	default:
+		return report.GradeInsufficient
+	}
+}
+
+func mbpsUnit(value float64) string               { return fmt.Sprintf("%.0f MB/s", value) }
+func countUnit(value float64) string              { return fmt.Sprintf("%.0f", value) }
+func gigabytesUnit(value float64) string          { return fmt.Sprintf("%.0f GB", value) }
+func gigabytesPerSecondUnit(value float64) string { return fmt.Sprintf("%.0f GB/s", value) }
+func durationUnit(value float64) string           { return format.Duration(time.Duration(value)) }
@@ -0,0 +1,53 @@
+package hardware
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Harikakasimahanthi/benchmark-test/report"
+)
+
+func TestGivenRequirementWhenGradeThenBoundsInclusive(t *testing.T) {
+	assert.Equal(t, report.GradeGood, readIOPSRequirement.grade(10000))
+	assert.Equal(t, report.GradeMinimum, readIOPSRequirement.grade(3000))
+	assert.Equal(t, report.GradeInsufficient, readIOPSRequirement.grade(100))
+
+	assert.Equal(t, report.GradeGood, fsyncRequirement.grade(float64(time.Millisecond)))
+	assert.Equal(t, report.GradeInsufficient, fsyncRequirement.grade(float64(50*time.Millisecond)))
+}
+
+func TestGivenUnsynchronizedClockAndCachedDiskWhenGradeThenInsufficientOverall(t *testing.T) {
+	synchronized := false
+	checks := Grade(Assessment{
+		CPU:    CPUResult{Cores: 8, SingleCoreMBps: 1000, MultiCoreMBps: 8000},
+		Memory: MemoryResult{TotalBytes: 64e9, BandwidthGBps: 20},
+		Disk:   DiskResult{RandomReadIOPS: 500000, FsyncP99: time.Millisecond, Cached: true},
+		Clock:  ClockResult{Synchronized: &synchronized, TimerP99: time.Millisecond},
+	})
+
+	grades := make(map[string]string, len(checks))
+	for _, check := range checks {
+		grades[check.Check] = check.Grade
+	}
+	assert.Equal(t, report.GradeUnknown, grades["Disk random read"])
+	assert.Equal(t, report.GradeInsufficient, grades["Clock sync"])
+	assert.Equal(t, report.GradeGood, grades["CPU cores"])
+	assert.Equal(t, report.GradeInsufficient, report.OverallGrade(checks))
+}
+
+func TestGivenDirectoryWhenDiskThenMeasuredAndTestFileRemoved(t *testing.T) {
+	dir := t.TempDir()
+
+	result, err := Disk(context.Background(), dir, 1<<20, 50*time.Millisecond)
+
+	assert.NoError(t, err)
+	assert.Positive(t, result.RandomReadIOPS)
+	assert.Positive(t, result.SyncWriteIOPS)
+	entries, err := os.ReadDir(dir)
+	assert.NoError(t, err)
+	assert.Empty(t, entries)
+}
@@ -0,0 +1,40 @@
+package hardware
+
+import (
+	"context"
+	"time"
+
+	"github.com/mackerelio/go-osstat/memory"
+)
+
+// copyBuffer is the size of the buffers copied by the memory benchmark, far beyond the CPU caches
+const copyBuffer = 64 << 20
+
+// MemoryResult is the size of the memory and the bandwidth of copying within it
+type MemoryResult struct {
+	TotalBytes    uint64  `json:"total_bytes"`
+	BandwidthGBps float64 `json:"bandwidth_gbps"`
+}
+
+// Memory reads the size of the memory and copies buffers for the duration
+func Memory(ctx context.Context, duration time.Duration) (MemoryResult, error) {
+	stats, err := memory.Get()
+	if err != nil {
+		return MemoryResult{}, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, duration)
+	defer cancel()
+	src, dst := make([]byte, copyBuffer), make([]byte, copyBuffer)
+	copied := 0
+	start := time.Now()
+	for ctx.Err() == nil {
+		copied += copy(dst, src)
+		src, dst = dst, src
+	}
+
+	return MemoryResult{
+		TotalBytes:    stats.Total,
+		BandwidthGBps: float64(copied) / time.Since(start).Seconds() / 1e9,
+	}, nil
+}
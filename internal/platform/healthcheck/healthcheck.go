@@ -0,0 +1,93 @@
+package healthcheck
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const pingTimeout = time.Second * 10
+
+// Pinger pings a dead man's switch such as healthchecks.io, which alerts when the pings stop: '<url>/start' when the
+// run starts, the URL itself every interval and once the run completes, '<url>/fail' when the run fails
+type Pinger struct {
+	url      string
+	interval time.Duration
+	client   *http.Client
+}
+
+// New pings the URL every interval while the run lasts, zero pings at the start and the end of the run only
+func New(url string, interval time.Duration) *Pinger {
+	return &Pinger{
+		url:      strings.TrimSuffix(url, "/"),
+		interval: interval,
+		client:   &http.Client{Timeout: pingTimeout},
+	}
+}
+
+// Start signals the run started, so the switch measures its duration and alerts when it never completes
+func (p *Pinger) Start() {
+	p.ping("/start", "")
+}
+
+// Run pings every interval until the context is done, so the switch alerts when the run stops without ending
+func (p *Pinger) Run(ctx context.Context) {
+	if p.interval == 0 {
+		return
+	}
+
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			p.ping("", "running")
+		}
+	}
+}
+
+// Success signals the run completed, the message is shown in the log of the switch
+func (p *Pinger) Success(message string) {
+	p.ping("", message)
+}
+
+// Fail signals the run failed, the switch alerts right away
+func (p *Pinger) Fail(message string) {
+	p.ping("/fail", message)
+}
+
+func (p *Pinger) ping(path, message string) {
+	if err := p.post(path, message); err != nil {
+		slog.With("err", err.Error()).With("path", path).Error("failed pinging the health check")
+		return
+	}
+	slog.With("path", path).Debug("health check pinged")
+}
+
+func (p *Pinger) post(path, message string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), pingTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.url+path, strings.NewReader(message))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "text/plain")
+
+	res, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return fmt.Errorf("health check responded with status %d", res.StatusCode)
+	}
+	return nil
+}
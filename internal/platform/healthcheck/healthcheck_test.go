@@ -0,0 +1,65 @@
+package healthcheck
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type pings struct {
+	paths    []string
+	messages []string
+	mutex    sync.Mutex
+}
+
+func (p *pings) server(t *testing.T) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		p.mutex.Lock()
+		defer p.mutex.Unlock()
+		p.paths = append(p.paths, r.URL.Path)
+		p.messages = append(p.messages, string(body))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestGivenRunWhenStartedAndCompletedThenPingsStartAndSuccess(t *testing.T) {
+	var received pings
+	pinger := New(received.server(t).URL+"/uuid/", 0)
+
+	pinger.Start()
+	pinger.Success("run completed")
+
+	assert.Equal(t, []string{"/uuid/start", "/uuid"}, received.paths)
+	assert.Equal(t, "run completed", received.messages[1])
+}
+
+func TestGivenRunWhenFailedThenPingsFail(t *testing.T) {
+	var received pings
+	pinger := New(received.server(t).URL+"/uuid", 0)
+
+	pinger.Fail("invalid configuration")
+
+	assert.Equal(t, []string{"/uuid/fail"}, received.paths)
+	assert.Equal(t, "invalid configuration", received.messages[0])
+}
+
+func TestGivenIntervalWhenRunningThenPingsPeriodically(t *testing.T) {
+	var received pings
+	pinger := New(received.server(t).URL+"/uuid", 10*time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 55*time.Millisecond)
+	defer cancel()
+	pinger.Run(ctx)
+
+	received.mutex.Lock()
+	defer received.mutex.Unlock()
+	assert.GreaterOrEqual(t, len(received.paths), 3)
+}
@@ -0,0 +1,267 @@
+package httpclient
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/breaker"
+)
+
+// Default is the HTTP client shared by the collectors. Its transport backs off from endpoints which are down, sets the
+// configured User-Agent and sends the headers and token registered for the endpoint of a request.
+var Default = &http.Client{
+	Transport: Configure(breaker.NewTransport(Intercept(NewTransport()))),
+}
+
+// NewTransport returns a transport sending the requests through the proxy of their endpoint
+func NewTransport() *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = Proxy
+	return transport
+}
+
+// Interceptor sees every request right before it is sent with the next transport, e.g. to record or replay it
+type Interceptor interface {
+	RoundTrip(req *http.Request, next http.RoundTripper) (*http.Response, error)
+}
+
+var interceptor atomic.Pointer[Interceptor]
+
+// SetInterceptor routes the requests of every intercepted transport through the interceptor
+func SetInterceptor(i Interceptor) {
+	interceptor.Store(&i)
+}
+
+// Chain combines the interceptors, the first one sees the requests first
+func Chain(interceptors ...Interceptor) Interceptor {
+	return chain(interceptors)
+}
+
+type chain []Interceptor
+
+func (c chain) RoundTrip(req *http.Request, next http.RoundTripper) (*http.Response, error) {
+	if len(c) == 0 {
+		return next.RoundTrip(req)
+	}
+	return c[0].RoundTrip(req, roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		return c[1:].RoundTrip(req, next)
+	}))
+}
+
+type roundTripperFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// Intercept returns a transport which sends requests through the interceptor, once one is set
+func Intercept(next http.RoundTripper) http.RoundTripper {
+	return &interceptedTransport{next: next}
+}
+
+type interceptedTransport struct {
+	next http.RoundTripper
+}
+
+func (t *interceptedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if i := interceptor.Load(); i != nil {
+		return (*i).RoundTrip(req, t.next)
+	}
+	return t.next.RoundTrip(req)
+}
+
+// Direct is the proxy of endpoints which are reached without the default proxy
+const Direct = "direct"
+
+var endpoints = struct {
+	tokens    map[string]string
+	headers   map[string]map[string]string
+	userAgent string
+	// proxies hold nil for the endpoints reached directly
+	proxies      map[string]*url.URL
+	defaultProxy *url.URL
+	mutex        sync.RWMutex
+}{tokens: make(map[string]string), headers: make(map[string]map[string]string), proxies: make(map[string]*url.URL)}
+
+// Configure returns a transport setting the User-Agent, and the headers and token registered for the endpoint (scheme
+// and host) of every request
+func Configure(next http.RoundTripper) http.RoundTripper {
+	return &endpointTransport{next: next}
+}
+
+type endpointTransport struct {
+	next http.RoundTripper
+}
+
+// SetUserAgent replaces the User-Agent of every request, the default one is kept when it is empty
+func SetUserAgent(userAgent string) {
+	endpoints.mutex.Lock()
+	defer endpoints.mutex.Unlock()
+
+	endpoints.userAgent = userAgent
+}
+
+// SetHeaders registers headers sent with every request to the endpoint of the address, e.g. for reverse proxies or
+// hosted providers which filter by header. They replace the headers of the same name set by the collectors.
+func SetHeaders(address string, headers map[string]string) error {
+	endpoint, err := endpointOf(address)
+	if err != nil {
+		return err
+	}
+
+	endpoints.mutex.Lock()
+	defer endpoints.mutex.Unlock()
+
+	registered := endpoints.headers[endpoint]
+	if registered == nil {
+		registered = make(map[string]string, len(headers))
+		endpoints.headers[endpoint] = registered
+	}
+	for name, value := range headers {
+		registered[http.CanonicalHeaderKey(name)] = value
+	}
+	return nil
+}
+
+// SetProxy registers the proxy the requests to the endpoint of the address go through, e.g. a bastion or
+// 'socks5://127.0.0.1:9050' for Tor. The proxy Direct reaches the endpoint without the default proxy.
+func SetProxy(address, proxy string) error {
+	endpoint, err := endpointOf(address)
+	if err != nil {
+		return err
+	}
+	proxyURL, err := parseProxy(proxy)
+	if err != nil {
+		return err
+	}
+
+	endpoints.mutex.Lock()
+	defer endpoints.mutex.Unlock()
+
+	endpoints.proxies[endpoint] = proxyURL
+	return nil
+}
+
+// SetDefaultProxy sets the proxy of the endpoints without proxy of their own, the proxy of the environment (e.g.
+// HTTPS_PROXY) is used when it is empty
+func SetDefaultProxy(proxy string) error {
+	proxyURL, err := parseProxy(proxy)
+	if err != nil {
+		return err
+	}
+
+	endpoints.mutex.Lock()
+	defer endpoints.mutex.Unlock()
+
+	endpoints.defaultProxy = proxyURL
+	return nil
+}
+
+// Proxy returns the proxy of the request: the one of its endpoint, the default one or the one of the environment. It
+// is nil when the request is sent directly.
+func Proxy(req *http.Request) (*url.URL, error) {
+	endpoints.mutex.RLock()
+	proxyURL, ok := endpoints.proxies[req.URL.Scheme+"://"+req.URL.Host]
+	defaultProxy := endpoints.defaultProxy
+	endpoints.mutex.RUnlock()
+
+	switch {
+	case ok:
+		return proxyURL, nil
+	case defaultProxy != nil:
+		return defaultProxy, nil
+	}
+	return http.ProxyFromEnvironment(req)
+}
+
+// ProxyOf returns the proxy the requests to the address go through, nil when they are sent directly
+func ProxyOf(address string) *url.URL {
+	req, err := http.NewRequest(http.MethodGet, address, nil)
+	if err != nil || req.URL.Host == "" {
+		return nil
+	}
+	proxyURL, err := Proxy(req)
+	if err != nil {
+		return nil
+	}
+	return proxyURL
+}
+
+// parseProxy parses an HTTP(S) or SOCKS5 proxy URL, it returns nil for the Direct and the empty proxy
+func parseProxy(proxy string) (*url.URL, error) {
+	if proxy == "" || proxy == Direct {
+		return nil, nil
+	}
+	proxyURL, err := url.Parse(proxy)
+	if err != nil {
+		return nil, errors.Join(err, errors.New("proxy was not a valid URL"))
+	}
+	switch proxyURL.Scheme {
+	case "http", "https", "socks5", "socks5h":
+		return proxyURL, nil
+	}
+	return nil, fmt.Errorf("proxy scheme '%s' is not supported, use http, https, socks5 or socks5h", proxyURL.Scheme)
+}
+
+// SetToken registers the bearer token sent with every request to the endpoint of the address
+func SetToken(address, token string) error {
+	endpoint, err := endpointOf(address)
+	if err != nil {
+		return err
+	}
+
+	endpoints.mutex.Lock()
+	defer endpoints.mutex.Unlock()
+
+	endpoints.tokens[endpoint] = token
+	return nil
+}
+
+// SetTokenFile registers the token stored in the file, e.g. the API token file of a validator client keymanager API
+func SetTokenFile(address, path string) error {
+	token, err := os.ReadFile(path)
+	if err != nil {
+		return errors.Join(err, fmt.Errorf("failed reading token file '%s'", path))
+	}
+	return SetToken(address, strings.TrimSpace(string(token)))
+}
+
+func endpointOf(address string) (string, error) {
+	parsed, err := url.Parse(address)
+	if err != nil {
+		return "", errors.Join(err, fmt.Errorf("address '%s' was not a valid URL", address))
+	}
+	return parsed.Scheme + "://" + parsed.Host, nil
+}
+
+func (t *endpointTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	endpoint := req.URL.Scheme + "://" + req.URL.Host
+	endpoints.mutex.RLock()
+	token, withToken := endpoints.tokens[endpoint]
+	headers, userAgent := endpoints.headers[endpoint], endpoints.userAgent
+	endpoints.mutex.RUnlock()
+
+	withToken = withToken && req.Header.Get("Authorization") == ""
+	if !withToken && len(headers) == 0 && userAgent == "" {
+		return t.next.RoundTrip(req)
+	}
+
+	// Round trippers must not modify the request of the caller
+	req = req.Clone(req.Context())
+	if withToken {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	if userAgent != "" {
+		req.Header.Set("User-Agent", userAgent)
+	}
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+	return t.next.RoundTrip(req)
+}
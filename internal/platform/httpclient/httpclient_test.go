@@ -0,0 +1,120 @@
+package httpclient
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGivenTokenFileWhenRequestThenSendsBearerToken(t *testing.T) {
+	var authorization string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "api-token.txt")
+	require.NoError(t, os.WriteFile(path, []byte("api-token-0x1234\n"), 0o600))
+	require.NoError(t, SetTokenFile(server.URL+"/eth/v1/keystores", path))
+
+	res, err := Default.Get(server.URL + "/eth/v1/keystores")
+	require.NoError(t, err)
+	res.Body.Close()
+
+	assert.Equal(t, "Bearer api-token-0x1234", authorization)
+}
+
+func TestGivenNoTokenWhenRequestThenSendsNoAuthorization(t *testing.T) {
+	authorization := "unset"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+	}))
+	defer server.Close()
+
+	res, err := Default.Get(server.URL)
+	require.NoError(t, err)
+	res.Body.Close()
+
+	assert.Empty(t, authorization)
+}
+
+func TestGivenHeadersAndUserAgentWhenRequestThenSent(t *testing.T) {
+	var header http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header
+	}))
+	defer server.Close()
+	require.NoError(t, SetHeaders(server.URL, map[string]string{"x-api-key": "key-1234"}))
+	SetUserAgent("node-benchmark")
+	defer SetUserAgent("")
+
+	req, err := http.NewRequest(http.MethodGet, server.URL+"/eth/v1/node/health", nil)
+	require.NoError(t, err)
+	req.Header.Set("User-Agent", "go-eth2-client")
+	res, err := Default.Do(req)
+	require.NoError(t, err)
+	res.Body.Close()
+
+	assert.Equal(t, "key-1234", header.Get("X-Api-Key"))
+	assert.Equal(t, "node-benchmark", header.Get("User-Agent"))
+	assert.Equal(t, "go-eth2-client", req.Header.Get("User-Agent"), "the request of the caller is unchanged")
+}
+
+func TestGivenChainWhenRequestThenInterceptorsInOrder(t *testing.T) {
+	var order []string
+	first := interceptorFunc(func(req *http.Request, next http.RoundTripper) (*http.Response, error) {
+		order = append(order, "first")
+		return next.RoundTrip(req)
+	})
+	second := interceptorFunc(func(req *http.Request, next http.RoundTripper) (*http.Response, error) {
+		order = append(order, "second")
+		return next.RoundTrip(req)
+	})
+	transport := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		order = append(order, "transport")
+		return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody}, nil
+	})
+
+	req, err := http.NewRequest(http.MethodGet, "http://localhost", nil)
+	require.NoError(t, err)
+	_, err = Chain(first, second).RoundTrip(req, transport)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"first", "second", "transport"}, order)
+}
+
+type interceptorFunc func(req *http.Request, next http.RoundTripper) (*http.Response, error)
+
+func (f interceptorFunc) RoundTrip(req *http.Request, next http.RoundTripper) (*http.Response, error) {
+	return f(req, next)
+}
+
+func TestGivenProxyOfEndpointWhenRequestThenSentThroughProxy(t *testing.T) {
+	var proxied string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied = r.URL.String()
+	}))
+	defer proxy.Close()
+	require.NoError(t, SetProxy("http://beacon.internal:5052", proxy.URL))
+
+	res, err := Default.Get("http://beacon.internal:5052/eth/v1/node/health")
+	require.NoError(t, err)
+	res.Body.Close()
+
+	assert.Equal(t, "http://beacon.internal:5052/eth/v1/node/health", proxied)
+}
+
+func TestGivenDefaultProxyWhenEndpointDirectThenNoProxy(t *testing.T) {
+	require.NoError(t, SetDefaultProxy("socks5://127.0.0.1:9050"))
+	defer func() { require.NoError(t, SetDefaultProxy("")) }()
+	require.NoError(t, SetProxy("http://geth:8545", Direct))
+
+	assert.Nil(t, ProxyOf("http://geth:8545"))
+	assert.Equal(t, "socks5://127.0.0.1:9050", ProxyOf("http://lighthouse:5052").String())
+	assert.Error(t, SetDefaultProxy("ftp://127.0.0.1:21"))
+}
@@ -0,0 +1,122 @@
+package httptiming
+
+import (
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptrace"
+	"time"
+
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/breaker"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/format"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/httpclient"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/metric"
+)
+
+// Timing is the duration of every phase of an HTTP request. Phases which didn't happen (e.g. TLS for plain HTTP) are zero.
+type Timing struct {
+	DNS     time.Duration
+	Connect time.Duration
+	TLS     time.Duration
+	// Tunnel is the time a proxy took to open the connection to the node, it is near zero without proxy
+	Tunnel time.Duration
+	// TTFB is the time from sending the request until the first response byte
+	TTFB  time.Duration
+	Total time.Duration
+}
+
+// ProxyOverhead is the time spent reaching the node through a proxy: resolving and connecting to the proxy and the
+// proxy opening the connection to the node. It is only meaningful when the request went through a proxy.
+func (t Timing) ProxyOverhead() time.Duration {
+	return t.DNS + t.Connect + t.Tunnel
+}
+
+// NewClient returns a client opening a new connection for every request, so every request goes through all phases.
+// Like the shared client it backs off from endpoints which are down, sends the configured headers and goes through the
+// proxy of the endpoint.
+func NewClient(timeout time.Duration) *http.Client {
+	transport := httpclient.NewTransport()
+	transport.DisableKeepAlives = true
+	return &http.Client{
+		Transport: httpclient.Configure(breaker.NewTransport(httpclient.Intercept(transport))),
+		Timeout:   timeout,
+	}
+}
+
+// Do sends the request, reads the whole response body and returns the timing of the phases along with the status code
+func Do(client *http.Client, req *http.Request) (Timing, int, error) {
+	var (
+		timing                                            Timing
+		dnsStart, connectStart, connected, tlsStart, sent time.Time
+	)
+
+	trace := &httptrace.ClientTrace{
+		DNSStart: func(httptrace.DNSStartInfo) { dnsStart = time.Now() },
+		DNSDone:  func(httptrace.DNSDoneInfo) { timing.DNS = time.Since(dnsStart) },
+		ConnectStart: func(string, string) {
+			connectStart = time.Now()
+		},
+		ConnectDone: func(string, string, error) {
+			connected = time.Now()
+			timing.Connect = connected.Sub(connectStart)
+		},
+		// Between connecting and getting the connection a proxy opens the connection to the node, followed by TLS
+		GotConn: func(info httptrace.GotConnInfo) {
+			if !info.Reused && !connected.IsZero() {
+				timing.Tunnel = max(time.Since(connected)-timing.TLS, 0)
+			}
+		},
+		TLSHandshakeStart:    func() { tlsStart = time.Now() },
+		TLSHandshakeDone:     func(tls.ConnectionState, error) { timing.TLS = time.Since(tlsStart) },
+		WroteRequest:         func(httptrace.WroteRequestInfo) { sent = time.Now() },
+		GotFirstResponseByte: func() { timing.TTFB = time.Since(sent) },
+	}
+
+	start := time.Now()
+	res, err := client.Do(req.WithContext(httptrace.WithClientTrace(req.Context(), trace)))
+	if err != nil {
+		return timing, 0, err
+	}
+	defer res.Body.Close()
+
+	if _, err := io.Copy(io.Discard, res.Body); err != nil {
+		return timing, res.StatusCode, err
+	}
+	timing.Total = time.Since(start)
+
+	return timing, res.StatusCode, nil
+}
+
+// Percentile returns the percentile of every phase over the timings
+func Percentile(timings []Timing, percentile float64) Timing {
+	phases := make([][]time.Duration, 6)
+	for _, timing := range timings {
+		phases[0] = append(phases[0], timing.DNS)
+		phases[1] = append(phases[1], timing.Connect)
+		phases[2] = append(phases[2], timing.TLS)
+		phases[3] = append(phases[3], timing.TTFB)
+		phases[4] = append(phases[4], timing.Total)
+		phases[5] = append(phases[5], timing.Tunnel)
+	}
+
+	return Timing{
+		DNS:     metric.CalculatePercentiles(phases[0], percentile)[percentile],
+		Connect: metric.CalculatePercentiles(phases[1], percentile)[percentile],
+		TLS:     metric.CalculatePercentiles(phases[2], percentile)[percentile],
+		TTFB:    metric.CalculatePercentiles(phases[3], percentile)[percentile],
+		Total:   metric.CalculatePercentiles(phases[4], percentile)[percentile],
+		Tunnel:  metric.CalculatePercentiles(phases[5], percentile)[percentile],
+	}
+}
+
+// FormatProxy describes the proxy the requests to the address go through and the time spent reaching the node through
+// it as a line of a latency result, empty when the requests are sent directly
+func FormatProxy(address string, overhead time.Duration) string {
+	proxy := httpclient.ProxyOf(address)
+	if proxy == nil {
+		return ""
+	}
+	// The credentials of the proxy stay out of the report
+	return fmt.Sprintf(" \n proxy=%s://%s, proxy_p50=%s (excluded from the durations)", proxy.Scheme, proxy.Host, format.Duration(overhead))
+}
@@ -0,0 +1,40 @@
+package httptiming
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGivenServerWhenDoThenRecordsPhases(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(10 * time.Millisecond)
+		w.WriteHeader(http.StatusTeapot)
+	}))
+	defer server.Close()
+
+	req, err := http.NewRequest(http.MethodGet, server.URL, nil)
+	require.NoError(t, err)
+
+	timing, status, err := Do(NewClient(time.Second), req)
+
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusTeapot, status)
+	assert.GreaterOrEqual(t, timing.TTFB, 10*time.Millisecond)
+	assert.GreaterOrEqual(t, timing.Total, timing.TTFB)
+	assert.Equal(t, time.Duration(0), timing.TLS)
+}
+
+func TestGivenTimingsWhenPercentileThenCalculatesEveryPhase(t *testing.T) {
+	timings := []Timing{
+		{DNS: 1, Connect: 10, TLS: 100, TTFB: 1000, Total: 10000},
+		{DNS: 2, Connect: 20, TLS: 200, TTFB: 2000, Total: 20000},
+		{DNS: 3, Connect: 30, TLS: 300, TTFB: 3000, Total: 30000},
+	}
+
+	assert.Equal(t, Timing{DNS: 2, Connect: 20, TLS: 200, TTFB: 2000, Total: 20000}, Percentile(timings, 50))
+}
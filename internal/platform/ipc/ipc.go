@@ -0,0 +1,71 @@
+package ipc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"sync/atomic"
+
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/audit"
+)
+
+// IsSocket reports whether the address is a path to a Unix domain socket (e.g. /data/geth/geth.ipc) rather than a URL
+func IsSocket(address string) bool {
+	if strings.Contains(address, "://") {
+		return false
+	}
+	return strings.HasPrefix(address, "/") || strings.HasSuffix(address, ".ipc")
+}
+
+// Interceptor sees every call right before it is sent over the socket, e.g. to record or replay it. Calling send
+// sends the request and decodes the response.
+type Interceptor interface {
+	CallIPC(path string, request, response any, send func() error) error
+}
+
+var interceptor atomic.Pointer[Interceptor]
+
+// SetInterceptor routes every call through the interceptor
+func SetInterceptor(i Interceptor) {
+	interceptor.Store(&i)
+}
+
+// Call sends a single JSON request over the Unix domain socket and decodes the JSON response into the passed value
+func Call(ctx context.Context, path string, request, response any) error {
+	return audit.IPC(path, request, func() error {
+		send := func() error {
+			return call(ctx, path, request, response)
+		}
+		if i := interceptor.Load(); i != nil {
+			return (*i).CallIPC(path, request, response, send)
+		}
+		return send()
+	})
+}
+
+func call(ctx context.Context, path string, request, response any) error {
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "unix", path)
+	if err != nil {
+		return errors.Join(err, fmt.Errorf("failed connecting to IPC socket '%s'", path))
+	}
+	defer conn.Close()
+
+	// Unblock reads and writes once the context is done
+	stop := context.AfterFunc(ctx, func() {
+		conn.Close()
+	})
+	defer stop()
+
+	if err := json.NewEncoder(conn).Encode(request); err != nil {
+		return errors.Join(err, ctx.Err(), errors.New("failed writing IPC request"))
+	}
+	if err := json.NewDecoder(conn).Decode(response); err != nil {
+		return errors.Join(err, ctx.Err(), errors.New("failed reading IPC response"))
+	}
+
+	return nil
+}
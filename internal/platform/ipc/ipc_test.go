@@ -0,0 +1,72 @@
+package ipc
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGivenAddressWhenIsSocketThenDetectsSocketPaths(t *testing.T) {
+	tests := []struct {
+		address  string
+		expected bool
+	}{
+		{address: "/data/geth/geth.ipc", expected: true},
+		{address: "geth.ipc", expected: true},
+		{address: "/var/run/reth.sock", expected: true},
+		{address: "http://localhost:8545", expected: false},
+		{address: "https://geth:8545/rpc.ipc", expected: false},
+		{address: "localhost:8545", expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.address, func(t *testing.T) {
+			assert.Equal(t, tt.expected, IsSocket(tt.address))
+		})
+	}
+}
+
+func TestGivenListeningSocketWhenCallThenDecodesResponse(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "node.ipc")
+	listener, err := net.Listen("unix", path)
+	require.NoError(t, err)
+	defer listener.Close()
+
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		var request map[string]any
+		if err := json.NewDecoder(conn).Decode(&request); err != nil {
+			return
+		}
+		_ = json.NewEncoder(conn).Encode(map[string]any{"jsonrpc": "2.0", "id": request["id"], "result": request["method"]})
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	var response struct {
+		Result string `json:"result"`
+	}
+	err = Call(ctx, path, map[string]any{"jsonrpc": "2.0", "id": 1, "method": "net_peerCount"}, &response)
+
+	require.NoError(t, err)
+	assert.Equal(t, "net_peerCount", response.Result)
+}
+
+func TestGivenMissingSocketWhenCallThenReturnsError(t *testing.T) {
+	var response any
+	err := Call(context.Background(), filepath.Join(t.TempDir(), "missing.ipc"), map[string]any{}, &response)
+
+	assert.Error(t, err)
+}
@@ -0,0 +1,131 @@
+package journal
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/annotation"
+)
+
+const (
+	// Command reads the systemd journal
+	Command = "journalctl"
+	// Source marks the annotations ingested from the journal
+	Source = "journal"
+
+	// maxMessageLength bounds the messages taken over into the annotations
+	maxMessageLength = 200
+)
+
+var (
+	// unitEvents are the messages systemd logs about the lifecycle of a unit, e.g. 'geth.service: Scheduled restart
+	// job, restart counter is at 3.'
+	unitEvents = []string{"Started ", "Stopped ", "Scheduled restart job", "Main process exited", "Failed with result"}
+	// oomKill matches the kernel message of a process killed for lack of memory, e.g. 'Out of memory: Killed process
+	// 1234 (geth) total-vm:...'
+	oomKill = regexp.MustCompile(`Out of memory: Killed process \d+ \(([^)]+)\)`)
+	// segfault matches the kernel message of a crashed process, e.g. 'geth[1234]: segfault at 0 ip ...'
+	segfault = regexp.MustCompile(`^([^\[\s]+)\[\d+\]: segfault at`)
+)
+
+// Entry is a journal entry in the JSON output format of journalctl, which encodes every field as string
+type Entry struct {
+	Message   string `json:"MESSAGE"`
+	Unit      string `json:"UNIT"`
+	Transport string `json:"_TRANSPORT"`
+	// Realtime is the time of the entry in microseconds since the epoch
+	Realtime string `json:"__REALTIME_TIMESTAMP"`
+}
+
+// Follow ingests the lifecycle events of the units, e.g. restarts, and the OOM kills and segfaults logged by the kernel
+// from the journal as annotations until the context is done. Only entries logged from now on are read.
+func Follow(ctx context.Context, units []string, add func(annotation.Annotation)) error {
+	// Matches of different fields separated by '+' are alternatives, the units are matched in the messages systemd logs
+	// about them
+	args := []string{"--follow", "--output=json", "--lines=0", "--no-pager"}
+	for _, unit := range units {
+		args = append(args, "UNIT="+unitName(unit))
+	}
+	args = append(args, "+", "_TRANSPORT=kernel")
+
+	command := exec.CommandContext(ctx, Command, args...)
+	out, err := command.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	if err := command.Start(); err != nil {
+		return errors.Join(err, errors.New("failed starting journalctl"))
+	}
+
+	scanner := bufio.NewScanner(out)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var entry Entry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			slog.With("err", err.Error()).Debug("skipping undecodable journal entry")
+			continue
+		}
+		if a, ok := Annotate(entry); ok {
+			add(a)
+		}
+	}
+
+	err = command.Wait()
+	if ctx.Err() != nil {
+		return nil
+	}
+	return errors.Join(err, scanner.Err(), errors.New("journalctl stopped"))
+}
+
+// Annotate returns the annotation of the entry, entries neither about the lifecycle of a unit nor an OOM kill or
+// segfault have none
+func Annotate(entry Entry) (annotation.Annotation, bool) {
+	var text string
+	switch {
+	case entry.Transport == "kernel":
+		if match := oomKill.FindStringSubmatch(entry.Message); match != nil {
+			text = fmt.Sprintf("OOM kill of %s", match[1])
+		} else if match := segfault.FindStringSubmatch(entry.Message); match != nil {
+			text = fmt.Sprintf("segfault of %s", match[1])
+		}
+	case entry.Unit != "":
+		for _, event := range unitEvents {
+			if strings.Contains(entry.Message, event) {
+				text = entry.Message
+				if !strings.HasPrefix(text, entry.Unit) {
+					text = entry.Unit + ": " + text
+				}
+				break
+			}
+		}
+	}
+	if text == "" {
+		return annotation.Annotation{}, false
+	}
+	if len(text) > maxMessageLength {
+		text = text[:maxMessageLength] + "…"
+	}
+
+	a := annotation.Annotation{Text: text, Source: Source}
+	if micros, err := strconv.ParseInt(entry.Realtime, 10, 64); err == nil {
+		a.Time = time.UnixMicro(micros)
+	}
+	return a, true
+}
+
+// unitName completes the unit name with the '.service' suffix systemd logs it with, e.g. 'geth' is 'geth.service'
+func unitName(unit string) string {
+	if strings.Contains(unit, ".") {
+		return unit
+	}
+	return unit + ".service"
+}
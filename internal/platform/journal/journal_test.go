@@ -0,0 +1,56 @@
+package journal
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGivenUnitLifecycleEntriesWhenAnnotateThenAnnotated(t *testing.T) {
+	tests := []struct {
+		message  string
+		expected string
+	}{
+		{"geth.service: Scheduled restart job, restart counter is at 3.", "geth.service: Scheduled restart job, restart counter is at 3."},
+		{"Started geth.service - Geth execution client.", "geth.service: Started geth.service - Geth execution client."},
+		{"geth.service: Main process exited, code=killed, status=9/KILL", "geth.service: Main process exited, code=killed, status=9/KILL"},
+		{"geth.service: Failed with result 'oom-kill'.", "geth.service: Failed with result 'oom-kill'."},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.message, func(t *testing.T) {
+			a, ok := Annotate(Entry{Message: tt.message, Unit: "geth.service", Realtime: "1717243200000000"})
+
+			assert.True(t, ok)
+			assert.Equal(t, tt.expected, a.Text)
+			assert.Equal(t, Source, a.Source)
+			assert.Equal(t, time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC), a.Time.UTC())
+		})
+	}
+}
+
+func TestGivenKernelEntriesWhenAnnotateThenOOMKillsAndSegfaultsAnnotated(t *testing.T) {
+	a, ok := Annotate(Entry{Transport: "kernel", Message: "Out of memory: Killed process 1234 (geth) total-vm:33554432kB, anon-rss:30000000kB"})
+	assert.True(t, ok)
+	assert.Equal(t, "OOM kill of geth", a.Text)
+
+	a, ok = Annotate(Entry{Transport: "kernel", Message: "lighthouse[4321]: segfault at 0 ip 000055d5 sp 00007ffc error 4 in lighthouse"})
+	assert.True(t, ok)
+	assert.Equal(t, "segfault of lighthouse", a.Text)
+
+	_, ok = Annotate(Entry{Transport: "kernel", Message: "EXT4-fs (nvme0n1p2): mounted filesystem"})
+	assert.False(t, ok)
+}
+
+func TestGivenUnrelatedUnitMessageWhenAnnotateThenNone(t *testing.T) {
+	_, ok := Annotate(Entry{Unit: "geth.service", Message: "Consumed 1h 2min CPU time."})
+
+	assert.False(t, ok)
+}
+
+func TestGivenUnitWithoutSuffixWhenUnitNameThenServiceSuffixAdded(t *testing.T) {
+	assert.Equal(t, "geth.service", unitName("geth"))
+	assert.Equal(t, "lighthouse-beacon.service", unitName("lighthouse-beacon.service"))
+	assert.Equal(t, "geth.scope", unitName("geth.scope"))
+}
@@ -25,3 +25,19 @@ func ListenForApplicationShutDown(ctx context.Context, shutdownFunc func(), sign
 		time.Sleep(terminationDelay)
 	}
 }
+
+// ListenForInterimReport calls the report function on every SIGUSR1 until the context is done, without ending the run
+func ListenForInterimReport(ctx context.Context, reportFunc func(), signalChannel chan os.Signal) {
+	signal.Notify(signalChannel, syscall.SIGUSR1)
+	defer signal.Stop(signalChannel)
+
+	for {
+		select {
+		case sig := <-signalChannel:
+			slog.With("sig", sig.String()).Info("interim report signal received")
+			reportFunc()
+		case <-ctx.Done():
+			return
+		}
+	}
+}
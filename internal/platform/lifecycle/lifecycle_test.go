@@ -27,3 +27,17 @@ func Test_GivenTerminationSignal_WhenListenForShutdown_ThenCallsTheFunctionPasse
 
 	assert.True(t, shutdownFuncCalled)
 }
+
+func Test_GivenInterimReportSignal_WhenListenForInterimReport_ThenCallsTheFunctionWithoutStopping(t *testing.T) {
+	reportSignal := make(chan os.Signal, 1)
+	reported := make(chan struct{})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go ListenForInterimReport(ctx, func() { reported <- struct{}{} }, reportSignal)
+
+	reportSignal <- syscall.SIGUSR1
+	<-reported
+	reportSignal <- syscall.SIGUSR1
+	<-reported
+}
@@ -1,10 +1,14 @@
 package logger
 
 import (
+	"errors"
+	"io"
 	"log/slog"
 	"os"
 	"strings"
 
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/availability"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/breaker"
 	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/metric"
 )
 
@@ -13,7 +17,20 @@ func init() {
 	slog.SetDefault(logger)
 }
 
+// AddOutput duplicates the application logs to the passed writer, e.g. the log file of a run
+func AddOutput(w io.Writer) {
+	logger := slog.New(slog.NewJSONHandler(io.MultiWriter(os.Stdout, w), &slog.HandlerOptions{Level: slog.LevelDebug}))
+	slog.SetDefault(logger)
+}
+
+// AvailabilityTarget is the name under which the measurement failures of a metric are tracked
+func AvailabilityTarget(metricGroup metric.Group, metricName string) string {
+	return string(metricGroup) + " / " + metricName
+}
+
 func WriteMetric(metricGroup metric.Group, metricName string, nameValue map[string]any) {
+	availability.Success(AvailabilityTarget(metricGroup, metricName))
+
 	logger := slog.Default()
 
 	logger.
@@ -24,9 +41,17 @@ func WriteMetric(metricGroup metric.Group, metricName string, nameValue map[stri
 }
 
 func WriteError(metricGroup metric.Group, metricName string, err error) {
-	slog.
+	availability.Failure(AvailabilityTarget(metricGroup, metricName))
+
+	logger := slog.
 		With("err", err.Error()).
 		With("metric_group", strings.ToLower(string(metricGroup))).
-		With("metric_name", strings.ToLower(string(metricName))).
-		Error("error")
+		With("metric_name", strings.ToLower(string(metricName)))
+
+	// Skipped measurements of a backed off endpoint would flood the logs, the outage itself is logged once by its breaker
+	if errors.Is(err, breaker.ErrOpen) {
+		logger.Debug("measurement skipped")
+		return
+	}
+	logger.Error("error")
 }
@@ -0,0 +1,18 @@
+package metric
+
+// EvaluateAggregated evaluates the health conditions against the aggregate of the data points of the evaluated window
+// instead of every data point, e.g. the percentiles of single readings. Trends still see every data point.
+func (bm *Base[T]) EvaluateAggregated(aggregate func(dataPoints []DataPoint[T]) map[string]T) {
+	bm.mutex.Lock()
+	defer bm.mutex.Unlock()
+
+	bm.aggregate = aggregate
+}
+
+// aggregated replaces the data points with their aggregate, taken at the time of the latest one
+func aggregated[T any](dataPoints []DataPoint[T], aggregate func(dataPoints []DataPoint[T]) map[string]T) []DataPoint[T] {
+	if aggregate == nil || len(dataPoints) == 0 {
+		return dataPoints
+	}
+	return []DataPoint[T]{{Timestamp: dataPoints[len(dataPoints)-1].Timestamp, Values: aggregate(dataPoints)}}
+}
@@ -0,0 +1,59 @@
+package metric
+
+import "time"
+
+// Window is a period in which deviating readings are expected, e.g. the resource spike of a validator proposing
+type Window struct {
+	Start  time.Time
+	End    time.Time
+	Reason string
+}
+
+// Covers tells whether the reading taken at the time falls within the window
+func (w Window) Covers(at time.Time) bool {
+	return !at.Before(w.Start) && at.Before(w.End)
+}
+
+// ExpectDuring excludes the readings taken within the windows from the health evaluation, they are still recorded and
+// exported. The windows are requested on every evaluation, so their source can learn about new ones during the run.
+func (bm *Base[T]) ExpectDuring(windows func() []Window) {
+	bm.mutex.Lock()
+	defer bm.mutex.Unlock()
+
+	bm.expected = windows
+}
+
+// ExpectDuring excludes the readings taken within the windows from the health evaluation, they are still recorded and
+// exported. The windows are requested on every evaluation, so their source can learn about new ones during the run.
+func (bm *ValueBase) ExpectDuring(windows func() []Window) {
+	bm.mutex.Lock()
+	defer bm.mutex.Unlock()
+
+	bm.expected = windows
+}
+
+// unexpected drops the data points taken within the windows
+func unexpected[T any](dataPoints []DataPoint[T], windows func() []Window) []DataPoint[T] {
+	if windows == nil {
+		return dataPoints
+	}
+	expected := windows()
+	if len(expected) == 0 {
+		return dataPoints
+	}
+
+	kept := make([]DataPoint[T], 0, len(dataPoints))
+	for _, dp := range dataPoints {
+		covered := false
+		for _, window := range expected {
+			if window.Covers(dp.Timestamp) {
+				covered = true
+				break
+			}
+		}
+		if !covered {
+			kept = append(kept, dp)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,35 @@
+package metric
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGivenReadingWithinExpectedWindowWhenEvaluatedThenItIsIgnored(t *testing.T) {
+	base := Base[float64]{
+		Name:             "CPU",
+		HealthConditions: []HealthCondition[float64]{{Name: "User", Threshold: 90, Operator: OperatorGreaterThanOrEqual, Severity: SeverityHigh}},
+	}
+	base.AddDataPoint(map[string]float64{"User": 99})
+	base.ExpectDuring(func() []Window {
+		return []Window{{Start: time.Now().Add(-time.Minute), End: time.Now().Add(time.Minute), Reason: "proposal"}}
+	})
+
+	assert.Equal(t, Healthy, base.EvaluateMetric().Health)
+	assert.Len(t, base.ExportDataPoints(), 1, "expected readings are still exported")
+}
+
+func TestGivenReadingOutsideExpectedWindowWhenEvaluatedThenItIsFlagged(t *testing.T) {
+	base := ValueBase{
+		Name:             "CPU",
+		HealthConditions: []ValueCondition{{Name: "User", Threshold: Number(90), Operator: OperatorGreaterThanOrEqual, Severity: SeverityHigh}},
+	}
+	base.AddDataPoint(map[string]Value{"User": Number(99)})
+	base.ExpectDuring(func() []Window {
+		return []Window{{Start: time.Now().Add(time.Minute), End: time.Now().Add(time.Hour), Reason: "proposal"}}
+	})
+
+	assert.Equal(t, Unhealthy, base.EvaluateMetric().Health)
+}
@@ -0,0 +1,131 @@
+package metric
+
+import (
+	"fmt"
+	"maps"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+// Fault replaces the readings of a measurement with a degraded value while it is active, so operators can verify
+// their health conditions and alerts trigger as configured
+type Fault struct {
+	Measurement string
+	// Value is parsed as the type of the readings, e.g. '5s' for latencies or '0' for peer counts
+	Value string
+	Start time.Time
+	// End is zero for faults lasting until the run ends
+	End time.Time
+}
+
+// Active tells whether the fault degrades readings taken at the time
+func (f Fault) Active(at time.Time) bool {
+	return !at.Before(f.Start) && (f.End.IsZero() || at.Before(f.End))
+}
+
+// InjectFaults degrades the readings added from now on while the faults are active
+func (bm *Base[T]) InjectFaults(faults ...Fault) error {
+	values := make([]T, 0, len(faults))
+	for _, fault := range faults {
+		value, err := parseFaultValue[T](fault.Value)
+		if err != nil {
+			return fmt.Errorf("fault value '%s' of measurement '%s' of metric '%s' is invalid: %w", fault.Value, fault.Measurement, bm.Name, err)
+		}
+		values = append(values, value)
+	}
+
+	bm.mutex.Lock()
+	defer bm.mutex.Unlock()
+
+	bm.faults = append(bm.faults, faults...)
+	bm.faultValues = append(bm.faultValues, values...)
+	return nil
+}
+
+// InjectFaults degrades the readings added from now on while the faults are active. The value of a fault is parsed as
+// the kind of the reading it replaces, readings it is not valid for are kept.
+func (bm *ValueBase) InjectFaults(faults ...Fault) error {
+	bm.mutex.Lock()
+	defer bm.mutex.Unlock()
+
+	bm.faults = append(bm.faults, faults...)
+	return nil
+}
+
+// degrade returns the values with the readings of active faults replaced by the value of the fault, the passed values
+// are left unchanged. faultValue returns the value of the i-th fault replacing the reading, if it is valid for it.
+func degrade[T any](values map[string]T, faults []Fault, at time.Time, faultValue func(i int, reading T) (T, bool)) map[string]T {
+	var degraded map[string]T
+	for i, fault := range faults {
+		reading, ok := values[fault.Measurement]
+		if !ok || !fault.Active(at) {
+			continue
+		}
+		value, ok := faultValue(i, reading)
+		if !ok {
+			continue
+		}
+		if degraded == nil {
+			degraded = maps.Clone(values)
+		}
+		degraded[fault.Measurement] = value
+	}
+	if degraded == nil {
+		return values
+	}
+	return degraded
+}
+
+func parseFaultValue[T any](text string) (T, error) {
+	var value T
+	target := reflect.ValueOf(&value).Elem()
+	switch {
+	case target.Type() == reflect.TypeOf(time.Duration(0)):
+		duration, err := time.ParseDuration(text)
+		if err != nil {
+			return value, err
+		}
+		target.SetInt(int64(duration))
+	case target.CanInt():
+		number, err := strconv.ParseInt(text, 10, 64)
+		if err != nil {
+			return value, err
+		}
+		target.SetInt(number)
+	case target.CanUint():
+		number, err := strconv.ParseUint(text, 10, 64)
+		if err != nil {
+			return value, err
+		}
+		target.SetUint(number)
+	case target.CanFloat():
+		number, err := strconv.ParseFloat(text, 64)
+		if err != nil {
+			return value, err
+		}
+		target.SetFloat(number)
+	case target.Kind() == reflect.String:
+		target.SetString(text)
+	default:
+		return value, fmt.Errorf("readings of type %s can't be degraded", target.Type())
+	}
+	return value, nil
+}
+
+// parseValue parses the text as a value of the kind
+func parseValue(kind Kind, text string) (Value, error) {
+	switch kind {
+	case KindDuration:
+		duration, err := time.ParseDuration(text)
+		return Duration(duration), err
+	case KindString:
+		return String(text), nil
+	case KindBool:
+		b, err := strconv.ParseBool(text)
+		return Bool(b), err
+	default:
+		number, err := strconv.ParseFloat(text, 64)
+		return Number(number), err
+	}
+}
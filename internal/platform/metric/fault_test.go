@@ -0,0 +1,64 @@
+package metric
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGivenActiveFaultWhenDataPointAddedThenReadingIsDegraded(t *testing.T) {
+	base := Base[time.Duration]{Name: "Latency"}
+	require.NoError(t, base.InjectFaults(Fault{Measurement: "DurationP90", Value: "5s", Start: time.Now().Add(-time.Minute)}))
+
+	values := map[string]time.Duration{"DurationP90": time.Millisecond, "DurationP50": time.Millisecond}
+	base.AddDataPoint(values)
+
+	latest, ok := base.Latest()
+	require.True(t, ok)
+	assert.Equal(t, time.Second*5, latest.Values["DurationP90"])
+	assert.Equal(t, time.Millisecond, latest.Values["DurationP50"])
+	assert.Equal(t, time.Millisecond, values["DurationP90"], "the passed values must not be modified")
+}
+
+func TestGivenFaultNotActiveWhenDataPointAddedThenReadingIsKept(t *testing.T) {
+	base := Base[uint32]{Name: "Peers"}
+	require.NoError(t, base.InjectFaults(
+		Fault{Measurement: "PeerCount", Value: "0", Start: time.Now().Add(time.Hour)},
+		Fault{Measurement: "PeerCount", Value: "1", Start: time.Now().Add(-time.Hour), End: time.Now().Add(-time.Minute)},
+	))
+
+	base.AddDataPoint(map[string]uint32{"PeerCount": 50})
+
+	latest, ok := base.Latest()
+	require.True(t, ok)
+	assert.Equal(t, uint32(50), latest.Values["PeerCount"])
+}
+
+func TestGivenInvalidFaultValueWhenInjectedThenFails(t *testing.T) {
+	base := Base[uint32]{Name: "Peers"}
+	assert.Error(t, base.InjectFaults(Fault{Measurement: "PeerCount", Value: "-1"}))
+}
+
+func TestGivenValueBaseFaultWhenDataPointAddedThenParsedAsKindOfReading(t *testing.T) {
+	base := ValueBase{Name: "Client"}
+	require.NoError(t, base.InjectFaults(
+		Fault{Measurement: "Latency", Value: "2s"},
+		Fault{Measurement: "Version", Value: "2s"},
+		Fault{Measurement: "Synced", Value: "2s"},
+	))
+
+	base.AddDataPoint(map[string]Value{
+		"Latency": Duration(time.Millisecond),
+		"Version": String("Lighthouse/v5.3.0"),
+		"Synced":  Bool(true),
+	})
+
+	latency, _ := base.Last("Latency")
+	version, _ := base.Last("Version")
+	synced, _ := base.Last("Synced")
+	assert.Equal(t, time.Second*2, latency.Duration())
+	assert.Equal(t, "2s", version.Any())
+	assert.True(t, synced.Bool(), "values invalid for the kind of the reading must be ignored")
+}
@@ -0,0 +1,58 @@
+package metric
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"runtime/debug"
+	"sync"
+)
+
+// Goroutines runs the goroutines of a metric which live as long as its measurement, e.g. an event stream along with
+// a slot loop. Once one of them panics the others are stopped and Wait raises the panic again, so the measurement
+// panics and is restarted as a whole instead of going on with a part of it stopped.
+type Goroutines struct {
+	name      string
+	cancel    context.CancelFunc
+	wait      sync.WaitGroup
+	once      sync.Once
+	recovered any
+}
+
+// NewGoroutines returns the goroutines along with the context they run with, which is done once one of them panicked
+func NewGoroutines(ctx context.Context, name string) (*Goroutines, context.Context) {
+	ctx, cancel := context.WithCancel(ctx)
+	return &Goroutines{name: name, cancel: cancel}, ctx
+}
+
+// Go runs the function in a goroutine, a panic stops the others
+func (g *Goroutines) Go(f func()) {
+	g.wait.Add(1)
+	go func() {
+		defer g.wait.Done()
+		defer func() {
+			recovered := recover()
+			if recovered == nil {
+				return
+			}
+			// The stack is lost once the panic is raised again
+			slog.
+				With("metric_name", g.name).
+				With("panic", fmt.Sprint(recovered)).
+				With("stack", string(debug.Stack())).
+				Debug("goroutine of the metric panicked, stopping the others")
+			g.once.Do(func() { g.recovered = recovered })
+			g.cancel()
+		}()
+		f()
+	}()
+}
+
+// Wait returns once all goroutines returned, it panics with the first panic of them
+func (g *Goroutines) Wait() {
+	g.wait.Wait()
+	g.cancel()
+	if g.recovered != nil {
+		panic(g.recovered)
+	}
+}
@@ -0,0 +1,32 @@
+package metric
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGivenPanickingGoroutineWhenWaitThenOthersStoppedAndPanicRaised(t *testing.T) {
+	goroutines, ctx := NewGoroutines(context.Background(), "Attestation")
+	stopped := make(chan struct{})
+
+	goroutines.Go(func() {
+		<-ctx.Done()
+		close(stopped)
+	})
+	goroutines.Go(func() { panic("nil pointer") })
+
+	assert.PanicsWithValue(t, "nil pointer", goroutines.Wait)
+	assert.NotPanics(t, func() { <-stopped })
+}
+
+func TestGivenGoroutinesWhenContextDoneThenWaitReturns(t *testing.T) {
+	parent, cancel := context.WithCancel(context.Background())
+	goroutines, ctx := NewGoroutines(parent, "Attestation")
+
+	goroutines.Go(func() { <-ctx.Done() })
+	cancel()
+
+	assert.NotPanics(t, goroutines.Wait)
+}
@@ -5,5 +5,18 @@ type Group string
 const (
 	ConsensusGroup      Group = "Consensus"
 	ExecutionGroup      Group = "Execution"
+	ValidatorGroup      Group = "Validator"
 	InfrastructureGroup Group = "Infrastructure"
+	// ObserverGroup holds the resource usage of the benchmark tool itself
+	ObserverGroup Group = "Observer"
+	// AvailabilityGroup holds the uptime and outage windows of endpoints and metrics, reports render it as its own section
+	AvailabilityGroup Group = "Availability"
+	// SecurityGroup holds the findings of the one-shot security checks at the start of the run, e.g. exposed ports
+	SecurityGroup Group = "Security"
+	// EpochsGroup holds the per-epoch breakdown of the consensus measurements, reports render it as an appendix section
+	EpochsGroup Group = "Epochs"
+	// TimelineGroup holds the health of every metric over the course of the run, reports render it as an appendix section
+	TimelineGroup Group = "Timeline"
+	// RulesGroup holds the outcome of the health rules spanning several metrics, reports render it as its own section
+	RulesGroup Group = "Rules"
 )
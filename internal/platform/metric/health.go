@@ -1,5 +1,11 @@
 package metric
 
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
 type (
 	HealthStatus  string
 	SeverityLevel string
@@ -46,12 +52,74 @@ func (c HealthCondition[T]) Evaluate(value T) bool {
 	}
 }
 
-var severityOrder = map[SeverityLevel]int{
+type (
+	// ConditionResult explains a health condition which was met by at least one data point
+	ConditionResult struct {
+		Measurement string
+		Operator    Operator
+		Threshold   string
+		// Observed is the last value which met the condition
+		Observed    string
+		Severity    SeverityLevel
+		Occurrences int
+	}
+
+	// Evaluation is the health of a metric along with the highest severity per measurement and the conditions causing it
+	Evaluation struct {
+		Health     HealthStatus
+		Severities map[string]SeverityLevel
+		Conditions []ConditionResult
+	}
+)
+
+var severityWeights = struct {
+	weights map[SeverityLevel]int
+	mutex   sync.RWMutex
+}{weights: map[SeverityLevel]int{
 	SeverityLow:    1,
 	SeverityMedium: 2,
 	SeverityHigh:   3,
+}}
+
+// SetSeverityWeight defines a custom severity level or changes the weight of an existing one.
+// Weights order the levels and add up to the score of an evaluation.
+func SetSeverityWeight(level SeverityLevel, weight int) {
+	severityWeights.mutex.Lock()
+	defer severityWeights.mutex.Unlock()
+
+	severityWeights.weights[level] = weight
+}
+
+// SeverityWeight returns the weight of the level, unknown levels and SeverityNone weigh 0
+func SeverityWeight(level SeverityLevel) int {
+	severityWeights.mutex.RLock()
+	defer severityWeights.mutex.RUnlock()
+
+	return severityWeights.weights[level]
 }
 
 func CompareSeverities(a, b SeverityLevel) int {
-	return severityOrder[a] - severityOrder[b]
+	return SeverityWeight(a) - SeverityWeight(b)
+}
+
+// Score sums up the weights of the highest severity of every measurement
+func (e Evaluation) Score() int {
+	var score int
+	for _, severity := range e.Severities {
+		score += SeverityWeight(severity)
+	}
+	return score
+}
+
+func (r ConditionResult) String() string {
+	return fmt.Sprintf("%s %s %s (%s: observed %s, %dx)", r.Measurement, r.Operator, r.Threshold, r.Severity, r.Observed, r.Occurrences)
+}
+
+// Reasons describes every condition met, most severe first
+func (e Evaluation) Reasons() string {
+	reasons := make([]string, 0, len(e.Conditions))
+	for _, condition := range e.Conditions {
+		reasons = append(reasons, condition.String())
+	}
+	return strings.Join(reasons, " \n ")
 }
@@ -0,0 +1,37 @@
+package metric
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGivenConditionsMetWhenEvaluateMetricThenExplainsConditions(t *testing.T) {
+	base := Base[int]{
+		HealthConditions: []HealthCondition[int]{
+			{Name: "Peers", Threshold: 20, Operator: OperatorLessThanOrEqual, Severity: SeverityMedium},
+			{Name: "Peers", Threshold: 5, Operator: OperatorLessThanOrEqual, Severity: SeverityHigh},
+		},
+	}
+	base.AddDataPoint(map[string]int{"Peers": 50})
+	base.AddDataPoint(map[string]int{"Peers": 10})
+	base.AddDataPoint(map[string]int{"Peers": 3})
+
+	evaluation := base.EvaluateMetric()
+
+	assert.Equal(t, Unhealthy, evaluation.Health)
+	assert.Equal(t, SeverityHigh, evaluation.Severities["Peers"])
+	assert.Equal(t, []ConditionResult{
+		{Measurement: "Peers", Operator: OperatorLessThanOrEqual, Threshold: "5", Observed: "3", Severity: SeverityHigh, Occurrences: 1},
+		{Measurement: "Peers", Operator: OperatorLessThanOrEqual, Threshold: "20", Observed: "3", Severity: SeverityMedium, Occurrences: 2},
+	}, evaluation.Conditions)
+	assert.Equal(t, 3, evaluation.Score())
+}
+
+func TestGivenCustomSeverityWhenCompareSeveritiesThenUsesWeight(t *testing.T) {
+	const critical SeverityLevel = "Critical"
+	SetSeverityWeight(critical, 10)
+
+	assert.Greater(t, CompareSeverities(critical, SeverityHigh), 0)
+	assert.Equal(t, 10, Evaluation{Severities: map[string]SeverityLevel{"A": critical, "B": SeverityNone}}.Score())
+}
@@ -0,0 +1,82 @@
+package metric
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/format"
+)
+
+const (
+	HistogramBuckets = 8
+	// histogramWidth is the number of characters of the longest bar
+	histogramWidth = 20
+)
+
+// Bucket counts the durations up to its upper bound which exceed the bound of the previous bucket
+type Bucket struct {
+	UpTo  time.Duration
+	Count int
+}
+
+// Histogram counts the durations in buckets growing exponentially from the shortest to the longest duration, so a
+// few stalls of seconds next to latencies of milliseconds get buckets of their own
+func Histogram(durations []time.Duration, buckets int) []Bucket {
+	if len(durations) == 0 || buckets < 1 {
+		return nil
+	}
+
+	shortest, longest := durations[0], durations[0]
+	for _, duration := range durations {
+		shortest, longest = min(shortest, duration), max(longest, duration)
+	}
+	shortest = max(shortest, time.Microsecond)
+	if longest <= shortest {
+		return []Bucket{{UpTo: longest, Count: len(durations)}}
+	}
+
+	growth := math.Pow(float64(longest)/float64(shortest), 1/float64(buckets))
+	histogram := make([]Bucket, buckets)
+	for i := range histogram {
+		histogram[i].UpTo = time.Duration(float64(shortest) * math.Pow(growth, float64(i+1)))
+	}
+	// Rounding must not leave the longest duration out
+	histogram[buckets-1].UpTo = longest
+
+	for _, duration := range durations {
+		for i := range histogram {
+			if duration <= histogram[i].UpTo {
+				histogram[i].Count++
+				break
+			}
+		}
+	}
+	return histogram
+}
+
+// FormatHistogram renders every bucket as a line with a bar scaled to the fullest bucket, non-empty buckets have a bar
+// of at least one character
+func FormatHistogram(histogram []Bucket) []string {
+	fullest := 0
+	for _, bucket := range histogram {
+		fullest = max(fullest, bucket.Count)
+	}
+
+	lines := make([]string, 0, len(histogram))
+	for _, bucket := range histogram {
+		bar := 0
+		if fullest != 0 {
+			bar = int(math.Round(float64(bucket.Count) / float64(fullest) * histogramWidth))
+		}
+		if bucket.Count != 0 {
+			bar = max(bar, 1)
+		}
+		lines = append(lines, fmt.Sprintf("<=%s |%-*s| %s",
+			format.Pad(format.Duration(bucket.UpTo), format.ValueWidth),
+			histogramWidth, strings.Repeat("#", bar),
+			format.Pad(fmt.Sprintf("%d", bucket.Count), 5)))
+	}
+	return lines
+}
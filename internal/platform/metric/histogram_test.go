@@ -0,0 +1,46 @@
+package metric
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGivenBimodalDurationsWhenHistogramThenStallsGetOwnBucket(t *testing.T) {
+	durations := []time.Duration{}
+	for i := 0; i < 90; i++ {
+		durations = append(durations, 10*time.Millisecond)
+	}
+	for i := 0; i < 10; i++ {
+		durations = append(durations, 2*time.Second)
+	}
+
+	histogram := Histogram(durations, 4)
+
+	require.Len(t, histogram, 4)
+	assert.Equal(t, 90, histogram[0].Count)
+	assert.Equal(t, 0, histogram[1].Count)
+	assert.Equal(t, 0, histogram[2].Count)
+	assert.Equal(t, 10, histogram[3].Count)
+	assert.Equal(t, 2*time.Second, histogram[3].UpTo)
+}
+
+func TestGivenEqualDurationsWhenHistogramThenSingleBucket(t *testing.T) {
+	histogram := Histogram([]time.Duration{time.Millisecond, time.Millisecond}, 8)
+
+	assert.Equal(t, []Bucket{{UpTo: time.Millisecond, Count: 2}}, histogram)
+	assert.Empty(t, Histogram(nil, 8))
+}
+
+func TestGivenBucketsWhenFormatHistogramThenBarsScaledToFullestBucket(t *testing.T) {
+	lines := FormatHistogram([]Bucket{{UpTo: time.Millisecond, Count: 100}, {UpTo: time.Second, Count: 1}, {UpTo: 2 * time.Second}})
+
+	require.Len(t, lines, 3)
+	assert.Equal(t, histogramWidth, strings.Count(lines[0], "#"))
+	assert.Equal(t, 1, strings.Count(lines[1], "#"))
+	assert.Equal(t, 0, strings.Count(lines[2], "#"))
+	assert.Equal(t, len(lines[0]), len(lines[2]))
+}
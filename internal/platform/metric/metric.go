@@ -1,6 +1,9 @@
 package metric
 
 import (
+	"fmt"
+	"sort"
+	"sync"
 	"time"
 
 	"golang.org/x/exp/constraints"
@@ -11,53 +14,233 @@ type (
 		constraints.Integer | constraints.Float | ~string
 	}
 
+	// Base records the data points of a metric. Collectors add data points from their own goroutine while reports
+	// and exports read them, so DataPoints is guarded by the mutex and must be read through Snapshot.
 	Base[T Metricable] struct {
 		Name             string
 		DataPoints       []DataPoint[T]
 		HealthConditions []HealthCondition[T]
+		mutex            sync.RWMutex
+		// faults degrade the readings, faultValues holds the value of every fault parsed as T
+		faults      []Fault
+		faultValues []T
+		// timeout bounds a single measurement, zero keeps the default of the metric
+		timeout time.Duration
+		// warmUp skips the measurement at the start of the run
+		warmUp bool
+		// expected returns the windows whose readings are left out of the health evaluation
+		expected func() []Window
+		trends   []TrendCondition
+		// aggregate replaces the data points of an evaluation, see EvaluateAggregated
+		aggregate func(dataPoints []DataPoint[T]) map[string]T
 	}
 
-	DataPoint[T Metricable] struct {
+	DataPoint[T any] struct {
 		Timestamp time.Time
 		Values    map[string]T
 	}
+
+	// ExportedDataPoint is the type agnostic representation of a data point used by raw exports
+	ExportedDataPoint struct {
+		Timestamp time.Time      `json:"timestamp"`
+		Values    map[string]any `json:"values"`
+	}
 )
 
 func (bm *Base[T]) GetName() string {
 	return bm.Name
 }
 
+// AddDataPoint records the values, which must not be modified afterwards
 func (bm *Base[T]) AddDataPoint(values map[string]T) {
+	bm.mutex.Lock()
+	defer bm.mutex.Unlock()
+
+	now := time.Now()
 	bm.DataPoints = append(bm.DataPoints, DataPoint[T]{
-		Timestamp: time.Now(),
-		Values:    values,
+		Timestamp: now,
+		Values: degrade(values, bm.faults, now, func(i int, _ T) (T, bool) {
+			return bm.faultValues[i], true
+		}),
+	})
+}
+
+// Snapshot returns the data points measured so far, safe to read while new ones are added
+func (bm *Base[T]) Snapshot() []DataPoint[T] {
+	bm.mutex.RLock()
+	defer bm.mutex.RUnlock()
+
+	return snapshot(bm.DataPoints)
+}
+
+// Latest returns the most recent data point
+func (bm *Base[T]) Latest() (DataPoint[T], bool) {
+	bm.mutex.RLock()
+	defer bm.mutex.RUnlock()
+
+	if len(bm.DataPoints) == 0 {
+		return DataPoint[T]{}, false
+	}
+	return bm.DataPoints[len(bm.DataPoints)-1], true
+}
+
+// Samples returns the number of data points measured so far
+func (bm *Base[T]) Samples() int {
+	bm.mutex.RLock()
+	defer bm.mutex.RUnlock()
+
+	return len(bm.DataPoints)
+}
+
+func (bm *Base[T]) ExportDataPoints() []ExportedDataPoint {
+	return export(bm.Snapshot(), func(value T) any { return value })
+}
+
+// snapshot limits the capacity of the data points, so appending to either the snapshot or the recorded data points
+// never writes to the other. Data points are never modified once added, the shared elements can be read concurrently.
+func snapshot[T any](dataPoints []DataPoint[T]) []DataPoint[T] {
+	return dataPoints[:len(dataPoints):len(dataPoints)]
+}
+
+func export[T any](dataPoints []DataPoint[T], native func(T) any) []ExportedDataPoint {
+	exported := make([]ExportedDataPoint, 0, len(dataPoints))
+	for _, dp := range dataPoints {
+		values := make(map[string]any, len(dp.Values))
+		for name, value := range dp.Values {
+			values[name] = native(value)
+		}
+		exported = append(exported, ExportedDataPoint{
+			Timestamp: dp.Timestamp,
+			Values:    values,
+		})
+	}
+	return exported
+}
+
+func (bm *Base[T]) EvaluateMetric() Evaluation {
+	return bm.EvaluateSince(time.Time{})
+}
+
+// EvaluateSince evaluates the data points measured from the time on, e.g. the latest readings of a running benchmark
+func (bm *Base[T]) EvaluateSince(since time.Time) Evaluation {
+	conditions := make([]conditionInfo, 0, len(bm.HealthConditions))
+	for _, condition := range bm.HealthConditions {
+		conditions = append(conditions, conditionInfo{
+			name:      condition.Name,
+			operator:  condition.Operator,
+			threshold: fmt.Sprint(condition.Threshold),
+			severity:  condition.Severity,
+		})
+	}
+	bm.mutex.RLock()
+	expected, trends, aggregate := bm.expected, bm.trends, bm.aggregate
+	bm.mutex.RUnlock()
+
+	dataPoints := unexpected(bm.Snapshot(), expected)
+	evaluation := evaluate(aggregated(measuredSince(dataPoints, since), aggregate), conditions, func(i int, value T) bool {
+		return bm.HealthConditions[i].Evaluate(value)
+	})
+	evaluateTrends(&evaluation, dataPoints, since, trends, func(value T) (float64, bool) {
+		return ToFloat(any(value))
+	})
+	return evaluation
+}
+
+// EvaluateBetween evaluates the health conditions against the data points measured from the start until the end, e.g.
+// a bucket of the health timeline. Trends span the whole run and aren't evaluated within the window.
+func (bm *Base[T]) EvaluateBetween(from, to time.Time) Evaluation {
+	conditions := make([]conditionInfo, 0, len(bm.HealthConditions))
+	for _, condition := range bm.HealthConditions {
+		conditions = append(conditions, conditionInfo{
+			name:      condition.Name,
+			operator:  condition.Operator,
+			threshold: fmt.Sprint(condition.Threshold),
+			severity:  condition.Severity,
+		})
+	}
+	bm.mutex.RLock()
+	expected, aggregate := bm.expected, bm.aggregate
+	bm.mutex.RUnlock()
+
+	dataPoints := aggregated(measuredBetween(unexpected(bm.Snapshot(), expected), from, to), aggregate)
+	return evaluate(dataPoints, conditions, func(i int, value T) bool {
+		return bm.HealthConditions[i].Evaluate(value)
+	})
+}
+
+// measuredSince drops the data points measured before the time, the data points are ordered by their timestamp
+func measuredSince[T any](dataPoints []DataPoint[T], since time.Time) []DataPoint[T] {
+	first := sort.Search(len(dataPoints), func(i int) bool {
+		return !dataPoints[i].Timestamp.Before(since)
+	})
+	return dataPoints[first:]
+}
+
+// measuredBetween keeps the data points measured from the start until the end, the end excluded
+func measuredBetween[T any](dataPoints []DataPoint[T], from, to time.Time) []DataPoint[T] {
+	dataPoints = measuredSince(dataPoints, from)
+	last := sort.Search(len(dataPoints), func(i int) bool {
+		return !dataPoints[i].Timestamp.Before(to)
 	})
+	return dataPoints[:last]
 }
 
-func (bm *Base[T]) EvaluateMetric() (HealthStatus, map[string]SeverityLevel) {
-	overallHealth := Healthy
-	maxSeverities := make(map[string]SeverityLevel)
+// conditionInfo describes a health condition independently of the type of its threshold
+type conditionInfo struct {
+	name      string
+	operator  Operator
+	threshold string
+	severity  SeverityLevel
+}
 
-	for _, dp := range bm.DataPoints {
+// evaluate checks every data point against the conditions, met reports whether the value meets the i-th condition
+func evaluate[T any](dataPoints []DataPoint[T], conditions []conditionInfo, met func(i int, value T) bool) Evaluation {
+	evaluation := Evaluation{
+		Health:     Healthy,
+		Severities: make(map[string]SeverityLevel),
+	}
+
+	for _, dp := range dataPoints {
 		for name := range dp.Values {
-			maxSeverities[name] = SeverityNone
+			evaluation.Severities[name] = SeverityNone
 		}
 	}
 
-	for _, dp := range bm.DataPoints {
+	results := make([]*ConditionResult, len(conditions))
+	for _, dp := range dataPoints {
 		for name, value := range dp.Values {
-			for _, condition := range bm.HealthConditions {
-				if condition.Name == name {
-					if condition.Evaluate((value)) {
-						overallHealth = Unhealthy
-						if CompareSeverities(condition.Severity, maxSeverities[name]) > 0 {
-							maxSeverities[name] = condition.Severity
-						}
+			for i, condition := range conditions {
+				if condition.name != name || !met(i, value) {
+					continue
+				}
+
+				evaluation.Health = Unhealthy
+				if CompareSeverities(condition.severity, evaluation.Severities[name]) > 0 {
+					evaluation.Severities[name] = condition.severity
+				}
+
+				if results[i] == nil {
+					results[i] = &ConditionResult{
+						Measurement: name,
+						Operator:    condition.operator,
+						Threshold:   condition.threshold,
+						Severity:    condition.severity,
 					}
 				}
+				results[i].Observed = fmt.Sprint(value)
+				results[i].Occurrences++
 			}
 		}
 	}
 
-	return overallHealth, maxSeverities
+	for _, result := range results {
+		if result != nil {
+			evaluation.Conditions = append(evaluation.Conditions, *result)
+		}
+	}
+	sort.SliceStable(evaluation.Conditions, func(i, j int) bool {
+		return CompareSeverities(evaluation.Conditions[i].Severity, evaluation.Conditions[j].Severity) > 0
+	})
+
+	return evaluation
 }
@@ -0,0 +1,89 @@
+package metric
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGivenSnapshotWhenDataPointsAddedThenSnapshotUnchanged(t *testing.T) {
+	base := Base[int]{}
+	base.AddDataPoint(map[string]int{"Peers": 1})
+
+	snapshot := base.Snapshot()
+	base.AddDataPoint(map[string]int{"Peers": 2})
+	snapshot = append(snapshot, DataPoint[int]{Values: map[string]int{"Peers": 3}})
+
+	assert.Len(t, base.Snapshot(), 2)
+	assert.Equal(t, 2, base.Snapshot()[1].Values["Peers"])
+	assert.Equal(t, 3, snapshot[1].Values["Peers"])
+}
+
+func TestGivenNoTimeoutWhenTimeoutThenReturnsDefault(t *testing.T) {
+	base := Base[int]{}
+
+	assert.Equal(t, 5*time.Second, base.Timeout(5*time.Second))
+
+	base.SetTimeout(2 * time.Second)
+	assert.Equal(t, 2*time.Second, base.Timeout(5*time.Second))
+}
+
+func TestGivenOlderUnhealthyDataPointWhenEvaluateSinceThenOnlyLaterOnesEvaluated(t *testing.T) {
+	now := time.Now()
+	base := Base[int]{
+		Name: "Peers",
+		DataPoints: []DataPoint[int]{
+			{Timestamp: now.Add(-time.Minute), Values: map[string]int{"PeerCount": 0}},
+			{Timestamp: now, Values: map[string]int{"PeerCount": 50}},
+		},
+		HealthConditions: []HealthCondition[int]{{Name: "PeerCount", Threshold: 10, Operator: OperatorLessThan, Severity: SeverityHigh}},
+	}
+
+	assert.Equal(t, Unhealthy, base.EvaluateMetric().Health)
+	assert.Equal(t, Healthy, base.EvaluateSince(now.Add(-time.Second)).Health)
+}
+
+func TestGivenUnhealthyDataPointWhenEvaluateBetweenThenOnlyItsWindowUnhealthy(t *testing.T) {
+	now := time.Now()
+	base := Base[int]{
+		Name: "Peers",
+		DataPoints: []DataPoint[int]{
+			{Timestamp: now.Add(-2 * time.Minute), Values: map[string]int{"PeerCount": 50}},
+			{Timestamp: now.Add(-time.Minute), Values: map[string]int{"PeerCount": 0}},
+			{Timestamp: now, Values: map[string]int{"PeerCount": 50}},
+		},
+		HealthConditions: []HealthCondition[int]{{Name: "PeerCount", Threshold: 10, Operator: OperatorLessThan, Severity: SeverityHigh}},
+	}
+
+	assert.Equal(t, Healthy, base.EvaluateBetween(now.Add(-2*time.Minute), now.Add(-time.Minute)).Health)
+	evaluation := base.EvaluateBetween(now.Add(-time.Minute), now)
+	assert.Equal(t, Unhealthy, evaluation.Health)
+	assert.Equal(t, SeverityHigh, evaluation.Severities["PeerCount"])
+	assert.Equal(t, Healthy, base.EvaluateBetween(now, now.Add(time.Minute)).Health)
+}
+
+func TestGivenAggregatedEvaluationWhenEvaluatedThenConditionsMetByAggregateOnly(t *testing.T) {
+	now := time.Now()
+	base := Base[int]{
+		Name:             "Head Delay",
+		HealthConditions: []HealthCondition[int]{{Name: "HeadDelayMax", Threshold: 4, Operator: OperatorGreaterThanOrEqual, Severity: SeverityHigh}},
+	}
+	for i, delay := range []int{1, 2, 5} {
+		base.DataPoints = append(base.DataPoints, DataPoint[int]{Timestamp: now.Add(time.Duration(i) * time.Minute), Values: map[string]int{"HeadDelay": delay}})
+	}
+	base.EvaluateAggregated(func(dataPoints []DataPoint[int]) map[string]int {
+		var highest int
+		for _, dataPoint := range dataPoints {
+			highest = max(highest, dataPoint.Values["HeadDelay"])
+		}
+		return map[string]int{"HeadDelayMax": highest}
+	})
+
+	evaluation := base.EvaluateMetric()
+	assert.Equal(t, Unhealthy, evaluation.Health)
+	assert.Equal(t, "5", evaluation.Conditions[0].Observed)
+	assert.Equal(t, 1, evaluation.Conditions[0].Occurrences, "the aggregate is evaluated once")
+	assert.Equal(t, Healthy, base.EvaluateBetween(now, now.Add(2*time.Minute)).Health)
+	assert.Equal(t, Unhealthy, base.EvaluateSince(now.Add(2*time.Minute)).Health)
+}
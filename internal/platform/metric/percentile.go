@@ -3,6 +3,8 @@ package metric
 import (
 	"fmt"
 	"sort"
+
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/format"
 )
 
 type (
@@ -39,5 +41,10 @@ func CalculatePercentiles[T Metricable](values []T, percentiles ...float64) map[
 }
 
 func FormatPercentiles[T stringable](min, p10, p50, p90, max T) string {
-	return fmt.Sprintf("min=%v, p10=%v, p50=%v, p90=%v, max=%v", min, p10, p50, p90, max)
+	return fmt.Sprintf("min=%s, p10=%s, p50=%s, p90=%s, max=%s",
+		formatValue(min), formatValue(p10), formatValue(p50), formatValue(p90), formatValue(max))
+}
+
+func formatValue(value any) string {
+	return format.Pad(format.Value(value), format.ValueWidth)
 }
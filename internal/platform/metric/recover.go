@@ -0,0 +1,47 @@
+package metric
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"runtime/debug"
+)
+
+// PanicHandler is told about a panic recovered in a measurement or in a goroutine of a metric, with its stack
+type PanicHandler func(recovered any, stack []byte)
+
+type panicHandlerKey struct{}
+
+// WithPanicHandler reports the panics recovered while measuring with the context to the handler, e.g. to restart or
+// stop the metric
+func WithPanicHandler(ctx context.Context, handler PanicHandler) context.Context {
+	return context.WithValue(ctx, panicHandlerKey{}, handler)
+}
+
+// Recover recovers a panic of the metric when deferred, it is reported to the panic handler of the context or logged
+// without one
+func Recover(ctx context.Context, name string) {
+	recovered := recover()
+	if recovered == nil {
+		return
+	}
+	stack := debug.Stack()
+	if handler, ok := ctx.Value(panicHandlerKey{}).(PanicHandler); ok {
+		handler(recovered, stack)
+		return
+	}
+	slog.
+		With("metric_name", name).
+		With("panic", fmt.Sprint(recovered)).
+		With("stack", string(stack)).
+		Error("measurement panicked")
+}
+
+// Go runs the function in a goroutine of the metric, its panic is recovered like the one of a measurement instead of
+// crashing the run
+func Go(ctx context.Context, name string, f func()) {
+	go func() {
+		defer Recover(ctx, name)
+		f()
+	}()
+}
@@ -0,0 +1,82 @@
+package metric
+
+import (
+	"context"
+	"log/slog"
+	"math/rand/v2"
+	"time"
+)
+
+// defaultJitterFraction is the share of the interval the first tick of a runner is delayed by at most
+const defaultJitterFraction = 10
+
+// Runner measures right at the start and then on every interval until the context is done, so collectors only
+// implement a single measurement and short runs don't lose their first interval. The first tick is delayed by a random
+// jitter, so metrics of the same interval started together don't measure at the same moment and load the nodes with
+// spikes. A panicking measurement is recovered, see Recover, and the next tick measures again.
+type Runner struct {
+	name     string
+	interval time.Duration
+	jitter   time.Duration
+	warmUp   bool
+}
+
+// NewRunner measures at the start and every interval, with a jitter of up to a tenth of the interval. The timeout of
+// a measurement is applied by the measurement itself, see Base.MeasurementContext.
+func NewRunner(name string, interval time.Duration) *Runner {
+	return &Runner{
+		name:     name,
+		interval: interval,
+		jitter:   interval / defaultJitterFraction,
+	}
+}
+
+// WithJitter delays the first tick by a random duration up to the jitter, zero ticks in step with the start
+func (r *Runner) WithJitter(jitter time.Duration) *Runner {
+	r.jitter = jitter
+	return r
+}
+
+// WithWarmUp waits one interval before the first measurement, for metrics whose first readings are off, e.g. while
+// the node warms its caches
+func (r *Runner) WithWarmUp(warmUp bool) *Runner {
+	r.warmUp = warmUp
+	return r
+}
+
+// Run measures until the context is done
+func (r *Runner) Run(ctx context.Context, measureOnce func(ctx context.Context)) {
+	if !r.warmUp {
+		r.measure(ctx, measureOnce)
+	}
+
+	if r.jitter > 0 {
+		delay := time.NewTimer(rand.N(r.jitter))
+		select {
+		case <-ctx.Done():
+			delay.Stop()
+			slog.With("metric_name", r.name).Debug("metric was stopped")
+			return
+		case <-delay.C:
+		}
+	}
+
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			slog.With("metric_name", r.name).Debug("metric was stopped")
+			return
+		case <-ticker.C:
+			r.measure(ctx, measureOnce)
+		}
+	}
+}
+
+func (r *Runner) measure(ctx context.Context, measureOnce func(ctx context.Context)) {
+	defer Recover(ctx, r.name)
+
+	measureOnce(ctx)
+}
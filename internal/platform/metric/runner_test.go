@@ -0,0 +1,59 @@
+package metric
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGivenRunnerWhenRunThenMeasuredBeforeFirstInterval(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	var measured atomic.Int32
+
+	go NewRunner("Peers", time.Hour).Run(ctx, func(context.Context) {
+		measured.Add(1)
+		cancel()
+	})
+
+	assert.Eventually(t, func() bool { return measured.Load() == 1 }, time.Second, time.Millisecond*10)
+}
+
+func TestGivenPanickingMeasurementWhenRunThenNextTickMeasuresAgain(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+	var measured atomic.Int32
+
+	NewRunner("Peers", time.Millisecond*5).WithJitter(0).WithWarmUp(true).Run(ctx, func(context.Context) {
+		if measured.Add(1) == 3 {
+			cancel()
+		}
+		panic("nil pointer")
+	})
+
+	assert.Equal(t, int32(3), measured.Load())
+}
+
+func TestGivenWarmUpWhenContextCanceledThenReturnsWithoutMeasuring(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	measured := false
+
+	NewRunner("Peers", time.Hour).WithWarmUp(true).Run(ctx, func(context.Context) { measured = true })
+
+	assert.False(t, measured)
+}
+
+func TestGivenWarmUpSetWhenRunnerThenFirstMeasurementWaits(t *testing.T) {
+	base := Base[int]{Name: "Peers"}
+	assert.False(t, base.Runner(time.Second).warmUp)
+
+	base.SetWarmUp(true)
+	assert.True(t, base.Runner(time.Second).warmUp)
+
+	valueBase := ValueBase{Name: "Client"}
+	valueBase.SetWarmUp(true)
+	assert.True(t, valueBase.Runner(time.Second).warmUp)
+}
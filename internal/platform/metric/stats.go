@@ -0,0 +1,204 @@
+package metric
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+type (
+	// Summary describes the distribution of the samples of a measurement
+	Summary struct {
+		Count  int     `json:"count"`
+		Mean   float64 `json:"mean"`
+		StdDev float64 `json:"std_dev"`
+	}
+
+	// Interval is a 95% confidence interval
+	Interval struct {
+		Low  float64 `json:"low"`
+		High float64 `json:"high"`
+	}
+
+	// PercentileEstimate is a percentile of the samples along with the range the true percentile lies in
+	PercentileEstimate struct {
+		Percentile float64  `json:"percentile"`
+		Value      float64  `json:"value"`
+		CI95       Interval `json:"ci95"`
+	}
+
+	// Statistics of a measurement, so comparisons between runs can tell regressions from noise. Durations are in
+	// nanoseconds and booleans count as 0 or 1, like in the exported data points.
+	Statistics struct {
+		Summary
+		// CI95 is the confidence interval of the mean, missing for less than two samples
+		CI95        *Interval            `json:"ci95,omitempty"`
+		Percentiles []PercentileEstimate `json:"percentiles"`
+	}
+)
+
+// z95 is the two-sided 95% quantile of the standard normal distribution
+const z95 = 1.96
+
+// StatisticsPercentiles are the percentiles estimated by ComputeStatistics
+var StatisticsPercentiles = []float64{10, 50, 90}
+
+// tCritical95 are the two-sided 95% critical values of Student's t-distribution by degrees of freedom 1 to 30
+var tCritical95 = []float64{
+	12.706, 4.303, 3.182, 2.776, 2.571, 2.447, 2.365, 2.306, 2.262, 2.228,
+	2.201, 2.179, 2.160, 2.145, 2.131, 2.120, 2.110, 2.101, 2.093, 2.086,
+	2.080, 2.074, 2.069, 2.064, 2.060, 2.056, 2.052, 2.048, 2.045, 2.042,
+}
+
+// Summarize computes the sample count, mean and sample standard deviation of the values
+func Summarize(values []float64) Summary {
+	summary := Summary{Count: len(values)}
+	if summary.Count == 0 {
+		return summary
+	}
+
+	var sum float64
+	for _, value := range values {
+		sum += value
+	}
+	summary.Mean = sum / float64(summary.Count)
+
+	if summary.Count > 1 {
+		var squares float64
+		for _, value := range values {
+			squares += (value - summary.Mean) * (value - summary.Mean)
+		}
+		summary.StdDev = math.Sqrt(squares / float64(summary.Count-1))
+	}
+	return summary
+}
+
+// SignificantDifference tells whether the means of the summaries differ at the 95% level according to Welch's
+// t-test, which doesn't assume equal variances. A single sample on either side is never significant.
+func SignificantDifference(a, b Summary) bool {
+	if a.Count < 2 || b.Count < 2 || a.Mean == b.Mean {
+		return false
+	}
+
+	varA := a.StdDev * a.StdDev / float64(a.Count)
+	varB := b.StdDev * b.StdDev / float64(b.Count)
+	if varA+varB == 0 {
+		// Constant samples which differ are a real difference
+		return true
+	}
+
+	t := math.Abs(a.Mean-b.Mean) / math.Sqrt(varA+varB)
+	// Welch-Satterthwaite approximation of the degrees of freedom
+	df := (varA + varB) * (varA + varB) / (varA*varA/float64(a.Count-1) + varB*varB/float64(b.Count-1))
+	return t >= tCritical(df)
+}
+
+// tCritical returns the two-sided 95% critical value of the t-distribution, rounding the degrees of freedom down
+func tCritical(df float64) float64 {
+	if df < 1 {
+		return tCritical95[0]
+	}
+	if int(df) > len(tCritical95) {
+		return z95
+	}
+	return tCritical95[int(df)-1]
+}
+
+// MeanInterval returns the 95% confidence interval of the mean, which is unknown for less than two samples
+func (s Summary) MeanInterval() (Interval, bool) {
+	if s.Count < 2 {
+		return Interval{}, false
+	}
+	margin := tCritical(float64(s.Count-1)) * s.StdDev / math.Sqrt(float64(s.Count))
+	return Interval{Low: s.Mean - margin, High: s.Mean + margin}, true
+}
+
+// Deviation returns by how many standard deviations the value lies above (positive) or below (negative) the mean. It is
+// unknown for less than two samples and for constant samples, whose spread tells nothing about the value.
+func (s Summary) Deviation(value float64) (float64, bool) {
+	if s.Count < 2 || s.StdDev == 0 {
+		return 0, false
+	}
+	return (value - s.Mean) / s.StdDev, true
+}
+
+// ComputeStatistics computes the statistics of every numeric measurement of the data points
+func ComputeStatistics(dataPoints []ExportedDataPoint) map[string]Statistics {
+	samples := make(map[string][]float64)
+	for _, dp := range dataPoints {
+		for name, value := range dp.Values {
+			if number, ok := ToFloat(value); ok {
+				samples[name] = append(samples[name], number)
+			}
+		}
+	}
+
+	result := make(map[string]Statistics, len(samples))
+	for name, values := range samples {
+		summary := Summarize(values)
+		statistics := Statistics{
+			Summary:     summary,
+			Percentiles: EstimatePercentiles(values, StatisticsPercentiles...),
+		}
+		if interval, ok := summary.MeanInterval(); ok {
+			statistics.CI95 = &interval
+		}
+		result[name] = statistics
+	}
+	return result
+}
+
+// EstimatePercentiles picks the percentiles like CalculatePercentiles, each with the distribution-free confidence
+// interval given by the order statistics around its rank. The values are sorted in place.
+func EstimatePercentiles(values []float64, percentiles ...float64) []PercentileEstimate {
+	if len(values) == 0 {
+		return nil
+	}
+	sort.Float64s(values)
+
+	n := float64(len(values))
+	last := len(values) - 1
+	estimates := make([]PercentileEstimate, 0, len(percentiles))
+	for _, percentile := range percentiles {
+		p := percentile / 100
+		// The rank of the true percentile among the samples is binomially distributed
+		margin := z95 * math.Sqrt(n*p*(1-p))
+		low := min(max(int(math.Floor(n*p-margin)), 0), last)
+		high := min(max(int(math.Ceil(n*p+margin)), 0), last)
+		estimates = append(estimates, PercentileEstimate{
+			Percentile: percentile,
+			Value:      values[int(float64(last)*p)],
+			CI95:       Interval{Low: values[low], High: values[high]},
+		})
+	}
+	return estimates
+}
+
+// ToFloat converts the native value of an exported data point to a number, reporting false for text
+func ToFloat(value any) (float64, bool) {
+	switch v := value.(type) {
+	case time.Duration:
+		return float64(v), true
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case uint16:
+		return float64(v), true
+	case uint32:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	case bool:
+		if v {
+			return 1, true
+		}
+		return 0, true
+	default:
+		return 0, false
+	}
+}
@@ -0,0 +1,82 @@
+package metric
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGivenValuesWhenSummarizeThenSampleStandardDeviation(t *testing.T) {
+	summary := Summarize([]float64{2, 4, 4, 4, 5, 5, 7, 9})
+
+	assert.Equal(t, 8, summary.Count)
+	assert.Equal(t, 5.0, summary.Mean)
+	assert.InDelta(t, 2.138, summary.StdDev, 0.001)
+}
+
+func TestGivenSummariesWhenSignificantDifferenceThenNoiseIsNotFlagged(t *testing.T) {
+	tests := []struct {
+		name     string
+		a, b     []float64
+		expected bool
+	}{
+		{name: "Overlapping noise", a: []float64{10, 12, 9, 11, 13}, b: []float64{11, 13, 10, 12, 12}, expected: false},
+		{name: "Shifted samples", a: []float64{10, 11, 10, 9, 10, 11}, b: []float64{20, 21, 19, 20, 22, 21}, expected: true},
+		{name: "Single sample", a: []float64{10}, b: []float64{20}, expected: false},
+		{name: "Constant samples", a: []float64{1, 1, 1}, b: []float64{2, 2, 2}, expected: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, SignificantDifference(Summarize(test.a), Summarize(test.b)))
+		})
+	}
+}
+
+func TestGivenSummaryWhenDeviationThenStandardDeviationsFromMean(t *testing.T) {
+	summary := Summary{Count: 10, Mean: 100, StdDev: 20}
+
+	deviation, ok := summary.Deviation(160)
+	require.True(t, ok)
+	assert.Equal(t, 3.0, deviation)
+
+	deviation, ok = summary.Deviation(90)
+	require.True(t, ok)
+	assert.Equal(t, -0.5, deviation)
+
+	_, ok = Summary{Count: 10, Mean: 1}.Deviation(2)
+	assert.False(t, ok)
+	_, ok = Summary{Count: 1, Mean: 1, StdDev: 1}.Deviation(2)
+	assert.False(t, ok)
+}
+
+func TestGivenSamplesWhenEstimatePercentilesThenIntervalEnclosesEstimate(t *testing.T) {
+	values := make([]float64, 0, 100)
+	for i := 100; i > 0; i-- {
+		values = append(values, float64(i))
+	}
+
+	estimates := EstimatePercentiles(values, 50, 90)
+
+	assert.Equal(t, []PercentileEstimate{
+		{Percentile: 50, Value: 50, CI95: Interval{Low: 41, High: 61}},
+		{Percentile: 90, Value: 90, CI95: Interval{Low: 85, High: 97}},
+	}, estimates)
+}
+
+func TestGivenDataPointsWhenComputeStatisticsThenNumericMeasurementsSummarized(t *testing.T) {
+	statistics := ComputeStatistics([]ExportedDataPoint{
+		{Values: map[string]any{"Duration": time.Second, "Version": "v1", "Offline": true}},
+		{Values: map[string]any{"Duration": 3 * time.Second, "Version": "v1", "Offline": false}},
+	})
+
+	require.Contains(t, statistics, "Duration")
+	assert.NotContains(t, statistics, "Version")
+	assert.Equal(t, 2e9, statistics["Duration"].Mean)
+	assert.Equal(t, 0.5, statistics["Offline"].Mean)
+	require.NotNil(t, statistics["Duration"].CI95)
+	assert.Less(t, statistics["Duration"].CI95.Low, 2e9)
+	assert.Nil(t, ComputeStatistics([]ExportedDataPoint{{Values: map[string]any{"Peers": uint32(5)}}})["Peers"].CI95)
+}
@@ -0,0 +1,82 @@
+package metric
+
+import (
+	"context"
+	"time"
+)
+
+// SetTimeout bounds every measurement of the metric, zero keeps the default timeout of the metric
+func (bm *Base[T]) SetTimeout(timeout time.Duration) {
+	bm.mutex.Lock()
+	defer bm.mutex.Unlock()
+
+	bm.timeout = timeout
+}
+
+// Timeout returns the configured timeout of a measurement, or the default of the metric when none is configured
+func (bm *Base[T]) Timeout(fallback time.Duration) time.Duration {
+	bm.mutex.RLock()
+	defer bm.mutex.RUnlock()
+
+	if bm.timeout == 0 {
+		return fallback
+	}
+	return bm.timeout
+}
+
+// MeasurementContext bounds a single measurement by the timeout, the requests of the shared HTTP client honor it
+func (bm *Base[T]) MeasurementContext(ctx context.Context, fallback time.Duration) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(ctx, bm.Timeout(fallback))
+}
+
+// SetWarmUp has the runner of the metric wait one interval before the first measurement
+func (bm *Base[T]) SetWarmUp(warmUp bool) {
+	bm.mutex.Lock()
+	defer bm.mutex.Unlock()
+
+	bm.warmUp = warmUp
+}
+
+// Runner measures the metric every interval, starting as configured by SetWarmUp
+func (bm *Base[T]) Runner(interval time.Duration) *Runner {
+	bm.mutex.RLock()
+	defer bm.mutex.RUnlock()
+
+	return NewRunner(bm.Name, interval).WithWarmUp(bm.warmUp)
+}
+
+// SetTimeout bounds every measurement of the metric, zero keeps the default timeout of the metric
+func (bm *ValueBase) SetTimeout(timeout time.Duration) {
+	bm.mutex.Lock()
+	defer bm.mutex.Unlock()
+
+	bm.timeout = timeout
+}
+
+// MeasurementContext bounds a single measurement by the configured timeout, or the default of the metric
+func (bm *ValueBase) MeasurementContext(ctx context.Context, fallback time.Duration) (context.Context, context.CancelFunc) {
+	bm.mutex.RLock()
+	timeout := bm.timeout
+	bm.mutex.RUnlock()
+
+	if timeout == 0 {
+		timeout = fallback
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+// SetWarmUp has the runner of the metric wait one interval before the first measurement
+func (bm *ValueBase) SetWarmUp(warmUp bool) {
+	bm.mutex.Lock()
+	defer bm.mutex.Unlock()
+
+	bm.warmUp = warmUp
+}
+
+// Runner measures the metric every interval, starting as configured by SetWarmUp
+func (bm *ValueBase) Runner(interval time.Duration) *Runner {
+	bm.mutex.RLock()
+	defer bm.mutex.RUnlock()
+
+	return NewRunner(bm.Name, interval).WithWarmUp(bm.warmUp)
+}
@@ -0,0 +1,118 @@
+package metric
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// TrendCondition is met when a measurement changes by the threshold within the window, which slides over the data
+// points, e.g. a memory leak or a sudden drop which stays above the absolute thresholds. The change is in the unit of
+// the measurement, or in percent of the value at the start of the window when relative: a threshold of -50 with
+// OperatorLessThanOrEqual is met when the value halves.
+type TrendCondition struct {
+	Name      string
+	Window    time.Duration
+	Threshold float64
+	Relative  bool
+	Operator  Operator
+	Severity  SeverityLevel
+}
+
+// Evaluate tells whether the change meets the condition
+func (c TrendCondition) Evaluate(change float64) bool {
+	return HealthCondition[float64]{Threshold: c.Threshold, Operator: c.Operator}.Evaluate(change)
+}
+
+// AddTrends adds conditions on the change of the measurements to the health evaluation
+func (bm *Base[T]) AddTrends(conditions ...TrendCondition) {
+	bm.mutex.Lock()
+	defer bm.mutex.Unlock()
+
+	bm.trends = append(bm.trends, conditions...)
+}
+
+// AddTrends adds conditions on the change of the measurements to the health evaluation, only numbers and durations
+// can meet them
+func (bm *ValueBase) AddTrends(conditions ...TrendCondition) {
+	bm.mutex.Lock()
+	defer bm.mutex.Unlock()
+
+	bm.trends = append(bm.trends, conditions...)
+}
+
+// evaluateTrends adds the trend conditions met by the data points measured since the time to the evaluation. The
+// windows of those data points reach back to the ones measured before.
+func evaluateTrends[T any](evaluation *Evaluation, dataPoints []DataPoint[T], since time.Time, trends []TrendCondition, toFloat func(T) (float64, bool)) {
+	for _, trend := range trends {
+		var (
+			times  []time.Time
+			values []float64
+		)
+		for _, dp := range dataPoints {
+			if value, ok := dp.Values[trend.Name]; ok {
+				if number, ok := toFloat(value); ok {
+					times = append(times, dp.Timestamp)
+					values = append(values, number)
+				}
+			}
+		}
+
+		var result *ConditionResult
+		start := 0
+		for i := range values {
+			for times[i].Sub(times[start]) > trend.Window {
+				start++
+			}
+			if start == i || times[i].Before(since) {
+				continue
+			}
+
+			change := values[i] - values[start]
+			if trend.Relative {
+				if values[start] == 0 {
+					continue
+				}
+				change = change / values[start] * 100
+			}
+			if !trend.Evaluate(change) {
+				continue
+			}
+
+			evaluation.Health = Unhealthy
+			if CompareSeverities(trend.Severity, evaluation.Severities[trend.Name]) > 0 {
+				evaluation.Severities[trend.Name] = trend.Severity
+			}
+			if result == nil {
+				result = &ConditionResult{
+					Measurement: trend.Name,
+					Operator:    trend.Operator,
+					Threshold:   fmt.Sprintf("%s change within %s", formatChange(trend.Threshold, trend.Relative), trend.Window),
+					Severity:    trend.Severity,
+				}
+			}
+			result.Observed = formatChange(change, trend.Relative)
+			result.Occurrences++
+		}
+		if result != nil {
+			evaluation.Conditions = append(evaluation.Conditions, *result)
+		}
+	}
+
+	sort.SliceStable(evaluation.Conditions, func(i, j int) bool {
+		return CompareSeverities(evaluation.Conditions[i].Severity, evaluation.Conditions[j].Severity) > 0
+	})
+}
+
+// formatChange signs the change and adds the percent sign to relative ones, e.g. '+1073741824' or '-62.5%'
+func formatChange(change float64, relative bool) string {
+	text := strconv.FormatFloat(change, 'f', -1, 64)
+	if relative {
+		text = strconv.FormatFloat(change, 'f', 1, 64) + "%"
+	}
+	if change >= 0 {
+		text = "+" + text
+	}
+	return text
+}
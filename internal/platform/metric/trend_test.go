@@ -0,0 +1,56 @@
+package metric
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func peerDataPoints(start time.Time, counts ...uint32) []DataPoint[uint32] {
+	dataPoints := make([]DataPoint[uint32], 0, len(counts))
+	for i, count := range counts {
+		dataPoints = append(dataPoints, DataPoint[uint32]{
+			Timestamp: start.Add(time.Duration(i) * time.Minute),
+			Values:    map[string]uint32{"PeerCount": count},
+		})
+	}
+	return dataPoints
+}
+
+func TestGivenRelativeDropWithinWindowWhenEvaluateMetricThenConditionMet(t *testing.T) {
+	base := Base[uint32]{Name: "Peers", DataPoints: peerDataPoints(time.Now(), 80, 78, 30, 28)}
+	base.AddTrends(TrendCondition{Name: "PeerCount", Window: time.Minute * 5, Threshold: -50, Relative: true, Operator: OperatorLessThanOrEqual, Severity: SeverityMedium})
+
+	evaluation := base.EvaluateMetric()
+
+	assert.Equal(t, Unhealthy, evaluation.Health)
+	assert.Equal(t, SeverityMedium, evaluation.Severities["PeerCount"])
+	require.Len(t, evaluation.Conditions, 1)
+	assert.Equal(t, "-50.0% change within 5m0s", evaluation.Conditions[0].Threshold)
+	assert.Equal(t, "-65.0%", evaluation.Conditions[0].Observed)
+	assert.Equal(t, 2, evaluation.Conditions[0].Occurrences)
+}
+
+func TestGivenSlowDropOutsideWindowWhenEvaluateMetricThenConditionNotMet(t *testing.T) {
+	base := Base[uint32]{Name: "Peers", DataPoints: peerDataPoints(time.Now(), 80, 70, 60, 50, 40, 30)}
+	base.AddTrends(TrendCondition{Name: "PeerCount", Window: time.Minute * 2, Threshold: -50, Relative: true, Operator: OperatorLessThanOrEqual, Severity: SeverityMedium})
+
+	assert.Equal(t, Healthy, base.EvaluateMetric().Health)
+}
+
+func TestGivenAbsoluteGrowthWhenEvaluateMetricThenConditionMet(t *testing.T) {
+	now := time.Now()
+	base := ValueBase{Name: "Memory", DataPoints: []DataPoint[Value]{
+		{Timestamp: now, Values: map[string]Value{"Used": Number(1e9)}},
+		{Timestamp: now.Add(time.Minute * 30), Values: map[string]Value{"Used": Number(2.5e9)}},
+	}}
+	base.AddTrends(TrendCondition{Name: "Used", Window: time.Hour, Threshold: 1e9, Operator: OperatorGreaterThanOrEqual, Severity: SeverityMedium})
+
+	evaluation := base.EvaluateMetric()
+
+	assert.Equal(t, Unhealthy, evaluation.Health)
+	require.Len(t, evaluation.Conditions, 1)
+	assert.Equal(t, "+1500000000", evaluation.Conditions[0].Observed)
+}
@@ -0,0 +1,238 @@
+package metric
+
+import (
+	"cmp"
+	"sync"
+	"time"
+
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/format"
+)
+
+type (
+	Kind int
+
+	// Value is a measurement of one of several kinds, so a single metric can record e.g. a version string next to a
+	// latency without encoding the latency as string
+	Value struct {
+		kind Kind
+		// number holds numbers, durations in nanoseconds and booleans as 0 or 1
+		number float64
+		text   string
+	}
+
+	// ValueBase is the base of metrics recording measurements of different kinds. Like Base, DataPoints is guarded by
+	// the mutex and must be read through Snapshot.
+	ValueBase struct {
+		Name             string
+		DataPoints       []DataPoint[Value]
+		HealthConditions []ValueCondition
+		mutex            sync.RWMutex
+		faults           []Fault
+		// expected returns the windows whose readings are left out of the health evaluation
+		expected func() []Window
+		trends   []TrendCondition
+		timeout  time.Duration
+		warmUp   bool
+	}
+
+	// ValueCondition is a health condition of a measurement of any kind. Values of another kind than the threshold
+	// never meet it.
+	ValueCondition struct {
+		Name      string
+		Threshold Value
+		Operator  Operator
+		Severity  SeverityLevel
+	}
+)
+
+const (
+	KindNumber Kind = iota
+	KindDuration
+	KindString
+	KindBool
+)
+
+func Number(value float64) Value {
+	return Value{kind: KindNumber, number: value}
+}
+
+func Duration(value time.Duration) Value {
+	return Value{kind: KindDuration, number: float64(value)}
+}
+
+func String(value string) Value {
+	return Value{kind: KindString, text: value}
+}
+
+func Bool(value bool) Value {
+	if value {
+		return Value{kind: KindBool, number: 1}
+	}
+	return Value{kind: KindBool}
+}
+
+func (v Value) Kind() Kind {
+	return v.kind
+}
+
+// Float returns numbers, durations in nanoseconds and booleans as 0 or 1
+func (v Value) Float() float64 {
+	return v.number
+}
+
+func (v Value) Duration() time.Duration {
+	return time.Duration(v.number)
+}
+
+func (v Value) Bool() bool {
+	return v.number != 0
+}
+
+// Any returns the value as its native type, e.g. for raw exports
+func (v Value) Any() any {
+	switch v.kind {
+	case KindDuration:
+		return v.Duration()
+	case KindString:
+		return v.text
+	case KindBool:
+		return v.Bool()
+	default:
+		return v.number
+	}
+}
+
+// String formats the value according to its kind, e.g. '850.25ms' for durations
+func (v Value) String() string {
+	return format.Value(v.Any())
+}
+
+// Compare orders values of the same kind, strings lexicographically and the other kinds numerically
+func (v Value) Compare(other Value) int {
+	if v.kind == KindString {
+		return cmp.Compare(v.text, other.text)
+	}
+	return cmp.Compare(v.number, other.number)
+}
+
+func (c ValueCondition) Evaluate(value Value) bool {
+	if value.kind != c.Threshold.kind {
+		return false
+	}
+
+	comparison := value.Compare(c.Threshold)
+	switch c.Operator {
+	case OperatorGreaterThan:
+		return comparison > 0
+	case OperatorLessThan:
+		return comparison < 0
+	case OperatorGreaterThanOrEqual:
+		return comparison >= 0
+	case OperatorLessThanOrEqual:
+		return comparison <= 0
+	case OperatorEqual:
+		return comparison == 0
+	default:
+		return false
+	}
+}
+
+func (bm *ValueBase) GetName() string {
+	return bm.Name
+}
+
+// AddDataPoint records the values, which must not be modified afterwards
+func (bm *ValueBase) AddDataPoint(values map[string]Value) {
+	bm.mutex.Lock()
+	defer bm.mutex.Unlock()
+
+	now := time.Now()
+	bm.DataPoints = append(bm.DataPoints, DataPoint[Value]{
+		Timestamp: now,
+		Values: degrade(values, bm.faults, now, func(i int, reading Value) (Value, bool) {
+			value, err := parseValue(reading.Kind(), bm.faults[i].Value)
+			return value, err == nil
+		}),
+	})
+}
+
+// Snapshot returns the data points measured so far, safe to read while new ones are added
+func (bm *ValueBase) Snapshot() []DataPoint[Value] {
+	bm.mutex.RLock()
+	defer bm.mutex.RUnlock()
+
+	return snapshot(bm.DataPoints)
+}
+
+// Samples returns the number of data points measured so far
+func (bm *ValueBase) Samples() int {
+	bm.mutex.RLock()
+	defer bm.mutex.RUnlock()
+
+	return len(bm.DataPoints)
+}
+
+func (bm *ValueBase) ExportDataPoints() []ExportedDataPoint {
+	return export(bm.Snapshot(), Value.Any)
+}
+
+func (bm *ValueBase) EvaluateMetric() Evaluation {
+	return bm.EvaluateSince(time.Time{})
+}
+
+// EvaluateSince evaluates the data points measured from the time on, e.g. the latest readings of a running benchmark
+func (bm *ValueBase) EvaluateSince(since time.Time) Evaluation {
+	conditions := make([]conditionInfo, 0, len(bm.HealthConditions))
+	for _, condition := range bm.HealthConditions {
+		conditions = append(conditions, conditionInfo{
+			name:      condition.Name,
+			operator:  condition.Operator,
+			threshold: condition.Threshold.String(),
+			severity:  condition.Severity,
+		})
+	}
+	bm.mutex.RLock()
+	expected, trends := bm.expected, bm.trends
+	bm.mutex.RUnlock()
+
+	dataPoints := unexpected(bm.Snapshot(), expected)
+	evaluation := evaluate(measuredSince(dataPoints, since), conditions, func(i int, value Value) bool {
+		return bm.HealthConditions[i].Evaluate(value)
+	})
+	evaluateTrends(&evaluation, dataPoints, since, trends, func(value Value) (float64, bool) {
+		return value.number, value.kind == KindNumber || value.kind == KindDuration
+	})
+	return evaluation
+}
+
+// EvaluateBetween evaluates the health conditions against the data points measured from the start until the end
+func (bm *ValueBase) EvaluateBetween(from, to time.Time) Evaluation {
+	conditions := make([]conditionInfo, 0, len(bm.HealthConditions))
+	for _, condition := range bm.HealthConditions {
+		conditions = append(conditions, conditionInfo{
+			name:      condition.Name,
+			operator:  condition.Operator,
+			threshold: condition.Threshold.String(),
+			severity:  condition.Severity,
+		})
+	}
+	bm.mutex.RLock()
+	expected := bm.expected
+	bm.mutex.RUnlock()
+
+	dataPoints := measuredBetween(unexpected(bm.Snapshot(), expected), from, to)
+	return evaluate(dataPoints, conditions, func(i int, value Value) bool {
+		return bm.HealthConditions[i].Evaluate(value)
+	})
+}
+
+// Last returns the most recent value of the measurement
+func (bm *ValueBase) Last(name string) (Value, bool) {
+	dataPoints := bm.Snapshot()
+	for i := len(dataPoints) - 1; i >= 0; i-- {
+		if value, ok := dataPoints[i].Values[name]; ok {
+			return value, true
+		}
+	}
+	return Value{}, false
+}
@@ -0,0 +1,42 @@
+package metric
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGivenMixedMeasurementsWhenEvaluateMetricThenComparesByKind(t *testing.T) {
+	base := ValueBase{
+		HealthConditions: []ValueCondition{
+			{Name: "Version", Threshold: String(""), Operator: OperatorEqual, Severity: SeverityHigh},
+			{Name: "Latency", Threshold: Duration(time.Second), Operator: OperatorGreaterThanOrEqual, Severity: SeverityMedium},
+			{Name: "Synced", Threshold: Bool(false), Operator: OperatorEqual, Severity: SeverityLow},
+		},
+	}
+	base.AddDataPoint(map[string]Value{"Version": String("Lighthouse/v5.3.0"), "Latency": Duration(1500 * time.Millisecond)})
+	base.AddDataPoint(map[string]Value{"Synced": Bool(true)})
+
+	evaluation := base.EvaluateMetric()
+
+	assert.Equal(t, Unhealthy, evaluation.Health)
+	assert.Equal(t, map[string]SeverityLevel{"Version": SeverityNone, "Latency": SeverityMedium, "Synced": SeverityNone}, evaluation.Severities)
+	assert.Equal(t, []ConditionResult{
+		{Measurement: "Latency", Operator: OperatorGreaterThanOrEqual, Threshold: "1.00s", Observed: "1.50s", Severity: SeverityMedium, Occurrences: 1},
+	}, evaluation.Conditions)
+}
+
+func TestGivenValueOfOtherKindWhenEvaluateConditionThenNotMet(t *testing.T) {
+	condition := ValueCondition{Name: "Latency", Threshold: Duration(time.Second), Operator: OperatorGreaterThan}
+
+	assert.False(t, condition.Evaluate(Number(5e9)))
+	assert.True(t, condition.Evaluate(Duration(5*time.Second)))
+}
+
+func TestGivenValuesWhenExportDataPointsThenNativeTypes(t *testing.T) {
+	base := ValueBase{}
+	base.AddDataPoint(map[string]Value{"Latency": Duration(time.Second), "Version": String("v1"), "Synced": Bool(true), "Peers": Number(50)})
+
+	assert.Equal(t, map[string]any{"Latency": time.Second, "Version": "v1", "Synced": true, "Peers": float64(50)}, base.ExportDataPoints()[0].Values)
+}
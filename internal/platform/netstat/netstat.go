@@ -0,0 +1,99 @@
+package netstat
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/netip"
+	"strconv"
+	"strings"
+)
+
+const (
+	TCP Protocol = "tcp"
+	UDP Protocol = "udp"
+
+	// tcpListen is the state of a listening TCP socket
+	tcpListen = "0A"
+	// udpUnconnected is the state of a bound UDP socket without a peer, the UDP equivalent of listening
+	udpUnconnected = "07"
+)
+
+type (
+	Protocol string
+
+	// Listener is a socket accepting connections or datagrams on the address
+	Listener struct {
+		Protocol Protocol   `json:"protocol"`
+		Address  netip.Addr `json:"address"`
+		Port     uint16     `json:"port"`
+	}
+)
+
+// Exposed reports whether the listener is reachable from other hosts, i.e. it isn't bound to the loopback interface
+func (l Listener) Exposed() bool {
+	return !l.Address.IsLoopback()
+}
+
+func (l Listener) String() string {
+	return fmt.Sprintf("%s %s", l.Protocol, netip.AddrPortFrom(l.Address, l.Port))
+}
+
+// Parse reads the listeners of a socket table in the format of '/proc/net/tcp', '/proc/net/tcp6', '/proc/net/udp' or
+// '/proc/net/udp6', sockets which aren't listening are skipped
+func Parse(r io.Reader, protocol Protocol) ([]Listener, error) {
+	listening := tcpListen
+	if protocol == UDP {
+		listening = udpUnconnected
+	}
+
+	var listeners []Listener
+	scanner := bufio.NewScanner(r)
+	for first := true; scanner.Scan(); first = false {
+		fields := strings.Fields(scanner.Text())
+		// The first line is the header
+		if first || len(fields) < 4 {
+			continue
+		}
+		if fields[3] != listening {
+			continue
+		}
+
+		address, port, err := parseAddress(fields[1])
+		if err != nil {
+			return nil, errors.Join(err, fmt.Errorf("invalid local address '%s'", fields[1]))
+		}
+		listeners = append(listeners, Listener{Protocol: protocol, Address: address, Port: port})
+	}
+	return listeners, scanner.Err()
+}
+
+// parseAddress decodes an address like '0100007F:1F90', the address is hex encoded in host byte order (little endian)
+// 32 bit words and the port is hex encoded in network byte order
+func parseAddress(text string) (netip.Addr, uint16, error) {
+	host, portText, ok := strings.Cut(text, ":")
+	if !ok {
+		return netip.Addr{}, 0, errors.New("missing port")
+	}
+	port, err := strconv.ParseUint(portText, 16, 16)
+	if err != nil {
+		return netip.Addr{}, 0, err
+	}
+
+	raw, err := hex.DecodeString(host)
+	if err != nil {
+		return netip.Addr{}, 0, err
+	}
+	if len(raw) != 4 && len(raw) != 16 {
+		return netip.Addr{}, 0, fmt.Errorf("unexpected address length %d", len(raw))
+	}
+	for i := 0; i < len(raw); i += 4 {
+		binary.BigEndian.PutUint32(raw[i:], binary.LittleEndian.Uint32(raw[i:]))
+	}
+
+	address, _ := netip.AddrFromSlice(raw)
+	return address.Unmap(), uint16(port), nil
+}
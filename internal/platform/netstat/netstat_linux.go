@@ -0,0 +1,41 @@
+//go:build linux
+
+package netstat
+
+import (
+	"errors"
+	"fmt"
+	"os"
+)
+
+var tables = []struct {
+	path     string
+	protocol Protocol
+}{
+	{"/proc/net/tcp", TCP},
+	{"/proc/net/tcp6", TCP},
+	{"/proc/net/udp", UDP},
+	{"/proc/net/udp6", UDP},
+}
+
+// Listeners lists the listening TCP and UDP sockets of the host. The IPv6 tables are missing when IPv6 is disabled.
+func Listeners() ([]Listener, error) {
+	var listeners []Listener
+	for _, table := range tables {
+		file, err := os.Open(table.path)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		tableListeners, err := Parse(file, table.protocol)
+		file.Close()
+		if err != nil {
+			return nil, errors.Join(err, fmt.Errorf("failed parsing '%s'", table.path))
+		}
+		listeners = append(listeners, tableListeners...)
+	}
+	return listeners, nil
+}
@@ -0,0 +1,10 @@
+//go:build !linux
+
+package netstat
+
+import "errors"
+
+// Listeners lists the listening TCP and UDP sockets of the host
+func Listeners() ([]Listener, error) {
+	return nil, errors.New("listing listening sockets is not supported on this platform")
+}
@@ -0,0 +1,62 @@
+package netstat
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const tcpTable = `  sl  local_address rem_address   st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode
+   0: 00000000:2161 00000000:0000 0A 00000000:00000000 00:00000000 00000000  1000        0 20301 1 0000000000000000 100 0 0 10 0
+   1: 0100007F:1F90 00000000:0000 0A 00000000:00000000 00:00000000 00000000  1000        0 20302 1 0000000000000000 100 0 0 10 0
+   2: 0100007F:D2F4 0100007F:2161 01 00000000:00000000 00:00000000 00000000  1000        0 20303 1 0000000000000000 20 4 30 10 -1
+`
+
+const tcp6Table = `  sl  local_address                         remote_address                        st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode
+   0: 00000000000000000000000000000000:13C4 00000000000000000000000000000000:0000 0A 00000000:00000000 00:00000000 00000000  1000        0 20304 1 0000000000000000 100 0 0 10 0
+   1: 00000000000000000000000001000000:1F91 00000000000000000000000000000000:0000 0A 00000000:00000000 00:00000000 00000000  1000        0 20305 1 0000000000000000 100 0 0 10 0
+`
+
+func TestGivenTCPTableWhenParseThenReturnsListeningSockets(t *testing.T) {
+	listeners, err := Parse(strings.NewReader(tcpTable), TCP)
+
+	require.NoError(t, err)
+	require.Len(t, listeners, 2)
+	assert.Equal(t, "tcp 0.0.0.0:8545", listeners[0].String())
+	assert.True(t, listeners[0].Exposed())
+	assert.Equal(t, "tcp 127.0.0.1:8080", listeners[1].String())
+	assert.False(t, listeners[1].Exposed())
+}
+
+func TestGivenTCP6TableWhenParseThenDecodesIPv6Addresses(t *testing.T) {
+	listeners, err := Parse(strings.NewReader(tcp6Table), TCP)
+
+	require.NoError(t, err)
+	require.Len(t, listeners, 2)
+	assert.Equal(t, "tcp [::]:5060", listeners[0].String())
+	assert.True(t, listeners[0].Exposed())
+	assert.Equal(t, "tcp [::1]:8081", listeners[1].String())
+	assert.False(t, listeners[1].Exposed())
+}
+
+func TestGivenUDPTableWhenParseThenReturnsUnconnectedSockets(t *testing.T) {
+	table := `  sl  local_address rem_address   st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode ref pointer drops
+  100: 00000000:765F 00000000:0000 07 00000000:00000000 00:00000000 00000000  1000        0 20306 2 0000000000000000 0
+  101: 0100007F:8B2C 0100007F:0035 01 00000000:00000000 00:00000000 00000000  1000        0 20307 2 0000000000000000 0
+`
+	listeners, err := Parse(strings.NewReader(table), UDP)
+
+	require.NoError(t, err)
+	require.Len(t, listeners, 1)
+	assert.Equal(t, "udp 0.0.0.0:30303", listeners[0].String())
+}
+
+func TestGivenInvalidAddressWhenParseThenFails(t *testing.T) {
+	table := "header\n   0: XYZ:2161 00000000:0000 0A\n"
+
+	_, err := Parse(strings.NewReader(table), TCP)
+
+	assert.Error(t, err)
+}
@@ -2,27 +2,86 @@ package network
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 	"time"
 )
 
-var (
-	GenesisTime = map[Name]time.Time{
-		Holesky: time.Unix(1695902400, 0),
-		Mainnet: time.Unix(1606824023, 0),
-	}
-)
-
 type Name string
 
 const (
-	Holesky Name = "holesky"
 	Mainnet Name = "mainnet"
+	Holesky Name = "holesky"
+	Sepolia Name = "sepolia"
+	Hoodi   Name = "hoodi"
+	Gnosis  Name = "gnosis"
+	Chiado  Name = "chiado"
+	// Custom is any other network, its timing is taken from the spec of the beacon node and its genesis isn't verified
+	Custom Name = "custom"
 )
 
+// Preset is what is known about a network without asking its nodes
+type Preset struct {
+	GenesisTime time.Time
+	// GenesisValidatorsRoot identifies the beacon chain of the network
+	GenesisValidatorsRoot        string
+	SlotDuration                 time.Duration
+	SlotsPerEpoch                uint64
+	EpochsPerSyncCommitteePeriod uint64
+	// DepositChainID is the chain ID of the execution layer of the network
+	DepositChainID uint64
+}
+
+// Presets are the networks known by name, Gnosis Chain and its testnet run shorter slots and epochs
+var Presets = map[Name]Preset{
+	Mainnet: ethereum(time.Unix(1606824023, 0), "0x4b363db94e286120d76eb905340fdd4e54bfe9f06bf33ff6cf5ad27f511bfe95", 1),
+	Holesky: ethereum(time.Unix(1695902400, 0), "0x9143aa7c615a7f7115e2b6aac319c03529df8242ae705fba9df39b79c59fa8b1", 17000),
+	Sepolia: ethereum(time.Unix(1655733600, 0), "0xd8ea171f3c94aea21ebc42a1ed61052acf3f9209c00e4efbaaddac09ed9b8078", 11155111),
+	Hoodi:   ethereum(time.Unix(1742213400, 0), "0x212f13fc4df078b6cb7db228f1c8307566dcecf900867401a92023d7ba99cb5f", 560048),
+	Gnosis:  gnosis(time.Unix(1638993340, 0), "0xf5dcb5564e829aab27264b9becd5dfaa017085611224cb3036f573368dbb9d47", 100),
+	Chiado:  gnosis(time.Unix(1665396300, 0), "0x9d642dac73058fbf39c0ae41ab1e34e4d889043cb199851ded7095bc99eb4c1e", 10200),
+}
+
+func ethereum(genesis time.Time, root string, chainID uint64) Preset {
+	return Preset{
+		GenesisTime:                  genesis,
+		GenesisValidatorsRoot:        root,
+		SlotDuration:                 defaultSlotDuration,
+		SlotsPerEpoch:                defaultSlotsPerEpoch,
+		EpochsPerSyncCommitteePeriod: defaultEpochsPerSyncCommitteePeriod,
+		DepositChainID:               chainID,
+	}
+}
+
+func gnosis(genesis time.Time, root string, chainID uint64) Preset {
+	return Preset{
+		GenesisTime:                  genesis,
+		GenesisValidatorsRoot:        root,
+		SlotDuration:                 time.Second * 5,
+		SlotsPerEpoch:                16,
+		EpochsPerSyncCommitteePeriod: 512,
+		DepositChainID:               chainID,
+	}
+}
+
+// Names returns the names of the networks accepted in the configuration
+func Names() []Name {
+	names := make([]Name, 0, len(Presets)+1)
+	for name := range Presets {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool { return names[i] < names[j] })
+	return append(names, Custom)
+}
+
 func (n Name) Validate() error {
-	if !strings.EqualFold(string(n), string(Holesky)) && !strings.EqualFold(string(n), string(Mainnet)) {
-		return fmt.Errorf("network name should be either '%s' or '%s'", Holesky, Mainnet)
+	names := Names()
+	quoted := make([]string, 0, len(names))
+	for _, name := range names {
+		if strings.EqualFold(string(n), string(name)) {
+			return nil
+		}
+		quoted = append(quoted, fmt.Sprintf("'%s'", name))
 	}
-	return nil
+	return fmt.Errorf("network name should be one of %s", strings.Join(quoted, ", "))
 }
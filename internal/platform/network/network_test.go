@@ -0,0 +1,14 @@
+package network
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGivenNamesWhenValidateThenPresetsAndCustomAccepted(t *testing.T) {
+	for _, name := range []Name{Mainnet, Holesky, Sepolia, Hoodi, Gnosis, Chiado, Custom, "Gnosis"} {
+		assert.NoError(t, name.Validate(), name)
+	}
+	assert.ErrorContains(t, Name("goerli").Validate(), "'chiado', 'gnosis', 'holesky', 'hoodi', 'mainnet', 'sepolia', 'custom'")
+}
@@ -0,0 +1,103 @@
+package network
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/clock"
+)
+
+const (
+	defaultSlotDuration                 = time.Second * 12
+	defaultSlotsPerEpoch                = 32
+	defaultEpochsPerSyncCommitteePeriod = 256
+	// intervalsPerSlot splits the slot into proposing, attesting and aggregating
+	intervalsPerSlot = 3
+)
+
+// Spec is the timing of the beacon chain of a network. It is fetched from the beacon node, so networks with other
+// slot durations or epoch lengths are measured at the right points in time.
+type Spec struct {
+	GenesisTime                  time.Time
+	SlotDuration                 time.Duration
+	SlotsPerEpoch                uint64
+	EpochsPerSyncCommitteePeriod uint64
+	DepositChainID               uint64
+}
+
+// DefaultSpec is the spec of the network as known from its preset without asking the beacon node. Custom networks
+// get the timing of mainnet and an unknown genesis time.
+func DefaultSpec(name Name) Spec {
+	preset, ok := Presets[name]
+	if !ok {
+		return Spec{
+			SlotDuration:                 defaultSlotDuration,
+			SlotsPerEpoch:                defaultSlotsPerEpoch,
+			EpochsPerSyncCommitteePeriod: defaultEpochsPerSyncCommitteePeriod,
+		}
+	}
+	return Spec{
+		GenesisTime:                  preset.GenesisTime,
+		SlotDuration:                 preset.SlotDuration,
+		SlotsPerEpoch:                preset.SlotsPerEpoch,
+		EpochsPerSyncCommitteePeriod: preset.EpochsPerSyncCommitteePeriod,
+		DepositChainID:               preset.DepositChainID,
+	}
+}
+
+// SlotTime returns the start of the slot
+func (s Spec) SlotTime(slot uint64) time.Time {
+	return s.GenesisTime.Add(time.Duration(slot) * s.SlotDuration)
+}
+
+// SlotAt returns the slot running at the time, 0 before genesis
+func (s Spec) SlotAt(t time.Time) uint64 {
+	if t.Before(s.GenesisTime) {
+		return 0
+	}
+	return uint64(t.Sub(s.GenesisTime) / s.SlotDuration)
+}
+
+// CurrentSlot returns the slot running at the time of the run
+func (s Spec) CurrentSlot() uint64 {
+	return s.SlotAt(clock.Now())
+}
+
+// Epoch returns the epoch of the slot
+func (s Spec) Epoch(slot uint64) uint64 {
+	return slot / s.SlotsPerEpoch
+}
+
+// EpochStart returns the first slot of the epoch
+func (s Spec) EpochStart(epoch uint64) uint64 {
+	return epoch * s.SlotsPerEpoch
+}
+
+func (s Spec) EpochDuration() time.Duration {
+	return s.SlotDuration * time.Duration(s.SlotsPerEpoch)
+}
+
+// AttestationDeadline is the point in the slot at which attesters vote for the head they know about
+func (s Spec) AttestationDeadline() time.Duration {
+	return s.SlotDuration / intervalsPerSlot
+}
+
+// AggregationDeadline is the point in the slot at which aggregators publish the aggregated attestations
+func (s Spec) AggregationDeadline() time.Duration {
+	return s.SlotDuration / intervalsPerSlot * 2
+}
+
+// SyncCommitteePeriod returns the sync committee period of the epoch
+func (s Spec) SyncCommitteePeriod(epoch uint64) uint64 {
+	return epoch / s.EpochsPerSyncCommitteePeriod
+}
+
+// Verify fails when the spec belongs to another network than the preset of the name, e.g. a Gnosis node used with
+// mainnet. Custom networks and specs without deposit chain ID pass.
+func (s Spec) Verify(name Name) error {
+	preset, ok := Presets[name]
+	if !ok || s.DepositChainID == 0 || s.DepositChainID == preset.DepositChainID {
+		return nil
+	}
+	return fmt.Errorf("beacon node is not on network '%s', its deposit chain ID is %d instead of %d", name, s.DepositChainID, preset.DepositChainID)
+}
@@ -0,0 +1,43 @@
+package network
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGivenShortSlotsWhenSlotMathThenFollowsSpec(t *testing.T) {
+	genesis := time.Unix(1638993340, 0)
+	spec := Spec{GenesisTime: genesis, SlotDuration: time.Second * 5, SlotsPerEpoch: 16, EpochsPerSyncCommitteePeriod: 512}
+
+	assert.Equal(t, genesis.Add(time.Second*50), spec.SlotTime(10))
+	assert.Equal(t, uint64(10), spec.SlotAt(genesis.Add(time.Second*54)))
+	assert.Equal(t, uint64(0), spec.SlotAt(genesis.Add(-time.Hour)))
+	assert.Equal(t, uint64(2), spec.Epoch(32))
+	assert.Equal(t, uint64(48), spec.EpochStart(3))
+	assert.Equal(t, time.Second*80, spec.EpochDuration())
+	assert.Equal(t, uint64(1), spec.SyncCommitteePeriod(600))
+	assert.Equal(t, time.Second*5/3, spec.AttestationDeadline())
+}
+
+func TestGivenMainnetWhenDefaultSpecThenTwelveSecondSlots(t *testing.T) {
+	spec := DefaultSpec(Mainnet)
+
+	assert.Equal(t, Presets[Mainnet].GenesisTime, spec.GenesisTime)
+	assert.Equal(t, uint64(1), spec.DepositChainID)
+	assert.Equal(t, time.Second*4, spec.AttestationDeadline())
+	assert.Equal(t, time.Second*8, spec.AggregationDeadline())
+	assert.Equal(t, time.Minute*6+time.Second*24, spec.EpochDuration())
+}
+
+func TestGivenGnosisWhenDefaultSpecThenFiveSecondSlots(t *testing.T) {
+	spec := DefaultSpec(Gnosis)
+
+	assert.Equal(t, time.Second*5, spec.SlotDuration)
+	assert.Equal(t, uint64(16), spec.SlotsPerEpoch)
+	assert.Equal(t, time.Second*80, spec.EpochDuration())
+	assert.NoError(t, spec.Verify(Gnosis))
+	assert.Error(t, spec.Verify(Mainnet))
+	assert.NoError(t, spec.Verify(Custom))
+}
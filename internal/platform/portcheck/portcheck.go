@@ -0,0 +1,46 @@
+package portcheck
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/httpclient"
+)
+
+// Probe asks an external port check service whether the port is reachable from the internet. The service URL is
+// requested with '{host}' and '{port}' replaced, a 2xx response status means the port is reachable.
+func Probe(ctx context.Context, serviceURL string, ip net.IP, port uint16) (bool, error) {
+	url := strings.NewReplacer("{host}", ip.String(), "{port}", strconv.Itoa(int(port))).Replace(serviceURL)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return false, err
+	}
+
+	res, err := httpclient.Default.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode >= http.StatusInternalServerError {
+		return false, fmt.Errorf("port check service failed with status '%s'", res.Status)
+	}
+	return res.StatusCode >= http.StatusOK && res.StatusCode < http.StatusMultipleChoices, nil
+}
+
+// IsPublic reports whether the IP is routable on the internet
+func IsPublic(ip net.IP) bool {
+	return ip != nil && !ip.IsPrivate() && !ip.IsLoopback() && !ip.IsUnspecified() && !ip.IsLinkLocalUnicast()
+}
+
+// Address formats the announced address of a node, 'none' when it announces none
+func Address(ip net.IP, port uint16) string {
+	if ip == nil {
+		return "none"
+	}
+	return net.JoinHostPort(ip.String(), strconv.Itoa(int(port)))
+}
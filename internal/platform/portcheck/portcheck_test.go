@@ -0,0 +1,56 @@
+package portcheck
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGivenServiceWhenProbeThenRequestsHostAndPort(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/203.0.113.5/30303" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	reachable, err := Probe(context.Background(), server.URL+"/{host}/{port}", net.ParseIP("203.0.113.5"), 30303)
+
+	require.NoError(t, err)
+	assert.True(t, reachable)
+}
+
+func TestGivenClosedPortWhenProbeThenUnreachable(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	reachable, err := Probe(context.Background(), server.URL+"/{host}/{port}", net.ParseIP("203.0.113.5"), 9000)
+
+	require.NoError(t, err)
+	assert.False(t, reachable)
+}
+
+func TestGivenFailingServiceWhenProbeThenFails(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	_, err := Probe(context.Background(), server.URL+"/{host}/{port}", net.ParseIP("203.0.113.5"), 9000)
+
+	assert.Error(t, err)
+}
+
+func TestGivenAddressesWhenIsPublicThenOnlyRoutableAreTrue(t *testing.T) {
+	assert.True(t, IsPublic(net.ParseIP("203.0.113.5")))
+	assert.False(t, IsPublic(net.ParseIP("192.168.1.10")))
+	assert.False(t, IsPublic(net.ParseIP("127.0.0.1")))
+	assert.False(t, IsPublic(nil))
+}
@@ -0,0 +1,174 @@
+package promtext
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/httpclient"
+)
+
+type (
+	// Sample is a single line of the Prometheus text exposition format, e.g. 'go_gc_duration_seconds{quantile="1"} 0.002'
+	Sample struct {
+		Name   string
+		Labels map[string]string
+		Value  float64
+	}
+
+	Samples []Sample
+)
+
+// Fetch scrapes the Prometheus endpoint, e.g. 'http://geth:6060/debug/metrics/prometheus'
+func Fetch(ctx context.Context, url string) (Samples, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "text/plain")
+
+	res, err := httpclient.Default.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("received unsuccessful status code. Code: '%s'. URL: '%s'", res.Status, url)
+	}
+	return Parse(res.Body)
+}
+
+// Parse reads the samples of the text exposition format, comments and timestamps are skipped
+func Parse(r io.Reader) (Samples, error) {
+	var samples Samples
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		sample, err := parseLine(line)
+		if err != nil {
+			return nil, errors.Join(err, fmt.Errorf("failed parsing sample '%s'", line))
+		}
+		samples = append(samples, sample)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return samples, nil
+}
+
+// Value returns the value of the first sample of the metric having the labels, given as name and value pairs
+func (s Samples) Value(name string, labels ...string) (float64, bool) {
+	for _, sample := range s {
+		if sample.Name == name && sample.matches(labels) {
+			return sample.Value, true
+		}
+	}
+	return 0, false
+}
+
+// Has tells whether the metric has any sample
+func (s Samples) Has(name string) bool {
+	for _, sample := range s {
+		if sample.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (s Sample) matches(labels []string) bool {
+	for i := 0; i+1 < len(labels); i += 2 {
+		if s.Labels[labels[i]] != labels[i+1] {
+			return false
+		}
+	}
+	return true
+}
+
+func parseLine(line string) (Sample, error) {
+	sample := Sample{Labels: map[string]string{}}
+
+	i := strings.IndexAny(line, "{ \t")
+	if i < 0 {
+		return sample, errors.New("sample has no value")
+	}
+	sample.Name = line[:i]
+	rest := line[i:]
+
+	if strings.HasPrefix(rest, "{") {
+		var err error
+		rest, err = parseLabels(rest[1:], sample.Labels)
+		if err != nil {
+			return sample, err
+		}
+	}
+
+	fields := strings.Fields(rest)
+	if len(fields) == 0 {
+		return sample, errors.New("sample has no value")
+	}
+	value, err := parseValue(fields[0])
+	if err != nil {
+		return sample, err
+	}
+	sample.Value = value
+	return sample, nil
+}
+
+// parseLabels reads the labels up to the closing brace and returns the rest of the line
+func parseLabels(text string, labels map[string]string) (string, error) {
+	for {
+		text = strings.TrimLeft(text, " \t,")
+		if strings.HasPrefix(text, "}") {
+			return text[1:], nil
+		}
+
+		name, value, ok := strings.Cut(text, "=")
+		if !ok || !strings.HasPrefix(value, `"`) {
+			return "", errors.New("label has no quoted value")
+		}
+
+		// Values escape backslashes, quotes and line feeds
+		var unquoted strings.Builder
+		i := 1
+		for ; i < len(value) && value[i] != '"'; i++ {
+			if value[i] == '\\' && i+1 < len(value) {
+				i++
+				if value[i] == 'n' {
+					unquoted.WriteByte('\n')
+					continue
+				}
+			}
+			unquoted.WriteByte(value[i])
+		}
+		if i == len(value) {
+			return "", errors.New("label value is not terminated")
+		}
+
+		labels[strings.TrimSpace(name)] = unquoted.String()
+		text = value[i+1:]
+	}
+}
+
+func parseValue(text string) (float64, error) {
+	switch text {
+	case "+Inf":
+		return math.Inf(1), nil
+	case "-Inf":
+		return math.Inf(-1), nil
+	case "NaN":
+		return math.NaN(), nil
+	}
+	return strconv.ParseFloat(text, 64)
+}
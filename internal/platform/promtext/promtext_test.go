@@ -0,0 +1,55 @@
+package promtext
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const exposition = `# HELP go_goroutines Number of goroutines that currently exist.
+# TYPE go_goroutines gauge
+go_goroutines 235
+# TYPE go_gc_duration_seconds summary
+go_gc_duration_seconds{quantile="0.5"} 0.000123
+go_gc_duration_seconds{quantile="1"} 0.0042
+go_gc_duration_seconds_sum 1.5
+http_requests_total{path="/eth/v1/node/health",note="a \"quoted\", value"} 17 1712345678000
+process_max_fds +Inf
+`
+
+func TestGivenExpositionWhenParsedThenSamplesHaveLabelsAndValues(t *testing.T) {
+	samples, err := Parse(strings.NewReader(exposition))
+	require.NoError(t, err)
+	require.Len(t, samples, 6)
+
+	goroutines, ok := samples.Value("go_goroutines")
+	assert.True(t, ok)
+	assert.Equal(t, float64(235), goroutines)
+
+	pause, ok := samples.Value("go_gc_duration_seconds", "quantile", "1")
+	assert.True(t, ok)
+	assert.Equal(t, 0.0042, pause)
+
+	requests, ok := samples.Value("http_requests_total", "path", "/eth/v1/node/health")
+	assert.True(t, ok)
+	assert.Equal(t, float64(17), requests)
+	assert.Equal(t, `a "quoted", value`, samples[4].Labels["note"])
+
+	fds, _ := samples.Value("process_max_fds")
+	assert.True(t, math.IsInf(fds, 1))
+
+	_, ok = samples.Value("go_gc_duration_seconds", "quantile", "0.99")
+	assert.False(t, ok)
+	assert.False(t, samples.Has("go_threads"))
+}
+
+func TestGivenMalformedSampleWhenParsedThenFails(t *testing.T) {
+	_, err := Parse(strings.NewReader(`go_goroutines{quantile="1} 3`))
+	assert.Error(t, err)
+
+	_, err = Parse(strings.NewReader(`go_goroutines`))
+	assert.Error(t, err)
+}
@@ -0,0 +1,376 @@
+package recording
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/clock"
+)
+
+const (
+	exchangesFile = "exchanges.jsonl"
+	metadataFile  = "recording.json"
+
+	eventStreamContentType = "text/event-stream"
+
+	// ipcMethod is the method of the exchanges recorded from IPC calls, their URL is the path of the socket
+	ipcMethod = "IPC"
+)
+
+type (
+	// Exchange is a recorded request along with its response, or the error of the transport
+	Exchange struct {
+		// Offset is the time since the recording started
+		Offset      time.Duration `json:"offset"`
+		Method      string        `json:"method"`
+		URL         string        `json:"url"`
+		RequestBody []byte        `json:"request_body,omitempty"`
+		Status      int           `json:"status,omitempty"`
+		Header      http.Header   `json:"header,omitempty"`
+		Body        []byte        `json:"body,omitempty"`
+		// Trailer holds e.g. the status of gRPC responses, sent after the body
+		Trailer http.Header `json:"trailer,omitempty"`
+		// Event is a single event of an event stream, recorded at the time it was received. The exchange opening the
+		// stream only has the status and header of the response.
+		Event []byte `json:"event,omitempty"`
+		Err   string `json:"error,omitempty"`
+	}
+
+	metadata struct {
+		Start time.Time `json:"start"`
+	}
+
+	// Recorder writes every request and its response to the recording directory, along with the calls over IPC. Event
+	// streams never end, so their events are recorded one by one as they are received.
+	Recorder struct {
+		start   time.Time
+		file    *os.File
+		encoder *json.Encoder
+		mutex   sync.Mutex
+	}
+
+	// Replayer answers requests with the responses of a recording. A request gets the latest response recorded
+	// for it up to the time of the run, so together with the clock set to the start of the recording the run
+	// sees what the recorded run saw. Event streams get the events recorded after they were opened, at the time they
+	// were received.
+	Replayer struct {
+		start     time.Time
+		exchanges map[string][]Exchange
+		events    map[string][]Exchange
+	}
+
+	// eventRecorder records the events of a stream as they are read
+	eventRecorder struct {
+		io.ReadCloser
+		recorder *Recorder
+		method   string
+		url      string
+		pending  []byte
+	}
+)
+
+func NewRecorder(dir string) (*Recorder, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, errors.Join(err, fmt.Errorf("failed creating recording directory '%s'", dir))
+	}
+
+	start := time.Now()
+	meta, err := json.Marshal(metadata{Start: start})
+	if err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(filepath.Join(dir, metadataFile), meta, 0o644); err != nil {
+		return nil, errors.Join(err, errors.New("failed writing recording metadata"))
+	}
+
+	file, err := os.Create(filepath.Join(dir, exchangesFile))
+	if err != nil {
+		return nil, errors.Join(err, errors.New("failed creating recording file"))
+	}
+
+	return &Recorder{
+		start:   start,
+		file:    file,
+		encoder: json.NewEncoder(file),
+	}, nil
+}
+
+func (r *Recorder) RoundTrip(req *http.Request, next http.RoundTripper) (*http.Response, error) {
+	req, requestBody, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	exchange := Exchange{
+		Offset:      time.Since(r.start),
+		Method:      req.Method,
+		URL:         req.URL.String(),
+		RequestBody: requestBody,
+	}
+
+	res, err := next.RoundTrip(req)
+	if err != nil {
+		exchange.Err = err.Error()
+		r.write(exchange)
+		return nil, err
+	}
+	if strings.HasPrefix(res.Header.Get("Content-Type"), eventStreamContentType) {
+		exchange.Status = res.StatusCode
+		exchange.Header = res.Header
+		r.write(exchange)
+		res.Body = &eventRecorder{ReadCloser: res.Body, recorder: r, method: req.Method, url: exchange.URL}
+		return res, nil
+	}
+
+	body, err := io.ReadAll(res.Body)
+	res.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	res.Body = io.NopCloser(bytes.NewReader(body))
+
+	exchange.Status = res.StatusCode
+	exchange.Header = res.Header
+	exchange.Body = body
+	exchange.Trailer = res.Trailer
+	r.write(exchange)
+	return res, nil
+}
+
+// CallIPC records the call like an HTTP exchange, with the path of the socket as URL and the response as body
+func (r *Recorder) CallIPC(path string, request, response any, send func() error) error {
+	requestBody, err := json.Marshal(request)
+	if err != nil {
+		return err
+	}
+	exchange := Exchange{
+		Offset:      time.Since(r.start),
+		Method:      ipcMethod,
+		URL:         path,
+		RequestBody: requestBody,
+	}
+
+	if err := send(); err != nil {
+		exchange.Err = err.Error()
+		r.write(exchange)
+		return err
+	}
+	if exchange.Body, err = json.Marshal(response); err == nil {
+		r.write(exchange)
+	}
+	return nil
+}
+
+func (e *eventRecorder) Read(p []byte) (int, error) {
+	n, err := e.ReadCloser.Read(p)
+	e.pending = append(e.pending, p[:n]...)
+
+	// An empty line ends an event
+	for {
+		end := bytes.Index(e.pending, []byte("\n\n"))
+		if end < 0 {
+			break
+		}
+		e.recorder.write(Exchange{
+			Offset: time.Since(e.recorder.start),
+			Method: e.method,
+			URL:    e.url,
+			Event:  bytes.Clone(e.pending[:end+2]),
+		})
+		e.pending = e.pending[end+2:]
+	}
+	return n, err
+}
+
+func (r *Recorder) write(exchange Exchange) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	// A failed write must not fail the request, the recording is only missing the exchange
+	_ = r.encoder.Encode(exchange)
+}
+
+func (r *Recorder) Close() error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	return r.file.Close()
+}
+
+func NewReplayer(dir string) (*Replayer, error) {
+	meta, err := os.ReadFile(filepath.Join(dir, metadataFile))
+	if err != nil {
+		return nil, errors.Join(err, fmt.Errorf("'%s' is not a recording", dir))
+	}
+	var m metadata
+	if err := json.Unmarshal(meta, &m); err != nil {
+		return nil, errors.Join(err, errors.New("failed parsing recording metadata"))
+	}
+
+	file, err := os.Open(filepath.Join(dir, exchangesFile))
+	if err != nil {
+		return nil, errors.Join(err, errors.New("failed opening recording file"))
+	}
+	defer file.Close()
+
+	replayer := &Replayer{
+		start:     m.Start,
+		exchanges: make(map[string][]Exchange),
+		events:    make(map[string][]Exchange),
+	}
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(nil, 64*1024*1024)
+	for scanner.Scan() {
+		var exchange Exchange
+		if err := json.Unmarshal(scanner.Bytes(), &exchange); err != nil {
+			return nil, errors.Join(err, errors.New("failed parsing recorded exchange"))
+		}
+		key := exchangeKey(exchange.Method, exchange.URL, exchange.RequestBody)
+		if len(exchange.Event) != 0 {
+			replayer.events[key] = append(replayer.events[key], exchange)
+			continue
+		}
+		replayer.exchanges[key] = append(replayer.exchanges[key], exchange)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errors.Join(err, errors.New("failed reading recording file"))
+	}
+
+	for _, exchanges := range replayer.exchanges {
+		sort.SliceStable(exchanges, func(i, j int) bool { return exchanges[i].Offset < exchanges[j].Offset })
+	}
+	for _, events := range replayer.events {
+		sort.SliceStable(events, func(i, j int) bool { return events[i].Offset < events[j].Offset })
+	}
+	return replayer, nil
+}
+
+// Start returns the time the recording started, the clock of the replayed run starts from it
+func (r *Replayer) Start() time.Time {
+	return r.start
+}
+
+func (r *Replayer) RoundTrip(req *http.Request, _ http.RoundTripper) (*http.Response, error) {
+	req, requestBody, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	exchange, err := r.find(req.Method, req.URL.String(), requestBody)
+	if err != nil {
+		return nil, err
+	}
+	if exchange.Err != "" {
+		return nil, errors.New(exchange.Err)
+	}
+	if strings.HasPrefix(exchange.Header.Get("Content-Type"), eventStreamContentType) {
+		return &http.Response{
+			Status:     fmt.Sprintf("%d %s", exchange.Status, http.StatusText(exchange.Status)),
+			StatusCode: exchange.Status,
+			Proto:      "HTTP/1.1",
+			ProtoMajor: 1,
+			ProtoMinor: 1,
+			Header:     exchange.Header.Clone(),
+			Body:       r.replayEvents(req.Context(), exchange.Offset, r.events[exchangeKey(req.Method, req.URL.String(), requestBody)]),
+			Request:    req,
+		}, nil
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", exchange.Status, http.StatusText(exchange.Status)),
+		StatusCode:    exchange.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        exchange.Header.Clone(),
+		Body:          io.NopCloser(bytes.NewReader(exchange.Body)),
+		ContentLength: int64(len(exchange.Body)),
+		Trailer:       exchange.Trailer.Clone(),
+		Request:       req,
+	}, nil
+}
+
+// CallIPC answers the call with the recorded response without contacting the node
+func (r *Replayer) CallIPC(path string, request, response any, _ func() error) error {
+	requestBody, err := json.Marshal(request)
+	if err != nil {
+		return err
+	}
+	exchange, err := r.find(ipcMethod, path, requestBody)
+	if err != nil {
+		return err
+	}
+	if exchange.Err != "" {
+		return errors.New(exchange.Err)
+	}
+	return json.Unmarshal(exchange.Body, response)
+}
+
+// find returns the latest exchange recorded until now, requests sent earlier than recorded get the first one
+func (r *Replayer) find(method, url string, requestBody []byte) (Exchange, error) {
+	exchanges := r.exchanges[exchangeKey(method, url, requestBody)]
+	if len(exchanges) == 0 {
+		return Exchange{}, fmt.Errorf("no recorded response for %s %s", method, url)
+	}
+
+	offset := clock.Since(r.start)
+	i := sort.Search(len(exchanges), func(i int) bool { return exchanges[i].Offset > offset })
+	return exchanges[max(i-1, 0)], nil
+}
+
+// replayEvents streams the events recorded since the stream was opened at the time they were received. Once the
+// recording is over the stream stays open until the request is done, like the stream of a node with nothing to send.
+func (r *Replayer) replayEvents(ctx context.Context, opened time.Duration, events []Exchange) io.ReadCloser {
+	reader, writer := io.Pipe()
+	go func() {
+		for _, event := range events {
+			if event.Offset < opened {
+				continue
+			}
+			select {
+			case <-ctx.Done():
+				writer.CloseWithError(ctx.Err())
+				return
+			case <-time.After(event.Offset - clock.Since(r.start)):
+			}
+			if _, err := writer.Write(event.Event); err != nil {
+				return
+			}
+		}
+		<-ctx.Done()
+		writer.CloseWithError(ctx.Err())
+	}()
+	return reader
+}
+
+// readRequestBody returns the body of the request along with a copy of the request whose body can still be sent
+func readRequestBody(req *http.Request) (*http.Request, []byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return req, nil, nil
+	}
+
+	body, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	req = req.Clone(req.Context())
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	return req, body, nil
+}
+
+func exchangeKey(method, url string, body []byte) string {
+	return method + " " + url + "\n" + string(body)
+}
@@ -0,0 +1,123 @@
+package recording
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/clock"
+)
+
+func TestGivenRecordedExchangesWhenReplayThenRecordedResponsesReturned(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"path":"` + r.URL.Path + `","request":"` + string(body) + `"}`))
+	}))
+	defer server.Close()
+	dir := t.TempDir()
+
+	recorder, err := NewRecorder(dir)
+	require.NoError(t, err)
+	res, err := recorder.RoundTrip(httptest.NewRequest(http.MethodPost, server.URL+"/rpc", strings.NewReader("peers")), http.DefaultTransport)
+	require.NoError(t, err)
+	recorded, _ := io.ReadAll(res.Body)
+	require.NoError(t, recorder.Close())
+
+	replayer, err := NewReplayer(dir)
+	require.NoError(t, err)
+	replayed, err := replayer.RoundTrip(httptest.NewRequest(http.MethodPost, server.URL+"/rpc", strings.NewReader("peers")), nil)
+	require.NoError(t, err)
+	replayedBody, _ := io.ReadAll(replayed.Body)
+
+	assert.Equal(t, `{"path":"/rpc","request":"peers"}`, string(recorded))
+	assert.Equal(t, recorded, replayedBody)
+	assert.Equal(t, http.StatusOK, replayed.StatusCode)
+	assert.Equal(t, "application/json", replayed.Header.Get("Content-Type"))
+
+	_, err = replayer.RoundTrip(httptest.NewRequest(http.MethodPost, server.URL+"/rpc", strings.NewReader("syncing")), nil)
+	assert.Error(t, err)
+}
+
+func TestGivenRecordedEventStreamWhenReplayThenEventsStreamedUntilRequestDone(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		for slot := range 2 {
+			fmt.Fprintf(w, "event: head\ndata: {\"slot\":\"%d\"}\n\n", slot)
+			w.(http.Flusher).Flush()
+		}
+	}))
+	defer server.Close()
+	dir := t.TempDir()
+
+	recorder, err := NewRecorder(dir)
+	require.NoError(t, err)
+	res, err := recorder.RoundTrip(httptest.NewRequest(http.MethodGet, server.URL+"/eth/v1/events?topics=head", nil), http.DefaultTransport)
+	require.NoError(t, err)
+	recorded, _ := io.ReadAll(res.Body)
+	require.NoError(t, recorder.Close())
+
+	replayer, err := NewReplayer(dir)
+	require.NoError(t, err)
+	clock.Set(replayer.Start())
+	defer clock.Set(time.Now())
+	ctx, cancel := context.WithCancel(context.Background())
+	req := httptest.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/eth/v1/events?topics=head", nil)
+	replayed, err := replayer.RoundTrip(req, nil)
+	require.NoError(t, err)
+	reader := bufio.NewReader(replayed.Body)
+	var events []string
+	for len(events) < 2 {
+		event, err := reader.ReadString('\n')
+		require.NoError(t, err)
+		if strings.HasPrefix(event, "data: ") {
+			events = append(events, strings.TrimSpace(event))
+		}
+	}
+	cancel()
+	_, err = io.ReadAll(reader)
+
+	assert.Equal(t, "event: head\ndata: {\"slot\":\"0\"}\n\nevent: head\ndata: {\"slot\":\"1\"}\n\n", string(recorded))
+	assert.Equal(t, []string{`data: {"slot":"0"}`, `data: {"slot":"1"}`}, events)
+	assert.Equal(t, "text/event-stream", replayed.Header.Get("Content-Type"))
+	assert.ErrorIs(t, err, context.Canceled, "the replayed stream stays open until the request is done")
+}
+
+func TestGivenRecordedIPCCallsWhenReplayThenRecordedResponsesDecoded(t *testing.T) {
+	dir := t.TempDir()
+	request := map[string]any{"method": "eth_syncing", "id": 1}
+
+	recorder, err := NewRecorder(dir)
+	require.NoError(t, err)
+	var response map[string]any
+	require.NoError(t, recorder.CallIPC("/data/geth.ipc", request, &response, func() error {
+		response = map[string]any{"result": false}
+		return nil
+	}))
+	failure := errors.New("failed connecting to IPC socket")
+	assert.ErrorIs(t, recorder.CallIPC("/data/other.ipc", request, &response, func() error { return failure }), failure)
+	require.NoError(t, recorder.Close())
+
+	replayer, err := NewReplayer(dir)
+	require.NoError(t, err)
+	var replayed map[string]any
+	err = replayer.CallIPC("/data/geth.ipc", request, &replayed, func() error {
+		t.Fatal("the replayed call must not reach the socket")
+		return nil
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"result": false}, replayed)
+	assert.EqualError(t, replayer.CallIPC("/data/other.ipc", request, &replayed, nil), failure.Error())
+	assert.Error(t, replayer.CallIPC("/data/geth.ipc", map[string]any{"method": "eth_chainId", "id": 1}, &replayed, nil))
+}
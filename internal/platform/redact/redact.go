@@ -0,0 +1,253 @@
+// Package redact obscures the addresses and identifiers of an installation in the outputs of a run, so reports can be
+// shared publicly, e.g. when asking for help, without revealing the infrastructure behind them.
+package redact
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Kinds of the redacted values, the tokens replacing them are prefixed with their kind, e.g. 'host-1f2e3d4c'
+const (
+	KindURL       = "url"
+	KindIP        = "ip"
+	KindHost      = "host"
+	KindPath      = "path"
+	KindValidator = "validator"
+)
+
+const (
+	// tokenLength is the number of hex digits of the hash in the tokens
+	tokenLength = 8
+	// minStandaloneIndex is the number of digits from which validator indices are redacted wherever they appear in
+	// text, shorter indices are too likely to be other numbers, e.g. a peer count, and are redacted after 'validator'
+	minStandaloneIndex = 4
+)
+
+var (
+	urlPattern  = regexp.MustCompile(`[a-zA-Z][a-zA-Z0-9+.-]*://[^\s"'<>,)]+`)
+	ipv4Pattern = regexp.MustCompile(`\b(?:\d{1,3}\.){3}\d{1,3}\b`)
+	// validatorPattern matches the index following the word validator, e.g. 'validator 12' or 'validator_index=12'
+	validatorPattern = regexp.MustCompile(`(?i)\bvalidator(?:s|_index|\s+index)?[\s:=]+"?(\d+)`)
+)
+
+// Redactor replaces the URLs and IPv4 addresses found in outputs and the registered values, e.g. the hostnames of the
+// nodes and the validator indices, with tokens. The tokens hash the values with a key drawn per redactor, so the same
+// value gets the same token throughout a run while small value spaces, like validator indices, can't be enumerated.
+// It is safe for concurrent use.
+type Redactor struct {
+	key    []byte
+	kinds  map[string]string
+	values *regexp.Regexp
+	mutex  sync.RWMutex
+}
+
+func New() *Redactor {
+	key := make([]byte, 32)
+	_, _ = rand.Read(key)
+	return &Redactor{
+		key:   key,
+		kinds: make(map[string]string),
+	}
+}
+
+// Add registers values of the kind to redact, e.g. the hostname of the machine. Empty values are ignored.
+func (r *Redactor) Add(kind string, values ...string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	for _, value := range values {
+		if value = strings.TrimSpace(value); value != "" {
+			r.kinds[value] = kind
+		}
+	}
+
+	// Longer values go first, so a hostname is redacted whole rather than a registered part of it
+	var alternatives []string
+	for value, kind := range r.kinds {
+		if kind == KindValidator && len(value) < minStandaloneIndex {
+			continue
+		}
+		alternatives = append(alternatives, value)
+	}
+	sort.Slice(alternatives, func(i, j int) bool {
+		if len(alternatives[i]) != len(alternatives[j]) {
+			return len(alternatives[i]) > len(alternatives[j])
+		}
+		return alternatives[i] < alternatives[j]
+	})
+	for i, value := range alternatives {
+		alternatives[i] = bounded(value)
+	}
+	r.values = nil
+	if len(alternatives) != 0 {
+		r.values = regexp.MustCompile(strings.Join(alternatives, "|"))
+	}
+}
+
+// String redacts the text
+func (r *Redactor) String(text string) string {
+	text = urlPattern.ReplaceAllStringFunc(text, func(match string) string {
+		// A sentence may end right after the URL
+		trimmed := strings.TrimRight(match, ".")
+		return r.token(KindURL, trimmed) + match[len(trimmed):]
+	})
+
+	r.mutex.RLock()
+	values := r.values
+	r.mutex.RUnlock()
+	if values != nil {
+		text = values.ReplaceAllStringFunc(text, func(match string) string {
+			return r.token(r.kind(match), match)
+		})
+	}
+
+	text = validatorPattern.ReplaceAllStringFunc(text, func(match string) string {
+		index := validatorPattern.FindStringSubmatch(match)[1]
+		if r.kind(index) != KindValidator {
+			return match
+		}
+		return strings.TrimSuffix(match, index) + r.token(KindValidator, index)
+	})
+	return ipv4Pattern.ReplaceAllStringFunc(text, func(match string) string {
+		return r.token(KindIP, match)
+	})
+}
+
+// JSON redacts the value as encoded in JSON, so it can be encoded again and stays valid JSON. Every string is redacted,
+// the keys of objects included. Numbers are left unless they are registered validator indices under a key naming
+// validators or indices, e.g. the indices of the configuration, which become the token.
+func (r *Redactor) JSON(value any) (any, error) {
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(encoded))
+	decoder.UseNumber()
+	var decoded any
+	if err := decoder.Decode(&decoded); err != nil {
+		return nil, err
+	}
+	return r.walk(decoded, ""), nil
+}
+
+func (r *Redactor) walk(value any, key string) any {
+	switch v := value.(type) {
+	case string:
+		return r.String(v)
+	case json.Number:
+		lower := strings.ToLower(key)
+		if (strings.Contains(lower, "validator") || strings.Contains(lower, "index") || strings.Contains(lower, "indices")) && r.kind(v.String()) == KindValidator {
+			return r.token(KindValidator, v.String())
+		}
+		return v
+	case []any:
+		for i := range v {
+			v[i] = r.walk(v[i], key)
+		}
+		return v
+	case map[string]any:
+		redacted := make(map[string]any, len(v))
+		for name, element := range v {
+			redacted[r.String(name)] = r.walk(element, name)
+		}
+		return redacted
+	default:
+		return value
+	}
+}
+
+// Writer redacts everything written to the writer line by line, lines holding a JSON object like the log records
+// are redacted as JSON. A line is written once it is complete, Close writes what is left of the last line.
+func (r *Redactor) Writer(w io.Writer) io.WriteCloser {
+	return &writer{redactor: r, out: w}
+}
+
+type writer struct {
+	redactor *Redactor
+	out      io.Writer
+	pending  []byte
+	mutex    sync.Mutex
+}
+
+func (w *writer) Write(p []byte) (int, error) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	w.pending = append(w.pending, p...)
+	for {
+		end := bytes.IndexByte(w.pending, '\n')
+		if end < 0 {
+			return len(p), nil
+		}
+		line := w.pending[:end]
+		if _, err := io.WriteString(w.out, w.redactor.line(line)+"\n"); err != nil {
+			return 0, err
+		}
+		w.pending = w.pending[end+1:]
+	}
+}
+
+func (w *writer) Close() error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	if len(w.pending) == 0 {
+		return nil
+	}
+	_, err := io.WriteString(w.out, w.redactor.line(w.pending))
+	w.pending = nil
+	return err
+}
+
+func (r *Redactor) line(line []byte) string {
+	if trimmed := bytes.TrimSpace(line); len(trimmed) != 0 && trimmed[0] == '{' {
+		decoder := json.NewDecoder(bytes.NewReader(trimmed))
+		decoder.UseNumber()
+		var decoded map[string]any
+		if decoder.Decode(&decoded) == nil {
+			if encoded, err := json.Marshal(r.walk(decoded, "")); err == nil {
+				return string(encoded)
+			}
+		}
+	}
+	return r.String(string(line))
+}
+
+func (r *Redactor) kind(value string) string {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	return r.kinds[value]
+}
+
+func (r *Redactor) token(kind, value string) string {
+	mac := hmac.New(sha256.New, r.key)
+	mac.Write([]byte(value))
+	return kind + "-" + hex.EncodeToString(mac.Sum(nil))[:tokenLength]
+}
+
+// bounded matches the value only as a whole word, e.g. the validator index '1234' isn't redacted in '12345'
+func bounded(value string) string {
+	pattern := regexp.QuoteMeta(value)
+	if isWordChar(value[0]) {
+		pattern = `\b` + pattern
+	}
+	if isWordChar(value[len(value)-1]) {
+		pattern += `\b`
+	}
+	return pattern
+}
+
+func isWordChar(c byte) bool {
+	return c == '_' || ('0' <= c && c <= '9') || ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z')
+}
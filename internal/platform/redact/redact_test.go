@@ -0,0 +1,77 @@
+package redact
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGivenTextWithAddressesWhenStringThenReplacedByStableTokens(t *testing.T) {
+	r := New()
+	r.Add(KindHost, "node1.example.com", "staking-box")
+
+	redacted := r.String("beacon node https://node1.example.com:5052/eth/v1 unreachable, dial tcp 10.0.0.5:5052 from staking-box.")
+
+	assert.NotContains(t, redacted, "node1.example.com")
+	assert.NotContains(t, redacted, "10.0.0.5")
+	assert.NotContains(t, redacted, "staking-box")
+	assert.Regexp(t, `^beacon node url-[0-9a-f]{8} unreachable, dial tcp ip-[0-9a-f]{8}:5052 from host-[0-9a-f]{8}\.$`, redacted)
+	assert.Equal(t, redacted, r.String("beacon node https://node1.example.com:5052/eth/v1 unreachable, dial tcp 10.0.0.5:5052 from staking-box."))
+	assert.Equal(t, "geth v1.14.0 synced", r.String("geth v1.14.0 synced"))
+}
+
+func TestGivenValidatorIndicesWhenStringThenOnlyTheIndicesRedacted(t *testing.T) {
+	r := New()
+	r.Add(KindValidator, "12", "123456")
+
+	assert.Regexp(t, `^proposal of validator validator-[0-9a-f]{8} in slot 1234567$`, r.String("proposal of validator 12 in slot 1234567"))
+	assert.Regexp(t, `^missed head of validator-[0-9a-f]{8}, 12 peers$`, r.String("missed head of 123456, 12 peers"))
+	assert.Equal(t, "1234567", r.String("1234567"))
+}
+
+func TestGivenValueWhenJSONThenStringsAndIndicesRedactedAsValidJSON(t *testing.T) {
+	r := New()
+	r.Add(KindValidator, "12")
+	value := map[string]any{
+		"address": "http://10.0.0.5:5052",
+		"indices": []uint64{12, 13},
+		"peers":   12,
+	}
+
+	redacted, err := r.JSON(value)
+	require.NoError(t, err)
+	encoded, err := json.Marshal(redacted)
+	require.NoError(t, err)
+
+	var decoded map[string]any
+	require.NoError(t, json.Unmarshal(encoded, &decoded))
+	assert.Regexp(t, `^url-[0-9a-f]{8}$`, decoded["address"])
+	assert.Regexp(t, `^validator-[0-9a-f]{8}$`, decoded["indices"].([]any)[0])
+	assert.Equal(t, 13.0, decoded["indices"].([]any)[1])
+	assert.Equal(t, 12.0, decoded["peers"])
+}
+
+func TestGivenLinesWrittenInPiecesWhenWriterThenLinesRedactedWhole(t *testing.T) {
+	r := New()
+	r.Add(KindValidator, "12")
+	var out bytes.Buffer
+	w := r.Writer(&out)
+
+	_, err := w.Write([]byte(`{"msg":"proposal scheduled","validator":12,"addr":"http://10.0.`))
+	require.NoError(t, err)
+	_, err = w.Write([]byte("0.5:5052\"}\nconnected to 10.0.0.5"))
+	require.NoError(t, err)
+	assert.NotContains(t, out.String(), "connected")
+	require.NoError(t, w.Close())
+
+	lines := bytes.Split(out.Bytes(), []byte("\n"))
+	require.Len(t, lines, 2)
+	var record map[string]any
+	require.NoError(t, json.Unmarshal(lines[0], &record))
+	assert.Regexp(t, `^validator-[0-9a-f]{8}$`, record["validator"])
+	assert.Regexp(t, `^url-[0-9a-f]{8}$`, record["addr"])
+	assert.Regexp(t, `^connected to ip-[0-9a-f]{8}$`, string(lines[1]))
+}
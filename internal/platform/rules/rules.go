@@ -0,0 +1,186 @@
+package rules
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/format"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/metric"
+)
+
+type Statistic string
+
+const (
+	StatisticMean Statistic = "mean"
+	StatisticMin  Statistic = "min"
+	StatisticMax  Statistic = "max"
+	StatisticLast Statistic = "last"
+)
+
+var (
+	statistics = []Statistic{StatisticMean, StatisticMin, StatisticMax, StatisticLast}
+	operators  = []metric.Operator{
+		metric.OperatorGreaterThan,
+		metric.OperatorLessThan,
+		metric.OperatorGreaterThanOrEqual,
+		metric.OperatorLessThanOrEqual,
+		metric.OperatorEqual,
+	}
+)
+
+type (
+	// Rule is a health condition over several metrics, met when all of its conditions are, e.g. the peers of both
+	// clients being low points at the network rather than at one of the clients
+	Rule struct {
+		Name       string
+		Conditions []Condition
+		Severity   metric.SeverityLevel
+		Hint       string
+	}
+
+	// Condition compares a statistic of the readings of a measurement with the threshold, durations are compared in
+	// nanoseconds
+	Condition struct {
+		Group       metric.Group
+		Metric      string
+		Measurement string
+		Statistic   Statistic
+		Operator    metric.Operator
+		Threshold   float64
+	}
+
+	// Source returns the data points of the metric of the group, false when the metric is not enabled
+	Source func(group metric.Group, name string) ([]metric.ExportedDataPoint, bool)
+
+	// Result is the outcome of a rule, along with the observed statistic of every condition
+	Result struct {
+		Rule         Rule
+		Met          bool
+		Observations []Observation
+	}
+
+	// Observation is the statistic of a condition, Measured is false when the measurement has no readings
+	Observation struct {
+		Condition Condition
+		Value     float64
+		Duration  bool
+		Measured  bool
+		Met       bool
+	}
+)
+
+// ParseThreshold parses a number or a duration, e.g. '10' or '1.5s', durations are returned in nanoseconds
+func ParseThreshold(text string) (float64, error) {
+	if number, err := strconv.ParseFloat(text, 64); err == nil {
+		return number, nil
+	}
+	duration, err := time.ParseDuration(text)
+	if err != nil {
+		return 0, fmt.Errorf("threshold '%s' should be a number or a duration", text)
+	}
+	return float64(duration), nil
+}
+
+// Validate checks the operators and statistics of the conditions, an empty statistic is the mean
+func (r *Rule) Validate() error {
+	if len(r.Conditions) == 0 {
+		return fmt.Errorf("rule '%s' should have at least one condition", r.Name)
+	}
+	for i, condition := range r.Conditions {
+		if condition.Statistic == "" {
+			r.Conditions[i].Statistic = StatisticMean
+		} else if !slices.Contains(statistics, condition.Statistic) {
+			return fmt.Errorf("rule '%s' should use one of the statistics 'mean', 'min', 'max' or 'last', got '%s'", r.Name, condition.Statistic)
+		}
+		if !slices.Contains(operators, condition.Operator) {
+			return fmt.Errorf("rule '%s' should use one of the operators '>', '<', '>=', '<=' or '==', got '%s'", r.Name, condition.Operator)
+		}
+	}
+	return nil
+}
+
+// Evaluate evaluates every rule against the data points of the source, conditions whose metric is not enabled or
+// whose measurement has no readings are not met
+func Evaluate(rules []Rule, source Source) []Result {
+	results := make([]Result, 0, len(rules))
+	for _, rule := range rules {
+		result := Result{Rule: rule, Met: true}
+		for _, condition := range rule.Conditions {
+			dataPoints, _ := source(condition.Group, condition.Metric)
+			observation := observe(condition, dataPoints)
+			result.Met = result.Met && observation.Met
+			result.Observations = append(result.Observations, observation)
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
+func observe(condition Condition, dataPoints []metric.ExportedDataPoint) Observation {
+	observation := Observation{Condition: condition}
+	var sum float64
+	var count int
+	for _, dp := range dataPoints {
+		value, ok := dp.Values[condition.Measurement]
+		if !ok {
+			continue
+		}
+		number, ok := metric.ToFloat(value)
+		if !ok {
+			continue
+		}
+		_, observation.Duration = value.(time.Duration)
+
+		switch {
+		case count == 0, condition.Statistic == StatisticLast:
+			observation.Value = number
+		case condition.Statistic == StatisticMin:
+			observation.Value = min(observation.Value, number)
+		case condition.Statistic == StatisticMax:
+			observation.Value = max(observation.Value, number)
+		}
+		sum += number
+		count++
+	}
+	if count == 0 {
+		return observation
+	}
+	if condition.Statistic == StatisticMean {
+		observation.Value = sum / float64(count)
+	}
+
+	observation.Measured = true
+	observation.Met = metric.HealthCondition[float64]{Threshold: condition.Threshold, Operator: condition.Operator}.Evaluate(observation.Value)
+	return observation
+}
+
+// String describes the observation, e.g. 'Consensus/Peers PeerCount mean=4.5 <= 10 met'
+func (o Observation) String() string {
+	c := o.Condition
+	name := fmt.Sprintf("%s/%s %s", c.Group, c.Metric, c.Measurement)
+	if !o.Measured {
+		return name + " not measured"
+	}
+
+	value, threshold := format.Number(o.Value, 2), format.Number(c.Threshold, 2)
+	if o.Duration {
+		value, threshold = format.Duration(time.Duration(o.Value)), format.Duration(time.Duration(c.Threshold))
+	}
+	outcome := "not met"
+	if o.Met {
+		outcome = "met"
+	}
+	return fmt.Sprintf("%s %s=%s %s %s %s", name, c.Statistic, value, c.Operator, threshold, outcome)
+}
+
+// String describes every observation of the result
+func (r Result) String() string {
+	lines := make([]string, 0, len(r.Observations))
+	for _, observation := range r.Observations {
+		lines = append(lines, observation.String())
+	}
+	return strings.Join(lines, " \n ")
+}
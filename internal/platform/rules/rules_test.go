@@ -0,0 +1,85 @@
+package rules
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/metric"
+)
+
+func peers(counts ...float64) []metric.ExportedDataPoint {
+	dataPoints := make([]metric.ExportedDataPoint, 0, len(counts))
+	for _, count := range counts {
+		dataPoints = append(dataPoints, metric.ExportedDataPoint{Values: map[string]any{"PeerCount": count}})
+	}
+	return dataPoints
+}
+
+func lowPeers(group metric.Group) Condition {
+	return Condition{Group: group, Metric: "Peers", Measurement: "PeerCount", Statistic: StatisticMean, Operator: metric.OperatorLessThanOrEqual, Threshold: 10}
+}
+
+func TestGivenBothClientsWithLowPeersWhenEvaluateThenRuleMet(t *testing.T) {
+	source := func(group metric.Group, name string) ([]metric.ExportedDataPoint, bool) {
+		if group == metric.ConsensusGroup {
+			return peers(4, 6), true
+		}
+		return peers(8), true
+	}
+	rule := Rule{Name: "Network", Severity: metric.SeverityHigh, Conditions: []Condition{lowPeers(metric.ConsensusGroup), lowPeers(metric.ExecutionGroup)}}
+
+	results := Evaluate([]Rule{rule}, source)
+
+	require.Len(t, results, 1)
+	assert.True(t, results[0].Met)
+	assert.Equal(t, 5.0, results[0].Observations[0].Value)
+	assert.Equal(t, "Consensus/Peers PeerCount mean=5.00 <= 10.00 met", results[0].Observations[0].String())
+}
+
+func TestGivenOneConditionNotMeasuredWhenEvaluateThenRuleNotMet(t *testing.T) {
+	source := func(group metric.Group, name string) ([]metric.ExportedDataPoint, bool) {
+		if group == metric.ConsensusGroup {
+			return peers(4), true
+		}
+		return nil, false
+	}
+	rule := Rule{Name: "Network", Severity: metric.SeverityHigh, Conditions: []Condition{lowPeers(metric.ConsensusGroup), lowPeers(metric.ExecutionGroup)}}
+
+	results := Evaluate([]Rule{rule}, source)
+
+	assert.False(t, results[0].Met)
+	assert.False(t, results[0].Observations[1].Measured)
+}
+
+func TestGivenStatisticsWhenEvaluateThenStatisticCompared(t *testing.T) {
+	source := func(metric.Group, string) ([]metric.ExportedDataPoint, bool) { return peers(30, 2, 20), true }
+	for statistic, expected := range map[Statistic]float64{StatisticMin: 2, StatisticMax: 30, StatisticLast: 20} {
+		condition := lowPeers(metric.ConsensusGroup)
+		condition.Statistic = statistic
+
+		results := Evaluate([]Rule{{Name: "Peers", Conditions: []Condition{condition}}}, source)
+
+		assert.Equal(t, expected, results[0].Observations[0].Value, statistic)
+	}
+}
+
+func TestGivenDurationWhenParseThresholdThenNanoseconds(t *testing.T) {
+	threshold, err := ParseThreshold("1.5s")
+	require.NoError(t, err)
+	assert.Equal(t, float64(1500*time.Millisecond), threshold)
+
+	_, err = ParseThreshold("fast")
+	assert.Error(t, err)
+}
+
+func TestGivenUnknownStatisticWhenValidateThenError(t *testing.T) {
+	rule := Rule{Name: "Peers", Conditions: []Condition{{Operator: metric.OperatorLessThan, Statistic: "median"}}}
+	assert.Error(t, rule.Validate())
+
+	rule.Conditions[0].Statistic = ""
+	require.NoError(t, rule.Validate())
+	assert.Equal(t, StatisticMean, rule.Conditions[0].Statistic)
+}
@@ -0,0 +1,142 @@
+package run
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+const (
+	CompressionNone = ""
+	CompressionGzip = "gzip"
+	// CompressionZstd compresses with the zstd command, which has to be installed
+	CompressionZstd = "zstd"
+
+	gzipExtension = ".gz"
+	zstdExtension = ".zst"
+)
+
+// CompressionExtension returns the file extension of the compression, none without compression
+func CompressionExtension(compression string) string {
+	switch compression {
+	case CompressionGzip:
+		return gzipExtension
+	case CompressionZstd:
+		return zstdExtension
+	default:
+		return ""
+	}
+}
+
+// TrimCompressionExtension returns the name of a compressed file without its compression extension
+func TrimCompressionExtension(name string) string {
+	return strings.TrimSuffix(strings.TrimSuffix(name, gzipExtension), zstdExtension)
+}
+
+// WriteCompressedJSON writes the value as a JSON artifact compressed with the compression, the name gets its
+// extension appended
+func (r *Run) WriteCompressedJSON(name, compression string, value any) error {
+	value, err := r.redacted(value)
+	if err != nil {
+		return err
+	}
+	file, err := r.Create(name + CompressionExtension(compression))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	writer, err := compress(file, compression)
+	if err != nil {
+		return err
+	}
+	if err := json.NewEncoder(writer).Encode(value); err != nil {
+		_ = writer.Close()
+		return err
+	}
+	return writer.Close()
+}
+
+// Open opens an artifact, decompressing it by its extension
+func Open(path string) (io.ReadCloser, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case strings.HasSuffix(path, gzipExtension):
+		reader, err := gzip.NewReader(file)
+		if err != nil {
+			_ = file.Close()
+			return nil, errors.Join(err, fmt.Errorf("failed decompressing '%s'", path))
+		}
+		return &closers{Reader: reader, close: []func() error{reader.Close, file.Close}}, nil
+	case strings.HasSuffix(path, zstdExtension):
+		command := exec.Command(CompressionZstd, "-q", "-d", "-c")
+		command.Stdin = file
+		out, err := command.StdoutPipe()
+		if err != nil {
+			_ = file.Close()
+			return nil, err
+		}
+		if err := command.Start(); err != nil {
+			_ = file.Close()
+			return nil, errors.Join(err, fmt.Errorf("failed decompressing '%s' with the zstd command", path))
+		}
+		return &closers{Reader: out, close: []func() error{command.Wait, file.Close}}, nil
+	default:
+		return file, nil
+	}
+}
+
+// compress wraps the writer with the compression, closing the returned writer flushes the compressed data
+func compress(w io.Writer, compression string) (io.WriteCloser, error) {
+	switch compression {
+	case CompressionNone:
+		return nopCloser{w}, nil
+	case CompressionGzip:
+		return gzip.NewWriter(w), nil
+	case CompressionZstd:
+		command := exec.Command(CompressionZstd, "-q", "-c")
+		command.Stdout = w
+		in, err := command.StdinPipe()
+		if err != nil {
+			return nil, err
+		}
+		if err := command.Start(); err != nil {
+			return nil, errors.Join(err, errors.New("failed starting the zstd command"))
+		}
+		return &closers{Writer: in, close: []func() error{in.Close, command.Wait}}, nil
+	default:
+		return nil, fmt.Errorf("unknown compression '%s'", compression)
+	}
+}
+
+// closers closes the layers of a compressed stream in order
+type closers struct {
+	io.Reader
+	io.Writer
+	close []func() error
+}
+
+func (c *closers) Close() error {
+	var err error
+	for _, closeLayer := range c.close {
+		err = errors.Join(err, closeLayer())
+	}
+	return err
+}
+
+type nopCloser struct {
+	io.Writer
+}
+
+func (nopCloser) Close() error {
+	return nil
+}
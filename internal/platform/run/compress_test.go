@@ -0,0 +1,23 @@
+package run
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGivenGzipCompressionWhenWriteCompressedJSONThenOpenDecompresses(t *testing.T) {
+	r := &Run{Dir: t.TempDir()}
+	require.NoError(t, r.WriteCompressedJSON("datapoints/chunk.json", CompressionGzip, map[string]int{"PeerCount": 50}))
+
+	reader, err := Open(r.Path("datapoints/chunk.json.gz"))
+	require.NoError(t, err)
+	defer reader.Close()
+
+	var decoded map[string]int
+	require.NoError(t, json.NewDecoder(reader).Decode(&decoded))
+	assert.Equal(t, 50, decoded["PeerCount"])
+	assert.Equal(t, "datapoints/chunk.json", TrimCompressionExtension("datapoints/chunk.json.gz"))
+}
@@ -0,0 +1,70 @@
+package run
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// Retention bounds the runs kept in the artifacts directory, zero values don't bound it
+type Retention struct {
+	// Runs is the number of most recent runs kept
+	Runs int
+	// MaxAge is the age from which runs are removed
+	MaxAge time.Duration
+}
+
+// Prune removes the run directories of the base directory exceeding the retention, the newest first kept. Directories
+// not named by a run ID are left alone, as well as the excluded run, e.g. the one in progress. It returns the IDs of
+// the removed runs, only listing them when dry.
+func Prune(baseDir string, retention Retention, exclude string, dry bool) ([]string, error) {
+	if retention.Runs <= 0 && retention.MaxAge <= 0 {
+		return nil, nil
+	}
+
+	entries, err := os.ReadDir(baseDir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Join(err, errors.New("failed listing runs"))
+	}
+
+	type storedRun struct {
+		id        string
+		startedAt time.Time
+	}
+	var runs []storedRun
+	for _, entry := range entries {
+		startedAt, ok := ulidTime(entry.Name())
+		if !entry.IsDir() || !ok || entry.Name() == exclude {
+			continue
+		}
+		runs = append(runs, storedRun{id: entry.Name(), startedAt: startedAt})
+	}
+	// Run IDs sort by their start, newest first
+	sort.Slice(runs, func(i, j int) bool { return runs[i].id > runs[j].id })
+
+	kept := 0
+	if exclude != "" {
+		kept++
+	}
+	var pruned []string
+	for _, r := range runs {
+		expired := retention.MaxAge > 0 && time.Since(r.startedAt) > retention.MaxAge
+		if !expired && (retention.Runs <= 0 || kept < retention.Runs) {
+			kept++
+			continue
+		}
+		if !dry {
+			if err := os.RemoveAll(filepath.Join(baseDir, r.id)); err != nil {
+				return pruned, errors.Join(err, fmt.Errorf("failed removing run '%s'", r.id))
+			}
+		}
+		pruned = append(pruned, r.id)
+	}
+	return pruned, nil
+}
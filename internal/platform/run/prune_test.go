@@ -0,0 +1,63 @@
+package run
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func createRun(t *testing.T, baseDir string, startedAt time.Time) string {
+	id, err := newULID(startedAt)
+	require.NoError(t, err)
+	require.NoError(t, os.MkdirAll(filepath.Join(baseDir, id), 0o755))
+	return id
+}
+
+func TestGivenRunIDWhenUlidTimeThenStartDecoded(t *testing.T) {
+	startedAt := time.UnixMilli(time.Now().UnixMilli())
+	id, err := newULID(startedAt)
+	require.NoError(t, err)
+
+	decoded, ok := ulidTime(id)
+	assert.True(t, ok)
+	assert.True(t, startedAt.Equal(decoded))
+
+	_, ok = ulidTime("reports")
+	assert.False(t, ok)
+}
+
+func TestGivenMoreRunsThanKeptWhenPruneThenOldestRemoved(t *testing.T) {
+	baseDir := t.TempDir()
+	now := time.Now()
+	oldest := createRun(t, baseDir, now.Add(-3*time.Hour))
+	older := createRun(t, baseDir, now.Add(-2*time.Hour))
+	newest := createRun(t, baseDir, now.Add(-time.Hour))
+	require.NoError(t, os.MkdirAll(filepath.Join(baseDir, "baseline"), 0o755))
+
+	pruned, err := Prune(baseDir, Retention{Runs: 2}, "", true)
+	require.NoError(t, err)
+	assert.Equal(t, []string{oldest}, pruned)
+	assert.DirExists(t, filepath.Join(baseDir, oldest))
+
+	pruned, err = Prune(baseDir, Retention{Runs: 2}, newest, false)
+	require.NoError(t, err)
+	assert.Equal(t, []string{oldest}, pruned)
+	assert.NoDirExists(t, filepath.Join(baseDir, oldest))
+	assert.DirExists(t, filepath.Join(baseDir, older))
+	assert.DirExists(t, filepath.Join(baseDir, "baseline"))
+}
+
+func TestGivenExpiredRunWhenPruneByAgeThenRemoved(t *testing.T) {
+	baseDir := t.TempDir()
+	expired := createRun(t, baseDir, time.Now().Add(-48*time.Hour))
+	recent := createRun(t, baseDir, time.Now().Add(-time.Hour))
+
+	pruned, err := Prune(baseDir, Retention{MaxAge: 24 * time.Hour}, "", false)
+	require.NoError(t, err)
+	assert.Equal(t, []string{expired}, pruned)
+	assert.DirExists(t, filepath.Join(baseDir, recent))
+}
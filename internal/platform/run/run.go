@@ -0,0 +1,135 @@
+package run
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+const metadataFileName = "metadata.json"
+
+type (
+	// Run holds the identity of a single benchmark run and the directory its artifacts are written to
+	Run struct {
+		ID       string
+		Dir      string
+		Metadata Metadata
+		hooks    []func()
+		// redact obscures the JSON artifacts before they are written, they are written as they are when nil
+		redact func(value any) (any, error)
+		mutex  sync.Mutex
+	}
+
+	Metadata struct {
+		RunID      string         `json:"run_id"`
+		Version    string         `json:"version"`
+		StartedAt  time.Time      `json:"started_at"`
+		FinishedAt time.Time      `json:"finished_at"`
+		Sessions   []string       `json:"sessions"`
+		Config     any            `json:"config"`
+		Extra      map[string]any `json:"extra,omitempty"`
+	}
+)
+
+// New generates a run ID and creates the '<baseDir>/<run-id>' artifacts directory
+func New(baseDir string) (*Run, error) {
+	startedAt := time.Now()
+	id, err := newULID(startedAt)
+	if err != nil {
+		return nil, errors.Join(err, errors.New("failed generating run ID"))
+	}
+
+	dir := filepath.Join(baseDir, id)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, errors.Join(err, errors.New("failed creating run artifacts directory"))
+	}
+
+	return &Run{
+		ID:  id,
+		Dir: dir,
+		Metadata: Metadata{
+			RunID:     id,
+			StartedAt: startedAt,
+			Extra:     make(map[string]any),
+		},
+	}, nil
+}
+
+// Path returns the path of the named artifact inside the run directory
+func (r *Run) Path(name string) string {
+	return filepath.Join(r.Dir, name)
+}
+
+// Create creates the named artifact file inside the run directory
+func (r *Run) Create(name string) (*os.File, error) {
+	if err := os.MkdirAll(filepath.Dir(r.Path(name)), 0o755); err != nil {
+		return nil, err
+	}
+	return os.Create(r.Path(name))
+}
+
+// Redact registers the function obscuring the values of the JSON artifacts written afterwards, e.g. the addresses of
+// the nodes in the metadata
+func (r *Run) Redact(redact func(value any) (any, error)) {
+	r.redact = redact
+}
+
+// redacted returns the value obscured by the registered function
+func (r *Run) redacted(value any) (any, error) {
+	if r.redact == nil {
+		return value, nil
+	}
+	return r.redact(value)
+}
+
+// WriteJSON writes the value as an indented JSON artifact
+func (r *Run) WriteJSON(name string, value any) error {
+	value, err := r.redacted(value)
+	if err != nil {
+		return err
+	}
+	file, err := r.Create(name)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	encoder := json.NewEncoder(file)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(value)
+}
+
+// SetMetadata records an additional metadata entry, e.g. measurements taken once per run
+func (r *Run) SetMetadata(key string, value any) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.Metadata.Extra[key] = value
+}
+
+// BeforeFinish registers a hook executed when the run finishes, before the metadata is written
+func (r *Run) BeforeFinish(hook func()) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.hooks = append(r.hooks, hook)
+}
+
+// Finish runs the registered hooks, stamps the finish time and writes the run metadata
+func (r *Run) Finish() error {
+	r.mutex.Lock()
+	hooks := r.hooks
+	r.mutex.Unlock()
+	for _, hook := range hooks {
+		hook()
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.Metadata.FinishedAt = time.Now()
+	return r.WriteJSON(metadataFileName, r.Metadata)
+}
@@ -0,0 +1,60 @@
+package run
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"math/big"
+	"strings"
+	"time"
+)
+
+const (
+	crockfordAlphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+	ulidLength        = 26
+)
+
+// newULID generates a lexicographically sortable identifier: 48 bits of millisecond timestamp followed by 80 random bits,
+// encoded with Crockford's base32 as per the ULID specification
+func newULID(t time.Time) (string, error) {
+	var id [16]byte
+
+	var timestamp [8]byte
+	binary.BigEndian.PutUint64(timestamp[:], uint64(t.UnixMilli()))
+	copy(id[:6], timestamp[2:])
+
+	if _, err := rand.Read(id[6:]); err != nil {
+		return "", err
+	}
+
+	var (
+		value   = new(big.Int).SetBytes(id[:])
+		base    = big.NewInt(int64(len(crockfordAlphabet)))
+		mod     = new(big.Int)
+		encoded = make([]byte, ulidLength)
+	)
+	for i := ulidLength - 1; i >= 0; i-- {
+		value.DivMod(value, base, mod)
+		encoded[i] = crockfordAlphabet[mod.Int64()]
+	}
+
+	return string(encoded), nil
+}
+
+// ulidTime decodes the timestamp of a ULID, reporting false for strings which aren't ULIDs
+func ulidTime(id string) (time.Time, bool) {
+	if len(id) != ulidLength {
+		return time.Time{}, false
+	}
+	var value big.Int
+	base := big.NewInt(int64(len(crockfordAlphabet)))
+	for _, char := range id {
+		digit := strings.IndexRune(crockfordAlphabet, char)
+		if digit == -1 {
+			return time.Time{}, false
+		}
+		value.Mul(&value, base)
+		value.Add(&value, big.NewInt(int64(digit)))
+	}
+	// The 128 bits of the ULID end with 80 random bits
+	return time.UnixMilli(value.Rsh(&value, 80).Int64()), true
+}
@@ -2,8 +2,10 @@ package host
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
+	"net"
 	"net/http"
 )
 
@@ -22,14 +24,19 @@ func New(port uint16, handler http.Handler) *WebHost {
 	}
 }
 
-func (h *WebHost) Run() {
+// Run listens on the port and serves in the background, it fails when the port can't be listened on, e.g. because it
+// is taken
+func (h *WebHost) Run() error {
+	listener, err := net.Listen("tcp", h.server.Addr)
+	if err != nil {
+		return errors.Join(err, fmt.Errorf("failed listening on '%s' for the web host", h.server.Addr))
+	}
 	go func() {
-		if err := h.server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			const errMsg = "error running web host"
-			slog.With("error", err.Error()).Error(errMsg)
-			panic(errMsg)
+		if err := h.server.Serve(listener); err != nil && err != http.ErrServerClosed {
+			slog.With("error", err.Error()).Error("error running web host")
 		}
 	}()
+	return nil
 }
 
 func (h *WebHost) Terminate(ctx context.Context) error {
@@ -1,14 +1,32 @@
 package route
 
 import (
+	"encoding/json"
+	"errors"
+	"io"
 	"net/http"
 
 	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/annotation"
 )
 
-type Router struct {
-	router *http.ServeMux
-}
+// ErrMetricNotFound is returned by the metric control when no session has the metric
+var ErrMetricNotFound = errors.New("metric not found")
+
+type (
+	Router struct {
+		router *http.ServeMux
+	}
+
+	// MetricState tells whether a metric of a session is measuring
+	MetricState struct {
+		Session string `json:"session,omitempty"`
+		Group   string `json:"group"`
+		Name    string `json:"name"`
+		Enabled bool   `json:"enabled"`
+	}
+)
 
 func NewRouter() *Router {
 	router := http.NewServeMux()
@@ -22,6 +40,73 @@ func (r *Router) WithMetrics() *Router {
 	return r
 }
 
+// WithReport serves the report written by the function, e.g. an interim report of a running benchmark
+func (r *Router) WithReport(render func(w io.Writer)) *Router {
+	r.router.HandleFunc("/report", func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		render(w)
+	})
+	return r
+}
+
+// WithMetricControl lists the metrics on GET /api/v1/metrics and switches them on and off on
+// POST /api/v1/metrics/{group}/{name}/enable and /disable, e.g. to stop a load heavy metric during a proposal
+func (r *Router) WithMetricControl(list func() []MetricState, set func(group, name string, enabled bool) ([]MetricState, error)) *Router {
+	r.router.HandleFunc("GET /api/v1/metrics", func(w http.ResponseWriter, req *http.Request) {
+		writeJSON(w, http.StatusOK, list())
+	})
+	r.router.HandleFunc("POST /api/v1/metrics/{group}/{name}/{action}", func(w http.ResponseWriter, req *http.Request) {
+		var enabled bool
+		switch req.PathValue("action") {
+		case "enable":
+			enabled = true
+		case "disable":
+		default:
+			writeJSON(w, http.StatusNotFound, map[string]string{"error": "action should be 'enable' or 'disable'"})
+			return
+		}
+
+		states, err := set(req.PathValue("group"), req.PathValue("name"), enabled)
+		switch {
+		case errors.Is(err, ErrMetricNotFound):
+			writeJSON(w, http.StatusNotFound, map[string]string{"error": err.Error()})
+		case err != nil:
+			writeJSON(w, http.StatusConflict, map[string]string{"error": err.Error()})
+		default:
+			writeJSON(w, http.StatusOK, states)
+		}
+	})
+	return r
+}
+
+// WithAnnotations lists the annotations of the run on GET /api/v1/annotations and adds the one posted as JSON, e.g.
+// '{"text": "restarted geth"}', on POST /api/v1/annotations
+func (r *Router) WithAnnotations(store *annotation.Store) *Router {
+	r.router.HandleFunc("GET /api/v1/annotations", func(w http.ResponseWriter, req *http.Request) {
+		writeJSON(w, http.StatusOK, store.List())
+	})
+	r.router.HandleFunc("POST /api/v1/annotations", func(w http.ResponseWriter, req *http.Request) {
+		var posted annotation.Annotation
+		if err := json.NewDecoder(io.LimitReader(req.Body, 1<<16)).Decode(&posted); err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "body should be an annotation as JSON, e.g. {\"text\": \"restarted geth\"}"})
+			return
+		}
+		added, err := store.Add(posted)
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+			return
+		}
+		writeJSON(w, http.StatusCreated, added)
+	})
+	return r
+}
+
 func (r *Router) Router() *http.ServeMux {
 	return r.router
 }
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
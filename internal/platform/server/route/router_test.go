@@ -0,0 +1,82 @@
+package route
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/annotation"
+)
+
+func TestGivenMetricControlWhenDisabledThenSwitchedOff(t *testing.T) {
+	var switched []string
+	router := NewRouter().WithMetricControl(
+		func() []MetricState { return []MetricState{{Group: "Consensus", Name: "Head Delay", Enabled: true}} },
+		func(group, name string, enabled bool) ([]MetricState, error) {
+			if name != "Head Delay" {
+				return nil, ErrMetricNotFound
+			}
+			switched = append(switched, group+"/"+name)
+			return []MetricState{{Group: group, Name: name, Enabled: enabled}}, nil
+		}).Router()
+
+	res := httptest.NewRecorder()
+	router.ServeHTTP(res, httptest.NewRequest(http.MethodPost, "/api/v1/metrics/Consensus/Head%20Delay/disable", nil))
+
+	require.Equal(t, http.StatusOK, res.Code)
+	var states []MetricState
+	require.NoError(t, json.NewDecoder(res.Body).Decode(&states))
+	assert.Equal(t, []MetricState{{Group: "Consensus", Name: "Head Delay", Enabled: false}}, states)
+	assert.Equal(t, []string{"Consensus/Head Delay"}, switched)
+}
+
+func TestGivenMetricControlWhenUnknownMetricOrActionThenNotFound(t *testing.T) {
+	router := NewRouter().WithMetricControl(
+		func() []MetricState { return nil },
+		func(group, name string, enabled bool) ([]MetricState, error) { return nil, ErrMetricNotFound }).Router()
+
+	for _, path := range []string{"/api/v1/metrics/Consensus/Unknown/enable", "/api/v1/metrics/Consensus/Sync/pause"} {
+		res := httptest.NewRecorder()
+		router.ServeHTTP(res, httptest.NewRequest(http.MethodPost, path, nil))
+
+		assert.Equal(t, http.StatusNotFound, res.Code, path)
+	}
+
+	res := httptest.NewRecorder()
+	router.ServeHTTP(res, httptest.NewRequest(http.MethodGet, "/api/v1/metrics/Consensus/Sync/enable", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, res.Code)
+}
+
+func TestGivenAnnotationPostedWhenListedThenReturned(t *testing.T) {
+	store := annotation.NewStore()
+	router := NewRouter().WithAnnotations(store).Router()
+
+	res := httptest.NewRecorder()
+	router.ServeHTTP(res, httptest.NewRequest(http.MethodPost, "/api/v1/annotations", strings.NewReader(`{"text": "restarted geth"}`)))
+	require.Equal(t, http.StatusCreated, res.Code)
+
+	res = httptest.NewRecorder()
+	router.ServeHTTP(res, httptest.NewRequest(http.MethodGet, "/api/v1/annotations", nil))
+	require.Equal(t, http.StatusOK, res.Code)
+	var annotations []annotation.Annotation
+	require.NoError(t, json.NewDecoder(res.Body).Decode(&annotations))
+	require.Len(t, annotations, 1)
+	assert.Equal(t, "restarted geth", annotations[0].Text)
+	assert.Equal(t, annotation.SourceUser, annotations[0].Source)
+}
+
+func TestGivenInvalidAnnotationWhenPostedThenBadRequest(t *testing.T) {
+	router := NewRouter().WithAnnotations(annotation.NewStore()).Router()
+
+	for _, body := range []string{`{"text": ""}`, `restarted geth`} {
+		res := httptest.NewRecorder()
+		router.ServeHTTP(res, httptest.NewRequest(http.MethodPost, "/api/v1/annotations", strings.NewReader(body)))
+
+		assert.Equal(t, http.StatusBadRequest, res.Code, body)
+	}
+}
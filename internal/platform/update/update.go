@@ -0,0 +1,272 @@
+package update
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// Repository publishes the releases of the benchmark
+	Repository = "Harikakasimahanthi/benchmark-test"
+
+	githubAPI      = "https://api.github.com"
+	checksumsAsset = "checksums.txt"
+	requestTimeout = time.Minute
+)
+
+type (
+	// Updater looks up the latest release of the repository and installs its binary
+	Updater struct {
+		repository string
+		api        string
+		client     *http.Client
+	}
+
+	Release struct {
+		Version string  `json:"tag_name"`
+		URL     string  `json:"html_url"`
+		Assets  []Asset `json:"assets"`
+	}
+
+	Asset struct {
+		Name string `json:"name"`
+		URL  string `json:"browser_download_url"`
+	}
+)
+
+func New(repository string) *Updater {
+	return &Updater{
+		repository: repository,
+		api:        githubAPI,
+		client:     &http.Client{Timeout: requestTimeout},
+	}
+}
+
+// Latest returns the latest release of the repository, pre-releases and drafts are not considered
+func (u *Updater) Latest(ctx context.Context) (Release, error) {
+	var release Release
+	body, err := u.get(ctx, fmt.Sprintf("%s/repos/%s/releases/latest", u.api, u.repository), "application/vnd.github+json")
+	if err != nil {
+		return release, errors.Join(err, errors.New("failed fetching the latest release"))
+	}
+	if err := json.Unmarshal(body, &release); err != nil {
+		return release, errors.Join(err, errors.New("failed decoding the latest release"))
+	}
+	return release, nil
+}
+
+// Install downloads the binary of the release built for this platform and replaces the executable at the path with
+// it. The download is verified against the checksums of the release, releases without checksums aren't installed.
+func (u *Updater) Install(ctx context.Context, release Release, path string) error {
+	asset, ok := release.asset(runtime.GOOS, runtime.GOARCH)
+	if !ok {
+		return fmt.Errorf("release %s has no binary for %s/%s", release.Version, runtime.GOOS, runtime.GOARCH)
+	}
+
+	data, err := u.get(ctx, asset.URL, "application/octet-stream")
+	if err != nil {
+		return errors.Join(err, fmt.Errorf("failed downloading '%s'", asset.Name))
+	}
+	if err := u.verify(ctx, release, asset.Name, data); err != nil {
+		return err
+	}
+
+	binary := data
+	if strings.HasSuffix(asset.Name, ".tar.gz") || strings.HasSuffix(asset.Name, ".tgz") {
+		if binary, err = extract(data, filepath.Base(path)); err != nil {
+			return errors.Join(err, fmt.Errorf("failed extracting '%s'", asset.Name))
+		}
+	}
+
+	// The new binary is written next to the executable, so renaming it over the executable is atomic
+	temp := filepath.Join(filepath.Dir(path), "."+filepath.Base(path)+".new")
+	if err := os.WriteFile(temp, binary, 0o755); err != nil {
+		return errors.Join(err, errors.New("failed writing the new binary"))
+	}
+	if err := os.Rename(temp, path); err != nil {
+		_ = os.Remove(temp)
+		return errors.Join(err, fmt.Errorf("failed replacing '%s'", path))
+	}
+	return nil
+}
+
+// asset returns the archive or binary of the release built for the platform, e.g. 'benchmark_linux_arm64.tar.gz'. The
+// platform is matched as a whole token, so 'linux_arm' doesn't match the binary of 'linux_arm64'.
+func (r Release) asset(goos, goarch string) (Asset, bool) {
+	platform := goos + "_" + goarch
+	for _, asset := range r.Assets {
+		name := strings.ToLower(asset.Name)
+		if name == checksumsAsset || strings.HasSuffix(name, ".zip") || strings.HasSuffix(name, ".sha256") {
+			continue
+		}
+		if hasToken(name, platform) {
+			return asset, true
+		}
+	}
+	return Asset{}, false
+}
+
+// hasToken tells whether the name contains the token delimited by the start or end of the name or by a separator
+func hasToken(name, token string) bool {
+	for i := 0; i+len(token) <= len(name); i++ {
+		if !strings.HasPrefix(name[i:], token) {
+			continue
+		}
+		end := i + len(token)
+		if (i == 0 || isSeparator(name[i-1])) && (end == len(name) || isSeparator(name[end])) {
+			return true
+		}
+	}
+	return false
+}
+
+func isSeparator(c byte) bool {
+	return c == '_' || c == '-' || c == '.'
+}
+
+// verify compares the checksum of the downloaded asset with the one published by the release
+func (u *Updater) verify(ctx context.Context, release Release, name string, data []byte) error {
+	var checksums *Asset
+	for _, asset := range release.Assets {
+		if asset.Name == checksumsAsset {
+			checksums = &asset
+			break
+		}
+	}
+	if checksums == nil {
+		return fmt.Errorf("release %s publishes no %s to verify '%s' with", release.Version, checksumsAsset, name)
+	}
+
+	list, err := u.get(ctx, checksums.URL, "text/plain")
+	if err != nil {
+		return errors.Join(err, errors.New("failed downloading the checksums of the release"))
+	}
+	sum := sha256.Sum256(data)
+	scanner := bufio.NewScanner(bytes.NewReader(list))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 && fields[1] == name {
+			if !strings.EqualFold(fields[0], hex.EncodeToString(sum[:])) {
+				return fmt.Errorf("checksum of '%s' does not match the one of the release", name)
+			}
+			return nil
+		}
+	}
+	return fmt.Errorf("the checksums of the release do not list '%s'", name)
+}
+
+func (u *Updater) get(ctx context.Context, url, accept string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", accept)
+
+	res, err := u.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s responded with status %d", url, res.StatusCode)
+	}
+	return io.ReadAll(res.Body)
+}
+
+// extract returns the file of the archive named like the executable, or its only file
+func extract(archive []byte, name string) ([]byte, error) {
+	gz, err := gzip.NewReader(bytes.NewReader(archive))
+	if err != nil {
+		return nil, err
+	}
+	defer gz.Close()
+
+	var files [][]byte
+	reader := tar.NewReader(gz)
+	for {
+		header, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		data, err := io.ReadAll(reader)
+		if err != nil {
+			return nil, err
+		}
+		if filepath.Base(header.Name) == name {
+			return data, nil
+		}
+		if header.FileInfo().Mode()&0o111 != 0 {
+			files = append(files, data)
+		}
+	}
+	if len(files) != 1 {
+		return nil, fmt.Errorf("the archive has no single executable named '%s'", name)
+	}
+	return files[0], nil
+}
+
+// Newer tells whether the version is newer than the current one, e.g. 'v1.2.0' is newer than '1.1'. Versions which
+// are not dotted numbers are never newer, so development builds are not replaced.
+func Newer(version, current string) bool {
+	v, ok := parseVersion(version)
+	if !ok {
+		return false
+	}
+	c, ok := parseVersion(current)
+	if !ok {
+		return false
+	}
+	for i := 0; i < max(len(v), len(c)); i++ {
+		var a, b int
+		if i < len(v) {
+			a = v[i]
+		}
+		if i < len(c) {
+			b = c[i]
+		}
+		if a != b {
+			return a > b
+		}
+	}
+	return false
+}
+
+// parseVersion parses the numbers of a version, the 'v' prefix and pre-release or build suffixes are ignored
+func parseVersion(version string) ([]int, bool) {
+	version = strings.TrimPrefix(strings.TrimSpace(version), "v")
+	if i := strings.IndexAny(version, "-+"); i >= 0 {
+		version = version[:i]
+	}
+	var numbers []int
+	for _, part := range strings.Split(version, ".") {
+		number, err := strconv.Atoi(part)
+		if err != nil {
+			return nil, false
+		}
+		numbers = append(numbers, number)
+	}
+	return numbers, true
+}
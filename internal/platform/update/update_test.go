@@ -0,0 +1,122 @@
+package update
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGivenVersionsWhenNewerThenComparedNumerically(t *testing.T) {
+	assert.True(t, Newer("v1.10.0", "1.9"))
+	assert.True(t, Newer("1.0.1", "v1.0"))
+	assert.False(t, Newer("v1.0", "1.0.0"))
+	assert.False(t, Newer("v1.2.0-rc1", "1.2"))
+	assert.False(t, Newer("v2.0", "dev"))
+}
+
+func TestGivenReleaseWhenInstallThenExecutableReplaced(t *testing.T) {
+	binary := []byte("new binary")
+	sum := sha256.Sum256(binary)
+	asset := fmt.Sprintf("benchmark_%s_%s", runtime.GOOS, runtime.GOARCH)
+
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	defer server.Close()
+	mux.HandleFunc("/repos/owner/repo/releases/latest", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(Release{Version: "v2.0.0", Assets: []Asset{
+			{Name: checksumsAsset, URL: server.URL + "/checksums"},
+			{Name: asset, URL: server.URL + "/binary"},
+		}})
+	})
+	mux.HandleFunc("/checksums", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, "%s  %s\n", hex.EncodeToString(sum[:]), asset)
+	})
+	mux.HandleFunc("/binary", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(binary)
+	})
+
+	updater := New("owner/repo")
+	updater.api = server.URL
+	release, err := updater.Latest(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "v2.0.0", release.Version)
+
+	path := filepath.Join(t.TempDir(), "benchmark")
+	require.NoError(t, os.WriteFile(path, []byte("old binary"), 0o755))
+	require.NoError(t, updater.Install(context.Background(), release, path))
+
+	installed, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, binary, installed)
+}
+
+func TestGivenChecksumMismatchWhenInstallThenExecutableKept(t *testing.T) {
+	asset := fmt.Sprintf("benchmark_%s_%s", runtime.GOOS, runtime.GOARCH)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/checksums" {
+			fmt.Fprintf(w, "%064d  %s\n", 0, asset)
+			return
+		}
+		_, _ = w.Write([]byte("tampered binary"))
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "benchmark")
+	require.NoError(t, os.WriteFile(path, []byte("old binary"), 0o755))
+	release := Release{Version: "v2.0.0", Assets: []Asset{
+		{Name: checksumsAsset, URL: server.URL + "/checksums"},
+		{Name: asset, URL: server.URL + "/binary"},
+	}}
+
+	assert.Error(t, New("owner/repo").Install(context.Background(), release, path))
+	installed, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "old binary", string(installed))
+}
+
+func TestGivenReleaseWithoutChecksumsWhenInstallThenExecutableKept(t *testing.T) {
+	asset := fmt.Sprintf("benchmark_%s_%s", runtime.GOOS, runtime.GOARCH)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("unverified binary"))
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "benchmark")
+	require.NoError(t, os.WriteFile(path, []byte("old binary"), 0o755))
+	release := Release{Version: "v2.0.0", Assets: []Asset{{Name: asset, URL: server.URL + "/binary"}}}
+
+	assert.Error(t, New("owner/repo").Install(context.Background(), release, path))
+	installed, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "old binary", string(installed))
+}
+
+func TestGivenSimilarArchitecturesWhenAssetThenExactPlatformMatched(t *testing.T) {
+	release := Release{Assets: []Asset{
+		{Name: "benchmark_linux_arm64.tar.gz"},
+		{Name: "benchmark_linux_arm.tar.gz"},
+		{Name: "benchmark_linux_amd64v3.tar.gz"},
+	}}
+
+	asset, ok := release.asset("linux", "arm")
+	assert.True(t, ok)
+	assert.Equal(t, "benchmark_linux_arm.tar.gz", asset.Name)
+
+	asset, ok = release.asset("linux", "arm64")
+	assert.True(t, ok)
+	assert.Equal(t, "benchmark_linux_arm64.tar.gz", asset.Name)
+
+	_, ok = release.asset("linux", "amd64")
+	assert.False(t, ok)
+}
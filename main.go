@@ -2,13 +2,16 @@ package main
 
 import (
 	"errors"
+	"fmt"
 	"log/slog"
+	"os"
 
 	"github.com/ssvlabs/ssv-pulse/internal/loki"
 
 	"github.com/Harikakasimahanthi/benchmark-test/"
 	"github.com/Harikakasimahanthi/benchmark-test/configs"
 	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/cmd"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/failure"
 	_ "github.com/Harikakasimahanthi/benchmark-test/internal/platform/logger"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -17,29 +20,34 @@ import (
 var (
 	appName = "solostaking-benchmark"
 	version = "1.0"
+
+	configPath string
+	profile    string
 )
 
 var rootCmd = &cobra.Command{
 	Use:   "solostaking-benchmark",
 	Short: "CLI for analyzing and benchmarking ssv node",
 	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
-		viper.SetConfigName("config")
-		viper.SetConfigType("yaml")
-		viper.AddConfigPath(".")
+		if err := configs.BindEnv(viper.GetViper()); err != nil {
+			return failure.Config(err)
+		}
 
-		if err := viper.ReadInConfig(); err != nil {
+		// Without configuration file the configuration comes from flags and environment variables, e.g. in containers
+		if err := configs.Load(viper.GetViper(), configPath, profile); err != nil {
 			const errMsg = "error reading config file"
 			slog.With("err", err.Error()).Error(errMsg)
-			return errors.Join(err, errors.New(errMsg))
+			return failure.Config(errors.Join(err, errors.New(errMsg)))
 		}
 		if err := viper.Unmarshal(&configs.Values); err != nil {
 			const errMsg = "unable to decode application config"
 			slog.With("err", err.Error()).Error(errMsg)
-			return errors.Join(err, errors.New(errMsg))
+			return failure.Config(errors.Join(err, errors.New(errMsg)))
 		}
 
 		slog.
 			With("config_file", viper.ConfigFileUsed()).
+			With("profile", profile).
 			With("config", configs.Values).
 			Debug("configurations loaded")
 		return nil
@@ -49,6 +57,10 @@ var rootCmd = &cobra.Command{
 func main() {
 	rootCmd.Short = appName
 	rootCmd.Version = version
+	// Failures are explained along with what to check, by their kind
+	rootCmd.SilenceErrors = true
+	rootCmd.PersistentFlags().StringVar(&configPath, "config", "", "Configuration file, or directory holding config.yaml and one '<profile>.yaml' per profile (default ./config.yaml)")
+	rootCmd.PersistentFlags().StringVar(&profile, "profile", "", "Named profile merged over the configuration, from its 'profiles' section or from '<profile>.yaml' of the configuration directory")
 
 	rootCmd.AddCommand(analyzer.CMD)
 	rootCmd.AddCommand(benchmark.CMD)
@@ -56,6 +68,8 @@ func main() {
 	rootCmd.AddCommand(loki.CMD)
 	if err := rootCmd.Execute(); err != nil {
 		slog.With("err", err.Error()).Error("failed to execute root command")
-		panic(err.Error())
+		fmt.Fprintln(os.Stderr, failure.Describe(err))
+		// Scripts tell configuration, connectivity and runtime failures apart by the exit code
+		os.Exit(failure.ExitCode(err))
 	}
 }
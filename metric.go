@@ -2,107 +2,588 @@ package benchmark
 
 import (
 	"errors"
+	"fmt"
+	"log/slog"
 	"time"
 
 	"github.com/Harikakasimahanthi/benchmark-test/configs"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/clientinfo"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/failure"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/httpclient"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/metric"
 	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/network"
+	"github.com/Harikakasimahanthi/benchmark-test/metrics/connectivity"
 	"github.com/Harikakasimahanthi/benchmark-test/metrics/consensus"
 	"github.com/Harikakasimahanthi/benchmark-test/metrics/execution"
+	"github.com/Harikakasimahanthi/benchmark-test/metrics/goruntime"
 	"github.com/Harikakasimahanthi/benchmark-test/metrics/infrastructure"
+	"github.com/Harikakasimahanthi/benchmark-test/metrics/observer"
+	"github.com/Harikakasimahanthi/benchmark-test/metrics/validator"
 )
 
-func LoadEnabledMetrics(config configs.Config) (map[metric.Group][]metricService, error) {
+const runtimeInterval = time.Second * 15
+
+type (
+	// timedMetric is a metric whose measurements are bounded by a timeout
+	timedMetric interface {
+		metricService
+		SetTimeout(timeout time.Duration)
+		SetWarmUp(warmUp bool)
+	}
+
+	// expectingMetric is a metric which can leave the readings of expected windows out of its health evaluation
+	expectingMetric interface {
+		ExpectDuring(windows func() []metric.Window)
+	}
+
+	// timeouts applies the configured measurement timeouts and warm-up, and collects the invalid timeouts
+	timeouts struct {
+		errs []error
+	}
+)
+
+func LoadEnabledMetrics(config configs.Benchmark, clients clientinfo.Detection, spec network.Spec) (map[metric.Group][]metricService, error) {
 	enabledMetrics := make(map[metric.Group][]metricService)
+	var (
+		timeouts        timeouts
+		proposalWindows func() []metric.Window
+	)
 
 	// Consensus metrics
-	if config.Benchmark.Consensus.Metrics.Client.Enabled {
-		enabledMetrics[metric.ConsensusGroup] = append(enabledMetrics[metric.ConsensusGroup], consensus.NewClientMetric(
-			configs.Values.Benchmark.Consensus.Address,
+	if config.BeaconNode.Metrics.Client.Enabled {
+		interval := time.Second * 10
+		enabledMetrics[metric.ConsensusGroup] = append(enabledMetrics[metric.ConsensusGroup], timeouts.apply(consensus.NewClientMetric(
+			config.BeaconNode.Address,
 			"Client",
-			[]metric.HealthCondition[string]{
-				{Name: consensus.VersionMeasurement, Threshold: "", Operator: metric.OperatorEqual, Severity: metric.SeverityHigh},
-			}))
+			[]metric.ValueCondition{
+				{Name: consensus.VersionMeasurement, Threshold: metric.String(""), Operator: metric.OperatorEqual, Severity: metric.SeverityHigh},
+			},
+			interval), config.BeaconNode.Metrics.Client, interval))
 	}
 
-	if config.Benchmark.Consensus.Metrics.Latency.Enabled {
-		consensusClientURL, err := config.Benchmark.Consensus.AddrURL()
-		if err != nil {
-			return nil, errors.Join(err, errors.New("failed fetching Consensus client address as URL"))
-		}
-		enabledMetrics[metric.ConsensusGroup] = append(enabledMetrics[metric.ConsensusGroup], consensus.NewLatencyMetric(
-			consensusClientURL.Host,
+	if config.BeaconNode.Metrics.Latency.Enabled {
+		interval := time.Second * 3
+		enabledMetrics[metric.ConsensusGroup] = append(enabledMetrics[metric.ConsensusGroup], timeouts.apply(consensus.NewLatencyMetric(
+			config.BeaconNode.Address,
 			"Latency",
-			time.Second*3,
+			interval,
 			[]metric.HealthCondition[time.Duration]{
 				{Name: consensus.DurationP90Measurement, Threshold: time.Second, Operator: metric.OperatorGreaterThanOrEqual, Severity: metric.SeverityHigh},
-			}))
+			}), config.BeaconNode.Metrics.Latency.TimedMetric, interval))
 	}
 
-	if config.Benchmark.Consensus.Metrics.Peers.Enabled {
-		enabledMetrics[metric.ConsensusGroup] = append(enabledMetrics[metric.ConsensusGroup], consensus.NewPeerMetric(
-			configs.Values.Benchmark.Consensus.Address,
+	if config.BeaconNode.Metrics.Latency.Enabled && len(config.BeaconNode.Metrics.Latency.Paths) != 0 {
+		interval := time.Second * 5
+		pathMetric, err := connectivity.NewPathLatencyMetric(
+			metric.ConsensusGroup,
+			"/eth/v1/node/health",
+			"Latency Paths",
+			networkPaths(config.BeaconNode.Address, config.BeaconNode.Metrics.Latency.Paths),
+			interval,
+			[]metric.HealthCondition[time.Duration]{})
+		if err != nil {
+			return nil, errors.Join(err, errors.New("failed creating Consensus client latency paths metric"))
+		}
+		enabledMetrics[metric.ConsensusGroup] = append(enabledMetrics[metric.ConsensusGroup], timeouts.apply(pathMetric, config.BeaconNode.Metrics.Latency.TimedMetric, interval))
+	}
+
+	if config.BeaconNode.Metrics.Peers.Enabled {
+		interval := time.Second * 10
+		peerMetric := consensus.NewPeerMetric(
+			config.BeaconNode.Address,
 			"Peers",
-			time.Second*10,
+			interval,
 			[]metric.HealthCondition[uint32]{
 				{Name: consensus.PeerCountMeasurement, Threshold: 5, Operator: metric.OperatorLessThanOrEqual, Severity: metric.SeverityHigh},
 				{Name: consensus.PeerCountMeasurement, Threshold: 20, Operator: metric.OperatorLessThanOrEqual, Severity: metric.SeverityMedium},
 				{Name: consensus.PeerCountMeasurement, Threshold: 40, Operator: metric.OperatorLessThanOrEqual, Severity: metric.SeverityLow},
-			}))
+			}).WithClient(clients.Consensus).WithGRPC(clients.ConsensusGRPC)
+		// A sudden loss of peers points at a network problem even when enough peers are left
+		peerMetric.AddTrends(metric.TrendCondition{Name: consensus.PeerCountMeasurement, Window: time.Minute * 5, Threshold: -50, Relative: true, Operator: metric.OperatorLessThanOrEqual, Severity: metric.SeverityMedium})
+		enabledMetrics[metric.ConsensusGroup] = append(enabledMetrics[metric.ConsensusGroup], timeouts.apply(peerMetric, config.BeaconNode.Metrics.Peers, interval))
 	}
 
-	if config.Benchmark.Consensus.Metrics.Attestation.Enabled {
-		enabledMetrics[metric.ConsensusGroup] = append(enabledMetrics[metric.ConsensusGroup], consensus.NewAttestationMetric(
-			configs.Values.Benchmark.Consensus.Address,
+	if config.BeaconNode.Metrics.SyncStatus.Enabled {
+		interval := spec.SlotDuration
+		enabledMetrics[metric.ConsensusGroup] = append(enabledMetrics[metric.ConsensusGroup], timeouts.apply(consensus.NewSyncMetric(
+			config.BeaconNode.Address,
+			"Sync",
+			interval,
+			[]metric.HealthCondition[uint64]{
+				{Name: consensus.SyncDistanceMeasurement, Threshold: 32, Operator: metric.OperatorGreaterThanOrEqual, Severity: metric.SeverityHigh},
+				{Name: consensus.SyncDistanceMeasurement, Threshold: 2, Operator: metric.OperatorGreaterThanOrEqual, Severity: metric.SeverityMedium},
+				{Name: consensus.ELOfflineMeasurement, Threshold: 1, Operator: metric.OperatorEqual, Severity: metric.SeverityHigh},
+				{Name: consensus.OptimisticMeasurement, Threshold: 1, Operator: metric.OperatorEqual, Severity: metric.SeverityMedium},
+			}), config.BeaconNode.Metrics.SyncStatus, interval))
+	}
+
+	if config.BeaconNode.Metrics.Network.Enabled {
+		interval := time.Minute * 5
+		enabledMetrics[metric.ConsensusGroup] = append(enabledMetrics[metric.ConsensusGroup], timeouts.apply(consensus.NewNetworkMetric(
+			config.BeaconNode.Address,
+			"Network",
+			network.Name(config.Network),
+			spec,
+			interval,
+			[]metric.HealthCondition[float64]{
+				{Name: consensus.GenesisMismatchMeasurement, Threshold: 1, Operator: metric.OperatorEqual, Severity: metric.SeverityHigh},
+				{Name: consensus.ForkMismatchMeasurement, Threshold: 1, Operator: metric.OperatorEqual, Severity: metric.SeverityHigh},
+			}), config.BeaconNode.Metrics.Network, interval))
+	}
+
+	if config.BeaconNode.Metrics.Inbound.Enabled {
+		interval := time.Minute
+		enabledMetrics[metric.ConsensusGroup] = append(enabledMetrics[metric.ConsensusGroup], timeouts.apply(consensus.NewInboundMetric(
+			config.BeaconNode.Address,
+			config.BeaconNode.Metrics.Inbound.ProbeURL,
+			"Inbound Connectivity",
+			interval,
+			[]metric.HealthCondition[float64]{
+				{Name: consensus.InboundReachableMeasurement, Threshold: 0, Operator: metric.OperatorEqual, Severity: metric.SeverityMedium},
+				{Name: consensus.PrivateENRMeasurement, Threshold: 1, Operator: metric.OperatorEqual, Severity: metric.SeverityMedium},
+			}).WithDefaultPort(clients.Consensus.P2PPort), config.BeaconNode.Metrics.Inbound.TimedMetric, interval))
+	}
+
+	if config.BeaconNode.Metrics.Attestation.Enabled {
+		attestation, err := consensus.NewAttestationMetric(
+			config.BeaconNode.Address,
 			"Attestation",
-			network.GenesisTime[network.Name(config.Benchmark.Network)],
+			spec,
 			[]metric.HealthCondition[float64]{
 				{Name: consensus.CorrectnessMeasurement, Threshold: 97, Operator: metric.OperatorLessThanOrEqual, Severity: metric.SeverityHigh},
 				{Name: consensus.CorrectnessMeasurement, Threshold: 98.5, Operator: metric.OperatorLessThanOrEqual, Severity: metric.SeverityMedium},
 			},
-		))
+		)
+		if err != nil {
+			return nil, failure.Connectivity(errors.Join(err, errors.New("failed connecting Consensus client attestation metric")))
+		}
+		enabledMetrics[metric.ConsensusGroup] = append(enabledMetrics[metric.ConsensusGroup],
+			attestation.WithClient(clients.Consensus).WithGRPC(clients.ConsensusGRPC).WithBackfill(config.BeaconNode.Metrics.Attestation.BackfillEpochs, config.ValidatorClient.Indices))
+	}
+
+	if config.BeaconNode.Metrics.HeadDelay.Enabled {
+		headDelay := consensus.NewHeadDelayMetric(
+			config.BeaconNode.Address,
+			"Head Delay",
+			spec,
+			// Heads arriving after the attestation deadline get no timely vote
+			[]metric.HealthCondition[time.Duration]{
+				{Name: consensus.HeadDelayP90Measurement, Threshold: spec.AttestationDeadline(), Operator: metric.OperatorGreaterThanOrEqual, Severity: metric.SeverityHigh},
+				{Name: consensus.HeadDelayP50Measurement, Threshold: spec.AttestationDeadline() * 3 / 4, Operator: metric.OperatorGreaterThanOrEqual, Severity: metric.SeverityMedium},
+			})
+		enabledMetrics[metric.ConsensusGroup] = append(enabledMetrics[metric.ConsensusGroup], headDelay)
+	}
+
+	if config.BeaconNode.Metrics.Blobs.Enabled {
+		// The sidecars are fetched every slot
+		enabledMetrics[metric.ConsensusGroup] = append(enabledMetrics[metric.ConsensusGroup], timeouts.apply(consensus.NewBlobMetric(
+			config.BeaconNode.Address,
+			"Blobs",
+			spec,
+			[]metric.HealthCondition[float64]{
+				{Name: consensus.MissingBlobsMeasurement, Threshold: 1, Operator: metric.OperatorGreaterThanOrEqual, Severity: metric.SeverityHigh},
+				{Name: consensus.BlobRetrievalP90Measurement, Threshold: 1000, Operator: metric.OperatorGreaterThanOrEqual, Severity: metric.SeverityMedium},
+			}), config.BeaconNode.Metrics.Blobs, spec.SlotDuration))
+	}
+
+	if config.BeaconNode.Metrics.Aggregation.Enabled {
+		// The pool is checked every slot
+		enabledMetrics[metric.ConsensusGroup] = append(enabledMetrics[metric.ConsensusGroup], timeouts.apply(consensus.NewAggregationMetric(
+			config.BeaconNode.Address,
+			"Aggregation",
+			spec,
+			[]metric.HealthCondition[float64]{
+				{Name: consensus.AggregateSuccessRateMeasurement, Threshold: 50, Operator: metric.OperatorLessThanOrEqual, Severity: metric.SeverityHigh},
+				{Name: consensus.AggregateSuccessRateMeasurement, Threshold: 90, Operator: metric.OperatorLessThanOrEqual, Severity: metric.SeverityMedium},
+				{Name: consensus.AggregateLatencyP90Measurement, Threshold: 1000, Operator: metric.OperatorGreaterThanOrEqual, Severity: metric.SeverityMedium},
+			}), config.BeaconNode.Metrics.Aggregation, spec.SlotDuration))
+	}
+
+	if config.BeaconNode.Metrics.VoteCrossCheck.Enabled {
+		enabledMetrics[metric.ConsensusGroup] = append(enabledMetrics[metric.ConsensusGroup], consensus.NewVoteCrossCheckMetric(
+			append([]string{config.BeaconNode.Address}, config.BeaconNode.OtherAddresses...),
+			"Vote Cross-Check",
+			spec,
+			[]metric.HealthCondition[float64]{
+				{Name: consensus.TargetDivergenceMeasurement, Threshold: 1, Operator: metric.OperatorEqual, Severity: metric.SeverityHigh},
+				{Name: consensus.VoteDivergenceStreakMeasurement, Threshold: 4, Operator: metric.OperatorGreaterThanOrEqual, Severity: metric.SeverityHigh},
+				{Name: consensus.VoteDivergenceStreakMeasurement, Threshold: 2, Operator: metric.OperatorGreaterThanOrEqual, Severity: metric.SeverityMedium},
+			}))
+	}
+
+	if failover := config.BeaconNode.Metrics.Failover; failover.Enabled {
+		interval := time.Second * 3
+		enabledMetrics[metric.ConsensusGroup] = append(enabledMetrics[metric.ConsensusGroup], timeouts.apply(consensus.NewFailoverMetric(
+			config.BeaconNode.Address,
+			failover.Fallback,
+			"Failover",
+			interval,
+			failover.SimulateEvery,
+			spec,
+			[]metric.HealthCondition[float64]{
+				{Name: consensus.FallbackReadyMeasurement, Threshold: 0, Operator: metric.OperatorEqual, Severity: metric.SeverityHigh},
+				// Duties served after the attestation deadline miss the attestation of the slot
+				{Name: consensus.SwitchoverMeasurement, Threshold: float64(spec.AttestationDeadline().Milliseconds()), Operator: metric.OperatorGreaterThanOrEqual, Severity: metric.SeverityHigh},
+				{Name: consensus.SwitchoverMeasurement, Threshold: float64(spec.AttestationDeadline().Milliseconds()) / 2, Operator: metric.OperatorGreaterThanOrEqual, Severity: metric.SeverityMedium},
+			}), failover.TimedMetric, interval))
+	}
+
+	if runtimeMetric, ok := newRuntimeMetric(metric.ConsensusGroup, config.BeaconNode.Metrics.Runtime, config.BeaconNode.Address, clients.Consensus); ok {
+		enabledMetrics[metric.ConsensusGroup] = append(enabledMetrics[metric.ConsensusGroup], timeouts.apply(runtimeMetric, config.BeaconNode.Metrics.Runtime.TimedMetric, runtimeInterval))
+	}
+
+	// Validator metrics
+	if config.ValidatorClient.TokenPath != "" {
+		for _, address := range append([]string{config.ValidatorClient.Address}, config.ValidatorClient.OtherAddresses...) {
+			if err := httpclient.SetTokenFile(address, config.ValidatorClient.TokenPath); err != nil {
+				return nil, errors.Join(err, errors.New("failed setting validator client API token"))
+			}
+		}
+	}
+
+	if config.ValidatorClient.Metrics.KeySafety.Enabled {
+		interval := time.Minute
+		enabledMetrics[metric.ValidatorGroup] = append(enabledMetrics[metric.ValidatorGroup], timeouts.apply(validator.NewKeySafetyMetric(
+			config.ValidatorClient.Address,
+			config.ValidatorClient.OtherAddresses,
+			config.ValidatorClient.SlashingProtectionPath,
+			"Key Safety",
+			interval,
+			[]metric.HealthCondition[float64]{
+				{Name: validator.LoadedKeysMeasurement, Threshold: 0, Operator: metric.OperatorEqual, Severity: metric.SeverityHigh},
+				{Name: validator.DuplicateKeysMeasurement, Threshold: 1, Operator: metric.OperatorGreaterThanOrEqual, Severity: metric.SeverityHigh},
+				{Name: validator.SlashingProtectionMissingMeasurement, Threshold: 1, Operator: metric.OperatorEqual, Severity: metric.SeverityHigh},
+				// Every attestation updates the database, so it should not be older than a few epochs
+				{Name: validator.SlashingProtectionAgeMeasurement, Threshold: (time.Minute * 20).Seconds(), Operator: metric.OperatorGreaterThanOrEqual, Severity: metric.SeverityMedium},
+			}), config.ValidatorClient.Metrics.KeySafety, interval))
+	}
+
+	if config.BeaconNode.Metrics.ProposalDryRun.Enabled {
+		enabledMetrics[metric.ValidatorGroup] = append(enabledMetrics[metric.ValidatorGroup], consensus.NewProposalMetric(
+			config.BeaconNode.Address,
+			"Proposal Dry Run",
+			spec,
+			time.Minute*5,
+			[]metric.HealthCondition[float64]{
+				{Name: consensus.ProductionFailedMeasurement, Threshold: 1, Operator: metric.OperatorEqual, Severity: metric.SeverityHigh},
+				{Name: consensus.ProductionDurationMeasurement, Threshold: 2000, Operator: metric.OperatorGreaterThanOrEqual, Severity: metric.SeverityHigh},
+				{Name: consensus.ProductionDurationMeasurement, Threshold: 1000, Operator: metric.OperatorGreaterThanOrEqual, Severity: metric.SeverityMedium},
+			}))
+	}
+
+	if builder := config.BeaconNode.Metrics.BuilderComparison; builder.Enabled {
+		// Every comparison has the execution client build a payload, like the proposal dry run
+		enabledMetrics[metric.ValidatorGroup] = append(enabledMetrics[metric.ValidatorGroup], consensus.NewBuilderComparisonMetric(
+			config.BeaconNode.Address,
+			"Builder Comparison",
+			builder.Relays,
+			spec,
+			time.Minute*5,
+			[]metric.HealthCondition[float64]{}))
+	}
+
+	if config.BeaconNode.Metrics.SyncCommittee.Enabled {
+		// The messages are fetched every slot
+		enabledMetrics[metric.ValidatorGroup] = append(enabledMetrics[metric.ValidatorGroup], timeouts.apply(consensus.NewSyncCommitteeMetric(
+			config.BeaconNode.Address,
+			"Sync Committee",
+			spec,
+			config.ValidatorClient.Indices,
+			[]metric.HealthCondition[float64]{
+				{Name: consensus.SyncCommitteeMissStreakMeasurement, Threshold: 4, Operator: metric.OperatorGreaterThanOrEqual, Severity: metric.SeverityHigh},
+				{Name: consensus.SyncCommitteeInclusionMeasurement, Threshold: 95, Operator: metric.OperatorLessThanOrEqual, Severity: metric.SeverityMedium},
+			}), config.BeaconNode.Metrics.SyncCommittee, spec.SlotDuration))
+	}
+
+	if config.BeaconNode.Metrics.Balances.Enabled {
+		// The balances are sampled every epoch
+		enabledMetrics[metric.ValidatorGroup] = append(enabledMetrics[metric.ValidatorGroup], timeouts.apply(consensus.NewBalanceMetric(
+			config.BeaconNode.Address,
+			"Balances",
+			spec,
+			config.ValidatorClient.Indices,
+			[]metric.HealthCondition[float64]{
+				{Name: consensus.BalanceDeltaMeasurement, Threshold: 0, Operator: metric.OperatorLessThan, Severity: metric.SeverityMedium},
+			}), config.BeaconNode.Metrics.Balances, spec.EpochDuration()))
+	}
+
+	if config.BeaconNode.Metrics.DutySimulation.Enabled {
+		enabledMetrics[metric.ValidatorGroup] = append(enabledMetrics[metric.ValidatorGroup], consensus.NewDutySimulationMetric(
+			config.BeaconNode.Address,
+			"Duty Simulation",
+			spec,
+			config.ValidatorClient.Indices,
+			[]metric.HealthCondition[float64]{
+				{Name: consensus.OnTimeRateMeasurement, Threshold: 95, Operator: metric.OperatorLessThanOrEqual, Severity: metric.SeverityHigh},
+				{Name: consensus.OnTimeRateMeasurement, Threshold: 99, Operator: metric.OperatorLessThanOrEqual, Severity: metric.SeverityMedium},
+			}))
+	}
+
+	if config.BeaconNode.Metrics.DutyCalendar.Enabled {
+		calendar := consensus.NewDutyCalendarMetric(
+			config.BeaconNode.Address,
+			"Duty Calendar",
+			spec,
+			config.ValidatorClient.Indices,
+			[]metric.HealthCondition[float64]{
+				{Name: consensus.MissedProposalMeasurement, Threshold: 1, Operator: metric.OperatorEqual, Severity: metric.SeverityHigh},
+			})
+		enabledMetrics[metric.ValidatorGroup] = append(enabledMetrics[metric.ValidatorGroup], calendar)
+		proposalWindows = calendar.ProposalWindows
 	}
 
 	// Execution metrics
-	if config.Benchmark.Execution.Metrics.Peers.Enabled {
-		enabledMetrics[metric.ExecutionGroup] = append(enabledMetrics[metric.ExecutionGroup], execution.NewPeerMetric(
-			configs.Values.Benchmark.Execution.Address,
+	if config.ExecutionNode.Metrics.Peers.Enabled {
+		interval := time.Second * 10
+		peerMetric := execution.NewPeerMetric(
+			config.ExecutionNode.Address,
 			"Peers",
-			time.Second*10,
+			interval,
 			[]metric.HealthCondition[uint32]{
 				{Name: execution.PeerCountMeasurement, Threshold: 5, Operator: metric.OperatorLessThanOrEqual, Severity: metric.SeverityHigh},
 				{Name: execution.PeerCountMeasurement, Threshold: 20, Operator: metric.OperatorLessThanOrEqual, Severity: metric.SeverityMedium},
 				{Name: execution.PeerCountMeasurement, Threshold: 40, Operator: metric.OperatorLessThanOrEqual, Severity: metric.SeverityLow},
-			}))
+			})
+		// Unknown clients get a chance, the metric falls back to net_peerCount when admin_peers is not available
+		if config.ExecutionNode.Metrics.AdminPeers.Enabled &&
+			(clients.Execution.AdminPeers || clients.Execution.Client == clientinfo.Unknown) {
+			peerMetric = peerMetric.WithAdminPeers()
+		}
+		enabledMetrics[metric.ExecutionGroup] = append(enabledMetrics[metric.ExecutionGroup], timeouts.apply(peerMetric, config.ExecutionNode.Metrics.Peers, interval))
 	}
 
-	if config.Benchmark.Execution.Metrics.Latency.Enabled {
-		executionClientURL, err := config.Benchmark.Execution.AddrURL()
-		if err != nil {
-			return nil, errors.Join(err, errors.New("failed fetching Execution client address as URL"))
-		}
-		enabledMetrics[metric.ExecutionGroup] = append(enabledMetrics[metric.ExecutionGroup], execution.NewLatencyMetric(
-			executionClientURL.Host,
+	if config.ExecutionNode.Metrics.Inbound.Enabled {
+		interval := time.Minute
+		enabledMetrics[metric.ExecutionGroup] = append(enabledMetrics[metric.ExecutionGroup], timeouts.apply(execution.NewInboundMetric(
+			config.ExecutionNode.Address,
+			config.ExecutionNode.Metrics.Inbound.ProbeURL,
+			"Inbound Connectivity",
+			interval,
+			[]metric.HealthCondition[float64]{
+				{Name: execution.InboundReachableMeasurement, Threshold: 0, Operator: metric.OperatorEqual, Severity: metric.SeverityMedium},
+				{Name: execution.PrivateEnodeMeasurement, Threshold: 1, Operator: metric.OperatorEqual, Severity: metric.SeverityMedium},
+			}).WithDefaultPort(clients.Execution.P2PPort), config.ExecutionNode.Metrics.Inbound.TimedMetric, interval))
+	}
+
+	if config.ExecutionNode.Metrics.StateAccess.Enabled {
+		interval := time.Second * 30
+		enabledMetrics[metric.ExecutionGroup] = append(enabledMetrics[metric.ExecutionGroup], timeouts.apply(execution.NewStateAccessMetric(
+			config.ExecutionNode.Address,
+			"State Access",
+			interval,
+			[]metric.HealthCondition[float64]{
+				{Name: execution.ResponsivenessMeasurement, Threshold: 10, Operator: metric.OperatorLessThanOrEqual, Severity: metric.SeverityHigh},
+				{Name: execution.ResponsivenessMeasurement, Threshold: 25, Operator: metric.OperatorLessThanOrEqual, Severity: metric.SeverityMedium},
+			}), config.ExecutionNode.Metrics.StateAccess, interval))
+	}
+
+	if config.ExecutionNode.Metrics.Latency.Enabled {
+		interval := time.Second * 3
+		latencyMetric := execution.NewLatencyMetric(
+			config.ExecutionNode.Address,
 			"Latency",
-			time.Second*3,
+			interval,
 			[]metric.HealthCondition[time.Duration]{
 				{Name: execution.DurationP90Measurement, Threshold: time.Second, Operator: metric.OperatorGreaterThanOrEqual, Severity: metric.SeverityHigh},
-			}))
+			})
+		if config.ExecutionNode.IsIPC() {
+			latencyMetric = latencyMetric.WithIPC()
+		}
+		enabledMetrics[metric.ExecutionGroup] = append(enabledMetrics[metric.ExecutionGroup], timeouts.apply(latencyMetric, config.ExecutionNode.Metrics.Latency.TimedMetric, interval))
+	}
+
+	if config.ExecutionNode.Metrics.Latency.Enabled && len(config.ExecutionNode.Metrics.Latency.Paths) != 0 {
+		interval := time.Second * 5
+		var paths []connectivity.Path
+		// An IPC socket can't be reached through a network path, so the configured paths are compared to each other
+		if config.ExecutionNode.IsIPC() {
+			paths = networkPaths("", config.ExecutionNode.Metrics.Latency.Paths)
+		} else {
+			paths = networkPaths(config.ExecutionNode.Address, config.ExecutionNode.Metrics.Latency.Paths)
+		}
+		pathMetric, err := connectivity.NewPathLatencyMetric(
+			metric.ExecutionGroup,
+			"",
+			"Latency Paths",
+			paths,
+			interval,
+			[]metric.HealthCondition[time.Duration]{})
+		if err != nil {
+			return nil, errors.Join(err, errors.New("failed creating Execution client latency paths metric"))
+		}
+		enabledMetrics[metric.ExecutionGroup] = append(enabledMetrics[metric.ExecutionGroup], timeouts.apply(pathMetric, config.ExecutionNode.Metrics.Latency.TimedMetric, interval))
+	}
+
+	if config.ExecutionNode.Metrics.Engine.Enabled {
+		interval := spec.SlotDuration
+		engineMetric, err := execution.NewEngineMetric(
+			config.ExecutionNode.EngineAddress,
+			config.ExecutionNode.JWTSecretPath,
+			"Engine API",
+			interval,
+			[]metric.HealthCondition[float64]{
+				{Name: execution.CapabilitiesFailedMeasurement, Threshold: 1, Operator: metric.OperatorGreaterThanOrEqual, Severity: metric.SeverityHigh},
+				{Name: execution.MissingCapabilitiesMeasurement, Threshold: 1, Operator: metric.OperatorGreaterThanOrEqual, Severity: metric.SeverityHigh},
+				{Name: execution.NewPayloadDurationMeasurement, Threshold: 1000, Operator: metric.OperatorGreaterThanOrEqual, Severity: metric.SeverityMedium},
+			})
+		if err != nil {
+			return nil, errors.Join(err, errors.New("failed creating Execution client Engine API metric"))
+		}
+		if config.ExecutionNode.Metrics.Engine.Simulate {
+			engineMetric = engineMetric.WithSimulation()
+		}
+		enabledMetrics[metric.ExecutionGroup] = append(enabledMetrics[metric.ExecutionGroup], timeouts.apply(engineMetric, config.ExecutionNode.Metrics.Engine.TimedMetric, interval))
+	}
+
+	if runtimeMetric, ok := newRuntimeMetric(metric.ExecutionGroup, config.ExecutionNode.Metrics.Runtime, config.ExecutionNode.Address, clients.Execution); ok {
+		enabledMetrics[metric.ExecutionGroup] = append(enabledMetrics[metric.ExecutionGroup], timeouts.apply(runtimeMetric, config.ExecutionNode.Metrics.Runtime.TimedMetric, runtimeInterval))
 	}
 
 	// Infrastructure metrics
-	if config.Benchmark.Infrastructure.Metrics.CPU.Enabled {
+	if config.Infrastructure.Metrics.CPU.Enabled {
 		enabledMetrics[metric.InfrastructureGroup] = append(enabledMetrics[metric.InfrastructureGroup],
-			infrastructure.NewCPUMetric("CPU", time.Second*5, []metric.HealthCondition[float64]{}),
+			infrastructure.NewCPUMetric("CPU", time.Second*5, []metric.HealthCondition[float64]{
+				{Name: infrastructure.IOWaitMeasurement, Threshold: 20, Operator: metric.OperatorGreaterThanOrEqual, Severity: metric.SeverityMedium},
+				{Name: infrastructure.StealMeasurement, Threshold: 10, Operator: metric.OperatorGreaterThanOrEqual, Severity: metric.SeverityMedium},
+			}),
 		)
 	}
 
-	if config.Benchmark.Infrastructure.Metrics.Memory.Enabled {
+	if config.Infrastructure.Metrics.Memory.Enabled {
+		memoryMetric := infrastructure.NewMemoryMetric("Memory", time.Second*10, []metric.HealthCondition[uint64]{
+			{Name: infrastructure.FreeMemoryMeasurement, Threshold: 0, Operator: metric.OperatorEqual, Severity: metric.SeverityHigh},
+		})
+		// A leak takes hours to exhaust the memory, its growth shows it early
+		memoryMetric.AddTrends(metric.TrendCondition{Name: infrastructure.UsedMemoryMeasurement, Window: time.Hour, Threshold: 1 << 30, Operator: metric.OperatorGreaterThanOrEqual, Severity: metric.SeverityMedium})
+		enabledMetrics[metric.InfrastructureGroup] = append(enabledMetrics[metric.InfrastructureGroup], memoryMetric)
+	}
+
+	if config.Infrastructure.Metrics.MemoryPressure.Enabled {
 		enabledMetrics[metric.InfrastructureGroup] = append(enabledMetrics[metric.InfrastructureGroup],
-			infrastructure.NewMemoryMetric("Memory", time.Second*10, []metric.HealthCondition[uint64]{
-				{Name: infrastructure.FreeMemoryMeasurement, Threshold: 0, Operator: metric.OperatorEqual, Severity: metric.SeverityHigh},
+			infrastructure.NewMemoryPressureMetric("Memory Pressure", time.Second*10, []metric.HealthCondition[float64]{
+				{Name: infrastructure.OOMKillsMeasurement, Threshold: 1, Operator: metric.OperatorGreaterThanOrEqual, Severity: metric.SeverityHigh},
+				{Name: infrastructure.PressureFullMeasurement, Threshold: 10, Operator: metric.OperatorGreaterThanOrEqual, Severity: metric.SeverityHigh},
+				{Name: infrastructure.PressureSomeMeasurement, Threshold: 20, Operator: metric.OperatorGreaterThanOrEqual, Severity: metric.SeverityMedium},
+				{Name: infrastructure.SwapOutMeasurement, Threshold: 100, Operator: metric.OperatorGreaterThanOrEqual, Severity: metric.SeverityMedium},
 			}),
 		)
 	}
 
+	if config.Infrastructure.Metrics.Load.Enabled {
+		enabledMetrics[metric.InfrastructureGroup] = append(enabledMetrics[metric.InfrastructureGroup],
+			infrastructure.NewLoadMetric("Load", time.Second*15, []metric.HealthCondition[float64]{
+				{Name: infrastructure.Load5Measurement, Threshold: 2, Operator: metric.OperatorGreaterThanOrEqual, Severity: metric.SeverityHigh},
+				{Name: infrastructure.Load5Measurement, Threshold: 1, Operator: metric.OperatorGreaterThanOrEqual, Severity: metric.SeverityMedium},
+				{Name: infrastructure.SchedulerLatencyMeasurement, Threshold: 5, Operator: metric.OperatorGreaterThanOrEqual, Severity: metric.SeverityMedium},
+			}).WithSchedulerLatency(config.Infrastructure.Metrics.Load.SchedulerLatency),
+		)
+	}
+
+	if config.Infrastructure.Metrics.Hardware.Enabled {
+		enabledMetrics[metric.InfrastructureGroup] = append(enabledMetrics[metric.InfrastructureGroup],
+			infrastructure.NewHardwareMetric("Hardware", config.Infrastructure.Metrics.DataDirs.Paths, time.Second*30, []metric.HealthCondition[float64]{
+				{Name: infrastructure.UndervoltageMeasurement, Threshold: 1, Operator: metric.OperatorEqual, Severity: metric.SeverityHigh},
+				{Name: infrastructure.ThrottledMeasurement, Threshold: 1, Operator: metric.OperatorEqual, Severity: metric.SeverityMedium},
+				{Name: infrastructure.SDCardMeasurement, Threshold: 1, Operator: metric.OperatorEqual, Severity: metric.SeverityHigh},
+			}),
+		)
+	}
+
+	if config.Infrastructure.Metrics.Probe.Enabled {
+		interval := time.Second * 2
+		enabledMetrics[metric.InfrastructureGroup] = append(enabledMetrics[metric.InfrastructureGroup], timeouts.apply(
+			infrastructure.NewProbeMetric("Packet Loss", config.Infrastructure.Metrics.Probe.Hosts, interval, []metric.HealthCondition[float64]{
+				{Name: infrastructure.PacketLossMeasurement, Threshold: 5, Operator: metric.OperatorGreaterThanOrEqual, Severity: metric.SeverityHigh},
+				{Name: infrastructure.PacketLossMeasurement, Threshold: 1, Operator: metric.OperatorGreaterThanOrEqual, Severity: metric.SeverityMedium},
+				{Name: infrastructure.JitterMeasurement, Threshold: 30, Operator: metric.OperatorGreaterThanOrEqual, Severity: metric.SeverityMedium},
+			}), config.Infrastructure.Metrics.Probe.TimedMetric, interval),
+		)
+	}
+
+	if config.Infrastructure.Metrics.DataDirs.Enabled {
+		enabledMetrics[metric.InfrastructureGroup] = append(enabledMetrics[metric.InfrastructureGroup],
+			infrastructure.NewDataDirMetric("Data Directories", config.Infrastructure.Metrics.DataDirs.Paths, time.Minute*5, []metric.HealthCondition[float64]{
+				{Name: infrastructure.DiskFullDaysMeasurement, Threshold: 7, Operator: metric.OperatorLessThanOrEqual, Severity: metric.SeverityHigh},
+				{Name: infrastructure.DiskFullDaysMeasurement, Threshold: 30, Operator: metric.OperatorLessThanOrEqual, Severity: metric.SeverityMedium},
+				{Name: infrastructure.DiskFullDaysMeasurement, Threshold: 90, Operator: metric.OperatorLessThanOrEqual, Severity: metric.SeverityLow},
+			}),
+		)
+	}
+
+	// Observer metrics
+	if config.Observer.Enabled {
+		enabledMetrics[metric.ObserverGroup] = append(enabledMetrics[metric.ObserverGroup],
+			observer.NewSelfMetric("Benchmark Process", time.Second*5, []metric.HealthCondition[float64]{
+				{Name: observer.CPUPercentMeasurement, Threshold: 10, Operator: metric.OperatorGreaterThanOrEqual, Severity: metric.SeverityMedium},
+			}),
+		)
+	}
+
+	if err := timeouts.err(); err != nil {
+		return nil, errors.Join(err, errors.New("invalid measurement timeouts"))
+	}
+
+	// The node builds and propagates a block during the proposals of the validators, the resource usage spikes then
+	if proposalWindows != nil {
+		for _, m := range enabledMetrics[metric.InfrastructureGroup] {
+			if expecting, ok := m.(expectingMetric); ok {
+				expecting.ExpectDuring(proposalWindows)
+			}
+		}
+	}
 	return enabledMetrics, nil
 }
+
+// apply bounds the measurements of the metric by the configured timeout. The timeout must be shorter than the interval,
+// a slow measurement would overlap the next one.
+func (t *timeouts) apply(m timedMetric, config configs.TimedMetric, interval time.Duration) metricService {
+	if config.Timeout < 0 || config.Timeout >= interval {
+		t.errs = append(t.errs, fmt.Errorf("timeout '%s' of metric '%s' should be positive and shorter than its interval '%s'", config.Timeout, m.GetName(), interval))
+		return m
+	}
+	m.SetTimeout(config.Timeout)
+	m.SetWarmUp(config.WarmUp)
+	return m
+}
+
+func (t *timeouts) err() error {
+	return errors.Join(t.errs...)
+}
+
+// newRuntimeMetric creates the Go runtime metric of the client. Without configured Prometheus endpoint the default one
+// of the detected client is scraped, clients which are not written in Go expose no runtime stats and are skipped.
+func newRuntimeMetric(group metric.Group, config configs.RuntimeMetric, address string, adapter clientinfo.Adapter) (*goruntime.RuntimeMetric, bool) {
+	if !config.Enabled {
+		return nil, false
+	}
+
+	url := config.Address
+	if url == "" {
+		if !adapter.GoRuntime {
+			slog.With("group", group).With("client", adapter.Client).Debug("client is not written in Go, skipping runtime metric")
+			return nil, false
+		}
+		var err error
+		if url, err = adapter.PrometheusURL(address); err != nil {
+			slog.With("group", group).With("err", err.Error()).Warn("skipping runtime metric")
+			return nil, false
+		}
+	}
+
+	return goruntime.NewRuntimeMetric(group, url, "Client Runtime", runtimeInterval, []metric.HealthCondition[float64]{
+		{Name: goruntime.GCPauseMeasurement, Threshold: 500, Operator: metric.OperatorGreaterThanOrEqual, Severity: metric.SeverityHigh},
+		{Name: goruntime.GCPauseMeasurement, Threshold: 100, Operator: metric.OperatorGreaterThanOrEqual, Severity: metric.SeverityMedium},
+	}), true
+}
+
+// networkPaths returns the configured paths preceded by the direct path to the node address, the overhead baseline
+func networkPaths(address string, configured []configs.NetworkPath) []connectivity.Path {
+	var paths []connectivity.Path
+	if address != "" {
+		paths = append(paths, connectivity.Path{Name: connectivity.DirectPathName, Address: address})
+	}
+	for _, path := range configured {
+		paths = append(paths, connectivity.Path{Name: path.Name, Address: path.Address, Proxy: path.Proxy})
+	}
+	return paths
+}
@@ -0,0 +1,134 @@
+package benchmark
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Harikakasimahanthi/benchmark-test/configs"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/clientinfo"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/metric"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/network"
+	"github.com/Harikakasimahanthi/benchmark-test/testutil/mocknode"
+)
+
+// enableAll enables every metric of the configuration
+func enableAll(value reflect.Value) {
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Field(i)
+		switch {
+		case value.Type().Field(i).Name == "Enabled" && field.Kind() == reflect.Bool:
+			field.SetBool(true)
+		case field.Kind() == reflect.Struct:
+			enableAll(field)
+		}
+	}
+}
+
+// recorder returns the function recording a data point of the type of the metric
+func recorder(m metricService) func(i int) {
+	switch recorder := m.(type) {
+	case interface{ AddDataPoint(map[string]float64) }:
+		return func(i int) { recorder.AddDataPoint(map[string]float64{"Value": float64(i)}) }
+	case interface{ AddDataPoint(map[string]uint64) }:
+		return func(i int) { recorder.AddDataPoint(map[string]uint64{"Value": uint64(i)}) }
+	case interface{ AddDataPoint(map[string]uint32) }:
+		return func(i int) { recorder.AddDataPoint(map[string]uint32{"Value": uint32(i)}) }
+	case interface {
+		AddDataPoint(map[string]time.Duration)
+	}:
+		return func(i int) { recorder.AddDataPoint(map[string]time.Duration{"Value": time.Duration(i)}) }
+	case interface{ AddDataPoint(map[string]metric.Value) }:
+		return func(i int) { recorder.AddDataPoint(map[string]metric.Value{"Value": metric.Number(float64(i))}) }
+	default:
+		return nil
+	}
+}
+
+// Run with -race: collectors add data points while reports, exports and progress logs read them
+func TestGivenEveryMetricWhenDataPointsAddedConcurrentlyWithReadsThenNoRace(t *testing.T) {
+	jwtSecretPath := filepath.Join(t.TempDir(), "jwt.hex")
+	require.NoError(t, os.WriteFile(jwtSecretPath, []byte("0x0000000000000000000000000000000000000000000000000000000000000000"), 0o600))
+
+	// The attestation metric connects to the beacon node once it is created
+	node := mocknode.New(network.Mainnet).Start()
+	defer node.Close()
+
+	var config configs.Benchmark
+	enableAll(reflect.ValueOf(&config).Elem())
+	config.BeaconNode.Address = node.ConsensusURL()
+	config.ExecutionNode.Address = node.ExecutionURL()
+	config.ExecutionNode.EngineAddress = node.ExecutionURL()
+	config.ExecutionNode.JWTSecretPath = jwtSecretPath
+	config.ValidatorClient.Address = "http://localhost:5062"
+	config.ValidatorClient.Indices = []uint64{1}
+	config.Infrastructure.Metrics.Probe.Hosts = []string{"127.0.0.1"}
+	config.Network = "mainnet"
+
+	metrics, err := LoadEnabledMetrics(config, clientinfo.Detection{}, network.DefaultSpec(network.Mainnet))
+	require.NoError(t, err)
+
+	for _, groupMetrics := range metrics {
+		for _, m := range groupMetrics {
+			t.Run(m.GetName(), func(t *testing.T) {
+				addDataPoint := recorder(m)
+				require.NotNil(t, addDataPoint, "metric has no known data point type")
+
+				var wg sync.WaitGroup
+				wg.Add(2)
+				go func() {
+					defer wg.Done()
+					for i := 0; i < 100; i++ {
+						addDataPoint(i)
+					}
+				}()
+				go func() {
+					defer wg.Done()
+					for i := 0; i < 100; i++ {
+						m.AggregateResults()
+						m.EvaluateMetric()
+						m.ExportDataPoints()
+						m.Samples()
+					}
+				}()
+				wg.Wait()
+
+				assert.Equal(t, 100, m.Samples())
+			})
+		}
+	}
+}
+
+func TestGivenTimeoutLongerThanIntervalWhenLoadEnabledMetricsThenFails(t *testing.T) {
+	var config configs.Benchmark
+	config.BeaconNode.Address = "http://localhost:5052"
+	config.BeaconNode.Metrics.Peers.Enabled = true
+	config.BeaconNode.Metrics.Peers.Timeout = time.Minute
+
+	_, err := LoadEnabledMetrics(config, clientinfo.Detection{}, network.DefaultSpec(network.Mainnet))
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "timeout '1m0s' of metric 'Peers'")
+}
+
+func TestGivenTimeoutWhenLoadEnabledMetricsThenMetricUsesIt(t *testing.T) {
+	var config configs.Benchmark
+	config.BeaconNode.Address = "http://localhost:5052"
+	config.BeaconNode.Metrics.Peers.Enabled = true
+	config.BeaconNode.Metrics.Peers.Timeout = 2 * time.Second
+
+	metrics, err := LoadEnabledMetrics(config, clientinfo.Detection{}, network.DefaultSpec(network.Mainnet))
+
+	require.NoError(t, err)
+	require.Len(t, metrics[metric.ConsensusGroup], 1)
+	timed := metrics[metric.ConsensusGroup][0].(interface {
+		Timeout(time.Duration) time.Duration
+	})
+	assert.Equal(t, 2*time.Second, timed.Timeout(5*time.Second))
+}
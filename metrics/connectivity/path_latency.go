@@ -0,0 +1,189 @@
+package connectivity
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/clock"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/format"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/httpclient"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/logger"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/metric"
+)
+
+const (
+	// DirectPathName is the name of the path to the configured node address, the baseline of the overhead calculation
+	DirectPathName = "node"
+
+	pathP50Suffix         = "P50"
+	pathP90Suffix         = "P90"
+	pathOverheadP50Suffix = "OverheadP50"
+)
+
+type (
+	// Path is a route to the measured endpoint
+	Path struct {
+		Name    string
+		Address string
+		// Proxy is an optional HTTP(S) or SOCKS5 proxy URL the requests are sent through
+		Proxy string
+	}
+
+	// PathLatencyMetric measures the latency of the same endpoint through several network paths (e.g. direct IP,
+	// reverse proxy, VPN) and reports the overhead every path adds compared to the direct path.
+	PathLatencyMetric struct {
+		metric.Base[time.Duration]
+		group     metric.Group
+		endpoint  string
+		paths     []Path
+		clients   map[string]*http.Client
+		durations map[string][]time.Duration
+		interval  time.Duration
+		mutex     sync.Mutex
+	}
+)
+
+// NewPathLatencyMetric creates the metric measuring the endpoint (e.g. '/eth/v1/node/health') behind every path. The first path is the baseline.
+func NewPathLatencyMetric(group metric.Group, endpoint, name string, paths []Path, interval time.Duration, healthCondition []metric.HealthCondition[time.Duration]) (*PathLatencyMetric, error) {
+	clients := make(map[string]*http.Client, len(paths))
+	for _, path := range paths {
+		// Paths without proxy of their own go through the proxy of the node, if any
+		transport := httpclient.NewTransport()
+		if path.Proxy != "" {
+			proxyURL, err := url.Parse(path.Proxy)
+			if err != nil {
+				return nil, errors.Join(err, fmt.Errorf("proxy of path '%s' was not a valid URL", path.Name))
+			}
+			transport.Proxy = http.ProxyURL(proxyURL)
+		}
+		// Measurements are bounded by their context
+		clients[path.Name] = &http.Client{Transport: httpclient.Configure(httpclient.Intercept(transport))}
+	}
+
+	return &PathLatencyMetric{
+		Base: metric.Base[time.Duration]{
+			HealthConditions: healthCondition,
+			Name:             name,
+		},
+		group:     group,
+		endpoint:  endpoint,
+		paths:     paths,
+		clients:   clients,
+		durations: make(map[string][]time.Duration, len(paths)),
+		interval:  interval,
+	}, nil
+}
+
+// PathP50Measurement is the name of the P50 latency measurement of the path
+func PathP50Measurement(path string) string {
+	return path + pathP50Suffix
+}
+
+// PathP90Measurement is the name of the P90 latency measurement of the path
+func PathP90Measurement(path string) string {
+	return path + pathP90Suffix
+}
+
+// PathOverheadP50Measurement is the name of the P50 overhead measurement of the path compared to the baseline
+func PathOverheadP50Measurement(path string) string {
+	return path + pathOverheadP50Suffix
+}
+
+func (p *PathLatencyMetric) Measure(ctx context.Context) {
+	p.Runner(p.interval).Run(ctx, p.measure)
+}
+
+func (p *PathLatencyMetric) measure(ctx context.Context) {
+	// Paths are measured one after another so they don't compete for bandwidth
+	latencies := make(map[string]time.Duration, len(p.paths))
+	for _, path := range p.paths {
+		latency, err := p.measurePath(ctx, path)
+		if err != nil {
+			logger.WriteError(p.group, p.Name, errors.Join(err, fmt.Errorf("failed measuring path '%s'", path.Name)))
+			continue
+		}
+		latencies[path.Name] = latency
+	}
+
+	p.writeMetric(latencies)
+}
+
+func (p *PathLatencyMetric) measurePath(ctx context.Context, path Path) (time.Duration, error) {
+	ctx, cancel := p.MeasurementContext(ctx, time.Duration(float64(p.interval)*0.75))
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(path.Address, "/")+p.endpoint, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	start := clock.Now()
+	res, err := p.clients[path.Name].Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer res.Body.Close()
+
+	return clock.Since(start), nil
+}
+
+func (p *PathLatencyMetric) writeMetric(latencies map[string]time.Duration) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	logged := make(map[string]any, len(latencies))
+	for name, latency := range latencies {
+		p.durations[name] = append(p.durations[name], latency)
+		logged[name] = latency
+	}
+
+	values := make(map[string]time.Duration)
+	for name, durations := range p.durations {
+		percentiles := metric.CalculatePercentiles(append([]time.Duration(nil), durations...), 50, 90)
+		values[PathP50Measurement(name)] = percentiles[50]
+		values[PathP90Measurement(name)] = percentiles[90]
+	}
+
+	baseline, ok := values[PathP50Measurement(p.paths[0].Name)]
+	if ok {
+		for _, path := range p.paths[1:] {
+			if p50, ok := values[PathP50Measurement(path.Name)]; ok {
+				values[PathOverheadP50Measurement(path.Name)] = p50 - baseline
+			}
+		}
+	}
+
+	p.AddDataPoint(values)
+
+	logger.WriteMetric(p.group, p.Name, logged)
+}
+
+func (p *PathLatencyMetric) AggregateResults() string {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	var latest map[string]time.Duration
+	if dataPoint, ok := p.Latest(); ok {
+		latest = dataPoint.Values
+	}
+
+	var lines []string
+	for i, path := range p.paths {
+		line := fmt.Sprintf("%s: p50=%s, p90=%s",
+			path.Name,
+			format.Duration(latest[PathP50Measurement(path.Name)]),
+			format.Duration(latest[PathP90Measurement(path.Name)]))
+		if i > 0 {
+			line += fmt.Sprintf(", overhead_p50=%s", format.Duration(latest[PathOverheadP50Measurement(path.Name)]))
+		}
+		lines = append(lines, line)
+	}
+
+	return strings.Join(lines, " \n ")
+}
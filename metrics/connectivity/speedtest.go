@@ -0,0 +1,204 @@
+package connectivity
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os/exec"
+	"strconv"
+	"time"
+)
+
+const (
+	SpeedTestMethodIperf3 = "iperf3"
+	SpeedTestMethodHTTP   = "http"
+
+	defaultIperf3Port        = "5201"
+	defaultSpeedTestDuration = time.Second * 10
+)
+
+type (
+	// SpeedTest measures the available upload and download bandwidth of the machine, either against an iperf3 server
+	// (requires the iperf3 binary) or by downloading an HTTP test file and uploading data to an HTTP endpoint
+	SpeedTest struct {
+		// Iperf3 is the 'host[:port]' of the iperf3 server, it takes precedence over the HTTP test
+		Iperf3      string
+		DownloadURL string
+		UploadURL   string
+		// Duration of every direction, defaults to 10 seconds
+		Duration time.Duration
+	}
+
+	SpeedTestResult struct {
+		Method                string    `json:"method"`
+		Target                string    `json:"target"`
+		MeasuredAt            time.Time `json:"measured_at"`
+		DownloadBitsPerSecond float64   `json:"download_bits_per_second"`
+		UploadBitsPerSecond   float64   `json:"upload_bits_per_second"`
+		Error                 string    `json:"error,omitempty"`
+	}
+
+	iperf3Output struct {
+		End struct {
+			SumReceived struct {
+				BitsPerSecond float64 `json:"bits_per_second"`
+			} `json:"sum_received"`
+		} `json:"end"`
+		Error string `json:"error"`
+	}
+
+	// deadlineReader produces zeroes until the deadline and counts them
+	deadlineReader struct {
+		deadline time.Time
+		read     int64
+	}
+)
+
+// Run executes the speed test, failures are reported in the result so the run can continue without bandwidth context
+func (s SpeedTest) Run(ctx context.Context) SpeedTestResult {
+	var (
+		result SpeedTestResult
+		err    error
+	)
+	if s.Duration <= 0 {
+		s.Duration = defaultSpeedTestDuration
+	}
+	if s.Iperf3 != "" {
+		result, err = s.runIperf3(ctx)
+	} else {
+		result, err = s.runHTTP(ctx)
+	}
+	result.MeasuredAt = time.Now()
+	if err != nil {
+		result.Error = err.Error()
+	}
+	return result
+}
+
+func (s SpeedTest) runIperf3(ctx context.Context) (SpeedTestResult, error) {
+	result := SpeedTestResult{Method: SpeedTestMethodIperf3, Target: s.Iperf3}
+
+	host, port, err := net.SplitHostPort(s.Iperf3)
+	if err != nil {
+		host, port = s.Iperf3, defaultIperf3Port
+	}
+
+	// The client sends by default, the reverse mode lets the server send
+	result.UploadBitsPerSecond, err = s.iperf3(ctx, host, port)
+	if err != nil {
+		return result, errors.Join(err, errors.New("iperf3 upload test failed"))
+	}
+	result.DownloadBitsPerSecond, err = s.iperf3(ctx, host, port, "-R")
+	if err != nil {
+		return result, errors.Join(err, errors.New("iperf3 download test failed"))
+	}
+
+	return result, nil
+}
+
+func (s SpeedTest) iperf3(ctx context.Context, host, port string, args ...string) (float64, error) {
+	seconds := strconv.Itoa(max(int(s.Duration/time.Second), 1))
+	args = append([]string{"--client", host, "--port", port, "--json", "--time", seconds}, args...)
+
+	out, err := exec.CommandContext(ctx, "iperf3", args...).Output()
+	var output iperf3Output
+	if jsonErr := json.Unmarshal(out, &output); jsonErr != nil {
+		return 0, errors.Join(err, jsonErr)
+	}
+	if output.Error != "" {
+		return 0, errors.New(output.Error)
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	return output.End.SumReceived.BitsPerSecond, nil
+}
+
+func (s SpeedTest) runHTTP(ctx context.Context) (SpeedTestResult, error) {
+	result := SpeedTestResult{Method: SpeedTestMethodHTTP, Target: s.DownloadURL}
+
+	var err error
+	if s.DownloadURL != "" {
+		result.DownloadBitsPerSecond, err = s.download(ctx)
+		if err != nil {
+			return result, errors.Join(err, errors.New("HTTP download test failed"))
+		}
+	}
+	if s.UploadURL != "" {
+		result.UploadBitsPerSecond, err = s.upload(ctx)
+		if err != nil {
+			return result, errors.Join(err, errors.New("HTTP upload test failed"))
+		}
+	}
+
+	return result, nil
+}
+
+// download reads the test file until its end or until the test duration is over
+func (s SpeedTest) download(ctx context.Context) (float64, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.Duration)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.DownloadURL, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	start := time.Now()
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("received unsuccessful status code. Code: '%s'", res.Status)
+	}
+
+	read, err := io.Copy(io.Discard, res.Body)
+	if err != nil && !errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return 0, err
+	}
+
+	return bitsPerSecond(read, time.Since(start)), nil
+}
+
+// upload sends zeroes to the endpoint for the test duration
+func (s SpeedTest) upload(ctx context.Context) (float64, error) {
+	body := &deadlineReader{deadline: time.Now().Add(s.Duration)}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.UploadURL, body)
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/octet-stream")
+
+	start := time.Now()
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer res.Body.Close()
+
+	return bitsPerSecond(body.read, time.Since(start)), nil
+}
+
+func (r *deadlineReader) Read(p []byte) (int, error) {
+	if time.Now().After(r.deadline) {
+		return 0, io.EOF
+	}
+	clear(p)
+	r.read += int64(len(p))
+	return len(p), nil
+}
+
+func bitsPerSecond(bytes int64, elapsed time.Duration) float64 {
+	if elapsed <= 0 {
+		return 0
+	}
+	return float64(bytes) * 8 / elapsed.Seconds()
+}
@@ -0,0 +1,203 @@
+package consensus
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/clientinfo"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/httpclient"
+)
+
+var errEndpointNotSupported = errors.New("endpoint is not supported by the client")
+
+type (
+	// beaconAPI abstracts the parts of the beacon node API whose endpoints or response shapes differ between clients
+	beaconAPI interface {
+		peerCount(ctx context.Context, url string) (uint32, error)
+		attestationBlockRoot(ctx context.Context, url string, slot phase0.Slot) (phase0.Root, error)
+	}
+
+	standardAPI struct{}
+
+	// prysmAPI handles Prysm nodes, which serve the beacon API through a gRPC gateway. Older gateway versions
+	// don't implement the peer_count endpoint, encode quantities as numbers instead of strings and serve attestation
+	// data only through the native v1alpha1 API with base64 encoded roots.
+	prysmAPI struct {
+		standardAPI
+	}
+
+	// flexibleUint decodes quantities encoded either as JSON strings (per spec) or as JSON numbers
+	flexibleUint uint64
+)
+
+func apiFor(adapter clientinfo.Adapter) beaconAPI {
+	if adapter.GatewayAPI {
+		return prysmAPI{}
+	}
+	return standardAPI{}
+}
+
+func (standardAPI) peerCount(ctx context.Context, url string) (uint32, error) {
+	var resp struct {
+		Data struct {
+			Connected flexibleUint `json:"connected"`
+		} `json:"data"`
+	}
+	if err := getJSON(ctx, fmt.Sprintf("%s/eth/v1/node/peer_count", url), &resp); err != nil {
+		return 0, err
+	}
+	return uint32(resp.Data.Connected), nil
+}
+
+func (p prysmAPI) peerCount(ctx context.Context, url string) (uint32, error) {
+	count, err := p.standardAPI.peerCount(ctx, url)
+	if !errors.Is(err, errEndpointNotSupported) {
+		return count, err
+	}
+
+	// Fall back to the Prysm native peers endpoint exposed by the gateway
+	var resp struct {
+		Peers []json.RawMessage `json:"peers"`
+	}
+	if err := getJSON(ctx, fmt.Sprintf("%s/eth/v1alpha1/node/peers", url), &resp); err != nil {
+		return 0, errors.Join(err, errors.New("failed fetching peers from Prysm gateway"))
+	}
+	return uint32(len(resp.Peers)), nil
+}
+
+func (standardAPI) attestationBlockRoot(ctx context.Context, url string, slot phase0.Slot) (phase0.Root, error) {
+	var resp struct {
+		Data struct {
+			BeaconBlockRoot string `json:"beacon_block_root"`
+		} `json:"data"`
+	}
+	if err := getJSON(ctx, fmt.Sprintf("%s/eth/v1/validator/attestation_data?slot=%d&committee_index=0", url, slot), &resp); err != nil {
+		return phase0.Root{}, err
+	}
+	root, err := hex.DecodeString(strings.TrimPrefix(resp.Data.BeaconBlockRoot, "0x"))
+	if err != nil {
+		return phase0.Root{}, errors.Join(err, errors.New("failed decoding beacon block root"))
+	}
+	return toRoot(root)
+}
+
+func (p prysmAPI) attestationBlockRoot(ctx context.Context, url string, slot phase0.Slot) (phase0.Root, error) {
+	root, err := p.standardAPI.attestationBlockRoot(ctx, url, slot)
+	if !errors.Is(err, errEndpointNotSupported) {
+		return root, err
+	}
+
+	var resp struct {
+		BeaconBlockRoot string `json:"beaconBlockRoot"`
+	}
+	if err := getJSON(ctx, fmt.Sprintf("%s/eth/v1alpha1/validator/attestation?slot=%d&committee_index=0", url, slot), &resp); err != nil {
+		return phase0.Root{}, errors.Join(err, errors.New("failed fetching attestation data from Prysm gateway"))
+	}
+	decoded, err := base64.StdEncoding.DecodeString(resp.BeaconBlockRoot)
+	if err != nil {
+		return phase0.Root{}, errors.Join(err, errors.New("failed decoding beacon block root"))
+	}
+	return toRoot(decoded)
+}
+
+func toRoot(value []byte) (phase0.Root, error) {
+	var root phase0.Root
+	if len(value) != len(root) {
+		return root, fmt.Errorf("beacon block root has unexpected length %d", len(value))
+	}
+	copy(root[:], value)
+	return root, nil
+}
+
+func (f *flexibleUint) UnmarshalJSON(data []byte) error {
+	var value any
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	switch v := value.(type) {
+	case string:
+		parsed, err := strconv.ParseUint(v, 10, 64)
+		if err != nil {
+			return err
+		}
+		*f = flexibleUint(parsed)
+	case float64:
+		*f = flexibleUint(v)
+	default:
+		return fmt.Errorf("unexpected quantity '%s'", string(data))
+	}
+	return nil
+}
+
+func getJSON(ctx context.Context, url string, result any) error {
+	return requestJSON(ctx, http.MethodGet, url, nil, result)
+}
+
+// postJSON posts the body encoded as JSON and decodes the response into the result
+func postJSON(ctx context.Context, url string, body, result any) error {
+	encoded, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	return requestJSON(ctx, http.MethodPost, url, bytes.NewReader(encoded), result)
+}
+
+func requestJSON(ctx context.Context, method, url string, body io.Reader, result any) error {
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	res, err := httpclient.Default.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode == http.StatusNotFound || res.StatusCode == http.StatusNotImplemented {
+		return errors.Join(errEndpointNotSupported, responseError(res))
+	}
+	if res.StatusCode != http.StatusOK {
+		return responseError(res)
+	}
+
+	return json.NewDecoder(res.Body).Decode(result)
+}
+
+// responseError describes an unsuccessful response including its body
+func responseError(res *http.Response) error {
+	var responseString string
+	if res.Header.Get("Content-Type") == "application/json" {
+		var errorResponse any
+		if err := json.NewDecoder(res.Body).Decode(&errorResponse); err != nil {
+			return errors.Join(err, fmt.Errorf("received unsuccessful status code. Code: '%s'. Failed to JSON decode response", res.Status))
+		}
+		jsonErrResponse, err := json.Marshal(errorResponse)
+		if err != nil {
+			return errors.Join(err, fmt.Errorf("received unsuccessful status code. Code: '%s'. Failed to marshal response", res.Status))
+		}
+		responseString = string(jsonErrResponse)
+	} else {
+		body, err := io.ReadAll(res.Body)
+		if err != nil {
+			return errors.Join(err, fmt.Errorf("received unsuccessful status code. Code: '%s'. Failed to decode response", res.Status))
+		}
+		responseString = string(body)
+	}
+
+	return fmt.Errorf("received unsuccessful status code. Code: '%s'. Response: '%s'", res.Status, responseString)
+}
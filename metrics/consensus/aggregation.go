@@ -0,0 +1,216 @@
+package consensus
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/bits"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/clock"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/format"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/logger"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/metric"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/network"
+)
+
+const (
+	PoolAttestationsMeasurement = "PoolAttestations"
+	// AggregateSuccessRateMeasurement is the percentage of the slots the node served an aggregate for attestations of
+	// its pool
+	AggregateSuccessRateMeasurement = "AggregateSuccessRate"
+	AggregateLatencyP50Measurement  = "AggregateLatencyP50Ms"
+	AggregateLatencyP90Measurement  = "AggregateLatencyP90Ms"
+)
+
+type (
+	// AggregationMetric checks at the aggregation deadline of every slot the attestations in the pool of the node and
+	// requests the aggregate of the first of them, as an aggregator would. The node holds attestations of the subnets
+	// it is subscribed to only, slots without attestations in the pool are counted but not aggregated.
+	AggregationMetric struct {
+		metric.Base[float64]
+		url        string
+		spec       network.Spec
+		slots      int
+		aggregates int
+		failures   int
+		durations  []time.Duration
+		mutex      sync.Mutex
+	}
+
+	// poolAttestation is an attestation of the pool, committee bits are set from Electra on
+	poolAttestation struct {
+		Data          *phase0.AttestationData `json:"data"`
+		CommitteeBits string                  `json:"committee_bits"`
+	}
+)
+
+func NewAggregationMetric(url, name string, spec network.Spec, healthCondition []metric.HealthCondition[float64]) *AggregationMetric {
+	return &AggregationMetric{
+		Base: metric.Base[float64]{
+			HealthConditions: healthCondition,
+			Name:             name,
+		},
+		url:  url,
+		spec: spec,
+	}
+}
+
+func (a *AggregationMetric) Measure(ctx context.Context) {
+	slot := currentSlot(a.spec)
+	for {
+		slot++
+		deadline := time.After(clock.Until(slotTime(a.spec, slot).Add(a.spec.AggregationDeadline())))
+		select {
+		case <-deadline:
+			a.measure(ctx, slot)
+		case <-ctx.Done():
+			slog.With("metric_name", a.Name).Debug("metric was stopped")
+			return
+		}
+	}
+}
+
+func (a *AggregationMetric) measure(ctx context.Context, slot phase0.Slot) {
+	ctx, cancel := a.MeasurementContext(ctx, 5*time.Second)
+	defer cancel()
+
+	pool, err := a.fetchPool(ctx, slot)
+	if err != nil {
+		logger.WriteError(metric.ConsensusGroup, a.Name, errors.Join(err, errors.New("failed fetching attestation pool")))
+		return
+	}
+	if len(pool) == 0 || pool[0].Data == nil {
+		a.record(slot, 0, nil, 0)
+		return
+	}
+
+	start := clock.Now()
+	err = a.fetchAggregate(ctx, slot, pool[0])
+	duration := clock.Since(start)
+	if err != nil {
+		logger.WriteError(metric.ConsensusGroup, a.Name, errors.Join(err, errors.New("failed fetching aggregate attestation")))
+	}
+	a.record(slot, len(pool), err, duration)
+}
+
+// fetchPool returns the attestations of the slot in the pool, through the v2 endpoint serving Electra attestations
+// or the v1 endpoint of older nodes
+func (a *AggregationMetric) fetchPool(ctx context.Context, slot phase0.Slot) ([]poolAttestation, error) {
+	var pool struct {
+		Data []poolAttestation `json:"data"`
+	}
+	err := getJSON(ctx, fmt.Sprintf("%s/eth/v2/beacon/pool/attestations?slot=%d", a.url, slot), &pool)
+	if errors.Is(err, errEndpointNotSupported) {
+		err = getJSON(ctx, fmt.Sprintf("%s/eth/v1/beacon/pool/attestations?slot=%d", a.url, slot), &pool)
+	}
+	return pool.Data, err
+}
+
+func (a *AggregationMetric) fetchAggregate(ctx context.Context, slot phase0.Slot, attestation poolAttestation) error {
+	root, err := attestation.Data.HashTreeRoot()
+	if err != nil {
+		return errors.Join(err, errors.New("failed hashing attestation data"))
+	}
+	committee, err := attestation.committeeIndex()
+	if err != nil {
+		return err
+	}
+
+	var aggregate struct {
+		Data struct {
+			AggregationBits string `json:"aggregation_bits"`
+		} `json:"data"`
+	}
+	dataRoot := "0x" + hex.EncodeToString(root[:])
+	err = getJSON(ctx, fmt.Sprintf("%s/eth/v2/validator/aggregate_attestation?attestation_data_root=%s&slot=%d&committee_index=%d", a.url, dataRoot, slot, committee), &aggregate)
+	if errors.Is(err, errEndpointNotSupported) {
+		err = getJSON(ctx, fmt.Sprintf("%s/eth/v1/validator/aggregate_attestation?attestation_data_root=%s&slot=%d", a.url, dataRoot, slot), &aggregate)
+	}
+	if err != nil {
+		return err
+	}
+	if aggregate.Data.AggregationBits == "" {
+		return errors.New("aggregate attestation has no aggregation bits")
+	}
+	return nil
+}
+
+// committeeIndex is the first committee of the committee bits from Electra on, the index of the data before
+func (p poolAttestation) committeeIndex() (uint64, error) {
+	if p.CommitteeBits == "" {
+		return uint64(p.Data.Index), nil
+	}
+	committeeBits, err := hex.DecodeString(strings.TrimPrefix(p.CommitteeBits, "0x"))
+	if err != nil {
+		return 0, errors.Join(err, errors.New("failed decoding committee bits"))
+	}
+	// Bitvectors are little-endian, the bit of committee i is bit i%8 of byte i/8
+	for i, b := range committeeBits {
+		if b != 0 {
+			return uint64(i*8 + bits.TrailingZeros8(b)), nil
+		}
+	}
+	return 0, errors.New("attestation has no committee bit set")
+}
+
+func (a *AggregationMetric) record(slot phase0.Slot, poolSize int, err error, duration time.Duration) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	a.slots++
+	if poolSize != 0 {
+		if err != nil {
+			a.failures++
+		} else {
+			a.aggregates++
+			a.durations = append(a.durations, duration)
+		}
+	}
+
+	values := map[string]float64{
+		PoolAttestationsMeasurement: float64(poolSize),
+	}
+	if attempts := a.aggregates + a.failures; attempts != 0 {
+		values[AggregateSuccessRateMeasurement] = float64(a.aggregates) / float64(attempts) * 100
+	}
+	if len(a.durations) != 0 {
+		percentiles := metric.CalculatePercentiles(append([]time.Duration(nil), a.durations...), 50, 90)
+		values[AggregateLatencyP50Measurement] = float64(percentiles[50]) / float64(time.Millisecond)
+		values[AggregateLatencyP90Measurement] = float64(percentiles[90]) / float64(time.Millisecond)
+	}
+	a.AddDataPoint(values)
+
+	logger.WriteMetric(metric.ConsensusGroup, a.Name, map[string]any{
+		"Slot":                      slot,
+		PoolAttestationsMeasurement: poolSize,
+		"AggregateLatency":          duration,
+		"AggregateFailures":         a.failures,
+	})
+}
+
+func (a *AggregationMetric) AggregateResults() string {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	var pool []float64
+	for _, point := range a.Snapshot() {
+		pool = append(pool, point.Values[PoolAttestationsMeasurement])
+	}
+	poolPercentiles := metric.CalculatePercentiles(pool, 50, 90)
+
+	var p50, p90 time.Duration
+	if len(a.durations) != 0 {
+		percentiles := metric.CalculatePercentiles(append([]time.Duration(nil), a.durations...), 50, 90)
+		p50, p90 = percentiles[50], percentiles[90]
+	}
+
+	return fmt.Sprintf("slots=%d, pool p50=%.0f, p90=%.0f \n aggregates=%d, failed=%d, latency p50=%s, p90=%s",
+		a.slots, poolPercentiles[50], poolPercentiles[90], a.aggregates, a.failures, format.Duration(p50), format.Duration(p90))
+}
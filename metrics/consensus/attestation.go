@@ -9,17 +9,20 @@ import (
 
 	client "github.com/attestantio/go-eth2-client"
 	"github.com/attestantio/go-eth2-client/api"
-	v1 "github.com/attestantio/go-eth2-client/api/v1"
-	"github.com/attestantio/go-eth2-client/auto"
+	eth2http "github.com/attestantio/go-eth2-client/http"
 	"github.com/attestantio/go-eth2-client/spec/phase0"
 	"github.com/rs/zerolog"
 
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/clientinfo"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/clock"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/exporter"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/httpclient"
 	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/logger"
 	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/metric"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/network"
 )
 
 const (
-	blockMintingTime             = time.Second * 12
 	unreadyBlockDelay            = time.Millisecond * 200
 	MissedBlockMeasurement       = "MissedBlock"
 	ReceivedBlockMeasurement     = "ReceivedBlock"
@@ -41,20 +44,26 @@ type (
 	AttestationMetric struct {
 		metric.Base[float64]
 		client                client.Service
-		genesisTime           time.Time
+		url                   string
+		api                   beaconAPI
+		spec                  network.Spec
 		eventBlockRoots       sync.Map
 		attestationBlockRoots sync.Map
+		backfillEpochs        int
+		backfillIndices       []uint64
+		backfillOnce          sync.Once
 	}
 )
 
-func NewAttestationMetric(url, name string, genesisTime time.Time, healthCondition []metric.HealthCondition[float64]) *AttestationMetric {
-	client, err := auto.New(
+func NewAttestationMetric(url, name string, spec network.Spec, healthCondition []metric.HealthCondition[float64]) (*AttestationMetric, error) {
+	client, err := eth2http.New(
 		context.TODO(),
-		auto.WithLogLevel(zerolog.DebugLevel),
-		auto.WithAddress(url), // Modify this to point to your solo staking Beacon node's API URL
+		eth2http.WithLogLevel(zerolog.DebugLevel),
+		eth2http.WithAddress(url), // Modify this to point to your solo staking Beacon node's API URL
+		eth2http.WithHTTPClient(httpclient.Default),
 	)
 	if err != nil {
-		panic(err.Error())
+		return nil, err
 	}
 	return &AttestationMetric{
 		Base: metric.Base[float64]{
@@ -62,37 +71,72 @@ func NewAttestationMetric(url, name string, genesisTime time.Time, healthConditi
 			Name:             name,
 		},
 		client:                client,
+		url:                   url,
 		eventBlockRoots:       sync.Map{},
 		attestationBlockRoots: sync.Map{},
-		genesisTime:           genesisTime,
+		spec:                  spec,
+	}, nil
+}
+
+// WithClient adapts the metric to the API differences of the detected consensus client. Clients following the
+// standard beacon API are served by the go-eth2-client, gateway based clients (Prysm) get their own adapter.
+func (a *AttestationMetric) WithClient(adapter clientinfo.Adapter) *AttestationMetric {
+	if adapter.GatewayAPI {
+		a.api = apiFor(adapter)
 	}
+	return a
 }
 
+// WithGRPC fetches the attestation data through the native gRPC API at the address, selected for clients having one
+func (a *AttestationMetric) WithGRPC(address string) *AttestationMetric {
+	if address == "" {
+		return a
+	}
+	api, err := newGRPCAPI(address)
+	if err != nil {
+		slog.With("err", err.Error()).With("metric_name", a.Name).Warn("failed creating gRPC client, using the beacon API")
+		return a
+	}
+	a.api = api
+	return a
+}
+
+// Measure listens to the head events and fetches the attestation data of every slot until the context is done. A panic
+// of either stops both and is raised, so the metric is restarted as a whole.
 func (a *AttestationMetric) Measure(ctx context.Context) {
-	go a.launchListener(ctx)
+	goroutines, ctx := metric.NewGoroutines(ctx, a.Name)
+	goroutines.Go(func() { a.launchListener(ctx) })
+	if a.backfillEpochs > 0 {
+		// A restarted metric doesn't backfill the same epochs again
+		a.backfillOnce.Do(func() {
+			goroutines.Go(func() { a.backfill(ctx) })
+		})
+	}
 
-	go func() {
-		slot := currentSlot(a.genesisTime)
+	goroutines.Go(func() {
+		slot := currentSlot(a.spec)
 		const calculationSlotLag = 2
 		laggedSlot := slot + calculationSlotLag
 		for {
 			slot++
-			nextSlotWithDelay := time.After(time.Until(slotTime(a.genesisTime, slot).Add(time.Second * 4)))
+			nextSlotWithDelay := time.After(clock.Until(slotTime(a.spec, slot).Add(a.spec.AttestationDeadline())))
 			select {
 			case <-nextSlotWithDelay:
-				go func(slot phase0.Slot) {
+				slot := slot
+				metric.Go(ctx, a.Name, func() {
 					a.fetchAttestationData(ctx, slot)
 
 					if slot > laggedSlot {
-						a.calculateMeasurements(slot - calculationSlotLag)
+						a.calculateMeasurements(ctx, slot-calculationSlotLag)
 					}
-				}(slot)
+				})
 			case <-ctx.Done():
 				slog.With("metric_name", a.Name).Debug("metric was stopped")
 				return
 			}
 		}
-	}()
+	})
+	goroutines.Wait()
 }
 
 func (a *AttestationMetric) fetchAttestationData(ctx context.Context, slot phase0.Slot) {
@@ -106,22 +150,17 @@ func (a *AttestationMetric) fetchAttestationData(ctx context.Context, slot phase
 }
 
 func (a *AttestationMetric) launchListener(ctx context.Context) {
-	if err := a.client.(client.EventsProvider).Events(
-		ctx,
-		[]string{"head"},
-		func(event *v1.Event) {
-			data := event.Data.(*v1.HeadEvent)
+	subscribeHeads(ctx, a.url, a.Name, func(head headEvent) {
+		// The callbacks run in the goroutine of the event stream
+		defer metric.Recover(ctx, a.Name)
 
-			a.eventBlockRoots.Store(data.Slot, SlotData{
-				Received:  time.Now(),
-				RootBlock: data.Block,
-			})
+		a.eventBlockRoots.Store(head.Slot, SlotData{
+			Received:  clock.Now(),
+			RootBlock: head.Block,
+		})
 
-			go a.checkUnreadyBlock(ctx, data.Slot, data.Block)
-		},
-	); err != nil {
-		logger.WriteError(metric.ConsensusGroup, a.Name, err)
-	}
+		metric.Go(ctx, a.Name, func() { a.checkUnreadyBlock(ctx, head.Slot, head.Block) })
+	})
 }
 
 func (a *AttestationMetric) checkUnreadyBlock(ctx context.Context, slot phase0.Slot, block phase0.Root) {
@@ -143,6 +182,12 @@ func (a *AttestationMetric) checkUnreadyBlock(ctx context.Context, slot phase0.S
 }
 
 func (a *AttestationMetric) fetchAttestationBlockRoot(ctx context.Context, slot phase0.Slot) (phase0.Root, error) {
+	if a.api != nil {
+		ctx, cancel := context.WithTimeout(ctx, 6*time.Second)
+		defer cancel()
+		return a.api.attestationBlockRoot(ctx, a.url, slot)
+	}
+
 	resp, err := a.client.(client.AttestationDataProvider).AttestationData(
 		ctx,
 		&api.AttestationDataOpts{
@@ -164,7 +209,11 @@ func (a *AttestationMetric) AggregateResults() string {
 		missedAttestations, freshAttestations, missedBlocks, receivedBlocks, unreadyBlocks, correctness float64
 	)
 
-	for _, point := range a.DataPoints {
+	var backfillMissed, backfillVotes, backfillCorrect float64
+	for _, point := range a.Snapshot() {
+		backfillMissed += point.Values[BackfillMissedBlockMeasurement]
+		backfillVotes += point.Values[BackfillVoteMeasurement]
+		backfillCorrect += point.Values[BackfillHeadCorrectMeasurement]
 		missedAttestations += point.Values[MissedAttestationMeasurement]
 		missedBlocks += point.Values[MissedBlockMeasurement]
 		freshAttestations += point.Values[FreshAttestationMeasurement]
@@ -180,7 +229,7 @@ func (a *AttestationMetric) AggregateResults() string {
 		}
 	}
 
-	return fmt.Sprintf(
+	result := fmt.Sprintf(
 		"missed_attestations=%.0f, unready_blocks_%d_ms=%.0f, missed_blocks=%.0f \n fresh_attestations=%.0f received_blocks=%.0f, correctness=%.2f %%",
 		missedAttestations,
 		unreadyBlockDelay/time.Millisecond, unreadyBlocks,
@@ -188,9 +237,14 @@ func (a *AttestationMetric) AggregateResults() string {
 		freshAttestations,
 		receivedBlocks,
 		correctness)
+	if a.backfillEpochs > 0 {
+		result += fmt.Sprintf(" \n backfilled_epochs=%d: missed_blocks=%.0f, head_correct_votes=%.0f/%.0f",
+			a.backfillEpochs, backfillMissed, backfillCorrect, backfillVotes)
+	}
+	return result
 }
 
-func (a *AttestationMetric) calculateMeasurements(slot phase0.Slot) {
+func (a *AttestationMetric) calculateMeasurements(ctx context.Context, slot phase0.Slot) {
 	eventBlockRoot, ok := a.eventBlockRoots.Load(slot)
 
 	if !ok {
@@ -198,7 +252,7 @@ func (a *AttestationMetric) calculateMeasurements(slot phase0.Slot) {
 			MissedBlockMeasurement: 1,
 		})
 
-		missedBlocksMetric.Inc()
+		exporter.CountSlot(ctx, a.url, exporter.MissedBlock)
 
 		logger.WriteMetric(metric.ConsensusGroup, a.Name, map[string]any{
 			MissedBlockMeasurement: 1,
@@ -213,15 +267,14 @@ func (a *AttestationMetric) calculateMeasurements(slot phase0.Slot) {
 			ReceivedBlockMeasurement:     1,
 		})
 
-		missedAttestationsMetric.Inc()
-		receivedBlocksMetric.Inc()
+		exporter.CountSlot(ctx, a.url, exporter.MissedAttestation)
 
 		logger.WriteMetric(metric.ConsensusGroup, a.Name, map[string]any{
 			MissedAttestationMeasurement: 1,
 			ReceivedBlockMeasurement:     1,
 		})
 
-		a.calculateCorrectness()
+		a.calculateCorrectness(ctx)
 
 		return
 	}
@@ -232,8 +285,7 @@ func (a *AttestationMetric) calculateMeasurements(slot phase0.Slot) {
 			ReceivedBlockMeasurement:    1,
 		})
 
-		freshAttestationsMetric.Inc()
-		receivedBlocksMetric.Inc()
+		exporter.CountSlot(ctx, a.url, exporter.FreshAttestation)
 
 		logger.WriteMetric(metric.ConsensusGroup, a.Name, map[string]any{
 			FreshAttestationMeasurement: 1,
@@ -241,15 +293,16 @@ func (a *AttestationMetric) calculateMeasurements(slot phase0.Slot) {
 		})
 	}
 
-	a.calculateCorrectness()
+	a.calculateCorrectness(ctx)
 }
 
-func (a *AttestationMetric) calculateCorrectness() {
+// calculateCorrectness combines the head votes measured during the run with the backfilled ones
+func (a *AttestationMetric) calculateCorrectness(ctx context.Context) {
 	var freshAttestations, receivedBlocks float64
 
-	for _, point := range a.DataPoints {
-		freshAttestations += point.Values[FreshAttestationMeasurement]
-		receivedBlocks += point.Values[ReceivedBlockMeasurement]
+	for _, point := range a.Snapshot() {
+		freshAttestations += point.Values[FreshAttestationMeasurement] + point.Values[BackfillHeadCorrectMeasurement]
+		receivedBlocks += point.Values[ReceivedBlockMeasurement] + point.Values[BackfillVoteMeasurement]
 	}
 
 	correctness := freshAttestations / receivedBlocks * 100
@@ -258,17 +311,17 @@ func (a *AttestationMetric) calculateCorrectness() {
 		CorrectnessMeasurement: correctness,
 	})
 
-	correctnessMetric.Set(correctness)
+	exporter.SetCorrectness(ctx, a.url, correctness)
 
 	logger.WriteMetric(metric.ConsensusGroup, a.Name, map[string]any{
 		CorrectnessMeasurement: correctness,
 	})
 }
 
-func slotTime(genesisTime time.Time, slot phase0.Slot) time.Time {
-	return genesisTime.Add(time.Duration(slot) * 12 * time.Second)
+func slotTime(spec network.Spec, slot phase0.Slot) time.Time {
+	return spec.SlotTime(uint64(slot))
 }
 
-func currentSlot(genesisTime time.Time) phase0.Slot {
-	return phase0.Slot(time.Since(genesisTime) / (12 * time.Second))
+func currentSlot(spec network.Spec) phase0.Slot {
+	return phase0.Slot(spec.CurrentSlot())
 }
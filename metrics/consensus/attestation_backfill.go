@@ -0,0 +1,141 @@
+package consensus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/logger"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/metric"
+)
+
+const (
+	// BackfillMissedBlockMeasurement counts the slots without block of the backfilled epochs
+	BackfillMissedBlockMeasurement = "BackfillMissedBlock"
+	// BackfillVoteMeasurement and BackfillHeadCorrectMeasurement count the attestations of the configured validators
+	// during the backfilled epochs and those which voted for the correct head
+	BackfillVoteMeasurement        = "BackfillVote"
+	BackfillHeadCorrectMeasurement = "BackfillHeadCorrect"
+)
+
+// WithBackfill makes the metric evaluate the epochs before the run at startup. The slots without block are counted
+// from the block headers, the head votes of the validators from their attestation rewards, which only pay the head
+// component for a timely vote on the correct head. Without validator indices only the blocks are backfilled.
+func (a *AttestationMetric) WithBackfill(epochs int, indices []uint64) *AttestationMetric {
+	a.backfillEpochs = epochs
+	a.backfillIndices = indices
+	return a
+}
+
+func (a *AttestationMetric) backfill(ctx context.Context) {
+	// Rewards of an epoch are known once the following epoch is processed, so the backfill ends two epochs back
+	current := a.spec.Epoch(a.spec.CurrentSlot())
+	if current < 2 {
+		return
+	}
+	last := current - 2
+	first := uint64(0)
+	if last+1 > uint64(a.backfillEpochs) {
+		first = last + 1 - uint64(a.backfillEpochs)
+	}
+
+	if len(a.backfillIndices) == 0 {
+		slog.With("metric_name", a.Name).Info("no validator indices configured, only missed blocks are backfilled")
+	}
+
+	var missed, votes, correct float64
+	for epoch := first; epoch <= last; epoch++ {
+		epochMissed, err := a.backfillBlocks(ctx, epoch)
+		if err != nil {
+			logger.WriteError(metric.ConsensusGroup, a.Name, errors.Join(err, fmt.Errorf("failed backfilling blocks of epoch %d", epoch)))
+			return
+		}
+		missed += epochMissed
+
+		if len(a.backfillIndices) == 0 {
+			continue
+		}
+		epochVotes, epochCorrect, err := a.backfillVotes(ctx, epoch)
+		if err != nil {
+			logger.WriteError(metric.ConsensusGroup, a.Name, errors.Join(err, fmt.Errorf("failed backfilling votes of epoch %d", epoch)))
+			return
+		}
+		votes += epochVotes
+		correct += epochCorrect
+	}
+
+	values := map[string]float64{
+		BackfillMissedBlockMeasurement: missed,
+		BackfillVoteMeasurement:        votes,
+		BackfillHeadCorrectMeasurement: correct,
+	}
+	a.AddDataPoint(values)
+	logger.WriteMetric(metric.ConsensusGroup, a.Name, map[string]any{
+		"BackfillEpochs":               last - first + 1,
+		BackfillMissedBlockMeasurement: missed,
+		BackfillVoteMeasurement:        votes,
+		BackfillHeadCorrectMeasurement: correct,
+	})
+
+	if votes != 0 {
+		a.calculateCorrectness(ctx)
+	}
+}
+
+// backfillBlocks counts the slots of the epoch without block, the node answers 404 for them
+func (a *AttestationMetric) backfillBlocks(ctx context.Context, epoch uint64) (float64, error) {
+	var missed float64
+	for slot := a.spec.EpochStart(epoch); slot < a.spec.EpochStart(epoch+1); slot++ {
+		var resp struct {
+			Data struct {
+				Root string `json:"root"`
+			} `json:"data"`
+		}
+		err := getJSON(ctx, fmt.Sprintf("%s/eth/v1/beacon/headers/%d", a.url, phase0.Slot(slot)), &resp)
+		if errors.Is(err, errEndpointNotSupported) {
+			missed++
+			continue
+		}
+		if err != nil {
+			return 0, err
+		}
+	}
+	return missed, nil
+}
+
+// backfillVotes counts the attestations of the validators during the epoch and those rewarded for the head
+func (a *AttestationMetric) backfillVotes(ctx context.Context, epoch uint64) (float64, float64, error) {
+	indices := make([]string, 0, len(a.backfillIndices))
+	for _, index := range a.backfillIndices {
+		indices = append(indices, strconv.FormatUint(index, 10))
+	}
+
+	var resp struct {
+		Data struct {
+			TotalRewards []struct {
+				ValidatorIndex string `json:"validator_index"`
+				Head           string `json:"head"`
+			} `json:"total_rewards"`
+		} `json:"data"`
+	}
+	if err := postJSON(ctx, fmt.Sprintf("%s/eth/v1/beacon/rewards/attestations/%d", a.url, epoch), indices, &resp); err != nil {
+		return 0, 0, err
+	}
+
+	var votes, correct float64
+	for _, reward := range resp.Data.TotalRewards {
+		head, err := strconv.ParseInt(reward.Head, 10, 64)
+		if err != nil {
+			return 0, 0, errors.Join(err, fmt.Errorf("failed parsing head reward of validator %s", reward.ValidatorIndex))
+		}
+		votes++
+		if head > 0 {
+			correct++
+		}
+	}
+	return votes, correct, nil
+}
@@ -0,0 +1,168 @@
+package consensus
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/clock"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/format"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/logger"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/metric"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/network"
+)
+
+const (
+	BalanceDeltaMeasurement = "BalanceDeltaGwei"
+	APRMeasurement          = "APR"
+
+	// withdrawalThreshold separates partial withdrawals from penalties, which stay far below it even for missed sync committee duties
+	withdrawalThreshold = 10_000_000 // Gwei
+	yearDuration        = time.Hour * 24 * 365
+)
+
+type (
+	// BalanceMetric samples the balances of the configured validators at every epoch boundary and reports the
+	// rewards earned since the first sample, along with the annual percentage rate they extrapolate to.
+	// Balance drops above withdrawalThreshold are treated as withdrawals and don't count as losses.
+	// Rewards are mostly credited once per epoch, so the baseline is an epoch boundary too and the rewards are
+	// annualized over the epochs between the samples rather than the time it took to take them.
+	BalanceMetric struct {
+		metric.Base[float64]
+		url          string
+		spec         network.Spec
+		indices      []uint64
+		balances     map[string]*validatorBalance
+		started      bool
+		startEpoch   uint64
+		sampledEpoch uint64
+		mutex        sync.Mutex
+	}
+
+	validatorBalance struct {
+		initial, current, withdrawn uint64
+	}
+)
+
+func NewBalanceMetric(url, name string, spec network.Spec, indices []uint64, healthCondition []metric.HealthCondition[float64]) *BalanceMetric {
+	return &BalanceMetric{
+		Base: metric.Base[float64]{
+			HealthConditions: healthCondition,
+			Name:             name,
+		},
+		url:      url,
+		spec:     spec,
+		indices:  indices,
+		balances: make(map[string]*validatorBalance, len(indices)),
+	}
+}
+
+func (b *BalanceMetric) Measure(ctx context.Context) {
+	// Every sample is taken at an epoch boundary, the first one is the baseline
+	epoch := b.spec.Epoch(b.spec.CurrentSlot())
+	for {
+		epoch++
+		// Sampling at the attestation deadline gives the node time to process the first block of the epoch
+		epochStart := time.After(clock.Until(b.spec.SlotTime(b.spec.EpochStart(epoch)).Add(b.spec.AttestationDeadline())))
+		select {
+		case <-epochStart:
+			b.measure(ctx, epoch)
+		case <-ctx.Done():
+			slog.With("metric_name", b.Name).Debug("metric was stopped")
+			return
+		}
+	}
+}
+
+func (b *BalanceMetric) measure(ctx context.Context, epoch uint64) {
+	ctx, cancel := b.MeasurementContext(ctx, 10*time.Second)
+	defer cancel()
+
+	ids := make([]string, 0, len(b.indices))
+	for _, index := range b.indices {
+		ids = append(ids, strconv.FormatUint(index, 10))
+	}
+
+	var resp struct {
+		Data []struct {
+			Index   string       `json:"index"`
+			Balance flexibleUint `json:"balance"`
+		} `json:"data"`
+	}
+	if err := getJSON(ctx, fmt.Sprintf("%s/eth/v1/beacon/states/head/validator_balances?id=%s", b.url, strings.Join(ids, ",")), &resp); err != nil {
+		logger.WriteError(metric.ConsensusGroup, b.Name, err)
+		return
+	}
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if !b.started {
+		b.started, b.startEpoch = true, epoch
+	}
+	b.sampledEpoch = epoch
+
+	for _, validator := range resp.Data {
+		balance := uint64(validator.Balance)
+		state, ok := b.balances[validator.Index]
+		if !ok {
+			b.balances[validator.Index] = &validatorBalance{initial: balance, current: balance}
+			continue
+		}
+		if state.current > balance && state.current-balance >= withdrawalThreshold {
+			state.withdrawn += state.current - balance
+		}
+		state.current = balance
+	}
+
+	b.writeMetric()
+}
+
+func (b *BalanceMetric) writeMetric() {
+	delta, apr := b.rewards()
+
+	b.AddDataPoint(map[string]float64{
+		BalanceDeltaMeasurement: delta,
+		APRMeasurement:          apr,
+	})
+
+	logger.WriteMetric(metric.ConsensusGroup, b.Name, map[string]any{
+		BalanceDeltaMeasurement: delta,
+		APRMeasurement:          apr,
+		"Validators":            len(b.balances),
+	})
+}
+
+// rewards returns the balance delta of all validators in Gwei and the annual percentage rate it extrapolates to
+func (b *BalanceMetric) rewards() (delta, apr float64) {
+	var initial float64
+	for _, state := range b.balances {
+		initial += float64(state.initial)
+		delta += float64(state.current+state.withdrawn) - float64(state.initial)
+	}
+
+	elapsed := b.elapsed()
+	if initial == 0 || elapsed <= 0 {
+		return delta, 0
+	}
+	return delta, delta / initial * float64(yearDuration) / float64(elapsed) * 100
+}
+
+// elapsed returns the time the epochs between the baseline and the latest sample span
+func (b *BalanceMetric) elapsed() time.Duration {
+	return time.Duration(b.sampledEpoch-b.startEpoch) * b.spec.EpochDuration()
+}
+
+func (b *BalanceMetric) AggregateResults() string {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	delta, apr := b.rewards()
+
+	return fmt.Sprintf("Validators: %d, Balance Delta: %s Gwei, APR: %s %%, Sampled: %s",
+		len(b.balances), format.Number(delta, 0), format.Number(apr, 2), format.Duration(b.elapsed()))
+}
@@ -0,0 +1,72 @@
+package consensus
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/network"
+)
+
+func TestGivenBalanceSamplesWhenRewardsThenAnnualizedOverEpochsWithoutWithdrawals(t *testing.T) {
+	// A day of mainnet epochs
+	const day = 225
+
+	tests := []struct {
+		name      string
+		balances  []uint64
+		epochs    []uint64
+		wantDelta float64
+		wantAPR   float64
+	}{
+		{
+			name:     "baseline only",
+			balances: []uint64{32_000_000_000},
+			epochs:   []uint64{10},
+		},
+		{
+			name:      "rewards over a day",
+			balances:  []uint64{32_000_000_000, 32_002_000_000},
+			epochs:    []uint64{10, 10 + day},
+			wantDelta: 2_000_000,
+			wantAPR:   2.28125,
+		},
+		{
+			name:      "withdrawal is not a loss",
+			balances:  []uint64{32_000_000_000, 32_002_000_000, 31_002_000_000},
+			epochs:    []uint64{10, 10 + day/2, 10 + day},
+			wantDelta: 2_000_000,
+			wantAPR:   2.28125,
+		},
+		{
+			name:      "penalty is a loss",
+			balances:  []uint64{32_000_000_000, 31_999_000_000},
+			epochs:    []uint64{10, 10 + day},
+			wantDelta: -1_000_000,
+			wantAPR:   -1.140625,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var balance uint64
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprintf(w, `{"data":[{"index":"1","balance":"%d"}]}`, balance)
+			}))
+			defer server.Close()
+			balances := NewBalanceMetric(server.URL, "Balances", network.DefaultSpec(network.Mainnet), []uint64{1}, nil)
+
+			for i, epoch := range test.epochs {
+				balance = test.balances[i]
+				balances.measure(context.Background(), epoch)
+			}
+			delta, apr := balances.rewards()
+
+			assert.Equal(t, test.wantDelta, delta)
+			assert.InDelta(t, test.wantAPR, apr, 1e-9)
+		})
+	}
+}
@@ -0,0 +1,167 @@
+package consensus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/clock"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/format"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/logger"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/metric"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/network"
+)
+
+const (
+	BlobsMeasurement = "Blobs"
+	// MissingBlobsMeasurement counts the blobs committed to by the blocks of the run the node couldn't serve at the
+	// attestation deadline of their slot
+	MissingBlobsMeasurement     = "MissingBlobs"
+	BlobRetrievalP50Measurement = "BlobRetrievalP50Ms"
+	BlobRetrievalP90Measurement = "BlobRetrievalP90Ms"
+)
+
+type (
+	// BlobMetric checks every block with blob KZG commitments for the availability of its blob sidecars at the
+	// attestation deadline of its slot and times their retrieval. A node falling behind on blobs can't attest to the
+	// blocks carrying them even though it follows the chain otherwise.
+	BlobMetric struct {
+		metric.Base[float64]
+		url       string
+		spec      network.Spec
+		blocks    int
+		blobs     int
+		missing   int
+		durations []time.Duration
+		mutex     sync.Mutex
+	}
+)
+
+func NewBlobMetric(url, name string, spec network.Spec, healthCondition []metric.HealthCondition[float64]) *BlobMetric {
+	return &BlobMetric{
+		Base: metric.Base[float64]{
+			HealthConditions: healthCondition,
+			Name:             name,
+		},
+		url:  url,
+		spec: spec,
+	}
+}
+
+func (b *BlobMetric) Measure(ctx context.Context) {
+	slot := currentSlot(b.spec)
+	for {
+		slot++
+		deadline := time.After(clock.Until(slotTime(b.spec, slot).Add(b.spec.AttestationDeadline())))
+		select {
+		case <-deadline:
+			b.measure(ctx, slot)
+		case <-ctx.Done():
+			slog.With("metric_name", b.Name).Debug("metric was stopped")
+			return
+		}
+	}
+}
+
+func (b *BlobMetric) measure(ctx context.Context, slot phase0.Slot) {
+	ctx, cancel := b.MeasurementContext(ctx, 5*time.Second)
+	defer cancel()
+
+	// The blinded block carries the commitments without the transactions of the payload
+	var block struct {
+		Data struct {
+			Message struct {
+				Body struct {
+					BlobKZGCommitments []string `json:"blob_kzg_commitments"`
+				} `json:"body"`
+			} `json:"message"`
+		} `json:"data"`
+	}
+	err := getJSON(ctx, fmt.Sprintf("%s/eth/v1/beacon/blinded_blocks/%d", b.url, slot), &block)
+	if errors.Is(err, errEndpointNotSupported) {
+		slog.With("metric_name", b.Name, "slot", slot).Debug("no block in slot")
+		return
+	}
+	if err != nil {
+		logger.WriteError(metric.ConsensusGroup, b.Name, errors.Join(err, errors.New("failed fetching blinded block")))
+		return
+	}
+	expected := len(block.Data.Message.Body.BlobKZGCommitments)
+	if expected == 0 {
+		return
+	}
+
+	var sidecars struct {
+		Data []struct {
+			Index string `json:"index"`
+		} `json:"data"`
+	}
+	start := clock.Now()
+	err = getJSON(ctx, fmt.Sprintf("%s/eth/v1/beacon/blob_sidecars/%d", b.url, slot), &sidecars)
+	duration := clock.Since(start)
+	// Without sidecars for a block committing to blobs every blob of the block is missing
+	if err != nil && !errors.Is(err, errEndpointNotSupported) {
+		logger.WriteError(metric.ConsensusGroup, b.Name, errors.Join(err, errors.New("failed fetching blob sidecars")))
+		return
+	}
+
+	b.record(slot, expected, len(sidecars.Data), duration)
+}
+
+func (b *BlobMetric) record(slot phase0.Slot, expected, served int, duration time.Duration) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	missing := max(expected-served, 0)
+	b.blocks++
+	b.blobs += expected
+	b.missing += missing
+	if missing == 0 {
+		b.durations = append(b.durations, duration)
+	}
+
+	values := map[string]float64{
+		BlobsMeasurement:        float64(expected),
+		MissingBlobsMeasurement: float64(b.missing),
+	}
+	if len(b.durations) != 0 {
+		percentiles := metric.CalculatePercentiles(append([]time.Duration(nil), b.durations...), 50, 90)
+		values[BlobRetrievalP50Measurement] = float64(percentiles[50]) / float64(time.Millisecond)
+		values[BlobRetrievalP90Measurement] = float64(percentiles[90]) / float64(time.Millisecond)
+	}
+	b.AddDataPoint(values)
+
+	logger.WriteMetric(metric.ConsensusGroup, b.Name, map[string]any{
+		"Slot":                  slot,
+		BlobsMeasurement:        expected,
+		"ServedBlobs":           served,
+		"BlobRetrieval":         duration,
+		MissingBlobsMeasurement: b.missing,
+	})
+}
+
+// Distribution returns the retrieval durations of the sidecars measured so far
+func (b *BlobMetric) Distribution() []time.Duration {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return append([]time.Duration(nil), b.durations...)
+}
+
+func (b *BlobMetric) AggregateResults() string {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	var p50, p90 time.Duration
+	if latest, ok := b.Latest(); ok {
+		p50 = time.Duration(latest.Values[BlobRetrievalP50Measurement] * float64(time.Millisecond))
+		p90 = time.Duration(latest.Values[BlobRetrievalP90Measurement] * float64(time.Millisecond))
+	}
+
+	return fmt.Sprintf("blocks_with_blobs=%d, blobs=%d, missing_blobs=%d \n retrieval p50=%s, p90=%s",
+		b.blocks, b.blobs, b.missing, format.Duration(p50), format.Duration(p90))
+}
@@ -0,0 +1,231 @@
+package consensus
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/clock"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/format"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/httpclient"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/logger"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/metric"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/network"
+)
+
+const (
+	LocalValueMeasurement = "LocalValueETH"
+	RelayBidMeasurement   = "RelayBidETH"
+	// BuilderAdvantageMeasurement is the best relay bid minus the value of the locally built payload
+	BuilderAdvantageMeasurement = "BuilderAdvantageETH"
+	BuilderWinsMeasurement      = "BuilderWins"
+)
+
+// weiPerETH converts the payload values and bids, which are given in wei
+var weiPerETH = big.NewFloat(1e18)
+
+type (
+	// BuilderComparisonMetric compares the value of a payload built by the local execution client with the best bid
+	// the relays received for the same slot, the data behind the choice between local building and MEV-Boost. The
+	// local payload is produced at the start of the slot without a builder (builder boost factor 0) and never signed
+	// or published, the bids are read from the data API of the relays once the slot passed.
+	BuilderComparisonMetric struct {
+		metric.Base[float64]
+		url         string
+		relays      []string
+		spec        network.Spec
+		interval    time.Duration
+		comparisons []builderComparison
+		mutex       sync.Mutex
+	}
+
+	builderComparison struct {
+		local, relay float64
+	}
+)
+
+func NewBuilderComparisonMetric(url, name string, relays []string, spec network.Spec, interval time.Duration, healthCondition []metric.HealthCondition[float64]) *BuilderComparisonMetric {
+	return &BuilderComparisonMetric{
+		Base: metric.Base[float64]{
+			HealthConditions: healthCondition,
+			Name:             name,
+		},
+		url:      url,
+		relays:   relays,
+		spec:     spec,
+		interval: interval,
+	}
+}
+
+func (b *BuilderComparisonMetric) Measure(ctx context.Context) {
+	slotsPerInterval := max(phase0.Slot(b.interval/b.spec.SlotDuration), 1)
+	slot := currentSlot(b.spec)
+	for {
+		slot += slotsPerInterval
+		slotStart := time.After(clock.Until(slotTime(b.spec, slot).Add(productionOffset)))
+		select {
+		case <-slotStart:
+			slot := slot
+			metric.Go(ctx, b.Name, func() { b.measure(ctx, slot) })
+		case <-ctx.Done():
+			slog.With("metric_name", b.Name).Debug("metric was stopped")
+			return
+		}
+	}
+}
+
+func (b *BuilderComparisonMetric) measure(ctx context.Context, slot phase0.Slot) {
+	localCtx, cancel := context.WithTimeout(ctx, b.spec.AttestationDeadline())
+	local, err := b.localValue(localCtx, slot)
+	cancel()
+	if err != nil {
+		logger.WriteError(metric.ValidatorGroup, b.Name, errors.Join(err, fmt.Errorf("failed producing local block for slot %d", slot)))
+		return
+	}
+
+	// The relays stop receiving bids for the slot once it is proposed
+	select {
+	case <-time.After(clock.Until(slotTime(b.spec, slot+1))):
+	case <-ctx.Done():
+		return
+	}
+	relayCtx, cancel := b.MeasurementContext(ctx, 10*time.Second)
+	defer cancel()
+	relay, err := b.bestBid(relayCtx, slot)
+	if err != nil {
+		logger.WriteError(metric.ValidatorGroup, b.Name, errors.Join(err, fmt.Errorf("failed fetching relay bids for slot %d", slot)))
+		return
+	}
+
+	b.record(slot, local, relay)
+}
+
+// localValue produces a block with a locally built payload and returns the value of the payload in ETH
+func (b *BuilderComparisonMetric) localValue(ctx context.Context, slot phase0.Slot) (float64, error) {
+	url := fmt.Sprintf("%s/eth/v3/validator/blocks/%d?randao_reveal=%s&skip_randao_verification&builder_boost_factor=0", b.url, slot, infinityRandaoReveal)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	res, err := httpclient.Default.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return 0, responseError(res)
+	}
+
+	value := res.Header.Get("Eth-Execution-Payload-Value")
+	if value == "" {
+		var block struct {
+			ExecutionPayloadValue string `json:"execution_payload_value"`
+		}
+		if err := json.NewDecoder(res.Body).Decode(&block); err != nil {
+			return 0, err
+		}
+		value = block.ExecutionPayloadValue
+	}
+	return weiToETH(value)
+}
+
+// bestBid returns the highest bid in ETH any of the relays received for the slot, relays failing are skipped
+func (b *BuilderComparisonMetric) bestBid(ctx context.Context, slot phase0.Slot) (float64, error) {
+	var (
+		best     float64
+		errs     []error
+		answered bool
+	)
+	for _, relay := range b.relays {
+		var bids []struct {
+			Value string `json:"value"`
+		}
+		if err := getJSON(ctx, fmt.Sprintf("%s/relay/v1/data/bidtraces/builder_blocks_received?slot=%d", relay, slot), &bids); err != nil {
+			errs = append(errs, errors.Join(err, fmt.Errorf("relay '%s' failed", relay)))
+			continue
+		}
+		answered = true
+		for _, bid := range bids {
+			value, err := weiToETH(bid.Value)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			best = max(best, value)
+		}
+	}
+	if !answered {
+		return 0, errors.Join(errs...)
+	}
+	return best, nil
+}
+
+func (b *BuilderComparisonMetric) record(slot phase0.Slot, local, relay float64) {
+	b.mutex.Lock()
+	b.comparisons = append(b.comparisons, builderComparison{local: local, relay: relay})
+	b.mutex.Unlock()
+
+	wins := 0.0
+	if relay > local {
+		wins = 1
+	}
+	b.AddDataPoint(map[string]float64{
+		LocalValueMeasurement:       local,
+		RelayBidMeasurement:         relay,
+		BuilderAdvantageMeasurement: relay - local,
+		BuilderWinsMeasurement:      wins,
+	})
+
+	logger.WriteMetric(metric.ValidatorGroup, b.Name, map[string]any{
+		"Slot":                      slot,
+		LocalValueMeasurement:       local,
+		RelayBidMeasurement:         relay,
+		BuilderAdvantageMeasurement: relay - local,
+	})
+}
+
+func (b *BuilderComparisonMetric) AggregateResults() string {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if len(b.comparisons) == 0 {
+		return "no slots compared"
+	}
+	var locals, relays, advantages []float64
+	wins := 0
+	for _, c := range b.comparisons {
+		locals = append(locals, c.local)
+		relays = append(relays, c.relay)
+		advantages = append(advantages, c.relay-c.local)
+		if c.relay > c.local {
+			wins++
+		}
+	}
+	local := metric.CalculatePercentiles(locals, 50)
+	relay := metric.CalculatePercentiles(relays, 50)
+	advantage := metric.CalculatePercentiles(advantages, 10, 50, 90)
+
+	return fmt.Sprintf("slots=%d, builder wins=%d (%s%%) \n local p50=%s ETH, relay p50=%s ETH \n advantage p10=%s, p50=%s, p90=%s ETH",
+		len(b.comparisons), wins, format.Number(float64(wins)/float64(len(b.comparisons))*100, 0),
+		format.Number(local[50], 4), format.Number(relay[50], 4),
+		format.Number(advantage[10], 4), format.Number(advantage[50], 4), format.Number(advantage[90], 4))
+}
+
+func weiToETH(wei string) (float64, error) {
+	value, ok := new(big.Float).SetString(wei)
+	if !ok {
+		return 0, fmt.Errorf("value '%s' was not a number of wei", wei)
+	}
+	eth, _ := new(big.Float).Quo(value, weiPerETH).Float64()
+	return eth, nil
+}
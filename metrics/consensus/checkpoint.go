@@ -0,0 +1,112 @@
+package consensus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+type (
+	// Checkpoint is a finalized checkpoint of the beacon chain
+	Checkpoint struct {
+		Epoch flexibleUint `json:"epoch"`
+		Root  string       `json:"root"`
+	}
+
+	// CheckpointVerification is the outcome of comparing the chain of the node with the one of a checkpoint provider
+	CheckpointVerification struct {
+		Provider string     `json:"provider"`
+		Node     Checkpoint `json:"node"`
+		// Checked is the finalized checkpoint looked up on the other side, the one of the side which finalized less
+		Checked Checkpoint `json:"checked"`
+		Match   bool       `json:"match"`
+		Detail  string     `json:"detail,omitempty"`
+	}
+)
+
+// VerifyCheckpoint compares the genesis and finalized checkpoint of the node with the ones of a checkpoint provider,
+// e.g. a checkpoint sync endpoint. Node and provider rarely finalized the same epoch at the same time, so the
+// checkpoint of the one behind is looked up on the other, a finalized block unknown to it means the chains differ.
+func VerifyCheckpoint(ctx context.Context, url, provider string) (CheckpointVerification, error) {
+	verification := CheckpointVerification{Provider: provider}
+
+	nodeGenesis, err := genesisRoot(ctx, url)
+	if err != nil {
+		return verification, errors.Join(err, errors.New("failed fetching genesis of the node"))
+	}
+	providerGenesis, err := genesisRoot(ctx, provider)
+	if err != nil {
+		return verification, errors.Join(err, errors.New("failed fetching genesis of the provider"))
+	}
+	if !strings.EqualFold(nodeGenesis, providerGenesis) {
+		verification.Detail = fmt.Sprintf("genesis validators root is '%s' instead of '%s'", nodeGenesis, providerGenesis)
+		return verification, nil
+	}
+
+	nodeCheckpoint, err := finalizedCheckpoint(ctx, url)
+	if err != nil {
+		return verification, errors.Join(err, errors.New("failed fetching finalized checkpoint of the node"))
+	}
+	verification.Node = nodeCheckpoint
+	providerCheckpoint, err := finalizedCheckpoint(ctx, provider)
+	if err != nil {
+		return verification, errors.Join(err, errors.New("failed fetching finalized checkpoint of the provider"))
+	}
+
+	checkpoint, other := nodeCheckpoint, provider
+	if providerCheckpoint.Epoch < nodeCheckpoint.Epoch {
+		checkpoint, other = providerCheckpoint, url
+	}
+	verification.Checked = checkpoint
+
+	var resp struct {
+		Data struct {
+			Root string `json:"root"`
+		} `json:"data"`
+	}
+	err = getJSON(ctx, fmt.Sprintf("%s/eth/v1/beacon/blocks/%s/root", other, checkpoint.Root), &resp)
+	if errors.Is(err, errEndpointNotSupported) {
+		verification.Detail = fmt.Sprintf("finalized block %s of epoch %d is unknown to the %s", checkpoint.Root, checkpoint.Epoch, side(other, provider))
+		return verification, nil
+	}
+	if err != nil {
+		return verification, errors.Join(err, errors.New("failed looking up finalized checkpoint"))
+	}
+	verification.Match = strings.EqualFold(resp.Data.Root, checkpoint.Root)
+	if !verification.Match {
+		verification.Detail = fmt.Sprintf("finalized block of epoch %d is %s instead of %s", checkpoint.Epoch, resp.Data.Root, checkpoint.Root)
+	}
+	return verification, nil
+}
+
+func side(url, provider string) string {
+	if url == provider {
+		return "provider"
+	}
+	return "node"
+}
+
+func genesisRoot(ctx context.Context, url string) (string, error) {
+	var resp struct {
+		Data struct {
+			GenesisValidatorsRoot string `json:"genesis_validators_root"`
+		} `json:"data"`
+	}
+	if err := getJSON(ctx, fmt.Sprintf("%s/eth/v1/beacon/genesis", url), &resp); err != nil {
+		return "", err
+	}
+	return resp.Data.GenesisValidatorsRoot, nil
+}
+
+func finalizedCheckpoint(ctx context.Context, url string) (Checkpoint, error) {
+	var resp struct {
+		Data struct {
+			Finalized Checkpoint `json:"finalized"`
+		} `json:"data"`
+	}
+	if err := getJSON(ctx, fmt.Sprintf("%s/eth/v1/beacon/states/head/finality_checkpoints", url), &resp); err != nil {
+		return Checkpoint{}, err
+	}
+	return resp.Data.Finalized, nil
+}
@@ -7,6 +7,8 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/clock"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/httpclient"
 	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/logger"
 	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/metric"
 )
@@ -19,16 +21,16 @@ const (
 )
 
 type ClientMetric struct {
-	metric.Base[string]
+	metric.ValueBase
 	url             string
 	measureInterval time.Duration
 }
 
-func NewClientMetric(url, name string, healthCondition []metric.HealthCondition[string], measureInterval time.Duration) *ClientMetric {
+func NewClientMetric(url, name string, healthCondition []metric.ValueCondition, measureInterval time.Duration) *ClientMetric {
 	return &ClientMetric{
 		url:             url,
 		measureInterval: measureInterval,
-		Base: metric.Base[string]{
+		ValueBase: metric.ValueBase{
 			HealthConditions: healthCondition,
 			Name:             name,
 		},
@@ -36,40 +38,36 @@ func NewClientMetric(url, name string, healthCondition []metric.HealthCondition[
 }
 
 func (c *ClientMetric) Measure(ctx context.Context) {
-	ticker := time.NewTicker(c.measureInterval)
-	defer ticker.Stop()
-
-	for {
-		select {
-		case <-ticker.C:
-			// Fetch version and health data
-			c.measureNodeHealth(ctx)
-			c.measureNodeVersion(ctx)
-
-			// Measure additional metrics like sync status and latency
-			c.measureSyncStatus(ctx)
-			c.measureLatency(ctx)
-		case <-ctx.Done():
-			logger.WriteError(metric.ConsensusGroup, c.Name, fmt.Errorf("client metric measurement stopped"))
-			return
-		}
-	}
+	c.Runner(c.measureInterval).Run(ctx, c.measure)
+}
+
+func (c *ClientMetric) measure(ctx context.Context) {
+	ctx, cancel := c.MeasurementContext(ctx, 5*time.Second)
+	defer cancel()
+
+	// Fetch version and health data
+	c.measureNodeHealth(ctx)
+	c.measureNodeVersion(ctx)
+
+	// Measure additional metrics like sync status and latency
+	c.measureSyncStatus(ctx)
+	c.measureLatency(ctx)
 }
 
 func (c *ClientMetric) measureNodeHealth(ctx context.Context) {
 	// Check the health of the node (replace with actual health check endpoint if available)
-	res, err := http.Get(fmt.Sprintf("%s/eth/v1/node/health", c.url))
+	res, err := c.get(ctx, "/eth/v1/node/health")
 	if err != nil || res.StatusCode != http.StatusOK {
-		c.AddDataPoint(map[string]string{
-			NodeHealthMeasurement: "Unhealthy",
+		c.AddDataPoint(map[string]metric.Value{
+			NodeHealthMeasurement: metric.Bool(false),
 		})
 		logger.WriteError(metric.ConsensusGroup, c.Name, err)
 		return
 	}
 	defer res.Body.Close()
 
-	c.AddDataPoint(map[string]string{
-		NodeHealthMeasurement: "Healthy",
+	c.AddDataPoint(map[string]metric.Value{
+		NodeHealthMeasurement: metric.Bool(true),
 	})
 	logger.WriteMetric(metric.ConsensusGroup, c.Name, map[string]any{
 		NodeHealthMeasurement: "Healthy",
@@ -82,10 +80,10 @@ func (c *ClientMetric) measureNodeVersion(ctx context.Context) {
 			Version string `json:"version"`
 		} `json:"data"`
 	}
-	res, err := http.Get(fmt.Sprintf("%s/eth/v1/node/version", c.url))
+	res, err := c.get(ctx, "/eth/v1/node/version")
 	if err != nil {
-		c.AddDataPoint(map[string]string{
-			VersionMeasurement: "",
+		c.AddDataPoint(map[string]metric.Value{
+			VersionMeasurement: metric.String(""),
 		})
 		logger.WriteError(metric.ConsensusGroup, c.Name, err)
 		return
@@ -93,8 +91,8 @@ func (c *ClientMetric) measureNodeVersion(ctx context.Context) {
 	defer res.Body.Close()
 
 	if res.StatusCode != http.StatusOK {
-		c.AddDataPoint(map[string]string{
-			VersionMeasurement: "",
+		c.AddDataPoint(map[string]metric.Value{
+			VersionMeasurement: metric.String(""),
 		})
 		var errorResponse any
 		_ = json.NewDecoder(res.Body).Decode(&errorResponse)
@@ -107,15 +105,15 @@ func (c *ClientMetric) measureNodeVersion(ctx context.Context) {
 	}
 
 	if err = json.NewDecoder(res.Body).Decode(&resp); err != nil {
-		c.AddDataPoint(map[string]string{
-			VersionMeasurement: "",
+		c.AddDataPoint(map[string]metric.Value{
+			VersionMeasurement: metric.String(""),
 		})
 		logger.WriteError(metric.ConsensusGroup, c.Name, err)
 		return
 	}
 
-	c.AddDataPoint(map[string]string{
-		VersionMeasurement: resp.Data.Version,
+	c.AddDataPoint(map[string]metric.Value{
+		VersionMeasurement: metric.String(resp.Data.Version),
 	})
 
 	logger.WriteMetric(metric.ConsensusGroup, c.Name, map[string]any{VersionMeasurement: resp.Data.Version})
@@ -123,18 +121,18 @@ func (c *ClientMetric) measureNodeVersion(ctx context.Context) {
 
 func (c *ClientMetric) measureSyncStatus(ctx context.Context) {
 	// Measure sync status (replace with actual sync status endpoint if available)
-	res, err := http.Get(fmt.Sprintf("%s/eth/v1/node/syncing", c.url))
+	res, err := c.get(ctx, "/eth/v1/node/syncing")
 	if err != nil || res.StatusCode != http.StatusOK {
-		c.AddDataPoint(map[string]string{
-			SyncStatusMeasurement: "Not Synced",
+		c.AddDataPoint(map[string]metric.Value{
+			SyncStatusMeasurement: metric.Bool(false),
 		})
 		logger.WriteError(metric.ConsensusGroup, c.Name, err)
 		return
 	}
 	defer res.Body.Close()
 
-	c.AddDataPoint(map[string]string{
-		SyncStatusMeasurement: "Synced",
+	c.AddDataPoint(map[string]metric.Value{
+		SyncStatusMeasurement: metric.Bool(true),
 	})
 	logger.WriteMetric(metric.ConsensusGroup, c.Name, map[string]any{
 		SyncStatusMeasurement: "Synced",
@@ -142,45 +140,55 @@ func (c *ClientMetric) measureSyncStatus(ctx context.Context) {
 }
 
 func (c *ClientMetric) measureLatency(ctx context.Context) {
-	startTime := time.Now()
-	res, err := http.Get(fmt.Sprintf("%s/eth/v1/node/health", c.url)) // Using health endpoint for latency check
+	startTime := clock.Now()
+	res, err := c.get(ctx, "/eth/v1/node/health") // Using health endpoint for latency check
 	if err != nil {
-		c.AddDataPoint(map[string]string{
-			LatencyMeasurement: "Error",
-		})
 		logger.WriteError(metric.ConsensusGroup, c.Name, err)
 		return
 	}
 	defer res.Body.Close()
 
-	latency := time.Since(startTime).Milliseconds()
-	c.AddDataPoint(map[string]string{
-		LatencyMeasurement: fmt.Sprintf("%dms", latency),
+	latency := clock.Since(startTime)
+	c.AddDataPoint(map[string]metric.Value{
+		LatencyMeasurement: metric.Duration(latency),
 	})
 	logger.WriteMetric(metric.ConsensusGroup, c.Name, map[string]any{
-		LatencyMeasurement: fmt.Sprintf("%dms", latency),
+		LatencyMeasurement: latency,
 	})
 }
 
+// get requests the path of the beacon API, bounded by the context of the measurement
+func (c *ClientMetric) get(ctx context.Context, path string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.url+path, nil)
+	if err != nil {
+		return nil, err
+	}
+	return httpclient.Default.Do(req)
+}
+
 func (c *ClientMetric) AggregateResults() string {
-	var version, health, syncStatus, latency string
-
-	if len(c.DataPoints) != 0 {
-		for _, point := range c.DataPoints {
-			if versionValue, ok := point.Values[VersionMeasurement]; ok {
-				version = versionValue
-			}
-			if healthValue, ok := point.Values[NodeHealthMeasurement]; ok {
-				health = healthValue
-			}
-			if syncValue, ok := point.Values[SyncStatusMeasurement]; ok {
-				syncStatus = syncValue
-			}
-			if latencyValue, ok := point.Values[LatencyMeasurement]; ok {
-				latency = latencyValue
-			}
+	version, health, syncStatus, latency := "", "", "", ""
+
+	if value, ok := c.Last(VersionMeasurement); ok {
+		version = value.String()
+	}
+	if value, ok := c.Last(NodeHealthMeasurement); ok {
+		health = "Unhealthy"
+		if value.Bool() {
+			health = "Healthy"
+		}
+	}
+	if value, ok := c.Last(SyncStatusMeasurement); ok {
+		syncStatus = "Not Synced"
+		if value.Bool() {
+			syncStatus = "Synced"
 		}
 	}
+	if value, ok := c.Last(LatencyMeasurement); ok {
+		latency = value.String()
+	} else if c.Samples() != 0 {
+		latency = "Error"
+	}
 
 	return fmt.Sprintf(
 		"Version: %s, Node Health: %s, Sync Status: %s, Latency: %s",
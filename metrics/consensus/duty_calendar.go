@@ -0,0 +1,322 @@
+package consensus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/clock"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/format"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/logger"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/metric"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/network"
+)
+
+const (
+	ProposalDutyMeasurement   = "ProposalDuty"
+	MissedProposalMeasurement = "MissedProposal"
+)
+
+type (
+	// DutyCalendarMetric fetches the proposer and sync committee duties of the configured validators every epoch.
+	// Proposals are checked against the block of their slot, so missed ones are detected precisely, and their windows
+	// tell other metrics when a resource spike is expected.
+	DutyCalendarMetric struct {
+		metric.Base[float64]
+		url       string
+		spec      network.Spec
+		indices   []uint64
+		epoch     uint64
+		period    uint64
+		proposals map[phase0.Slot]*proposalDuty
+		// syncCommittees maps the sync committee periods to their members among the configured validators
+		syncCommittees map[uint64][]uint64
+		mutex          sync.Mutex
+	}
+
+	proposalDuty struct {
+		validator uint64
+		checked   bool
+		missed    bool
+	}
+)
+
+func NewDutyCalendarMetric(url, name string, spec network.Spec, indices []uint64, healthCondition []metric.HealthCondition[float64]) *DutyCalendarMetric {
+	return &DutyCalendarMetric{
+		Base: metric.Base[float64]{
+			HealthConditions: healthCondition,
+			Name:             name,
+		},
+		url:            url,
+		spec:           spec,
+		indices:        indices,
+		epoch:          ^uint64(0),
+		period:         ^uint64(0),
+		proposals:      make(map[phase0.Slot]*proposalDuty),
+		syncCommittees: make(map[uint64][]uint64),
+	}
+}
+
+func (d *DutyCalendarMetric) Measure(ctx context.Context) {
+	slot := currentSlot(d.spec)
+	d.fetchDuties(ctx, slot)
+	for {
+		slot++
+		// The block of the previous slot is checked once the one of this slot is due
+		checkTime := time.After(clock.Until(slotTime(d.spec, slot).Add(d.spec.AttestationDeadline())))
+		select {
+		case <-checkTime:
+			d.fetchDuties(ctx, slot)
+			d.checkProposals(ctx, slot)
+		case <-ctx.Done():
+			slog.With("metric_name", d.Name).Debug("metric was stopped")
+			return
+		}
+	}
+}
+
+// fetchDuties fetches the proposer duties once per epoch and the sync committee duties once per period
+func (d *DutyCalendarMetric) fetchDuties(ctx context.Context, slot phase0.Slot) {
+	ctx, cancel := d.MeasurementContext(ctx, 5*time.Second)
+	defer cancel()
+
+	epoch := d.spec.Epoch(uint64(slot))
+	if epoch != d.epoch {
+		if err := d.fetchProposerDuties(ctx, epoch); err != nil {
+			logger.WriteError(metric.ValidatorGroup, d.Name, errors.Join(err, fmt.Errorf("failed fetching proposer duties of epoch %d", epoch)))
+		} else {
+			d.epoch = epoch
+		}
+	}
+
+	period := d.spec.SyncCommitteePeriod(epoch)
+	if period != d.period {
+		if err := d.fetchSyncDuties(ctx, epoch, period); err != nil {
+			logger.WriteError(metric.ValidatorGroup, d.Name, errors.Join(err, fmt.Errorf("failed fetching sync committee duties of epoch %d", epoch)))
+		} else {
+			d.period = period
+		}
+	}
+}
+
+func (d *DutyCalendarMetric) fetchProposerDuties(ctx context.Context, epoch uint64) error {
+	var resp struct {
+		Data []struct {
+			ValidatorIndex string `json:"validator_index"`
+			Slot           string `json:"slot"`
+		} `json:"data"`
+	}
+	if err := getJSON(ctx, fmt.Sprintf("%s/eth/v1/validator/duties/proposer/%d", d.url, epoch), &resp); err != nil {
+		return err
+	}
+
+	configured := make(map[uint64]struct{}, len(d.indices))
+	for _, index := range d.indices {
+		configured[index] = struct{}{}
+	}
+
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	for _, duty := range resp.Data {
+		index, err := strconv.ParseUint(duty.ValidatorIndex, 10, 64)
+		if err != nil {
+			return errors.Join(err, errors.New("failed parsing validator index"))
+		}
+		if _, ok := configured[index]; !ok {
+			continue
+		}
+		slot, err := strconv.ParseUint(duty.Slot, 10, 64)
+		if err != nil {
+			return errors.Join(err, errors.New("failed parsing slot"))
+		}
+		if _, ok := d.proposals[phase0.Slot(slot)]; !ok {
+			d.proposals[phase0.Slot(slot)] = &proposalDuty{validator: index}
+			slog.With("metric_name", d.Name, "validator", index, "slot", slot).Info("proposal scheduled")
+		}
+	}
+	return nil
+}
+
+func (d *DutyCalendarMetric) fetchSyncDuties(ctx context.Context, epoch, period uint64) error {
+	indices := make([]string, 0, len(d.indices))
+	for _, index := range d.indices {
+		indices = append(indices, strconv.FormatUint(index, 10))
+	}
+
+	var resp struct {
+		Data []struct {
+			ValidatorIndex string `json:"validator_index"`
+		} `json:"data"`
+	}
+	if err := postJSON(ctx, fmt.Sprintf("%s/eth/v1/validator/duties/sync/%d", d.url, epoch), indices, &resp); err != nil {
+		return err
+	}
+
+	members := make([]uint64, 0, len(resp.Data))
+	for _, duty := range resp.Data {
+		index, err := strconv.ParseUint(duty.ValidatorIndex, 10, 64)
+		if err != nil {
+			return errors.Join(err, errors.New("failed parsing validator index"))
+		}
+		members = append(members, index)
+	}
+
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	d.syncCommittees[period] = members
+	return nil
+}
+
+// checkProposals checks the past proposals against the block of their slot, a missing block or one of another
+// proposer, e.g. after a reorg, is a missed proposal
+func (d *DutyCalendarMetric) checkProposals(ctx context.Context, slot phase0.Slot) {
+	d.mutex.Lock()
+	var due []phase0.Slot
+	for dutySlot, duty := range d.proposals {
+		if dutySlot < slot && !duty.checked {
+			due = append(due, dutySlot)
+		}
+	}
+	d.mutex.Unlock()
+	sort.Slice(due, func(i, j int) bool { return due[i] < due[j] })
+
+	for _, dutySlot := range due {
+		ctx, cancel := d.MeasurementContext(ctx, 5*time.Second)
+		proposer, err := d.blockProposer(ctx, dutySlot)
+		cancel()
+		if err != nil {
+			logger.WriteError(metric.ValidatorGroup, d.Name, errors.Join(err, fmt.Errorf("failed checking proposal of slot %d", dutySlot)))
+			continue
+		}
+
+		d.mutex.Lock()
+		duty := d.proposals[dutySlot]
+		duty.checked = true
+		duty.missed = proposer == nil || *proposer != duty.validator
+		missed := duty.missed
+		validator := duty.validator
+		d.mutex.Unlock()
+
+		d.AddDataPoint(map[string]float64{
+			ProposalDutyMeasurement:   1,
+			MissedProposalMeasurement: boolToFloat(missed),
+		})
+		logger.WriteMetric(metric.ValidatorGroup, d.Name, map[string]any{
+			"Slot":                    dutySlot,
+			"Validator":               validator,
+			MissedProposalMeasurement: missed,
+		})
+	}
+}
+
+// blockProposer returns the proposer of the block of the slot, nil when the slot has no block
+func (d *DutyCalendarMetric) blockProposer(ctx context.Context, slot phase0.Slot) (*uint64, error) {
+	var resp struct {
+		Data struct {
+			Header struct {
+				Message struct {
+					ProposerIndex string `json:"proposer_index"`
+				} `json:"message"`
+			} `json:"header"`
+		} `json:"data"`
+	}
+	err := getJSON(ctx, fmt.Sprintf("%s/eth/v1/beacon/headers/%d", d.url, slot), &resp)
+	if errors.Is(err, errEndpointNotSupported) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	proposer, err := strconv.ParseUint(resp.Data.Header.Message.ProposerIndex, 10, 64)
+	if err != nil {
+		return nil, errors.Join(err, errors.New("failed parsing proposer index"))
+	}
+	return &proposer, nil
+}
+
+// ProposalWindows returns the windows around the proposals of the configured validators, the past and the scheduled
+// ones, in which the node builds and propagates a block
+func (d *DutyCalendarMetric) ProposalWindows() []metric.Window {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	windows := make([]metric.Window, 0, len(d.proposals))
+	for slot, duty := range d.proposals {
+		// The window is widened by a slot on both sides, the payload is prepared before the slot and the block
+		// propagated after it
+		start := slotTime(d.spec, slot)
+		windows = append(windows, metric.Window{
+			Start:  start.Add(-d.spec.SlotDuration),
+			End:    start.Add(d.spec.SlotDuration * 2),
+			Reason: fmt.Sprintf("proposal of validator %d in slot %d", duty.validator, slot),
+		})
+	}
+	return windows
+}
+
+func (d *DutyCalendarMetric) AggregateResults() string {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	slots := make([]phase0.Slot, 0, len(d.proposals))
+	for slot := range d.proposals {
+		slots = append(slots, slot)
+	}
+	sort.Slice(slots, func(i, j int) bool { return slots[i] < slots[j] })
+
+	var proposed, missed int
+	var lines []string
+	for _, slot := range slots {
+		duty := d.proposals[slot]
+		status := "scheduled"
+		switch {
+		case duty.checked && duty.missed:
+			status = "missed"
+			missed++
+		case duty.checked:
+			status = "proposed"
+			proposed++
+		}
+		lines = append(lines, fmt.Sprintf("proposal: validator=%d, slot=%d, at=%s, %s",
+			duty.validator, slot, format.Timestamp(slotTime(d.spec, slot)), status))
+	}
+
+	periods := make([]uint64, 0, len(d.syncCommittees))
+	for period := range d.syncCommittees {
+		periods = append(periods, period)
+	}
+	sort.Slice(periods, func(i, j int) bool { return periods[i] < periods[j] })
+	for _, period := range periods {
+		if len(d.syncCommittees[period]) == 0 {
+			continue
+		}
+		members := make([]string, 0, len(d.syncCommittees[period]))
+		for _, index := range d.syncCommittees[period] {
+			members = append(members, strconv.FormatUint(index, 10))
+		}
+		start := d.spec.SlotTime(d.spec.EpochStart(period * d.spec.EpochsPerSyncCommitteePeriod))
+		end := d.spec.SlotTime(d.spec.EpochStart((period + 1) * d.spec.EpochsPerSyncCommitteePeriod))
+		lines = append(lines, fmt.Sprintf("sync committee: validators=%s, from=%s, until=%s",
+			strings.Join(members, ","), format.Timestamp(start), format.Timestamp(end)))
+	}
+
+	summary := fmt.Sprintf("Proposals: %d, Proposed: %d, Missed: %d", len(slots), proposed, missed)
+	return strings.Join(append([]string{summary}, lines...), " \n ")
+}
+
+func boolToFloat(value bool) float64 {
+	if value {
+		return 1
+	}
+	return 0
+}
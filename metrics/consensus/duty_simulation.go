@@ -0,0 +1,331 @@
+package consensus
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/clock"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/httpclient"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/logger"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/metric"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/network"
+)
+
+const (
+	OnTimeMeasurement           = "OnTime"
+	LateMeasurement             = "Late"
+	FailedPipelineMeasurement   = "FailedPipeline"
+	PipelineDurationMeasurement = "PipelineMs"
+	OnTimeRateMeasurement       = "OnTimeRate"
+)
+
+var (
+	// emptySignature is the point at infinity, the signature of an attestation without attesters
+	emptySignature = "0xc0" + strings.Repeat("0", 190)
+	// singleAttestationForks are the forks whose attestations are submitted as single attestations through the v2
+	// endpoint, in the order of the schedule
+	singleAttestationForks = []string{"electra", "fulu"}
+	// rejectionReasons are the words of the rejections of the dry-run attestation for its signature or its attesters,
+	// other rejections e.g. of its format mean the dry-run isn't validated the way a real attestation is
+	rejectionReasons = []string{"signature", "aggregation", "bitfield", "attester"}
+)
+
+// DutySimulationMetric replays the attestation workflow of a validator client every slot:
+// it fetches attester duties, requests attestation data at 1/3 of the slot and submits an attestation with the empty
+// signature, which the beacon node rejects rather than publishing. The pipeline is on time when it completes before
+// aggregation starts at 2/3 of the slot, late until the slot is over and failed after that. A beacon node accepting
+// the attestation, or rejecting it for another reason than its signature or attesters, fails the pipeline.
+type DutySimulationMetric struct {
+	metric.Base[float64]
+	url     string
+	spec    network.Spec
+	indices []uint64
+	// forkEpochs are the epochs of the single attestation forks scheduled by the beacon node, read once
+	forkEpochs map[string]uint64
+	forkMutex  sync.Mutex
+}
+
+func NewDutySimulationMetric(url, name string, spec network.Spec, indices []uint64, healthCondition []metric.HealthCondition[float64]) *DutySimulationMetric {
+	if len(indices) == 0 {
+		// Duties of any validator exercise the same endpoints, the result itself is not used
+		indices = []uint64{0}
+	}
+	return &DutySimulationMetric{
+		url: url,
+		Base: metric.Base[float64]{
+			HealthConditions: healthCondition,
+			Name:             name,
+		},
+		spec:    spec,
+		indices: indices,
+	}
+}
+
+func (d *DutySimulationMetric) Measure(ctx context.Context) {
+	slot := currentSlot(d.spec)
+	for {
+		slot++
+		attestationTime := time.After(clock.Until(slotTime(d.spec, slot).Add(d.spec.AttestationDeadline())))
+		select {
+		case <-attestationTime:
+			slot := slot
+			metric.Go(ctx, d.Name, func() { d.simulate(ctx, slot) })
+		case <-ctx.Done():
+			slog.With("metric_name", d.Name).Debug("metric was stopped")
+			return
+		}
+	}
+}
+
+func (d *DutySimulationMetric) simulate(ctx context.Context, slot phase0.Slot) {
+	deadline := slotTime(d.spec, slot).Add(d.spec.AggregationDeadline())
+	// A pipeline missing the aggregation is late, it only fails once the slot is over
+	ctx, cancel := context.WithDeadline(ctx, slotTime(d.spec, slot+1))
+	defer cancel()
+
+	start := clock.Now()
+	if err := d.runPipeline(ctx, slot); err != nil {
+		d.writeMetric(d.withOnTimeRate(map[string]float64{
+			FailedPipelineMeasurement: 1,
+		}))
+		logger.WriteError(metric.ValidatorGroup, d.Name, err)
+		return
+	}
+	duration := clock.Since(start)
+
+	values := map[string]float64{
+		PipelineDurationMeasurement: float64(duration.Milliseconds()),
+	}
+	if clock.Now().Before(deadline) {
+		values[OnTimeMeasurement] = 1
+	} else {
+		values[LateMeasurement] = 1
+	}
+	d.writeMetric(d.withOnTimeRate(values))
+}
+
+func (d *DutySimulationMetric) runPipeline(ctx context.Context, slot phase0.Slot) error {
+	// Get duties
+	indices := make([]string, 0, len(d.indices))
+	for _, index := range d.indices {
+		indices = append(indices, strconv.FormatUint(index, 10))
+	}
+	body, err := json.Marshal(indices)
+	if err != nil {
+		return err
+	}
+	epoch := d.spec.Epoch(uint64(slot))
+	if err := d.request(ctx, http.MethodPost, fmt.Sprintf("%s/eth/v1/validator/duties/attester/%d", d.url, epoch), body, nil); err != nil {
+		return err
+	}
+
+	// Get attestation data
+	var attestationData struct {
+		Data json.RawMessage `json:"data"`
+	}
+	if err := d.request(ctx, http.MethodGet, fmt.Sprintf("%s/eth/v1/validator/attestation_data?slot=%d&committee_index=0", d.url, slot), nil, &attestationData); err != nil {
+		return err
+	}
+
+	// Dry-run: the attestation is submitted the way a validator client would, but with the empty signature, so the
+	// beacon node validates and rejects it rather than publishing it
+	fork, err := d.fork(ctx, epoch)
+	if err != nil {
+		return err
+	}
+	url := fmt.Sprintf("%s/eth/v1/beacon/pool/attestations", d.url)
+	attestation := map[string]any{
+		"aggregation_bits": "0x01",
+		"data":             attestationData.Data,
+		"signature":        emptySignature,
+	}
+	if fork != "" {
+		url = fmt.Sprintf("%s/eth/v2/beacon/pool/attestations", d.url)
+		attestation = map[string]any{
+			"committee_index": "0",
+			"attester_index":  strconv.FormatUint(d.indices[0], 10),
+			"data":            attestationData.Data,
+			"signature":       emptySignature,
+		}
+	}
+	body, err = json.Marshal([]map[string]any{attestation})
+	if err != nil {
+		return err
+	}
+	return d.submit(ctx, url, fork, body)
+}
+
+// fork returns the single attestation fork of the epoch, empty before Electra. The fork epochs are read from the spec
+// of the beacon node once.
+func (d *DutySimulationMetric) fork(ctx context.Context, epoch uint64) (string, error) {
+	d.forkMutex.Lock()
+	defer d.forkMutex.Unlock()
+
+	if d.forkEpochs == nil {
+		var specResp struct {
+			Data map[string]any `json:"data"`
+		}
+		if err := getJSON(ctx, fmt.Sprintf("%s/eth/v1/config/spec", d.url), &specResp); err != nil {
+			return "", errors.Join(err, errors.New("failed fetching the fork schedule of the spec"))
+		}
+		d.forkEpochs = make(map[string]uint64, len(singleAttestationForks))
+		for _, fork := range singleAttestationForks {
+			if forkEpoch, ok := specUint(specResp.Data, strings.ToUpper(fork)+"_FORK_EPOCH"); ok {
+				d.forkEpochs[fork] = forkEpoch
+			}
+		}
+	}
+
+	var current string
+	for _, fork := range singleAttestationForks {
+		if forkEpoch, ok := d.forkEpochs[fork]; ok && epoch >= forkEpoch {
+			current = fork
+		}
+	}
+	return current, nil
+}
+
+// submit posts the dry-run attestation, a rejection for its signature or attesters is the expected answer
+func (d *DutySimulationMetric) submit(ctx context.Context, url, fork string, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if fork != "" {
+		req.Header.Set("Eth-Consensus-Version", fork)
+	}
+
+	res, err := httpclient.Default.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	switch res.StatusCode {
+	case http.StatusOK:
+		return fmt.Errorf("beacon node accepted the dry-run attestation into its pool, it may publish it. URL: '%s'", url)
+	case http.StatusBadRequest:
+	default:
+		return fmt.Errorf("received unsuccessful status code. Code: '%s'. URL: '%s'", res.Status, url)
+	}
+
+	var rejection struct {
+		Message  string `json:"message"`
+		Failures []struct {
+			Message string `json:"message"`
+		} `json:"failures"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&rejection); err != nil {
+		return errors.Join(err, errors.New("failed decoding the rejection of the dry-run attestation"))
+	}
+	messages := []string{rejection.Message}
+	for _, failure := range rejection.Failures {
+		messages = append(messages, failure.Message)
+	}
+	if !expectedRejection(messages) {
+		return fmt.Errorf("beacon node rejected the dry-run attestation for another reason than its signature: '%s'", strings.Join(messages, "', '"))
+	}
+	return nil
+}
+
+// expectedRejection tells whether one of the messages rejects the attestation for its signature or its attesters
+func expectedRejection(messages []string) bool {
+	for _, message := range messages {
+		message = strings.ToLower(message)
+		for _, reason := range rejectionReasons {
+			if strings.Contains(message, reason) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (d *DutySimulationMetric) request(ctx context.Context, method, url string, body []byte, result any) error {
+	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	res, err := httpclient.Default.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("received unsuccessful status code. Code: '%s'. URL: '%s'", res.Status, url)
+	}
+	if result == nil {
+		return nil
+	}
+	return json.NewDecoder(res.Body).Decode(result)
+}
+
+// withOnTimeRate adds the on-time rate of the run including the pipeline of the slot to its values, so health conditions
+// apply to the rate while every slot stays a single data point
+func (d *DutySimulationMetric) withOnTimeRate(values map[string]float64) map[string]float64 {
+	onTime, _, _, total := onTimeCounts(append(d.Snapshot(), metric.DataPoint[float64]{Values: values}))
+	values[OnTimeRateMeasurement] = onTime / total * 100
+	return values
+}
+
+func (d *DutySimulationMetric) writeMetric(values map[string]float64) {
+	d.AddDataPoint(values)
+
+	logValues := make(map[string]any, len(values))
+	for name, value := range values {
+		logValues[name] = value
+	}
+	logger.WriteMetric(metric.ValidatorGroup, d.Name, logValues)
+}
+
+func (d *DutySimulationMetric) AggregateResults() string {
+	var durations []float64
+	dataPoints := d.Snapshot()
+	for _, point := range dataPoints {
+		if value, ok := point.Values[PipelineDurationMeasurement]; ok {
+			durations = append(durations, value)
+		}
+	}
+
+	var rate float64
+	onTime, late, failed, total := onTimeCounts(dataPoints)
+	if total != 0 {
+		rate = onTime / total * 100
+	}
+	percentiles := metric.CalculatePercentiles(durations, 50, 90)
+
+	verdict := "ready"
+	if rate < 95 {
+		verdict = "not ready"
+	}
+
+	return fmt.Sprintf("on_time=%.0f, late=%.0f, failed=%.0f, on_time_rate=%.2f %% \n pipeline_P50=%.0fms, pipeline_P90=%.0fms, verdict=%s",
+		onTime, late, failed, rate, percentiles[50], percentiles[90], verdict)
+}
+
+// onTimeCounts counts the pipelines by their outcome, the total includes the late and failed ones
+func onTimeCounts(dataPoints []metric.DataPoint[float64]) (onTime, late, failed, total float64) {
+	for _, point := range dataPoints {
+		onTime += point.Values[OnTimeMeasurement]
+		late += point.Values[LateMeasurement]
+		failed += point.Values[FailedPipelineMeasurement]
+	}
+	return onTime, late, failed, onTime + late + failed
+}
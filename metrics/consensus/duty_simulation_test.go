@@ -0,0 +1,56 @@
+package consensus
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/network"
+	"github.com/Harikakasimahanthi/benchmark-test/testutil/mocknode"
+)
+
+func TestGivenAnswerOfBeaconNodeWhenSubmitThenOnlyExpectedRejectionSucceeds(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		body    string
+		wantErr bool
+	}{
+		{name: "accepted into the pool", status: http.StatusOK, body: `{}`, wantErr: true},
+		{name: "rejected signature", status: http.StatusBadRequest, body: `{"code":400,"message":"Invalid signature"}`},
+		{name: "rejected attesters of a failure", status: http.StatusBadRequest, body: `{"code":400,"message":"error processing attestations","failures":[{"index":0,"message":"AttesterNotInCommittee"}]}`},
+		{name: "rejected fork", status: http.StatusBadRequest, body: `{"code":400,"message":"unsupported fork for the v1 endpoint"}`, wantErr: true},
+		{name: "unavailable", status: http.StatusServiceUnavailable, body: `{}`, wantErr: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(test.status)
+				w.Write([]byte(test.body))
+			}))
+			defer server.Close()
+			d := NewDutySimulationMetric(server.URL, "Duty Simulation", network.DefaultSpec(network.Mainnet), nil, nil)
+
+			err := d.submit(context.Background(), server.URL+"/eth/v2/beacon/pool/attestations", "electra", []byte(`[]`))
+
+			assert.Equal(t, test.wantErr, err != nil, err)
+		})
+	}
+}
+
+func TestGivenElectraNodeWhenPipelineRunThenSingleAttestationSubmitted(t *testing.T) {
+	node := mocknode.New(network.Mainnet).Start()
+	defer node.Close()
+	spec := network.DefaultSpec(network.Mainnet)
+	d := NewDutySimulationMetric(node.ConsensusURL(), "Duty Simulation", spec, []uint64{7}, nil)
+
+	fork, err := d.fork(context.Background(), 0)
+	require.NoError(t, err)
+	assert.Equal(t, "electra", fork)
+
+	assert.NoError(t, d.runPipeline(context.Background(), currentSlot(spec)))
+}
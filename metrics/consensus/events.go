@@ -0,0 +1,140 @@
+package consensus
+
+import (
+	"bufio"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/httpclient"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/logger"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/metric"
+)
+
+const (
+	// eventsResubscribeDelay is how long a dropped event stream waits before subscribing again
+	eventsResubscribeDelay = time.Second * 2
+	eventStreamContentType = "text/event-stream"
+)
+
+// headEvent is a head event of the beacon node event stream
+type headEvent struct {
+	Slot  phase0.Slot
+	Block phase0.Root
+}
+
+// subscribeHeads calls handle with every head event of the beacon node until the context is done, subscribing again
+// whenever the stream drops. The stream is opened with the shared client like every other request, so it goes through
+// the proxy, headers, audit and recording of the endpoint, the event streams of the go-eth2-client have a transport of
+// their own.
+func subscribeHeads(ctx context.Context, url, name string, handle func(headEvent)) {
+	for {
+		err := streamHeads(ctx, url, name, handle)
+		if ctx.Err() != nil {
+			return
+		}
+		logger.WriteError(metric.ConsensusGroup, name, errors.Join(err, errors.New("head event stream dropped, subscribing again")))
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(eventsResubscribeDelay):
+		}
+	}
+}
+
+// streamHeads reads the head events of a single subscription until the stream ends
+func streamHeads(ctx context.Context, url, name string, handle func(headEvent)) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url+"/eth/v1/events?topics=head", nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", eventStreamContentType)
+
+	res, err := httpclient.Default.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("received unsuccessful status code. Code: '%s'", res.Status)
+	}
+
+	return readEvents(res.Body, func(event, data string) {
+		if event != "head" {
+			return
+		}
+		head, err := decodeHeadEvent(data)
+		if err != nil {
+			logger.WriteError(metric.ConsensusGroup, name, err)
+			return
+		}
+		handle(head)
+	})
+}
+
+func decodeHeadEvent(data string) (headEvent, error) {
+	var event struct {
+		Slot  flexibleUint `json:"slot"`
+		Block string       `json:"block"`
+	}
+	if err := json.Unmarshal([]byte(data), &event); err != nil {
+		return headEvent{}, errors.Join(err, errors.New("failed decoding head event"))
+	}
+	decoded, err := hex.DecodeString(strings.TrimPrefix(event.Block, "0x"))
+	if err != nil {
+		return headEvent{}, errors.Join(err, errors.New("failed decoding block root of head event"))
+	}
+	root, err := toRoot(decoded)
+	if err != nil {
+		return headEvent{}, err
+	}
+	return headEvent{Slot: phase0.Slot(event.Slot), Block: root}, nil
+}
+
+// readEvents parses the server-sent events of the stream and calls handle with the name and the data of each, until
+// the stream ends. A stream of events never ends by itself, so ending is an error too.
+func readEvents(stream io.Reader, handle func(event, data string)) error {
+	scanner := bufio.NewScanner(stream)
+	scanner.Buffer(nil, 1024*1024)
+
+	var (
+		event string
+		data  []string
+	)
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case line == "":
+			// An empty line dispatches the event
+			if len(data) != 0 {
+				handle(event, strings.Join(data, "\n"))
+			}
+			event, data = "", nil
+		case strings.HasPrefix(line, ":"):
+			// Comments keep the connection alive
+		default:
+			field, value, _ := strings.Cut(line, ":")
+			value = strings.TrimPrefix(value, " ")
+			switch field {
+			case "event":
+				event = value
+			case "data":
+				data = append(data, value)
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	return io.ErrUnexpectedEOF
+}
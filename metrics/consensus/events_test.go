@@ -0,0 +1,51 @@
+package consensus
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGivenServerSentEventsWhenReadThenEventsDispatchedOnEmptyLines(t *testing.T) {
+	stream := ": keep-alive\n\nevent: head\ndata: {\"slot\":\"1\"}\n\nevent: block\ndata: first\ndata: second\n\nevent: head\ndata: {\"slot\":\"2\"}"
+	var events []string
+
+	err := readEvents(strings.NewReader(stream), func(event, data string) {
+		events = append(events, event+"="+data)
+	})
+
+	assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+	assert.Equal(t, []string{`head={"slot":"1"}`, "block=first\nsecond"}, events)
+}
+
+func TestGivenHeadEventStreamWhenStreamedThenHeadsHandled(t *testing.T) {
+	block := "0x" + strings.Repeat("ab", 32)
+	var topics, accept string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		topics, accept = r.URL.Query().Get("topics"), r.Header.Get("Accept")
+		w.Header().Set("Content-Type", eventStreamContentType)
+		fmt.Fprintf(w, "event: head\ndata: {\"slot\":\"7\",\"block\":\"%s\"}\n\n", block)
+		fmt.Fprint(w, "event: head\ndata: {\"slot\":\"8\",\"block\":\"0x01\"}\n\n")
+	}))
+	defer server.Close()
+	var heads []headEvent
+
+	err := streamHeads(context.Background(), server.URL, "Head Delay", func(head headEvent) {
+		heads = append(heads, head)
+	})
+
+	assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+	assert.Equal(t, "head", topics)
+	assert.Equal(t, eventStreamContentType, accept)
+	require.Len(t, heads, 1, "the head with a malformed root is skipped")
+	assert.Equal(t, phase0.Slot(7), heads[0].Slot)
+	assert.Equal(t, byte(0xab), heads[0].Block[31])
+}
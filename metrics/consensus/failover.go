@@ -0,0 +1,253 @@
+package consensus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/clock"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/httpclient"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/logger"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/metric"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/network"
+)
+
+const (
+	FallbackReadyMeasurement = "FallbackReady"
+	SwitchoverMeasurement    = "SwitchoverMs"
+	SimulatedMeasurement     = "Simulated"
+
+	// failoverRetryInterval is how often the fallback is asked for duties during a switchover
+	failoverRetryInterval = time.Millisecond * 250
+)
+
+var (
+	errFallbackSyncing = errors.New("fallback beacon node is syncing")
+	errSimulatedOutage = errors.New("beacon node outage is simulated")
+)
+
+// FailoverMetric measures how quickly a fallback beacon node serves duties once the beacon node is unavailable, like
+// a validator client switching over. An outage starts with the health check of the beacon node which failed, so the
+// switchover includes detecting it, and lasts until the synced fallback served the attestation data of the current
+// slot. A fallback which doesn't within a slot failed the switchover. With a simulation interval the beacon node is
+// additionally unavailable for a slot once every interval, it isn't asked at all meanwhile. Between outages the
+// fallback is checked to be ready.
+type FailoverMetric struct {
+	metric.Base[float64]
+	url            string
+	fallback       string
+	interval       time.Duration
+	simulateEvery  time.Duration
+	spec           network.Spec
+	mutex          sync.Mutex
+	nextSimulation time.Time
+	// simulatedUntil ends the simulated outage, the beacon node is treated as unavailable until then
+	simulatedUntil time.Time
+	// outage tells whether the beacon node is unavailable, the switchover of an outage is measured once
+	outage    bool
+	outages   int
+	simulated int
+	failed    int
+	lastErr   error
+}
+
+func NewFailoverMetric(url, fallback, name string, interval, simulateEvery time.Duration, spec network.Spec, healthCondition []metric.HealthCondition[float64]) *FailoverMetric {
+	return &FailoverMetric{
+		Base: metric.Base[float64]{
+			HealthConditions: healthCondition,
+			Name:             name,
+		},
+		url:           url,
+		fallback:      fallback,
+		interval:      interval,
+		simulateEvery: simulateEvery,
+		spec:          spec,
+	}
+}
+
+func (f *FailoverMetric) Measure(ctx context.Context) {
+	if f.simulateEvery > 0 {
+		f.nextSimulation = clock.Now().Add(f.simulateEvery)
+	}
+	f.Runner(f.interval).Run(ctx, f.measure)
+}
+
+func (f *FailoverMetric) measure(ctx context.Context) {
+	start := clock.Now()
+	if f.simulateEvery > 0 && !start.Before(f.nextSimulation) {
+		f.nextSimulation = start.Add(f.simulateEvery)
+		f.simulatedUntil = start.Add(f.spec.SlotDuration)
+		slog.With("metric_name", f.Name).Info("simulating an outage of the beacon node for a slot")
+	}
+
+	err := f.available(ctx, start)
+	switch {
+	case err == nil:
+		if f.outage {
+			slog.With("metric_name", f.Name).Info("beacon node is available again")
+			f.outage = false
+		}
+		f.measureReady(ctx)
+		return
+	case f.outage:
+		return
+	}
+	simulated := errors.Is(err, errSimulatedOutage)
+	if !simulated {
+		slog.With("metric_name", f.Name).With("err", err.Error()).Warn("beacon node is unavailable, switching over to the fallback")
+	}
+	f.outage = true
+
+	f.measureSwitchover(ctx, start, simulated)
+}
+
+// measureReady checks whether the fallback could serve duties right now
+func (f *FailoverMetric) measureReady(ctx context.Context) {
+	ready := 1.0
+	if err := f.attempt(ctx); err != nil {
+		ready = 0
+		logger.WriteError(metric.ConsensusGroup, f.Name, errors.Join(err, errors.New("fallback beacon node is not ready")))
+	}
+	f.AddDataPoint(map[string]float64{FallbackReadyMeasurement: ready})
+	logger.WriteMetric(metric.ConsensusGroup, f.Name, map[string]any{FallbackReadyMeasurement: ready == 1})
+}
+
+// measureSwitchover asks the fallback for duties until it serves them, for at most one slot
+func (f *FailoverMetric) measureSwitchover(ctx context.Context, start time.Time, simulated bool) {
+	switchCtx, cancel := context.WithTimeout(ctx, f.spec.SlotDuration)
+	defer cancel()
+
+	err := f.attempt(switchCtx)
+	for err != nil && switchCtx.Err() == nil {
+		select {
+		case <-switchCtx.Done():
+		case <-time.After(failoverRetryInterval):
+			err = f.attempt(switchCtx)
+		}
+	}
+	// The run ending isn't an outage the fallback failed
+	if ctx.Err() != nil {
+		return
+	}
+
+	values := map[string]float64{
+		FallbackReadyMeasurement: 1,
+		SwitchoverMeasurement:    float64(clock.Since(start).Milliseconds()),
+		SimulatedMeasurement:     0,
+	}
+	if simulated {
+		values[SimulatedMeasurement] = 1
+	}
+
+	f.mutex.Lock()
+	f.outages++
+	if simulated {
+		f.simulated++
+	}
+	if err != nil {
+		values[FallbackReadyMeasurement] = 0
+		values[SwitchoverMeasurement] = float64(f.spec.SlotDuration.Milliseconds())
+		f.failed++
+		f.lastErr = err
+	}
+	f.mutex.Unlock()
+
+	f.AddDataPoint(values)
+	if err != nil {
+		logger.WriteError(metric.ConsensusGroup, f.Name, errors.Join(err, errors.New("fallback beacon node didn't serve duties within a slot")))
+		return
+	}
+	logger.WriteMetric(metric.ConsensusGroup, f.Name, map[string]any{
+		SwitchoverMeasurement: values[SwitchoverMeasurement],
+		SimulatedMeasurement:  simulated,
+	})
+}
+
+// available checks the health of the beacon node, a syncing node can't serve duties either. During a simulated outage
+// the beacon node isn't asked.
+func (f *FailoverMetric) available(ctx context.Context, now time.Time) error {
+	if now.Before(f.simulatedUntil) {
+		return errSimulatedOutage
+	}
+
+	ctx, cancel := f.MeasurementContext(ctx, 2*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, f.url+"/eth/v1/node/health", nil)
+	if err != nil {
+		return err
+	}
+	res, err := httpclient.Default.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("beacon node health returned status '%s'", res.Status)
+	}
+	return nil
+}
+
+// attempt asks the fallback for duties once, bounded by the timeout of a measurement
+func (f *FailoverMetric) attempt(ctx context.Context) error {
+	ctx, cancel := f.MeasurementContext(ctx, 2*time.Second)
+	defer cancel()
+
+	return f.serves(ctx)
+}
+
+// serves checks the fallback is synced and serves the attestation data of the current slot, the duty a validator
+// client would ask it for
+func (f *FailoverMetric) serves(ctx context.Context) error {
+	var syncing struct {
+		Data struct {
+			IsSyncing bool `json:"is_syncing"`
+		} `json:"data"`
+	}
+	if err := getJSON(ctx, f.fallback+"/eth/v1/node/syncing", &syncing); err != nil {
+		return err
+	}
+	if syncing.Data.IsSyncing {
+		return errFallbackSyncing
+	}
+	_, err := FetchVote(ctx, f.fallback, currentSlot(f.spec))
+	return err
+}
+
+func (f *FailoverMetric) AggregateResults() string {
+	var switchovers []time.Duration
+	ready, checked := 0, 0
+	for _, point := range f.Snapshot() {
+		if value, ok := point.Values[SwitchoverMeasurement]; ok {
+			if point.Values[FallbackReadyMeasurement] == 1 {
+				switchovers = append(switchovers, time.Duration(value)*time.Millisecond)
+			}
+			continue
+		}
+		checked++
+		if point.Values[FallbackReadyMeasurement] == 1 {
+			ready++
+		}
+	}
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	summary := fmt.Sprintf("Outages: %d (simulated: %d), failed switchovers: %d", f.outages, f.simulated, f.failed)
+	if len(switchovers) != 0 {
+		percentiles := metric.CalculatePercentiles(switchovers, 0, 10, 50, 90, 100)
+		summary = metric.FormatPercentiles(percentiles[0], percentiles[10], percentiles[50], percentiles[90], percentiles[100]) + " \n " + summary
+	}
+	if checked != 0 {
+		summary += fmt.Sprintf(" \n fallback ready: %.2f %%", float64(ready)/float64(checked)*100)
+	}
+	if f.lastErr != nil {
+		summary += fmt.Sprintf(" \n last failure: %s", f.lastErr.Error())
+	}
+	return summary
+}
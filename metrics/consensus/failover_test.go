@@ -0,0 +1,105 @@
+package consensus
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/network"
+	"github.com/Harikakasimahanthi/benchmark-test/testutil/mocknode"
+)
+
+func TestGivenBeaconNodeAndFallbackWhenMeasuringFailoverThenOutageDetectedAndSwitchedOver(t *testing.T) {
+	// A short slot bounds the switchover, so a failing fallback doesn't slow the tests down
+	spec := network.DefaultSpec(network.Mainnet)
+	spec.SlotDuration = time.Millisecond * 500
+
+	tests := []struct {
+		name              string
+		primaryErrorRate  float64
+		fallbackErrorRate float64
+		simulate          bool
+		ticks             int
+		want              map[string]float64
+		wantOutages       int
+		wantSimulated     int
+		wantFailed        int
+		wantPrimaryAsked  bool
+	}{
+		{
+			name:             "available beacon node only checks the fallback is ready",
+			ticks:            1,
+			want:             map[string]float64{FallbackReadyMeasurement: 1},
+			wantPrimaryAsked: true,
+		},
+		{
+			name:             "unavailable beacon node switches over to the fallback",
+			primaryErrorRate: 1,
+			ticks:            2,
+			want:             map[string]float64{FallbackReadyMeasurement: 1, SimulatedMeasurement: 0},
+			wantOutages:      1,
+			wantPrimaryAsked: true,
+		},
+		{
+			name:              "unavailable fallback fails the switchover",
+			primaryErrorRate:  1,
+			fallbackErrorRate: 1,
+			ticks:             1,
+			want:              map[string]float64{FallbackReadyMeasurement: 0, SimulatedMeasurement: 0},
+			wantOutages:       1,
+			wantFailed:        1,
+			wantPrimaryAsked:  true,
+		},
+		{
+			name:          "simulated outage doesn't use the beacon node",
+			simulate:      true,
+			ticks:         2,
+			want:          map[string]float64{FallbackReadyMeasurement: 1, SimulatedMeasurement: 1},
+			wantOutages:   1,
+			wantSimulated: 1,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var primaryRequests atomic.Int32
+			primaryNode := mocknode.New(network.Mainnet).WithErrorRate(test.primaryErrorRate)
+			primaryHandler := primaryNode.ConsensusHandler()
+			primary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				primaryRequests.Add(1)
+				primaryHandler.ServeHTTP(w, r)
+			}))
+			defer primary.Close()
+			fallback := mocknode.New(network.Mainnet).WithErrorRate(test.fallbackErrorRate).Start()
+			defer fallback.Close()
+
+			var simulateEvery time.Duration
+			if test.simulate {
+				simulateEvery = time.Hour
+			}
+			failover := NewFailoverMetric(primary.URL, fallback.ConsensusURL(), "Failover", time.Second, simulateEvery, spec, nil)
+
+			// Ticks within the outage don't measure another switchover
+			for range test.ticks {
+				failover.measure(context.Background())
+			}
+
+			points := failover.Snapshot()
+			require.Len(t, points, 1)
+			for name, value := range test.want {
+				assert.Equal(t, value, points[0].Values[name], name)
+			}
+			_, switchedOver := points[0].Values[SwitchoverMeasurement]
+			assert.Equal(t, test.wantOutages != 0, switchedOver)
+			assert.Equal(t, test.wantOutages, failover.outages)
+			assert.Equal(t, test.wantSimulated, failover.simulated)
+			assert.Equal(t, test.wantFailed, failover.failed)
+			assert.Equal(t, test.wantPrimaryAsked, primaryRequests.Load() != 0)
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package consensus
+
+import (
+	"context"
+	"errors"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"google.golang.org/protobuf/encoding/protowire"
+
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/grpcclient"
+)
+
+// Methods of the native Prysm v1alpha1 gRPC API
+const (
+	listPeersMethod       = "/ethereum.eth.v1alpha1.Node/ListPeers"
+	syncStatusMethod      = "/ethereum.eth.v1alpha1.Node/GetSyncStatus"
+	attestationDataMethod = "/ethereum.eth.v1alpha1.BeaconNodeValidator/GetAttestationData"
+)
+
+// grpcAPI queries the native gRPC API of Prysm nodes, which serves the peers and the attestation data of every
+// version, unlike the gateway. Methods the node doesn't implement fall back to the gateway.
+type grpcAPI struct {
+	prysmAPI
+	client *grpcclient.Client
+}
+
+func newGRPCAPI(address string) (grpcAPI, error) {
+	client, err := grpcclient.New(address)
+	if err != nil {
+		return grpcAPI{}, err
+	}
+	return grpcAPI{client: client}, nil
+}
+
+// GRPCSyncing asks the native gRPC API at the address whether the node is syncing, which tells whether the API is
+// reachable before metrics are pointed at it
+func GRPCSyncing(ctx context.Context, address string) (bool, error) {
+	client, err := grpcclient.New(address)
+	if err != nil {
+		return false, err
+	}
+	response, err := client.Invoke(ctx, syncStatusMethod, nil)
+	if err != nil {
+		return false, err
+	}
+
+	// SyncStatus holds syncing as field 1
+	var syncing bool
+	err = protoFields(response, func(number protowire.Number, typ protowire.Type, value []byte) {
+		if number == 1 && typ == protowire.VarintType {
+			flag, _ := protowire.ConsumeVarint(value)
+			syncing = flag != 0
+		}
+	})
+	return syncing, err
+}
+
+func (g grpcAPI) peerCount(ctx context.Context, url string) (uint32, error) {
+	response, err := g.client.Invoke(ctx, listPeersMethod, nil)
+	if errors.Is(err, grpcclient.ErrUnimplemented) {
+		return g.prysmAPI.peerCount(ctx, url)
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	// Peers holds every peer as an occurrence of field 1
+	var count uint32
+	err = protoFields(response, func(number protowire.Number, _ protowire.Type, _ []byte) {
+		if number == 1 {
+			count++
+		}
+	})
+	return count, err
+}
+
+func (g grpcAPI) attestationBlockRoot(ctx context.Context, url string, slot phase0.Slot) (phase0.Root, error) {
+	// AttestationDataRequest holds the slot as field 1, the committee index 0 is the default and left out
+	request := protowire.AppendTag(nil, 1, protowire.VarintType)
+	request = protowire.AppendVarint(request, uint64(slot))
+
+	response, err := g.client.Invoke(ctx, attestationDataMethod, request)
+	if errors.Is(err, grpcclient.ErrUnimplemented) {
+		return g.prysmAPI.attestationBlockRoot(ctx, url, slot)
+	}
+	if err != nil {
+		return phase0.Root{}, err
+	}
+
+	// AttestationData holds the beacon block root as field 3
+	var root []byte
+	err = protoFields(response, func(number protowire.Number, typ protowire.Type, value []byte) {
+		if number == 3 && typ == protowire.BytesType {
+			root = value
+		}
+	})
+	if err != nil {
+		return phase0.Root{}, err
+	}
+	return toRoot(root)
+}
+
+// protoFields calls the field function for every field of the protobuf message, with the content of length-delimited
+// fields and the encoded value of the others
+func protoFields(message []byte, field func(number protowire.Number, typ protowire.Type, value []byte)) error {
+	for len(message) > 0 {
+		number, typ, n := protowire.ConsumeTag(message)
+		if n < 0 {
+			return errors.Join(protowire.ParseError(n), errors.New("failed decoding gRPC response"))
+		}
+		message = message[n:]
+
+		var value []byte
+		if typ == protowire.BytesType {
+			value, n = protowire.ConsumeBytes(message)
+		} else {
+			n = protowire.ConsumeFieldValue(number, typ, message)
+			if n >= 0 {
+				value = message[:n]
+			}
+		}
+		if n < 0 {
+			return errors.Join(protowire.ParseError(n), errors.New("failed decoding gRPC response"))
+		}
+		field(number, typ, value)
+		message = message[n:]
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+package consensus
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/clock"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/format"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/logger"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/metric"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/network"
+)
+
+const (
+	HeadDelayMeasurement    = "HeadDelay"
+	HeadDelayMinMeasurement = "HeadDelayMin"
+	HeadDelayP50Measurement = "HeadDelayP50"
+	HeadDelayP90Measurement = "HeadDelayP90"
+	HeadDelayMaxMeasurement = "HeadDelayMax"
+)
+
+// HeadDelayMetric records the delay of every head event after the start of its slot. The health conditions are
+// evaluated against the percentiles of the delays of the evaluated window rather than the single delays.
+type HeadDelayMetric struct {
+	metric.Base[time.Duration]
+	url  string
+	spec network.Spec
+}
+
+func NewHeadDelayMetric(url, name string, spec network.Spec, healthCondition []metric.HealthCondition[time.Duration]) *HeadDelayMetric {
+	h := &HeadDelayMetric{
+		Base: metric.Base[time.Duration]{
+			HealthConditions: healthCondition,
+			Name:             name,
+		},
+		url:  url,
+		spec: spec,
+	}
+	h.EvaluateAggregated(headDelayPercentiles)
+	return h
+}
+
+func (h *HeadDelayMetric) Measure(ctx context.Context) {
+	subscribeHeads(ctx, h.url, h.Name, func(head headEvent) {
+		// The callbacks run in the goroutine of the event stream
+		defer metric.Recover(ctx, h.Name)
+		h.writeMetric(clock.Since(slotTime(h.spec, head.Slot)))
+	})
+	slog.With("metric_name", h.Name).Debug("metric was stopped")
+}
+
+func (h *HeadDelayMetric) writeMetric(delay time.Duration) {
+	h.AddDataPoint(map[string]time.Duration{HeadDelayMeasurement: delay})
+
+	logger.WriteMetric(metric.ConsensusGroup, h.Name, map[string]any{
+		HeadDelayMeasurement: delay,
+	})
+}
+
+// headDelayPercentiles aggregates the delays of the data points to the percentiles the health conditions are set on
+func headDelayPercentiles(dataPoints []metric.DataPoint[time.Duration]) map[string]time.Duration {
+	percentiles := metric.CalculatePercentiles(headDelays(dataPoints), 0, 50, 90, 100)
+	return map[string]time.Duration{
+		HeadDelayMinMeasurement: percentiles[0],
+		HeadDelayP50Measurement: percentiles[50],
+		HeadDelayP90Measurement: percentiles[90],
+		HeadDelayMaxMeasurement: percentiles[100],
+	}
+}
+
+func headDelays(dataPoints []metric.DataPoint[time.Duration]) []time.Duration {
+	delays := make([]time.Duration, 0, len(dataPoints))
+	for _, dataPoint := range dataPoints {
+		if delay, ok := dataPoint.Values[HeadDelayMeasurement]; ok {
+			delays = append(delays, delay)
+		}
+	}
+	return delays
+}
+
+// Distribution returns the head delays measured so far
+func (h *HeadDelayMetric) Distribution() []time.Duration {
+	return headDelays(h.Snapshot())
+}
+
+func (h *HeadDelayMetric) AggregateResults() string {
+	dataPoints := h.Snapshot()
+	delays := headDelays(dataPoints)
+
+	deadline := h.spec.AttestationDeadline()
+	var late int
+	for _, delay := range delays {
+		if delay > deadline {
+			late++
+		}
+	}
+	var lateShare float64
+	if len(delays) != 0 {
+		lateShare = float64(late) / float64(len(delays)) * 100
+	}
+
+	percentiles := headDelayPercentiles(dataPoints)
+
+	return fmt.Sprintf("min=%s, p50=%s, p90=%s, max=%s \n heads=%d, late_heads_%gs=%.2f %%",
+		format.Duration(percentiles[HeadDelayMinMeasurement]), format.Duration(percentiles[HeadDelayP50Measurement]),
+		format.Duration(percentiles[HeadDelayP90Measurement]), format.Duration(percentiles[HeadDelayMaxMeasurement]),
+		len(delays), deadline.Round(time.Millisecond*100).Seconds(), lateShare)
+}
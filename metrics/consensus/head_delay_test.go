@@ -0,0 +1,37 @@
+package consensus
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/metric"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/network"
+)
+
+func TestGivenHeadDelaysWhenEvaluatedThenPercentilesOfDelaysEvaluated(t *testing.T) {
+	spec := network.DefaultSpec(network.Mainnet)
+	headDelay := NewHeadDelayMetric("http://localhost:5052", "Head Delay", spec, []metric.HealthCondition[time.Duration]{
+		{Name: HeadDelayP90Measurement, Threshold: spec.AttestationDeadline(), Operator: metric.OperatorGreaterThanOrEqual, Severity: metric.SeverityHigh},
+	})
+
+	// A single late head among many timely ones keeps the p90 below the deadline
+	for range 19 {
+		headDelay.writeMetric(time.Second)
+	}
+	headDelay.writeMetric(time.Second * 6)
+
+	points := headDelay.Snapshot()
+	assert.Len(t, points, 20)
+	assert.Equal(t, map[string]time.Duration{HeadDelayMeasurement: time.Second * 6}, points[19].Values, "every data point is a single delay")
+	assert.Equal(t, metric.Healthy, headDelay.EvaluateMetric().Health)
+	assert.Contains(t, headDelay.AggregateResults(), "p90=1.00s")
+	assert.Contains(t, headDelay.AggregateResults(), "max=6.00s")
+
+	for range 5 {
+		headDelay.writeMetric(time.Second * 6)
+	}
+	assert.Equal(t, metric.Unhealthy, headDelay.EvaluateMetric().Health)
+	assert.Len(t, headDelay.Distribution(), 25)
+}
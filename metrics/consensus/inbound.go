@@ -0,0 +1,160 @@
+package consensus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/enr"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/logger"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/metric"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/portcheck"
+)
+
+const (
+	InboundPeersMeasurement     = "InboundPeers"
+	InboundReachableMeasurement = "InboundReachable"
+	PrivateENRMeasurement       = "PrivateENR"
+)
+
+type (
+	// InboundMetric determines whether the P2P port of the beacon node is reachable from outside. NATed nodes only
+	// connect to peers themselves and silently underperform. The node is reachable when an external probe service
+	// reaches the address announced in its ENR, or without probe service when it has inbound peers.
+	// An ENR announcing a private IP means the node doesn't know its public address, e.g. UPnP failed.
+	InboundMetric struct {
+		metric.Base[float64]
+		url string
+		// probeURL is requested with '{host}' and '{port}' replaced, a 2xx status means the port is reachable
+		probeURL string
+		// defaultPort is probed when the ENR announces no TCP port, zero when the client is unknown
+		defaultPort uint16
+		interval    time.Duration
+		last        inboundResult
+		mutex       sync.Mutex
+	}
+
+	inboundResult struct {
+		record       enr.Record
+		inboundPeers int
+		reachable    bool
+		probed       bool
+	}
+)
+
+func NewInboundMetric(url, probeURL, name string, interval time.Duration, healthCondition []metric.HealthCondition[float64]) *InboundMetric {
+	return &InboundMetric{
+		Base: metric.Base[float64]{
+			HealthConditions: healthCondition,
+			Name:             name,
+		},
+		url:      url,
+		probeURL: probeURL,
+		interval: interval,
+	}
+}
+
+// WithDefaultPort probes the default P2P port of the client when its ENR announces no TCP port
+func (i *InboundMetric) WithDefaultPort(port uint16) *InboundMetric {
+	i.defaultPort = port
+	return i
+}
+
+func (i *InboundMetric) Measure(ctx context.Context) {
+	i.Runner(i.interval).Run(ctx, i.measure)
+}
+
+func (i *InboundMetric) measure(ctx context.Context) {
+	ctx, cancel := i.MeasurementContext(ctx, 10*time.Second)
+	defer cancel()
+
+	var identity struct {
+		Data struct {
+			ENR string `json:"enr"`
+		} `json:"data"`
+	}
+	if err := getJSON(ctx, fmt.Sprintf("%s/eth/v1/node/identity", i.url), &identity); err != nil {
+		logger.WriteError(metric.ConsensusGroup, i.Name, err)
+		return
+	}
+	record, err := enr.Parse(identity.Data.ENR)
+	if err != nil {
+		logger.WriteError(metric.ConsensusGroup, i.Name, errors.Join(err, errors.New("failed parsing the ENR of the node")))
+		return
+	}
+
+	var peers struct {
+		Data []struct {
+			PeerID string `json:"peer_id"`
+		} `json:"data"`
+	}
+	if err := getJSON(ctx, fmt.Sprintf("%s/eth/v1/node/peers?state=connected&direction=inbound", i.url), &peers); err != nil {
+		logger.WriteError(metric.ConsensusGroup, i.Name, err)
+		return
+	}
+
+	result := inboundResult{
+		record:       record,
+		inboundPeers: len(peers.Data),
+		reachable:    len(peers.Data) != 0,
+	}
+	if record.TCP == 0 {
+		record.TCP = i.defaultPort
+	}
+	if i.probeURL != "" && portcheck.IsPublic(record.IP) && record.TCP != 0 {
+		result.reachable, err = portcheck.Probe(ctx, i.probeURL, record.IP, record.TCP)
+		if err != nil {
+			logger.WriteError(metric.ConsensusGroup, i.Name, errors.Join(err, errors.New("failed probing the P2P port")))
+			return
+		}
+		result.probed = true
+	}
+
+	i.mutex.Lock()
+	i.last = result
+	i.mutex.Unlock()
+
+	values := map[string]float64{
+		InboundPeersMeasurement:     float64(result.inboundPeers),
+		InboundReachableMeasurement: 0,
+		PrivateENRMeasurement:       0,
+	}
+	if result.reachable {
+		values[InboundReachableMeasurement] = 1
+	}
+	if !portcheck.IsPublic(record.IP) {
+		values[PrivateENRMeasurement] = 1
+	}
+	i.AddDataPoint(values)
+
+	logger.WriteMetric(metric.ConsensusGroup, i.Name, map[string]any{
+		InboundPeersMeasurement:     result.inboundPeers,
+		InboundReachableMeasurement: result.reachable,
+		"ENRAddress":                portcheck.Address(record.IP, record.TCP),
+	})
+}
+
+func (i *InboundMetric) AggregateResults() string {
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+
+	connectivity, method := "no", "inbound peers"
+	if i.last.reachable {
+		connectivity = "yes"
+	}
+	if i.last.probed {
+		method = "external probe"
+	}
+
+	var values []float64
+	for _, point := range i.Snapshot() {
+		values = append(values, point.Values[InboundPeersMeasurement])
+	}
+	percentiles := metric.CalculatePercentiles(values, 0, 10, 50, 90, 100)
+
+	return fmt.Sprintf("Inbound connectivity: %s (%s), ENR: %s \n Inbound peers: %s",
+		connectivity, method, portcheck.Address(i.last.record.IP, i.last.record.TCP),
+		metric.FormatPercentiles(percentiles[0], percentiles[10], percentiles[50], percentiles[90], percentiles[100]))
+}
@@ -2,10 +2,15 @@ package consensus
 
 import (
 	"context"
-	"log/slog"
+	"fmt"
 	"net/http"
+	"sync"
 	"time"
 
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/exporter"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/format"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/httpclient"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/httptiming"
 	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/logger"
 	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/metric"
 )
@@ -16,13 +21,24 @@ const (
 	DurationP50Measurement = "DurationP50"
 	DurationP90Measurement = "DurationP90"
 	DurationMaxMeasurement = "DurationMax"
+	DNSP50Measurement      = "DNSP50"
+	ConnectP50Measurement  = "ConnectP50"
+	TLSP50Measurement      = "TLSP50"
+	TTFBP50Measurement     = "TTFBP50"
+	// ProxyP50Measurement is the time spent reaching the node through its proxy, excluded from the durations
+	ProxyP50Measurement = "ProxyP50"
 )
 
 type LatencyMetric struct {
 	metric.Base[time.Duration]
-	url               string
-	interval, timeout time.Duration
-	durations         []time.Duration
+	url       string
+	client    *http.Client
+	interval  time.Duration
+	durations []time.Duration
+	timings   []httptiming.Timing
+	overheads []time.Duration
+	// mutex guards the durations, which the report reads while measuring
+	mutex sync.Mutex
 }
 
 func NewLatencyMetric(url, name string, interval time.Duration, healthCondition []metric.HealthCondition[time.Duration]) *LatencyMetric {
@@ -32,85 +48,118 @@ func NewLatencyMetric(url, name string, interval time.Duration, healthCondition
 			HealthConditions: healthCondition,
 			Name:             name,
 		},
+		// Measurements are bounded by their context
+		client:   httptiming.NewClient(0),
 		interval: interval,
-		timeout:  time.Duration(float64(interval) * 0.75),
 	}
 }
 
 func (l *LatencyMetric) Measure(ctx context.Context) {
-	ticker := time.NewTicker(l.interval)
-	defer ticker.Stop()
-
-	for {
-		select {
-		case <-ctx.Done():
-			slog.With("metric_name", l.Name).Debug("metric was stopped")
-			return
-		case <-ticker.C:
-			l.measure()
-		}
-	}
+	l.Runner(l.interval).Run(ctx, l.measure)
 }
 
-func (l *LatencyMetric) measure() {
-	var latency time.Duration
-	start := time.Now()
+func (l *LatencyMetric) measure(ctx context.Context) {
+	// A slow measurement must not overlap the next one
+	ctx, cancel := l.MeasurementContext(ctx, time.Duration(float64(l.interval)*0.75))
+	defer cancel()
 
 	// Measure latency for the solo staking node’s key endpoint
-	client := http.Client{
-		Timeout: l.timeout,
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/eth/v1/node/version", l.url), nil)
+	if err != nil {
+		logger.WriteError(metric.ConsensusGroup, l.Name, err)
+		return
 	}
-	res, err := client.Get(l.url) // Replace with specific solo staking node endpoint if required
+	timing, _, err := httptiming.Do(l.client, req)
 	if err != nil {
 		logger.WriteError(metric.ConsensusGroup, l.Name, err)
 		return
 	}
-	defer res.Body.Close()
-
-	latency = time.Since(start)
 
+	latency := timing.Total
+	// Through a proxy the latency of the node excludes reaching it, which is reported on its own
+	if httpclient.ProxyOf(l.url) != nil {
+		latency -= timing.ProxyOverhead()
+		l.overheads = append(l.overheads, timing.ProxyOverhead())
+	}
+	l.mutex.Lock()
 	l.durations = append(l.durations, latency)
+	l.mutex.Unlock()
+	l.timings = append(l.timings, timing)
 
-	l.writeMetric(latency)
+	l.writeMetric(ctx, latency)
 }
 
-func (l *LatencyMetric) writeMetric(latency time.Duration) {
+func (l *LatencyMetric) writeMetric(ctx context.Context, latency time.Duration) {
 	// Calculate percentiles for latency
+	l.mutex.Lock()
 	percentiles := metric.CalculatePercentiles(l.durations, 0, 10, 50, 90, 100)
+	l.mutex.Unlock()
 
 	// Record latency metrics for reporting
-	l.AddDataPoint(map[string]time.Duration{
+	values := map[string]time.Duration{
 		DurationMinMeasurement: percentiles[0],
 		DurationP10Measurement: percentiles[10],
 		DurationP50Measurement: percentiles[50],
 		DurationP90Measurement: percentiles[90],
 		DurationMaxMeasurement: percentiles[100],
-	})
+	}
+	phases := httptiming.Percentile(l.timings, 50)
+	values[DNSP50Measurement] = phases.DNS
+	values[ConnectP50Measurement] = phases.Connect
+	values[TLSP50Measurement] = phases.TLS
+	values[TTFBP50Measurement] = phases.TTFB
+	if len(l.overheads) != 0 {
+		values[ProxyP50Measurement] = metric.CalculatePercentiles(l.overheads, 50)[50]
+	}
+	l.AddDataPoint(values)
 
 	// Assuming there is a Prometheus metric being used here for latency
-	latencyMetric.Observe(latency.Seconds())
+	exporter.ObserveLatency(ctx, metric.ConsensusGroup, l.url, latency)
 
 	// Log the measured metrics
-	logger.WriteMetric(metric.ConsensusGroup, l.Name, map[string]any{
+	logged := map[string]any{
 		DurationMinMeasurement: percentiles[0],
 		DurationP10Measurement: percentiles[10],
 		DurationP50Measurement: percentiles[50],
 		DurationP90Measurement: percentiles[90],
 		DurationMaxMeasurement: percentiles[100],
-	})
+		DNSP50Measurement:      values[DNSP50Measurement],
+		ConnectP50Measurement:  values[ConnectP50Measurement],
+		TLSP50Measurement:      values[TLSP50Measurement],
+		TTFBP50Measurement:     values[TTFBP50Measurement],
+	}
+	if proxy, ok := values[ProxyP50Measurement]; ok {
+		logged[ProxyP50Measurement] = proxy
+	}
+	logger.WriteMetric(metric.ConsensusGroup, l.Name, logged)
+}
+
+// Distribution returns the latencies measured so far
+func (l *LatencyMetric) Distribution() []time.Duration {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	return append([]time.Duration(nil), l.durations...)
 }
 
 func (l *LatencyMetric) AggregateResults() string {
 	// Extract and return the percentiles for latency measurements
-	var min, p10, p50, p90, max time.Duration
-
-	if len(l.DataPoints) > 0 {
-		min = l.DataPoints[len(l.DataPoints)-1].Values[DurationMinMeasurement]
-		p10 = l.DataPoints[len(l.DataPoints)-1].Values[DurationP10Measurement]
-		p50 = l.DataPoints[len(l.DataPoints)-1].Values[DurationP50Measurement]
-		p90 = l.DataPoints[len(l.DataPoints)-1].Values[DurationP90Measurement]
-		max = l.DataPoints[len(l.DataPoints)-1].Values[DurationMaxMeasurement]
+	var (
+		min, p10, p50, p90, max time.Duration
+		values                  map[string]time.Duration
+	)
+
+	if latest, ok := l.Latest(); ok {
+		values = latest.Values
+		min = values[DurationMinMeasurement]
+		p10 = values[DurationP10Measurement]
+		p50 = values[DurationP50Measurement]
+		p90 = values[DurationP90Measurement]
+		max = values[DurationMaxMeasurement]
 	}
 
-	return metric.FormatPercentiles(min, p10, p50, p90, max)
+	return metric.FormatPercentiles(min, p10, p50, p90, max) + fmt.Sprintf(" \n dns_p50=%s, connect_p50=%s, tls_p50=%s, ttfb_p50=%s",
+		format.Duration(values[DNSP50Measurement]),
+		format.Duration(values[ConnectP50Measurement]),
+		format.Duration(values[TLSP50Measurement]),
+		format.Duration(values[TTFBP50Measurement])) + httptiming.FormatProxy(l.url, values[ProxyP50Measurement])
 }
@@ -0,0 +1,206 @@
+package consensus
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/enr"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/logger"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/metric"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/network"
+)
+
+const (
+	GenesisMismatchMeasurement = "GenesisMismatch"
+	ForkMismatchMeasurement    = "ForkMismatch"
+)
+
+type (
+	// Identity is the network identity of a beacon node
+	Identity struct {
+		PeerID                string   `json:"peer_id"`
+		ENR                   string   `json:"enr"`
+		P2PAddresses          []string `json:"p2p_addresses"`
+		ForkDigest            string   `json:"fork_digest"`
+		GenesisValidatorsRoot string   `json:"genesis_validators_root"`
+		ForkSchedule          []Fork   `json:"fork_schedule"`
+		HeadFork              Fork     `json:"head_fork"`
+	}
+
+	Fork struct {
+		PreviousVersion string       `json:"previous_version"`
+		CurrentVersion  string       `json:"current_version"`
+		Epoch           flexibleUint `json:"epoch"`
+	}
+
+	// NetworkMetric verifies periodically that the node stays on the configured network and transitioned to the fork
+	// scheduled for the current epoch. Since Fulu the fork digest depends on blob parameters as well, so fork versions
+	// are compared instead of digests.
+	NetworkMetric struct {
+		metric.Base[float64]
+		url      string
+		network  network.Name
+		spec     network.Spec
+		interval time.Duration
+		last     Identity
+		mutex    sync.Mutex
+	}
+)
+
+// FetchIdentity captures the identity, genesis and fork schedule of the node
+func FetchIdentity(ctx context.Context, url string) (Identity, error) {
+	var identity Identity
+
+	var identityResp struct {
+		Data struct {
+			PeerID       string   `json:"peer_id"`
+			ENR          string   `json:"enr"`
+			P2PAddresses []string `json:"p2p_addresses"`
+		} `json:"data"`
+	}
+	if err := getJSON(ctx, fmt.Sprintf("%s/eth/v1/node/identity", url), &identityResp); err != nil {
+		return identity, errors.Join(err, errors.New("failed fetching node identity"))
+	}
+	identity.PeerID = identityResp.Data.PeerID
+	identity.ENR = identityResp.Data.ENR
+	identity.P2PAddresses = identityResp.Data.P2PAddresses
+	if record, err := enr.Parse(identity.ENR); err == nil {
+		// The 'eth2' entry starts with the fork digest, followed by the next fork version and epoch
+		if eth2 := record.Pairs["eth2"]; len(eth2) >= 4 {
+			identity.ForkDigest = "0x" + hex.EncodeToString(eth2[:4])
+		}
+	}
+
+	var genesisResp struct {
+		Data struct {
+			GenesisValidatorsRoot string `json:"genesis_validators_root"`
+		} `json:"data"`
+	}
+	if err := getJSON(ctx, fmt.Sprintf("%s/eth/v1/beacon/genesis", url), &genesisResp); err != nil {
+		return identity, errors.Join(err, errors.New("failed fetching genesis"))
+	}
+	identity.GenesisValidatorsRoot = genesisResp.Data.GenesisValidatorsRoot
+
+	var scheduleResp struct {
+		Data []Fork `json:"data"`
+	}
+	if err := getJSON(ctx, fmt.Sprintf("%s/eth/v1/config/fork_schedule", url), &scheduleResp); err != nil {
+		return identity, errors.Join(err, errors.New("failed fetching fork schedule"))
+	}
+	identity.ForkSchedule = scheduleResp.Data
+
+	var forkResp struct {
+		Data Fork `json:"data"`
+	}
+	if err := getJSON(ctx, fmt.Sprintf("%s/eth/v1/beacon/states/head/fork", url), &forkResp); err != nil {
+		return identity, errors.Join(err, errors.New("failed fetching head fork"))
+	}
+	identity.HeadFork = forkResp.Data
+
+	return identity, nil
+}
+
+// VerifyNetwork fails when the node is on another chain than the network, e.g. a testnet node used with mainnet
+func (i Identity) VerifyNetwork(name network.Name) error {
+	if name == network.Custom {
+		return nil
+	}
+	preset, ok := network.Presets[name]
+	if !ok {
+		return fmt.Errorf("genesis validators root of network '%s' is unknown", name)
+	}
+	expected := preset.GenesisValidatorsRoot
+	if !strings.EqualFold(i.GenesisValidatorsRoot, expected) {
+		return fmt.Errorf("beacon node is not on network '%s', its genesis validators root is '%s' instead of '%s'",
+			name, i.GenesisValidatorsRoot, expected)
+	}
+	return nil
+}
+
+// ScheduledFork returns the fork of the schedule active at the epoch
+func (i Identity) ScheduledFork(epoch uint64) (Fork, bool) {
+	var active Fork
+	found := false
+	for _, fork := range i.ForkSchedule {
+		if uint64(fork.Epoch) <= epoch && (!found || fork.Epoch >= active.Epoch) {
+			active, found = fork, true
+		}
+	}
+	return active, found
+}
+
+// ScheduledVersions maps the fork versions of the schedule to their epochs
+func (i Identity) ScheduledVersions() map[string]uint64 {
+	versions := make(map[string]uint64, len(i.ForkSchedule))
+	for _, fork := range i.ForkSchedule {
+		versions[strings.ToLower(fork.CurrentVersion)] = uint64(fork.Epoch)
+	}
+	return versions
+}
+
+func NewNetworkMetric(url, name string, networkName network.Name, spec network.Spec, interval time.Duration, healthCondition []metric.HealthCondition[float64]) *NetworkMetric {
+	return &NetworkMetric{
+		Base: metric.Base[float64]{
+			HealthConditions: healthCondition,
+			Name:             name,
+		},
+		url:      url,
+		network:  networkName,
+		spec:     spec,
+		interval: interval,
+	}
+}
+
+func (n *NetworkMetric) Measure(ctx context.Context) {
+	n.Runner(n.interval).Run(ctx, n.measure)
+}
+
+func (n *NetworkMetric) measure(ctx context.Context) {
+	ctx, cancel := n.MeasurementContext(ctx, 10*time.Second)
+	defer cancel()
+
+	identity, err := FetchIdentity(ctx, n.url)
+	if err != nil {
+		logger.WriteError(metric.ConsensusGroup, n.Name, err)
+		return
+	}
+
+	values := map[string]float64{
+		GenesisMismatchMeasurement: 0,
+		ForkMismatchMeasurement:    0,
+	}
+	if err := identity.VerifyNetwork(n.network); err != nil {
+		values[GenesisMismatchMeasurement] = 1
+		logger.WriteError(metric.ConsensusGroup, n.Name, err)
+	}
+
+	epoch := n.spec.Epoch(n.spec.CurrentSlot())
+	if scheduled, ok := identity.ScheduledFork(epoch); ok && !strings.EqualFold(scheduled.CurrentVersion, identity.HeadFork.CurrentVersion) {
+		values[ForkMismatchMeasurement] = 1
+	}
+
+	n.mutex.Lock()
+	n.last = identity
+	n.mutex.Unlock()
+
+	n.AddDataPoint(values)
+	logger.WriteMetric(metric.ConsensusGroup, n.Name, map[string]any{
+		GenesisMismatchMeasurement: values[GenesisMismatchMeasurement] == 1,
+		ForkMismatchMeasurement:    values[ForkMismatchMeasurement] == 1,
+		"ForkVersion":              identity.HeadFork.CurrentVersion,
+		"ForkDigest":               identity.ForkDigest,
+	})
+}
+
+func (n *NetworkMetric) AggregateResults() string {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+
+	return fmt.Sprintf("Network: %s, Fork Version: %s, Fork Digest: %s \n Peer ID: %s",
+		n.network, n.last.HeadFork.CurrentVersion, n.last.ForkDigest, n.last.PeerID)
+}
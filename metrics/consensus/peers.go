@@ -2,15 +2,11 @@ package consensus
 
 import (
 	"context"
-	"encoding/json"
-	"errors"
-	"fmt"
-	"io"
 	"log/slog"
-	"net/http"
-	"strconv"
 	"time"
 
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/clientinfo"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/exporter"
 	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/logger"
 	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/metric"
 )
@@ -23,6 +19,7 @@ type PeerMetric struct {
 	metric.Base[uint32]
 	url      string
 	interval time.Duration
+	api      beaconAPI
 }
 
 func NewPeerMetric(url, name string, interval time.Duration, healthCondition []metric.HealthCondition[uint32]) *PeerMetric {
@@ -33,71 +30,40 @@ func NewPeerMetric(url, name string, interval time.Duration, healthCondition []m
 			Name:             name,
 		},
 		interval: interval,
+		api:      standardAPI{},
 	}
 }
 
-func (p *PeerMetric) Measure(ctx context.Context) {
-	ticker := time.NewTicker(p.interval)
-	defer ticker.Stop()
-
-	for {
-		select {
-		case <-ctx.Done():
-			slog.With("metric_name", p.Name).Debug("metric was stopped")
-			return
-		case <-ticker.C:
-			p.measure(ctx)
-		}
-	}
+// WithClient adapts the metric to the API differences of the detected consensus client
+func (p *PeerMetric) WithClient(adapter clientinfo.Adapter) *PeerMetric {
+	p.api = apiFor(adapter)
+	return p
 }
 
-func (p *PeerMetric) measure(ctx context.Context) {
-	var (
-		resp struct {
-			Data struct {
-				Connected string `json:"connected"`
-			} `json:"data"`
-		}
-	)
-	// Context timeout set for the request
-	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
-	defer cancel()
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/eth/v1/node/peer_count", p.url), nil)
-	if err != nil {
-		logger.WriteError(metric.ConsensusGroup, p.Name, err)
-		return
+// WithGRPC counts the peers through the native gRPC API at the address, selected for clients having one
+func (p *PeerMetric) WithGRPC(address string) *PeerMetric {
+	if address == "" {
+		return p
 	}
-
-	// Make HTTP request to fetch peer count
-	res, err := http.DefaultClient.Do(req)
+	api, err := newGRPCAPI(address)
 	if err != nil {
-		p.AddDataPoint(map[string]uint32{
-			PeerCountMeasurement: 0,
-		})
-		logger.WriteError(metric.ConsensusGroup, p.Name, err)
-		return
+		slog.With("err", err.Error()).With("metric_name", p.Name).Warn("failed creating gRPC client, using the beacon API")
+		return p
 	}
-	defer res.Body.Close()
+	p.api = api
+	return p
+}
 
-	if res.StatusCode != http.StatusOK {
-		p.AddDataPoint(map[string]uint32{
-			PeerCountMeasurement: 0,
-		})
-		p.logErrorResponse(res)
-		return
-	}
+func (p *PeerMetric) Measure(ctx context.Context) {
+	p.Runner(p.interval).Run(ctx, p.measure)
+}
 
-	// Decode response body
-	if err = json.NewDecoder(res.Body).Decode(&resp); err != nil {
-		p.AddDataPoint(map[string]uint32{
-			PeerCountMeasurement: 0,
-		})
-		logger.WriteError(metric.ConsensusGroup, p.Name, err)
-		return
-	}
+func (p *PeerMetric) measure(ctx context.Context) {
+	// Context timeout set for the request
+	ctx, cancel := p.MeasurementContext(ctx, 5*time.Second)
+	defer cancel()
 
-	// Convert connected peer count to an integer
-	peerCount, err := strconv.Atoi(resp.Data.Connected)
+	peerCount, err := p.api.peerCount(ctx, p.url)
 	if err != nil {
 		p.AddDataPoint(map[string]uint32{
 			PeerCountMeasurement: 0,
@@ -107,61 +73,22 @@ func (p *PeerMetric) measure(ctx context.Context) {
 	}
 
 	// Record the peer count metric
-	p.writeMetric(peerCount)
-}
-
-func (p *PeerMetric) logErrorResponse(res *http.Response) {
-	var responseString string
-	if res.Header.Get("Content-Type") == "application/json" {
-		var errorResponse any
-		if err := json.NewDecoder(res.Body).Decode(&errorResponse); err != nil {
-			logger.WriteError(
-				metric.ConsensusGroup,
-				p.Name,
-				errors.Join(err, fmt.Errorf("received unsuccessful status code. Code: '%s'. Failed to JSON decode response", res.Status)))
-			return
-		}
-		jsonErrResponse, err := json.Marshal(errorResponse)
-		if err != nil {
-			logger.WriteError(
-				metric.ConsensusGroup,
-				p.Name,
-				errors.Join(err, fmt.Errorf("received unsuccessful status code. Code: '%s'. Failed to marshal response", res.Status)))
-			return
-		}
-		responseString = string(jsonErrResponse)
-	} else {
-		body, err := io.ReadAll(res.Body)
-		if err != nil {
-			logger.WriteError(
-				metric.ConsensusGroup,
-				p.Name,
-				errors.Join(err, fmt.Errorf("received unsuccessful status code. Code: '%s'. Failed to decode response", res.Status)))
-			return
-		}
-		responseString = string(body)
-	}
-
-	logger.WriteError(
-		metric.ConsensusGroup,
-		p.Name,
-		fmt.Errorf("received unsuccessful status code. Code: '%s'. Response: '%s'", res.Status, responseString))
+	p.writeMetric(ctx, int(peerCount))
 }
 
-func (p *PeerMetric) writeMetric(peerCount int) {
+func (p *PeerMetric) writeMetric(ctx context.Context, peerCount int) {
 	p.AddDataPoint(map[string]uint32{
 		PeerCountMeasurement: uint32(peerCount),
 	})
 
-	// Assuming Prometheus metric tracking
-	peerCountMetric.Set(float64(peerCount))
+	exporter.SetPeers(ctx, metric.ConsensusGroup, p.url, float64(peerCount))
 
 	logger.WriteMetric(metric.ConsensusGroup, p.Name, map[string]any{PeerCountMeasurement: peerCount})
 }
 
 func (p *PeerMetric) AggregateResults() string {
 	var values []uint32
-	for _, point := range p.DataPoints {
+	for _, point := range p.Snapshot() {
 		values = append(values, point.Values[PeerCountMeasurement])
 	}
 
@@ -0,0 +1,176 @@
+package consensus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/clock"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/httpclient"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/logger"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/metric"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/network"
+)
+
+const (
+	ProductionDurationMeasurement = "ProductionMs"
+	ProductionFailedMeasurement   = "ProductionFailed"
+	BlindedMeasurement            = "Blinded"
+
+	// infinityRandaoReveal is the compressed G2 point at infinity, accepted together with 'skip_randao_verification'
+	infinityRandaoReveal = "0xc00000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000"
+	// productionOffset requests the block right after the slot started, before the actual proposer publishes its block
+	productionOffset = time.Millisecond * 100
+)
+
+type (
+	// ProposalMetric requests an unsigned block from the beacon node every interval, the way a validator client
+	// does when proposing, without ever signing or publishing it. It verifies the node could propose and whether
+	// the payload came from a builder (blinded) or from the local execution client.
+	ProposalMetric struct {
+		metric.Base[float64]
+		url      string
+		spec     network.Spec
+		interval time.Duration
+		produced []proposalResult
+		mutex    sync.Mutex
+	}
+
+	proposalResult struct {
+		duration time.Duration
+		blinded  bool
+		err      error
+	}
+)
+
+func NewProposalMetric(url, name string, spec network.Spec, interval time.Duration, healthCondition []metric.HealthCondition[float64]) *ProposalMetric {
+	return &ProposalMetric{
+		Base: metric.Base[float64]{
+			HealthConditions: healthCondition,
+			Name:             name,
+		},
+		url:      url,
+		spec:     spec,
+		interval: interval,
+	}
+}
+
+func (p *ProposalMetric) Measure(ctx context.Context) {
+	slotsPerInterval := max(phase0.Slot(p.interval/p.spec.SlotDuration), 1)
+	slot := currentSlot(p.spec)
+	for {
+		slot += slotsPerInterval
+		slotStart := time.After(clock.Until(slotTime(p.spec, slot).Add(productionOffset)))
+		select {
+		case <-slotStart:
+			p.measure(ctx, slot)
+		case <-ctx.Done():
+			slog.With("metric_name", p.Name).Debug("metric was stopped")
+			return
+		}
+	}
+}
+
+func (p *ProposalMetric) measure(ctx context.Context, slot phase0.Slot) {
+	// A block has to be produced well within the first third of the slot to be attested to
+	ctx, cancel := context.WithTimeout(ctx, p.spec.AttestationDeadline())
+	defer cancel()
+
+	start := clock.Now()
+	blinded, err := p.produceBlock(ctx, slot)
+	result := proposalResult{duration: clock.Since(start), blinded: blinded, err: err}
+
+	p.mutex.Lock()
+	p.produced = append(p.produced, result)
+	p.mutex.Unlock()
+
+	if err != nil {
+		p.AddDataPoint(map[string]float64{
+			ProductionFailedMeasurement: 1,
+		})
+		logger.WriteError(metric.ValidatorGroup, p.Name, errors.Join(err, fmt.Errorf("failed producing block for slot %d", slot)))
+		return
+	}
+
+	values := map[string]float64{
+		ProductionDurationMeasurement: float64(result.duration.Milliseconds()),
+		ProductionFailedMeasurement:   0,
+		BlindedMeasurement:            0,
+	}
+	if blinded {
+		values[BlindedMeasurement] = 1
+	}
+	p.AddDataPoint(values)
+
+	logger.WriteMetric(metric.ValidatorGroup, p.Name, map[string]any{
+		ProductionDurationMeasurement: result.duration.Milliseconds(),
+		BlindedMeasurement:            blinded,
+		"Slot":                        slot,
+	})
+}
+
+// produceBlock requests the block and reports whether its payload is blinded, i.e. provided by a builder
+func (p *ProposalMetric) produceBlock(ctx context.Context, slot phase0.Slot) (bool, error) {
+	url := fmt.Sprintf("%s/eth/v3/validator/blocks/%d?randao_reveal=%s&skip_randao_verification", p.url, slot, infinityRandaoReveal)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	res, err := httpclient.Default.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return false, responseError(res)
+	}
+	// The block is only produced, reading it completes the measurement
+	if _, err := io.Copy(io.Discard, res.Body); err != nil {
+		return false, err
+	}
+
+	return strings.EqualFold(res.Header.Get("Eth-Execution-Payload-Blinded"), "true"), nil
+}
+
+func (p *ProposalMetric) AggregateResults() string {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	var (
+		durations       []time.Duration
+		failed, builder int
+		lastErr         error
+	)
+	for _, result := range p.produced {
+		if result.err != nil {
+			failed++
+			lastErr = result.err
+			continue
+		}
+		durations = append(durations, result.duration)
+		if result.blinded {
+			builder++
+		}
+	}
+
+	summary := fmt.Sprintf("Produced: %d/%d, Builder: %d, Local: %d", len(durations), len(p.produced), builder, len(durations)-builder)
+	if len(durations) != 0 {
+		percentiles := metric.CalculatePercentiles(durations, 0, 10, 50, 90, 100)
+		summary = metric.FormatPercentiles(percentiles[0], percentiles[10], percentiles[50], percentiles[90], percentiles[100]) + " \n " + summary
+	}
+	if lastErr != nil {
+		summary += fmt.Sprintf(" \n Failed: %d, last error: %s", failed, lastErr.Error())
+	}
+	return summary
+}
@@ -0,0 +1,63 @@
+package consensus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/network"
+)
+
+// FetchSpec reads the timing of the chain from the spec and genesis of the node. Values missing from the spec keep
+// the ones of the fallback.
+func FetchSpec(ctx context.Context, url string, fallback network.Spec) (network.Spec, error) {
+	spec := fallback
+
+	var specResp struct {
+		Data map[string]any `json:"data"`
+	}
+	if err := getJSON(ctx, fmt.Sprintf("%s/eth/v1/config/spec", url), &specResp); err != nil {
+		return fallback, errors.Join(err, errors.New("failed fetching spec"))
+	}
+	if value, ok := specUint(specResp.Data, "SLOT_DURATION_MS"); ok {
+		spec.SlotDuration = time.Duration(value) * time.Millisecond
+	} else if value, ok := specUint(specResp.Data, "SECONDS_PER_SLOT"); ok {
+		spec.SlotDuration = time.Duration(value) * time.Second
+	}
+	if value, ok := specUint(specResp.Data, "SLOTS_PER_EPOCH"); ok {
+		spec.SlotsPerEpoch = value
+	}
+	if value, ok := specUint(specResp.Data, "EPOCHS_PER_SYNC_COMMITTEE_PERIOD"); ok {
+		spec.EpochsPerSyncCommitteePeriod = value
+	}
+	if value, ok := specUint(specResp.Data, "DEPOSIT_CHAIN_ID"); ok {
+		spec.DepositChainID = value
+	}
+	if spec.SlotDuration == 0 || spec.SlotsPerEpoch == 0 || spec.EpochsPerSyncCommitteePeriod == 0 {
+		return fallback, errors.New("spec has zero slot duration, slots per epoch or sync committee period")
+	}
+
+	var genesisResp struct {
+		Data struct {
+			GenesisTime flexibleUint `json:"genesis_time"`
+		} `json:"data"`
+	}
+	if err := getJSON(ctx, fmt.Sprintf("%s/eth/v1/beacon/genesis", url), &genesisResp); err != nil {
+		return fallback, errors.Join(err, errors.New("failed fetching genesis"))
+	}
+	spec.GenesisTime = time.Unix(int64(genesisResp.Data.GenesisTime), 0)
+
+	return spec, nil
+}
+
+// specUint reads a number of the spec, which encodes them as strings
+func specUint(data map[string]any, key string) (uint64, bool) {
+	value, ok := data[key].(string)
+	if !ok {
+		return 0, false
+	}
+	number, err := strconv.ParseUint(value, 10, 64)
+	return number, err == nil
+}
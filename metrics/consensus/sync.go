@@ -0,0 +1,148 @@
+package consensus
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/clock"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/format"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/logger"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/metric"
+)
+
+const (
+	SyncDistanceMeasurement = "SyncDistance"
+	OptimisticMeasurement   = "Optimistic"
+	ELOfflineMeasurement    = "ELOffline"
+)
+
+type (
+	// SyncMetric tracks the distance of the beacon node head to the current slot and estimates when a syncing node catches up
+	SyncMetric struct {
+		metric.Base[uint64]
+		url      string
+		interval time.Duration
+		samples  []syncSample
+		last     syncStatus
+		mutex    sync.Mutex
+	}
+
+	syncStatus struct {
+		HeadSlot     flexibleUint `json:"head_slot"`
+		SyncDistance flexibleUint `json:"sync_distance"`
+		IsSyncing    bool         `json:"is_syncing"`
+		IsOptimistic bool         `json:"is_optimistic"`
+		ELOffline    bool         `json:"el_offline"`
+	}
+
+	syncSample struct {
+		at       time.Time
+		distance uint64
+	}
+)
+
+func NewSyncMetric(url, name string, interval time.Duration, healthCondition []metric.HealthCondition[uint64]) *SyncMetric {
+	return &SyncMetric{
+		Base: metric.Base[uint64]{
+			HealthConditions: healthCondition,
+			Name:             name,
+		},
+		url:      url,
+		interval: interval,
+	}
+}
+
+func (s *SyncMetric) Measure(ctx context.Context) {
+	s.Runner(s.interval).Run(ctx, s.measure)
+}
+
+func (s *SyncMetric) measure(ctx context.Context) {
+	ctx, cancel := s.MeasurementContext(ctx, 5*time.Second)
+	defer cancel()
+
+	var resp struct {
+		Data syncStatus `json:"data"`
+	}
+	if err := getJSON(ctx, fmt.Sprintf("%s/eth/v1/node/syncing", s.url), &resp); err != nil {
+		logger.WriteError(metric.ConsensusGroup, s.Name, err)
+		return
+	}
+
+	s.writeMetric(resp.Data, clock.Now())
+}
+
+func (s *SyncMetric) writeMetric(status syncStatus, at time.Time) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.last = status
+	if status.IsSyncing {
+		s.samples = append(s.samples, syncSample{at: at, distance: uint64(status.SyncDistance)})
+	} else {
+		// A synced node starts a new estimation should it fall behind again
+		s.samples = nil
+	}
+
+	s.AddDataPoint(map[string]uint64{
+		SyncDistanceMeasurement: uint64(status.SyncDistance),
+		OptimisticMeasurement:   boolToUint(status.IsOptimistic),
+		ELOfflineMeasurement:    boolToUint(status.ELOffline),
+	})
+
+	values := map[string]any{
+		SyncDistanceMeasurement: uint64(status.SyncDistance),
+		OptimisticMeasurement:   status.IsOptimistic,
+		ELOfflineMeasurement:    status.ELOffline,
+	}
+	if eta, ok := estimateTimeToSync(s.samples); ok {
+		values["ETA"] = eta.String()
+	}
+	logger.WriteMetric(metric.ConsensusGroup, s.Name, values)
+}
+
+// estimateTimeToSync extrapolates the rate at which the sync distance shrank since the node started syncing.
+// The distance already accounts for new slots, so no estimation is possible while it doesn't shrink.
+func estimateTimeToSync(samples []syncSample) (time.Duration, bool) {
+	if len(samples) < 2 {
+		return 0, false
+	}
+	first, last := samples[0], samples[len(samples)-1]
+	if last.distance >= first.distance || !last.at.After(first.at) {
+		return 0, false
+	}
+
+	rate := float64(first.distance-last.distance) / last.at.Sub(first.at).Seconds()
+	return time.Duration(float64(last.distance) / rate * float64(time.Second)).Round(time.Second), true
+}
+
+func boolToUint(value bool) uint64 {
+	if value {
+		return 1
+	}
+	return 0
+}
+
+func (s *SyncMetric) AggregateResults() string {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	var values []uint64
+	for _, point := range s.Snapshot() {
+		values = append(values, point.Values[SyncDistanceMeasurement])
+	}
+	percentiles := metric.CalculatePercentiles(values, 0, 10, 50, 90, 100)
+
+	state := "synced"
+	if s.last.IsSyncing {
+		state = "syncing"
+		if eta, ok := estimateTimeToSync(s.samples); ok {
+			state = fmt.Sprintf("syncing, ETA=%s", format.Duration(eta))
+		}
+	}
+
+	return fmt.Sprintf("%s \n Status: %s, Optimistic: %t, EL Offline: %t",
+		metric.FormatPercentiles(percentiles[0], percentiles[10], percentiles[50], percentiles[90], percentiles[100]),
+		state, s.last.IsOptimistic, s.last.ELOffline)
+}
@@ -0,0 +1,236 @@
+package consensus
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/clock"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/format"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/logger"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/metric"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/network"
+)
+
+const (
+	SyncCommitteeInclusionMeasurement  = "SyncCommitteeInclusion"
+	SyncCommitteeMissStreakMeasurement = "SyncCommitteeMissStreak"
+)
+
+type (
+	// SyncCommitteeMetric checks every block for the sync committee messages of the configured validators which are
+	// members of the current sync committee. The sync aggregate of a block covers the messages of the previous slot.
+	SyncCommitteeMetric struct {
+		metric.Base[float64]
+		url     string
+		spec    network.Spec
+		indices []uint64
+		period  uint64
+		// positions maps the committee members among the configured validators to their positions in the committee
+		positions map[uint64][]int
+		results   map[uint64]*syncCommitteeResult
+		mutex     sync.Mutex
+	}
+
+	syncCommitteeResult struct {
+		expected, included, missStreak int
+	}
+)
+
+func NewSyncCommitteeMetric(url, name string, spec network.Spec, indices []uint64, healthCondition []metric.HealthCondition[float64]) *SyncCommitteeMetric {
+	return &SyncCommitteeMetric{
+		Base: metric.Base[float64]{
+			HealthConditions: healthCondition,
+			Name:             name,
+		},
+		url:     url,
+		spec:    spec,
+		indices: indices,
+		period:  ^uint64(0),
+		results: make(map[uint64]*syncCommitteeResult),
+	}
+}
+
+func (s *SyncCommitteeMetric) Measure(ctx context.Context) {
+	slot := currentSlot(s.spec)
+	for {
+		slot++
+		blockTime := time.After(clock.Until(slotTime(s.spec, slot).Add(s.spec.AttestationDeadline())))
+		select {
+		case <-blockTime:
+			s.measure(ctx, slot)
+		case <-ctx.Done():
+			slog.With("metric_name", s.Name).Debug("metric was stopped")
+			return
+		}
+	}
+}
+
+func (s *SyncCommitteeMetric) measure(ctx context.Context, slot phase0.Slot) {
+	ctx, cancel := s.MeasurementContext(ctx, 5*time.Second)
+	defer cancel()
+
+	period := s.spec.SyncCommitteePeriod(s.spec.Epoch(uint64(slot)))
+	if period != s.period {
+		if err := s.fetchCommittee(ctx, s.spec.Epoch(uint64(slot))); err != nil {
+			logger.WriteError(metric.ConsensusGroup, s.Name, errors.Join(err, errors.New("failed fetching sync committee")))
+			return
+		}
+		s.period = period
+	}
+	if len(s.positions) == 0 {
+		return
+	}
+
+	var resp struct {
+		Data struct {
+			Message struct {
+				Body struct {
+					SyncAggregate struct {
+						SyncCommitteeBits string `json:"sync_committee_bits"`
+					} `json:"sync_aggregate"`
+				} `json:"body"`
+			} `json:"message"`
+		} `json:"data"`
+	}
+	err := getJSON(ctx, fmt.Sprintf("%s/eth/v2/beacon/blocks/%d", s.url, slot), &resp)
+	if errors.Is(err, errEndpointNotSupported) {
+		// The slot was missed, without a block no sync committee messages could be included
+		slog.With("metric_name", s.Name, "slot", slot).Debug("no block in slot")
+		return
+	}
+	if err != nil {
+		logger.WriteError(metric.ConsensusGroup, s.Name, err)
+		return
+	}
+
+	bits, err := hex.DecodeString(strings.TrimPrefix(resp.Data.Message.Body.SyncAggregate.SyncCommitteeBits, "0x"))
+	if err != nil {
+		logger.WriteError(metric.ConsensusGroup, s.Name, errors.Join(err, errors.New("failed decoding sync committee bits")))
+		return
+	}
+
+	s.record(bits)
+}
+
+func (s *SyncCommitteeMetric) fetchCommittee(ctx context.Context, epoch uint64) error {
+	var resp struct {
+		Data struct {
+			Validators []string `json:"validators"`
+		} `json:"data"`
+	}
+	if err := getJSON(ctx, fmt.Sprintf("%s/eth/v1/beacon/states/head/sync_committees?epoch=%d", s.url, epoch), &resp); err != nil {
+		return err
+	}
+
+	configured := make(map[uint64]struct{}, len(s.indices))
+	for _, index := range s.indices {
+		configured[index] = struct{}{}
+	}
+
+	// A validator can hold several positions in the committee
+	positions := make(map[uint64][]int)
+	for position, value := range resp.Data.Validators {
+		index, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			return err
+		}
+		if _, ok := configured[index]; ok {
+			positions[index] = append(positions[index], position)
+		}
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.positions = positions
+	slog.With("metric_name", s.Name, "epoch", epoch, "members", len(positions)).Info("sync committee fetched")
+	return nil
+}
+
+func (s *SyncCommitteeMetric) record(bits []byte) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	var expected, included float64
+	missStreak := 0
+	for index, positions := range s.positions {
+		result, ok := s.results[index]
+		if !ok {
+			result = &syncCommitteeResult{}
+			s.results[index] = result
+		}
+
+		result.expected++
+		if anyBitSet(bits, positions) {
+			result.included++
+			result.missStreak = 0
+		} else {
+			result.missStreak++
+		}
+
+		expected += float64(result.expected)
+		included += float64(result.included)
+		missStreak = max(missStreak, result.missStreak)
+	}
+
+	inclusion := included / expected * 100
+	s.AddDataPoint(map[string]float64{
+		SyncCommitteeInclusionMeasurement:  inclusion,
+		SyncCommitteeMissStreakMeasurement: float64(missStreak),
+	})
+
+	logger.WriteMetric(metric.ConsensusGroup, s.Name, map[string]any{
+		SyncCommitteeInclusionMeasurement:  inclusion,
+		SyncCommitteeMissStreakMeasurement: missStreak,
+	})
+}
+
+// isBitSet reads the bit at the position of an SSZ bitvector, whose bits are ordered from the least significant bit of each byte
+func isBitSet(bits []byte, position int) bool {
+	if position/8 >= len(bits) {
+		return false
+	}
+	return bits[position/8]&(1<<(position%8)) != 0
+}
+
+func anyBitSet(bits []byte, positions []int) bool {
+	for _, position := range positions {
+		if isBitSet(bits, position) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *SyncCommitteeMetric) AggregateResults() string {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if len(s.results) == 0 {
+		return "No configured validator was in the sync committee"
+	}
+
+	indices := make([]uint64, 0, len(s.results))
+	for index := range s.results {
+		indices = append(indices, index)
+	}
+	sort.Slice(indices, func(i, j int) bool { return indices[i] < indices[j] })
+
+	var lines []string
+	for _, index := range indices {
+		result := s.results[index]
+		lines = append(lines, fmt.Sprintf("%d: included=%d/%d (%s %%)",
+			index, result.included, result.expected, format.Number(float64(result.included)/float64(result.expected)*100, 2)))
+	}
+	return strings.Join(lines, " \n ")
+}
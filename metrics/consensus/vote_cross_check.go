@@ -0,0 +1,220 @@
+package consensus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/clock"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/logger"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/metric"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/network"
+)
+
+const (
+	VoteDivergenceMeasurement       = "VoteDivergence"
+	HeadDivergenceMeasurement       = "HeadDivergence"
+	TargetDivergenceMeasurement     = "TargetDivergence"
+	VoteDivergenceStreakMeasurement = "VoteDivergenceStreak"
+)
+
+type (
+	// Vote is the head and target an attester would vote for according to a beacon node
+	Vote struct {
+		Head        string
+		Target      string
+		TargetEpoch uint64
+	}
+
+	// VoteCrossCheckMetric requests the attestation data of every slot from several beacon nodes at the attestation
+	// deadline and reports the slots at which they disagree. A node which keeps disagreeing with the majority follows
+	// a minority fork or lags behind the head.
+	VoteCrossCheckMetric struct {
+		metric.Base[float64]
+		urls      []string
+		spec      network.Spec
+		mutex     sync.Mutex
+		compared  int
+		divergent int
+		streak    int
+		diverging map[string]int
+		lastEvent string
+	}
+)
+
+func NewVoteCrossCheckMetric(urls []string, name string, spec network.Spec, healthCondition []metric.HealthCondition[float64]) *VoteCrossCheckMetric {
+	return &VoteCrossCheckMetric{
+		Base: metric.Base[float64]{
+			HealthConditions: healthCondition,
+			Name:             name,
+		},
+		urls:      urls,
+		spec:      spec,
+		diverging: make(map[string]int, len(urls)),
+	}
+}
+
+func (v *VoteCrossCheckMetric) Measure(ctx context.Context) {
+	slot := currentSlot(v.spec)
+	for {
+		slot++
+		deadline := time.After(clock.Until(slotTime(v.spec, slot).Add(v.spec.AttestationDeadline())))
+		select {
+		case <-deadline:
+			slot := slot
+			metric.Go(ctx, v.Name, func() { v.compare(ctx, slot) })
+		case <-ctx.Done():
+			slog.With("metric_name", v.Name).Debug("metric was stopped")
+			return
+		}
+	}
+}
+
+func (v *VoteCrossCheckMetric) compare(ctx context.Context, slot phase0.Slot) {
+	// The votes are only comparable while the nodes look at the same moment of the slot
+	ctx, cancel := context.WithTimeout(ctx, v.spec.AttestationDeadline())
+	defer cancel()
+
+	votes := make(map[string]Vote, len(v.urls))
+	var (
+		wg    sync.WaitGroup
+		mutex sync.Mutex
+	)
+	for _, url := range v.urls {
+		wg.Add(1)
+		url := url
+		metric.Go(ctx, v.Name, func() {
+			defer wg.Done()
+			vote, err := FetchVote(ctx, url, slot)
+			if err != nil {
+				logger.WriteError(metric.ConsensusGroup, v.Name, errors.Join(err, fmt.Errorf("failed fetching the vote of '%s'", url)))
+				return
+			}
+			mutex.Lock()
+			votes[url] = vote
+			mutex.Unlock()
+		})
+	}
+	wg.Wait()
+
+	if len(votes) < 2 {
+		logger.WriteError(metric.ConsensusGroup, v.Name, fmt.Errorf("slot %d was not compared, less than two beacon nodes answered", slot))
+		return
+	}
+
+	majority := v.majority(votes)
+	values := map[string]float64{
+		VoteDivergenceMeasurement:   0,
+		HeadDivergenceMeasurement:   0,
+		TargetDivergenceMeasurement: 0,
+	}
+	var diverging []string
+	for _, url := range v.urls {
+		vote, ok := votes[url]
+		if !ok || vote == majority {
+			continue
+		}
+		diverging = append(diverging, url)
+		values[VoteDivergenceMeasurement] = 1
+		if vote.Head != majority.Head {
+			values[HeadDivergenceMeasurement] = 1
+		}
+		if vote.Target != majority.Target || vote.TargetEpoch != majority.TargetEpoch {
+			values[TargetDivergenceMeasurement] = 1
+		}
+	}
+
+	v.mutex.Lock()
+	v.compared++
+	if len(diverging) == 0 {
+		v.streak = 0
+	} else {
+		v.divergent++
+		v.streak++
+		for _, url := range diverging {
+			v.diverging[url]++
+		}
+		v.lastEvent = fmt.Sprintf("slot %d: %s", slot, strings.Join(diverging, ", "))
+	}
+	values[VoteDivergenceStreakMeasurement] = float64(v.streak)
+	v.mutex.Unlock()
+
+	v.AddDataPoint(values)
+	logger.WriteMetric(metric.ConsensusGroup, v.Name, map[string]any{
+		"Slot":                          slot,
+		VoteDivergenceMeasurement:       values[VoteDivergenceMeasurement] == 1,
+		HeadDivergenceMeasurement:       values[HeadDivergenceMeasurement] == 1,
+		TargetDivergenceMeasurement:     values[TargetDivergenceMeasurement] == 1,
+		VoteDivergenceStreakMeasurement: values[VoteDivergenceStreakMeasurement],
+		"DivergingNodes":                diverging,
+	})
+}
+
+// majority returns the vote shared by most nodes, ties are decided in favour of the first configured node
+func (v *VoteCrossCheckMetric) majority(votes map[string]Vote) Vote {
+	counts := make(map[Vote]int, len(votes))
+	for _, vote := range votes {
+		counts[vote]++
+	}
+
+	var (
+		result Vote
+		best   int
+	)
+	for _, url := range v.urls {
+		vote, ok := votes[url]
+		if ok && counts[vote] > best {
+			result, best = vote, counts[vote]
+		}
+	}
+	return result
+}
+
+// FetchVote requests the attestation data of the slot, the committee index is irrelevant for the head and target
+func FetchVote(ctx context.Context, url string, slot phase0.Slot) (Vote, error) {
+	var resp struct {
+		Data struct {
+			BeaconBlockRoot string `json:"beacon_block_root"`
+			Target          struct {
+				Epoch flexibleUint `json:"epoch"`
+				Root  string       `json:"root"`
+			} `json:"target"`
+		} `json:"data"`
+	}
+	if err := getJSON(ctx, fmt.Sprintf("%s/eth/v1/validator/attestation_data?slot=%d&committee_index=0", url, slot), &resp); err != nil {
+		return Vote{}, err
+	}
+	return Vote{
+		Head:        strings.ToLower(resp.Data.BeaconBlockRoot),
+		Target:      strings.ToLower(resp.Data.Target.Root),
+		TargetEpoch: uint64(resp.Data.Target.Epoch),
+	}, nil
+}
+
+func (v *VoteCrossCheckMetric) AggregateResults() string {
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+
+	var rate float64
+	if v.compared != 0 {
+		rate = float64(v.divergent) / float64(v.compared) * 100
+	}
+
+	nodes := make([]string, 0, len(v.urls))
+	for _, url := range v.urls {
+		nodes = append(nodes, fmt.Sprintf("%s=%d", url, v.diverging[url]))
+	}
+
+	result := fmt.Sprintf("compared_slots=%d, divergent_slots=%d (%.2f %%) \n diverging: %s",
+		v.compared, v.divergent, rate, strings.Join(nodes, ", "))
+	if v.lastEvent != "" {
+		result += fmt.Sprintf(" \n last divergence at %s", v.lastEvent)
+	}
+	return result
+}
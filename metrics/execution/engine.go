@@ -0,0 +1,342 @@
+package execution
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/clock"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/logger"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/metric"
+)
+
+const (
+	ExchangeCapabilitiesDurationMeasurement = "ExchangeCapabilitiesMs"
+	NewPayloadDurationMeasurement           = "NewPayloadMs"
+	CapabilitiesFailedMeasurement           = "CapabilitiesFailed"
+	MissingCapabilitiesMeasurement          = "MissingCapabilities"
+)
+
+// requiredCapabilities are the Engine API methods a consensus client relies on during block proposals
+var requiredCapabilities = []string{
+	"engine_forkchoiceUpdatedV3",
+	"engine_newPayloadV3",
+	"engine_getPayloadV3",
+}
+
+// emptyRequestsHash is the requests hash of blocks without execution requests, the sha256 of no requests
+const emptyRequestsHash = "0xe3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+
+// errRequestsUnknown skips the blocks carrying execution requests, only the consensus client has them
+var errRequestsUnknown = errors.New("execution requests of the block are only known to the consensus client")
+
+type (
+	EngineMetric struct {
+		metric.Base[float64]
+		url      string
+		secret   []byte
+		interval time.Duration
+		simulate bool
+	}
+
+	// executionBlock is a block along with its transactions as returned by eth_getBlockByNumber, the quantities are
+	// kept hex encoded as the Engine API takes them
+	executionBlock struct {
+		ParentHash    string             `json:"parentHash"`
+		Miner         string             `json:"miner"`
+		StateRoot     string             `json:"stateRoot"`
+		ReceiptsRoot  string             `json:"receiptsRoot"`
+		LogsBloom     string             `json:"logsBloom"`
+		MixHash       string             `json:"mixHash"`
+		Number        string             `json:"number"`
+		GasLimit      string             `json:"gasLimit"`
+		GasUsed       string             `json:"gasUsed"`
+		Timestamp     string             `json:"timestamp"`
+		ExtraData     string             `json:"extraData"`
+		BaseFeePerGas string             `json:"baseFeePerGas"`
+		Hash          string             `json:"hash"`
+		Transactions  []blockTransaction `json:"transactions"`
+		// Withdrawals are encoded the same way by the Engine API
+		Withdrawals           json.RawMessage `json:"withdrawals"`
+		BlobGasUsed           string          `json:"blobGasUsed"`
+		ExcessBlobGas         string          `json:"excessBlobGas"`
+		ParentBeaconBlockRoot string          `json:"parentBeaconBlockRoot"`
+		RequestsHash          string          `json:"requestsHash"`
+	}
+
+	blockTransaction struct {
+		Hash                string   `json:"hash"`
+		BlobVersionedHashes []string `json:"blobVersionedHashes"`
+	}
+)
+
+func NewEngineMetric(url, jwtSecretPath, name string, interval time.Duration, healthCondition []metric.HealthCondition[float64]) (*EngineMetric, error) {
+	secret, err := readJWTSecret(jwtSecretPath)
+	if err != nil {
+		return nil, errors.Join(err, errors.New("failed reading Engine API JWT secret"))
+	}
+
+	return &EngineMetric{
+		url:    url,
+		secret: secret,
+		Base: metric.Base[float64]{
+			HealthConditions: healthCondition,
+			Name:             name,
+		},
+		interval: interval,
+	}, nil
+}
+
+// WithSimulation enables the consensus-simulating mode which re-submits the payload of the latest block via
+// engine_newPayloadV3 to measure the latency of the call a consensus client makes for every block. The execution client
+// knows the block already, so it only validates it again without changing its state. No forkchoice update is sent,
+// unlike the blocks of a payload the fork choice of the consensus client can't be read from the execution client.
+func (e *EngineMetric) WithSimulation() *EngineMetric {
+	e.simulate = true
+	return e
+}
+
+func (e *EngineMetric) Measure(ctx context.Context) {
+	e.Runner(e.interval).Run(ctx, e.measure)
+}
+
+// Probe runs a single measurement without recording it, a new payload is only submitted when it is simulated
+func (e *EngineMetric) Probe(ctx context.Context) error {
+	ctx, cancel := e.MeasurementContext(ctx, 5*time.Second)
+	defer cancel()
+
+	token, err := e.token()
+	if err != nil {
+		return err
+	}
+	var capabilities []string
+	if err := callAuthenticatedRPC(ctx, e.url, token, "engine_exchangeCapabilities", &capabilities, requiredCapabilities); err != nil {
+		return err
+	}
+	if !e.simulate {
+		return nil
+	}
+	if _, err := e.measureNewPayload(ctx, token); err != nil && !errors.Is(err, errRequestsUnknown) {
+		return err
+	}
+	return nil
+}
+
+func (e *EngineMetric) measure(ctx context.Context) {
+	ctx, cancel := e.MeasurementContext(ctx, 5*time.Second)
+	defer cancel()
+
+	token, err := e.token()
+	if err != nil {
+		logger.WriteError(metric.ExecutionGroup, e.Name, err)
+		return
+	}
+
+	var capabilities []string
+	start := clock.Now()
+	if err := callAuthenticatedRPC(ctx, e.url, token, "engine_exchangeCapabilities", &capabilities, requiredCapabilities); err != nil {
+		e.writeMetric(map[string]float64{
+			CapabilitiesFailedMeasurement: 1,
+		})
+		logger.WriteError(metric.ExecutionGroup, e.Name, err)
+		return
+	}
+	values := map[string]float64{
+		ExchangeCapabilitiesDurationMeasurement: float64(clock.Since(start).Milliseconds()),
+		MissingCapabilitiesMeasurement:          float64(len(missingCapabilities(capabilities))),
+	}
+
+	if e.simulate {
+		duration, err := e.measureNewPayload(ctx, token)
+		switch {
+		case errors.Is(err, errRequestsUnknown):
+			slog.With("metric_name", e.Name).Debug("new payload skipped, the block has execution requests")
+		case err != nil:
+			logger.WriteError(metric.ExecutionGroup, e.Name, err)
+		default:
+			values[NewPayloadDurationMeasurement] = float64(duration.Milliseconds())
+		}
+	}
+
+	e.writeMetric(values)
+}
+
+// measureNewPayload re-submits the payload of the latest block, the call is idempotent for a block the execution client
+// knows already
+func (e *EngineMetric) measureNewPayload(ctx context.Context, token string) (time.Duration, error) {
+	var block executionBlock
+	if err := callAuthenticatedRPC(ctx, e.url, token, "eth_getBlockByNumber", &block, "latest", true); err != nil {
+		return 0, errors.Join(err, errors.New("failed fetching 'latest' block"))
+	}
+	method, params, err := e.newPayloadParams(ctx, token, block)
+	if err != nil {
+		return 0, err
+	}
+
+	var resp struct {
+		Status          string  `json:"status"`
+		ValidationError *string `json:"validationError"`
+	}
+	start := clock.Now()
+	if err := callAuthenticatedRPC(ctx, e.url, token, method, &resp, params...); err != nil {
+		return 0, err
+	}
+	duration := clock.Since(start)
+
+	if resp.Status != "VALID" {
+		if resp.ValidationError != nil {
+			return 0, fmt.Errorf("%s returned '%s' payload status: '%s'", method, resp.Status, *resp.ValidationError)
+		}
+		return 0, fmt.Errorf("%s returned '%s' payload status", method, resp.Status)
+	}
+	return duration, nil
+}
+
+// newPayloadParams builds the engine_newPayload call of the block, its raw transactions are fetched in one batch. The
+// blocks of Prague and later carry execution requests, they are sent through engine_newPayloadV4 when there are none.
+func (e *EngineMetric) newPayloadParams(ctx context.Context, token string, block executionBlock) (string, []any, error) {
+	method := "engine_newPayloadV3"
+	var requests []any
+	if block.RequestsHash != "" {
+		if !strings.EqualFold(block.RequestsHash, emptyRequestsHash) {
+			return "", nil, errRequestsUnknown
+		}
+		method, requests = "engine_newPayloadV4", []any{[]string{}}
+	}
+
+	transactions := make([]string, len(block.Transactions))
+	blobHashes := []string{}
+	calls := make([]*rpcCall, 0, len(block.Transactions))
+	for i, transaction := range block.Transactions {
+		calls = append(calls, &rpcCall{Method: "eth_getRawTransactionByHash", Params: []any{transaction.Hash}, Result: &transactions[i]})
+		blobHashes = append(blobHashes, transaction.BlobVersionedHashes...)
+	}
+	if len(calls) != 0 {
+		if err := callAuthenticatedBatch(ctx, e.url, token, calls); err != nil {
+			return "", nil, errors.Join(err, errors.New("failed fetching the raw transactions of the block"))
+		}
+		for i, call := range calls {
+			if call.Err != nil {
+				return "", nil, errors.Join(call.Err, fmt.Errorf("failed fetching raw transaction '%s'", block.Transactions[i].Hash))
+			}
+		}
+	}
+
+	withdrawals := block.Withdrawals
+	if len(withdrawals) == 0 {
+		withdrawals = json.RawMessage("[]")
+	}
+	payload := map[string]any{
+		"parentHash":    block.ParentHash,
+		"feeRecipient":  block.Miner,
+		"stateRoot":     block.StateRoot,
+		"receiptsRoot":  block.ReceiptsRoot,
+		"logsBloom":     block.LogsBloom,
+		"prevRandao":    block.MixHash,
+		"blockNumber":   block.Number,
+		"gasLimit":      block.GasLimit,
+		"gasUsed":       block.GasUsed,
+		"timestamp":     block.Timestamp,
+		"extraData":     block.ExtraData,
+		"baseFeePerGas": block.BaseFeePerGas,
+		"blockHash":     block.Hash,
+		"transactions":  transactions,
+		"withdrawals":   withdrawals,
+		"blobGasUsed":   block.BlobGasUsed,
+		"excessBlobGas": block.ExcessBlobGas,
+	}
+	return method, append([]any{payload, blobHashes, block.ParentBeaconBlockRoot}, requests...), nil
+}
+
+func (e *EngineMetric) writeMetric(values map[string]float64) {
+	e.AddDataPoint(values)
+
+	logValues := make(map[string]any, len(values))
+	for name, value := range values {
+		logValues[name] = value
+	}
+	logger.WriteMetric(metric.ExecutionGroup, e.Name, logValues)
+}
+
+func (e *EngineMetric) AggregateResults() string {
+	var (
+		failures, missing                     float64
+		capabilityDurations, payloadDurations []float64
+	)
+
+	for _, point := range e.Snapshot() {
+		failures += point.Values[CapabilitiesFailedMeasurement]
+		if value, ok := point.Values[ExchangeCapabilitiesDurationMeasurement]; ok {
+			capabilityDurations = append(capabilityDurations, value)
+			missing = point.Values[MissingCapabilitiesMeasurement]
+		}
+		if value, ok := point.Values[NewPayloadDurationMeasurement]; ok {
+			payloadDurations = append(payloadDurations, value)
+		}
+	}
+
+	capabilities := metric.CalculatePercentiles(capabilityDurations, 50, 90)
+	result := fmt.Sprintf("capabilities_failed=%.0f, missing_capabilities=%.0f, exchange_capabilities_P50=%.0fms, exchange_capabilities_P90=%.0fms",
+		failures, missing, capabilities[50], capabilities[90])
+
+	if e.simulate {
+		payload := metric.CalculatePercentiles(payloadDurations, 50, 90)
+		result += fmt.Sprintf(" \n new_payload_P50=%.0fms, new_payload_P90=%.0fms", payload[50], payload[90])
+	}
+
+	return result
+}
+
+// token creates a JWT as required by the Engine API authentication spec, which only mandates the 'iat' claim
+func (e *EngineMetric) token() (string, error) {
+	header := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`))
+	claims, err := json.Marshal(map[string]int64{"iat": time.Now().Unix()})
+	if err != nil {
+		return "", err
+	}
+	unsigned := header + "." + base64.RawURLEncoding.EncodeToString(claims)
+
+	mac := hmac.New(sha256.New, e.secret)
+	mac.Write([]byte(unsigned))
+
+	return unsigned + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil)), nil
+}
+
+func readJWTSecret(path string) ([]byte, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	secret, err := hex.DecodeString(strings.TrimPrefix(strings.TrimSpace(string(content)), "0x"))
+	if err != nil {
+		return nil, errors.Join(err, errors.New("JWT secret should be hex encoded"))
+	}
+	if len(secret) != 32 {
+		return nil, fmt.Errorf("JWT secret should be 32 bytes long, got %d bytes", len(secret))
+	}
+	return secret, nil
+}
+
+func missingCapabilities(capabilities []string) []string {
+	supported := make(map[string]struct{}, len(capabilities))
+	for _, capability := range capabilities {
+		supported[capability] = struct{}{}
+	}
+
+	var missing []string
+	for _, capability := range requiredCapabilities {
+		if _, ok := supported[capability]; !ok {
+			missing = append(missing, capability)
+		}
+	}
+	return missing
+}
@@ -0,0 +1,80 @@
+package execution
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/network"
+	"github.com/Harikakasimahanthi/benchmark-test/testutil/mocknode"
+)
+
+func newEngineMetric(t *testing.T, url string) *EngineMetric {
+	jwtSecretPath := filepath.Join(t.TempDir(), "jwt.hex")
+	require.NoError(t, os.WriteFile(jwtSecretPath, []byte("0x0000000000000000000000000000000000000000000000000000000000000000"), 0o600))
+
+	engine, err := NewEngineMetric(url, jwtSecretPath, "Engine API", time.Second, nil)
+	require.NoError(t, err)
+	return engine.WithSimulation()
+}
+
+func TestGivenSimulationWhenMeasuredThenLatestPayloadResubmitted(t *testing.T) {
+	node := mocknode.New(network.Mainnet).Start()
+	defer node.Close()
+	engine := newEngineMetric(t, node.ExecutionURL())
+
+	engine.measure(context.Background())
+
+	latest, ok := engine.Latest()
+	require.True(t, ok)
+	assert.Contains(t, latest.Values, NewPayloadDurationMeasurement)
+	assert.Zero(t, latest.Values[MissingCapabilitiesMeasurement])
+}
+
+func TestGivenBlockWhenNewPayloadParamsThenCallOfItsFork(t *testing.T) {
+	node := mocknode.New(network.Mainnet).Start()
+	defer node.Close()
+	engine := newEngineMetric(t, node.ExecutionURL())
+	token, err := engine.token()
+	require.NoError(t, err)
+
+	tests := []struct {
+		name         string
+		requestsHash string
+		wantMethod   string
+		wantParams   int
+		wantErr      error
+	}{
+		{name: "block before Prague", wantMethod: "engine_newPayloadV3", wantParams: 3},
+		{name: "block without requests", requestsHash: emptyRequestsHash, wantMethod: "engine_newPayloadV4", wantParams: 4},
+		{name: "block with requests", requestsHash: "0x01", wantErr: errRequestsUnknown},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			block := executionBlock{
+				Hash:         "0xbb",
+				Transactions: []blockTransaction{{Hash: "0xaa", BlobVersionedHashes: []string{"0x01ff"}}},
+				RequestsHash: test.requestsHash,
+			}
+
+			method, params, err := engine.newPayloadParams(context.Background(), token, block)
+
+			if test.wantErr != nil {
+				assert.ErrorIs(t, err, test.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.wantMethod, method)
+			require.Len(t, params, test.wantParams)
+			payload := params[0].(map[string]any)
+			assert.Equal(t, "0xbb", payload["blockHash"])
+			assert.Equal(t, []string{mocknode.RawTransaction}, payload["transactions"])
+			assert.Equal(t, []string{"0x01ff"}, params[1])
+		})
+	}
+}
@@ -0,0 +1,180 @@
+package execution
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/logger"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/metric"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/portcheck"
+)
+
+const (
+	InboundPeersMeasurement     = "InboundPeers"
+	InboundReachableMeasurement = "InboundReachable"
+	PrivateEnodeMeasurement     = "PrivateEnode"
+)
+
+type (
+	// InboundMetric determines whether the P2P port of the execution client (default 30303) is reachable from
+	// outside. The node is reachable when an external probe service reaches the address announced in its enode, or
+	// without probe service when it has inbound peers. It needs the admin RPC namespace.
+	InboundMetric struct {
+		metric.Base[float64]
+		url string
+		// probeURL is requested with '{host}' and '{port}' replaced, a 2xx status means the port is reachable
+		probeURL string
+		// defaultPort is probed when the node info has no listener port, zero when the client is unknown
+		defaultPort uint16
+		interval    time.Duration
+		last        inboundResult
+		mutex       sync.Mutex
+	}
+
+	inboundResult struct {
+		ip           net.IP
+		port         uint16
+		inboundPeers int
+		reachable    bool
+		probed       bool
+	}
+
+	// nodeInfo is the admin_nodeInfo response, the IP is the one announced in the enode
+	nodeInfo struct {
+		IP    string `json:"ip"`
+		Ports struct {
+			Listener uint16 `json:"listener"`
+		} `json:"ports"`
+	}
+)
+
+func NewInboundMetric(url, probeURL, name string, interval time.Duration, healthCondition []metric.HealthCondition[float64]) *InboundMetric {
+	return &InboundMetric{
+		Base: metric.Base[float64]{
+			HealthConditions: healthCondition,
+			Name:             name,
+		},
+		url:      url,
+		probeURL: probeURL,
+		interval: interval,
+	}
+}
+
+// WithDefaultPort probes the default P2P port of the client when its node info has no listener port
+func (i *InboundMetric) WithDefaultPort(port uint16) *InboundMetric {
+	i.defaultPort = port
+	return i
+}
+
+func (i *InboundMetric) Measure(ctx context.Context) {
+	i.Runner(i.interval).Run(ctx, i.measure)
+}
+
+// Probe runs a single measurement without recording it, the external probe service isn't asked
+func (i *InboundMetric) Probe(ctx context.Context) error {
+	ctx, cancel := i.MeasurementContext(ctx, 10*time.Second)
+	defer cancel()
+
+	_, _, err := i.fetch(ctx)
+	return err
+}
+
+func (i *InboundMetric) measure(ctx context.Context) {
+	ctx, cancel := i.MeasurementContext(ctx, 10*time.Second)
+	defer cancel()
+
+	info, peers, err := i.fetch(ctx)
+	if err != nil {
+		logger.WriteError(metric.ExecutionGroup, i.Name, err)
+		return
+	}
+
+	result := inboundResult{ip: net.ParseIP(info.IP), port: info.Ports.Listener}
+	if result.port == 0 {
+		result.port = i.defaultPort
+	}
+	for _, peer := range peers {
+		if peer.Network.Inbound {
+			result.inboundPeers++
+		}
+	}
+	result.reachable = result.inboundPeers != 0
+	if i.probeURL != "" && portcheck.IsPublic(result.ip) && result.port != 0 {
+		reachable, err := portcheck.Probe(ctx, i.probeURL, result.ip, result.port)
+		if err != nil {
+			logger.WriteError(metric.ExecutionGroup, i.Name, errors.Join(err, errors.New("failed probing the P2P port")))
+			return
+		}
+		result.reachable, result.probed = reachable, true
+	}
+
+	i.mutex.Lock()
+	i.last = result
+	i.mutex.Unlock()
+
+	values := map[string]float64{
+		InboundPeersMeasurement:     float64(result.inboundPeers),
+		InboundReachableMeasurement: 0,
+		PrivateEnodeMeasurement:     0,
+	}
+	if result.reachable {
+		values[InboundReachableMeasurement] = 1
+	}
+	if !portcheck.IsPublic(result.ip) {
+		values[PrivateEnodeMeasurement] = 1
+	}
+	i.AddDataPoint(values)
+
+	logger.WriteMetric(metric.ExecutionGroup, i.Name, map[string]any{
+		InboundPeersMeasurement:     result.inboundPeers,
+		InboundReachableMeasurement: result.reachable,
+		"EnodeAddress":              portcheck.Address(result.ip, result.port),
+	})
+}
+
+// fetch returns the node info and the peers of the client in a single batch
+func (i *InboundMetric) fetch(ctx context.Context) (nodeInfo, []adminPeer, error) {
+	var (
+		info  nodeInfo
+		peers []adminPeer
+	)
+	infoCall := &rpcCall{Method: "admin_nodeInfo", Result: &info}
+	peersCall := &rpcCall{Method: "admin_peers", Result: &peers}
+	if err := callBatch(ctx, i.url, []*rpcCall{infoCall, peersCall}); err != nil {
+		return nodeInfo{}, nil, err
+	}
+	if infoCall.Err != nil {
+		return nodeInfo{}, nil, errors.Join(infoCall.Err, errors.New("failed fetching the node info, the admin RPC namespace is required"))
+	}
+	if peersCall.Err != nil {
+		return nodeInfo{}, nil, peersCall.Err
+	}
+	return info, peers, nil
+}
+
+func (i *InboundMetric) AggregateResults() string {
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+
+	connectivity, method := "no", "inbound peers"
+	if i.last.reachable {
+		connectivity = "yes"
+	}
+	if i.last.probed {
+		method = "external probe"
+	}
+
+	var values []float64
+	for _, point := range i.Snapshot() {
+		values = append(values, point.Values[InboundPeersMeasurement])
+	}
+	percentiles := metric.CalculatePercentiles(values, 0, 10, 50, 90, 100)
+
+	return fmt.Sprintf("Inbound connectivity: %s (%s), enode: %s \n Inbound peers: %s",
+		connectivity, method, portcheck.Address(i.last.ip, i.last.port),
+		metric.FormatPercentiles(percentiles[0], percentiles[10], percentiles[50], percentiles[90], percentiles[100]))
+}
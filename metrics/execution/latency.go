@@ -1,11 +1,19 @@
 package execution
 
 import (
+	"bytes"
 	"context"
-	"log/slog"
+	"fmt"
 	"net"
+	"net/http"
+	"sync"
 	"time"
 
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/clock"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/exporter"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/format"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/httpclient"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/httptiming"
 	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/logger"
 	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/metric"
 )
@@ -16,100 +24,177 @@ const (
 	DurationP50Measurement = "DurationP50"
 	DurationP90Measurement = "DurationP90"
 	DurationMaxMeasurement = "DurationMax"
+	DNSP50Measurement      = "DNSP50"
+	ConnectP50Measurement  = "ConnectP50"
+	TLSP50Measurement      = "TLSP50"
+	TTFBP50Measurement     = "TTFBP50"
+	// ProxyP50Measurement is the time spent reaching the node through its proxy, excluded from the durations
+	ProxyP50Measurement = "ProxyP50"
 )
 
+var clientVersionRequest = []byte(`{"jsonrpc":"2.0","method":"web3_clientVersion","params":[],"id":1}`)
+
 type LatencyMetric struct {
 	metric.Base[time.Duration]
-	host              string
-	interval, timeout time.Duration
-	durations         []time.Duration
+	url       string
+	ipc       bool
+	client    *http.Client
+	interval  time.Duration
+	durations []time.Duration
+	timings   []httptiming.Timing
+	overheads []time.Duration
+	// mutex guards the durations, which the report reads while measuring
+	mutex sync.Mutex
 }
 
-func NewLatencyMetric(host, name string, interval time.Duration, healthCondition []metric.HealthCondition[time.Duration]) *LatencyMetric {
+func NewLatencyMetric(url, name string, interval time.Duration, healthCondition []metric.HealthCondition[time.Duration]) *LatencyMetric {
 	return &LatencyMetric{
-		host: host,
+		url: url,
 		Base: metric.Base[time.Duration]{
 			HealthConditions: healthCondition,
 			Name:             name,
 		},
+		// Measurements are bounded by their context
+		client:   httptiming.NewClient(0),
 		interval: interval,
-		timeout:  time.Duration(float64(interval) * 0.75),
 	}
 }
 
+// WithIPC measures the time to connect to the IPC socket at the URL path, an IPC connection has no request phases
+func (l *LatencyMetric) WithIPC() *LatencyMetric {
+	l.ipc = true
+	return l
+}
+
 func (l *LatencyMetric) Measure(ctx context.Context) {
-	ticker := time.NewTicker(l.interval)
-	defer ticker.Stop()
-
-	for {
-		select {
-		case <-ctx.Done():
-			slog.With("metric_name", l.Name).Debug("metric was stopped")
-			return
-		case <-ticker.C:
-			l.measure()
-		}
-	}
+	l.Runner(l.interval).Run(ctx, l.measure)
 }
 
-func (l *LatencyMetric) measure() {
-	var latency time.Duration
-	start := time.Now()
+// Probe runs a single measurement without recording it
+func (l *LatencyMetric) Probe(ctx context.Context) error {
+	_, err := l.time(ctx)
+	return err
+}
 
-	// Measure the latency between the execution layer and the host
-	conn, err := net.DialTimeout("tcp", l.host, l.timeout)
+func (l *LatencyMetric) measure(ctx context.Context) {
+	timing, err := l.time(ctx)
 	if err != nil {
-		// Log error if the connection fails
 		logger.WriteError(metric.ExecutionGroup, l.Name, err)
 		return
 	}
-	defer conn.Close()
-
-	latency = time.Since(start)
 
-	// Store the latency measurements
+	// Store the latency measurements, through a proxy the latency of the node excludes reaching it
+	latency := timing.Total
+	if !l.ipc && httpclient.ProxyOf(l.url) != nil {
+		latency -= timing.ProxyOverhead()
+		l.overheads = append(l.overheads, timing.ProxyOverhead())
+	}
+	l.mutex.Lock()
 	l.durations = append(l.durations, latency)
+	l.mutex.Unlock()
+	l.timings = append(l.timings, timing)
 
 	// Report the latency metric
-	l.writeMetric(latency)
+	l.writeMetric(ctx, latency)
+}
+
+func (l *LatencyMetric) time(ctx context.Context) (httptiming.Timing, error) {
+	// A slow measurement must not overlap the next one
+	ctx, cancel := l.MeasurementContext(ctx, time.Duration(float64(l.interval)*0.75))
+	defer cancel()
+
+	if l.ipc {
+		return l.measureIPC(ctx)
+	}
+	return l.measureHTTP(ctx)
 }
 
-func (l *LatencyMetric) writeMetric(latency time.Duration) {
+// measureHTTP times a cheap JSON-RPC call on a new connection, so every request phase is included
+func (l *LatencyMetric) measureHTTP(ctx context.Context) (httptiming.Timing, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, l.url, bytes.NewReader(clientVersionRequest))
+	if err != nil {
+		return httptiming.Timing{}, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	timing, _, err := httptiming.Do(l.client, req)
+	return timing, err
+}
+
+func (l *LatencyMetric) measureIPC(ctx context.Context) (httptiming.Timing, error) {
+	var dialer net.Dialer
+	start := clock.Now()
+	conn, err := dialer.DialContext(ctx, "unix", l.url)
+	if err != nil {
+		return httptiming.Timing{}, err
+	}
+	defer conn.Close()
+
+	latency := clock.Since(start)
+	return httptiming.Timing{Connect: latency, Total: latency}, nil
+}
+
+func (l *LatencyMetric) writeMetric(ctx context.Context, latency time.Duration) {
 	// Calculate percentiles for latency (e.g., min, p10, p50, p90, max)
+	l.mutex.Lock()
 	percentiles := metric.CalculatePercentiles(l.durations, 0, 10, 50, 90, 100)
+	l.mutex.Unlock()
 
 	// Record latency percentiles as data points
-	l.AddDataPoint(map[string]time.Duration{
+	phases := httptiming.Percentile(l.timings, 50)
+	values := map[string]time.Duration{
 		DurationMinMeasurement: percentiles[0],
 		DurationP10Measurement: percentiles[10],
 		DurationP50Measurement: percentiles[50],
 		DurationP90Measurement: percentiles[90],
 		DurationMaxMeasurement: percentiles[100],
-	})
+		DNSP50Measurement:      phases.DNS,
+		ConnectP50Measurement:  phases.Connect,
+		TLSP50Measurement:      phases.TLS,
+		TTFBP50Measurement:     phases.TTFB,
+	}
+	if len(l.overheads) != 0 {
+		values[ProxyP50Measurement] = metric.CalculatePercentiles(l.overheads, 50)[50]
+	}
+	l.AddDataPoint(values)
 
-	// Assuming Prometheus metric tracking is used for latency
-	latencyMetric.Observe(latency.Seconds())
+	exporter.ObserveLatency(ctx, metric.ExecutionGroup, l.url, latency)
 
 	// Log the latency metric
-	logger.WriteMetric(metric.ExecutionGroup, l.Name, map[string]any{
-		DurationMinMeasurement: percentiles[0],
-		DurationP10Measurement: percentiles[10],
-		DurationP50Measurement: percentiles[50],
-		DurationP90Measurement: percentiles[90],
-		DurationMaxMeasurement: percentiles[100],
-	})
+	logged := make(map[string]any, len(values))
+	for name, value := range values {
+		logged[name] = value
+	}
+	logger.WriteMetric(metric.ExecutionGroup, l.Name, logged)
+}
+
+// Distribution returns the latencies measured so far
+func (l *LatencyMetric) Distribution() []time.Duration {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	return append([]time.Duration(nil), l.durations...)
 }
 
 func (l *LatencyMetric) AggregateResults() string {
 	// Retrieve the last recorded latency values
-	var min, p10, p50, p90, max time.Duration
-
-	min = l.DataPoints[len(l.DataPoints)-1].Values[DurationMinMeasurement]
-	p10 = l.DataPoints[len(l.DataPoints)-1].Values[DurationP10Measurement]
-	p50 = l.DataPoints[len(l.DataPoints)-1].Values[DurationP50Measurement]
-	p90 = l.DataPoints[len(l.DataPoints)-1].Values[DurationP90Measurement]
-	max = l.DataPoints[len(l.DataPoints)-1].Values[DurationMaxMeasurement]
+	var (
+		min, p10, p50, p90, max time.Duration
+		values                  map[string]time.Duration
+	)
+
+	if latest, ok := l.Latest(); ok {
+		values = latest.Values
+		min = values[DurationMinMeasurement]
+		p10 = values[DurationP10Measurement]
+		p50 = values[DurationP50Measurement]
+		p90 = values[DurationP90Measurement]
+		max = values[DurationMaxMeasurement]
+	}
 
-	// Return formatted latency percentiles
-	return metric.FormatPercentiles(min, p10, p50, p90, max)
+	// Return formatted latency percentiles followed by the request phases
+	return metric.FormatPercentiles(min, p10, p50, p90, max) + fmt.Sprintf(" \n dns_p50=%s, connect_p50=%s, tls_p50=%s, ttfb_p50=%s",
+		format.Duration(values[DNSP50Measurement]),
+		format.Duration(values[ConnectP50Measurement]),
+		format.Duration(values[TLSP50Measurement]),
+		format.Duration(values[TTFBP50Measurement])) + httptiming.FormatProxy(l.url, values[ProxyP50Measurement])
 }
@@ -1,33 +1,47 @@
 package execution
 
 import (
-	"bytes"
 	"context"
-	"encoding/json"
 	"errors"
 	"fmt"
-	"io"
 	"log/slog"
-	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/exporter"
 	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/logger"
 	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/metric"
 )
 
 const (
-	PeerCountMeasurement = "Count"
+	PeerCountMeasurement         = "Count"
+	Eth68PeerCountMeasurement    = "Eth68"
+	SnapPeerCountMeasurement     = "Snap"
+	InboundPeerCountMeasurement  = "Inbound"
+	OutboundPeerCountMeasurement = "Outbound"
+	StaticPeerCountMeasurement   = "Static"
 )
 
 var measuringErr = errors.New("UNABLE_TO_MEASURE")
 
-type PeerMetric struct {
-	metric.Base[uint32]
-	url             string
-	interval        time.Duration
-	measuringErrors map[string]error
-}
+type (
+	PeerMetric struct {
+		metric.Base[uint32]
+		url             string
+		interval        time.Duration
+		adminPeers      bool
+		measuringErrors map[string]error
+	}
+
+	adminPeer struct {
+		Caps    []string `json:"caps"`
+		Network struct {
+			Inbound bool `json:"inbound"`
+			Static  bool `json:"static"`
+		} `json:"network"`
+	}
+)
 
 func NewPeerMetric(url, name string, interval time.Duration, healthCondition []metric.HealthCondition[uint32]) *PeerMetric {
 	return &PeerMetric{
@@ -41,87 +55,67 @@ func NewPeerMetric(url, name string, interval time.Duration, healthCondition []m
 	}
 }
 
+// WithAdminPeers enables the admin_peers based protocol and direction breakdown.
+// The metric falls back to net_peerCount when the admin namespace is not exposed by the client.
+func (p *PeerMetric) WithAdminPeers() *PeerMetric {
+	p.adminPeers = true
+	return p
+}
+
 func (p *PeerMetric) Measure(ctx context.Context) {
-	ticker := time.NewTicker(p.interval)
-	defer ticker.Stop()
+	p.Runner(p.interval).Run(ctx, p.measure)
+}
 
-	for {
-		select {
-		case <-ctx.Done():
-			slog.With("metric_name", p.Name).Debug("metric was stopped")
-			return
-		case <-ticker.C:
-			p.measure(ctx)
-		}
+// Probe runs a single measurement without recording it, admin_peers isn't required as net_peerCount is its fallback
+func (p *PeerMetric) Probe(ctx context.Context) error {
+	ctx, cancel := p.MeasurementContext(ctx, 5*time.Second)
+	defer cancel()
+
+	var peerCountHex string
+	if err := callRPC(ctx, p.url, "net_peerCount", &peerCountHex); err != nil {
+		return err
+	}
+	if peerCountHex == "" {
+		return errors.New("peer count RPC response was empty. Most likely net_peerCount RPC method is not supported")
 	}
+	return nil
 }
 
 func (p *PeerMetric) measure(ctx context.Context) {
-	var (
-		resp struct {
-			Result string `json:"result"`
+	if p.adminPeers {
+		err := p.measureAdminPeers(ctx)
+		if err == nil {
+			return
+		}
+		if errors.Is(err, errMethodNotFound) {
+			slog.
+				With("metric_name", p.Name).
+				With("err", err.Error()).
+				Warn("admin_peers RPC method is not available, falling back to net_peerCount")
+			p.adminPeers = false
+		} else {
+			logger.WriteError(metric.ExecutionGroup, p.Name, err)
 		}
-	)
-
-	// Prepare the JSON-RPC request for peer count
-	request := struct {
-		Jsonrpc string `json:"jsonrpc"`
-		Method  string `json:"method"`
-		Params  []any  `json:"params"`
-		ID      int    `json:"id"`
-	}{
-		Jsonrpc: "2.0",
-		Method:  "net_peerCount",
-		Params:  []any{},
-		ID:      1,
-	}
-
-	// Marshal the request into JSON
-	requestBytes, err := json.Marshal(request)
-	if err != nil {
-		logger.WriteError(metric.ExecutionGroup, p.Name, err)
-		return
 	}
 
+	var peerCountHex string
+
 	// Set the request timeout
-	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	ctx, cancel := p.MeasurementContext(ctx, 5*time.Second)
 	defer cancel()
 
-	// Create the HTTP POST request
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.url, bytes.NewBuffer(requestBytes))
-	req.Header.Set("Content-Type", "application/json")
-	if err != nil {
-		logger.WriteError(metric.ExecutionGroup, p.Name, err)
-		return
-	}
-
-	// Send the request to the Reth execution layer
-	res, err := http.DefaultClient.Do(req)
-	if err != nil {
-		p.writeMetric(0)
-		logger.WriteError(metric.ExecutionGroup, p.Name, err)
-		return
-	}
-	defer res.Body.Close()
-
-	// Handle unsuccessful responses
-	if res.StatusCode != http.StatusOK {
-		p.writeMetric(0)
-		p.logErrorResponse(res)
-		return
-	}
-
-	// Decode the response body
-	if err = json.NewDecoder(res.Body).Decode(&resp); err != nil {
-		p.writeMetric(0)
+	if err := callRPC(ctx, p.url, "net_peerCount", &peerCountHex); err != nil {
+		p.writeMetric(ctx, 0)
 		logger.WriteError(metric.ExecutionGroup, p.Name, err)
+		if errors.Is(err, errMethodNotFound) {
+			p.measuringErrors[PeerCountMeasurement] = errors.Join(measuringErr, err)
+		}
 		return
 	}
 
 	// Parse the peer count from the response (hexadecimal string)
-	peerCountHex := resp.Result
 	if peerCountHex == "" {
-		p.writeMetric(0)
+		p.writeMetric(ctx, 0)
 		err := errors.New("peer count RPC response was empty. Most likely net_peerCount RPC method is not supported")
 		logger.WriteError(metric.ExecutionGroup, p.Name, err)
 		p.measuringErrors[PeerCountMeasurement] = errors.Join(measuringErr, err)
@@ -129,69 +123,83 @@ func (p *PeerMetric) measure(ctx context.Context) {
 	}
 
 	// Convert the peer count from hex to integer
-	peerCount, err := strconv.ParseInt(peerCountHex[2:], 16, 64)
+	peerCount, err := strconv.ParseInt(strings.TrimPrefix(peerCountHex, "0x"), 16, 64)
 	if err != nil {
-		p.writeMetric(0)
+		p.writeMetric(ctx, 0)
 		logger.WriteError(metric.ExecutionGroup, p.Name, err)
 		return
 	}
 
 	// Write the measured peer count to the metric
-	p.writeMetric(peerCount)
+	p.writeMetric(ctx, peerCount)
 }
 
-func (p *PeerMetric) logErrorResponse(res *http.Response) {
-	var responseString string
-	if res.Header.Get("Content-Type") == "application/json" {
-		var errorResponse any
-		if err := json.NewDecoder(res.Body).Decode(&errorResponse); err != nil {
-			logger.WriteError(
-				metric.ExecutionGroup,
-				p.Name,
-				errors.Join(err, fmt.Errorf("received unsuccessful status code. Code: '%s'. Failed to JSON decode response", res.Status)))
-			return
+func (p *PeerMetric) measureAdminPeers(ctx context.Context) error {
+	var peers []adminPeer
+
+	ctx, cancel := p.MeasurementContext(ctx, 5*time.Second)
+	defer cancel()
+
+	if err := callRPC(ctx, p.url, "admin_peers", &peers); err != nil {
+		return err
+	}
+
+	values := map[string]uint32{
+		PeerCountMeasurement:         uint32(len(peers)),
+		Eth68PeerCountMeasurement:    0,
+		SnapPeerCountMeasurement:     0,
+		InboundPeerCountMeasurement:  0,
+		OutboundPeerCountMeasurement: 0,
+		StaticPeerCountMeasurement:   0,
+	}
+	for _, peer := range peers {
+		for _, capability := range peer.Caps {
+			switch {
+			case capability == "eth/68":
+				values[Eth68PeerCountMeasurement]++
+			case strings.HasPrefix(capability, "snap/"):
+				values[SnapPeerCountMeasurement]++
+			}
 		}
-		jsonErrResponse, err := json.Marshal(errorResponse)
-		if err != nil {
-			logger.WriteError(
-				metric.ExecutionGroup,
-				p.Name,
-				errors.Join(err, fmt.Errorf("received unsuccessful status code. Code: '%s'. Failed to marshal response", res.Status)))
-			return
+		if peer.Network.Inbound {
+			values[InboundPeerCountMeasurement]++
+		} else {
+			values[OutboundPeerCountMeasurement]++
 		}
-		responseString = string(jsonErrResponse)
-	} else {
-		body, err := io.ReadAll(res.Body)
-		if err != nil {
-			logger.WriteError(
-				metric.ExecutionGroup,
-				p.Name,
-				errors.Join(err, fmt.Errorf("received unsuccessful status code. Code: '%s'. Failed to decode response", res.Status)))
-			return
+		if peer.Network.Static {
+			values[StaticPeerCountMeasurement]++
 		}
-		responseString = string(body)
 	}
 
-	// Log the error response from the server
-	logger.WriteError(
-		metric.ExecutionGroup,
-		p.Name,
-		fmt.Errorf("received unsuccessful status code. Code: '%s'. Response: '%s'", res.Status, responseString))
+	p.writeAdminMetric(ctx, values)
+
+	return nil
 }
 
-func (p *PeerMetric) writeMetric(value int64) {
+func (p *PeerMetric) writeMetric(ctx context.Context, value int64) {
 	// Record the peer count in the metric system
 	p.AddDataPoint(map[string]uint32{
 		PeerCountMeasurement: uint32(value),
 	})
 
-	// Update the metric value (e.g., Prometheus)
-	peerCountMetric.Set(float64(value))
+	exporter.SetPeers(ctx, metric.ExecutionGroup, p.url, float64(value))
 
 	// Log the metric
 	logger.WriteMetric(metric.ExecutionGroup, p.Name, map[string]any{PeerCountMeasurement: value})
 }
 
+func (p *PeerMetric) writeAdminMetric(ctx context.Context, values map[string]uint32) {
+	p.AddDataPoint(values)
+
+	exporter.SetPeers(ctx, metric.ExecutionGroup, p.url, float64(values[PeerCountMeasurement]))
+
+	logValues := make(map[string]any, len(values))
+	for name, value := range values {
+		logValues[name] = value
+	}
+	logger.WriteMetric(metric.ExecutionGroup, p.Name, logValues)
+}
+
 func (p *PeerMetric) AggregateResults() string {
 	// Check for any errors encountered during measurement
 	for measurementName, err := range p.measuringErrors {
@@ -207,7 +215,7 @@ func (p *PeerMetric) AggregateResults() string {
 
 	// Collect the peer count values from all data points
 	var values []uint32
-	for _, point := range p.DataPoints {
+	for _, point := range p.Snapshot() {
 		values = append(values, point.Values[PeerCountMeasurement])
 	}
 
@@ -215,10 +223,37 @@ func (p *PeerMetric) AggregateResults() string {
 	percentiles := metric.CalculatePercentiles(values, 0, 10, 50, 90, 100)
 
 	// Return the formatted percentiles
-	return metric.FormatPercentiles(
+	result := metric.FormatPercentiles(
 		percentiles[0],
 		percentiles[10],
 		percentiles[50],
 		percentiles[90],
 		percentiles[100])
+
+	// Append the admin_peers breakdown when it was measured
+	breakdown := make(map[string][]uint32)
+	for _, point := range p.Snapshot() {
+		if _, ok := point.Values[Eth68PeerCountMeasurement]; !ok {
+			continue
+		}
+		for _, name := range []string{
+			Eth68PeerCountMeasurement,
+			SnapPeerCountMeasurement,
+			InboundPeerCountMeasurement,
+			OutboundPeerCountMeasurement,
+			StaticPeerCountMeasurement,
+		} {
+			breakdown[name] = append(breakdown[name], point.Values[name])
+		}
+	}
+	if len(breakdown) != 0 {
+		result += fmt.Sprintf(" \n eth68_P50=%d, snap_P50=%d, inbound_P50=%d, outbound_P50=%d, static_P50=%d",
+			metric.CalculatePercentiles(breakdown[Eth68PeerCountMeasurement], 50)[50],
+			metric.CalculatePercentiles(breakdown[SnapPeerCountMeasurement], 50)[50],
+			metric.CalculatePercentiles(breakdown[InboundPeerCountMeasurement], 50)[50],
+			metric.CalculatePercentiles(breakdown[OutboundPeerCountMeasurement], 50)[50],
+			metric.CalculatePercentiles(breakdown[StaticPeerCountMeasurement], 50)[50])
+	}
+
+	return result
 }
@@ -0,0 +1,188 @@
+package execution
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/httpclient"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/ipc"
+)
+
+const methodNotFoundCode = -32601
+
+var errMethodNotFound = errors.New("RPC method not found")
+
+type (
+	rpcRequest struct {
+		Jsonrpc string `json:"jsonrpc"`
+		Method  string `json:"method"`
+		Params  []any  `json:"params"`
+		ID      int    `json:"id"`
+	}
+
+	rpcResponse struct {
+		ID     int             `json:"id"`
+		Result json.RawMessage `json:"result"`
+		Error  *rpcError       `json:"error"`
+	}
+
+	// rpcCall is a call of a JSON-RPC batch, its result is decoded into Result and its own failure kept in Err
+	rpcCall struct {
+		Method string
+		Params []any
+		Result any
+		Err    error
+	}
+
+	rpcError struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	}
+)
+
+func (e *rpcError) Error() string {
+	return fmt.Sprintf("RPC error. Code: '%d'. Message: '%s'", e.Code, e.Message)
+}
+
+// Unsupported tells whether the client answered a call with an error, e.g. a method of a disabled namespace, rather
+// than not answering it
+func Unsupported(err error) bool {
+	var rpcErr *rpcError
+	return errors.As(err, &rpcErr)
+}
+
+// callRPC sends a single JSON-RPC request and decodes its result into the passed value. The URL may also be the path
+// of an IPC socket. Methods which are not exposed by the client (e.g. disabled namespace) are reported as errMethodNotFound.
+func callRPC(ctx context.Context, url, method string, result any, params ...any) error {
+	return doRPC(ctx, url, nil, method, result, params...)
+}
+
+// callAuthenticatedRPC sends a single JSON-RPC request authenticated with the passed bearer token
+func callAuthenticatedRPC(ctx context.Context, url, token, method string, result any, params ...any) error {
+	return doRPC(ctx, url, map[string]string{"Authorization": "Bearer " + token}, method, result, params...)
+}
+
+// callBatch sends the calls as a single JSON-RPC batch, so they cost one round trip and see the same state of the
+// client. The returned error fails every call, the error of a single call is kept in its Err. Clients rejecting
+// batches are sent the calls one after the other.
+func callBatch(ctx context.Context, url string, calls []*rpcCall) error {
+	return doBatch(ctx, url, nil, calls)
+}
+
+// callAuthenticatedBatch sends the calls as a single JSON-RPC batch authenticated with the passed bearer token
+func callAuthenticatedBatch(ctx context.Context, url, token string, calls []*rpcCall) error {
+	return doBatch(ctx, url, map[string]string{"Authorization": "Bearer " + token}, calls)
+}
+
+func doRPC(ctx context.Context, url string, headers map[string]string, method string, result any, params ...any) error {
+	var resp rpcResponse
+	if err := send(ctx, url, headers, method, newRequest(1, method, params), &resp); err != nil {
+		return err
+	}
+	return decodeResult(resp, result)
+}
+
+func doBatch(ctx context.Context, url string, headers map[string]string, calls []*rpcCall) error {
+	requests := make([]rpcRequest, 0, len(calls))
+	methods := make([]string, 0, len(calls))
+	for i, call := range calls {
+		requests = append(requests, newRequest(i+1, call.Method, call.Params))
+		methods = append(methods, call.Method)
+	}
+
+	var raw json.RawMessage
+	if err := send(ctx, url, headers, strings.Join(methods, ","), requests, &raw); err != nil {
+		return err
+	}
+
+	var responses []rpcResponse
+	if err := json.Unmarshal(raw, &responses); err != nil {
+		// A single response instead of one per call means the batch as a whole was rejected
+		for _, call := range calls {
+			call.Err = doRPC(ctx, url, headers, call.Method, call.Result, call.Params...)
+		}
+		return nil
+	}
+
+	answered := make(map[int]bool, len(responses))
+	for _, resp := range responses {
+		if resp.ID < 1 || resp.ID > len(calls) {
+			continue
+		}
+		answered[resp.ID] = true
+		calls[resp.ID-1].Err = decodeResult(resp, calls[resp.ID-1].Result)
+	}
+	for i, call := range calls {
+		if !answered[i+1] {
+			call.Err = fmt.Errorf("no response to '%s' in the batch", call.Method)
+		}
+	}
+	return nil
+}
+
+func newRequest(id int, method string, params []any) rpcRequest {
+	if params == nil {
+		params = []any{}
+	}
+	return rpcRequest{
+		Jsonrpc: "2.0",
+		Method:  method,
+		Params:  params,
+		ID:      id,
+	}
+}
+
+// send posts the request, a single request or a batch, and decodes the response. The URL may be the path of an IPC
+// socket.
+func send(ctx context.Context, url string, headers map[string]string, method string, request, response any) error {
+	if ipc.IsSocket(url) {
+		return ipc.Call(ctx, url, request, response)
+	}
+
+	requestBytes, err := json.Marshal(request)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewBuffer(requestBytes))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+
+	res, err := httpclient.Default.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(res.Body, 1024))
+		return fmt.Errorf("received unsuccessful status code. Code: '%s'. Method: '%s'. Response: '%s'", res.Status, method, string(body))
+	}
+
+	return json.NewDecoder(res.Body).Decode(response)
+}
+
+func decodeResult(resp rpcResponse, result any) error {
+	if resp.Error != nil {
+		if resp.Error.Code == methodNotFoundCode {
+			return errors.Join(errMethodNotFound, resp.Error)
+		}
+		return resp.Error
+	}
+	if len(resp.Result) == 0 {
+		return nil
+	}
+
+	return json.Unmarshal(resp.Result, result)
+}
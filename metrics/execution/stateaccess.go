@@ -0,0 +1,259 @@
+package execution
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/clock"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/format"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/logger"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/metric"
+)
+
+const (
+	StateAccessP50Measurement = "StateAccessP50Ms"
+	StateAccessP90Measurement = "StateAccessP90Ms"
+	// DiskReadPerCallMeasurement is the data the host read from disk during the probe divided by its calls. The disk
+	// counters are those of the whole host, other processes reading at the same time inflate it.
+	DiskReadPerCallMeasurement = "DiskReadKBPerCall"
+	// ResponsivenessMeasurement scores the state access from 0 to 100, 100 when the p90 of the probe calls is within
+	// responsiveStateAccess and falling in proportion to it above
+	ResponsivenessMeasurement = "Responsiveness"
+
+	// probeAccounts is the number of accounts whose state is read per measurement
+	probeAccounts = 4
+	// probeDepth is how many blocks behind the head the probed accounts are taken from, accounts of the head block
+	// were just executed and are likely still in the caches of the client
+	probeDepth = 64
+	// responsiveStateAccess is the p90 of the probe calls an execution client on a good SSD stays within
+	responsiveStateAccess = 20 * time.Millisecond
+	// totalSupplySelector is the selector of the ERC-20 'totalSupply()', a read of contract code and storage which
+	// returns nothing for accounts without code
+	totalSupplySelector = "0x18160ddd"
+)
+
+type (
+	// StateAccessMetric reads the state of accounts touched by a recent block with eth_getProof and eth_call, and
+	// relates the latency of the calls to the data the host read from disk meanwhile. The trie lookups behind these
+	// calls are what tells an NVMe SSD apart from slower storage.
+	StateAccessMetric struct {
+		metric.Base[float64]
+		url       string
+		interval  time.Duration
+		durations []time.Duration
+		// unsupported are the probe methods the client doesn't expose, e.g. eth_getProof on clients without proof support
+		unsupported map[string]bool
+		// mutex guards the durations and unsupported methods, which the report reads while measuring
+		mutex sync.Mutex
+	}
+
+	probeBlock struct {
+		Miner        string `json:"miner"`
+		Transactions []struct {
+			To *string `json:"to"`
+		} `json:"transactions"`
+	}
+)
+
+func NewStateAccessMetric(url, name string, interval time.Duration, healthCondition []metric.HealthCondition[float64]) *StateAccessMetric {
+	return &StateAccessMetric{
+		url: url,
+		Base: metric.Base[float64]{
+			HealthConditions: healthCondition,
+			Name:             name,
+		},
+		interval:    interval,
+		unsupported: make(map[string]bool),
+	}
+}
+
+func (s *StateAccessMetric) Measure(ctx context.Context) {
+	s.Runner(s.interval).Run(ctx, s.measure)
+}
+
+// Probe runs a single measurement without recording it, it fails unless the client exposes one of the probe calls
+func (s *StateAccessMetric) Probe(ctx context.Context) error {
+	ctx, cancel := s.MeasurementContext(ctx, time.Duration(float64(s.interval)*0.75))
+	defer cancel()
+
+	accounts, err := s.accountsToProbe(ctx)
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for _, probe := range []struct {
+		method string
+		params []any
+	}{
+		{"eth_getProof", []any{accounts[0], []string{"0x0"}, "latest"}},
+		{"eth_call", []any{map[string]string{"to": accounts[0], "data": totalSupplySelector}, "latest"}},
+	} {
+		err := callRPC(ctx, s.url, probe.method, new(any), probe.params...)
+		// A reverted call read the state all the same
+		var rpcErr *rpcError
+		if err == nil || (errors.As(err, &rpcErr) && !errors.Is(err, errMethodNotFound)) {
+			return nil
+		}
+		errs = append(errs, err)
+	}
+	return errors.Join(append(errs, errors.New("the client exposes neither eth_getProof nor eth_call"))...)
+}
+
+func (s *StateAccessMetric) measure(ctx context.Context) {
+	// A slow measurement must not overlap the next one
+	ctx, cancel := s.MeasurementContext(ctx, time.Duration(float64(s.interval)*0.75))
+	defer cancel()
+
+	accounts, err := s.accountsToProbe(ctx)
+	if err != nil {
+		logger.WriteError(metric.ExecutionGroup, s.Name, err)
+		return
+	}
+
+	readBefore, diskErr := readDiskBytes()
+	var durations []time.Duration
+	for _, account := range accounts {
+		probes := []struct {
+			method string
+			params []any
+		}{
+			{"eth_getProof", []any{account, []string{"0x0"}, "latest"}},
+			{"eth_call", []any{map[string]string{"to": account, "data": totalSupplySelector}, "latest"}},
+		}
+		for _, probe := range probes {
+			if s.isUnsupported(probe.method) {
+				continue
+			}
+			start := clock.Now()
+			err := callRPC(ctx, s.url, probe.method, new(any), probe.params...)
+			duration := clock.Since(start)
+			var rpcErr *rpcError
+			switch {
+			case errors.Is(err, errMethodNotFound):
+				s.mutex.Lock()
+				s.unsupported[probe.method] = true
+				s.mutex.Unlock()
+				continue
+			// A reverted call read the state all the same
+			case err != nil && !errors.As(err, &rpcErr):
+				logger.WriteError(metric.ExecutionGroup, s.Name, errors.Join(err, fmt.Errorf("failed probing state access with '%s'", probe.method)))
+				return
+			}
+			durations = append(durations, duration)
+		}
+	}
+	if len(durations) == 0 {
+		logger.WriteError(metric.ExecutionGroup, s.Name, errors.New("the client exposes neither eth_getProof nor eth_call"))
+		return
+	}
+	s.mutex.Lock()
+	s.durations = append(s.durations, durations...)
+	percentiles := metric.CalculatePercentiles(s.durations, 50, 90)
+	s.mutex.Unlock()
+	values := map[string]float64{
+		StateAccessP50Measurement: float64(percentiles[50]) / float64(time.Millisecond),
+		StateAccessP90Measurement: float64(percentiles[90]) / float64(time.Millisecond),
+		ResponsivenessMeasurement: responsiveness(percentiles[90]),
+	}
+	if diskErr == nil {
+		if readAfter, err := readDiskBytes(); err == nil && readAfter >= readBefore {
+			values[DiskReadPerCallMeasurement] = float64(readAfter-readBefore) / 1024 / float64(len(durations))
+		}
+	}
+	s.AddDataPoint(values)
+
+	logged := make(map[string]any, len(values)+1)
+	for name, value := range values {
+		logged[name] = value
+	}
+	logged["Calls"] = len(durations)
+	logger.WriteMetric(metric.ExecutionGroup, s.Name, logged)
+}
+
+// accountsToProbe returns the fee recipient and the called accounts of the block probeDepth blocks behind the head
+func (s *StateAccessMetric) accountsToProbe(ctx context.Context) ([]string, error) {
+	var head string
+	if err := callRPC(ctx, s.url, "eth_blockNumber", &head); err != nil {
+		return nil, errors.Join(err, errors.New("failed fetching the head block number"))
+	}
+	number, err := strconv.ParseUint(strings.TrimPrefix(head, "0x"), 16, 64)
+	if err != nil {
+		return nil, errors.Join(err, errors.New("failed parsing the head block number"))
+	}
+	if number > probeDepth {
+		number -= probeDepth
+	}
+
+	var block probeBlock
+	if err := callRPC(ctx, s.url, "eth_getBlockByNumber", &block, fmt.Sprintf("0x%x", number), true); err != nil {
+		return nil, errors.Join(err, errors.New("failed fetching the block of the probed accounts"))
+	}
+
+	seen := make(map[string]bool)
+	var accounts []string
+	add := func(account string) {
+		account = strings.ToLower(account)
+		if account == "" || seen[account] || len(accounts) == probeAccounts {
+			return
+		}
+		seen[account] = true
+		accounts = append(accounts, account)
+	}
+	for _, transaction := range block.Transactions {
+		if transaction.To != nil {
+			add(*transaction.To)
+		}
+	}
+	add(block.Miner)
+	if len(accounts) == 0 {
+		return nil, fmt.Errorf("block %d has no accounts to probe", number)
+	}
+	return accounts, nil
+}
+
+func (s *StateAccessMetric) isUnsupported(method string) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.unsupported[method]
+}
+
+// Distribution returns the durations of the probe calls measured so far
+func (s *StateAccessMetric) Distribution() []time.Duration {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return append([]time.Duration(nil), s.durations...)
+}
+
+// responsiveness scores the p90 of the state access calls from 0 to 100
+func responsiveness(p90 time.Duration) float64 {
+	if p90 <= responsiveStateAccess {
+		return 100
+	}
+	return 100 * float64(responsiveStateAccess) / float64(p90)
+}
+
+func (s *StateAccessMetric) AggregateResults() string {
+	var values map[string]float64
+	if latest, ok := s.Latest(); ok {
+		values = latest.Values
+	}
+
+	diskRead := "unknown"
+	if value, ok := values[DiskReadPerCallMeasurement]; ok {
+		diskRead = fmt.Sprintf("%.1fKB", value)
+	}
+	result := fmt.Sprintf("Responsiveness: %.0f/100 \n p50=%s, p90=%s, disk_read_per_call=%s",
+		values[ResponsivenessMeasurement],
+		format.Duration(time.Duration(values[StateAccessP50Measurement]*float64(time.Millisecond))),
+		format.Duration(time.Duration(values[StateAccessP90Measurement]*float64(time.Millisecond))),
+		diskRead)
+	if s.isUnsupported("eth_getProof") {
+		result += " \n eth_getProof not supported"
+	}
+	return result
+}
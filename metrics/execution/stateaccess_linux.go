@@ -0,0 +1,38 @@
+package execution
+
+import (
+	"bufio"
+	"errors"
+	"os"
+	"strconv"
+	"strings"
+)
+
+const vmstatPath = "/proc/vmstat"
+
+// readDiskBytes reads the bytes the host read from block devices since boot from the 'pgpgin' counter of /proc/vmstat,
+// which counts kB
+func readDiskBytes() (uint64, error) {
+	file, err := os.Open(vmstatPath)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		name, value, ok := strings.Cut(scanner.Text(), " ")
+		if !ok || name != "pgpgin" {
+			continue
+		}
+		kilobytes, err := strconv.ParseUint(strings.TrimSpace(value), 10, 64)
+		if err != nil {
+			return 0, err
+		}
+		return kilobytes * 1024, nil
+	}
+	if err := scanner.Err(); err != nil {
+		return 0, err
+	}
+	return 0, errors.New("pgpgin counter missing from " + vmstatPath)
+}
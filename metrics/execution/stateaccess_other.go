@@ -0,0 +1,9 @@
+//go:build !linux
+
+package execution
+
+import "errors"
+
+func readDiskBytes() (uint64, error) {
+	return 0, errors.New("disk read counters are not supported on this platform")
+}
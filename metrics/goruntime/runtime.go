@@ -0,0 +1,152 @@
+package goruntime
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/format"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/logger"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/metric"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/promtext"
+)
+
+const (
+	GoroutinesMeasurement = "Goroutines"
+	HeapMeasurement       = "HeapBytes"
+	// GCPauseMeasurement is the longest recent stop-the-world GC pause in milliseconds
+	GCPauseMeasurement = "GCPauseMs"
+)
+
+type (
+	// RuntimeMetric scrapes the Go runtime stats of a Go client (e.g. Geth, Prysm) from its Prometheus endpoint, long GC
+	// pauses and a growing heap hint at memory pressure delaying duties
+	RuntimeMetric struct {
+		metric.Base[float64]
+		group    metric.Group
+		url      string
+		interval time.Duration
+	}
+
+	// runtimeNames are the names a client exposes the runtime stats under, the pause quantile is scaled to seconds
+	runtimeNames struct {
+		goroutines, heap, gcPause string
+		gcPauseLabels             []string
+		gcPauseUnit               float64
+	}
+)
+
+var (
+	// standardNames are exposed by the collector of the Prometheus Go client, e.g. by Prysm
+	standardNames = runtimeNames{
+		goroutines:    "go_goroutines",
+		heap:          "go_memstats_heap_alloc_bytes",
+		gcPause:       "go_gc_duration_seconds",
+		gcPauseLabels: []string{"quantile", "1"},
+		gcPauseUnit:   1,
+	}
+	// gethNames are exposed by the metrics registry of geth and its forks (e.g. Erigon), pauses are in nanoseconds
+	gethNames = runtimeNames{
+		goroutines:    "system_cpu_goroutines",
+		heap:          "system_memory_used",
+		gcPause:       "system_memory_pauses",
+		gcPauseLabels: []string{"quantile", "0.99"},
+		gcPauseUnit:   float64(time.Nanosecond) / float64(time.Second),
+	}
+)
+
+// NewRuntimeMetric creates the metric scraping the Prometheus endpoint at the URL, e.g. 'http://geth:6060/debug/metrics/prometheus'
+func NewRuntimeMetric(group metric.Group, url, name string, interval time.Duration, healthCondition []metric.HealthCondition[float64]) *RuntimeMetric {
+	return &RuntimeMetric{
+		Base: metric.Base[float64]{
+			HealthConditions: healthCondition,
+			Name:             name,
+		},
+		group:    group,
+		url:      url,
+		interval: interval,
+	}
+}
+
+func (r *RuntimeMetric) Measure(ctx context.Context) {
+	r.Runner(r.interval).Run(ctx, r.measure)
+}
+
+func (r *RuntimeMetric) measure(ctx context.Context) {
+	ctx, cancel := r.MeasurementContext(ctx, 5*time.Second)
+	defer cancel()
+
+	samples, err := promtext.Fetch(ctx, r.url)
+	if err != nil {
+		logger.WriteError(r.group, r.Name, errors.Join(err, errors.New("failed scraping the Prometheus endpoint")))
+		return
+	}
+
+	values, err := normalize(samples)
+	if err != nil {
+		logger.WriteError(r.group, r.Name, err)
+		return
+	}
+
+	r.AddDataPoint(values)
+	logger.WriteMetric(r.group, r.Name, map[string]any{
+		GoroutinesMeasurement: values[GoroutinesMeasurement],
+		HeapMeasurement:       format.Bytes(values[HeapMeasurement]),
+		GCPauseMeasurement:    values[GCPauseMeasurement],
+	})
+}
+
+// normalize maps the runtime stats of either naming scheme to the measurements
+func normalize(samples promtext.Samples) (map[string]float64, error) {
+	names := standardNames
+	if samples.Has(gethNames.goroutines) {
+		names = gethNames
+	}
+	if !samples.Has(names.goroutines) {
+		return nil, errors.New("the Prometheus endpoint exposes no Go runtime stats, the client is probably not written in Go")
+	}
+
+	values := make(map[string]float64, 3)
+	values[GoroutinesMeasurement], _ = samples.Value(names.goroutines)
+	if heap, ok := samples.Value(names.heap); ok {
+		values[HeapMeasurement] = heap
+	}
+	if pause, ok := samples.Value(names.gcPause, names.gcPauseLabels...); ok {
+		values[GCPauseMeasurement] = pause * names.gcPauseUnit * 1000
+	}
+	return values, nil
+}
+
+func (r *RuntimeMetric) AggregateResults() string {
+	var goroutines, heap, pauses []float64
+	for _, point := range r.Snapshot() {
+		goroutines = append(goroutines, point.Values[GoroutinesMeasurement])
+		if value, ok := point.Values[HeapMeasurement]; ok {
+			heap = append(heap, value)
+		}
+		if value, ok := point.Values[GCPauseMeasurement]; ok {
+			pauses = append(pauses, value)
+		}
+	}
+	if len(goroutines) == 0 {
+		return "no runtime stats were scraped"
+	}
+
+	goroutinePercentiles := metric.CalculatePercentiles(goroutines, 50, 100)
+	summary := fmt.Sprintf("Goroutines: P50=%s, max=%s",
+		format.Number(goroutinePercentiles[50], 0), format.Number(goroutinePercentiles[100], 0))
+	if len(heap) != 0 {
+		// The percentiles sort the values, so the last value is taken first
+		last := heap[len(heap)-1]
+		heapPercentiles := metric.CalculatePercentiles(heap, 50, 100)
+		summary += fmt.Sprintf(" \n Heap: P50=%s, max=%s, last=%s",
+			format.Bytes(heapPercentiles[50]), format.Bytes(heapPercentiles[100]), format.Bytes(last))
+	}
+	if len(pauses) != 0 {
+		pausePercentiles := metric.CalculatePercentiles(pauses, 50, 100)
+		summary += fmt.Sprintf(" \n GC pause: P50=%sms, max=%sms",
+			format.Number(pausePercentiles[50], 2), format.Number(pausePercentiles[100], 2))
+	}
+	return summary
+}
@@ -2,23 +2,47 @@ package infrastructure
 
 import (
 	"context"
+	"fmt"
 	"time"
 
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/format"
 	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/logger"
 	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/metric"
-	"github.com/mackerelio/go-osstat/cpu"
 )
 
 const (
 	SystemCPUMeasurement = "System"
 	UserCPUMeasurement   = "User"
+	IOWaitMeasurement    = "IOWait"
+	StealMeasurement     = "Steal"
+	// MaxCoreMeasurement is the utilization of the busiest core
+	MaxCoreMeasurement = "MaxCore"
+	// PeggedCoresMeasurement counts the cores busier than peggedCoreThreshold
+	PeggedCoresMeasurement = "PeggedCores"
+
+	// peggedCoreThreshold is the utilization from which a core is saturated, single-threaded work such as block
+	// processing then waits for it even though the average CPU utilization looks fine
+	peggedCoreThreshold = 95.0
 )
 
-type CPUMetric struct {
-	metric.Base[float64]
-	prevUser, prevSystem, total uint64
-	interval                    time.Duration
-}
+type (
+	// cpuTimes are the cumulative times a CPU spent in each state, in ticks
+	cpuTimes struct {
+		User, Nice, System, Idle, IOWait, IRQ, SoftIRQ, Steal, Total uint64
+	}
+
+	// cpuSample holds the times of all CPUs together and, where the platform exposes them, of every core
+	cpuSample struct {
+		total cpuTimes
+		cores []cpuTimes
+	}
+
+	CPUMetric struct {
+		metric.Base[float64]
+		prev     *cpuSample
+		interval time.Duration
+	}
+)
 
 func NewCPUMetric(name string, interval time.Duration, healthCondition []metric.HealthCondition[float64]) *CPUMetric {
 	return &CPUMetric{
@@ -31,43 +55,127 @@ func NewCPUMetric(name string, interval time.Duration, healthCondition []metric.
 }
 
 func (c *CPUMetric) Measure(ctx context.Context) {
-	ticker := time.NewTicker(c.interval)
-	defer ticker.Stop()
-
-	for {
-		select {
-		case <-ctx.Done():
-			return
-		case <-ticker.C:
-			c.measure()
-		}
-	}
+	c.Runner(c.interval).Run(ctx, func(context.Context) { c.measure() })
 }
 
 func (c *CPUMetric) measure() {
-	cpu, err := cpu.Get()
+	sample, err := readCPU()
 	if err != nil {
 		logger.WriteError(metric.InfrastructureGroup, c.Name, err)
 		return
 	}
-	systemPercent := float64(cpu.System-c.prevSystem) / float64(cpu.Total-c.total) * 100
-	userPercent := float64(cpu.User-c.prevUser) / float64(cpu.Total-c.total) * 100
 
-	c.prevUser = cpu.User
-	c.prevSystem = cpu.System
-	c.total = cpu.Total
+	// The times are cumulative since boot, so the first sample only serves as baseline
+	prev := c.prev
+	c.prev = &sample
+	if prev == nil || sample.total.Total <= prev.total.Total {
+		return
+	}
 
-	c.writeMetric(systemPercent, userPercent)
+	elapsed := float64(sample.total.Total - prev.total.Total)
+	values := map[string]float64{
+		SystemCPUMeasurement: float64(sample.total.System-prev.total.System) / elapsed * 100,
+		UserCPUMeasurement:   float64(sample.total.User-prev.total.User) / elapsed * 100,
+		IOWaitMeasurement:    float64(sample.total.IOWait-prev.total.IOWait) / elapsed * 100,
+		StealMeasurement:     float64(sample.total.Steal-prev.total.Steal) / elapsed * 100,
+	}
+
+	// Cores going offline between samples change the list, the breakdown is then skipped once
+	if len(sample.cores) != 0 && len(sample.cores) == len(prev.cores) {
+		var maxCore, pegged float64
+		for i, core := range sample.cores {
+			utilization := core.busySince(prev.cores[i])
+			values[coreMeasurement(i)] = utilization
+			maxCore = max(maxCore, utilization)
+			if utilization > peggedCoreThreshold {
+				pegged++
+			}
+		}
+		values[MaxCoreMeasurement] = maxCore
+		values[PeggedCoresMeasurement] = pegged
+	}
+
+	c.writeMetric(values)
 }
 
-func (c *CPUMetric) writeMetric(systemPercent, userPercent float64) {
-	c.AddDataPoint(map[string]float64{
-		SystemCPUMeasurement: systemPercent,
-		UserCPUMeasurement:   userPercent,
-	})
+func (c *CPUMetric) writeMetric(values map[string]float64) {
+	c.AddDataPoint(values)
+
+	logValues := make(map[string]any, len(values))
+	for name, value := range values {
+		logValues[name] = value
+	}
+	logger.WriteMetric(metric.InfrastructureGroup, c.Name, logValues)
+}
+
+func (c *CPUMetric) AggregateResults() string {
+	var (
+		values      = make(map[string][]float64)
+		coreSums    = make(map[string]float64)
+		coreSamples int
+		peggedTime  int
+	)
+	for _, point := range c.Snapshot() {
+		for _, name := range []string{SystemCPUMeasurement, UserCPUMeasurement, IOWaitMeasurement, StealMeasurement, MaxCoreMeasurement} {
+			if value, ok := point.Values[name]; ok {
+				values[name] = append(values[name], value)
+			}
+		}
+
+		pegged, ok := point.Values[PeggedCoresMeasurement]
+		if !ok {
+			continue
+		}
+		coreSamples++
+		if pegged > 0 {
+			peggedTime++
+		}
+		for i := 0; ; i++ {
+			utilization, ok := point.Values[coreMeasurement(i)]
+			if !ok {
+				break
+			}
+			coreSums[coreMeasurement(i)] += utilization
+		}
+	}
+
+	result := fmt.Sprintf("user_P50=%s %%, system_P50=%s %%, iowait_P50=%s %%, steal_P50=%s %%",
+		format.Number(metric.CalculatePercentiles(values[UserCPUMeasurement], 50)[50], 2),
+		format.Number(metric.CalculatePercentiles(values[SystemCPUMeasurement], 50)[50], 2),
+		format.Number(metric.CalculatePercentiles(values[IOWaitMeasurement], 50)[50], 2),
+		format.Number(metric.CalculatePercentiles(values[StealMeasurement], 50)[50], 2))
+	if coreSamples == 0 {
+		return result
+	}
+
+	var busiest string
+	for name, sum := range coreSums {
+		if busiest == "" || sum > coreSums[busiest] || (sum == coreSums[busiest] && name < busiest) {
+			busiest = name
+		}
+	}
+	maxCore := metric.CalculatePercentiles(values[MaxCoreMeasurement], 50, 90)
+	return result + fmt.Sprintf(" \n max_core_P50=%s %%, max_core_P90=%s %%, pegged_%.0f_%%=%s %% of time, busiest=%s (avg %s %%)",
+		format.Number(maxCore[50], 2),
+		format.Number(maxCore[90], 2),
+		peggedCoreThreshold, format.Number(float64(peggedTime)/float64(coreSamples)*100, 2),
+		busiest, format.Number(coreSums[busiest]/float64(coreSamples), 2))
+}
+
+// busySince returns the share of the time since the previous times the core was not idle, in percent. I/O wait
+// counts as idle, the core is free to run other work meanwhile.
+func (t cpuTimes) busySince(prev cpuTimes) float64 {
+	if t.Total <= prev.Total {
+		return 0
+	}
+	elapsed := t.Total - prev.Total
+	idle := (t.Idle - prev.Idle) + (t.IOWait - prev.IOWait)
+	if idle > elapsed {
+		return 0
+	}
+	return float64(elapsed-idle) / float64(elapsed) * 100
+}
 
-	logger.WriteMetric(metric.InfrastructureGroup, c.Name, map[string]any{
-		SystemCPUMeasurement: systemPercent,
-		UserCPUMeasurement:   userPercent,
-	})
+func coreMeasurement(core int) string {
+	return fmt.Sprintf("Core%d", core)
 }
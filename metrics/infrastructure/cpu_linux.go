@@ -0,0 +1,82 @@
+package infrastructure
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+const procStatPath = "/proc/stat"
+
+// readCPU reads the times of all CPUs and of every core from /proc/stat
+func readCPU() (cpuSample, error) {
+	file, err := os.Open(procStatPath)
+	if err != nil {
+		return cpuSample{}, err
+	}
+	defer file.Close()
+
+	var (
+		sample cpuSample
+		found  bool
+	)
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || !strings.HasPrefix(fields[0], "cpu") {
+			continue
+		}
+
+		times, err := parseCPUTimes(fields[1:])
+		if err != nil {
+			return cpuSample{}, errors.Join(err, fmt.Errorf("failed parsing '%s' of %s", fields[0], procStatPath))
+		}
+		if fields[0] == "cpu" {
+			sample.total, found = times, true
+		} else {
+			sample.cores = append(sample.cores, times)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return cpuSample{}, err
+	}
+	if !found {
+		return cpuSample{}, fmt.Errorf("%s has no CPU times", procStatPath)
+	}
+	return sample, nil
+}
+
+// parseCPUTimes parses the columns user, nice, system, idle, iowait, irq, softirq and steal. Guest times are
+// already part of the user and nice times, so they are not added to the total.
+func parseCPUTimes(columns []string) (cpuTimes, error) {
+	if len(columns) < 4 {
+		return cpuTimes{}, fmt.Errorf("expected at least 4 columns, got %d", len(columns))
+	}
+
+	var values [8]uint64
+	for i := 0; i < len(values) && i < len(columns); i++ {
+		value, err := strconv.ParseUint(columns[i], 10, 64)
+		if err != nil {
+			return cpuTimes{}, err
+		}
+		values[i] = value
+	}
+
+	times := cpuTimes{
+		User:    values[0],
+		Nice:    values[1],
+		System:  values[2],
+		Idle:    values[3],
+		IOWait:  values[4],
+		IRQ:     values[5],
+		SoftIRQ: values[6],
+		Steal:   values[7],
+	}
+	for _, value := range values {
+		times.Total += value
+	}
+	return times, nil
+}
@@ -0,0 +1,22 @@
+//go:build !linux
+
+package infrastructure
+
+import "github.com/mackerelio/go-osstat/cpu"
+
+// readCPU reads the times of all CPUs, the platform doesn't expose the times of the cores
+func readCPU() (cpuSample, error) {
+	stats, err := cpu.Get()
+	if err != nil {
+		return cpuSample{}, err
+	}
+	return cpuSample{
+		total: cpuTimes{
+			User:   stats.User,
+			Nice:   stats.Nice,
+			System: stats.System,
+			Idle:   stats.Idle,
+			Total:  stats.Total,
+		},
+	}, nil
+}
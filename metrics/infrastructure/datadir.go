@@ -0,0 +1,216 @@
+package infrastructure
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"math"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/format"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/logger"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/metric"
+)
+
+const (
+	DataDirSizeMeasurement = "SizeGB"
+	// DataDirGrowthMeasurement is the growth of all data directories extrapolated to a day
+	DataDirGrowthMeasurement = "GrowthGBPerDay"
+	// DiskFullDaysMeasurement is the number of days until the first filesystem holding a data directory is full at
+	// the measured growth, it is not measured while the directories don't grow
+	DiskFullDaysMeasurement = "DiskFullDays"
+
+	gigabyte = 1024 * 1024 * 1024
+	day      = time.Hour * 24
+)
+
+type (
+	// DataDirMetric watches the size of the data directories of the clients (e.g. '/var/lib/lighthouse', geth chaindata)
+	// and projects when their disk is full at the growth measured over the run
+	DataDirMetric struct {
+		metric.Base[float64]
+		paths    []string
+		interval time.Duration
+		samples  map[string][]sizeSample
+		free     map[string]diskSpace
+		mutex    sync.Mutex
+	}
+
+	sizeSample struct {
+		at   time.Time
+		size uint64
+	}
+
+	// diskSpace is the free space of the filesystem holding a directory, directories on the same filesystem share the device
+	diskSpace struct {
+		device string
+		free   uint64
+	}
+)
+
+func NewDataDirMetric(name string, paths []string, interval time.Duration, healthCondition []metric.HealthCondition[float64]) *DataDirMetric {
+	return &DataDirMetric{
+		Base: metric.Base[float64]{
+			HealthConditions: healthCondition,
+			Name:             name,
+		},
+		paths:    paths,
+		interval: interval,
+		samples:  make(map[string][]sizeSample, len(paths)),
+		free:     make(map[string]diskSpace, len(paths)),
+	}
+}
+
+func (d *DataDirMetric) Measure(ctx context.Context) {
+	// The growth is measured from the size at the start of the run
+	d.Runner(d.interval).Run(ctx, d.measure)
+}
+
+func (d *DataDirMetric) measure(ctx context.Context) {
+	for _, path := range d.paths {
+		size, err := directorySize(ctx, path)
+		if err != nil {
+			logger.WriteError(metric.InfrastructureGroup, d.Name, errors.Join(err, fmt.Errorf("failed measuring the size of '%s'", path)))
+			continue
+		}
+		space, err := freeSpace(path)
+		if err != nil {
+			logger.WriteError(metric.InfrastructureGroup, d.Name, errors.Join(err, fmt.Errorf("failed measuring the free space of '%s'", path)))
+			continue
+		}
+
+		d.mutex.Lock()
+		d.samples[path] = append(d.samples[path], sizeSample{at: time.Now(), size: size})
+		d.free[path] = space
+		d.mutex.Unlock()
+	}
+
+	d.writeMetric()
+}
+
+// directorySize sums the size of the files below the path, files removed while walking (e.g. compacted database
+// files) are skipped
+func directorySize(ctx context.Context, path string) (uint64, error) {
+	var size uint64
+	err := filepath.WalkDir(path, func(current string, entry fs.DirEntry, err error) error {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		if err != nil {
+			if current != path && errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if !entry.Type().IsRegular() {
+			return nil
+		}
+		info, err := entry.Info()
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		size += uint64(info.Size())
+		return nil
+	})
+	return size, err
+}
+
+// growthPerDay extrapolates the growth between the first and the last sample to a day
+func growthPerDay(samples []sizeSample) (float64, bool) {
+	if len(samples) < 2 {
+		return 0, false
+	}
+	first, last := samples[0], samples[len(samples)-1]
+	elapsed := last.at.Sub(first.at)
+	if elapsed <= 0 {
+		return 0, false
+	}
+	return (float64(last.size) - float64(first.size)) / elapsed.Seconds() * day.Seconds(), true
+}
+
+// projection is the size, the growth per day in bytes and the days until the first filesystem is full
+func (d *DataDirMetric) projection() (size, growth, fullDays float64, grows bool) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	growthByDevice := make(map[string]float64)
+	freeByDevice := make(map[string]uint64)
+	for _, path := range d.paths {
+		samples := d.samples[path]
+		if len(samples) == 0 {
+			continue
+		}
+		size += float64(samples[len(samples)-1].size)
+
+		pathGrowth, ok := growthPerDay(samples)
+		if !ok {
+			continue
+		}
+		growth += pathGrowth
+		space := d.free[path]
+		growthByDevice[space.device] += pathGrowth
+		freeByDevice[space.device] = space.free
+	}
+
+	fullDays = math.Inf(1)
+	for device, deviceGrowth := range growthByDevice {
+		if deviceGrowth > 0 {
+			fullDays = math.Min(fullDays, float64(freeByDevice[device])/deviceGrowth)
+		}
+	}
+	return size, growth, fullDays, !math.IsInf(fullDays, 1)
+}
+
+func (d *DataDirMetric) writeMetric() {
+	size, growth, fullDays, grows := d.projection()
+
+	values := map[string]float64{
+		DataDirSizeMeasurement:   size / gigabyte,
+		DataDirGrowthMeasurement: growth / gigabyte,
+	}
+	logValues := map[string]any{
+		DataDirSizeMeasurement:   format.Bytes(size),
+		DataDirGrowthMeasurement: format.Bytes(growth) + "/day",
+	}
+	if grows {
+		values[DiskFullDaysMeasurement] = fullDays
+		logValues[DiskFullDaysMeasurement] = format.Number(fullDays, 1)
+	}
+
+	d.AddDataPoint(values)
+	logger.WriteMetric(metric.InfrastructureGroup, d.Name, logValues)
+}
+
+func (d *DataDirMetric) AggregateResults() string {
+	size, growth, fullDays, grows := d.projection()
+
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	var directories []string
+	for _, path := range d.paths {
+		samples := d.samples[path]
+		if len(samples) == 0 {
+			directories = append(directories, fmt.Sprintf("%s: not measured", path))
+			continue
+		}
+		directory := fmt.Sprintf("%s: %s", path, format.Bytes(float64(samples[len(samples)-1].size)))
+		if pathGrowth, ok := growthPerDay(samples); ok {
+			directory += fmt.Sprintf(" (%s/day)", format.Bytes(pathGrowth))
+		}
+		directories = append(directories, directory)
+	}
+
+	summary := fmt.Sprintf("Size: %s, Growth: %s/day", format.Bytes(size), format.Bytes(growth))
+	if grows {
+		summary += fmt.Sprintf(", disk full in %s days", format.Number(fullDays, 1))
+	}
+	return summary + " \n " + strings.Join(directories, " \n ")
+}
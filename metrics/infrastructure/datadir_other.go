@@ -0,0 +1,9 @@
+//go:build !linux && !darwin
+
+package infrastructure
+
+import "errors"
+
+func freeSpace(string) (diskSpace, error) {
+	return diskSpace{}, errors.New("free disk space is not supported on this platform")
+}
@@ -0,0 +1,20 @@
+//go:build linux || darwin
+
+package infrastructure
+
+import (
+	"fmt"
+	"syscall"
+)
+
+// freeSpace returns the space of the filesystem holding the path which is available to unprivileged users
+func freeSpace(path string) (diskSpace, error) {
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(path, &stat); err != nil {
+		return diskSpace{}, err
+	}
+	return diskSpace{
+		device: fmt.Sprint(stat.Fsid),
+		free:   uint64(stat.Bavail) * uint64(stat.Bsize),
+	}, nil
+}
@@ -0,0 +1,204 @@
+package infrastructure
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mackerelio/go-osstat/memory"
+
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/format"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/logger"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/metric"
+)
+
+const (
+	// UndervoltageMeasurement and ThrottledMeasurement report whether the board is currently undervolted or throttled
+	UndervoltageMeasurement = "Undervoltage"
+	ThrottledMeasurement    = "Throttled"
+	// SDCardMeasurement reports whether a data directory is stored on an SD card or eMMC
+	SDCardMeasurement = "SDCard"
+
+	minMemory         = 8 * gigabyte
+	recommendedMemory = 16 * gigabyte
+	recommendedCores  = 4
+)
+
+type (
+	// StorageKind is the kind of device a directory is stored on
+	StorageKind string
+
+	// throttling are the flags of the firmware of the Raspberry Pi, e.g. reported by 'vcgencmd get_throttled'
+	throttling struct {
+		undervoltage, capped, throttled, softTempLimit bool
+	}
+
+	// HardwareMetric checks the health of ARM boards such as the Raspberry Pi: undervoltage and throttling reported
+	// by the firmware and data stored on SD cards, and concludes whether the hardware suits a node
+	HardwareMetric struct {
+		metric.Base[float64]
+		paths    []string
+		interval time.Duration
+		storage  map[string]StorageKind
+		memory   uint64
+		occurred throttling
+		mutex    sync.Mutex
+	}
+)
+
+const (
+	StorageSDCard     StorageKind = "SD card/eMMC"
+	StorageSSD        StorageKind = "SSD"
+	StorageRotational StorageKind = "HDD"
+	StorageUnknown    StorageKind = "unknown"
+)
+
+// throttlingFlags decodes the bits of 'get_throttled': 0 undervoltage, 1 frequency capped, 2 throttled and
+// 3 soft temperature limit, the bits 16 to 19 tell the same conditions occurred since boot
+func throttlingFlags(value uint64, shift uint) throttling {
+	return throttling{
+		undervoltage:  value>>shift&1 == 1,
+		capped:        value>>(shift+1)&1 == 1,
+		throttled:     value>>(shift+2)&1 == 1,
+		softTempLimit: value>>(shift+3)&1 == 1,
+	}
+}
+
+// NewHardwareMetric checks the storage of the paths, the root filesystem when none are passed
+func NewHardwareMetric(name string, paths []string, interval time.Duration, healthCondition []metric.HealthCondition[float64]) *HardwareMetric {
+	if len(paths) == 0 {
+		paths = []string{"/"}
+	}
+	return &HardwareMetric{
+		Base: metric.Base[float64]{
+			HealthConditions: healthCondition,
+			Name:             name,
+		},
+		paths:    paths,
+		interval: interval,
+		storage:  make(map[string]StorageKind, len(paths)),
+	}
+}
+
+func (h *HardwareMetric) Measure(ctx context.Context) {
+	// The storage and memory don't change during the run
+	h.mutex.Lock()
+	for _, path := range h.paths {
+		kind, err := storageKind(path)
+		if err != nil {
+			logger.WriteError(metric.InfrastructureGroup, h.Name, err)
+		}
+		h.storage[path] = kind
+	}
+	if stats, err := memory.Get(); err == nil {
+		h.memory = stats.Total
+	} else {
+		logger.WriteError(metric.InfrastructureGroup, h.Name, err)
+	}
+	h.mutex.Unlock()
+
+	h.Runner(h.interval).Run(ctx, func(context.Context) { h.measure() })
+}
+
+func (h *HardwareMetric) measure() {
+	h.mutex.Lock()
+	values := map[string]float64{SDCardMeasurement: 0}
+	for _, kind := range h.storage {
+		if kind == StorageSDCard {
+			values[SDCardMeasurement] = 1
+		}
+	}
+	h.mutex.Unlock()
+
+	// Boards without the firmware interface, e.g. any non Raspberry Pi machine, only get the storage checked
+	value, err := readThrottled()
+	if err == nil {
+		current := throttlingFlags(value, 0)
+		values[UndervoltageMeasurement] = boolToFloat(current.undervoltage)
+		values[ThrottledMeasurement] = boolToFloat(current.capped || current.throttled || current.softTempLimit)
+
+		occurred := throttlingFlags(value, 16)
+		h.mutex.Lock()
+		h.occurred.undervoltage = h.occurred.undervoltage || current.undervoltage || occurred.undervoltage
+		h.occurred.capped = h.occurred.capped || current.capped || occurred.capped
+		h.occurred.throttled = h.occurred.throttled || current.throttled || occurred.throttled
+		h.occurred.softTempLimit = h.occurred.softTempLimit || current.softTempLimit || occurred.softTempLimit
+		h.mutex.Unlock()
+	} else {
+		slog.With("metric_name", h.Name).With("err", err.Error()).Debug("throttling flags are not available")
+	}
+
+	h.AddDataPoint(values)
+
+	logValues := make(map[string]any, len(values))
+	for name, value := range values {
+		logValues[name] = value == 1
+	}
+	logger.WriteMetric(metric.InfrastructureGroup, h.Name, logValues)
+}
+
+func (h *HardwareMetric) AggregateResults() string {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	storage := make([]string, 0, len(h.paths))
+	for _, path := range h.paths {
+		storage = append(storage, fmt.Sprintf("%s=%s", path, h.storage[path]))
+	}
+
+	verdict, reasons := h.verdict()
+	result := fmt.Sprintf("arch=%s, cores=%d, memory=%s, storage: %s \n verdict: %s",
+		runtime.GOARCH, runtime.NumCPU(), format.Bytes(float64(h.memory)), strings.Join(storage, ", "), verdict)
+	if len(reasons) != 0 {
+		result += " (" + strings.Join(reasons, ", ") + ")"
+	}
+	return result
+}
+
+// verdict concludes whether the hardware suits a node, listing the reasons of a worse verdict than 'suitable'
+func (h *HardwareMetric) verdict() (string, []string) {
+	var unsuitable, marginal []string
+
+	for _, path := range h.paths {
+		switch h.storage[path] {
+		case StorageSDCard:
+			unsuitable = append(unsuitable, fmt.Sprintf("%s on SD card/eMMC", path))
+		case StorageRotational:
+			marginal = append(marginal, fmt.Sprintf("%s on HDD", path))
+		}
+	}
+	if h.occurred.undervoltage {
+		unsuitable = append(unsuitable, "undervoltage")
+	}
+	if h.occurred.capped || h.occurred.throttled || h.occurred.softTempLimit {
+		marginal = append(marginal, "throttled")
+	}
+	if h.memory != 0 && h.memory < minMemory {
+		unsuitable = append(unsuitable, "less than 8GB memory")
+	} else if h.memory != 0 && h.memory < recommendedMemory {
+		marginal = append(marginal, "less than 16GB memory")
+	}
+	if runtime.NumCPU() < recommendedCores {
+		marginal = append(marginal, fmt.Sprintf("less than %d cores", recommendedCores))
+	}
+
+	switch {
+	case len(unsuitable) != 0:
+		return "unsuitable", append(unsuitable, marginal...)
+	case len(marginal) != 0:
+		return "marginal", marginal
+	default:
+		return "suitable", nil
+	}
+}
+
+func boolToFloat(value bool) float64 {
+	if value {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,117 @@
+package infrastructure
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// throttledPath is exposed by the firmware driver of the Raspberry Pi kernels
+	throttledPath = "/sys/devices/platform/soc/soc:firmware/get_throttled"
+	mountsPath    = "/proc/mounts"
+)
+
+// readThrottled reads the throttling flags from sysfs, falling back to 'vcgencmd get_throttled'
+func readThrottled() (uint64, error) {
+	if content, err := os.ReadFile(throttledPath); err == nil {
+		return strconv.ParseUint(strings.TrimSpace(string(content)), 16, 64)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	output, err := exec.CommandContext(ctx, "vcgencmd", "get_throttled").Output()
+	if err != nil {
+		return 0, errors.Join(err, errors.New("neither the firmware sysfs nor vcgencmd provided the throttling flags"))
+	}
+
+	// The output is e.g. 'throttled=0x50000'
+	value, ok := strings.CutPrefix(strings.TrimSpace(string(output)), "throttled=")
+	if !ok {
+		return 0, fmt.Errorf("unexpected output of vcgencmd: '%s'", output)
+	}
+	return strconv.ParseUint(strings.TrimPrefix(value, "0x"), 16, 64)
+}
+
+// storageKind finds the block device of the filesystem holding the path and tells SD cards and eMMC (mmcblk) apart
+// from solid state and rotational disks
+func storageKind(path string) (StorageKind, error) {
+	device, err := mountDevice(path)
+	if err != nil {
+		return StorageUnknown, err
+	}
+	if !strings.HasPrefix(device, "/dev/") {
+		// Network and virtual filesystems, e.g. tmpfs or overlay
+		return StorageUnknown, nil
+	}
+
+	disk := diskOf(filepath.Base(device))
+	if strings.HasPrefix(disk, "mmcblk") {
+		return StorageSDCard, nil
+	}
+
+	rotational, err := os.ReadFile(filepath.Join("/sys/block", disk, "queue/rotational"))
+	if err != nil {
+		return StorageUnknown, nil
+	}
+	if strings.TrimSpace(string(rotational)) == "1" {
+		return StorageRotational, nil
+	}
+	return StorageSSD, nil
+}
+
+// mountDevice returns the device mounted at the longest mount point containing the path
+func mountDevice(path string) (string, error) {
+	absolute, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+	if resolved, err := filepath.EvalSymlinks(absolute); err == nil {
+		absolute = resolved
+	}
+
+	file, err := os.Open(mountsPath)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	var device, mountPoint string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 {
+			continue
+		}
+		point := fields[1]
+		contained := absolute == point || point == "/" || strings.HasPrefix(absolute, point+"/")
+		if contained && len(point) >= len(mountPoint) {
+			device, mountPoint = fields[0], point
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return "", err
+	}
+	if device == "" {
+		return "", fmt.Errorf("no filesystem of %s holds '%s'", mountsPath, path)
+	}
+	return device, nil
+}
+
+// diskOf strips the partition of a block device name, e.g. 'mmcblk0p2' and 'nvme0n1p1' end with 'p<N>', 'sda1' with '<N>'
+func diskOf(name string) string {
+	if strings.HasPrefix(name, "mmcblk") || strings.HasPrefix(name, "nvme") {
+		if i := strings.LastIndex(name, "p"); i > 0 && name[i-1] >= '0' && name[i-1] <= '9' {
+			return name[:i]
+		}
+		return name
+	}
+	return strings.TrimRight(name, "0123456789")
+}
@@ -0,0 +1,13 @@
+//go:build !linux
+
+package infrastructure
+
+import "errors"
+
+func readThrottled() (uint64, error) {
+	return 0, errors.New("throttling flags are not supported on this platform")
+}
+
+func storageKind(string) (StorageKind, error) {
+	return StorageUnknown, nil
+}
@@ -0,0 +1,133 @@
+package infrastructure
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"runtime"
+	"time"
+
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/format"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/logger"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/metric"
+)
+
+const (
+	// Load1Measurement, Load5Measurement and Load15Measurement are the load averages divided by the number of cores,
+	// from 1 on tasks queue for a core
+	Load1Measurement  = "Load1"
+	Load5Measurement  = "Load5"
+	Load15Measurement = "Load15"
+	// SchedulerLatencyMeasurement is the average time in milliseconds a task waited on a run queue per time slice
+	SchedulerLatencyMeasurement = "SchedulerLatencyMs"
+)
+
+type (
+	// schedulerStats are the cumulative run queue statistics of all cores
+	schedulerStats struct {
+		waiting    time.Duration
+		timeslices uint64
+	}
+
+	// LoadMetric tracks the load averages normalized by the core count and optionally the scheduler latency. A
+	// saturated run queue explains latency spikes the CPU utilization alone doesn't.
+	LoadMetric struct {
+		metric.Base[float64]
+		interval  time.Duration
+		cores     int
+		scheduler bool
+		prev      *schedulerStats
+	}
+)
+
+func NewLoadMetric(name string, interval time.Duration, healthCondition []metric.HealthCondition[float64]) *LoadMetric {
+	return &LoadMetric{
+		Base: metric.Base[float64]{
+			HealthConditions: healthCondition,
+			Name:             name,
+		},
+		interval: interval,
+		cores:    runtime.NumCPU(),
+	}
+}
+
+// WithSchedulerLatency enables sampling the scheduler latency from /proc/schedstat
+func (l *LoadMetric) WithSchedulerLatency(enabled bool) *LoadMetric {
+	l.scheduler = enabled
+	return l
+}
+
+func (l *LoadMetric) Measure(ctx context.Context) {
+	l.Runner(l.interval).Run(ctx, func(context.Context) { l.measure() })
+}
+
+func (l *LoadMetric) measure() {
+	averages, err := readLoadAverages()
+	if err != nil {
+		logger.WriteError(metric.InfrastructureGroup, l.Name, err)
+		return
+	}
+
+	cores := float64(l.cores)
+	values := map[string]float64{
+		Load1Measurement:  averages[0] / cores,
+		Load5Measurement:  averages[1] / cores,
+		Load15Measurement: averages[2] / cores,
+	}
+
+	if l.scheduler {
+		if latency, ok := l.schedulerLatency(); ok {
+			values[SchedulerLatencyMeasurement] = latency
+		}
+	}
+
+	l.AddDataPoint(values)
+
+	logValues := make(map[string]any, len(values))
+	for name, value := range values {
+		logValues[name] = value
+	}
+	logger.WriteMetric(metric.InfrastructureGroup, l.Name, logValues)
+}
+
+// schedulerLatency returns the average run queue wait per time slice since the previous sample in milliseconds. The
+// sampling is disabled when the kernel doesn't provide the statistics.
+func (l *LoadMetric) schedulerLatency() (float64, bool) {
+	stats, err := readSchedulerStats()
+	if err != nil {
+		slog.With("metric_name", l.Name).With("err", err.Error()).Warn("scheduler statistics are not available, scheduler latency sampling was disabled")
+		l.scheduler = false
+		return 0, false
+	}
+
+	prev := l.prev
+	l.prev = &stats
+	if prev == nil || stats.timeslices <= prev.timeslices {
+		return 0, false
+	}
+	waiting := stats.waiting - prev.waiting
+	return float64(waiting) / float64(stats.timeslices-prev.timeslices) / float64(time.Millisecond), true
+}
+
+func (l *LoadMetric) AggregateResults() string {
+	values := make(map[string][]float64)
+	for _, point := range l.Snapshot() {
+		for name, value := range point.Values {
+			values[name] = append(values[name], value)
+		}
+	}
+
+	load5 := metric.CalculatePercentiles(values[Load5Measurement], 50, 100)
+	result := fmt.Sprintf("cores=%d, load1_max=%s, load5_P50=%s, load5_max=%s, load15_max=%s (per core)",
+		l.cores,
+		format.Number(metric.CalculatePercentiles(values[Load1Measurement], 100)[100], 2),
+		format.Number(load5[50], 2),
+		format.Number(load5[100], 2),
+		format.Number(metric.CalculatePercentiles(values[Load15Measurement], 100)[100], 2))
+	if latencies := values[SchedulerLatencyMeasurement]; len(latencies) != 0 {
+		percentiles := metric.CalculatePercentiles(latencies, 50, 90)
+		result += fmt.Sprintf(" \n scheduler_latency_P50=%sms, scheduler_latency_P90=%sms",
+			format.Number(percentiles[50], 3), format.Number(percentiles[90], 3))
+	}
+	return result
+}
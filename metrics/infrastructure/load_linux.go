@@ -0,0 +1,76 @@
+package infrastructure
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	loadAvgPath    = "/proc/loadavg"
+	schedStatsPath = "/proc/schedstat"
+)
+
+// readLoadAverages reads the 1, 5 and 15 minutes load averages, e.g. '0.24 0.20 0.18 2/79 14993'
+func readLoadAverages() ([3]float64, error) {
+	var averages [3]float64
+
+	content, err := os.ReadFile(loadAvgPath)
+	if err != nil {
+		return averages, err
+	}
+	fields := strings.Fields(string(content))
+	if len(fields) < len(averages) {
+		return averages, fmt.Errorf("unexpected content of %s: '%s'", loadAvgPath, content)
+	}
+	for i := range averages {
+		if averages[i], err = strconv.ParseFloat(fields[i], 64); err != nil {
+			return averages, errors.Join(err, fmt.Errorf("failed parsing %s", loadAvgPath))
+		}
+	}
+	return averages, nil
+}
+
+// readSchedulerStats sums the run queue statistics of the cores. The last three columns of the 'cpu<N>' lines are
+// the time tasks ran, the time they waited on the run queue, both in nanoseconds, and the number of time slices.
+func readSchedulerStats() (schedulerStats, error) {
+	file, err := os.Open(schedStatsPath)
+	if err != nil {
+		return schedulerStats{}, err
+	}
+	defer file.Close()
+
+	var (
+		stats schedulerStats
+		cores int
+	)
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 10 || !strings.HasPrefix(fields[0], "cpu") {
+			continue
+		}
+		waiting, err := strconv.ParseUint(fields[8], 10, 64)
+		if err != nil {
+			return stats, errors.Join(err, fmt.Errorf("failed parsing %s", schedStatsPath))
+		}
+		timeslices, err := strconv.ParseUint(fields[9], 10, 64)
+		if err != nil {
+			return stats, errors.Join(err, fmt.Errorf("failed parsing %s", schedStatsPath))
+		}
+		stats.waiting += time.Duration(waiting)
+		stats.timeslices += timeslices
+		cores++
+	}
+	if err := scanner.Err(); err != nil {
+		return stats, err
+	}
+	if cores == 0 {
+		return stats, fmt.Errorf("%s has no run queue statistics", schedStatsPath)
+	}
+	return stats, nil
+}
@@ -0,0 +1,13 @@
+//go:build !linux
+
+package infrastructure
+
+import "errors"
+
+func readLoadAverages() ([3]float64, error) {
+	return [3]float64{}, errors.New("load averages are not supported on this platform")
+}
+
+func readSchedulerStats() (schedulerStats, error) {
+	return schedulerStats{}, errors.New("scheduler statistics are not supported on this platform")
+}
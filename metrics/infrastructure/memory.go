@@ -3,13 +3,13 @@ package infrastructure
 import (
 	"context"
 	"fmt"
-	"log/slog"
 	"time"
 
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/exporter"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/format"
 	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/logger"
 	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/metric"
 	"github.com/mackerelio/go-osstat/memory"
-	"github.com/prometheus/client_golang/prometheus"
 )
 
 const (
@@ -35,21 +35,10 @@ func NewMemoryMetric(name string, interval time.Duration, healthCondition []metr
 }
 
 func (m *MemoryMetric) Measure(ctx context.Context) {
-	ticker := time.NewTicker(m.interval)
-	defer ticker.Stop()
-
-	for {
-		select {
-		case <-ctx.Done():
-			slog.With("metric_name", m.Name).Debug("memory metric was stopped")
-			return
-		case <-ticker.C:
-			m.measure()
-		}
-	}
+	m.Runner(m.interval).Run(ctx, m.measure)
 }
 
-func (m *MemoryMetric) measure() {
+func (m *MemoryMetric) measure(ctx context.Context) {
 	// Get the memory stats from the system
 	memoryStats, err := memory.Get()
 	if err != nil {
@@ -58,10 +47,10 @@ func (m *MemoryMetric) measure() {
 	}
 
 	// Log and record the memory metrics
-	m.writeMetric(memoryStats.Cached, memoryStats.Used, memoryStats.Free, memoryStats.Total)
+	m.writeMetric(ctx, memoryStats.Cached, memoryStats.Used, memoryStats.Free, memoryStats.Total)
 }
 
-func (m *MemoryMetric) writeMetric(cached, used, free, total uint64) {
+func (m *MemoryMetric) writeMetric(ctx context.Context, cached, used, free, total uint64) {
 	// Record the data points in memory metrics
 	m.AddDataPoint(map[string]uint64{
 		CachedMemoryMeasurement: cached,
@@ -71,10 +60,10 @@ func (m *MemoryMetric) writeMetric(cached, used, free, total uint64) {
 	})
 
 	// Push memory metrics to Prometheus
-	memoryUsageMetric.With(prometheus.Labels{memoryUsageTypeLabel: "cached"}).Set(float64(cached))
-	memoryUsageMetric.With(prometheus.Labels{memoryUsageTypeLabel: "used"}).Set(float64(used))
-	memoryUsageMetric.With(prometheus.Labels{memoryUsageTypeLabel: "free"}).Set(float64(free))
-	memoryUsageMetric.With(prometheus.Labels{memoryUsageTypeLabel: "total"}).Set(float64(total))
+	exporter.SetMemory(ctx, "cached", cached)
+	exporter.SetMemory(ctx, "used", used)
+	exporter.SetMemory(ctx, "free", free)
+	exporter.SetMemory(ctx, "total", total)
 
 	// Log the memory usage data
 	logger.WriteMetric(metric.InfrastructureGroup, m.Name, map[string]any{
@@ -89,19 +78,19 @@ func (m *MemoryMetric) AggregateResults() string {
 	// Prepare to calculate and display the percentiles
 	var values map[string][]float64 = make(map[string][]float64)
 
-	for _, point := range m.DataPoints {
-		values[TotalMemoryMeasurement] = append(values[TotalMemoryMeasurement], toMegabytes(point.Values[TotalMemoryMeasurement]))
-		values[FreeMemoryMeasurement] = append(values[FreeMemoryMeasurement], toMegabytes(point.Values[FreeMemoryMeasurement]))
-		values[UsedMemoryMeasurement] = append(values[UsedMemoryMeasurement], toMegabytes(point.Values[UsedMemoryMeasurement]))
-		values[CachedMemoryMeasurement] = append(values[CachedMemoryMeasurement], toMegabytes(point.Values[CachedMemoryMeasurement]))
+	for _, point := range m.Snapshot() {
+		values[TotalMemoryMeasurement] = append(values[TotalMemoryMeasurement], float64(point.Values[TotalMemoryMeasurement]))
+		values[FreeMemoryMeasurement] = append(values[FreeMemoryMeasurement], float64(point.Values[FreeMemoryMeasurement]))
+		values[UsedMemoryMeasurement] = append(values[UsedMemoryMeasurement], float64(point.Values[UsedMemoryMeasurement]))
+		values[CachedMemoryMeasurement] = append(values[CachedMemoryMeasurement], float64(point.Values[CachedMemoryMeasurement]))
 	}
 
 	// Return a formatted string with the 50th percentile (P50) for each memory category
-	return fmt.Sprintf("total_P50=%.2fMB, used_P50=%.2fMB, cached_P50=%.2fMB, free_P50=%.2fMB",
-		metric.CalculatePercentiles(values[TotalMemoryMeasurement], 50)[50],
-		metric.CalculatePercentiles(values[UsedMemoryMeasurement], 50)[50],
-		metric.CalculatePercentiles(values[CachedMemoryMeasurement], 50)[50],
-		metric.CalculatePercentiles(values[FreeMemoryMeasurement], 50)[50])
+	return fmt.Sprintf("total_P50=%s, used_P50=%s, cached_P50=%s, free_P50=%s",
+		format.Bytes(metric.CalculatePercentiles(values[TotalMemoryMeasurement], 50)[50]),
+		format.Bytes(metric.CalculatePercentiles(values[UsedMemoryMeasurement], 50)[50]),
+		format.Bytes(metric.CalculatePercentiles(values[CachedMemoryMeasurement], 50)[50]),
+		format.Bytes(metric.CalculatePercentiles(values[FreeMemoryMeasurement], 50)[50]))
 }
 
 func toMegabytes(bytes uint64) float64 {
@@ -0,0 +1,75 @@
+package infrastructure
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/metric"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/netstat"
+)
+
+type (
+	// PortExposure is a listening socket reachable from other hosts which serves an API that should stay private
+	PortExposure struct {
+		Listener netstat.Listener     `json:"listener"`
+		Service  string               `json:"service"`
+		Severity metric.SeverityLevel `json:"severity"`
+	}
+
+	sensitivePort struct {
+		service  string
+		severity metric.SeverityLevel
+	}
+)
+
+// sensitivePorts are the default TCP ports of the client APIs, anyone reaching an RPC or keymanager API can e.g.
+// drain an unlocked account or delete the validator keys
+var sensitivePorts = map[uint16]sensitivePort{
+	8545:  {"execution JSON-RPC", metric.SeverityHigh},
+	8546:  {"execution WebSocket RPC", metric.SeverityHigh},
+	8551:  {"engine API", metric.SeverityMedium},
+	5062:  {"validator client keymanager API", metric.SeverityHigh},
+	7500:  {"Prysm validator web and keymanager API", metric.SeverityHigh},
+	5052:  {"beacon node API", metric.SeverityMedium},
+	3500:  {"Prysm beacon node API", metric.SeverityMedium},
+	4000:  {"Prysm beacon node gRPC", metric.SeverityMedium},
+	9596:  {"Lodestar beacon node API", metric.SeverityMedium},
+	6060:  {"execution metrics and pprof", metric.SeverityMedium},
+	18550: {"MEV-Boost API", metric.SeverityMedium},
+	5054:  {"Lighthouse beacon node metrics", metric.SeverityLow},
+	5064:  {"Lighthouse validator client metrics", metric.SeverityLow},
+	8008:  {"client metrics", metric.SeverityLow},
+	8080:  {"Prysm beacon node metrics", metric.SeverityLow},
+	8081:  {"Prysm validator client metrics", metric.SeverityLow},
+	9090:  {"Prometheus", metric.SeverityLow},
+	3000:  {"Grafana", metric.SeverityLow},
+}
+
+// AuditPorts flags the exposed listeners serving a sensitive API, the most severe first. Peer-to-peer ports are
+// meant to be public and aren't flagged.
+func AuditPorts(listeners []netstat.Listener) []PortExposure {
+	var exposures []PortExposure
+	seen := make(map[string]struct{})
+	for _, listener := range listeners {
+		port, ok := sensitivePorts[listener.Port]
+		if !ok || listener.Protocol != netstat.TCP || !listener.Exposed() {
+			continue
+		}
+		// Dual stack sockets can be listed twice
+		if _, ok := seen[listener.String()]; ok {
+			continue
+		}
+		seen[listener.String()] = struct{}{}
+
+		exposures = append(exposures, PortExposure{Listener: listener, Service: port.service, Severity: port.severity})
+	}
+
+	sort.SliceStable(exposures, func(i, j int) bool {
+		return metric.CompareSeverities(exposures[i].Severity, exposures[j].Severity) > 0
+	})
+	return exposures
+}
+
+func (p PortExposure) String() string {
+	return fmt.Sprintf("%s is reachable on %s", p.Service, p.Listener)
+}
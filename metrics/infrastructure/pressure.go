@@ -0,0 +1,157 @@
+package infrastructure
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/format"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/logger"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/metric"
+)
+
+const (
+	SwapUsedMeasurement = "SwapUsed"
+	// SwapInMeasurement and SwapOutMeasurement are the pages swapped per second
+	SwapInMeasurement  = "SwapIn"
+	SwapOutMeasurement = "SwapOut"
+	// PressureSomeMeasurement and PressureFullMeasurement are the shares of the last 10 seconds some or all tasks
+	// stalled waiting for memory, as reported by the pressure stall information (PSI) of the kernel
+	PressureSomeMeasurement = "PressureSome"
+	PressureFullMeasurement = "PressureFull"
+	// OOMKillsMeasurement counts the processes killed by the OOM killer since the start of the run
+	OOMKillsMeasurement = "OOMKills"
+)
+
+type (
+	// pressureSample are the memory pressure readings of the kernel, the swap and OOM kill counters are cumulative
+	// since boot
+	pressureSample struct {
+		at       time.Time
+		swapUsed uint64
+		swapIn   uint64
+		swapOut  uint64
+		oomKills uint64
+		psi      bool
+		psiSome  float64
+		psiFull  float64
+	}
+
+	// MemoryPressureMetric tracks swapping, stalls for memory and OOM kills. OOM killed clients are a common failure
+	// of nodes short of memory, the kernel log names the killed processes when the benchmark may read it.
+	MemoryPressureMetric struct {
+		metric.Base[float64]
+		interval time.Duration
+		first    *pressureSample
+		prev     *pressureSample
+		victims  []string
+		mutex    sync.Mutex
+	}
+)
+
+func NewMemoryPressureMetric(name string, interval time.Duration, healthCondition []metric.HealthCondition[float64]) *MemoryPressureMetric {
+	return &MemoryPressureMetric{
+		Base: metric.Base[float64]{
+			HealthConditions: healthCondition,
+			Name:             name,
+		},
+		interval: interval,
+	}
+}
+
+func (m *MemoryPressureMetric) Measure(ctx context.Context) {
+	kernelLog, err := openKernelLog()
+	if err != nil {
+		slog.With("metric_name", m.Name).With("err", err.Error()).Warn("kernel log is not readable, OOM killed processes are counted but not named")
+	} else {
+		defer kernelLog.Close()
+	}
+
+	// The counters are measured from their values at the start of the run
+	m.Runner(m.interval).Run(ctx, func(context.Context) { m.measure(kernelLog) })
+}
+
+func (m *MemoryPressureMetric) measure(kernelLog *kernelLog) {
+	sample, err := readPressure()
+	if err != nil {
+		logger.WriteError(metric.InfrastructureGroup, m.Name, err)
+		return
+	}
+
+	var victims []string
+	if kernelLog != nil {
+		victims = kernelLog.oomVictims()
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	prev := m.prev
+	m.prev = &sample
+	if m.first == nil {
+		m.first = &sample
+	}
+	m.victims = append(m.victims, victims...)
+	if prev == nil {
+		return
+	}
+
+	values := map[string]float64{
+		SwapUsedMeasurement: float64(sample.swapUsed),
+		OOMKillsMeasurement: float64(sample.oomKills - m.first.oomKills),
+	}
+	if elapsed := sample.at.Sub(prev.at).Seconds(); elapsed > 0 {
+		values[SwapInMeasurement] = float64(sample.swapIn-prev.swapIn) / elapsed
+		values[SwapOutMeasurement] = float64(sample.swapOut-prev.swapOut) / elapsed
+	}
+	if sample.psi {
+		values[PressureSomeMeasurement] = sample.psiSome
+		values[PressureFullMeasurement] = sample.psiFull
+	}
+
+	m.AddDataPoint(values)
+
+	logValues := make(map[string]any, len(values)+1)
+	for name, value := range values {
+		logValues[name] = value
+	}
+	if len(victims) != 0 {
+		logValues["OOMKilled"] = victims
+	}
+	logger.WriteMetric(metric.InfrastructureGroup, m.Name, logValues)
+}
+
+func (m *MemoryPressureMetric) AggregateResults() string {
+	values := make(map[string][]float64)
+	for _, point := range m.Snapshot() {
+		for name, value := range point.Values {
+			values[name] = append(values[name], value)
+		}
+	}
+
+	maxOf := func(name string) float64 {
+		return metric.CalculatePercentiles(values[name], 100)[100]
+	}
+
+	result := fmt.Sprintf("swap_used_max=%s, swap_in_P90=%s pages/s, swap_out_P90=%s pages/s",
+		format.Bytes(maxOf(SwapUsedMeasurement)),
+		format.Number(metric.CalculatePercentiles(values[SwapInMeasurement], 90)[90], 2),
+		format.Number(metric.CalculatePercentiles(values[SwapOutMeasurement], 90)[90], 2))
+	if len(values[PressureSomeMeasurement]) != 0 {
+		result += fmt.Sprintf(", psi_some_max=%s %%, psi_full_max=%s %%",
+			format.Number(maxOf(PressureSomeMeasurement), 2),
+			format.Number(maxOf(PressureFullMeasurement), 2))
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	result += fmt.Sprintf(" \n oom_kills=%.0f", maxOf(OOMKillsMeasurement))
+	if len(m.victims) != 0 {
+		result += fmt.Sprintf(" (%s)", strings.Join(m.victims, ", "))
+	}
+	return result
+}
@@ -0,0 +1,149 @@
+package infrastructure
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+const (
+	meminfoPath        = "/proc/meminfo"
+	vmstatPath         = "/proc/vmstat"
+	memoryPressurePath = "/proc/pressure/memory"
+	kernelLogPath      = "/dev/kmsg"
+)
+
+// oomKilledPattern matches the kernel log record of an OOM kill, e.g. 'Out of memory: Killed process 1234 (geth)'
+var oomKilledPattern = regexp.MustCompile(`Killed process (\d+) \(([^)]+)\)`)
+
+// kernelLog reads the records the kernel logs after it was opened
+type kernelLog struct {
+	fd int
+}
+
+// readPressure reads the swap usage from /proc/meminfo, the swap and OOM kill counters from /proc/vmstat and the
+// memory pressure from /proc/pressure/memory, which requires a kernel with PSI enabled
+func readPressure() (pressureSample, error) {
+	sample := pressureSample{at: time.Now()}
+
+	meminfo, err := readKeyValues(meminfoPath)
+	if err != nil {
+		return sample, err
+	}
+	// The swap of /proc/meminfo is in kB
+	if total, free := meminfo["SwapTotal:"], meminfo["SwapFree:"]; total > free {
+		sample.swapUsed = (total - free) * 1024
+	}
+
+	vmstat, err := readKeyValues(vmstatPath)
+	if err != nil {
+		return sample, err
+	}
+	sample.swapIn = vmstat["pswpin"]
+	sample.swapOut = vmstat["pswpout"]
+	sample.oomKills = vmstat["oom_kill"]
+
+	some, full, err := readMemoryPressure()
+	if err == nil {
+		sample.psi, sample.psiSome, sample.psiFull = true, some, full
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return sample, err
+	}
+
+	return sample, nil
+}
+
+// readKeyValues reads the '<key> <value>' lines of a proc file, the unit following the value is ignored
+func readKeyValues(path string) (map[string]uint64, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	values := make(map[string]uint64)
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 {
+			continue
+		}
+		value, err := strconv.ParseUint(fields[1], 10, 64)
+		if err != nil {
+			continue
+		}
+		values[fields[0]] = value
+	}
+	return values, scanner.Err()
+}
+
+// readMemoryPressure returns the 10 seconds averages of the 'some' and 'full' lines, e.g.
+// 'some avg10=0.00 avg60=0.00 avg300=0.00 total=0'
+func readMemoryPressure() (float64, float64, error) {
+	content, err := os.ReadFile(memoryPressurePath)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	averages := make(map[string]float64, 2)
+	for _, line := range strings.Split(string(content), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		value, ok := strings.CutPrefix(fields[1], "avg10=")
+		if !ok {
+			continue
+		}
+		average, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return 0, 0, errors.Join(err, fmt.Errorf("failed parsing %s", memoryPressurePath))
+		}
+		averages[fields[0]] = average
+	}
+	return averages["some"], averages["full"], nil
+}
+
+// openKernelLog opens the kernel log positioned at its end, reading it may require root or CAP_SYSLOG
+func openKernelLog() (*kernelLog, error) {
+	fd, err := syscall.Open(kernelLogPath, syscall.O_RDONLY|syscall.O_NONBLOCK, 0)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := syscall.Seek(fd, 0, io.SeekEnd); err != nil {
+		syscall.Close(fd)
+		return nil, err
+	}
+	return &kernelLog{fd: fd}, nil
+}
+
+// oomVictims returns the processes killed by the OOM killer since the previous call, as '<name> (pid <pid>)'
+func (k *kernelLog) oomVictims() []string {
+	var victims []string
+	buffer := make([]byte, 8192)
+	for {
+		// Every read returns a single record
+		n, err := syscall.Read(k.fd, buffer)
+		if errors.Is(err, syscall.EPIPE) {
+			// Records were overwritten before they were read, continue with the next one
+			continue
+		}
+		if err != nil || n <= 0 {
+			return victims
+		}
+		if match := oomKilledPattern.FindSubmatch(buffer[:n]); match != nil {
+			victims = append(victims, fmt.Sprintf("%s (pid %s)", match[2], match[1]))
+		}
+	}
+}
+
+func (k *kernelLog) Close() error {
+	return syscall.Close(k.fd)
+}
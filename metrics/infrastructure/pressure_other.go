@@ -0,0 +1,23 @@
+//go:build !linux
+
+package infrastructure
+
+import "errors"
+
+type kernelLog struct{}
+
+func readPressure() (pressureSample, error) {
+	return pressureSample{}, errors.New("memory pressure is not supported on this platform")
+}
+
+func openKernelLog() (*kernelLog, error) {
+	return nil, errors.New("kernel log is not supported on this platform")
+}
+
+func (*kernelLog) oomVictims() []string {
+	return nil
+}
+
+func (*kernelLog) Close() error {
+	return nil
+}
@@ -0,0 +1,256 @@
+package infrastructure
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/format"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/logger"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/metric"
+	"golang.org/x/net/icmp"
+	"golang.org/x/net/ipv4"
+)
+
+const (
+	PacketLossMeasurement = "PacketLoss"
+	JitterMeasurement     = "JitterMs"
+	RTTMeasurement        = "RTTMs"
+
+	icmpProtocol = 1
+)
+
+var errTimeout = errors.New("probe timed out")
+
+type (
+	// ProbeMetric sends a probe to every host on each interval and measures packet loss and jitter over the run.
+	// Plain hosts (e.g. '1.1.1.1') are probed with ICMP echo requests over unprivileged ICMP sockets,
+	// 'host:port' addresses (e.g. a bootnode '203.0.113.5:30303') with TCP connects.
+	ProbeMetric struct {
+		metric.Base[float64]
+		hosts    []string
+		interval time.Duration
+		results  map[string]*probeResult
+		sequence int
+		mutex    sync.Mutex
+	}
+
+	probeResult struct {
+		sent, lost int
+		rtts       []time.Duration
+	}
+)
+
+func NewProbeMetric(name string, hosts []string, interval time.Duration, healthCondition []metric.HealthCondition[float64]) *ProbeMetric {
+	results := make(map[string]*probeResult, len(hosts))
+	for _, host := range hosts {
+		results[host] = &probeResult{}
+	}
+	return &ProbeMetric{
+		Base: metric.Base[float64]{
+			HealthConditions: healthCondition,
+			Name:             name,
+		},
+		hosts:    hosts,
+		interval: interval,
+		results:  results,
+	}
+}
+
+func (p *ProbeMetric) Measure(ctx context.Context) {
+	p.Runner(p.interval).Run(ctx, p.measure)
+}
+
+func (p *ProbeMetric) measure(ctx context.Context) {
+	p.sequence++
+	// A probe answered after the timeout is lost, a slow probe must not overlap the next one
+	timeout := p.Timeout(time.Duration(float64(p.interval) * 0.75))
+
+	var wg sync.WaitGroup
+	for _, host := range p.hosts {
+		wg.Add(1)
+		host := host
+		metric.Go(ctx, p.Name, func() {
+			defer wg.Done()
+
+			rtt, err := p.probe(host, p.sequence, timeout)
+			if err != nil && !isTimeout(err) {
+				// The probe could not be sent, which says nothing about the network
+				logger.WriteError(metric.InfrastructureGroup, p.Name, errors.Join(err, fmt.Errorf("failed probing host '%s'", host)))
+				return
+			}
+			p.record(host, rtt, err == nil)
+		})
+	}
+	wg.Wait()
+
+	p.writeMetric()
+}
+
+func (p *ProbeMetric) probe(host string, sequence int, timeout time.Duration) (time.Duration, error) {
+	if _, _, err := net.SplitHostPort(host); err == nil {
+		return p.probeTCP(host, timeout)
+	}
+	return p.probeICMP(host, sequence, timeout)
+}
+
+func (p *ProbeMetric) probeTCP(address string, timeout time.Duration) (time.Duration, error) {
+	start := time.Now()
+	conn, err := net.DialTimeout("tcp", address, timeout)
+	if err != nil {
+		var dnsErr *net.DNSError
+		if errors.As(err, &dnsErr) {
+			return 0, err
+		}
+		// Unreachable, refused and timed out connections are lost probes
+		return 0, errTimeout
+	}
+	defer conn.Close()
+
+	return time.Since(start), nil
+}
+
+func (p *ProbeMetric) probeICMP(host string, sequence int, timeout time.Duration) (time.Duration, error) {
+	addr, err := net.ResolveIPAddr("ip4", host)
+	if err != nil {
+		return 0, err
+	}
+
+	// Unprivileged ICMP sockets require the 'net.ipv4.ping_group_range' sysctl to include the group of the user
+	conn, err := icmp.ListenPacket("udp4", "0.0.0.0")
+	if err != nil {
+		return 0, errors.Join(err, errors.New("failed opening ICMP socket"))
+	}
+	defer conn.Close()
+
+	request := icmp.Message{
+		Type: ipv4.ICMPTypeEcho,
+		Body: &icmp.Echo{
+			ID:   sequence & 0xffff,
+			Seq:  sequence & 0xffff,
+			Data: []byte("solo-staking-benchmark"),
+		},
+	}
+	requestBytes, err := request.Marshal(nil)
+	if err != nil {
+		return 0, err
+	}
+
+	start := time.Now()
+	if _, err := conn.WriteTo(requestBytes, &net.UDPAddr{IP: addr.IP}); err != nil {
+		return 0, err
+	}
+	if err := conn.SetReadDeadline(start.Add(timeout)); err != nil {
+		return 0, err
+	}
+
+	reply := make([]byte, 1500)
+	for {
+		n, _, err := conn.ReadFrom(reply)
+		if err != nil {
+			var netErr net.Error
+			if errors.As(err, &netErr) && netErr.Timeout() {
+				return 0, errTimeout
+			}
+			return 0, err
+		}
+
+		message, err := icmp.ParseMessage(icmpProtocol, reply[:n])
+		if err != nil {
+			return 0, err
+		}
+		// The kernel rewrites the identifier of unprivileged echo requests, so replies are matched by sequence only
+		if echo, ok := message.Body.(*icmp.Echo); ok && message.Type == ipv4.ICMPTypeEchoReply && echo.Seq == sequence&0xffff {
+			return time.Since(start), nil
+		}
+	}
+}
+
+func isTimeout(err error) bool {
+	return errors.Is(err, errTimeout)
+}
+
+func (p *ProbeMetric) record(host string, rtt time.Duration, received bool) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	result := p.results[host]
+	result.sent++
+	if !received {
+		result.lost++
+		return
+	}
+	result.rtts = append(result.rtts, rtt)
+}
+
+func (p *ProbeMetric) writeMetric() {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	// The worst host defines the overall values, per host values are logged
+	values := map[string]float64{
+		PacketLossMeasurement: 0,
+		JitterMeasurement:     0,
+		RTTMeasurement:        0,
+	}
+	logged := make(map[string]any)
+	for host, result := range p.results {
+		loss, jitter, rtt := result.stats()
+		values[PacketLossMeasurement] = max(values[PacketLossMeasurement], loss)
+		values[JitterMeasurement] = max(values[JitterMeasurement], jitter)
+		values[RTTMeasurement] = max(values[RTTMeasurement], rtt)
+
+		logged[host] = map[string]float64{
+			PacketLossMeasurement: loss,
+			JitterMeasurement:     jitter,
+			RTTMeasurement:        rtt,
+		}
+	}
+
+	p.AddDataPoint(values)
+
+	logger.WriteMetric(metric.InfrastructureGroup, p.Name, logged)
+}
+
+// stats returns the packet loss in percent, the jitter as mean difference between consecutive round trips and the mean round trip, both in milliseconds
+func (r *probeResult) stats() (loss, jitter, rtt float64) {
+	if r.sent != 0 {
+		loss = float64(r.lost) / float64(r.sent) * 100
+	}
+	for i, value := range r.rtts {
+		rtt += float64(value) / float64(time.Millisecond)
+		if i > 0 {
+			diff := value - r.rtts[i-1]
+			if diff < 0 {
+				diff = -diff
+			}
+			jitter += float64(diff) / float64(time.Millisecond)
+		}
+	}
+	if len(r.rtts) > 0 {
+		rtt /= float64(len(r.rtts))
+	}
+	if len(r.rtts) > 1 {
+		jitter /= float64(len(r.rtts) - 1)
+	}
+	return loss, jitter, rtt
+}
+
+func (p *ProbeMetric) AggregateResults() string {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	var lines []string
+	for _, host := range p.hosts {
+		result := p.results[host]
+		loss, jitter, rtt := result.stats()
+		lines = append(lines, fmt.Sprintf("%s: loss=%s %%, jitter=%sms, rtt=%sms, probes=%d",
+			host, format.Number(loss, 2), format.Number(jitter, 2), format.Number(rtt, 2), result.sent))
+	}
+
+	return strings.Join(lines, " \n ")
+}
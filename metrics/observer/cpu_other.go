@@ -0,0 +1,12 @@
+//go:build !unix
+
+package observer
+
+import (
+	"errors"
+	"time"
+)
+
+func processCPUTime() (time.Duration, error) {
+	return 0, errors.New("process CPU time is not supported on this platform")
+}
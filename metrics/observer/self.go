@@ -0,0 +1,152 @@
+package observer
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/format"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/logger"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/metric"
+)
+
+const (
+	CPUPercentMeasurement  = "CPUPercent"
+	RSSMeasurement         = "RSS"
+	GoroutinesMeasurement  = "Goroutines"
+	GCPauseMaxMeasurement  = "GCPauseMaxMs"
+	GCPauseMeanMeasurement = "GCPauseMeanMs"
+)
+
+// SelfMetric tracks the resource usage of the benchmark process, so users can verify its overhead doesn't distort the infrastructure numbers
+type SelfMetric struct {
+	metric.Base[float64]
+	interval time.Duration
+
+	lastWallTime time.Time
+	lastCPU      time.Duration
+	lastNumGC    uint32
+	gcPauses     []time.Duration
+}
+
+func NewSelfMetric(name string, interval time.Duration, healthCondition []metric.HealthCondition[float64]) *SelfMetric {
+	s := &SelfMetric{
+		Base: metric.Base[float64]{
+			HealthConditions: healthCondition,
+			Name:             name,
+		},
+		interval: interval,
+	}
+	// The usage is calculated over an interval, so the first measurement waits for one
+	s.SetWarmUp(true)
+	return s
+}
+
+func (s *SelfMetric) Measure(ctx context.Context) {
+	// Establish the CPU time baseline, usage is calculated over the interval starting now
+	s.lastCPU, _ = processCPUTime()
+	s.lastWallTime = time.Now()
+
+	s.Runner(s.interval).Run(ctx, func(context.Context) { s.measure() })
+}
+
+func (s *SelfMetric) measure() {
+	var memStats runtime.MemStats
+	runtime.ReadMemStats(&memStats)
+
+	values := map[string]float64{
+		GoroutinesMeasurement: float64(runtime.NumGoroutine()),
+	}
+
+	cpuTime, err := processCPUTime()
+	if err != nil {
+		logger.WriteError(metric.ObserverGroup, s.Name, err)
+	} else {
+		now := time.Now()
+		if wall := now.Sub(s.lastWallTime); wall > 0 {
+			values[CPUPercentMeasurement] = float64(cpuTime-s.lastCPU) / float64(wall) * 100
+		}
+		s.lastCPU, s.lastWallTime = cpuTime, now
+	}
+
+	rss, err := residentSetSize()
+	if err != nil {
+		// Memory obtained from the OS by the Go runtime is the closest portable approximation
+		rss = memStats.Sys
+	}
+	values[RSSMeasurement] = float64(rss)
+
+	// PauseNs is a circular buffer of the most recent 256 GC pauses, older pauses are lost
+	first := s.lastNumGC + 1
+	if memStats.NumGC > 256 && first < memStats.NumGC-255 {
+		first = memStats.NumGC - 255
+	}
+	for gc := first; gc <= memStats.NumGC; gc++ {
+		s.gcPauses = append(s.gcPauses, time.Duration(memStats.PauseNs[(gc+255)%256]))
+	}
+	s.lastNumGC = memStats.NumGC
+	values[GCPauseMaxMeasurement], values[GCPauseMeanMeasurement] = pauseStats(s.gcPauses)
+
+	s.AddDataPoint(values)
+
+	logger.WriteMetric(metric.ObserverGroup, s.Name, map[string]any{
+		CPUPercentMeasurement: values[CPUPercentMeasurement],
+		RSSMeasurement:        rss,
+		GoroutinesMeasurement: values[GoroutinesMeasurement],
+		GCPauseMaxMeasurement: values[GCPauseMaxMeasurement],
+	})
+}
+
+// pauseStats returns the max and mean GC pause in milliseconds
+func pauseStats(pauses []time.Duration) (maxPause, meanPause float64) {
+	var total time.Duration
+	for _, pause := range pauses {
+		total += pause
+		maxPause = max(maxPause, float64(pause)/float64(time.Millisecond))
+	}
+	if len(pauses) > 0 {
+		meanPause = float64(total) / float64(len(pauses)) / float64(time.Millisecond)
+	}
+	return maxPause, meanPause
+}
+
+// residentSetSize reads the current RSS of the process, only available on Linux
+func residentSetSize() (uint64, error) {
+	statm, err := os.ReadFile("/proc/self/statm")
+	if err != nil {
+		return 0, err
+	}
+	fields := strings.Fields(string(statm))
+	if len(fields) < 2 {
+		return 0, fmt.Errorf("unexpected /proc/self/statm content '%s'", string(statm))
+	}
+	pages, err := strconv.ParseUint(fields[1], 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	return pages * uint64(os.Getpagesize()), nil
+}
+
+func (s *SelfMetric) AggregateResults() string {
+	var cpu, rss, goroutines []float64
+	for _, point := range s.Snapshot() {
+		cpu = append(cpu, point.Values[CPUPercentMeasurement])
+		rss = append(rss, point.Values[RSSMeasurement])
+		goroutines = append(goroutines, point.Values[GoroutinesMeasurement])
+	}
+
+	cpuPercentiles := metric.CalculatePercentiles(cpu, 50, 100)
+	rssPercentiles := metric.CalculatePercentiles(rss, 50, 100)
+	goroutinePercentiles := metric.CalculatePercentiles(goroutines, 100)
+	maxPause, meanPause := pauseStats(s.gcPauses)
+
+	return fmt.Sprintf("cpu_p50=%s %%, cpu_max=%s %%, rss_p50=%s, rss_max=%s \n goroutines_max=%s, gc_count=%d, gc_pause_mean=%sms, gc_pause_max=%sms",
+		format.Number(cpuPercentiles[50], 2), format.Number(cpuPercentiles[100], 2),
+		format.Bytes(rssPercentiles[50]), format.Bytes(rssPercentiles[100]),
+		format.Number(goroutinePercentiles[100], 0), len(s.gcPauses),
+		format.Number(meanPause, 3), format.Number(maxPause, 3))
+}
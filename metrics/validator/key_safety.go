@@ -0,0 +1,213 @@
+package validator
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/clock"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/format"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/httpclient"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/logger"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/metric"
+)
+
+const (
+	LoadedKeysMeasurement                = "LoadedKeys"
+	DuplicateKeysMeasurement             = "DuplicateKeys"
+	SlashingProtectionMissingMeasurement = "SlashingProtectionMissing"
+	SlashingProtectionAgeMeasurement     = "SlashingProtectionAgeSeconds"
+)
+
+type (
+	// KeySafetyMetric checks the validator client setup for misconfigurations which risk slashing: the keys loaded
+	// through its keymanager API, the presence and recency of its slashing protection database and keys which are
+	// also loaded by one of the other validator clients.
+	KeySafetyMetric struct {
+		metric.Base[float64]
+		address                string
+		otherAddresses         []string
+		slashingProtectionPath string
+		interval               time.Duration
+		last                   keySafetyResult
+		mutex                  sync.Mutex
+	}
+
+	keySafetyResult struct {
+		loaded             int
+		duplicates         []string
+		protectionModified time.Time
+		protectionErr      error
+	}
+
+	keystoresResponse struct {
+		Data []struct {
+			ValidatingPubkey string `json:"validating_pubkey"`
+		} `json:"data"`
+	}
+)
+
+func NewKeySafetyMetric(address string, otherAddresses []string, slashingProtectionPath, name string, interval time.Duration, healthCondition []metric.HealthCondition[float64]) *KeySafetyMetric {
+	return &KeySafetyMetric{
+		Base: metric.Base[float64]{
+			HealthConditions: healthCondition,
+			Name:             name,
+		},
+		address:                address,
+		otherAddresses:         otherAddresses,
+		slashingProtectionPath: slashingProtectionPath,
+		interval:               interval,
+	}
+}
+
+func (k *KeySafetyMetric) Measure(ctx context.Context) {
+	k.Runner(k.interval).Run(ctx, k.measure)
+}
+
+func (k *KeySafetyMetric) measure(ctx context.Context) {
+	ctx, cancel := k.MeasurementContext(ctx, 5*time.Second)
+	defer cancel()
+
+	keys, err := fetchKeys(ctx, k.address)
+	if err != nil {
+		logger.WriteError(metric.ValidatorGroup, k.Name, errors.Join(err, errors.New("failed listing the keys of the validator client")))
+		return
+	}
+
+	var result keySafetyResult
+	result.loaded = len(keys)
+
+	for _, address := range k.otherAddresses {
+		otherKeys, err := fetchKeys(ctx, address)
+		if err != nil {
+			logger.WriteError(metric.ValidatorGroup, k.Name, errors.Join(err, fmt.Errorf("failed listing the keys of validator client '%s'", address)))
+			continue
+		}
+		for key := range otherKeys {
+			if _, ok := keys[key]; ok {
+				result.duplicates = append(result.duplicates, key)
+			}
+		}
+	}
+	sort.Strings(result.duplicates)
+
+	values := map[string]float64{
+		LoadedKeysMeasurement:    float64(result.loaded),
+		DuplicateKeysMeasurement: float64(len(result.duplicates)),
+	}
+	if k.slashingProtectionPath != "" {
+		result.protectionModified, result.protectionErr = lastModified(k.slashingProtectionPath)
+		if result.protectionErr != nil {
+			values[SlashingProtectionMissingMeasurement] = 1
+		} else {
+			values[SlashingProtectionMissingMeasurement] = 0
+			values[SlashingProtectionAgeMeasurement] = clock.Since(result.protectionModified).Seconds()
+		}
+	}
+
+	k.mutex.Lock()
+	k.last = result
+	k.mutex.Unlock()
+
+	k.AddDataPoint(values)
+
+	logged := make(map[string]any, len(values))
+	for name, value := range values {
+		logged[name] = value
+	}
+	if len(result.duplicates) != 0 {
+		logged["DuplicatePubkeys"] = result.duplicates
+	}
+	logger.WriteMetric(metric.ValidatorGroup, k.Name, logged)
+}
+
+// fetchKeys lists the public keys of the keystores loaded by the validator client
+func fetchKeys(ctx context.Context, address string) (map[string]struct{}, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/eth/v1/keystores", address), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := httpclient.Default.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(res.Body, 1024))
+		return nil, fmt.Errorf("received unsuccessful status code. Code: '%s'. Response: '%s'", res.Status, body)
+	}
+
+	var resp keystoresResponse
+	if err := json.NewDecoder(res.Body).Decode(&resp); err != nil {
+		return nil, err
+	}
+
+	keys := make(map[string]struct{}, len(resp.Data))
+	for _, keystore := range resp.Data {
+		keys[strings.ToLower(keystore.ValidatingPubkey)] = struct{}{}
+	}
+	return keys, nil
+}
+
+// lastModified returns the modification time of the slashing protection database. Clients keeping it in a
+// directory (e.g. Teku) update one file per validator, so the most recently modified file counts.
+func lastModified(path string) (time.Time, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if !info.IsDir() {
+		return info.ModTime(), nil
+	}
+
+	latest := time.Time{}
+	err = filepath.WalkDir(path, func(_ string, entry os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		entryInfo, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		if !entry.IsDir() && entryInfo.ModTime().After(latest) {
+			latest = entryInfo.ModTime()
+		}
+		return nil
+	})
+	if err != nil {
+		return time.Time{}, err
+	}
+	if latest.IsZero() {
+		return time.Time{}, fmt.Errorf("slashing protection directory '%s' is empty", path)
+	}
+	return latest, nil
+}
+
+func (k *KeySafetyMetric) AggregateResults() string {
+	k.mutex.Lock()
+	defer k.mutex.Unlock()
+
+	lines := []string{fmt.Sprintf("Loaded Keys: %d, Duplicate Keys: %d", k.last.loaded, len(k.last.duplicates))}
+	for _, key := range k.last.duplicates {
+		lines = append(lines, fmt.Sprintf("Duplicate: %s", key))
+	}
+	switch {
+	case k.slashingProtectionPath == "":
+	case k.last.protectionErr != nil:
+		lines = append(lines, fmt.Sprintf("Slashing Protection: missing (%s)", k.last.protectionErr.Error()))
+	case !k.last.protectionModified.IsZero():
+		lines = append(lines, fmt.Sprintf("Slashing Protection: updated %s ago", format.Duration(clock.Since(k.last.protectionModified))))
+	}
+	return strings.Join(lines, " \n ")
+}
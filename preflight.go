@@ -0,0 +1,164 @@
+package benchmark
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/Harikakasimahanthi/benchmark-test/configs"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/clientinfo"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/conformance"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/httpclient"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/metric"
+	"github.com/Harikakasimahanthi/benchmark-test/metrics/execution"
+	"github.com/Harikakasimahanthi/benchmark-test/metrics/goruntime"
+	"github.com/Harikakasimahanthi/benchmark-test/metrics/validator"
+	"github.com/Harikakasimahanthi/benchmark-test/report"
+)
+
+// preflightTimeout bounds the probes of a session, the event stream of the beacon API alone may take two slots
+const preflightTimeout = time.Second * 40
+
+// prober runs a single measurement of the metric without recording it, it fails when the metric couldn't measure
+type prober interface {
+	Probe(ctx context.Context) error
+}
+
+// preflight probes what every enabled metric of the session depends on once, so metrics which can't measure are found
+// before the run rather than reported with empty aggregates. The metrics of the beacon node are matched with the beacon
+// API endpoints they use, those of the execution node run a measurement and the key safety metric needs the keymanager
+// API. The runtime, infrastructure and observer metrics read local or optional sources and aren't probed.
+func preflight(session configs.Benchmark, clients clientinfo.Detection, metrics map[metric.Group][]metricService) []report.PreflightResult {
+	ctx, cancel := context.WithTimeout(context.Background(), preflightTimeout)
+	defer cancel()
+
+	var (
+		results   []report.PreflightResult
+		endpoints []report.ConformanceResult
+		checked   bool
+	)
+	groups := make([]metric.Group, 0, len(metrics))
+	for group := range metrics {
+		groups = append(groups, group)
+	}
+	sort.Slice(groups, func(i, j int) bool { return groups[i] < groups[j] })
+
+	for _, group := range groups {
+		for _, m := range metrics[group] {
+			result := report.PreflightResult{Session: session.Name, Group: string(group), Metric: m.GetName(), Status: report.PreflightPassed}
+			// The runtime metrics are collected by every group, the key safety metric uses the keymanager API
+			switch m.(type) {
+			case *goruntime.RuntimeMetric:
+				continue
+			case *validator.KeySafetyMetric:
+				result.Status, result.Detail = probeReachable(ctx, session.ValidatorClient.Address)
+				results = append(results, result)
+				continue
+			}
+
+			switch group {
+			case metric.ConsensusGroup, metric.ValidatorGroup:
+				if clients.ConsensusVersion == "" {
+					result.Status, result.Detail = report.PreflightUnreachable, "beacon node didn't answer the client detection"
+					break
+				}
+				// The gateway of a gRPC client deviates from the beacon API where the metrics know how to handle it
+				if clients.Consensus.GatewayAPI {
+					break
+				}
+				if !checked {
+					endpoints, checked = conformance.Check(ctx, httpclient.Default, session.BeaconNode.Address), true
+				}
+				result.Status, result.Detail = endpointStatus(endpoints, m.GetName())
+			case metric.ExecutionGroup:
+				if clients.ExecutionVersion == "" {
+					result.Status, result.Detail = report.PreflightUnreachable, "execution node didn't answer the client detection"
+					break
+				}
+				if probed, ok := m.(prober); ok {
+					result.Status, result.Detail = probeStatus(ctx, probed)
+				}
+			default:
+				continue
+			}
+			results = append(results, result)
+		}
+	}
+
+	slog.With("session", session.Name).With("probed", len(results)).With("failed", len(failedPreflight(results))).Info("preflight of the enabled metrics finished")
+	return results
+}
+
+// endpointStatus fails the metric on the first beacon API endpoint it uses which doesn't conform
+func endpointStatus(results []report.ConformanceResult, name string) (string, string) {
+	for _, result := range results {
+		if result.Status == report.ConformanceSupported || !slices.Contains(result.UsedBy, name) {
+			continue
+		}
+		detail := fmt.Sprintf("%s: %s", result.Endpoint, result.Detail)
+		if result.Status == report.ConformanceUnreachable {
+			return report.PreflightUnreachable, detail
+		}
+		return report.PreflightUnsupported, detail
+	}
+	return report.PreflightPassed, ""
+}
+
+// probeStatus runs a measurement of the execution metric, the client answering with an error doesn't support it
+func probeStatus(ctx context.Context, probed prober) (string, string) {
+	err := probed.Probe(ctx)
+	switch {
+	case err == nil:
+		return report.PreflightPassed, ""
+	case execution.Unsupported(err):
+		return report.PreflightUnsupported, err.Error()
+	default:
+		return report.PreflightUnreachable, err.Error()
+	}
+}
+
+// probeReachable tells whether the API answers at all, its status codes depend on the authorization
+func probeReachable(ctx context.Context, address string) (string, string) {
+	if address == "" {
+		return report.PreflightPassed, ""
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, address, nil)
+	if err != nil {
+		return report.PreflightUnreachable, err.Error()
+	}
+	res, err := httpclient.Default.Do(req)
+	if err != nil {
+		return report.PreflightUnreachable, err.Error()
+	}
+	_ = res.Body.Close()
+	return report.PreflightPassed, ""
+}
+
+// failedPreflight returns the results of the metrics which failed the preflight
+func failedPreflight(results []report.PreflightResult) []report.PreflightResult {
+	var failed []report.PreflightResult
+	for _, result := range results {
+		if result.Status != report.PreflightPassed {
+			failed = append(failed, result)
+		}
+	}
+	return failed
+}
+
+// withoutFailed removes the metrics which failed the preflight of the session
+func withoutFailed(metrics map[metric.Group][]metricService, failed []report.PreflightResult) {
+	for _, result := range failed {
+		group := metric.Group(result.Group)
+		metrics[group] = slices.DeleteFunc(metrics[group], func(m metricService) bool {
+			return m.GetName() == result.Metric
+		})
+		if len(metrics[group]) == 0 {
+			delete(metrics, group)
+		}
+		slog.With("session", result.Session).With("group", result.Group).With("metric", result.Metric).Warn("metric disabled, it failed the preflight")
+	}
+}
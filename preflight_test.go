@@ -0,0 +1,57 @@
+package benchmark
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Harikakasimahanthi/benchmark-test/configs"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/clientinfo"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/metric"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/network"
+	"github.com/Harikakasimahanthi/benchmark-test/metrics/execution"
+	"github.com/Harikakasimahanthi/benchmark-test/report"
+	"github.com/Harikakasimahanthi/benchmark-test/testutil/mocknode"
+)
+
+func TestGivenDetectedExecutionNodeWhenPreflightThenEveryMetricMeasuresOnce(t *testing.T) {
+	node := mocknode.New(network.Mainnet).Start()
+	defer node.Close()
+	// The node answers the detection but no other method
+	withoutMethods := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"jsonrpc":"2.0","id":1,"error":{"code":-32601,"message":"the method does not exist/is not available"}}`))
+	}))
+	defer withoutMethods.Close()
+	stopped := httptest.NewServer(http.NotFoundHandler())
+	stopped.Close()
+
+	tests := []struct {
+		name string
+		url  string
+		want string
+	}{
+		{name: "answering node", url: node.ExecutionURL(), want: report.PreflightPassed},
+		{name: "node without the methods", url: withoutMethods.URL, want: report.PreflightUnsupported},
+		{name: "node stopped after the detection", url: stopped.URL, want: report.PreflightUnreachable},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			metrics := map[metric.Group][]metricService{metric.ExecutionGroup: {
+				execution.NewPeerMetric(test.url, "Peers", time.Minute, nil),
+				execution.NewInboundMetric(test.url, "", "Inbound", time.Minute, nil),
+			}}
+
+			results := preflight(configs.Benchmark{Name: "default"}, clientinfo.Detection{ExecutionVersion: mocknode.ExecutionVersion}, metrics)
+
+			require.Len(t, results, 2)
+			for _, result := range results {
+				assert.Equal(t, test.want, result.Status, result.Metric)
+			}
+		})
+	}
+}
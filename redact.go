@@ -0,0 +1,89 @@
+package benchmark
+
+import (
+	"net"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/Harikakasimahanthi/benchmark-test/configs"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/ipc"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/redact"
+	"github.com/Harikakasimahanthi/benchmark-test/report"
+)
+
+// redactor obscures the addresses and identifiers in the reports, artifacts and exported metrics of the run, it is nil
+// unless the run is redacted
+var redactor *redact.Redactor
+
+// newRedactor registers the hosts of the configured nodes of every session, the validator indices and the hostname of
+// the machine. URLs and IPv4 addresses are redacted wherever they appear, the hosts are registered for the places
+// naming them without scheme, e.g. errors dialing 'host:port'.
+func newRedactor(config configs.Benchmark) *redact.Redactor {
+	r := redact.New()
+	if hostname, err := os.Hostname(); err == nil {
+		r.Add(redact.KindHost, hostname)
+	}
+	for _, session := range config.SessionConfigs() {
+		addresses := []string{
+			session.BeaconNode.Address,
+			session.BeaconNode.GRPC.Address,
+			session.BeaconNode.Metrics.Runtime.Address,
+			session.BeaconNode.Metrics.Failover.Fallback,
+			session.ExecutionNode.Address,
+			session.ExecutionNode.EngineAddress,
+			session.ExecutionNode.Metrics.Runtime.Address,
+			session.ValidatorClient.Address,
+		}
+		addresses = append(addresses, session.BeaconNode.OtherAddresses...)
+		addresses = append(addresses, session.BeaconNode.CheckpointProviders...)
+		addresses = append(addresses, session.ValidatorClient.OtherAddresses...)
+		addresses = append(addresses, session.Infrastructure.Metrics.Probe.Hosts...)
+		for _, address := range addresses {
+			addAddress(r, address)
+		}
+
+		for _, index := range session.ValidatorClient.Indices {
+			r.Add(redact.KindValidator, strconv.FormatUint(index, 10))
+		}
+	}
+	return r
+}
+
+// addAddress registers the path of IPC sockets and the host of the other addresses, given as URL or 'host:port'. Hosts
+// of a single label, e.g. the 'geth' of a container network, are left, they rather name the client than the
+// infrastructure.
+func addAddress(r *redact.Redactor, address string) {
+	if address == "" {
+		return
+	}
+	if ipc.IsSocket(address) {
+		r.Add(redact.KindPath, address)
+		return
+	}
+
+	host := address
+	if parsed, err := url.Parse(address); err == nil && parsed.Host != "" {
+		host = parsed.Hostname()
+	} else if h, _, err := net.SplitHostPort(address); err == nil {
+		host = h
+	}
+	if ip := net.ParseIP(host); ip != nil {
+		if !ip.IsLoopback() {
+			r.Add(redact.KindIP, host)
+		}
+		return
+	}
+	if strings.Contains(host, ".") {
+		r.Add(redact.KindHost, host)
+	}
+}
+
+// redacted obscures the records of the sink when the run is redacted
+func redacted(sink report.Sink) report.Sink {
+	if redactor == nil {
+		return sink
+	}
+	return report.NewRedacted(sink, redactor.String)
+}
@@ -0,0 +1,91 @@
+package benchmark
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/spf13/cobra"
+
+	"github.com/Harikakasimahanthi/benchmark-test/report"
+)
+
+var ReportCMD = &cobra.Command{
+	Use:   "report",
+	Short: "Inspect the reports of saved runs",
+	// Saved runs are inspected without the configuration of a new run
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		return nil
+	},
+}
+
+var ReportDiffCMD = &cobra.Command{
+	Use:   "diff <run-A> <run-B>",
+	Short: "Compare the measurements of two saved runs, by run directory or run ID",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cobraCMD *cobra.Command, args []string) error {
+		artifactsDir, err := cobraCMD.Flags().GetString(artifactsDirFlag)
+		if err != nil {
+			return err
+		}
+
+		var runs [2]map[string]report.DataPoints
+		for i, arg := range args {
+			runs[i], err = report.LoadDataPoints(runDir(artifactsDir, arg))
+			if err != nil {
+				return errors.Join(err, fmt.Errorf("failed loading run '%s'", arg))
+			}
+		}
+
+		deltas, err := diffRuns(runs[0], runs[1])
+		if err != nil {
+			return err
+		}
+		report.RenderDiff(cobraCMD.OutOrStdout(), deltas)
+		return nil
+	},
+}
+
+func init() {
+	ReportDiffCMD.Flags().String(artifactsDirFlag, defaultArtifactsDir, "Directory holding the runs referenced by their run ID")
+	ReportCMD.AddCommand(ReportDiffCMD)
+	CMD.AddCommand(ReportCMD)
+}
+
+// runDir resolves a run passed either as directory or as run ID inside the artifacts directory
+func runDir(artifactsDir, run string) string {
+	if info, err := os.Stat(run); err == nil && info.IsDir() {
+		return run
+	}
+	return filepath.Join(artifactsDir, run)
+}
+
+// diffRuns compares the sessions of the same name, runs of a single session are compared whatever their names
+func diffRuns(before, after map[string]report.DataPoints) ([]report.Delta, error) {
+	if len(before) == 1 && len(after) == 1 {
+		for _, b := range before {
+			for _, a := range after {
+				return report.Diff("", b, a), nil
+			}
+		}
+	}
+
+	sessions := make([]string, 0, len(before))
+	for session := range before {
+		if _, ok := after[session]; ok {
+			sessions = append(sessions, session)
+		}
+	}
+	if len(sessions) == 0 {
+		return nil, errors.New("the runs have no session in common")
+	}
+	sort.Strings(sessions)
+
+	var deltas []report.Delta
+	for _, session := range sessions {
+		deltas = append(deltas, report.Diff(session, before[session], after[session])...)
+	}
+	return deltas, nil
+}
@@ -0,0 +1,49 @@
+package report
+
+import (
+	"slices"
+	"sync"
+
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/metric"
+)
+
+// collector keeps the records of the sinks which render all of them at once
+type collector struct {
+	records []Record
+	mutex   sync.Mutex
+}
+
+func (c *collector) AddRecord(record Record) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.records = append(c.records, record)
+}
+
+// split returns the records of the main table and the ones of every section, in the order of the sections
+func (c *collector) split() ([]Record, []section) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	var main []Record
+	sections := make([]section, 0, len(sectionHeaders))
+	for _, s := range sectionHeaders {
+		sections = append(sections, section{Group: s.group, Headers: s.headers})
+	}
+	for _, record := range c.records {
+		i := slices.IndexFunc(sections, func(s section) bool { return s.Group == record.GroupName })
+		if i == -1 {
+			main = append(main, record)
+			continue
+		}
+		sections[i].Records = append(sections[i].Records, record)
+	}
+	return main, slices.DeleteFunc(sections, func(s section) bool { return len(s.Records) == 0 })
+}
+
+// section is a group rendered below the main table
+type section struct {
+	Group   metric.Group
+	Headers []string
+	Records []Record
+}
@@ -0,0 +1,37 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+const (
+	ConformanceSupported   = "supported"
+	ConformanceUnsupported = "unsupported"
+	ConformanceViolation   = "violation"
+	ConformanceUnreachable = "unreachable"
+)
+
+// ConformanceResult is the outcome of checking one endpoint of the beacon API, the detail tells what was wrong
+type ConformanceResult struct {
+	Category string   `json:"category"`
+	Endpoint string   `json:"endpoint"`
+	Status   string   `json:"status"`
+	Detail   string   `json:"detail,omitempty"`
+	UsedBy   []string `json:"used_by,omitempty"`
+}
+
+// RenderConformance renders the results as table followed by the number of supported endpoints
+func RenderConformance(out io.Writer, results []ConformanceResult) {
+	t := newTable(out, []string{"Category", "Endpoint", "Status", "Detail", "Used By"})
+	supported := 0
+	for _, result := range results {
+		if result.Status == ConformanceSupported {
+			supported++
+		}
+		t.AddRow(result.Category, result.Endpoint, result.Status, result.Detail, strings.Join(result.UsedBy, " \n "))
+	}
+	t.Render()
+	fmt.Fprintf(out, "\n%d/%d endpoints supported\n", supported, len(results))
+}
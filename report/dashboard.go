@@ -0,0 +1,79 @@
+package report
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/format"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/metric"
+)
+
+var dashboardTemplate = template.Must(template.Must(htmlTemplate.Clone()).New("dashboard").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<meta http-equiv="refresh" content="60">
+<title>Fleet dashboard</title>
+<style>
+body { font-family: sans-serif; margin: 2em; }
+table { border-collapse: collapse; margin-bottom: 2em; }
+th, td { border: 1px solid #ccc; padding: 0.4em 0.8em; text-align: left; vertical-align: top; }
+th { background: #f0f0f0; }
+tr.unhealthy td { background: #fdecea; }
+</style>
+</head>
+<body>
+<h1>Fleet dashboard</h1>
+<p>Generated at {{.Generated}}</p>
+{{if .Hosts.Rows}}<h2>Hosts</h2>
+{{template "table" .Hosts}}<h2>Metrics by host</h2>
+{{template "table" .Metrics}}{{else}}<p>No agent pushed results yet.</p>
+{{end}}</body>
+</html>
+`))
+
+// RenderFleetDashboard renders the latest run of every host as HTML page: the verdict, score and findings of the
+// hosts, and the health of every metric side by side
+func RenderFleetDashboard(out io.Writer, hosts []FleetHost) error {
+	page := struct {
+		Generated string
+		Hosts     htmlTable
+		Metrics   htmlTable
+	}{
+		Generated: format.Timestamp(time.Now()),
+		Hosts:     htmlTable{Headers: []string{"Host", "Verdict", "Healthy Metrics", "Score", "Last Run", "Findings"}},
+	}
+	page.Hosts.Columns = len(page.Hosts.Headers)
+
+	for _, host := range hosts {
+		s := host.Summarize()
+		row := htmlRow{Unhealthy: host.Err != "" || s.Verdict != verdictHealthy}
+		if host.Err != "" {
+			row.Cells = []string{host.Host, s.Verdict, "-", "-", format.Timestamp(host.At), "-"}
+		} else {
+			row.Cells = []string{host.Host, s.Verdict, fmt.Sprintf("%d/%d", s.Healthy, s.Total), fmt.Sprint(s.Score),
+				format.Timestamp(host.At), strings.Join(s.Findings, " \n ")}
+		}
+		page.Hosts.Rows = append(page.Hosts.Rows, row)
+	}
+
+	page.Metrics.Headers = []string{"Group Name", "Metric Name"}
+	for _, host := range hosts {
+		page.Metrics.Headers = append(page.Metrics.Headers, host.Host)
+	}
+	page.Metrics.Columns = len(page.Metrics.Headers)
+	for _, key := range fleetMetrics(hosts) {
+		row := htmlRow{Cells: []string{string(key.group), key.name}}
+		for _, host := range hosts {
+			cell := fleetCell(host.Records, key.group, key.name)
+			row.Unhealthy = row.Unhealthy || strings.Contains(cell, string(metric.Unhealthy))
+			row.Cells = append(row.Cells, cell)
+		}
+		page.Metrics.Rows = append(page.Metrics.Rows, row)
+	}
+
+	return dashboardTemplate.Execute(out, page)
+}
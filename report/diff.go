@@ -0,0 +1,251 @@
+package report
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/format"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/metric"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/run"
+)
+
+// dataPointsPattern matches the data points of the sessions, either files, compressed or not, or directories of chunks
+const dataPointsPattern = "datapoints*"
+
+var diffHeaders = []string{"Group Name", "Metric Name", "Measurement", "Run A", "Run B", "Change", "Significance"}
+
+type (
+	// DataPoints are the raw data points of a session as exported to the run artifacts, by group and metric
+	DataPoints map[metric.Group]map[string][]metric.ExportedDataPoint
+
+	// Delta compares the samples of a measurement between two runs
+	Delta struct {
+		Session     string
+		GroupName   metric.Group
+		MetricName  string
+		Measurement string
+		Before      metric.Summary
+		After       metric.Summary
+		Significant bool
+	}
+)
+
+// LoadDataPoints reads the data points of every session of a run directory, keyed by the session name. The data
+// points of a run without sessions are keyed by an empty name. Compressed data points and the chunks of archives are
+// read as well.
+func LoadDataPoints(dir string) (map[string]DataPoints, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, dataPointsPattern))
+	if err != nil {
+		return nil, err
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("run directory '%s' has no data points", dir)
+	}
+
+	sessions := make(map[string]DataPoints, len(paths))
+	for _, path := range paths {
+		files := []string{path}
+		if info, err := os.Stat(path); err == nil && info.IsDir() {
+			if files, err = filepath.Glob(filepath.Join(path, "*.json*")); err != nil {
+				return nil, err
+			}
+		}
+
+		session := strings.TrimSuffix(run.TrimCompressionExtension(filepath.Base(path)), ".json")
+		session = strings.TrimPrefix(strings.TrimPrefix(session, "datapoints"), "-")
+		dataPoints := sessions[session]
+		if dataPoints == nil {
+			dataPoints = make(DataPoints)
+			sessions[session] = dataPoints
+		}
+		for _, file := range files {
+			if err := readDataPoints(file, dataPoints); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	// Chunks are read in the order of their names, which are their start
+	for _, dataPoints := range sessions {
+		for _, metrics := range dataPoints {
+			for _, points := range metrics {
+				sort.SliceStable(points, func(i, j int) bool { return points[i].Timestamp.Before(points[j].Timestamp) })
+			}
+		}
+	}
+	return sessions, nil
+}
+
+// readDataPoints adds the data points of the file to the ones of its session
+func readDataPoints(file string, dataPoints DataPoints) error {
+	reader, err := run.Open(file)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	var decoded DataPoints
+	if err := json.NewDecoder(reader).Decode(&decoded); err != nil {
+		return errors.Join(err, fmt.Errorf("failed decoding data points '%s'", file))
+	}
+	for group, metrics := range decoded {
+		if dataPoints[group] == nil {
+			dataPoints[group] = make(map[string][]metric.ExportedDataPoint)
+		}
+		for name, points := range metrics {
+			dataPoints[group][name] = append(dataPoints[group][name], points...)
+		}
+	}
+	return nil
+}
+
+// Diff compares every numeric measurement recorded by both runs. Measurements recorded by a single run are
+// reported with an empty summary on the other side.
+func Diff(session string, before, after DataPoints) []Delta {
+	samplesBefore, samplesAfter := samples(before), samples(after)
+
+	keys := make(map[[3]string]struct{}, len(samplesBefore))
+	for key := range samplesBefore {
+		keys[key] = struct{}{}
+	}
+	for key := range samplesAfter {
+		keys[key] = struct{}{}
+	}
+
+	deltas := make([]Delta, 0, len(keys))
+	for key := range keys {
+		delta := Delta{
+			Session:     session,
+			GroupName:   metric.Group(key[0]),
+			MetricName:  key[1],
+			Measurement: key[2],
+			Before:      metric.Summarize(samplesBefore[key]),
+			After:       metric.Summarize(samplesAfter[key]),
+		}
+		delta.Significant = metric.SignificantDifference(delta.Before, delta.After)
+		deltas = append(deltas, delta)
+	}
+
+	sort.Slice(deltas, func(i, j int) bool {
+		a, b := deltas[i], deltas[j]
+		if a.GroupName != b.GroupName {
+			return a.GroupName < b.GroupName
+		}
+		if a.MetricName != b.MetricName {
+			return a.MetricName < b.MetricName
+		}
+		return a.Measurement < b.Measurement
+	})
+	return deltas
+}
+
+// samples collects the numeric values of every measurement, keyed by group, metric and measurement
+func samples(dataPoints DataPoints) map[[3]string][]float64 {
+	result := make(map[[3]string][]float64)
+	for group, metrics := range dataPoints {
+		for name, points := range metrics {
+			for _, point := range points {
+				for measurement, value := range point.Values {
+					var number float64
+					switch v := value.(type) {
+					case float64:
+						number = v
+					case bool:
+						if v {
+							number = 1
+						}
+					default:
+						continue
+					}
+					key := [3]string{string(group), name, measurement}
+					result[key] = append(result[key], number)
+				}
+			}
+		}
+	}
+	return result
+}
+
+// RenderDiff writes the deltas as a table, significant changes are marked so they stand out from noise
+func RenderDiff(out io.Writer, deltas []Delta) {
+	withSession := false
+	for _, delta := range deltas {
+		if delta.Session != "" {
+			withSession = true
+			break
+		}
+	}
+
+	headers := diffHeaders
+	if withSession {
+		headers = append([]string{sessionHeader}, headers...)
+	}
+	t := newTable(out, headers)
+
+	for _, delta := range deltas {
+		row := []string{
+			string(delta.GroupName),
+			delta.MetricName,
+			delta.Measurement,
+			formatSummary(delta.Before),
+			formatSummary(delta.After),
+			formatChange(delta),
+			formatSignificance(delta),
+		}
+		if withSession {
+			row = append([]string{delta.Session}, row...)
+		}
+		t.AddRow(row...)
+	}
+	t.Render()
+}
+
+func formatSummary(summary metric.Summary) string {
+	if summary.Count == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%s ± %s \n n=%d", format.Number(summary.Mean, 2), format.Number(summary.StdDev, 2), summary.Count)
+}
+
+// formatChange shows the direction of the change of the mean along with its relative size
+func formatChange(delta Delta) string {
+	switch {
+	case delta.Before.Count == 0:
+		return "new"
+	case delta.After.Count == 0:
+		return "gone"
+	case delta.After.Mean == delta.Before.Mean:
+		return "="
+	}
+
+	arrow := "↑"
+	if delta.After.Mean < delta.Before.Mean {
+		arrow = "↓"
+	}
+	if delta.Before.Mean == 0 {
+		return fmt.Sprintf("%s %s", arrow, format.Number(delta.After.Mean-delta.Before.Mean, 2))
+	}
+	change := (delta.After.Mean - delta.Before.Mean) / math.Abs(delta.Before.Mean) * 100
+	sign := ""
+	if change > 0 {
+		sign = "+"
+	}
+	return fmt.Sprintf("%s %s%s %%", arrow, sign, format.Number(change, 2))
+}
+
+func formatSignificance(delta Delta) string {
+	if delta.Significant {
+		return "*** significant"
+	}
+	if delta.Before.Count < 2 || delta.After.Count < 2 {
+		return "too few samples"
+	}
+	return "noise"
+}
@@ -0,0 +1,178 @@
+package report
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	discordAPI = "https://discord.com/api/v10"
+	// discordTopIssues limits the findings listed in the message, Discord limits the size of embeds
+	discordTopIssues = 5
+	// discordFieldLimit is the maximum length of the value of an embed field
+	discordFieldLimit = 1024
+
+	discordColorHealthy   = 0x2e7d32
+	discordColorAttention = 0xf9a825
+	discordColorUnhealthy = 0xc62828
+)
+
+type (
+	// Discord posts the summary of the run to a Discord channel once it ends: the verdict, the score, the top issues
+	// along with their hints and where the artifacts are. It posts either through a channel webhook or as a bot.
+	Discord struct {
+		collector
+		webhookURL  string
+		token       string
+		channel     string
+		artifacts   string
+		withSession bool
+		client      *http.Client
+	}
+
+	discordMessage struct {
+		Embeds []discordEmbed `json:"embeds"`
+	}
+
+	discordEmbed struct {
+		Title     string         `json:"title"`
+		URL       string         `json:"url,omitempty"`
+		Color     int            `json:"color"`
+		Fields    []discordField `json:"fields,omitempty"`
+		Timestamp string         `json:"timestamp"`
+	}
+
+	discordField struct {
+		Name   string `json:"name"`
+		Value  string `json:"value"`
+		Inline bool   `json:"inline,omitempty"`
+	}
+)
+
+// NewDiscordWebhook posts the summary through the webhook URL of a channel
+func NewDiscordWebhook(webhookURL, artifacts string, withSession bool) *Discord {
+	return &Discord{
+		webhookURL:  webhookURL,
+		artifacts:   artifacts,
+		withSession: withSession,
+		client:      &http.Client{Timeout: webhookTimeout},
+	}
+}
+
+// NewDiscordBot posts the summary to the channel as the bot of the token, the bot needs to be allowed to send
+// messages in the channel
+func NewDiscordBot(token, channel, artifacts string, withSession bool) *Discord {
+	return &Discord{
+		token:       token,
+		channel:     channel,
+		artifacts:   artifacts,
+		withSession: withSession,
+		client:      &http.Client{Timeout: webhookTimeout},
+	}
+}
+
+func (d *Discord) Render() {
+	main, _ := d.split()
+	body, err := json.Marshal(discordMessage{Embeds: []discordEmbed{d.embed(summarize(main, d.withSession))}})
+	if err != nil {
+		slog.With("err", err.Error()).Error("failed encoding Discord message")
+		return
+	}
+
+	if err := d.post(body); err != nil {
+		slog.With("err", err.Error()).Error("failed posting run summary to Discord")
+		return
+	}
+	slog.Info("run summary posted to Discord")
+}
+
+func (d *Discord) embed(s summary) discordEmbed {
+	embed := discordEmbed{
+		Title:     "Benchmark run summary",
+		Color:     discordColorHealthy,
+		Timestamp: time.Now().UTC().Format(time.RFC3339),
+		Fields: []discordField{
+			{Name: "Verdict", Value: s.Verdict, Inline: true},
+			{Name: "Healthy metrics", Value: fmt.Sprintf("%d/%d", s.Healthy, s.Total), Inline: true},
+			{Name: "Score", Value: fmt.Sprintf("%d (lower is better)", s.Score), Inline: true},
+		},
+	}
+	switch s.Verdict {
+	case verdictAttention:
+		embed.Color = discordColorAttention
+	case verdictUnhealthy:
+		embed.Color = discordColorUnhealthy
+	}
+
+	if len(s.Findings) != 0 {
+		var issues []string
+		for i, finding := range s.Findings {
+			if i == discordTopIssues {
+				issues = append(issues, fmt.Sprintf("… and %d more", len(s.Findings)-discordTopIssues))
+				break
+			}
+			name := fmt.Sprintf("%s/%s", finding.GroupName, finding.MetricName)
+			if d.withSession && finding.Session != "" {
+				name = finding.Session + ": " + name
+			}
+			issue := fmt.Sprintf("**%s**: %s", name, formatSeverityMap(finding.Severity))
+			if len(finding.Hints) != 0 {
+				issue += "\n↳ " + finding.Hints[0]
+			}
+			issues = append(issues, issue)
+		}
+		embed.Fields = append(embed.Fields, discordField{Name: "Top issues", Value: truncate(strings.Join(issues, "\n"), discordFieldLimit)})
+	}
+
+	if d.artifacts != "" {
+		if strings.HasPrefix(d.artifacts, "http://") || strings.HasPrefix(d.artifacts, "https://") {
+			embed.URL = d.artifacts
+		}
+		embed.Fields = append(embed.Fields, discordField{Name: "Artifacts", Value: truncate(d.artifacts, discordFieldLimit)})
+	}
+	return embed
+}
+
+func (d *Discord) post(body []byte) error {
+	ctx, cancel := context.WithTimeout(context.Background(), webhookTimeout)
+	defer cancel()
+
+	url := d.webhookURL
+	if url == "" {
+		url = fmt.Sprintf("%s/channels/%s/messages", discordAPI, d.channel)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if d.token != "" {
+		req.Header.Set("Authorization", "Bot "+d.token)
+	}
+
+	res, err := d.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return fmt.Errorf("the Discord API responded with status %d", res.StatusCode)
+	}
+	return nil
+}
+
+// truncate shortens the text to the limit of runes, ending it with an ellipsis
+func truncate(text string, limit int) string {
+	runes := []rune(text)
+	if len(runes) <= limit {
+		return text
+	}
+	return string(runes[:limit-1]) + "…"
+}
@@ -0,0 +1,151 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/metric"
+)
+
+type (
+	// FleetHost is the outcome of a run on one host of a fleet, Err is set when the run failed there
+	FleetHost struct {
+		Host    string    `json:"host"`
+		At      time.Time `json:"at"`
+		Records []Record  `json:"records,omitempty"`
+		Err     string    `json:"error,omitempty"`
+	}
+
+	// FleetSummary condenses the records of a host
+	FleetSummary struct {
+		Verdict string `json:"verdict"`
+		Healthy int    `json:"healthy"`
+		Total   int    `json:"total"`
+		Score   int    `json:"score"`
+		// Findings name the unhealthy metrics, the highest score first
+		Findings []string `json:"findings,omitempty"`
+	}
+)
+
+// Summarize condenses the metrics of the main table of the host, the verdict tells the run failed
+func (h FleetHost) Summarize() FleetSummary {
+	if h.Err != "" {
+		return FleetSummary{Verdict: "Failed: " + h.Err}
+	}
+	main, _ := (&collector{records: h.Records}).split()
+	s := summarize(main, false)
+	findings := make([]string, 0, len(s.Findings))
+	for _, finding := range s.Findings {
+		name := fmt.Sprintf("%s/%s", finding.GroupName, finding.MetricName)
+		if finding.Session != "" {
+			name = finding.Session + ": " + name
+		}
+		findings = append(findings, name)
+	}
+	return FleetSummary{Verdict: s.Verdict, Healthy: s.Healthy, Total: s.Total, Score: s.Score, Findings: findings}
+}
+
+// RenderFleet renders the records of every host as one merged report whose sessions are the hosts, followed by the
+// comparison of the hosts: their verdict and score, and the health of every metric side by side
+func RenderFleet(out io.Writer, hosts []FleetHost) {
+	merged := NewMerged(out)
+	for _, host := range hosts {
+		for _, record := range host.Records {
+			merged.AddRecord(hostRecord(host.Host, record))
+		}
+	}
+	merged.Render()
+
+	fmt.Fprintf(out, "\nHosts\n")
+	summaries := newTable(out, []string{"Host", "Verdict", "Healthy Metrics", "Score"})
+	for _, host := range hosts {
+		s := host.Summarize()
+		if host.Err != "" {
+			summaries.AddRow(host.Host, s.Verdict, "-", "-")
+			continue
+		}
+		summaries.AddRow(host.Host, s.Verdict, fmt.Sprintf("%d/%d", s.Healthy, s.Total), fmt.Sprint(s.Score))
+	}
+	summaries.Render()
+
+	fmt.Fprintf(out, "\nMetrics by host\n")
+	headers := []string{"Group Name", "Metric Name"}
+	for _, host := range hosts {
+		headers = append(headers, host.Host)
+	}
+	comparison := newTable(out, headers)
+	for _, key := range fleetMetrics(hosts) {
+		row := []string{string(key.group), key.name}
+		for _, host := range hosts {
+			row = append(row, fleetCell(host.Records, key.group, key.name))
+		}
+		comparison.AddRow(row...)
+	}
+	comparison.Render()
+}
+
+type fleetMetric struct {
+	group metric.Group
+	name  string
+}
+
+// fleetMetrics lists the metrics of the main table measured on any host, in the order they first appear
+func fleetMetrics(hosts []FleetHost) []fleetMetric {
+	var keys []fleetMetric
+	seen := make(map[fleetMetric]bool)
+	for _, host := range hosts {
+		for _, record := range host.Records {
+			key := fleetMetric{group: record.GroupName, name: record.MetricName}
+			if _, ok := sectionOf(record.GroupName); ok || seen[key] {
+				continue
+			}
+			seen[key] = true
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// fleetCell shows the health and severity of the metric on a host, of every session when the host ran several
+func fleetCell(records []Record, group metric.Group, name string) string {
+	var lines []string
+	for _, record := range records {
+		if record.GroupName != group || record.MetricName != name {
+			continue
+		}
+		line := string(record.Health)
+		if severity := formatSeverityMap(unhealthySeverities(record.Severity)); severity != "" {
+			line += " " + severity
+		}
+		if record.Session != "" {
+			line = record.Session + ": " + line
+		}
+		lines = append(lines, line)
+	}
+	if len(lines) == 0 {
+		return "-"
+	}
+	return strings.Join(lines, " \n ")
+}
+
+func unhealthySeverities(severities map[string]metric.SeverityLevel) map[string]metric.SeverityLevel {
+	unhealthy := make(map[string]metric.SeverityLevel)
+	for name, severity := range severities {
+		if severity != metric.SeverityNone {
+			unhealthy[name] = severity
+		}
+	}
+	return unhealthy
+}
+
+// hostRecord prefixes the session of the record with its host
+func hostRecord(host string, record Record) Record {
+	if record.Session == "" {
+		record.Session = host
+	} else {
+		record.Session = host + "/" + record.Session
+	}
+	return record
+}
@@ -0,0 +1,24 @@
+package report
+
+import "sync"
+
+// footer holds the notes rendered below the tables of every report
+var footer struct {
+	notes []string
+	mutex sync.Mutex
+}
+
+// AddFooter adds a note below the tables of every report, e.g. that a newer version of the benchmark is available
+func AddFooter(note string) {
+	footer.mutex.Lock()
+	defer footer.mutex.Unlock()
+
+	footer.notes = append(footer.notes, note)
+}
+
+func footerNotes() []string {
+	footer.mutex.Lock()
+	defer footer.mutex.Unlock()
+
+	return append([]string(nil), footer.notes...)
+}
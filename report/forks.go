@@ -0,0 +1,47 @@
+package report
+
+import (
+	"fmt"
+	"io"
+)
+
+const (
+	ForkReady = "ready"
+	// ForkOutdated is a client release older than the first one supporting the fork
+	ForkOutdated = "outdated"
+	// ForkUnscheduled is a beacon node whose fork schedule lacks the fork, it stops following the chain at the fork
+	ForkUnscheduled = "not scheduled"
+	// ForkUnknown is a client whose release couldn't be determined or which isn't known
+	ForkUnknown = "unknown"
+)
+
+// ForkReadiness is the readiness of the client of one layer for a fork of the network
+type ForkReadiness struct {
+	Fork     string `json:"fork"`
+	Epoch    uint64 `json:"epoch"`
+	Upcoming bool   `json:"upcoming"`
+	Layer    string `json:"layer"`
+	Client   string `json:"client"`
+	Version  string `json:"version"`
+	Minimum  string `json:"minimum"`
+	Status   string `json:"status"`
+	Detail   string `json:"detail,omitempty"`
+}
+
+// RenderForkReadiness renders the readiness as table followed by the number of clients not ready for upcoming forks
+func RenderForkReadiness(out io.Writer, readiness []ForkReadiness) {
+	t := newTable(out, []string{"Fork", "Epoch", "Layer", "Client", "Version", "Minimum", "Status", "Detail"})
+	notReady := 0
+	for _, r := range readiness {
+		epoch := fmt.Sprintf("%d", r.Epoch)
+		if r.Upcoming {
+			epoch += " (upcoming)"
+			if r.Status != ForkReady {
+				notReady++
+			}
+		}
+		t.AddRow(r.Fork, epoch, r.Layer, r.Client, r.Version, r.Minimum, r.Status, r.Detail)
+	}
+	t.Render()
+	fmt.Fprintf(out, "\n%d clients not ready for upcoming forks\n", notReady)
+}
@@ -0,0 +1,52 @@
+package report
+
+import (
+	"fmt"
+	"io"
+)
+
+const (
+	GradeGood = "good"
+	// GradeMinimum meets the minimum requirements of staking, clients keep up but have little headroom
+	GradeMinimum = "minimum"
+	// GradeInsufficient falls short of the minimum requirements, clients will likely fall behind the chain
+	GradeInsufficient = "insufficient"
+	// GradeUnknown couldn't be assessed on this system
+	GradeUnknown = "unknown"
+)
+
+// HardwareCheck grades a result of the hardware assessment against the requirements of staking
+type HardwareCheck struct {
+	Check   string `json:"check"`
+	Result  string `json:"result"`
+	Good    string `json:"good"`
+	Minimum string `json:"minimum"`
+	Grade   string `json:"grade"`
+	Detail  string `json:"detail,omitempty"`
+}
+
+// RenderHardwareCheck renders the checks as table followed by the overall grade, the worst of the checks
+func RenderHardwareCheck(out io.Writer, checks []HardwareCheck) {
+	t := newTable(out, []string{"Check", "Result", "Good", "Minimum", "Grade", "Detail"})
+	for _, c := range checks {
+		t.AddRow(c.Check, c.Result, c.Good, c.Minimum, c.Grade, c.Detail)
+	}
+	t.Render()
+	fmt.Fprintf(out, "\nOverall grade: %s\n", OverallGrade(checks))
+}
+
+// OverallGrade is the worst grade of the checks, checks of unknown grade are left out
+func OverallGrade(checks []HardwareCheck) string {
+	overall := GradeUnknown
+	for _, c := range checks {
+		switch {
+		case c.Grade == GradeInsufficient:
+			return GradeInsufficient
+		case c.Grade == GradeMinimum:
+			overall = GradeMinimum
+		case c.Grade == GradeGood && overall == GradeUnknown:
+			overall = GradeGood
+		}
+	}
+	return overall
+}
@@ -0,0 +1,114 @@
+package report
+
+import (
+	"html/template"
+	"io"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/format"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/metric"
+)
+
+var htmlTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"lines": func(value string) []string { return strings.Split(value, " \n ") },
+}).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Benchmark report</title>
+<style>
+body { font-family: sans-serif; margin: 2em; }
+table { border-collapse: collapse; margin-bottom: 2em; }
+th, td { border: 1px solid #ccc; padding: 0.4em 0.8em; text-align: left; vertical-align: top; }
+th { background: #f0f0f0; }
+tr.unhealthy td { background: #fdecea; }
+td.hint { color: #555; font-style: italic; }
+p.note { color: #555; }
+</style>
+</head>
+<body>
+<h1>Benchmark report</h1>
+<p>Generated at {{.Generated}}</p>
+{{define "table"}}<table>
+<tr>{{range .Headers}}<th>{{.}}</th>{{end}}</tr>
+{{range .Rows}}<tr{{if .Unhealthy}} class="unhealthy"{{end}}>{{range .Cells}}<td>{{range $i, $line := lines .}}{{if $i}}<br>{{end}}{{$line}}{{end}}</td>{{end}}</tr>
+{{range .Hints}}<tr><td class="hint" colspan="{{$.Columns}}">Hint: {{.}}</td></tr>
+{{end}}{{end}}</table>
+{{end}}{{template "table" .Main}}
+{{range .Sections}}<h2>{{.Title}}</h2>
+{{template "table" .Table}}{{end}}{{range .Notes}}<p class="note">{{.}}</p>
+{{end}}</body>
+</html>
+`))
+
+type (
+	// HTML renders the records as a standalone HTML page, unhealthy rows are highlighted
+	HTML struct {
+		collector
+		out         io.Writer
+		withSession bool
+	}
+
+	htmlTable struct {
+		Headers []string
+		Rows    []htmlRow
+		Columns int
+	}
+
+	htmlRow struct {
+		Cells     []string
+		Hints     []string
+		Unhealthy bool
+	}
+)
+
+// NewHTML creates an HTML report, prefixing every row with its session name when it is shared by sessions
+func NewHTML(out io.Writer, withSession bool) *HTML {
+	return &HTML{
+		out:         out,
+		withSession: withSession,
+	}
+}
+
+func (h *HTML) Render() {
+	main, sections := h.split()
+
+	type htmlSection struct {
+		Title string
+		Table htmlTable
+	}
+	page := struct {
+		Generated string
+		Main      htmlTable
+		Sections  []htmlSection
+		Notes     []string
+	}{
+		Generated: format.Timestamp(time.Now()),
+		Notes:     footerNotes(),
+		Main:      h.table(headers, main, mainRow),
+	}
+	for _, s := range sections {
+		page.Sections = append(page.Sections, htmlSection{Title: string(s.Group), Table: h.table(s.Headers, s.Records, sectionRow)})
+	}
+
+	if err := htmlTemplate.Execute(h.out, page); err != nil {
+		slog.With("err", err.Error()).Error("failed writing HTML report")
+	}
+}
+
+func (h *HTML) table(headers []string, records []Record, row func(Record) []string) htmlTable {
+	if h.withSession {
+		headers = append([]string{sessionHeader}, headers...)
+	}
+	t := htmlTable{Headers: headers, Columns: len(headers)}
+	for _, record := range records {
+		cells := row(record)
+		if h.withSession {
+			cells = append([]string{record.Session}, cells...)
+		}
+		t.Rows = append(t.Rows, htmlRow{Cells: cells, Hints: record.Hints, Unhealthy: record.Health == metric.Unhealthy})
+	}
+	return t
+}
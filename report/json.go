@@ -0,0 +1,34 @@
+package report
+
+import (
+	"encoding/json"
+	"io"
+	"log/slog"
+)
+
+// JSON writes the records as a JSON document, e.g. for further processing by scripts
+type JSON struct {
+	collector
+	out io.Writer
+}
+
+func NewJSON(out io.Writer) *JSON {
+	return &JSON{
+		out: out,
+	}
+}
+
+func (j *JSON) Render() {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+
+	encoder := json.NewEncoder(j.out)
+	encoder.SetIndent("", "  ")
+	document := map[string]any{"records": j.records}
+	if notes := footerNotes(); len(notes) != 0 {
+		document["notes"] = notes
+	}
+	if err := encoder.Encode(document); err != nil {
+		slog.With("err", err.Error()).Error("failed writing JSON report")
+	}
+}
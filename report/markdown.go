@@ -0,0 +1,70 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Markdown renders the records as Markdown tables, e.g. for a pull request comment or a CI job summary
+type Markdown struct {
+	collector
+	out         io.Writer
+	withSession bool
+}
+
+// NewMarkdown creates a Markdown report, prefixing every row with its session name when it is shared by sessions
+func NewMarkdown(out io.Writer, withSession bool) *Markdown {
+	return &Markdown{
+		out:         out,
+		withSession: withSession,
+	}
+}
+
+func (m *Markdown) Render() {
+	main, sections := m.split()
+
+	var builder strings.Builder
+	m.writeTable(&builder, headers, main, mainRow)
+	for _, s := range sections {
+		fmt.Fprintf(&builder, "\n### %s\n\n", s.Group)
+		m.writeTable(&builder, s.Headers, s.Records, sectionRow)
+	}
+	for _, note := range footerNotes() {
+		fmt.Fprintf(&builder, "\n> %s\n", note)
+	}
+	fmt.Fprint(m.out, builder.String())
+}
+
+func (m *Markdown) writeTable(builder *strings.Builder, headers []string, records []Record, row func(Record) []string) {
+	if m.withSession {
+		headers = append([]string{sessionHeader}, headers...)
+	}
+	writeMarkdownRow(builder, headers)
+	builder.WriteString("|" + strings.Repeat(" --- |", len(headers)) + "\n")
+	for _, record := range records {
+		cells := row(record)
+		if m.withSession {
+			cells = append([]string{record.Session}, cells...)
+		}
+		writeMarkdownRow(builder, cells)
+
+		// Hints are rendered beneath the failing row, in the value column which precedes the health and severity
+		for _, hint := range record.Hints {
+			hintCells := make([]string, len(cells))
+			hintCells[len(cells)-3] = "Hint: " + hint
+			writeMarkdownRow(builder, hintCells)
+		}
+	}
+}
+
+// writeMarkdownRow writes the cells as a table row, the line breaks of multi-line values become '<br>'
+func writeMarkdownRow(builder *strings.Builder, cells []string) {
+	builder.WriteString("|")
+	for _, cell := range cells {
+		cell = strings.ReplaceAll(cell, " \n ", "<br>")
+		cell = strings.ReplaceAll(cell, "\n", "<br>")
+		builder.WriteString(" " + strings.ReplaceAll(cell, "|", "\\|") + " |")
+	}
+	builder.WriteString("\n")
+}
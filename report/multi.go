@@ -0,0 +1,32 @@
+package report
+
+type (
+	// Sink receives the records of a run and renders them once it ends, e.g. the console table or an exporter
+	Sink interface {
+		AddRecord(metric Record)
+		Render()
+	}
+
+	// Multi forwards records to several sinks, e.g. the console table and an exporter
+	Multi struct {
+		sinks []Sink
+	}
+)
+
+func NewMulti(sinks ...Sink) *Multi {
+	return &Multi{
+		sinks: sinks,
+	}
+}
+
+func (m *Multi) AddRecord(metric Record) {
+	for _, s := range m.sinks {
+		s.AddRecord(metric)
+	}
+}
+
+func (m *Multi) Render() {
+	for _, s := range m.sinks {
+		s.Render()
+	}
+}
@@ -0,0 +1,226 @@
+package report
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html/template"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/format"
+)
+
+const (
+	pdfTimeout = time.Minute
+	// chartWidth is the width in pixels of the longest bar of the score chart
+	chartWidth = 400
+)
+
+// pdfConverters print an HTML file to PDF, the first one installed is used
+var pdfConverters = []struct {
+	command string
+	args    func(htmlPath, pdfPath string) []string
+}{
+	{"wkhtmltopdf", func(htmlPath, pdfPath string) []string {
+		return []string{"--quiet", "--enable-local-file-access", htmlPath, pdfPath}
+	}},
+	{"chromium", chromeArgs},
+	{"chromium-browser", chromeArgs},
+	{"google-chrome", chromeArgs},
+}
+
+var summaryTemplate = template.Must(template.Must(htmlTemplate.Clone()).Funcs(template.FuncMap{
+	"severity": func(record Record) string { return formatSeverityMap(record.Severity) },
+}).New("summary").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Node benchmark summary</title>
+<style>
+body { font-family: sans-serif; margin: 2em; color: #222; }
+h1 { margin-bottom: 0; }
+.subtitle { color: #666; margin-top: 0.2em; }
+.cards { display: flex; gap: 1em; margin: 1.5em 0; }
+.card { border: 1px solid #ccc; border-radius: 6px; padding: 0.8em 1.2em; min-width: 8em; }
+.card .value { font-size: 1.8em; font-weight: bold; }
+.card.good .value { color: #2e7d32; }
+.card.bad .value { color: #c62828; }
+table { border-collapse: collapse; margin-bottom: 2em; page-break-inside: auto; }
+tr { page-break-inside: avoid; }
+th, td { border: 1px solid #ccc; padding: 0.4em 0.8em; text-align: left; vertical-align: top; }
+th { background: #f0f0f0; }
+tr.unhealthy td { background: #fdecea; }
+td.hint { color: #555; font-style: italic; }
+.appendix { page-break-before: always; }
+</style>
+</head>
+<body>
+<h1>Node benchmark summary</h1>
+<p class="subtitle">Generated at {{.Generated}}</p>
+<div class="cards">
+<div class="card {{if eq .Unhealthy 0}}good{{else}}bad{{end}}"><div>Verdict</div><div class="value">{{.Verdict}}</div></div>
+<div class="card"><div>Healthy metrics</div><div class="value">{{.Healthy}}/{{.Total}}</div></div>
+<div class="card {{if eq .Score 0}}good{{else}}bad{{end}}"><div>Score (lower is better)</div><div class="value">{{.Score}}</div></div>
+</div>
+{{if .Bars}}<h2>Score by group</h2>
+<svg width="{{.ChartWidth}}" height="{{.ChartHeight}}" xmlns="http://www.w3.org/2000/svg">
+{{range .Bars}}<text x="0" y="{{.TextY}}" font-size="13">{{.Label}}</text>
+<rect x="140" y="{{.Y}}" width="{{.Width}}" height="18" fill="#c62828"></rect>
+<text x="{{.ValueX}}" y="{{.TextY}}" font-size="13">{{.Score}}</text>
+{{end}}</svg>
+{{end}}{{if .Findings}}<h2>Findings</h2>
+<table>
+<tr><th>Group</th><th>Metric</th><th>Severity</th></tr>
+{{range .Findings}}<tr class="unhealthy"><td>{{.GroupName}}</td><td>{{.MetricName}}</td><td>{{severity .}}</td></tr>
+{{end}}</table>
+{{end}}{{if .Recommendations}}<h2>Recommendations</h2>
+<ul>
+{{range .Recommendations}}<li>{{.}}</li>
+{{end}}</ul>
+{{end}}<div class="appendix">
+<h2>Measurements</h2>
+{{template "table" .Main}}
+{{range .Sections}}<h2>{{.Title}}</h2>
+{{template "table" .Table}}{{end}}</div>
+</body>
+</html>
+`))
+
+type (
+	// PDF renders a summary document for people who don't read the console report: a verdict, the score of every
+	// group as a chart, the findings and the recommendations, followed by the measurements. The summary is written as
+	// HTML next to the PDF and printed by an installed converter, the HTML is kept when none is installed.
+	PDF struct {
+		collector
+		path        string
+		withSession bool
+	}
+
+	scoreBar struct {
+		Label                   string
+		Score                   int
+		Y, TextY, Width, ValueX int
+	}
+)
+
+// NewPDF creates a PDF report written to the path, prefixing every row with its session name when it is shared by
+// sessions
+func NewPDF(path string, withSession bool) *PDF {
+	return &PDF{
+		path:        path,
+		withSession: withSession,
+	}
+}
+
+func (p *PDF) Render() {
+	htmlPath := strings.TrimSuffix(p.path, ".pdf") + "-summary.html"
+	if err := p.writeSummary(htmlPath); err != nil {
+		slog.With("err", err.Error()).Error("failed writing PDF report summary")
+		return
+	}
+
+	if err := htmlToPDF(htmlPath, p.path); err != nil {
+		slog.With("err", err.Error()).With("summary", htmlPath).Error("failed printing PDF report, the summary is kept as HTML")
+		return
+	}
+	slog.With("path", p.path).Info("PDF report written")
+}
+
+func (p *PDF) writeSummary(path string) error {
+	main, sections := p.split()
+	h := HTML{withSession: p.withSession}
+
+	type htmlSection struct {
+		Title string
+		Table htmlTable
+	}
+	page := struct {
+		summary
+		Generated               string
+		Bars                    []scoreBar
+		ChartWidth, ChartHeight int
+		Main                    htmlTable
+		Sections                []htmlSection
+	}{
+		summary:    summarize(main, p.withSession),
+		Generated:  format.Timestamp(time.Now()),
+		Main:       h.table(headers, main, mainRow),
+		ChartWidth: chartWidth + 200,
+	}
+	for _, s := range sections {
+		page.Sections = append(page.Sections, htmlSection{Title: string(s.Group), Table: h.table(s.Headers, s.Records, sectionRow)})
+	}
+	page.Bars = scoreBars(page.GroupScores)
+	page.ChartHeight = len(page.Bars) * 26
+
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return summaryTemplate.Execute(file, page)
+}
+
+// scoreBars scales the scores of the groups to the width of the chart, the highest score first
+func scoreBars(scores map[string]int) []scoreBar {
+	var bars []scoreBar
+	highest := 0
+	for label, score := range scores {
+		if score == 0 {
+			continue
+		}
+		bars = append(bars, scoreBar{Label: label, Score: score})
+		highest = max(highest, score)
+	}
+	sort.Slice(bars, func(i, j int) bool {
+		if bars[i].Score != bars[j].Score {
+			return bars[i].Score > bars[j].Score
+		}
+		return bars[i].Label < bars[j].Label
+	})
+
+	for i := range bars {
+		bars[i].Y = i * 26
+		bars[i].TextY = bars[i].Y + 14
+		bars[i].Width = max(bars[i].Score*chartWidth/highest, 1)
+		bars[i].ValueX = 140 + bars[i].Width + 6
+	}
+	return bars
+}
+
+// htmlToPDF prints the HTML file with the first installed converter
+func htmlToPDF(htmlPath, pdfPath string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), pdfTimeout)
+	defer cancel()
+
+	for _, converter := range pdfConverters {
+		if _, err := exec.LookPath(converter.command); err != nil {
+			continue
+		}
+		if out, err := exec.CommandContext(ctx, converter.command, converter.args(htmlPath, pdfPath)...).CombinedOutput(); err != nil {
+			return errors.Join(err, fmt.Errorf("%s failed: %s", converter.command, strings.TrimSpace(string(out))))
+		}
+		return nil
+	}
+
+	commands := make([]string, 0, len(pdfConverters))
+	for _, converter := range pdfConverters {
+		commands = append(commands, converter.command)
+	}
+	return fmt.Errorf("no PDF converter found, install one of %s", strings.Join(commands, ", "))
+}
+
+func chromeArgs(htmlPath, pdfPath string) []string {
+	// Chrome opens URLs, which need absolute paths
+	if abs, err := filepath.Abs(htmlPath); err == nil {
+		htmlPath = abs
+	}
+	return []string{"--headless", "--disable-gpu", "--no-pdf-header-footer", "--print-to-pdf=" + pdfPath, "file://" + htmlPath}
+}
@@ -0,0 +1,32 @@
+package report
+
+import (
+	"fmt"
+	"io"
+)
+
+const (
+	PreflightPassed      = "passed"
+	PreflightUnreachable = "unreachable"
+	PreflightUnsupported = "unsupported"
+)
+
+// PreflightResult is the outcome of probing what an enabled metric depends on before the run, the detail tells what
+// failed
+type PreflightResult struct {
+	Session string `json:"session,omitempty"`
+	Group   string `json:"group"`
+	Metric  string `json:"metric"`
+	Status  string `json:"status"`
+	Detail  string `json:"detail,omitempty"`
+}
+
+// RenderPreflight renders the failed results as table, along with what happens to their metrics
+func RenderPreflight(out io.Writer, failed []PreflightResult, outcome string) {
+	fmt.Fprintf(out, "Preflight failed for %d metrics, %s\n", len(failed), outcome)
+	t := newTable(out, []string{"Session", "Group", "Metric", "Status", "Detail"})
+	for _, result := range failed {
+		t.AddRow(result.Session, result.Group, result.Metric, result.Status, result.Detail)
+	}
+	t.Render()
+}
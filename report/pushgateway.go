@@ -0,0 +1,117 @@
+package report
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/push"
+
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/metric"
+)
+
+const (
+	sessionLabel     = "session"
+	groupLabel       = "group"
+	metricLabel      = "metric"
+	measurementLabel = "measurement"
+)
+
+// Pushgateway pushes the collected Prometheus metrics periodically and the evaluated health of every metric
+// once the run is finished, so short-lived runs (e.g. cron jobs) don't rely on the /metrics endpoint being scraped.
+type Pushgateway struct {
+	pusher   *push.Pusher
+	interval time.Duration
+	health   *prometheus.GaugeVec
+	severity *prometheus.GaugeVec
+	mutex    sync.Mutex
+}
+
+func NewPushgateway(url, job string, grouping map[string]string, interval time.Duration) *Pushgateway {
+	health := prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "benchmark_metric_healthy",
+		Help: "Evaluated health of the metric at the end of the run, 1 when healthy",
+	}, []string{sessionLabel, groupLabel, metricLabel})
+	severity := prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "benchmark_measurement_severity",
+		Help: "Highest severity of the measurement at the end of the run: 0 none, 1 low, 2 medium, 3 high",
+	}, []string{sessionLabel, groupLabel, metricLabel, measurementLabel})
+
+	pusher := push.New(url, job).
+		Gatherer(prometheus.DefaultGatherer).
+		Collector(health).
+		Collector(severity)
+	for name, value := range grouping {
+		pusher = pusher.Grouping(name, value)
+	}
+
+	return &Pushgateway{
+		pusher:   pusher,
+		interval: interval,
+		health:   health,
+		severity: severity,
+	}
+}
+
+// Run pushes intermediate values every interval until the context is done
+func (p *Pushgateway) Run(ctx context.Context) {
+	if p.interval == 0 {
+		return
+	}
+
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			p.push()
+		}
+	}
+}
+
+func (p *Pushgateway) AddRecord(record Record) {
+	// A series per epoch would grow without bound, the epochs are only part of the rendered reports. The timeline
+	// repeats the health of the metrics.
+	if record.GroupName == metric.EpochsGroup || record.GroupName == metric.TimelineGroup {
+		return
+	}
+	var healthy float64
+	if record.Health == metric.Healthy {
+		healthy = 1
+	}
+	p.health.With(prometheus.Labels{
+		sessionLabel: record.Session,
+		groupLabel:   string(record.GroupName),
+		metricLabel:  record.MetricName,
+	}).Set(healthy)
+
+	for measurement, severity := range record.Severity {
+		p.severity.With(prometheus.Labels{
+			sessionLabel:     record.Session,
+			groupLabel:       string(record.GroupName),
+			metricLabel:      record.MetricName,
+			measurementLabel: measurement,
+		}).Set(float64(metric.CompareSeverities(severity, metric.SeverityNone)))
+	}
+}
+
+// Render pushes the final values
+func (p *Pushgateway) Render() {
+	p.push()
+}
+
+func (p *Pushgateway) push() {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if err := p.pusher.Add(); err != nil {
+		slog.With("err", err.Error()).Error("failed pushing metrics to the Pushgateway")
+		return
+	}
+	slog.Debug("metrics pushed to the Pushgateway")
+}
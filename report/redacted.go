@@ -0,0 +1,43 @@
+package report
+
+import "github.com/Harikakasimahanthi/benchmark-test/internal/platform/metric"
+
+// Redacted obscures the addresses and identifiers in the texts of the records before forwarding them to the sink, so
+// the rendered report can be shared publicly
+type Redacted struct {
+	sink   Sink
+	redact func(string) string
+}
+
+func NewRedacted(sink Sink, redact func(string) string) *Redacted {
+	return &Redacted{
+		sink:   sink,
+		redact: redact,
+	}
+}
+
+func (r *Redacted) AddRecord(record Record) {
+	record.Session = r.redact(record.Session)
+	record.Value = r.redact(record.Value)
+	if record.Conditions != nil {
+		conditions := make([]metric.ConditionResult, 0, len(record.Conditions))
+		for _, condition := range record.Conditions {
+			condition.Threshold = r.redact(condition.Threshold)
+			condition.Observed = r.redact(condition.Observed)
+			conditions = append(conditions, condition)
+		}
+		record.Conditions = conditions
+	}
+	if record.Hints != nil {
+		hints := make([]string, 0, len(record.Hints))
+		for _, hint := range record.Hints {
+			hints = append(hints, r.redact(hint))
+		}
+		record.Hints = hints
+	}
+	r.sink.AddRecord(record)
+}
+
+func (r *Redacted) Render() {
+	r.sink.Render()
+}
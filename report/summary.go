@@ -0,0 +1,74 @@
+package report
+
+import (
+	"slices"
+	"sort"
+
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/metric"
+)
+
+const (
+	verdictHealthy   = "Healthy"
+	verdictAttention = "Needs attention"
+	verdictUnhealthy = "Unhealthy"
+)
+
+// summary condenses the metrics of a run for people who don't read the whole report
+type summary struct {
+	Verdict                   string
+	Healthy, Unhealthy, Total int
+	// Score sums up the severity weights of the unhealthy metrics, lower is better
+	Score int
+	// Findings are the unhealthy metrics, the highest score first
+	Findings []Record
+	// Recommendations are the distinct hints of the findings
+	Recommendations []string
+	// GroupScores are the scores per group, prefixed by the session in merged reports
+	GroupScores map[string]int
+}
+
+func summarize(records []Record, withSession bool) summary {
+	s := summary{GroupScores: make(map[string]int)}
+	for _, record := range records {
+		s.Total++
+		if record.Health != metric.Unhealthy {
+			s.Healthy++
+			continue
+		}
+		s.Unhealthy++
+		s.Findings = append(s.Findings, record)
+
+		score := recordScore(record)
+		s.Score += score
+		group := string(record.GroupName)
+		if withSession && record.Session != "" {
+			group = record.Session + "/" + group
+		}
+		s.GroupScores[group] += score
+
+		for _, hint := range record.Hints {
+			if !slices.Contains(s.Recommendations, hint) {
+				s.Recommendations = append(s.Recommendations, hint)
+			}
+		}
+	}
+
+	// A quarter of unhealthy metrics at most needs attention, more make the node unhealthy
+	switch {
+	case s.Unhealthy == 0:
+		s.Verdict = verdictHealthy
+	case s.Unhealthy*4 <= s.Total:
+		s.Verdict = verdictAttention
+	default:
+		s.Verdict = verdictUnhealthy
+	}
+	sort.SliceStable(s.Findings, func(i, j int) bool {
+		return recordScore(s.Findings[i]) > recordScore(s.Findings[j])
+	})
+	return s
+}
+
+// recordScore sums up the weights of the highest severity of every measurement of the record
+func recordScore(record Record) int {
+	return metric.Evaluation{Severities: record.Severity}.Score()
+}
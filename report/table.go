@@ -2,7 +2,7 @@ package report
 
 import (
 	"fmt"
-	"os"
+	"io"
 	"strings"
 	"sync"
 
@@ -10,23 +10,64 @@ import (
 	"github.com/aquasecurity/table"
 )
 
-var headers = []string{"Group Name", "Metric Name", "Value", "Health", "Severity"}
+var (
+	headers = []string{"Group Name", "Metric Name", "Value", "Health", "Severity"}
+	// sectionHeaders are the headers of the groups rendered as their own section below the metrics, in this order
+	sectionHeaders = []struct {
+		group   metric.Group
+		headers []string
+	}{
+		{metric.AvailabilityGroup, []string{"Target", "Availability", "Health", "Severity"}},
+		{metric.SecurityGroup, []string{"Check", "Finding", "Health", "Severity"}},
+		{metric.RulesGroup, []string{"Rule", "Observed", "Health", "Severity"}},
+		{metric.EpochsGroup, []string{"Epoch", "Summary", "Health", "Severity"}},
+		{metric.TimelineGroup, []string{"Metric", "Timeline", "Health", "Severity"}},
+	}
+)
+
+const sessionHeader = "Session"
 
 type Record struct {
-	GroupName  metric.Group
-	MetricName string
-	Value      string
-	Health     metric.HealthStatus
-	Severity   map[string]metric.SeverityLevel
+	Session    string                          `json:"session,omitempty"`
+	GroupName  metric.Group                    `json:"group"`
+	MetricName string                          `json:"metric"`
+	Value      string                          `json:"value"`
+	Health     metric.HealthStatus             `json:"health"`
+	Severity   map[string]metric.SeverityLevel `json:"severity"`
+	// Conditions explain an unhealthy record
+	Conditions []metric.ConditionResult `json:"conditions,omitempty"`
+	// Hints suggest how to remediate an unhealthy record
+	Hints []string `json:"hints,omitempty"`
 }
 
 type Report struct {
-	t     *table.Table
-	mutex sync.Mutex
+	t           *table.Table
+	sections    map[metric.Group]*table.Table
+	out         io.Writer
+	withSession bool
+	mutex       sync.Mutex
+}
+
+func New(out io.Writer) *Report {
+	return newReport(out, headers, false)
+}
+
+// NewMerged creates a report shared by several benchmark sessions, prefixing every row with its session name
+func NewMerged(out io.Writer) *Report {
+	return newReport(out, append([]string{sessionHeader}, headers...), true)
+}
+
+func newReport(out io.Writer, headers []string, withSession bool) *Report {
+	return &Report{
+		t:           newTable(out, headers),
+		sections:    make(map[metric.Group]*table.Table),
+		out:         out,
+		withSession: withSession,
+	}
 }
 
-func New() *Report {
-	t := table.New(os.Stdout)
+func newTable(out io.Writer, headers []string) *table.Table {
+	t := table.New(out)
 
 	t.SetHeaders(headers...)
 
@@ -36,26 +77,113 @@ func New() *Report {
 	}
 	t.SetAlignment(alignments...)
 
-	return &Report{
-		t: t,
-	}
+	return t
 }
 
-func (r *Report) AddRecord(metric Record) {
+func (r *Report) AddRecord(record Record) {
 	r.mutex.Lock()
 	defer r.mutex.Unlock()
 
-	r.t.AddRow(
-		string(metric.GroupName),
-		string(metric.MetricName),
-		metric.Value,
-		string(metric.Health),
-		formatSeverityMap(metric.Severity),
-	)
+	if r.addSectionRecord(record) {
+		return
+	}
+
+	row := mainRow(record)
+	if r.withSession {
+		row = append([]string{record.Session}, row...)
+	}
+
+	r.t.AddRow(row...)
+
+	// Hints are rendered beneath the failing row
+	for _, hint := range record.Hints {
+		hintRow := []string{"", "", "Hint: " + hint, "", ""}
+		if r.withSession {
+			hintRow = append([]string{""}, hintRow...)
+		}
+		r.t.AddRow(hintRow...)
+	}
+}
+
+// addSectionRecord adds the record to the section of its group, which is rendered below the metrics. It reports
+// false when the group has no section of its own.
+func (r *Report) addSectionRecord(record Record) bool {
+	headers, ok := sectionOf(record.GroupName)
+	if !ok {
+		return false
+	}
+
+	t, ok := r.sections[record.GroupName]
+	if !ok {
+		if r.withSession {
+			headers = append([]string{sessionHeader}, headers...)
+		}
+		t = newTable(r.out, headers)
+		r.sections[record.GroupName] = t
+	}
+
+	row := sectionRow(record)
+	if r.withSession {
+		row = append([]string{record.Session}, row...)
+	}
+	t.AddRow(row...)
+	return true
+}
+
+// sectionOf returns the headers of the section of the group, false when its records belong to the main table
+func sectionOf(group metric.Group) ([]string, bool) {
+	for _, section := range sectionHeaders {
+		if section.group == group {
+			return section.headers, true
+		}
+	}
+	return nil, false
+}
+
+// mainRow is the row of a record in the main table, without the session
+func mainRow(record Record) []string {
+	return []string{
+		string(record.GroupName),
+		record.MetricName,
+		record.Value,
+		string(record.Health),
+		formatSeverity(record),
+	}
+}
+
+// sectionRow is the row of a record in the section of its group, without the session
+func sectionRow(record Record) []string {
+	return []string{
+		record.MetricName,
+		record.Value,
+		string(record.Health),
+		formatSeverityMap(record.Severity),
+	}
 }
 
 func (r *Report) Render() {
 	r.t.Render()
+
+	for _, section := range sectionHeaders {
+		if t, ok := r.sections[section.group]; ok {
+			fmt.Fprintf(r.out, "\n%s\n", section.group)
+			t.Render()
+		}
+	}
+	for _, note := range footerNotes() {
+		fmt.Fprintf(r.out, "\n%s\n", note)
+	}
+}
+
+// formatSeverity lists the severity of every measurement followed by the conditions which were met
+func formatSeverity(record Record) string {
+	severity := formatSeverityMap(record.Severity)
+	if len(record.Conditions) == 0 {
+		return severity
+	}
+
+	reasons := metric.Evaluation{Conditions: record.Conditions}.Reasons()
+	return severity + " \n " + reasons
 }
 
 func formatSeverityMap(severityMap map[string]metric.SeverityLevel) string {
@@ -0,0 +1,104 @@
+package report
+
+import (
+	"errors"
+	htmltemplate "html/template"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/format"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/metric"
+)
+
+var templateFuncs = map[string]any{
+	"lines":     func(value string) []string { return strings.Split(value, " \n ") },
+	"join":      strings.Join,
+	"clock":     format.Clock,
+	"timestamp": format.Timestamp,
+	"healthy":   func(record Record) bool { return record.Health == metric.Healthy },
+}
+
+type (
+	// Template renders the records with a template of the user, so organizations get the layout of their own reports.
+	// Templates of HTML files are HTML templates escaping the values, all others are text templates.
+	Template struct {
+		collector
+		template interface {
+			Execute(io.Writer, any) error
+		}
+		out io.Writer
+	}
+
+	// TemplateModel is the data the template is executed with
+	TemplateModel struct {
+		Generated time.Time
+		// Records are all records of the report, Main and Sections split them like the built-in reports
+		Records  []Record
+		Main     []Record
+		Sections []TemplateSection
+		// Headers are the headers of the main table
+		Headers []string
+		Notes   []string
+	}
+
+	// TemplateSection is a group rendered below the main table, e.g. the availability of the endpoints
+	TemplateSection struct {
+		Title   string
+		Headers []string
+		Records []Record
+	}
+)
+
+// NewTemplate parses the template file, named e.g. 'report.md.tmpl' or 'report.html'
+func NewTemplate(path string, out io.Writer) (*Template, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Join(err, errors.New("failed reading report template"))
+	}
+
+	t := &Template{out: out}
+	name := filepath.Base(path)
+	if filepath.Ext(TemplateOutput(name)) == ".html" {
+		t.template, err = htmltemplate.New(name).Funcs(templateFuncs).Parse(string(content))
+	} else {
+		t.template, err = template.New(name).Funcs(templateFuncs).Parse(string(content))
+	}
+	if err != nil {
+		return nil, errors.Join(err, errors.New("failed parsing report template"))
+	}
+	return t, nil
+}
+
+// TemplateOutput returns the name of the file rendered from the named template, without its template extension
+func TemplateOutput(name string) string {
+	for _, extension := range []string{".tmpl", ".gotmpl"} {
+		name = strings.TrimSuffix(name, extension)
+	}
+	return name
+}
+
+func (t *Template) Render() {
+	main, sections := t.split()
+
+	t.mutex.Lock()
+	model := TemplateModel{
+		Generated: time.Now(),
+		Records:   append([]Record(nil), t.records...),
+		Main:      main,
+		Headers:   headers,
+		Notes:     footerNotes(),
+	}
+	t.mutex.Unlock()
+	for _, s := range sections {
+		model.Sections = append(model.Sections, TemplateSection{Title: string(s.Group), Headers: s.Headers, Records: s.Records})
+	}
+
+	if err := t.template.Execute(t.out, model); err != nil {
+		slog.With("err", err.Error()).Error("failed rendering report template")
+	}
+}
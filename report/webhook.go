@@ -0,0 +1,65 @@
+package report
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+const webhookTimeout = time.Second * 10
+
+// Webhook posts the records as a JSON document to an HTTP endpoint once the run ends, e.g. a chat bot or a CI system
+type Webhook struct {
+	collector
+	url    string
+	client *http.Client
+}
+
+func NewWebhook(url string) *Webhook {
+	return &Webhook{
+		url:    url,
+		client: &http.Client{Timeout: webhookTimeout},
+	}
+}
+
+func (w *Webhook) Render() {
+	w.mutex.Lock()
+	body, err := json.Marshal(map[string]any{"records": w.records})
+	w.mutex.Unlock()
+	if err != nil {
+		slog.With("err", err.Error()).Error("failed encoding webhook report")
+		return
+	}
+
+	if err := w.post(body); err != nil {
+		slog.With("err", err.Error()).Error("failed sending report to webhook")
+		return
+	}
+	slog.Info("report sent to webhook")
+}
+
+func (w *Webhook) post(body []byte) error {
+	ctx, cancel := context.WithTimeout(context.Background(), webhookTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := w.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return fmt.Errorf("webhook responded with status %d", res.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,289 @@
+// Package results loads the artifacts of saved benchmark runs, so tooling written in Go reads them without
+// reverse-engineering the export schema. Runs are exported as JSON artifacts of the run directory, the data points and
+// statistics of every session, the report of the 'json' sink and the run metadata.
+package results
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/annotation"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/metric"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/run"
+	"github.com/Harikakasimahanthi/benchmark-test/report"
+)
+
+const (
+	metadataFile       = "metadata.json"
+	statisticsPattern  = "statistics*.json"
+	reportPattern      = "report*.json"
+	statisticsFileName = "statistics"
+)
+
+type (
+	// Metadata identifies the run, its configuration and the measurements taken once per run
+	Metadata = run.Metadata
+	// Summary is the sample count, mean and standard deviation of a measurement
+	Summary = metric.Summary
+	// Statistics of a measurement, durations are in nanoseconds and booleans count as 0 or 1
+	Statistics = metric.Statistics
+	// Record is a row of the report, the evaluated health of a metric
+	Record = report.Record
+	// Annotation marks an event during the run, e.g. a client restart
+	Annotation = annotation.Annotation
+
+	// Run is a saved benchmark run
+	Run struct {
+		Dir string
+		// Metadata is missing for runs which didn't finish
+		Metadata *Metadata
+		// Sessions are keyed by their name, the session of a run without sessions has an empty name
+		Sessions map[string]*Session
+		// Records are the report of the 'json' sink, of every session
+		Records []Record
+		// Annotations are the events marked during the run
+		Annotations []Annotation
+	}
+
+	// Session holds the measurements of a benchmark session
+	Session struct {
+		Name string
+		// DataPoints are keyed by group and metric name
+		DataPoints map[string]map[string][]DataPoint
+		// Statistics are keyed by group, metric and measurement name
+		Statistics map[string]map[string]map[string]Statistics
+	}
+
+	// DataPoint is a measurement of a metric. Numbers and durations (in nanoseconds) are float64, flags bool and
+	// texts string.
+	DataPoint struct {
+		Timestamp time.Time      `json:"timestamp"`
+		Values    map[string]any `json:"values"`
+	}
+
+	// Sample is the numeric value of a measurement at a time
+	Sample struct {
+		Timestamp time.Time
+		Value     float64
+	}
+
+	// Delta compares a measurement between two sessions, Change is the relative change of the mean in percent
+	Delta struct {
+		Group       string
+		Metric      string
+		Measurement string
+		Before      Summary
+		After       Summary
+		// Change is unknown (NaN) when the measurement is missing from either session or its mean was zero before
+		Change      float64
+		Significant bool
+	}
+)
+
+// Load reads the artifacts of the run directory, it fails for directories without data points
+func Load(dir string) (*Run, error) {
+	dataPoints, err := report.LoadDataPoints(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &Run{Dir: dir, Sessions: make(map[string]*Session, len(dataPoints))}
+	for name, groups := range dataPoints {
+		session := &Session{Name: name, DataPoints: make(map[string]map[string][]DataPoint, len(groups))}
+		for group, metrics := range groups {
+			session.DataPoints[string(group)] = make(map[string][]DataPoint, len(metrics))
+			for metricName, points := range metrics {
+				converted := make([]DataPoint, 0, len(points))
+				for _, point := range points {
+					converted = append(converted, DataPoint{Timestamp: point.Timestamp, Values: point.Values})
+				}
+				session.DataPoints[string(group)][metricName] = converted
+			}
+		}
+		r.Sessions[name] = session
+	}
+
+	if err := r.loadStatistics(); err != nil {
+		return nil, err
+	}
+	if err := r.loadRecords(); err != nil {
+		return nil, err
+	}
+
+	if err := readJSON(filepath.Join(dir, annotation.Artifact), &r.Annotations); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, errors.Join(err, errors.New("failed decoding run annotations"))
+	}
+
+	var metadata Metadata
+	if err := readJSON(filepath.Join(dir, metadataFile), &metadata); err == nil {
+		r.Metadata = &metadata
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return nil, errors.Join(err, errors.New("failed decoding run metadata"))
+	}
+	return r, nil
+}
+
+// loadStatistics reads the statistics of the sessions, runs exported before statistics existed have none
+func (r *Run) loadStatistics() error {
+	files, err := filepath.Glob(filepath.Join(r.Dir, statisticsPattern))
+	if err != nil {
+		return err
+	}
+	for _, file := range files {
+		name := strings.TrimPrefix(strings.TrimSuffix(strings.TrimPrefix(filepath.Base(file), statisticsFileName), ".json"), "-")
+		session, ok := r.Sessions[name]
+		if !ok {
+			continue
+		}
+		if err := readJSON(file, &session.Statistics); err != nil {
+			return errors.Join(err, fmt.Errorf("failed decoding statistics '%s'", file))
+		}
+	}
+	return nil
+}
+
+func (r *Run) loadRecords() error {
+	files, err := filepath.Glob(filepath.Join(r.Dir, reportPattern))
+	if err != nil {
+		return err
+	}
+	for _, file := range files {
+		var document struct {
+			Records []Record `json:"records"`
+		}
+		if err := readJSON(file, &document); err != nil {
+			return errors.Join(err, fmt.Errorf("failed decoding report '%s'", file))
+		}
+		r.Records = append(r.Records, document.Records...)
+	}
+	return nil
+}
+
+// Session returns the session of the name, the only session of the run when the name is empty
+func (r *Run) Session(name string) (*Session, error) {
+	if session, ok := r.Sessions[name]; ok {
+		return session, nil
+	}
+	if name == "" && len(r.Sessions) == 1 {
+		for _, session := range r.Sessions {
+			return session, nil
+		}
+	}
+	return nil, fmt.Errorf("run '%s' has no session '%s'", r.Dir, name)
+}
+
+// Unhealthy returns the records of the report which aren't healthy
+func (r *Run) Unhealthy() []Record {
+	var unhealthy []Record
+	for _, record := range r.Records {
+		if record.Health != metric.Healthy {
+			unhealthy = append(unhealthy, record)
+		}
+	}
+	return unhealthy
+}
+
+// Metrics returns the names of the metrics of the group measured by the session, sorted
+func (s *Session) Metrics(group string) []string {
+	names := make([]string, 0, len(s.DataPoints[group]))
+	for name := range s.DataPoints[group] {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Series returns the numeric values of a measurement of a metric in the order they were measured, text values are
+// skipped
+func (s *Session) Series(group, metricName, measurement string) []Sample {
+	var series []Sample
+	for _, point := range s.DataPoints[group][metricName] {
+		if number, ok := toFloat(point.Values[measurement]); ok {
+			series = append(series, Sample{Timestamp: point.Timestamp, Value: number})
+		}
+	}
+	return series
+}
+
+// Between returns the data points of a metric measured from the start until the end, the end excluded
+func (s *Session) Between(group, metricName string, from, to time.Time) []DataPoint {
+	var points []DataPoint
+	for _, point := range s.DataPoints[group][metricName] {
+		if !point.Timestamp.Before(from) && point.Timestamp.Before(to) {
+			points = append(points, point)
+		}
+	}
+	return points
+}
+
+// Compare computes the change of every numeric measurement between the sessions, sorted by group, metric and
+// measurement. The significance is a Welch's t-test of the means, like the one of 'report diff'.
+func Compare(before, after *Session) []Delta {
+	deltas := report.Diff("", before.exported(), after.exported())
+	result := make([]Delta, 0, len(deltas))
+	for _, delta := range deltas {
+		result = append(result, Delta{
+			Group:       string(delta.GroupName),
+			Metric:      delta.MetricName,
+			Measurement: delta.Measurement,
+			Before:      delta.Before,
+			After:       delta.After,
+			Change:      change(delta.Before, delta.After),
+			Significant: delta.Significant,
+		})
+	}
+	return result
+}
+
+// exported converts the data points back to the export schema the diff of the reports works on
+func (s *Session) exported() report.DataPoints {
+	dataPoints := make(report.DataPoints, len(s.DataPoints))
+	for group, metrics := range s.DataPoints {
+		dataPoints[metric.Group(group)] = make(map[string][]metric.ExportedDataPoint, len(metrics))
+		for name, points := range metrics {
+			converted := make([]metric.ExportedDataPoint, 0, len(points))
+			for _, point := range points {
+				converted = append(converted, metric.ExportedDataPoint{Timestamp: point.Timestamp, Values: point.Values})
+			}
+			dataPoints[metric.Group(group)][name] = converted
+		}
+	}
+	return dataPoints
+}
+
+func change(before, after Summary) float64 {
+	if before.Count == 0 || after.Count == 0 || before.Mean == 0 {
+		return math.NaN()
+	}
+	return (after.Mean - before.Mean) / math.Abs(before.Mean) * 100
+}
+
+// toFloat converts a decoded JSON value to a number, flags count as 0 or 1
+func toFloat(value any) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case bool:
+		if v {
+			return 1, true
+		}
+		return 0, true
+	default:
+		return 0, false
+	}
+}
+
+func readJSON(path string, value any) error {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(content, value)
+}
@@ -0,0 +1,69 @@
+package results
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeRun(t *testing.T, peers ...int) string {
+	dir := t.TempDir()
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	content := `{"Consensus": {"Peers": [`
+	for i, count := range peers {
+		if i != 0 {
+			content += ","
+		}
+		content += `{"timestamp": "` + start.Add(time.Duration(i)*time.Minute).Format(time.RFC3339) + `", "values": {"PeerCount": ` + strconv.Itoa(count) + `, "Client": "Lighthouse"}}`
+	}
+	content += `]}}`
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "datapoints.json"), []byte(content), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "report.json"), []byte(`{"records": [
+		{"group": "Consensus", "metric": "Peers", "value": "", "health": "Unhealthy⚠️", "severity": {"PeerCount": "High"}},
+		{"group": "Consensus", "metric": "Latency", "value": "", "health": "Healthy✅", "severity": {}}
+	]}`), 0o644))
+	return dir
+}
+
+func TestGivenRunDirectoryWhenLoadThenSessionAndReportLoaded(t *testing.T) {
+	r, err := Load(writeRun(t, 10, 20, 30))
+	require.NoError(t, err)
+
+	session, err := r.Session("")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Peers"}, session.Metrics("Consensus"))
+
+	series := session.Series("Consensus", "Peers", "PeerCount")
+	require.Len(t, series, 3)
+	assert.Equal(t, 20.0, series[1].Value)
+	assert.Empty(t, session.Series("Consensus", "Peers", "Client"))
+
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	assert.Len(t, session.Between("Consensus", "Peers", start, start.Add(2*time.Minute)), 2)
+
+	require.Len(t, r.Unhealthy(), 1)
+	assert.Equal(t, "Peers", r.Unhealthy()[0].MetricName)
+	assert.Nil(t, r.Metadata)
+}
+
+func TestGivenTwoRunsWhenCompareThenRelativeChangeOfMean(t *testing.T) {
+	before, err := Load(writeRun(t, 10, 20, 30))
+	require.NoError(t, err)
+	after, err := Load(writeRun(t, 20, 30, 40))
+	require.NoError(t, err)
+
+	deltas := Compare(before.Sessions[""], after.Sessions[""])
+	require.Len(t, deltas, 1)
+	assert.Equal(t, "PeerCount", deltas[0].Measurement)
+	assert.InDelta(t, 50, deltas[0].Change, 0.001)
+}
+
+func TestGivenDirectoryWithoutDataPointsWhenLoadThenError(t *testing.T) {
+	_, err := Load(t.TempDir())
+	assert.Error(t, err)
+}
@@ -0,0 +1,96 @@
+package benchmark
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/Harikakasimahanthi/benchmark-test/configs"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/metric"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/rules"
+	"github.com/Harikakasimahanthi/benchmark-test/report"
+)
+
+// newRules resolves the metrics of the configured rules. Like chaos faults, conditions which match no enabled metric
+// fail, the rule could never be met.
+func newRules(metrics map[metric.Group][]metricService, configured []configs.Rule) ([]rules.Rule, error) {
+	resolved := make([]rules.Rule, 0, len(configured))
+	for _, rule := range configured {
+		r := rules.Rule{
+			Name:     rule.Name,
+			Severity: metric.SeverityLevel(rule.Severity),
+			Hint:     rule.Hint,
+		}
+		for _, condition := range rule.Conditions {
+			threshold, err := rules.ParseThreshold(condition.Threshold)
+			if err != nil {
+				return nil, errors.Join(err, fmt.Errorf("invalid condition of rule '%s'", rule.Name))
+			}
+			group, name, ok := findMetric(metrics, condition.Group, condition.Metric)
+			if !ok {
+				return nil, fmt.Errorf("rule '%s' references metric '%s' of group '%s' which is not enabled", rule.Name, condition.Metric, condition.Group)
+			}
+			r.Conditions = append(r.Conditions, rules.Condition{
+				Group:       group,
+				Metric:      name,
+				Measurement: condition.Measurement,
+				Statistic:   rules.Statistic(condition.Statistic),
+				Operator:    metric.Operator(condition.Operator),
+				Threshold:   threshold,
+			})
+		}
+		if err := r.Validate(); err != nil {
+			return nil, err
+		}
+		resolved = append(resolved, r)
+	}
+	return resolved, nil
+}
+
+// findMetric returns the group and name of the enabled metric, matched case-insensitively
+func findMetric(metrics map[metric.Group][]metricService, group, name string) (metric.Group, string, bool) {
+	for metricGroup, groupMetrics := range metrics {
+		if !strings.EqualFold(string(metricGroup), group) {
+			continue
+		}
+		for _, m := range groupMetrics {
+			if strings.EqualFold(m.GetName(), name) {
+				return metricGroup, m.GetName(), true
+			}
+		}
+	}
+	return "", "", false
+}
+
+// ruleRecords evaluates the rules against the measurements of the session, a met rule is unhealthy with its severity
+func ruleRecords(session string, configured []rules.Rule, metrics map[metric.Group][]metricService) []report.Record {
+	results := rules.Evaluate(configured, func(group metric.Group, name string) ([]metric.ExportedDataPoint, bool) {
+		for _, m := range metrics[group] {
+			if m.GetName() == name {
+				return m.ExportDataPoints(), true
+			}
+		}
+		return nil, false
+	})
+
+	records := make([]report.Record, 0, len(results))
+	for _, result := range results {
+		record := report.Record{
+			Session:    session,
+			GroupName:  metric.RulesGroup,
+			MetricName: result.Rule.Name,
+			Value:      result.String(),
+			Health:     metric.Healthy,
+			Severity:   map[string]metric.SeverityLevel{},
+		}
+		if result.Met {
+			record.Health = metric.Unhealthy
+			record.Severity[result.Rule.Name] = result.Rule.Severity
+			if result.Rule.Hint != "" {
+				record.Hints = []string{result.Rule.Hint}
+			}
+		}
+		records = append(records, record)
+	}
+	return records
+}
@@ -0,0 +1,131 @@
+package benchmark
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/url"
+
+	"github.com/Harikakasimahanthi/benchmark-test/configs"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/metric"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/netstat"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/run"
+	"github.com/Harikakasimahanthi/benchmark-test/metrics/consensus"
+	"github.com/Harikakasimahanthi/benchmark-test/metrics/infrastructure"
+	"github.com/Harikakasimahanthi/benchmark-test/report"
+)
+
+const (
+	openPortsCheck  = "Open Ports"
+	checkpointCheck = "Checkpoint"
+)
+
+// securityRecords are the findings of the security checks run once at the start of the run, added to every report
+var securityRecords []report.Record
+
+// auditPorts lists the listening sockets of the host and reports every publicly reachable client API in the security
+// section. The audit is skipped where the sockets can't be listed.
+func auditPorts(benchmarkRun *run.Run) []report.Record {
+	listeners, err := netstat.Listeners()
+	if err != nil {
+		slog.With("err", err.Error()).Warn("skipping the open ports audit")
+		return nil
+	}
+
+	exposures := infrastructure.AuditPorts(listeners)
+	if benchmarkRun != nil {
+		benchmarkRun.SetMetadata("open_ports", exposures)
+	}
+	if len(exposures) == 0 {
+		return []report.Record{{
+			GroupName:  metric.SecurityGroup,
+			MetricName: openPortsCheck,
+			Value:      fmt.Sprintf("no client API is publicly reachable, %d listening sockets", len(listeners)),
+			Health:     metric.Healthy,
+			Severity:   map[string]metric.SeverityLevel{},
+		}}
+	}
+
+	records := make([]report.Record, 0, len(exposures))
+	for _, exposure := range exposures {
+		slog.
+			With("service", exposure.Service).
+			With("listener", exposure.Listener.String()).
+			With("severity", exposure.Severity).
+			Warn("client API is publicly reachable")
+		records = append(records, report.Record{
+			GroupName:  metric.SecurityGroup,
+			MetricName: fmt.Sprintf("%s %d/%s", openPortsCheck, exposure.Listener.Port, exposure.Listener.Protocol),
+			Value:      exposure.String() + " \n bind it to 127.0.0.1 or firewall the port",
+			Health:     metric.Unhealthy,
+			Severity:   map[string]metric.SeverityLevel{"Exposure": exposure.Severity},
+		})
+	}
+	return records
+}
+
+// verifyCheckpoints compares the finalized checkpoint of the beacon node with the ones of the configured checkpoint
+// providers, a node synced to another chain than the providers is reported in the security section. Unreachable
+// providers are skipped.
+func verifyCheckpoints(config configs.Benchmark, benchmarkRun *run.Run) []report.Record {
+	if config.BeaconNode.Address == "" || len(config.BeaconNode.CheckpointProviders) == 0 {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), clientDetectionTimeout)
+	defer cancel()
+
+	var (
+		records       []report.Record
+		verifications []consensus.CheckpointVerification
+	)
+	for _, provider := range config.BeaconNode.CheckpointProviders {
+		verification, err := consensus.VerifyCheckpoint(ctx, config.BeaconNode.Address, provider)
+		if err != nil {
+			slog.With("err", err.Error()).With("provider", providerHost(provider)).Warn("skipping the checkpoint verification")
+			continue
+		}
+		verification.Provider = providerHost(provider)
+		verifications = append(verifications, verification)
+
+		name := fmt.Sprintf("%s %s", checkpointCheck, verification.Provider)
+		if config.Name != "" {
+			name = fmt.Sprintf("%s (%s)", name, config.Name)
+		}
+		if verification.Match {
+			records = append(records, report.Record{
+				GroupName:  metric.SecurityGroup,
+				MetricName: name,
+				Value:      fmt.Sprintf("finalized block %s of epoch %d matches", verification.Checked.Root, verification.Checked.Epoch),
+				Health:     metric.Healthy,
+				Severity:   map[string]metric.SeverityLevel{},
+			})
+			continue
+		}
+
+		slog.
+			With("provider", verification.Provider).
+			With("detail", verification.Detail).
+			Error("beacon node is not on the chain of the checkpoint provider")
+		records = append(records, report.Record{
+			GroupName:  metric.SecurityGroup,
+			MetricName: name,
+			Value:      verification.Detail + " \n the node is not on the chain of the provider, resync it from a trusted checkpoint",
+			Health:     metric.Unhealthy,
+			Severity:   map[string]metric.SeverityLevel{"Checkpoint": metric.SeverityHigh},
+		})
+	}
+	if benchmarkRun != nil {
+		benchmarkRun.SetMetadata(sessionKey("checkpoints", config.Name), verifications)
+	}
+	return records
+}
+
+// providerHost names the provider by its host, its path may hold an API key
+func providerHost(provider string) string {
+	parsed, err := url.Parse(provider)
+	if err != nil || parsed.Host == "" {
+		return provider
+	}
+	return parsed.Host
+}
@@ -2,9 +2,26 @@ package benchmark
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"io"
 	"log/slog"
+	"path/filepath"
+	"runtime/debug"
+	"strings"
+	"sync"
+	"time"
 
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/annotation"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/availability"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/breaker"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/exporter"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/format"
 	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/metric"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/network"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/rules"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/run"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/server/route"
 	"github.com/Harikakasimahanthi/benchmark-test/report"
 )
 
@@ -13,60 +30,638 @@ type (
 		Measure(context.Context)
 		GetName() string
 		AggregateResults() string
-		EvaluateMetric() (metric.HealthStatus, map[string]metric.SeverityLevel)
-	}
-	reportService interface {
-		AddRecord(metric report.Record)
-		Render()
+		EvaluateMetric() metric.Evaluation
+		EvaluateSince(since time.Time) metric.Evaluation
+		EvaluateBetween(from, to time.Time) metric.Evaluation
+		ExportDataPoints() []metric.ExportedDataPoint
+		Samples() int
 	}
 
+	// distributedMetric keeps the durations it measured, the report can render their distribution
+	distributedMetric interface {
+		Distribution() []time.Duration
+	}
 	Service struct {
+		session string
+		run     *run.Run
 		metrics map[metric.Group][]metricService
-		report  reportService
+		report  report.Sink
+		// epochs holds the spec of the network when the report breaks the measurements down by epoch
+		epochs *network.Spec
+		rules  []rules.Rule
+		// histograms renders the distribution of the durations of the latency metrics in the report
+		histograms bool
+		// timeline renders the health of every metric over the course of the run in the report
+		timeline bool
+		started  time.Time
+		// annotations mark the events of the run on the timeline
+		annotations *annotation.Store
+		// compression and chunk define the archive of the raw data points, chunks of the time span are written while
+		// measuring so long runs don't hold all of them until the end
+		compression string
+		chunk       time.Duration
+		// archived is the end of the last written chunk, archiving is closed once the chunks are written until the end
+		archived  time.Time
+		archiving chan struct{}
+
+		// measuring holds the metrics which are enabled, so they can be switched off and on during the run
+		measuring map[metricService]measuring
+		// restarts counts the restarts of the metrics which panicked, by group and name
+		restarts map[string]*MetricRestart
+		ctx      context.Context
+		mutex    sync.Mutex
 	}
+
+	measuring struct {
+		cancel context.CancelFunc
+		done   chan struct{}
+	}
+
+	// MetricRestart counts the restarts of a metric after panics, Panic is the value of the last one. A metric which
+	// kept panicking is stopped.
+	MetricRestart struct {
+		Group    string `json:"group"`
+		Metric   string `json:"metric"`
+		Restarts int    `json:"restarts"`
+		Panic    string `json:"panic"`
+		Stopped  bool   `json:"stopped,omitempty"`
+	}
+)
+
+const (
+	// maxMetricRestarts is how often a panicking metric is restarted before it is stopped
+	maxMetricRestarts = 5
+	// metricRestartDelay keeps a metric panicking right away from restarting in a tight loop
+	metricRestartDelay = time.Second * 5
 )
 
 func New(
 	metrics map[metric.Group][]metricService,
-	reportService reportService,
+	reportSink report.Sink,
 ) *Service {
 	return &Service{
 		metrics: metrics,
-		report:  reportService,
+		report:  reportSink,
 	}
 }
 
+// WithSession names the benchmark session the service belongs to
+func (s *Service) WithSession(name string) *Service {
+	s.session = name
+	return s
+}
+
+// WithRun makes the service export raw data points to the artifacts directory of the run
+func (s *Service) WithRun(r *run.Run) *Service {
+	s.run = r
+	return s
+}
+
+// WithArchive compresses the exported raw data points and, with a chunk time span, writes them in chunks of the span
+// to the 'datapoints' directory of the run while measuring
+func (s *Service) WithArchive(compression string, chunk time.Duration) *Service {
+	s.compression = compression
+	s.chunk = chunk
+	return s
+}
+
+// WithEpochs adds a breakdown of the consensus measurements per epoch of the network to the report
+func (s *Service) WithEpochs(spec network.Spec) *Service {
+	s.epochs = &spec
+	return s
+}
+
+// WithHistograms renders a histogram of the durations beneath the aggregates of the latency metrics, percentiles
+// alone hide bimodal distributions caused by periodic stalls
+func (s *Service) WithHistograms() *Service {
+	s.histograms = true
+	return s
+}
+
+// WithTimeline adds a timeline of the health of every metric to the report, so intermittent issues aren't averaged
+// away by the evaluation of the whole run
+func (s *Service) WithTimeline() *Service {
+	s.timeline = true
+	return s
+}
+
+// WithAnnotations lists the annotations of the run beneath the timeline
+func (s *Service) WithAnnotations(store *annotation.Store) *Service {
+	s.annotations = store
+	return s
+}
+
+// WithRules adds the outcome of the health rules spanning several metrics to the report
+func (s *Service) WithRules(r []rules.Rule) *Service {
+	s.rules = r
+	return s
+}
+
 func (s *Service) Start(ctx context.Context) {
-	slog.With("metrics", s.metrics).Debug("starting benchmark service")
+	slog.With("session", s.session).With("metrics", s.metrics).Debug("starting benchmark service")
 
 	// Measure all metrics concurrently
-	for _, groupMetrics := range s.metrics {
-		for _, metric := range groupMetrics {
-			go metric.Measure(ctx)
+	s.mutex.Lock()
+	s.ctx = ctx
+	s.started = time.Now()
+	s.measuring = make(map[metricService]measuring)
+	s.restarts = make(map[string]*MetricRestart)
+	for group, groupMetrics := range s.metrics {
+		for _, m := range groupMetrics {
+			s.measure(group, m)
 		}
 	}
+	s.mutex.Unlock()
+
+	if s.run != nil && s.chunk > 0 {
+		s.archiving = make(chan struct{})
+		go s.archiveChunks(ctx)
+	}
 
 	// Wait for context cancellation
 	<-ctx.Done()
 
 	// Evaluate metrics and generate reports
+	for _, record := range s.records() {
+		slog.With("session", s.session).With("metric_group", record.GroupName).With("metric_name", record.MetricName).Info("adding report record")
+		s.report.AddRecord(record)
+	}
+	if s.epochs != nil {
+		for _, record := range epochRecords(s.session, s.metrics, *s.epochs) {
+			s.report.AddRecord(record)
+		}
+	}
+	if s.timeline {
+		end := time.Now()
+		for _, record := range timelineRecords(s.session, s.metrics, s.started, end) {
+			s.report.AddRecord(record)
+		}
+		if s.annotations != nil {
+			if record, ok := annotationRecord(s.session, s.annotations.Between(s.started, end), s.started, end); ok {
+				s.report.AddRecord(record)
+			}
+		}
+	}
+	for _, record := range ruleRecords(s.session, s.rules, s.metrics) {
+		s.report.AddRecord(record)
+	}
+
+	if s.run != nil {
+		s.exportDataPoints()
+		if restarts := s.MetricRestarts(); len(restarts) != 0 {
+			s.run.SetMetadata(sessionKey("metric_restarts", s.session), restarts)
+		}
+	}
+}
+
+// measure starts measuring the metric until the run ends or the metric is disabled, the mutex has to be held
+func (s *Service) measure(group metric.Group, m metricService) {
+	ctx, cancel := context.WithCancel(s.ctx)
+	done := make(chan struct{})
+	s.measuring[m] = measuring{cancel: cancel, done: done}
+	go func() {
+		defer close(done)
+		s.supervise(ctx, cancel, group, m)
+	}()
+}
+
+// supervise measures the metric and restarts it after a panic, so one failing metric neither takes down the run nor
+// silently stops measuring. Panics recovered by the runner or in the goroutines of the metric count as restarts too,
+// the metric measures again on its next tick. A metric panicking more than maxMetricRestarts times is stopped. The
+// values exported by the metric are labelled by the session.
+func (s *Service) supervise(ctx context.Context, stop context.CancelFunc, group metric.Group, m metricService) {
+	ctx = exporter.WithSession(ctx, s.session)
+	ctx = metric.WithPanicHandler(ctx, func(recovered any, stack []byte) {
+		if s.panicked(group, m.GetName(), recovered, stack) {
+			stop()
+		}
+	})
+
+	for {
+		recovered, stack := measureRecovering(ctx, m)
+		if recovered == nil || ctx.Err() != nil {
+			return
+		}
+		if s.panicked(group, m.GetName(), recovered, stack) {
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(metricRestartDelay):
+		}
+	}
+}
+
+// panicked records the restart of the metric after the panic and tells whether the metric has to be stopped instead
+func (s *Service) panicked(group metric.Group, name string, recovered any, stack []byte) bool {
+	restart := s.recordRestart(group, name, fmt.Sprint(recovered))
+	log := slog.
+		With("session", s.session).
+		With("metric_group", group).
+		With("metric_name", name).
+		With("panic", restart.Panic).
+		With("stack", string(stack)).
+		With("restarts", restart.Restarts)
+	if restart.Stopped {
+		log.Error("metric stopped, it kept panicking")
+		return true
+	}
+	log.Error("metric panicked, restarting it")
+	return false
+}
+
+// measureRecovering measures the metric until the context is done, a panic is recovered and returned with its stack
+func measureRecovering(ctx context.Context, m metricService) (recovered any, stack []byte) {
+	defer func() {
+		if recovered = recover(); recovered != nil {
+			stack = debug.Stack()
+		}
+	}()
+
+	m.Measure(ctx)
+	return nil, nil
+}
+
+// recordRestart counts the restart of the metric after the panic, once it was restarted maxMetricRestarts times it is
+// stopped instead
+func (s *Service) recordRestart(group metric.Group, name, recovered string) MetricRestart {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	key := restartKey(group, name)
+	restart, ok := s.restarts[key]
+	if !ok {
+		restart = &MetricRestart{Group: string(group), Metric: name}
+		s.restarts[key] = restart
+	}
+	restart.Panic = recovered
+	if restart.Restarts == maxMetricRestarts {
+		restart.Stopped = true
+	} else {
+		restart.Restarts++
+	}
+	return *restart
+}
+
+// MetricRestarts returns the metrics restarted after a panic, ordered by group and name
+func (s *Service) MetricRestarts() []MetricRestart {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	restarts := make([]MetricRestart, 0, len(s.restarts))
+	for _, key := range sortedKeys(s.restarts) {
+		restarts = append(restarts, *s.restarts[key])
+	}
+	return restarts
+}
+
+func restartKey(group metric.Group, name string) string {
+	return string(group) + "/" + name
+}
+
+// MetricStates tells which metrics of the session are measuring
+func (s *Service) MetricStates() []route.MetricState {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	var states []route.MetricState
+	for group, groupMetrics := range s.metrics {
+		for _, m := range groupMetrics {
+			_, enabled := s.measuring[m]
+			states = append(states, route.MetricState{Session: s.session, Group: string(group), Name: m.GetName(), Enabled: enabled})
+		}
+	}
+	return states
+}
+
+// SetEnabled switches the metric of the group on or off while the session runs, a disabled metric keeps the data
+// points measured so far. Group and name are matched case-insensitively.
+func (s *Service) SetEnabled(group, name string, enabled bool) ([]route.MetricState, error) {
+	var stopped []measuring
+	var states []route.MetricState
+
+	s.mutex.Lock()
 	for metricGroup, groupMetrics := range s.metrics {
+		if !strings.EqualFold(string(metricGroup), group) {
+			continue
+		}
 		for _, m := range groupMetrics {
-			health, severity := m.EvaluateMetric()
+			if !strings.EqualFold(m.GetName(), name) {
+				continue
+			}
+			if s.ctx == nil || s.ctx.Err() != nil {
+				s.mutex.Unlock()
+				return nil, errors.New("the session is not running")
+			}
+			running, ok := s.measuring[m]
+			switch {
+			case enabled && !ok:
+				s.measure(metricGroup, m)
+			case !enabled && ok:
+				delete(s.measuring, m)
+				stopped = append(stopped, running)
+			}
+			states = append(states, route.MetricState{Session: s.session, Group: string(metricGroup), Name: m.GetName(), Enabled: enabled})
+		}
+	}
+	s.mutex.Unlock()
+
+	// The metric is stopped once its measuring returned, so enabling it again doesn't measure twice
+	for _, running := range stopped {
+		running.cancel()
+		<-running.done
+	}
+	if len(states) == 0 {
+		return nil, route.ErrMetricNotFound
+	}
+	for _, state := range states {
+		slog.With("session", s.session).With("metric_group", state.Group).With("metric_name", state.Name).With("enabled", enabled).Info("metric switched")
+	}
+	return states, nil
+}
 
-			slog.With("metric_group", metricGroup).With("metric_name", m.GetName()).Info("adding report record")
-			// Add record to report
-			s.report.AddRecord(report.Record{
+// records evaluates every metric with the data points measured so far
+func (s *Service) records() []report.Record {
+	var records []report.Record
+	for metricGroup, groupMetrics := range s.metrics {
+		for _, m := range groupMetrics {
+			evaluation := m.EvaluateMetric()
+			value := m.AggregateResults()
+			if distributed, ok := m.(distributedMetric); ok && s.histograms {
+				if histogram := metric.Histogram(distributed.Distribution(), metric.HistogramBuckets); len(histogram) != 0 {
+					value += " \n " + strings.Join(metric.FormatHistogram(histogram), " \n ")
+				}
+			}
+			records = append(records, report.Record{
+				Session:    s.session,
 				GroupName:  metricGroup,
 				MetricName: m.GetName(),
-				Value:      m.AggregateResults(),
-				Health:     health,
-				Severity:   severity,
+				Value:      value,
+				Health:     evaluation.Health,
+				Severity:   evaluation.Severities,
+				Conditions: evaluation.Conditions,
+				Hints:      hintsFor(metricGroup, evaluation.Conditions),
 			})
 		}
 	}
+	return records
+}
+
+func (s *Service) exportDataPoints() {
+	exported := make(map[metric.Group]map[string][]metric.ExportedDataPoint)
+	for metricGroup, groupMetrics := range s.metrics {
+		exported[metricGroup] = make(map[string][]metric.ExportedDataPoint)
+		for _, m := range groupMetrics {
+			exported[metricGroup][m.GetName()] = m.ExportDataPoints()
+		}
+	}
+
+	switch {
+	case s.chunk > 0:
+		<-s.archiving
+		s.writeChunk(s.archived, time.Time{})
+	case s.compression != run.CompressionNone:
+		if err := s.run.WriteCompressedJSON(s.artifactName("datapoints"), s.compression, exported); err != nil {
+			slog.With("session", s.session).With("err", err.Error()).Error("failed exporting data points")
+		}
+	default:
+		if err := s.run.WriteJSON(s.artifactName("datapoints"), exported); err != nil {
+			slog.With("session", s.session).With("err", err.Error()).Error("failed exporting data points")
+		}
+	}
+
+	// The statistics of every measurement let automated comparisons between runs tell regressions from noise
+	statistics := make(map[metric.Group]map[string]map[string]metric.Statistics, len(exported))
+	for metricGroup, groupMetrics := range exported {
+		statistics[metricGroup] = make(map[string]map[string]metric.Statistics, len(groupMetrics))
+		for name, dataPoints := range groupMetrics {
+			statistics[metricGroup][name] = metric.ComputeStatistics(dataPoints)
+		}
+	}
+	if err := s.run.WriteJSON(s.artifactName("statistics"), statistics); err != nil {
+		slog.With("session", s.session).With("err", err.Error()).Error("failed exporting statistics")
+	}
+}
+
+// archiveChunks writes the data points of every chunk once it is over, until the run ends
+func (s *Service) archiveChunks(ctx context.Context) {
+	defer close(s.archiving)
+
+	s.archived = s.started
+	for {
+		end := s.archived.Add(s.chunk)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(time.Until(end)):
+			s.writeChunk(s.archived, end)
+			s.archived = end
+		}
+	}
+}
+
+// writeChunk writes the data points measured from the start until the end to a file of the 'datapoints' directory
+// named by the start, a zero end includes every later data point
+func (s *Service) writeChunk(from, to time.Time) {
+	chunk := make(map[metric.Group]map[string][]metric.ExportedDataPoint)
+	for metricGroup, groupMetrics := range s.metrics {
+		chunk[metricGroup] = make(map[string][]metric.ExportedDataPoint)
+		for _, m := range groupMetrics {
+			var dataPoints []metric.ExportedDataPoint
+			for _, dp := range m.ExportDataPoints() {
+				if !dp.Timestamp.Before(from) && (to.IsZero() || dp.Timestamp.Before(to)) {
+					dataPoints = append(dataPoints, dp)
+				}
+			}
+			chunk[metricGroup][m.GetName()] = dataPoints
+		}
+	}
+
+	name := filepath.Join(strings.TrimSuffix(s.artifactName("datapoints"), ".json"), from.UTC().Format("20060102T150405Z")+".json")
+	if err := s.run.WriteCompressedJSON(name, s.compression, chunk); err != nil {
+		slog.With("session", s.session).With("err", err.Error()).Error("failed archiving chunk of data points")
+	}
+}
+
+// artifactName names a JSON artifact of the service, suffixed by the session when the run has several
+func (s *Service) artifactName(name string) string {
+	if s.session != "" {
+		return fmt.Sprintf("%s-%s.json", name, s.session)
+	}
+	return name + ".json"
+}
+
+// RunSessions runs all benchmark sessions concurrently and renders their reports once every session is finished.
+// Sessions sharing a (merged) report have it rendered only once. When a run is passed its metadata is written at the end.
+func RunSessions(ctx context.Context, services []*Service, r *run.Run) {
+	var wg sync.WaitGroup
+	for _, s := range services {
+		wg.Add(1)
+		go func(s *Service) {
+			defer wg.Done()
+			s.Start(ctx)
+		}(s)
+	}
+	wg.Wait()
+
+	// Render the reports
+	records := append(availabilityRecords(time.Now()), securityRecords...)
+	rendered := make(map[report.Sink]struct{})
+	for _, s := range services {
+		if _, ok := rendered[s.report]; ok {
+			continue
+		}
+		rendered[s.report] = struct{}{}
+
+		for _, record := range records {
+			s.report.AddRecord(record)
+		}
+
+		slog.With("session", s.session).Info("rendering report")
+		s.report.Render()
+	}
 
-	// Render the report
-	slog.Info("rendering report")
-	s.report.Render()
+	if r != nil {
+		if err := r.Finish(); err != nil {
+			slog.With("err", err.Error()).Error("failed writing run metadata")
+		}
+		fmt.Printf("Run '%s' artifacts were written to '%s'\n", r.ID, r.Dir)
+	}
+}
+
+// LogProgress periodically logs how far the run of the duration is and how many samples every metric collected,
+// so a long run without output is visibly progressing
+func LogProgress(ctx context.Context, services []*Service, duration time.Duration) {
+	start := time.Now()
+	ticker := time.NewTicker(progressInterval(duration))
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			elapsed := time.Since(start)
+			remaining := max(duration-elapsed, 0)
+
+			samples := make(map[string]int)
+			for _, s := range services {
+				for group, groupMetrics := range s.metrics {
+					for _, m := range groupMetrics {
+						samples[sessionKey(fmt.Sprintf("%s/%s", group, m.GetName()), s.session)] = m.Samples()
+					}
+				}
+			}
+
+			slog.
+				With("percent", format.Number(min(float64(elapsed)/float64(duration)*100, 100), 0)).
+				With("elapsed", format.Duration(elapsed)).
+				With("remaining", format.Duration(remaining)).
+				With("eta", time.Now().Add(remaining).Format(time.TimeOnly)).
+				With("samples", samples).
+				Info("benchmark progress")
+		}
+	}
+}
+
+// progressInterval logs about ten times per run, at most every minute
+func progressInterval(duration time.Duration) time.Duration {
+	return min(max(duration/10, 10*time.Second), time.Minute)
+}
+
+// RenderInterim writes a report of the aggregates so far of every session without ending the run,
+// a checkpoint of very long runs
+func RenderInterim(out io.Writer, services []*Service) {
+	var interim report.Sink = report.New(out)
+	if len(services) > 1 {
+		interim = report.NewMerged(out)
+	}
+	interim = redacted(interim)
+	for _, s := range services {
+		for _, record := range s.records() {
+			interim.AddRecord(record)
+		}
+	}
+	for _, record := range append(availabilityRecords(time.Now()), securityRecords...) {
+		interim.AddRecord(record)
+	}
+
+	fmt.Fprintf(out, "Interim report at %s\n", format.Timestamp(time.Now()))
+	interim.Render()
+}
+
+// metricStates lists the metrics of every session
+func metricStates(services []*Service) []route.MetricState {
+	states := []route.MetricState{}
+	for _, s := range services {
+		states = append(states, s.MetricStates()...)
+	}
+	return states
+}
+
+// setMetricEnabled switches the metric on or off in every session which has it
+func setMetricEnabled(services []*Service, group, name string, enabled bool) ([]route.MetricState, error) {
+	var states []route.MetricState
+	for _, s := range services {
+		switched, err := s.SetEnabled(group, name, enabled)
+		if errors.Is(err, route.ErrMetricNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		states = append(states, switched...)
+	}
+	if len(states) == 0 {
+		return nil, route.ErrMetricNotFound
+	}
+	return states, nil
+}
+
+// availabilityRecords describes the uptime and outage windows of every endpoint contacted during the run
+// and of every metric whose measurements failed for a while
+func availabilityRecords(until time.Time) []report.Record {
+	var records []report.Record
+	for _, b := range breaker.All() {
+		records = append(records, availabilityRecord(b.Target(), until))
+	}
+	for _, target := range availability.Targets() {
+		if len(target.Windows) == 0 {
+			continue
+		}
+		records = append(records, availabilityRecord(target, until))
+	}
+	return records
+}
+
+func availabilityRecord(target availability.Target, until time.Time) report.Record {
+	uptime := target.Uptime(until)
+
+	lines := []string{fmt.Sprintf("uptime=%s %%, outages=%d", format.Number(uptime, 2), len(target.Windows))}
+	for _, window := range target.Windows {
+		end, ongoing := window.End, ""
+		if end.IsZero() {
+			end, ongoing = until, ", ongoing"
+		}
+		lines = append(lines, fmt.Sprintf("%s - %s (%s%s)",
+			format.Clock(window.Start), format.Clock(end), format.Duration(end.Sub(window.Start)), ongoing))
+	}
+
+	health, severity := metric.Healthy, map[string]metric.SeverityLevel{}
+	switch {
+	case uptime < 99:
+		health, severity["Uptime"] = metric.Unhealthy, metric.SeverityHigh
+	case len(target.Windows) > 0:
+		health, severity["Uptime"] = metric.Unhealthy, metric.SeverityMedium
+	}
+
+	return report.Record{
+		GroupName:  metric.AvailabilityGroup,
+		MetricName: target.Name,
+		Value:      strings.Join(lines, " \n "),
+		Health:     health,
+		Severity:   severity,
+	}
 }
@@ -0,0 +1,108 @@
+package benchmark
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/metric"
+)
+
+// panickingMetric panics on measuring, the other methods of the metric are not called
+type panickingMetric struct {
+	metricService
+}
+
+func (panickingMetric) Measure(context.Context) {
+	var values map[string]int
+	values["Peers"] = 1
+}
+
+// runnerMetric measures through the runner of its base like the collectors, its measurements panic. With spawn the
+// panic happens in a goroutine of the metric instead.
+type runnerMetric struct {
+	metric.Base[float64]
+	spawn bool
+}
+
+func (r *runnerMetric) Measure(ctx context.Context) {
+	if r.spawn {
+		metric.Go(ctx, r.Name, func() { r.measureOnce() })
+		return
+	}
+	r.Runner(time.Millisecond).Run(ctx, func(context.Context) { r.measureOnce() })
+}
+
+func (r *runnerMetric) measureOnce() {
+	var values map[string]float64
+	values["Peers"] = 1
+}
+
+func (r *runnerMetric) AggregateResults() string {
+	return ""
+}
+
+// measured starts measuring the metric like a running session
+func measured(ctx context.Context, m metricService) (*Service, measuring) {
+	s := &Service{ctx: ctx, restarts: make(map[string]*MetricRestart), measuring: make(map[metricService]measuring)}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.measure(metric.ConsensusGroup, m)
+	return s, s.measuring[m]
+}
+
+func TestGivenRunnerMetricKeptPanickingWhenMeasuringThenRestartsRecordedAndStopped(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	s, running := measured(ctx, &runnerMetric{Base: metric.Base[float64]{Name: "Peers"}})
+
+	select {
+	case <-running.done:
+	case <-ctx.Done():
+		require.Fail(t, "metric kept measuring")
+	}
+	restarts := s.MetricRestarts()
+	require.Len(t, restarts, 1)
+	assert.Equal(t, maxMetricRestarts, restarts[0].Restarts)
+	assert.True(t, restarts[0].Stopped)
+	assert.Contains(t, restarts[0].Panic, "nil map")
+}
+
+func TestGivenPanicInGoroutineOfMetricWhenMeasuringThenRestartRecorded(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	s, _ := measured(ctx, &runnerMetric{Base: metric.Base[float64]{Name: "Attestation"}, spawn: true})
+
+	assert.Eventually(t, func() bool {
+		restarts := s.MetricRestarts()
+		return len(restarts) == 1 && restarts[0].Restarts == 1 && !restarts[0].Stopped
+	}, time.Second, time.Millisecond*10)
+}
+
+func TestGivenPanickingMetricWhenMeasureRecoveringThenPanicReturned(t *testing.T) {
+	recovered, stack := measureRecovering(context.Background(), panickingMetric{})
+
+	assert.Contains(t, fmt.Sprint(recovered), "nil map")
+	assert.Contains(t, string(stack), "panickingMetric")
+}
+
+func TestGivenMetricKeptPanickingWhenRecordRestartThenStopped(t *testing.T) {
+	s := &Service{restarts: make(map[string]*MetricRestart)}
+
+	for i := 1; i <= maxMetricRestarts; i++ {
+		restart := s.recordRestart(metric.ConsensusGroup, "Peers", "nil map")
+		assert.Equal(t, i, restart.Restarts)
+		assert.False(t, restart.Stopped)
+	}
+	restart := s.recordRestart(metric.ConsensusGroup, "Peers", "nil map")
+
+	assert.True(t, restart.Stopped)
+	assert.Equal(t, []MetricRestart{{Group: "Consensus", Metric: "Peers", Restarts: maxMetricRestarts, Panic: "nil map", Stopped: true}}, s.MetricRestarts())
+}
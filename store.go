@@ -0,0 +1,81 @@
+package benchmark
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/Harikakasimahanthi/benchmark-test/configs"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/run"
+)
+
+const dryRunFlag = "dry-run"
+
+// StoreCMD loads the configuration like the benchmark, the stored runs are found in its artifacts directory
+var StoreCMD = &cobra.Command{
+	Use:   "store",
+	Short: "Manage the runs stored in the artifacts directory",
+}
+
+var StorePruneCMD = &cobra.Command{
+	Use:   "prune",
+	Short: "Remove the stored runs exceeding the retention, keeping the most recent runs",
+	RunE: func(cobraCMD *cobra.Command, args []string) error {
+		// The flags override the artifacts directory and the retention of the configuration
+		artifacts := configs.Values.Benchmark.Artifacts
+		flags := cobraCMD.Flags()
+		var err error
+		if flags.Changed(artifactsDirFlag) {
+			if artifacts.Dir, err = flags.GetString(artifactsDirFlag); err != nil {
+				return err
+			}
+		}
+		if flags.Changed(keepRunsFlag) {
+			if artifacts.KeepRuns, err = flags.GetInt(keepRunsFlag); err != nil {
+				return err
+			}
+		}
+		if flags.Changed(keepDaysFlag) {
+			if artifacts.KeepDays, err = flags.GetInt(keepDaysFlag); err != nil {
+				return err
+			}
+		}
+		dry, err := flags.GetBool(dryRunFlag)
+		if err != nil {
+			return err
+		}
+		if artifacts.Dir == "" {
+			return fmt.Errorf("no artifacts directory is configured, set --%s", artifactsDirFlag)
+		}
+		if artifacts.KeepRuns < 0 || artifacts.KeepDays < 0 {
+			return errors.New("retention should not be negative")
+		}
+		if artifacts.KeepRuns == 0 && artifacts.KeepDays == 0 {
+			return fmt.Errorf("either --%s or --%s is required unless the configuration sets a retention", keepRunsFlag, keepDaysFlag)
+		}
+
+		pruned, err := run.Prune(artifacts.Dir, artifacts.Retention(), "", dry)
+		verb := "removed"
+		if dry {
+			verb = "would remove"
+		}
+		for _, id := range pruned {
+			fmt.Fprintf(cobraCMD.OutOrStdout(), "%s run %s\n", verb, id)
+		}
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(cobraCMD.OutOrStdout(), "%s %d runs\n", verb, len(pruned))
+		return nil
+	},
+}
+
+func init() {
+	StorePruneCMD.Flags().String(artifactsDirFlag, defaultArtifactsDir, "Directory holding the stored runs, the configured one unless set")
+	StorePruneCMD.Flags().Int(keepRunsFlag, 0, "Number of most recent runs kept, 0 keeps all, the configured retention unless set")
+	StorePruneCMD.Flags().Int(keepDaysFlag, 0, "Days runs are kept, 0 keeps all, the configured retention unless set")
+	StorePruneCMD.Flags().Bool(dryRunFlag, false, "List the runs exceeding the retention without removing them")
+	StoreCMD.AddCommand(StorePruneCMD)
+	CMD.AddCommand(StoreCMD)
+}
@@ -0,0 +1,639 @@
+package mocknode
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/clock"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/network"
+)
+
+const (
+	ConsensusVersion = "Lighthouse/v5.3.0-mock/x86_64-linux"
+	ExecutionVersion = "Geth/v1.14.12-mock/linux-amd64/go1.23.4"
+
+	// FeeRecipient is the miner of every execution block and the account whose state is served
+	FeeRecipient = "0x388c818ca8b9251b393131c08a736a67ccb19297"
+
+	// RawTransaction is the encoding of every transaction asked for by hash
+	RawTransaction = "0x02f86b0180843b9aca00850c92a69c0082520894388c818ca8b9251b393131c08a736a67ccb1929780c0"
+
+	// ForkVersion is the only fork of the schedule, the node is always on it
+	ForkVersion = "0x05000000"
+
+	// syncCommitteeSize is the number of positions of a sync committee, all of them participate in every block
+	syncCommitteeSize = 512
+	// blobsPerBlock is the number of blobs every block commits to, their sidecars are always available
+	blobsPerBlock = 3
+	// balance of every validator in Gwei
+	balance = 32_000_000_000
+
+	// identityENR is a valid record of 127.0.0.1, the inbound metric parses the ENR of the node
+	identityENR    = "enr:-IS4QHCYrYZbAKWCBRlAy5zzaDZXJBGkcnh4MHcBFZntXNFrdvJjX04jRzjzCBOonrkTfj499SZuOh8R33Ls8RRcy5wBgmlkgnY0gmlwhH8AAAGJc2VjcDI1NmsxoQPKY0yuDUmstAHYpMa2_oxVtw0RW_QAdpzBQA8yWM0xOIN1ZHCCdl8"
+	identityPeerID = "16Uiu2HAmQ9WByeSsnxLb23Eyt9VVcVyaHnPbGtDwBdQPYQrdHU9H"
+
+	methodNotFoundCode = -32601
+)
+
+// Node serves a fake beacon node API, execution JSON-RPC and Prometheus endpoints on the head of the network, e.g. for
+// end-to-end tests and demos without a real node. Every response can be delayed and fail at random.
+type Node struct {
+	network       network.Name
+	spec          network.Spec
+	peers         uint32
+	inboundPeers  uint32
+	latency       time.Duration
+	jitter        time.Duration
+	errorRate     float64
+	blindedBlocks bool
+
+	random *rand.Rand
+	mutex  sync.Mutex
+
+	consensus *httptest.Server
+	execution *httptest.Server
+}
+
+// New creates the node, which has to be configured before it is started
+func New(networkName network.Name) *Node {
+	return &Node{
+		network:      networkName,
+		spec:         network.DefaultSpec(networkName),
+		peers:        50,
+		inboundPeers: 10,
+		random:       rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+func (n *Node) WithPeers(peers, inbound uint32) *Node {
+	n.peers = peers
+	n.inboundPeers = inbound
+	return n
+}
+
+// WithLatency delays every response by the latency plus a random share of the jitter
+func (n *Node) WithLatency(latency, jitter time.Duration) *Node {
+	n.latency = latency
+	n.jitter = jitter
+	return n
+}
+
+// WithErrorRate fails the share of requests, between 0 and 1, with '503 Service Unavailable'
+func (n *Node) WithErrorRate(rate float64) *Node {
+	n.errorRate = rate
+	return n
+}
+
+// WithBlindedBlocks produces blocks as if their payload was provided by a builder
+func (n *Node) WithBlindedBlocks(blinded bool) *Node {
+	n.blindedBlocks = blinded
+	return n
+}
+
+// Start serves the consensus and execution endpoints on local ports
+func (n *Node) Start() *Node {
+	n.consensus = httptest.NewServer(n.ConsensusHandler())
+	n.execution = httptest.NewServer(n.ExecutionHandler())
+	return n
+}
+
+func (n *Node) ConsensusURL() string {
+	return n.consensus.URL
+}
+
+func (n *Node) ExecutionURL() string {
+	return n.execution.URL
+}
+
+func (n *Node) Close() {
+	n.consensus.Close()
+	n.execution.Close()
+}
+
+// ConsensusHandler serves the beacon node API along with the Prometheus endpoint of the consensus client
+func (n *Node) ConsensusHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /eth/v1/node/health", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	mux.HandleFunc("GET /eth/v1/node/version", func(w http.ResponseWriter, r *http.Request) {
+		writeData(w, map[string]string{"version": ConsensusVersion})
+	})
+	mux.HandleFunc("GET /eth/v1/node/syncing", func(w http.ResponseWriter, r *http.Request) {
+		writeData(w, map[string]any{
+			"head_slot":     strconv.FormatUint(n.headSlot(), 10),
+			"sync_distance": "0",
+			"is_syncing":    false,
+			"is_optimistic": false,
+			"el_offline":    false,
+		})
+	})
+	mux.HandleFunc("GET /eth/v1/node/peer_count", func(w http.ResponseWriter, r *http.Request) {
+		writeData(w, map[string]string{
+			"disconnected":  "0",
+			"connecting":    "0",
+			"connected":     strconv.FormatUint(uint64(n.peers), 10),
+			"disconnecting": "0",
+		})
+	})
+	mux.HandleFunc("GET /eth/v1/node/peers", n.handlePeers)
+	mux.HandleFunc("GET /eth/v1/node/identity", func(w http.ResponseWriter, r *http.Request) {
+		writeData(w, map[string]any{
+			"peer_id":             identityPeerID,
+			"enr":                 identityENR,
+			"p2p_addresses":       []string{"/ip4/127.0.0.1/tcp/9000/p2p/" + identityPeerID},
+			"discovery_addresses": []string{"/ip4/127.0.0.1/udp/9000/p2p/" + identityPeerID},
+		})
+	})
+	mux.HandleFunc("GET /eth/v1/beacon/genesis", func(w http.ResponseWriter, r *http.Request) {
+		writeData(w, map[string]string{
+			"genesis_time":            strconv.FormatInt(network.Presets[n.network].GenesisTime.Unix(), 10),
+			"genesis_validators_root": network.Presets[n.network].GenesisValidatorsRoot,
+			"genesis_fork_version":    "0x00000000",
+		})
+	})
+	mux.HandleFunc("GET /eth/v1/config/spec", func(w http.ResponseWriter, r *http.Request) {
+		writeData(w, map[string]string{
+			"CONFIG_NAME":                      string(n.network),
+			"SECONDS_PER_SLOT":                 strconv.Itoa(int(n.spec.SlotDuration / time.Second)),
+			"SLOTS_PER_EPOCH":                  strconv.FormatUint(n.spec.SlotsPerEpoch, 10),
+			"EPOCHS_PER_SYNC_COMMITTEE_PERIOD": strconv.FormatUint(n.spec.EpochsPerSyncCommitteePeriod, 10),
+			"DEPOSIT_CHAIN_ID":                 strconv.FormatUint(n.spec.DepositChainID, 10),
+			"SYNC_COMMITTEE_SIZE":              strconv.Itoa(syncCommitteeSize),
+			// The node is on Electra from genesis, see ForkVersion
+			"ELECTRA_FORK_EPOCH": "0",
+			"FULU_FORK_EPOCH":    "18446744073709551615",
+		})
+	})
+	mux.HandleFunc("GET /eth/v1/config/fork_schedule", func(w http.ResponseWriter, r *http.Request) {
+		writeData(w, []map[string]string{fork()})
+	})
+	mux.HandleFunc("GET /eth/v1/beacon/states/head/fork", func(w http.ResponseWriter, r *http.Request) {
+		writeData(w, fork())
+	})
+	mux.HandleFunc("GET /eth/v1/beacon/states/head/validator_balances", func(w http.ResponseWriter, r *http.Request) {
+		balances := []map[string]string{}
+		for _, id := range strings.Split(r.URL.Query().Get("id"), ",") {
+			if id != "" {
+				balances = append(balances, map[string]string{"index": id, "balance": strconv.Itoa(balance)})
+			}
+		}
+		writeData(w, balances)
+	})
+	mux.HandleFunc("GET /eth/v1/beacon/states/head/sync_committees", func(w http.ResponseWriter, r *http.Request) {
+		validators := make([]string, 0, syncCommitteeSize)
+		for i := 0; i < syncCommitteeSize; i++ {
+			validators = append(validators, strconv.Itoa(i))
+		}
+		writeData(w, map[string]any{"validators": validators})
+	})
+	mux.HandleFunc("GET /eth/v2/beacon/blocks/{slot}", func(w http.ResponseWriter, r *http.Request) {
+		writeData(w, map[string]any{
+			"message": map[string]any{
+				"slot": r.PathValue("slot"),
+				"body": map[string]any{
+					"sync_aggregate": map[string]string{
+						"sync_committee_bits": "0x" + strings.Repeat("ff", syncCommitteeSize/8),
+					},
+				},
+			},
+		})
+	})
+	mux.HandleFunc("GET /eth/v1/beacon/states/head/finality_checkpoints", func(w http.ResponseWriter, r *http.Request) {
+		// The chain finalizes two epochs behind the head
+		epoch := n.spec.Epoch(n.headSlot())
+		if epoch >= 2 {
+			epoch -= 2
+		}
+		writeData(w, map[string]any{
+			"finalized": map[string]string{"epoch": strconv.FormatUint(epoch, 10), "root": blockRoot(n.spec.EpochStart(epoch))},
+		})
+	})
+	mux.HandleFunc("GET /eth/v1/beacon/blocks/{block_id}/root", func(w http.ResponseWriter, r *http.Request) {
+		// Every slot has a block, whose root encodes the slot
+		id := r.PathValue("block_id")
+		slot, err := strconv.ParseUint(strings.TrimPrefix(id, "0x"), 16, 64)
+		if !strings.HasPrefix(id, "0x") {
+			slot, err = strconv.ParseUint(id, 10, 64)
+		}
+		if id == "head" {
+			slot, err = n.headSlot(), nil
+		}
+		if err != nil || slot > n.headSlot() {
+			writeError(w, http.StatusNotFound, "block not found")
+			return
+		}
+		writeData(w, map[string]string{"root": blockRoot(slot)})
+	})
+	mux.HandleFunc("GET /eth/v1/beacon/blinded_blocks/{slot}", func(w http.ResponseWriter, r *http.Request) {
+		writeData(w, map[string]any{
+			"message": map[string]any{
+				"slot": r.PathValue("slot"),
+				"body": map[string]any{"blob_kzg_commitments": blobCommitments()},
+			},
+		})
+	})
+	mux.HandleFunc("GET /eth/v1/beacon/blob_sidecars/{slot}", func(w http.ResponseWriter, r *http.Request) {
+		sidecars := make([]map[string]string, 0, blobsPerBlock)
+		for i, commitment := range blobCommitments() {
+			sidecars = append(sidecars, map[string]string{"index": strconv.Itoa(i), "kzg_commitment": commitment})
+		}
+		writeData(w, sidecars)
+	})
+	mux.HandleFunc("GET /eth/v1/validator/attestation_data", func(w http.ResponseWriter, r *http.Request) {
+		slot, err := strconv.ParseUint(r.URL.Query().Get("slot"), 10, 64)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid slot")
+			return
+		}
+		writeData(w, attestationData(n.spec, slot))
+	})
+	mux.HandleFunc("GET /eth/v2/beacon/pool/attestations", func(w http.ResponseWriter, r *http.Request) {
+		slot, err := strconv.ParseUint(r.URL.Query().Get("slot"), 10, 64)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid slot")
+			return
+		}
+		writeData(w, []map[string]any{{
+			"aggregation_bits": "0x03",
+			"data":             attestationData(n.spec, slot),
+			"committee_bits":   "0x0100000000000000",
+			"signature":        "0x" + strings.Repeat("00", 96),
+		}})
+	})
+	mux.HandleFunc("POST /eth/v1/beacon/pool/attestations", func(w http.ResponseWriter, r *http.Request) {
+		// Attestations without attesters are rejected, like the dry-run of the duty simulation
+		writeError(w, http.StatusBadRequest, "attestation has no attesters")
+	})
+	mux.HandleFunc("POST /eth/v2/beacon/pool/attestations", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Eth-Consensus-Version") == "" {
+			writeError(w, http.StatusBadRequest, "missing Eth-Consensus-Version header")
+			return
+		}
+		// Attestations with the empty signature are rejected, like the dry-run of the duty simulation
+		writeError(w, http.StatusBadRequest, "invalid signature")
+	})
+	mux.HandleFunc("GET /eth/v2/validator/aggregate_attestation", func(w http.ResponseWriter, r *http.Request) {
+		slot, err := strconv.ParseUint(r.URL.Query().Get("slot"), 10, 64)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid slot")
+			return
+		}
+		writeData(w, map[string]any{
+			"aggregation_bits": "0x0f",
+			"data":             attestationData(n.spec, slot),
+			"committee_bits":   "0x0100000000000000",
+			"signature":        "0x" + strings.Repeat("00", 96),
+		})
+	})
+	mux.HandleFunc("POST /eth/v1/validator/duties/attester/{epoch}", func(w http.ResponseWriter, r *http.Request) {
+		epoch, err := strconv.ParseUint(r.PathValue("epoch"), 10, 64)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid epoch")
+			return
+		}
+		writeJSON(w, map[string]any{
+			"dependent_root":       blockRoot(n.spec.EpochStart(epoch)),
+			"execution_optimistic": false,
+			"data":                 []any{},
+		})
+	})
+	mux.HandleFunc("GET /eth/v3/validator/blocks/{slot}", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Eth-Consensus-Version", "electra")
+		w.Header().Set("Eth-Execution-Payload-Blinded", strconv.FormatBool(n.blindedBlocks))
+		writeJSON(w, map[string]any{
+			"version":                   "electra",
+			"execution_payload_blinded": n.blindedBlocks,
+			"data":                      map[string]string{"slot": r.PathValue("slot")},
+		})
+	})
+	mux.HandleFunc("GET /eth/v1/events", n.handleEvents)
+	mux.HandleFunc("GET /metrics", func(w http.ResponseWriter, r *http.Request) {
+		writeGauge(w, "libp2p_peers", "Number of connected peers", n.peers)
+	})
+	return n.inject(mux)
+}
+
+// ExecutionHandler serves the JSON-RPC and Engine API of the execution client on '/' along with its Prometheus endpoint
+// on '/debug/metrics/prometheus', as geth does
+func (n *Node) ExecutionHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /", n.handleRPC)
+	mux.HandleFunc("GET /debug/metrics/prometheus", func(w http.ResponseWriter, r *http.Request) {
+		writeGauge(w, "p2p_peers", "Number of connected peers", n.peers)
+		n.writeRuntime(w)
+	})
+	return n.inject(mux)
+}
+
+// inject delays the request and fails it at the configured rate before it is served
+func (n *Node) inject(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		delay, fail := n.fault()
+		if delay > 0 {
+			select {
+			case <-time.After(delay):
+			case <-r.Context().Done():
+				return
+			}
+		}
+		if fail {
+			writeError(w, http.StatusServiceUnavailable, "injected error")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (n *Node) fault() (time.Duration, bool) {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+
+	delay := n.latency
+	if n.jitter > 0 {
+		delay += time.Duration(n.random.Int63n(int64(n.jitter)))
+	}
+	return delay, n.errorRate > 0 && n.random.Float64() < n.errorRate
+}
+
+func (n *Node) headSlot() uint64 {
+	return n.spec.SlotAt(clock.Now())
+}
+
+func (n *Node) handlePeers(w http.ResponseWriter, r *http.Request) {
+	direction := r.URL.Query().Get("direction")
+	peers := []map[string]string{}
+	for i := uint32(0); i < n.peers; i++ {
+		peerDirection := "outbound"
+		if i < n.inboundPeers {
+			peerDirection = "inbound"
+		}
+		if direction != "" && direction != peerDirection {
+			continue
+		}
+		peers = append(peers, map[string]string{
+			"peer_id":   fmt.Sprintf("16Uiu2HAmMockPeer%04d", i),
+			"state":     "connected",
+			"direction": peerDirection,
+		})
+	}
+	writeJSON(w, map[string]any{
+		"data": peers,
+		"meta": map[string]int{"count": len(peers)},
+	})
+}
+
+// handleEvents streams a head event at the start of every slot
+func (n *Node) handleEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, http.StatusInternalServerError, "streaming is not supported")
+		return
+	}
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	if !strings.Contains(r.URL.Query().Get("topics"), "head") {
+		<-r.Context().Done()
+		return
+	}
+	for {
+		slot := n.headSlot() + 1
+		select {
+		case <-r.Context().Done():
+			return
+		case <-time.After(clock.Until(n.spec.SlotTime(slot))):
+		}
+
+		data, _ := json.Marshal(map[string]any{
+			"slot":                         strconv.FormatUint(slot, 10),
+			"block":                        blockRoot(slot),
+			"state":                        blockRoot(slot),
+			"epoch_transition":             slot%n.spec.SlotsPerEpoch == 0,
+			"previous_duty_dependent_root": blockRoot(slot - slot%n.spec.SlotsPerEpoch),
+			"current_duty_dependent_root":  blockRoot(slot - slot%n.spec.SlotsPerEpoch),
+			"execution_optimistic":         false,
+		})
+		if _, err := fmt.Fprintf(w, "event: head\ndata: %s\n\n", data); err != nil {
+			return
+		}
+		flusher.Flush()
+	}
+}
+
+type (
+	rpcRequest struct {
+		Method string            `json:"method"`
+		Params []json.RawMessage `json:"params"`
+		ID     json.RawMessage   `json:"id"`
+	}
+
+	rpcError struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	}
+)
+
+// handleRPC answers a single request or a batch of requests
+func (n *Node) handleRPC(w http.ResponseWriter, r *http.Request) {
+	var body json.RawMessage
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeJSON(w, parseError())
+		return
+	}
+
+	var batch []rpcRequest
+	if err := json.Unmarshal(body, &batch); err == nil {
+		responses := make([]map[string]any, 0, len(batch))
+		for _, request := range batch {
+			responses = append(responses, n.respond(request))
+		}
+		writeJSON(w, responses)
+		return
+	}
+	var request rpcRequest
+	if err := json.Unmarshal(body, &request); err != nil {
+		writeJSON(w, parseError())
+		return
+	}
+	writeJSON(w, n.respond(request))
+}
+
+func (n *Node) respond(request rpcRequest) map[string]any {
+	response := map[string]any{
+		"jsonrpc": "2.0",
+		"id":      request.ID,
+	}
+	result, err := n.call(request)
+	if err != nil {
+		response["error"] = err
+	} else {
+		response["result"] = result
+	}
+	return response
+}
+
+func parseError() map[string]any {
+	return map[string]any{
+		"jsonrpc": "2.0",
+		"id":      nil,
+		"error":   rpcError{Code: -32700, Message: "parse error"},
+	}
+}
+
+func (n *Node) call(request rpcRequest) (any, *rpcError) {
+	blockNumber := n.headSlot()
+	switch request.Method {
+	case "web3_clientVersion":
+		return ExecutionVersion, nil
+	case "net_peerCount":
+		return fmt.Sprintf("0x%x", n.peers), nil
+	case "admin_peers":
+		peers := make([]map[string]any, 0, n.peers)
+		for i := uint32(0); i < n.peers; i++ {
+			peers = append(peers, map[string]any{
+				"id":      fmt.Sprintf("%064x", i),
+				"caps":    []string{"eth/68", "snap/1"},
+				"network": map[string]bool{"inbound": i < n.inboundPeers, "static": false},
+			})
+		}
+		return peers, nil
+	case "admin_nodeInfo":
+		return map[string]any{
+			"enode": fmt.Sprintf("enode://%0128x@127.0.0.1:30303", 1),
+			"ip":    "127.0.0.1",
+			"ports": map[string]uint16{"discovery": 30303, "listener": 30303},
+		}, nil
+	case "eth_syncing":
+		return false, nil
+	case "eth_blockNumber":
+		return fmt.Sprintf("0x%x", blockNumber), nil
+	case "eth_getBlockByNumber":
+		// Blocks have no transactions, so asking for them with the block or not returns the same
+		return map[string]any{
+			"number":                fmt.Sprintf("0x%x", blockNumber),
+			"hash":                  blockRoot(blockNumber),
+			"parentHash":            blockRoot(blockNumber - 1),
+			"timestamp":             fmt.Sprintf("0x%x", clock.Now().Unix()),
+			"miner":                 FeeRecipient,
+			"transactions":          []string{},
+			"withdrawals":           []map[string]string{},
+			"parentBeaconBlockRoot": blockRoot(blockNumber - 1),
+		}, nil
+	case "eth_getRawTransactionByHash":
+		return RawTransaction, nil
+	case "eth_getProof":
+		return map[string]any{
+			"address":      FeeRecipient,
+			"balance":      "0x0",
+			"nonce":        "0x0",
+			"accountProof": []string{},
+			"storageProof": []map[string]any{{"key": "0x0", "value": "0x0", "proof": []string{}}},
+		}, nil
+	case "eth_call":
+		return "0x", nil
+	case "engine_exchangeCapabilities":
+		// Every capability the consensus client asks for is supported
+		var capabilities []string
+		if len(request.Params) != 0 {
+			_ = json.Unmarshal(request.Params[0], &capabilities)
+		}
+		return capabilities, nil
+	case "engine_newPayloadV3", "engine_newPayloadV4":
+		// Every payload is known and valid
+		var payload struct {
+			BlockHash string `json:"blockHash"`
+		}
+		if len(request.Params) != 0 {
+			_ = json.Unmarshal(request.Params[0], &payload)
+		}
+		return map[string]any{
+			"status":          "VALID",
+			"latestValidHash": payload.BlockHash,
+			"validationError": nil,
+		}, nil
+	default:
+		return nil, &rpcError{Code: methodNotFoundCode, Message: fmt.Sprintf("the method %s does not exist/is not available", request.Method)}
+	}
+}
+
+func blobCommitments() []string {
+	commitments := make([]string, 0, blobsPerBlock)
+	for i := 0; i < blobsPerBlock; i++ {
+		commitments = append(commitments, fmt.Sprintf("0x%096x", i+1))
+	}
+	return commitments
+}
+
+func fork() map[string]string {
+	return map[string]string{
+		"previous_version": ForkVersion,
+		"current_version":  ForkVersion,
+		"epoch":            "0",
+	}
+}
+
+func attestationData(spec network.Spec, slot uint64) map[string]any {
+	epoch := spec.Epoch(slot)
+	return map[string]any{
+		"slot":              strconv.FormatUint(slot, 10),
+		"index":             "0",
+		"beacon_block_root": blockRoot(slot),
+		"source": map[string]string{
+			"epoch": strconv.FormatUint(max(epoch, 1)-1, 10),
+			"root":  blockRoot(spec.EpochStart(max(epoch, 1) - 1)),
+		},
+		"target": map[string]string{
+			"epoch": strconv.FormatUint(epoch, 10),
+			"root":  blockRoot(spec.EpochStart(epoch)),
+		},
+	}
+}
+
+// blockRoot is the made up root of the block of the slot, the same slot always gets the same root
+func blockRoot(slot uint64) string {
+	return fmt.Sprintf("0x%064x", slot)
+}
+
+func writeData(w http.ResponseWriter, data any) {
+	writeJSON(w, map[string]any{"data": data})
+}
+
+func writeJSON(w http.ResponseWriter, value any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(value)
+}
+
+func writeError(w http.ResponseWriter, code int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(map[string]any{"code": code, "message": message})
+}
+
+func writeGauge(w http.ResponseWriter, name, help string, value uint32) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s gauge\n%s %d\n", name, help, name, name, value)
+}
+
+// writeRuntime writes Go runtime stats named as by geth, the heap grows and shrinks with the slots
+func (n *Node) writeRuntime(w http.ResponseWriter) {
+	n.mutex.Lock()
+	goroutines := 400 + n.random.Intn(50)
+	pause := time.Duration(n.random.Int63n(int64(time.Millisecond * 20)))
+	n.mutex.Unlock()
+
+	heap := (2 + n.headSlot()%8) * 256 * 1024 * 1024
+	fmt.Fprintf(w, "# TYPE system_cpu_goroutines gauge\nsystem_cpu_goroutines %d\n", goroutines)
+	fmt.Fprintf(w, "# TYPE system_memory_used gauge\nsystem_memory_used %d\n", heap)
+	fmt.Fprintf(w, "# TYPE system_memory_pauses summary\nsystem_memory_pauses{quantile=\"0.5\"} %d\nsystem_memory_pauses{quantile=\"0.99\"} %d\n",
+		pause/2, pause)
+}
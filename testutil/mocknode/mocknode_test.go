@@ -0,0 +1,128 @@
+package mocknode
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/clientinfo"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/network"
+	"github.com/Harikakasimahanthi/benchmark-test/metrics/consensus"
+)
+
+func TestGivenMockNodeWhenClientsQueriedThenNodeIsDetectedOnNetwork(t *testing.T) {
+	node := New(network.Mainnet).Start()
+	defer node.Close()
+
+	consensusVersion, err := clientinfo.ConsensusVersion(context.Background(), node.ConsensusURL())
+	require.NoError(t, err)
+	assert.Equal(t, clientinfo.Lighthouse, clientinfo.ParseClient(consensusVersion))
+
+	executionVersion, err := clientinfo.ExecutionVersion(context.Background(), node.ExecutionURL())
+	require.NoError(t, err)
+	assert.Equal(t, clientinfo.Geth, clientinfo.ParseClient(executionVersion))
+
+	identity, err := consensus.FetchIdentity(context.Background(), node.ConsensusURL())
+	require.NoError(t, err)
+	assert.NoError(t, identity.VerifyNetwork(network.Mainnet))
+	assert.Error(t, identity.VerifyNetwork(network.Holesky))
+	assert.Equal(t, ForkVersion, identity.HeadFork.CurrentVersion)
+}
+
+func TestGivenErrorRateWhenRequestedThenFailsWithServiceUnavailable(t *testing.T) {
+	node := New(network.Mainnet).WithErrorRate(1).Start()
+	defer node.Close()
+
+	res, err := http.Get(node.ConsensusURL() + "/eth/v1/node/health")
+	require.NoError(t, err)
+	defer res.Body.Close()
+
+	assert.Equal(t, http.StatusServiceUnavailable, res.StatusCode)
+}
+
+func TestGivenLatencyWhenRequestedThenResponseIsDelayed(t *testing.T) {
+	node := New(network.Mainnet).WithLatency(time.Millisecond*50, 0).Start()
+	defer node.Close()
+
+	start := time.Now()
+	res, err := http.Get(node.ConsensusURL() + "/eth/v1/node/health")
+	require.NoError(t, err)
+	defer res.Body.Close()
+
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.GreaterOrEqual(t, time.Since(start), time.Millisecond*50)
+}
+
+func TestGivenUnknownMethodWhenCalledThenReturnsMethodNotFound(t *testing.T) {
+	node := New(network.Mainnet).Start()
+	defer node.Close()
+
+	response, err := node.call(rpcRequest{Method: "eth_unknown"})
+	assert.Nil(t, response)
+	require.NotNil(t, err)
+	assert.Equal(t, methodNotFoundCode, err.Code)
+}
+
+func TestGivenBatchWhenCalledThenEveryRequestAnswered(t *testing.T) {
+	node := New(network.Mainnet).Start()
+	defer node.Close()
+
+	res, err := http.Post(node.ExecutionURL(), "application/json", strings.NewReader(
+		`[{"jsonrpc":"2.0","method":"net_peerCount","params":[],"id":1},{"jsonrpc":"2.0","method":"eth_unknown","params":[],"id":2}]`))
+	require.NoError(t, err)
+	defer res.Body.Close()
+
+	var responses []struct {
+		ID     int       `json:"id"`
+		Result string    `json:"result"`
+		Error  *rpcError `json:"error"`
+	}
+	require.NoError(t, json.NewDecoder(res.Body).Decode(&responses))
+	require.Len(t, responses, 2)
+	assert.Equal(t, 1, responses[0].ID)
+	assert.NotEmpty(t, responses[0].Result)
+	assert.Equal(t, 2, responses[1].ID)
+	require.NotNil(t, responses[1].Error)
+	assert.Equal(t, methodNotFoundCode, responses[1].Error.Code)
+}
+
+func TestGivenMockNodeWhenSpecFetchedThenTimingOfNetwork(t *testing.T) {
+	node := New(network.Mainnet).Start()
+	defer node.Close()
+
+	spec, err := consensus.FetchSpec(context.Background(), node.ConsensusURL(), network.Spec{})
+	require.NoError(t, err)
+
+	assert.Equal(t, network.DefaultSpec(network.Mainnet), spec)
+}
+
+func TestGivenNodesOnSameChainWhenVerifyCheckpointThenMatch(t *testing.T) {
+	node := New(network.Mainnet).Start()
+	defer node.Close()
+	provider := New(network.Mainnet).Start()
+	defer provider.Close()
+
+	verification, err := consensus.VerifyCheckpoint(context.Background(), node.ConsensusURL(), provider.ConsensusURL())
+	require.NoError(t, err)
+
+	assert.True(t, verification.Match, verification.Detail)
+}
+
+func TestGivenNodeOnOtherNetworkWhenVerifyCheckpointThenMismatch(t *testing.T) {
+	node := New(network.Holesky).Start()
+	defer node.Close()
+	provider := New(network.Mainnet).Start()
+	defer provider.Close()
+
+	verification, err := consensus.VerifyCheckpoint(context.Background(), node.ConsensusURL(), provider.ConsensusURL())
+	require.NoError(t, err)
+
+	assert.False(t, verification.Match)
+	assert.Contains(t, verification.Detail, "genesis validators root")
+}
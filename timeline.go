@@ -0,0 +1,134 @@
+package benchmark
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/annotation"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/format"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/metric"
+	"github.com/Harikakasimahanthi/benchmark-test/report"
+)
+
+const (
+	// timelineBucket is the time span of a block of the timeline, runs shorter than shortRun get blocks of a minute
+	timelineBucket = 5 * time.Minute
+	shortRun       = 30 * time.Minute
+	// timelineBlocks is the most blocks of a timeline, longer runs get wider blocks
+	timelineBlocks = 96
+	// timelineRow is the number of blocks rendered per line of the report
+	timelineRow = 12
+
+	healthyBlock   = "✅"
+	unhealthyBlock = "⚠️"
+	highBlock      = "❌"
+	noDataBlock    = "·"
+
+	annotationsRecord = "Annotations"
+)
+
+// timelineRecords renders the health of every metric per time bucket of the run, so intermittent issues show up
+// instead of being averaged away by the evaluation of the whole run
+func timelineRecords(session string, metrics map[metric.Group][]metricService, start, end time.Time) []report.Record {
+	bucket := timelineBucketOf(end.Sub(start))
+	if bucket == 0 {
+		return nil
+	}
+
+	groups := make([]metric.Group, 0, len(metrics))
+	for group := range metrics {
+		groups = append(groups, group)
+	}
+	sort.Slice(groups, func(i, j int) bool { return groups[i] < groups[j] })
+
+	var records []report.Record
+	for _, group := range groups {
+		for _, m := range metrics[group] {
+			var blocks []string
+			transitions, previous := 0, ""
+			for from := start; from.Before(end); from = from.Add(bucket) {
+				block := timelineBlock(m.EvaluateBetween(from, from.Add(bucket)))
+				if block != noDataBlock {
+					if previous != "" && block != previous {
+						transitions++
+					}
+					previous = block
+				}
+				blocks = append(blocks, block)
+			}
+
+			lines := []string{fmt.Sprintf("from %s, %s per block", format.Clock(start), format.Duration(bucket))}
+			for i := 0; i < len(blocks); i += timelineRow {
+				lines = append(lines, strings.Join(blocks[i:min(i+timelineRow, len(blocks))], ""))
+			}
+			lines = append(lines, fmt.Sprintf("transitions=%d", transitions))
+
+			evaluation := m.EvaluateMetric()
+			records = append(records, report.Record{
+				Session:    session,
+				GroupName:  metric.TimelineGroup,
+				MetricName: fmt.Sprintf("%s %s", group, m.GetName()),
+				Value:      strings.Join(lines, " \n "),
+				Health:     evaluation.Health,
+				Severity:   evaluation.Severities,
+			})
+		}
+	}
+	return records
+}
+
+// annotationRecord lists the annotations of the run with the block of the timeline they fall into, so changes of the
+// health can be correlated with them
+func annotationRecord(session string, annotations []annotation.Annotation, start, end time.Time) (report.Record, bool) {
+	bucket := timelineBucketOf(end.Sub(start))
+	if bucket == 0 || len(annotations) == 0 {
+		return report.Record{}, false
+	}
+
+	lines := make([]string, 0, len(annotations))
+	for _, a := range annotations {
+		block := int(a.Time.Sub(start)/bucket) + 1
+		lines = append(lines, fmt.Sprintf("block %d, %s (%s): %s", block, format.Clock(a.Time), a.Source, a.Text))
+	}
+	return report.Record{
+		Session:    session,
+		GroupName:  metric.TimelineGroup,
+		MetricName: annotationsRecord,
+		Value:      strings.Join(lines, " \n "),
+		Health:     metric.Healthy,
+		Severity:   map[string]metric.SeverityLevel{},
+	}, true
+}
+
+// timelineBucketOf returns the time span of a block for a run of the duration, none for runs without duration
+func timelineBucketOf(duration time.Duration) time.Duration {
+	switch {
+	case duration <= 0:
+		return 0
+	case duration < shortRun:
+		return time.Minute
+	}
+	bucket := timelineBucket
+	for duration > bucket*timelineBlocks {
+		bucket += timelineBucket
+	}
+	return bucket
+}
+
+// timelineBlock renders the health of a bucket, data points without any of the measured names leave it empty
+func timelineBlock(evaluation metric.Evaluation) string {
+	if len(evaluation.Severities) == 0 {
+		return noDataBlock
+	}
+	if evaluation.Health == metric.Healthy {
+		return healthyBlock
+	}
+	for _, severity := range evaluation.Severities {
+		if metric.CompareSeverities(severity, metric.SeverityHigh) >= 0 {
+			return highBlock
+		}
+	}
+	return unhealthyBlock
+}
@@ -0,0 +1,67 @@
+package benchmark
+
+import (
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+
+	"github.com/Harikakasimahanthi/benchmark-test/configs"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/metric"
+)
+
+// trendingMetric is implemented by metrics which evaluate conditions on the change of their measurements
+type trendingMetric interface {
+	AddTrends(conditions ...metric.TrendCondition)
+}
+
+var trendOperators = []metric.Operator{
+	metric.OperatorGreaterThan,
+	metric.OperatorLessThan,
+	metric.OperatorGreaterThanOrEqual,
+	metric.OperatorLessThanOrEqual,
+}
+
+// addTrends adds the configured trend conditions to the metrics they name. Like chaos faults, trends which match no
+// enabled metric fail, they would never be evaluated.
+func addTrends(metrics map[metric.Group][]metricService, trends []configs.Trend) error {
+	for _, trend := range trends {
+		condition := metric.TrendCondition{
+			Name:      trend.Measurement,
+			Window:    trend.Window,
+			Threshold: trend.Change,
+			Relative:  trend.Percent,
+			Operator:  metric.Operator(trend.Operator),
+			Severity:  metric.SeverityLevel(trend.Severity),
+		}
+		if !slices.Contains(trendOperators, condition.Operator) {
+			return fmt.Errorf("trend of '%s' should have one of the operators '>', '<', '>=' or '<=', got '%s'", trend.Measurement, trend.Operator)
+		}
+
+		matched := false
+		for group, groupMetrics := range metrics {
+			if !strings.EqualFold(string(group), trend.Group) {
+				continue
+			}
+			for _, m := range groupMetrics {
+				trending, ok := m.(trendingMetric)
+				if !ok || !strings.EqualFold(m.GetName(), trend.Metric) {
+					continue
+				}
+				trending.AddTrends(condition)
+				matched = true
+			}
+		}
+		if !matched {
+			return fmt.Errorf("trend targets metric '%s' of group '%s' which is not enabled", trend.Metric, trend.Group)
+		}
+
+		slog.
+			With("group", trend.Group).
+			With("metric_name", trend.Metric).
+			With("measurement_name", trend.Measurement).
+			With("window", trend.Window).
+			Debug("trend condition added")
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+package benchmark
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/update"
+	"github.com/Harikakasimahanthi/benchmark-test/report"
+)
+
+const (
+	selfUpdateCheckFlag = "check"
+	updateCheckTimeout  = time.Second * 10
+)
+
+var SelfUpdateCMD = &cobra.Command{
+	Use:   "self-update",
+	Short: "Replace the benchmark binary with the latest GitHub release",
+	// Updating must work while the configuration is invalid or missing
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		return nil
+	},
+	RunE: func(cobraCMD *cobra.Command, args []string) error {
+		checkOnly, err := cobraCMD.Flags().GetBool(selfUpdateCheckFlag)
+		if err != nil {
+			return err
+		}
+
+		ctx, cancel := context.WithTimeout(cobraCMD.Context(), time.Minute*5)
+		defer cancel()
+
+		current := cobraCMD.Root().Version
+		updater := update.New(update.Repository)
+		release, err := updater.Latest(ctx)
+		if err != nil {
+			return err
+		}
+		out := cobraCMD.OutOrStdout()
+		if !update.Newer(release.Version, current) {
+			fmt.Fprintf(out, "version %s is up to date, the latest release is %s\n", current, release.Version)
+			return nil
+		}
+		if checkOnly {
+			fmt.Fprintf(out, "version %s is available, running %s: %s\n", release.Version, current, release.URL)
+			return nil
+		}
+
+		executable, err := os.Executable()
+		if err != nil {
+			return errors.Join(err, errors.New("failed locating the running binary"))
+		}
+		if executable, err = filepath.EvalSymlinks(executable); err != nil {
+			return errors.Join(err, errors.New("failed resolving the running binary"))
+		}
+		if err := updater.Install(ctx, release, executable); err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "updated %s from %s to %s\n", executable, current, release.Version)
+		return nil
+	},
+}
+
+func init() {
+	SelfUpdateCMD.Flags().Bool(selfUpdateCheckFlag, false, "Only tell whether a newer release is available")
+	CMD.AddCommand(SelfUpdateCMD)
+}
+
+// checkForUpdate notes a newer release in the report footer, the check never fails the run
+func checkForUpdate(current string) {
+	ctx, cancel := context.WithTimeout(context.Background(), updateCheckTimeout)
+	defer cancel()
+
+	release, err := update.New(update.Repository).Latest(ctx)
+	if err != nil {
+		slog.With("err", err.Error()).Warn("failed checking for a newer version")
+		return
+	}
+	if !update.Newer(release.Version, current) {
+		slog.With("version", current).Debug("running the latest version")
+		return
+	}
+
+	slog.With("version", current).With("latest", release.Version).Info("a newer version is available")
+	report.AddFooter(fmt.Sprintf("Version %s is available, this run used %s. Update with 'benchmark self-update' or download it from %s",
+		release.Version, current, release.URL))
+}
@@ -0,0 +1,278 @@
+package benchmark
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/Harikakasimahanthi/benchmark-test/configs"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/alert"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/annotation"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/format"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/metric"
+	"github.com/Harikakasimahanthi/benchmark-test/results"
+)
+
+const (
+	watchBaselineFlag = "baseline"
+	watchSigmaFlag    = "sigma"
+
+	defaultWatchSigma = 3
+	// watchCheckInterval is how often the latest readings are compared with the baseline
+	watchCheckInterval = time.Second * 30
+	// watchSource marks the annotations of readings deviating from the baseline
+	watchSource = "watch"
+)
+
+var WatchCMD = &cobra.Command{
+	Use:   "watch --baseline <run-id>",
+	Short: "Run the benchmark comparing the measurements with a stored baseline run, e.g. right after a client upgrade, and alert on deviations",
+	RunE: func(cobraCMD *cobra.Command, args []string) error {
+		baseline, err := cobraCMD.Flags().GetString(watchBaselineFlag)
+		if err != nil {
+			return err
+		}
+		sigma, err := cobraCMD.Flags().GetFloat64(watchSigmaFlag)
+		if err != nil {
+			return err
+		}
+
+		configs.Values.Benchmark.Watch = configs.Watch{Baseline: baseline, Sigma: sigma}
+		// Watching lasts until it is stopped, unless a duration is configured
+		if !viper.IsSet(configs.BenchmarkPrefix + ".duration") {
+			configs.Values.Benchmark.Duration = 0
+		}
+		return CMD.RunE(cobraCMD, args)
+	},
+}
+
+func init() {
+	// The flags of the benchmark are shared, so they stay bound to the configuration keys
+	WatchCMD.Flags().AddFlagSet(CMD.Flags())
+	WatchCMD.Flags().String(watchBaselineFlag, "", "Run ID in the artifacts directory, or directory, of the baseline run")
+	WatchCMD.Flags().Float64(watchSigmaFlag, defaultWatchSigma, "Standard deviations from the baseline mean beyond which a reading alerts")
+	_ = WatchCMD.MarkFlagRequired(watchBaselineFlag)
+	CMD.AddCommand(WatchCMD)
+}
+
+// watch compares the readings of every session with those of the baseline session of the same name. A measurement
+// deviates when the mean of its readings since the previous check lies more than sigma standard deviations of the
+// baseline readings away from the baseline mean. Deviations are logged, marked on the timeline and sent to the alerting
+// services, once when they start and once when the readings are back within the baseline.
+type watch struct {
+	baseline    *results.Run
+	baselineID  string
+	sigma       float64
+	notifiers   []alert.Notifier
+	annotations *annotation.Store
+	// deviating holds the measurements deviating from the baseline, by alert key
+	deviating map[string]bool
+	checked   time.Time
+}
+
+func newWatch(config configs.Benchmark, annotations *annotation.Store) (*watch, error) {
+	dir := runDir(config.Artifacts.Dir, config.Watch.Baseline)
+	baseline, err := results.Load(dir)
+	if err != nil {
+		return nil, errors.Join(err, fmt.Errorf("failed loading baseline run '%s'", config.Watch.Baseline))
+	}
+	slog.With("baseline", dir).With("sigma", config.Watch.Sigma).Info("watching the measurements for deviations from the baseline")
+
+	return &watch{
+		baseline:    baseline,
+		baselineID:  filepath.Base(dir),
+		sigma:       config.Watch.Sigma,
+		notifiers:   newNotifiers(config.Export.Alerting),
+		annotations: annotations,
+		deviating:   make(map[string]bool),
+	}, nil
+}
+
+// Run compares the readings measured since the previous check until the context is done
+func (w *watch) Run(ctx context.Context, services []*Service) {
+	for _, s := range services {
+		if _, err := w.baseline.Session(s.session); err != nil {
+			slog.With("err", err.Error()).Warn("session is not watched, the baseline run lacks it")
+		}
+	}
+
+	ticker := time.NewTicker(watchCheckInterval)
+	defer ticker.Stop()
+
+	w.checked = time.Now()
+	for {
+		select {
+		case now := <-ticker.C:
+			w.check(ctx, services, now)
+		case <-ctx.Done():
+			slog.Debug("watching the baseline was stopped")
+			return
+		}
+	}
+}
+
+func (w *watch) check(ctx context.Context, services []*Service, now time.Time) {
+	for _, s := range services {
+		baseline, err := w.baseline.Session(s.session)
+		if err != nil {
+			continue
+		}
+		for group, groupMetrics := range s.metrics {
+			for _, m := range groupMetrics {
+				readings, durations := readingsSince(m.ExportDataPoints(), w.checked)
+				for _, measurement := range sortedKeys(readings) {
+					summary, ok := baselineSummary(baseline, string(group), m.GetName(), measurement)
+					if !ok {
+						continue
+					}
+					value := metric.Summarize(readings[measurement]).Mean
+					deviation, ok := summary.Deviation(value)
+					if !ok {
+						continue
+					}
+
+					key := alertKey(s.session, group, m.GetName()) + "/" + measurement
+					if math.Abs(deviation) < w.sigma {
+						if w.deviating[key] {
+							delete(w.deviating, key)
+							w.recovered(ctx, key, s.session, group, m.GetName(), measurement)
+						}
+						continue
+					}
+					if w.deviating[key] {
+						continue
+					}
+					w.deviating[key] = true
+					w.deviated(ctx, key, s.session, group, m.GetName(), measurement, deviation, value, summary, durations[measurement])
+				}
+			}
+		}
+	}
+	w.checked = now
+}
+
+func (w *watch) deviated(ctx context.Context, key, session string, group metric.Group, name, measurement string, deviation, value float64, baseline metric.Summary, duration bool) {
+	text := fmt.Sprintf("%s deviates %+.1fσ from baseline %s: %s against %s ± %s", watchedName(session, group, name, measurement),
+		deviation, w.baselineID, formatReading(value, duration), formatReading(baseline.Mean, duration), formatReading(baseline.StdDev, duration))
+	slog.
+		With("session", session).
+		With("group", group).
+		With("metric", name).
+		With("measurement", measurement).
+		With("deviation", fmt.Sprintf("%+.1fσ", deviation)).
+		With("value", formatReading(value, duration)).
+		With("baseline_mean", formatReading(baseline.Mean, duration)).
+		Warn("measurement deviates from the baseline")
+	w.annotate(text)
+
+	source := session
+	if source == "" {
+		source, _ = os.Hostname()
+	}
+	incident := alert.Alert{
+		Key:     key,
+		Summary: fmt.Sprintf("%s on %s", text, source),
+		Source:  source,
+		Details: map[string]string{
+			"group":       string(group),
+			"metric":      name,
+			"measurement": measurement,
+			"baseline":    w.baselineID,
+			"deviation":   fmt.Sprintf("%+.1f", deviation),
+			"value":       formatReading(value, duration),
+			"mean":        formatReading(baseline.Mean, duration),
+			"std_dev":     formatReading(baseline.StdDev, duration),
+		},
+	}
+	notifyAll(w.notifiers, key, func(n alert.Notifier) error { return n.Trigger(ctx, incident) })
+}
+
+func (w *watch) recovered(ctx context.Context, key, session string, group metric.Group, name, measurement string) {
+	text := fmt.Sprintf("%s is back within %gσ of baseline %s", watchedName(session, group, name, measurement), w.sigma, w.baselineID)
+	slog.
+		With("session", session).
+		With("group", group).
+		With("metric", name).
+		With("measurement", measurement).
+		Info("measurement is back within the baseline")
+	w.annotate(text)
+	notifyAll(w.notifiers, key, func(n alert.Notifier) error { return n.Resolve(ctx, key) })
+}
+
+func (w *watch) annotate(text string) {
+	if _, err := w.annotations.Add(annotation.Annotation{Text: text, Source: watchSource}); err != nil {
+		slog.With("err", err.Error()).Warn("failed annotating a deviation from the baseline")
+	}
+}
+
+// readingsSince returns the numeric readings of every measurement measured after the time, along with the measurements
+// which are durations
+func readingsSince(dataPoints []metric.ExportedDataPoint, since time.Time) (map[string][]float64, map[string]bool) {
+	readings := make(map[string][]float64)
+	durations := make(map[string]bool)
+	for _, dp := range dataPoints {
+		if !dp.Timestamp.After(since) {
+			continue
+		}
+		for measurement, value := range dp.Values {
+			if number, ok := metric.ToFloat(value); ok {
+				readings[measurement] = append(readings[measurement], number)
+			}
+			if _, ok := value.(time.Duration); ok {
+				durations[measurement] = true
+			}
+		}
+	}
+	return readings, durations
+}
+
+// baselineSummary returns the distribution of the measurement in the baseline session, computed from its data points
+// for runs exported before statistics existed
+func baselineSummary(session *results.Session, group, name, measurement string) (metric.Summary, bool) {
+	if statistics, ok := session.Statistics[group][name][measurement]; ok {
+		return statistics.Summary, true
+	}
+	series := session.Series(group, name, measurement)
+	if len(series) == 0 {
+		return metric.Summary{}, false
+	}
+	values := make([]float64, 0, len(series))
+	for _, sample := range series {
+		values = append(values, sample.Value)
+	}
+	return metric.Summarize(values), true
+}
+
+func watchedName(session string, group metric.Group, name, measurement string) string {
+	parts := []string{string(group), name, measurement}
+	if session != "" {
+		parts = append([]string{session}, parts...)
+	}
+	return strings.Join(parts, " ")
+}
+
+func formatReading(value float64, duration bool) string {
+	if duration {
+		return format.Duration(time.Duration(value))
+	}
+	return format.Number(value, 2)
+}
+
+func sortedKeys[T any](values map[string]T) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
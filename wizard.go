@@ -0,0 +1,216 @@
+package benchmark
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/clientinfo"
+	"github.com/Harikakasimahanthi/benchmark-test/internal/platform/network"
+)
+
+const (
+	outputFlag        = "output"
+	defaultConfigPath = "config.yaml"
+
+	probeTimeout = time.Second * 5
+)
+
+type wizardMetric struct {
+	key, question string
+	enabled       bool
+}
+
+var (
+	consensusWizardMetrics = []wizardMetric{
+		{key: "client", question: "Measure consensus client version and health?", enabled: true},
+		{key: "latency", question: "Measure consensus client latency?", enabled: true},
+		{key: "peers", question: "Measure consensus client peers?", enabled: true},
+		{key: "attestation", question: "Measure attestation correctness?", enabled: true},
+		{key: "head_delay", question: "Measure head delay relative to slot start?", enabled: true},
+		{key: "sync_status", question: "Measure sync distance of the consensus client?", enabled: true},
+		{key: "inbound", question: "Check the consensus client P2P port is reachable from outside?", enabled: true},
+		{key: "duty_simulation", question: "Simulate attestation duties of your validators?", enabled: false},
+	}
+	executionWizardMetrics = []wizardMetric{
+		{key: "peers", question: "Measure execution client peers?", enabled: true},
+		{key: "latency", question: "Measure execution client latency?", enabled: true},
+	}
+	infrastructureWizardMetrics = []wizardMetric{
+		{key: "cpu", question: "Measure CPU usage?", enabled: true},
+		{key: "memory", question: "Measure memory usage?", enabled: true},
+	}
+)
+
+var InitCMD = &cobra.Command{
+	Use:   "init",
+	Short: "Interactively create a benchmark configuration file",
+	// The configuration file does not exist yet, so skip loading it
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		return nil
+	},
+	RunE: func(cobraCMD *cobra.Command, args []string) error {
+		path, err := cobraCMD.Flags().GetString(outputFlag)
+		if err != nil {
+			return err
+		}
+		w := newWizard(cobraCMD.InOrStdin(), cobraCMD.OutOrStdout())
+		return w.run(cobraCMD.Context(), path)
+	},
+}
+
+func init() {
+	InitCMD.Flags().String(outputFlag, defaultConfigPath, "Path of the configuration file to write")
+	CMD.AddCommand(InitCMD)
+}
+
+type wizard struct {
+	in  *bufio.Reader
+	out io.Writer
+}
+
+func newWizard(in io.Reader, out io.Writer) *wizard {
+	return &wizard{
+		in:  bufio.NewReader(in),
+		out: out,
+	}
+}
+
+func (w *wizard) run(ctx context.Context, path string) error {
+	if _, err := os.Stat(path); err == nil {
+		if !w.confirm(fmt.Sprintf("'%s' already exists. Overwrite?", path), false) {
+			return errors.New("configuration file was not written")
+		}
+	}
+
+	config := viper.New()
+	config.SetConfigType("yaml")
+
+	// Endpoints
+	consensusAddr := w.ask("Consensus client (beacon node API) address", "http://localhost:5052")
+	w.probe(ctx, "consensus", func(ctx context.Context) (string, error) { return clientinfo.ConsensusVersion(ctx, consensusAddr) })
+	config.Set("benchmark.beacon_node.address", consensusAddr)
+
+	executionAddr := w.ask("Execution client (JSON-RPC) address", "http://localhost:8545")
+	w.probe(ctx, "execution", func(ctx context.Context) (string, error) { return clientinfo.ExecutionVersion(ctx, executionAddr) })
+	config.Set("benchmark.execution_node.address", executionAddr)
+
+	// Network
+	for {
+		networkName := w.ask("Network (mainnet/holesky/sepolia/hoodi/gnosis/chiado/custom)", string(network.Mainnet))
+		if err := network.Name(networkName).Validate(); err != nil {
+			fmt.Fprintln(w.out, err.Error())
+			continue
+		}
+		config.Set("benchmark.network", strings.ToLower(networkName))
+		break
+	}
+
+	// Validators
+	for {
+		indices, err := parseIndices(w.ask("Validator indices, comma separated (optional)", ""))
+		if err != nil {
+			fmt.Fprintln(w.out, err.Error())
+			continue
+		}
+		if len(indices) != 0 {
+			config.Set("benchmark.validator_client.indices", indices)
+		}
+		break
+	}
+
+	// Metrics
+	for _, m := range consensusWizardMetrics {
+		config.Set(fmt.Sprintf("benchmark.beacon_node.metrics.%s.enabled", m.key), w.confirm(m.question, m.enabled))
+	}
+	for _, m := range executionWizardMetrics {
+		config.Set(fmt.Sprintf("benchmark.execution_node.metrics.%s.enabled", m.key), w.confirm(m.question, m.enabled))
+	}
+	for _, m := range infrastructureWizardMetrics {
+		config.Set(fmt.Sprintf("benchmark.infrastructure.metrics.%s.enabled", m.key), w.confirm(m.question, m.enabled))
+	}
+
+	duration, err := time.ParseDuration(w.ask("Benchmark duration", defaultExecutionDuration.String()))
+	if err != nil {
+		return errors.Join(err, errors.New("duration was not valid"))
+	}
+	config.Set("benchmark.duration", duration.String())
+	config.Set("benchmark.server.port", defaultServerPort)
+
+	if err := config.WriteConfigAs(path); err != nil {
+		return errors.Join(err, errors.New("failed writing configuration file"))
+	}
+	fmt.Fprintf(w.out, "Configuration was written to '%s'\n", path)
+
+	return nil
+}
+
+func (w *wizard) ask(question, defaultValue string) string {
+	if defaultValue != "" {
+		fmt.Fprintf(w.out, "%s [%s]: ", question, defaultValue)
+	} else {
+		fmt.Fprintf(w.out, "%s: ", question)
+	}
+
+	answer, _ := w.in.ReadString('\n')
+	answer = strings.TrimSpace(answer)
+	if answer == "" {
+		return defaultValue
+	}
+	return answer
+}
+
+func (w *wizard) confirm(question string, defaultValue bool) bool {
+	defaultAnswer := "y/N"
+	if defaultValue {
+		defaultAnswer = "Y/n"
+	}
+	for {
+		switch strings.ToLower(w.ask(question, defaultAnswer)) {
+		case strings.ToLower(defaultAnswer):
+			return defaultValue
+		case "y", "yes":
+			return true
+		case "n", "no":
+			return false
+		}
+	}
+}
+
+// probe reports the client behind the endpoint, an unreachable endpoint is not fatal since the node may not be running yet
+func (w *wizard) probe(ctx context.Context, layer string, version func(context.Context) (string, error)) {
+	ctx, cancel := context.WithTimeout(ctx, probeTimeout)
+	defer cancel()
+
+	clientVersion, err := version(ctx)
+	if err != nil {
+		fmt.Fprintf(w.out, "  could not reach the %s client: %s\n", layer, err.Error())
+		return
+	}
+	fmt.Fprintf(w.out, "  detected %s client: %s (%s)\n", layer, clientinfo.ParseClient(clientVersion), clientVersion)
+}
+
+func parseIndices(value string) ([]uint64, error) {
+	var indices []uint64
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		index, err := strconv.ParseUint(part, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("validator index '%s' is not a number", part)
+		}
+		indices = append(indices, index)
+	}
+	return indices, nil
+}